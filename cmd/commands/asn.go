@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/lucchesi-sec/portscan/pkg/targets"
+	"github.com/spf13/viper"
+)
+
+// asnSplitBits is the shortest prefix an announced network is scanned as.
+// Larger announcements are split into prefixes this long, so each stays
+// within the per-CIDR expansion limit.
+const asnSplitBits = 16
+
+// loadASNTargets returns the --asn networks as CIDR targets. IPv6
+// announcements are far too large to enumerate and are skipped with a
+// warning on stderr, unless --quiet is set.
+func loadASNTargets(cfg *config.Config) ([]string, error) {
+	asns, err := cfg.GetASNs()
+	if err != nil {
+		return nil, asnError(err)
+	}
+	prefixes, err := readASNData(cfg.ASNData, asns)
+	if err != nil {
+		return nil, asnError(err)
+	}
+	var cidrs []string
+	skipped := 0
+	for _, asn := range asns {
+		announced := 0
+		for _, prefix := range prefixes[asn] {
+			if !prefix.Addr().Is4() {
+				skipped++
+				continue
+			}
+			announced++
+			for _, part := range targets.SplitPrefix(prefix, asnSplitBits) {
+				cidrs = append(cidrs, part.String())
+			}
+		}
+		if announced == 0 {
+			return nil, asnError(fmt.Errorf("AS%d announces no IPv4 prefixes in %s", asn, cfg.ASNData))
+		}
+	}
+	if skipped > 0 && !viper.GetBool("quiet") {
+		fmt.Fprintf(os.Stderr, "warning: skipped %d IPv6 prefixes announced by %s\n", skipped, asnSummary(asns))
+	}
+	return cidrs, nil
+}
+
+func readASNData(path string, asns []uint32) (map[uint32][]netip.Prefix, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	prefixes, err := targets.ReadPrefixes(f, asns)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return prefixes, nil
+}
+
+func asnError(err error) error {
+	return &errors.UserError{
+		Code:       "INVALID_ASN",
+		Message:    i18n.T("errors.invalid_asn.message"),
+		Details:    err.Error(),
+		Suggestion: i18n.T("errors.invalid_asn.suggestion"),
+	}
+}
+
+// asnSummary lists AS numbers as "AS13335, AS15169".
+func asnSummary(asns []uint32) string {
+	names := make([]string, len(asns))
+	for i, asn := range asns {
+		names[i] = fmt.Sprintf("AS%d", asn)
+	}
+	return strings.Join(names, ", ")
+}
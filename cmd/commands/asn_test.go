@@ -0,0 +1,44 @@
+package commands
+
+import (
+	stdErrors "errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+func TestLoadASNTargets(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("quiet", true)
+
+	path := filepath.Join(t.TempDir(), "pfx2as.txt")
+	data := "1.0.0.0\t24\t13335\n104.16.0.0\t15\t13335\n2606:4700::\t32\t13335\n8.8.8.0\t24\t15169\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cidrs, err := loadASNTargets(&config.Config{ASN: "AS13335", ASNData: path})
+	if err != nil {
+		t.Fatalf("loadASNTargets: %v", err)
+	}
+	if want := []string{"1.0.0.0/24", "104.16.0.0/16", "104.17.0.0/16"}; !reflect.DeepEqual(cidrs, want) {
+		t.Errorf("targets = %q, want %q", cidrs, want)
+	}
+
+	for _, cfg := range []*config.Config{
+		{ASN: "AS64500", ASNData: path},
+		{ASN: "AS13335", ASNData: filepath.Join(t.TempDir(), "missing.txt")},
+	} {
+		_, err := loadASNTargets(cfg)
+		var userErr *errors.UserError
+		if !stdErrors.As(err, &userErr) || userErr.Code != "INVALID_ASN" {
+			t.Errorf("loadASNTargets(%s from %s) = %v, want INVALID_ASN", cfg.ASN, filepath.Base(cfg.ASNData), err)
+		}
+	}
+}
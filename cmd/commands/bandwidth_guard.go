@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/lucchesi-sec/portscan/pkg/parser"
+	"github.com/spf13/viper"
+)
+
+// bandwidthNoticeInterval throttles the guard's pause notices, since a
+// scan held near its ceiling pauses every second or two.
+const bandwidthNoticeInterval = 30 * time.Second
+
+// newBandwidthGuard returns the --max-bandwidth and --max-pps guard
+// pausing control. Outside the TUI, unless --quiet is set, pauses are
+// noted on stderr at most every bandwidthNoticeInterval; the TUI and the
+// progress line show the scan paused.
+func newBandwidthGuard(cfg *config.Config, control core.Pausable) (*core.BandwidthGuard, error) {
+	if window := cfg.GetScanWindow(); window != nil {
+		control = windowedControl{Pausable: control, window: *window}
+	}
+	opts := core.BandwidthOptions{
+		Limits:    core.BandwidthLimits{BitsPerSec: cfg.GetMaxBandwidth(), PacketsPerSec: int64(cfg.MaxPPS)},
+		Interface: cfg.BandwidthIface,
+	}
+	if !viper.GetBool("quiet") && outputFormat(cfg) != "" {
+		var lastNotice time.Time
+		opts.OnChange = func(over bool, sample core.BandwidthSample) {
+			if over && time.Since(lastNotice) >= bandwidthNoticeInterval {
+				lastNotice = time.Now()
+				fmt.Fprintln(os.Stderr, bandwidthNotice(sample, cfg))
+			}
+		}
+	}
+	guard, err := core.NewBandwidthGuard(control, opts)
+	if err != nil {
+		return nil, &errors.UserError{
+			Code:       "INVALID_BANDWIDTH_GUARD",
+			Message:    i18n.T("errors.invalid_bandwidth_guard.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_bandwidth_guard.suggestion"),
+		}
+	}
+	return guard, nil
+}
+
+// windowedControl keeps the bandwidth guard from resuming a scan outside
+// its scan window.
+type windowedControl struct {
+	core.Pausable
+	window parser.ScanWindow
+}
+
+func (c windowedControl) Resume() {
+	if c.window.Contains(time.Now()) {
+		c.Pausable.Resume()
+	}
+}
+
+// bandwidthNotice explains a pause by the bandwidth guard.
+func bandwidthNotice(sample core.BandwidthSample, cfg *config.Config) string {
+	return fmt.Sprintf("Outbound traffic %s, %.0f pps is over %s; pausing the scan until it falls back",
+		formatBitRate(sample.BitsPerSec), sample.PacketsPerSec, bandwidthGuardSummary(cfg))
+}
+
+// bandwidthGuardSummary describes the bandwidth guard's limits.
+func bandwidthGuardSummary(cfg *config.Config) string {
+	var limits []string
+	if bits := cfg.GetMaxBandwidth(); bits > 0 {
+		limits = append(limits, formatBitRate(float64(bits)))
+	}
+	if cfg.MaxPPS > 0 {
+		limits = append(limits, fmt.Sprintf("%d pps", cfg.MaxPPS))
+	}
+	summary := strings.Join(limits, " or ")
+	if cfg.BandwidthIface != "" {
+		summary += " on " + cfg.BandwidthIface
+	}
+	return summary
+}
+
+// formatBitRate renders bits per second with an SI prefix, e.g. 12.5 Mbit/s.
+func formatBitRate(bits float64) string {
+	switch {
+	case bits >= 1e9:
+		return fmt.Sprintf("%.1f Gbit/s", bits/1e9)
+	case bits >= 1e6:
+		return fmt.Sprintf("%.1f Mbit/s", bits/1e6)
+	case bits >= 1e3:
+		return fmt.Sprintf("%.1f kbit/s", bits/1e3)
+	}
+	return fmt.Sprintf("%.0f bit/s", bits)
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	stdErrors "errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/parser"
+)
+
+func TestBandwidthGuardSummary(t *testing.T) {
+	cfg := &config.Config{MaxBandwidth: "100Mbit", MaxPPS: 20000, BandwidthIface: "eth0"}
+	if got, want := bandwidthGuardSummary(cfg), "100.0 Mbit/s or 20000 pps on eth0"; got != want {
+		t.Errorf("bandwidthGuardSummary() = %q, want %q", got, want)
+	}
+	notice := bandwidthNotice(core.BandwidthSample{BitsPerSec: 1.5e8, PacketsPerSec: 30000}, cfg)
+	if !strings.Contains(notice, "150.0 Mbit/s, 30000 pps") || !strings.Contains(notice, "pausing") {
+		t.Errorf("bandwidthNotice() = %q", notice)
+	}
+	for bits, want := range map[float64]string{2.5e9: "2.5 Gbit/s", 800: "800 bit/s", 64e3: "64.0 kbit/s"} {
+		if got := formatBitRate(bits); got != want {
+			t.Errorf("formatBitRate(%v) = %q, want %q", bits, got, want)
+		}
+	}
+}
+
+func TestNewBandwidthGuardUnknownInterface(t *testing.T) {
+	cfg := &config.Config{MaxPPS: 1000, BandwidthIface: "portscan-test0"}
+	_, err := newBandwidthGuard(cfg, core.NewPauseGate())
+	var userErr *errors.UserError
+	if !stdErrors.As(err, &userErr) || userErr.Code != "INVALID_BANDWIDTH_GUARD" {
+		t.Fatalf("err = %v; want INVALID_BANDWIDTH_GUARD", err)
+	}
+}
+
+func TestWindowedControlResumesInsideWindow(t *testing.T) {
+	now := time.Now()
+	open, err := parser.ParseScanWindow(now.Add(-time.Hour).Format("15:04") + "-" + now.Add(time.Hour).Format("15:04"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed, err := parser.ParseScanWindow(now.Add(time.Hour).Format("15:04") + "-" + now.Add(2*time.Hour).Format("15:04"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gate := core.NewPauseGate()
+	gate.Pause()
+	windowedControl{Pausable: gate, window: closed}.Resume()
+	if !gate.Paused() {
+		t.Error("resumed a scan outside its window")
+	}
+	windowedControl{Pausable: gate, window: open}.Resume()
+	if gate.Paused() {
+		t.Error("did not resume a scan inside its window")
+	}
+}
@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/bench"
+	"github.com/spf13/cobra"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Benchmark the scanner engine against a local simulator",
+	Long: `Run the core scanner against a built-in target simulator on loopback and
+report achieved packets per second, CPU time, allocations, and accuracy.
+
+The simulator opens listeners on a fraction of its ports and leaves the rest
+closed. Open ports can delay their banner (--latency) or reset a share of
+connections (--drop). Use --duration to repeat rounds as a soak test.
+
+Examples:
+  portscan bench
+  portscan bench --ports 500 --open-ratio 0.2 --latency 5ms --banners
+  portscan bench --duration 5m --drop 0.05`,
+	RunE: runBench,
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+	addBenchFlags(benchCmd)
+}
+
+// addBenchFlags registers the simulator and scanner flags on cmd.
+func addBenchFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("ports", 200, "Number of simulated ports (each uses a file descriptor)")
+	cmd.Flags().Float64("open-ratio", 0.1, "Fraction of simulated ports that are open (0-1)")
+	cmd.Flags().Duration("latency", 0, "Delay before open ports send their banner")
+	cmd.Flags().Float64("drop", 0, "Fraction of connections reset by open ports (0-1)")
+	cmd.Flags().Int("workers", 100, "Concurrent scanner workers")
+	cmd.Flags().Int("rate", 0, "Probe rate limit in pps (0 = unlimited)")
+	cmd.Flags().Int("timeout", 200, "Connection timeout in milliseconds")
+	cmd.Flags().Bool("banners", false, "Grab banners from open ports")
+	cmd.Flags().Duration("duration", 0, "Repeat rounds for this long (soak test)")
+	cmd.Flags().Int64("seed", 0, "Seed for the simulated port layout (0 = random)")
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	opts, err := benchOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Simulating %d ports (%.0f%% open) on 127.0.0.1\n",
+		opts.Simulator.Ports, opts.Simulator.OpenRatio*100)
+
+	summary, err := bench.Run(ctx, opts, func(r bench.RoundReport) {
+		printBenchRound(out, r)
+	})
+	if err != nil && ctx.Err() == nil {
+		return err
+	}
+	printBenchSummary(out, summary)
+	return nil
+}
+
+func benchOptions(cmd *cobra.Command) (bench.Options, error) {
+	flags := cmd.Flags()
+	ports, _ := flags.GetInt("ports")
+	openRatio, _ := flags.GetFloat64("open-ratio")
+	latency, _ := flags.GetDuration("latency")
+	drop, _ := flags.GetFloat64("drop")
+	workers, _ := flags.GetInt("workers")
+	rate, _ := flags.GetInt("rate")
+	timeoutMs, _ := flags.GetInt("timeout")
+	banners, _ := flags.GetBool("banners")
+	duration, _ := flags.GetDuration("duration")
+	seed, _ := flags.GetInt64("seed")
+
+	if ports <= 0 {
+		return bench.Options{}, fmt.Errorf("--ports must be positive, got %d", ports)
+	}
+	if workers <= 0 {
+		return bench.Options{}, fmt.Errorf("--workers must be positive, got %d", workers)
+	}
+	if timeoutMs <= 0 {
+		return bench.Options{}, fmt.Errorf("--timeout must be positive, got %d", timeoutMs)
+	}
+
+	banner := ""
+	if banners {
+		banner = "SSH-2.0-portscan-bench\r\n"
+	}
+	return bench.Options{
+		Simulator: bench.SimulatorOptions{
+			Ports:     ports,
+			OpenRatio: openRatio,
+			Latency:   latency,
+			DropRate:  drop,
+			Banner:    banner,
+			Seed:      seed,
+		},
+		Workers:  workers,
+		Rate:     rate,
+		Timeout:  time.Duration(timeoutMs) * time.Millisecond,
+		Banners:  banners,
+		Duration: duration,
+	}, nil
+}
+
+func printBenchRound(w io.Writer, r bench.RoundReport) {
+	fmt.Fprintf(w, "round %d: %d probes in %s • %.0f pps • cpu %s • %d allocs (%s) • accuracy %.2f%%",
+		r.Round, r.Probes, r.Elapsed.Round(time.Millisecond), r.PPS,
+		r.CPU.Round(time.Millisecond), r.Allocs, formatBytes(r.AllocBytes), r.Accuracy()*100)
+	if r.Missing > 0 {
+		fmt.Fprintf(w, " • %d missing", r.Missing)
+	}
+	if r.Errors > 0 {
+		fmt.Fprintf(w, " • %d errors", r.Errors)
+	}
+	fmt.Fprintln(w)
+}
+
+func printBenchSummary(w io.Writer, s bench.Summary) {
+	if len(s.Rounds) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "\n=== Benchmark Summary ===")
+	fmt.Fprintf(w, "  Rounds:       %d\n", len(s.Rounds))
+	fmt.Fprintf(w, "  Probes:       %d\n", s.Probes)
+	fmt.Fprintf(w, "  Elapsed:      %s\n", s.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "  Throughput:   %.0f pps\n", s.PPS)
+	fmt.Fprintf(w, "  CPU:          %s\n", s.CPU.Round(time.Millisecond))
+	fmt.Fprintf(w, "  Allocations:  %d (%s)\n", s.Allocs, formatBytes(s.AllocBytes))
+	fmt.Fprintf(w, "  Min accuracy: %.2f%%\n", s.MinAccuracy*100)
+}
+
+// formatBytes renders a byte count with a binary unit suffix.
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
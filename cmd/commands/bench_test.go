@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/bench"
+	"github.com/spf13/cobra"
+)
+
+// TestBenchCommand verifies the bench command is registered with its flags
+func TestBenchCommand(t *testing.T) {
+	found := false
+	for _, sub := range rootCmd.Commands() {
+		if sub == benchCmd {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("bench should be registered under root")
+	}
+
+	for _, name := range []string{"ports", "open-ratio", "latency", "drop", "workers", "rate", "timeout", "banners", "duration", "seed"} {
+		if benchCmd.Flags().Lookup(name) == nil {
+			t.Errorf("missing --%s flag", name)
+		}
+	}
+}
+
+// TestBenchOptions verifies flag values map onto bench options
+func TestBenchOptions(t *testing.T) {
+	cmd := &cobra.Command{}
+	addBenchFlags(cmd)
+	if err := cmd.Flags().Parse([]string{"--ports", "50", "--latency", "5ms", "--banners", "--timeout", "300"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+
+	opts, err := benchOptions(cmd)
+	if err != nil {
+		t.Fatalf("benchOptions: %v", err)
+	}
+	if opts.Simulator.Ports != 50 || opts.Simulator.Latency != 5*time.Millisecond {
+		t.Errorf("simulator = %+v", opts.Simulator)
+	}
+	if !opts.Banners || opts.Simulator.Banner == "" {
+		t.Error("--banners should enable banner grabbing and a simulator banner")
+	}
+	if opts.Timeout != 300*time.Millisecond {
+		t.Errorf("timeout = %v, want 300ms", opts.Timeout)
+	}
+}
+
+// TestPrintBenchSummary verifies the report lists throughput and accuracy
+func TestPrintBenchSummary(t *testing.T) {
+	var out bytes.Buffer
+	round := bench.RoundReport{Round: 1, Probes: 100, Correct: 99, Missing: 1, PPS: 5000, Elapsed: 20 * time.Millisecond}
+	printBenchRound(&out, round)
+	printBenchSummary(&out, bench.Summary{Rounds: []bench.RoundReport{round}, Probes: 100, PPS: 5000, MinAccuracy: 0.99, AllocBytes: 2048})
+
+	for _, want := range []string{"round 1", "5000 pps", "accuracy 99.00%", "1 missing", "Min accuracy: 99.00%", "2.0 KiB"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+// TestBenchOptions_Invalid verifies non-positive sizes are rejected
+func TestBenchOptions_Invalid(t *testing.T) {
+	for _, args := range [][]string{{"--ports", "0"}, {"--workers", "-1"}, {"--timeout", "0"}} {
+		cmd := &cobra.Command{}
+		addBenchFlags(cmd)
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatalf("parse flags: %v", err)
+		}
+		if _, err := benchOptions(cmd); err == nil {
+			t.Errorf("benchOptions(%v) succeeded, want error", args)
+		}
+	}
+}
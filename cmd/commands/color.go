@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/muesli/termenv"
+	"github.com/spf13/viper"
+)
+
+// colorEnabled reports whether styled output is allowed. Color is disabled
+// by --no-color (or no_color in the config file) and by a non-empty NO_COLOR
+// environment variable, following https://no-color.org.
+func colorEnabled() bool {
+	if viper.GetBool("no_color") {
+		return false
+	}
+	return os.Getenv("NO_COLOR") == ""
+}
+
+// applyColorMode switches lipgloss to plain ASCII output when color is
+// disabled, so every styled string renders as clean text.
+func applyColorMode() {
+	if !colorEnabled() {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// newRenderer returns a lipgloss renderer for w that honors the color mode.
+// Writers that are not terminals already render without styling.
+func newRenderer(w io.Writer) *lipgloss.Renderer {
+	r := lipgloss.NewRenderer(w)
+	if !colorEnabled() {
+		r.SetColorProfile(termenv.Ascii)
+	}
+	return r
+}
+
+// printError writes err to w with a highlighted "Error:" prefix, or as a
+// JSON errors.Report with --error-format json.
+func printError(w io.Writer, err error) {
+	if viper.GetString("error_format") == "json" {
+		_ = json.NewEncoder(w).Encode(errors.NewReport(err))
+		return
+	}
+	label := newRenderer(w).NewStyle().
+		Foreground(lipgloss.Color("9")).
+		Bold(true).
+		Render(i18n.T("errors.labels.error"))
+	fmt.Fprintf(w, "%s %v\n", label, err)
+}
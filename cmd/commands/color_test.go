@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	stdErrors "errors"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+func TestColorEnabled(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	t.Setenv("NO_COLOR", "")
+	if !colorEnabled() {
+		t.Error("color should be enabled by default")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if colorEnabled() {
+		t.Error("NO_COLOR should disable color")
+	}
+
+	t.Setenv("NO_COLOR", "")
+	viper.Set("no_color", true)
+	if colorEnabled() {
+		t.Error("--no-color should disable color")
+	}
+}
+
+func TestPrintErrorPlainText(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("no_color", true)
+
+	var buf bytes.Buffer
+	printError(&buf, stdErrors.New("boom"))
+
+	if got, want := buf.String(), "Error: boom\n"; got != want {
+		t.Errorf("printError = %q, want %q", got, want)
+	}
+}
+
+func TestPrintErrorJSON(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("error_format", "json")
+
+	var buf bytes.Buffer
+	printError(&buf, errors.NoTargetError())
+
+	var report errors.Report
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("printError wrote invalid JSON %q: %v", buf.String(), err)
+	}
+	if report.Code != "NO_TARGET" || report.Suggestion == "" || report.ExitCode != errors.ExitUsage {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/lucchesi-sec/portscan/pkg/importer"
+	"github.com/lucchesi-sec/portscan/pkg/profiles"
+	"github.com/lucchesi-sec/portscan/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate shell completion scripts",
+	Long: `Generate a completion script for your shell. Besides commands and flags, it
+completes scan profiles, themes (including your own theme files), timing
+templates, and output formats.
+
+Bash (requires bash-completion):
+  source <(portscan completion bash)
+  # or permanently:
+  portscan completion bash > /etc/bash_completion.d/portscan
+
+Zsh:
+  portscan completion zsh > "${fpath[1]}/_portscan"
+
+Fish:
+  portscan completion fish > ~/.config/fish/completions/portscan.fish
+
+PowerShell:
+  portscan completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return writeCompletion(os.Stdout, args[0])
+	},
+}
+
+// scanOutputFormats lists the --output values of the scan command.
+var scanOutputFormats = []string{"json", "csv", "html", "prometheus", "table", "elastic", "syslog", "kafka", "nats", "inventory"}
+
+func init() {
+	// Replace cobra's default completion command with one that documents
+	// the dynamic completions.
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+}
+
+// writeCompletion writes the completion script for shell to w.
+func writeCompletion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(w, true)
+	case "zsh":
+		return rootCmd.GenZshCompletion(w)
+	case "fish":
+		return rootCmd.GenFishCompletion(w, true)
+	case "powershell":
+		return rootCmd.GenPowerShellCompletionWithDesc(w)
+	default:
+		return fmt.Errorf("unsupported shell %q: use bash, zsh, fish, or powershell", shell)
+	}
+}
+
+// registerScanCompletions attaches value completions to the scan flags.
+func registerScanCompletions(cmd *cobra.Command) {
+	completions := map[string]cobra.CompletionFunc{
+		"profile":          completeProfiles,
+		"ui.theme":         completeThemes,
+		"timing":           completeTiming,
+		"output":           fixedCompletions(scanOutputFormats...),
+		"protocol":         fixedCompletions("tcp", "udp", "both"),
+		"banner-encoding":  fixedCompletions("text", "base64"),
+		"compress":         fixedCompletions(exporter.CompressionGzip, exporter.CompressionZstd, exporter.CompressionNone),
+		"syslog-format":    fixedCompletions(exporter.SyslogFormatRFC5424, exporter.SyslogFormatCEF),
+		"inventory-format": fixedCompletions(exporter.InventoryJSON, exporter.InventoryCSV),
+		"message-key":      fixedCompletions(exporter.MessageKeyHost, exporter.MessageKeyHostPort, exporter.MessageKeyNone),
+		"partitioner":      fixedCompletions(exporter.PartitionerHash, exporter.PartitionerRoundRobin),
+	}
+	registerFlagCompletions(cmd, completions)
+}
+
+// registerImportCompletions attaches value completions to the import flags.
+func registerImportCompletions(cmd *cobra.Command) {
+	registerFlagCompletions(cmd, map[string]cobra.CompletionFunc{
+		"format":   fixedCompletions(append([]string{importer.FormatAuto}, importer.Formats...)...),
+		"output":   fixedCompletions("json", "csv", "html", "table"),
+		"compress": fixedCompletions(exporter.CompressionGzip, exporter.CompressionZstd, exporter.CompressionNone),
+	})
+}
+
+// registerReplayCompletions attaches value completions to the replay flags.
+func registerReplayCompletions(cmd *cobra.Command) {
+	registerFlagCompletions(cmd, map[string]cobra.CompletionFunc{
+		"speed":    fixedCompletions("1x", "2x", "10x", "100x", "max"),
+		"output":   fixedCompletions("json", "csv", "html", "table"),
+		"compress": fixedCompletions(exporter.CompressionGzip, exporter.CompressionZstd, exporter.CompressionNone),
+	})
+}
+
+func registerFlagCompletions(cmd *cobra.Command, completions map[string]cobra.CompletionFunc) {
+	for name, fn := range completions {
+		if cmd.Flags().Lookup(name) == nil {
+			continue
+		}
+		_ = cmd.RegisterFlagCompletionFunc(name, fn)
+	}
+}
+
+// fixedCompletions completes one of values and never falls back to files.
+func fixedCompletions(values ...string) cobra.CompletionFunc {
+	return cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp)
+}
+
+// completeProfiles suggests the registered scan profiles with their port
+// counts.
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	names := profiles.ListProfiles()
+	sort.Strings(names)
+	completions := make([]cobra.Completion, 0, len(names))
+	for _, name := range names {
+		desc := strconv.Itoa(len(profiles.GetProfile(name))) + " ports"
+		completions = append(completions, cobra.CompletionWithDesc(name, desc))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeThemes suggests built-in themes and themes loaded from the user
+// theme directory.
+func completeThemes(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	names := theme.Names()
+	completions := make([]cobra.Completion, 0, len(names))
+	for _, name := range names {
+		desc := "built-in"
+		if name == theme.AutoThemeName {
+			desc = "follows the terminal background"
+		} else if t, ok := theme.Lookup(name); ok && t.Source != "" {
+			desc = t.Source
+		}
+		completions = append(completions, cobra.CompletionWithDesc(name, desc))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTiming suggests the timing templates by level.
+func completeTiming(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	completions := make([]cobra.Completion, 0, len(config.TimingTemplates))
+	for _, t := range config.TimingTemplates {
+		completions = append(completions, cobra.CompletionWithDesc(fmt.Sprintf("T%d", t.Level), t.Name))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
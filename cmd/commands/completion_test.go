@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+// TestWriteCompletion verifies a script is generated for each supported shell
+func TestWriteCompletion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		var buf bytes.Buffer
+		if err := writeCompletion(&buf, shell); err != nil {
+			t.Errorf("%s: %v", shell, err)
+			continue
+		}
+		if !strings.Contains(buf.String(), "portscan") {
+			t.Errorf("%s script does not mention portscan", shell)
+		}
+	}
+	if err := writeCompletion(&bytes.Buffer{}, "tcsh"); err == nil {
+		t.Error("unsupported shells should be rejected")
+	}
+}
+
+// TestScanFlagCompletions verifies dynamic values are offered for scan flags
+func TestScanFlagCompletions(t *testing.T) {
+	tests := []struct {
+		flag string
+		want []string
+	}{
+		{"profile", []string{"quick\t", "web\t", "full\t65535 ports"}},
+		{"ui.theme", []string{"auto\t", "default\tbuilt-in", "dracula\t"}},
+		{"timing", []string{"T0\tparanoid", "T5\tinsane"}},
+		{"output", []string{"json", "csv", "nats"}},
+		{"protocol", []string{"tcp", "udp", "both"}},
+	}
+	for _, tt := range tests {
+		fn, ok := scanCmd.GetFlagCompletionFunc(tt.flag)
+		if !ok {
+			t.Errorf("--%s has no completion function", tt.flag)
+			continue
+		}
+		values, directive := fn(scanCmd, nil, "")
+		if directive != cobra.ShellCompDirectiveNoFileComp {
+			t.Errorf("--%s directive = %v, want no file completion", tt.flag, directive)
+		}
+		joined := strings.Join(values, "\n")
+		for _, want := range tt.want {
+			if !strings.Contains(joined, want) {
+				t.Errorf("--%s completions %q missing %q", tt.flag, values, want)
+			}
+		}
+	}
+}
+
+// TestCompleteThemes_Registered verifies themes registered at runtime, such
+// as user theme files, are suggested with their source
+func TestCompleteThemes_Registered(t *testing.T) {
+	custom := theme.GetTheme("default")
+	custom.Name = "completion-test"
+	custom.Source = "/tmp/themes/completion-test.yaml"
+	theme.Register(custom.Name, custom)
+
+	values, _ := completeThemes(scanCmd, nil, "")
+	want := "completion-test\t/tmp/themes/completion-test.yaml"
+	if !strings.Contains(strings.Join(values, "\n"), want) {
+		t.Errorf("completions %q missing %q", values, want)
+	}
+}
+
+// TestImportFlagCompletions verifies import formats are offered
+func TestImportFlagCompletions(t *testing.T) {
+	fn, ok := importCmd.GetFlagCompletionFunc("format")
+	if !ok {
+		t.Fatal("--format has no completion function")
+	}
+	values, _ := fn(importCmd, nil, "")
+	if got := strings.Join(values, ","); got != "auto,nmap-xml,masscan-json,masscan-list" {
+		t.Errorf("--format completions = %s", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -54,20 +55,113 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 	// Default configuration with comments
 	defaultConfig := `# Port Scanner Configuration
 # This file configures default settings for the port scanner
+# ${NAME} in a value is replaced by the environment variable NAME
+
+include: []             # Files merged beneath this one, in order, e.g. ["/etc/portscan/team.yaml"]
 
 # Performance settings
 rate: 7500              # Packets per second (max safe: 15000)
-workers: 0              # Concurrent workers (0 = auto-detect based on CPU)
+workers: 0              # Concurrent workers (0 = auto-detect based on CPU); capped to the open file limit
+raise_fd_limit: false   # Raise the soft open file limit (ulimit -n) to fit the workers
+run_as: ""              # When started as root, switch to this USER[:GROUP] before scanning
+scope: ""               # Refuse to scan targets outside this allowlist of networks/domains (YAML)
+scope_key: ""           # Require scope to be signed (scope.yaml.sig) by this Ed25519 public key
+allow_localhost: true   # Permit scanning loopback addresses and localhost
+allow_private: true     # Permit scanning private (10/8, 172.16/12, 192.168/16, fc00::/7) and link-local ranges
+exclude_rdns: ""        # Never scan targets whose PTR names match these globs, e.g. "*.scada.corp"
+exclude_tags: ""        # Never scan targets with a tag matching these key=value globs, e.g. "role=plc*"
 timeout_ms: 200         # Connection timeout in milliseconds
+port_timeouts: ""       # Per-port overrides in ms, e.g. "443=1000,3306=500"
+from_masscan: ""        # Scan only the open ports in this masscan -oJ/-oL/-oX file instead of targets
+targets_csv: ""         # Read targets from this CSV or JSON asset inventory
+host_field: "ip"        # Asset field holding each target
+tag_fields: ""          # Asset fields attached to each host's results as tags, e.g. "owner,env"
+asn: ""                 # Scan the prefixes these autonomous systems announce, e.g. "AS13335"
+asn_data: ""            # Prefix-to-AS dataset --asn is looked up in, e.g. a RouteViews pfx2as file
+jobs: []                # Scan jobs "NAME:TARGETS_FILE[:key=value...]" run side by side under one rate
+timing: ""              # Timing template T0-T5; overrides rate/timeout/retries/jitter/host_parallelism
+retries: 2              # Retry attempts for ports that time out
+jitter_ms: 0            # Random delay of up to this many ms before each probe
+host_parallelism: 0     # Maximum concurrent probes per host (0 = unlimited)
+max_bandwidth: ""       # Pause while this host sends more bits/s than this, e.g. "100Mbit" (Linux)
+max_pps: 0              # Pause while this host sends more packets/s than this (0 = no limit)
+bandwidth_iface: ""     # Interface the bandwidth guard measures (empty = all but loopback)
+conntrack_limit: 90     # Pause while the conntrack table is this % full (Linux; 0 = off)
+dns_server: ""          # Resolve hostnames via host[:port], tls://host (DoT) or https://host/dns-query (DoH)
+dns_timeout_ms: 0       # Timeout for each hostname lookup (0 = 5000)
+all_ips: false          # Scan every A/AAAA address of a hostname, not only the first
+tags: []                # key=value tags attached to the scan and every result, e.g. ["env=prod"]
+overrides: []           # Settings for matching targets, first match wins, e.g.
+                        #   - match: "10.2.0.0/16"   # CIDR, address, hostname or *.domain
+                        #     rate: 500              # also timeout_ms, retries, jitter_ms,
+                        #     timeout_ms: 800        # host_parallelism and workers
 
 # Default scan settings
 ports: "1-1024"         # Default ports to scan
 banners: false          # Grab service banners by default
+banner_max_bytes: 512   # Maximum bytes read from each banner
+banner_timeout_ms: 1000 # Banner read timeout in milliseconds
+banner_workers: 50      # Concurrent banner reads, separate from the scan workers
+banner_encoding: text   # JSON banner encoding: text, or base64 for byte-exact binary banners
+verify_open: false      # Re-connect to open TCP ports before reporting them
+detect_edge: false      # Identify CDN/WAF front ends (Cloudflare, Akamai, Fastly, ...) on open 80/443/8080/8443
+local_discovery: false  # Name devices on RFC 1918 hosts with mDNS, NetBIOS and SSDP queries
+check_ntp: false        # Flag open NTP servers that answer mode 6/7 (monlist) amplification queries
+tls_inspect: false      # Record TLS versions and certificates, upgrading SMTP, IMAP, POP3, FTP and LDAP with STARTTLS
+http_audit: false       # Grade security headers (HSTS, CSP, X-Frame-Options, ...) on open 80/443/8080/8443
+owner_lookup: false     # Record the organization and abuse contact holding public hosts' networks (RDAP)
+owner_offline: false    # Answer owner lookups from the cache alone, sending no RDAP queries
+owner_cache: ""         # RDAP answer cache file (empty = rdap.json in the user cache directory)
+geoip_db: ""            # MaxMind DB files adding country and ASN, e.g. "GeoLite2-Country.mmdb,GeoLite2-ASN.mmdb"
+udp_probes: ""          # UDP probe packs replacing built-in payloads: YAML files, or "extended"
+snmp_communities: ""    # SNMP communities tried on UDP 161, e.g. "public,private"; empty skips the check
+fail_on: ""             # Exit with code 3 if these ports (e.g. "23,3389") or "any" port is open
+strict: false           # Exit with code 4 if any probe fails, not only when a host has no results
+scan_window: ""         # Daily local-time window, e.g. "22:00-06:00"; paused outside it
+spread: false           # Pace the scan across the remaining window
 output: ""              # Output format: json, csv, table, or empty for TUI
+output_file: ""         # Write results to this file (atomically; .gz or .zst compresses)
+timeline_file: ""       # Write a JSON timeline of hosts, rate changes, pauses and errors here
+record: ""              # Record the scan's events here for 'portscan replay'
+record_session: ""      # Record the TUI session here as an asciinema cast, e.g. "session.cast"
+output_max_size: ""     # Rotate NDJSON output files at this size, e.g. "100MB"
+output_keep: 5          # Rotated output files to keep
+compress: ""            # Stream compression for exports and output files: gzip, zstd, or empty
+elastic_url: ""         # Elasticsearch/OpenSearch URL for output: elastic
+elastic_index: "portscan-%{+yyyy.MM.dd}" # Index name; %{+...} expands to the UTC date
+elastic_batch_size: 500 # Documents per bulk request
+# elastic_api_key: ""   # API key; prefer the PORTSCAN_ELASTIC_API_KEY environment variable
+syslog_addr: ""         # Syslog collector for output: syslog, e.g. "tls://siem:6514" (empty = stdout)
+syslog_format: rfc5424  # Syslog message format: rfc5424 or cef
+inventory_format: json  # Per-host inventory format for output: inventory, json or csv
+brokers: ""             # Kafka brokers or NATS servers for output: kafka/nats, comma-separated
+topic: portscan.results # Kafka topic or NATS subject
+message_key: host       # Message key: host, host_port, or none
+partitioner: hash       # Kafka partitioner: hash (by key) or round-robin
+broker_tls: false       # TLS to the brokers (implied by broker_ca or broker_cert)
+broker_ca: ""           # PEM CA bundle for broker certificates
+broker_cert: ""         # PEM client certificate for the brokers, with broker_key
+broker_key: ""          # PEM private key for broker_cert
+broker_sasl: ""         # Kafka SASL mechanism: plain, scram-sha-256, or scram-sha-512
+broker_user: ""         # Kafka SASL or NATS user
+# broker_password: ""   # Password for broker_user; prefer the PORTSCAN_BROKER_PASSWORD environment variable
+nats_creds: ""          # NATS credentials file (user JWT and nkey seed)
+upload: ""              # Upload the finished export, e.g. "s3://bucket/scans/{date}/{scan_id}.json.gz"
+upload_endpoint: ""     # S3-compatible endpoint for upload, e.g. MinIO (empty = AWS or GCS)
+upload_region: ""       # S3 region for upload (empty = the bucket's region)
+otel_endpoint: ""       # OTLP/HTTP collector for scan traces and metrics, e.g. http://localhost:4318
 
 # UI preferences
 ui:
-  theme: default        # Options: default, dracula, monokai
+  theme: default        # auto, a built-in, or a custom theme (see: portscan themes list)
+  accessible: false     # Colorblind-safe state colors plus glyphs (● open, ✕ closed, ▲ filtered)
+  screen_reader: false  # Announce open ports and progress as plain lines instead of the TUI
+  ascii: false          # Draw the TUI with ASCII symbols (automatic on the legacy Windows console)
+  result_buffer_size: 10000 # Results kept in the TUI; older ones scroll out
+  spill_overflow: false # Save results beyond the buffer to a temp file so exports stay complete
+  columns: []           # Table columns in order, e.g. [host, port, state, service, rdns] (empty = default; C or Ctrl+K edits)
+  presets: {}           # Saved table views, applied from Ctrl+K ("Apply preset: <name>"), e.g.
+                        #   external-web: {state: open, ports: "80-443", service: http, sort: host}
 
 # DNS settings
 dns:
@@ -78,7 +172,8 @@ dns:
 
 # Logging
 quiet: false            # Suppress non-essential output
-no_color: false         # Disable colored output
+no_color: false         # Disable colored output (NO_COLOR is also honored)
+lang: ""                # Message language, en or es (empty follows LC_ALL/LC_MESSAGES/LANG)
 log_json: false         # Output logs in JSON format
 verbose: false          # Enable verbose debug output
 
@@ -124,6 +219,18 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 	fmt.Printf("  Timeout:    %d ms\n", viper.GetInt("timeout_ms"))
+	if pt := viper.GetString("port_timeouts"); pt != "" {
+		fmt.Printf("  Per-port:   %s\n", pt)
+	}
+	if timing := viper.GetString("timing"); timing != "" {
+		fmt.Printf("  Timing:     %s\n", timing)
+	}
+	if sw := viper.GetString("scan_window"); sw != "" {
+		fmt.Printf("  Window:     %s (spread: %v)\n", sw, viper.GetBool("spread"))
+	}
+	if overrides, ok := viper.Get("overrides").([]interface{}); ok && len(overrides) > 0 {
+		fmt.Printf("  Overrides:  %d target rules\n", len(overrides))
+	}
 
 	// Scan settings
 	fmt.Println("\nScan Defaults:")
@@ -138,11 +245,15 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	// UI settings
 	fmt.Println("\nUI:")
 	fmt.Printf("  Theme:      %s\n", viper.GetString("ui.theme"))
+	fmt.Printf("  Accessible: %v\n", viper.GetBool("ui.accessible"))
+	fmt.Printf("  Screen reader: %v\n", viper.GetBool("ui.screen_reader"))
+	fmt.Printf("  ASCII:      %v\n", viper.GetBool("ui.ascii"))
 
 	// Output settings
 	fmt.Println("\nOutput:")
 	fmt.Printf("  Quiet:      %v\n", viper.GetBool("quiet"))
 	fmt.Printf("  No Color:   %v\n", viper.GetBool("no_color"))
+	fmt.Printf("  Language:   %s\n", i18n.Language())
 	fmt.Printf("  JSON Logs:  %v\n", viper.GetBool("log_json"))
 	fmt.Printf("  Verbose:    %v\n", viper.GetBool("verbose"))
 
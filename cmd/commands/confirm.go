@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/spf13/viper"
+)
+
+// confirmProbeThreshold is the probe count above which a scan must be
+// confirmed.
+const confirmProbeThreshold = 100_000
+
+// scanRisks lists why a scan needs confirmation: too many probes, or
+// targets that are, or resolve to, public internet addresses. resolved
+// holds the addresses of the hostnames among hosts; a hostname missing
+// from it did not resolve and will not be probed.
+func scanRisks(hosts []string, resolved map[string][]string, est scanEstimate) []string {
+	var risks []string
+	if est.Probes > confirmProbeThreshold {
+		risks = append(risks, i18n.T("confirm.risks.probes", formatCount(est.Probes), formatCount(confirmProbeThreshold)))
+	}
+	public, names := 0, 0
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			if core.IsPublicIP(ip) {
+				public++
+			}
+			continue
+		}
+		for _, addr := range resolved[host] {
+			if ip := net.ParseIP(addr); ip != nil && core.IsPublicIP(ip) {
+				names++
+				break
+			}
+		}
+	}
+	if public > 0 {
+		risks = append(risks, i18n.T("confirm.risks.public", formatCount(public)))
+	}
+	if names > 0 {
+		risks = append(risks, i18n.T("confirm.risks.hostnames", formatCount(names)))
+	}
+	return risks
+}
+
+// resolveNames looks up the hostnames among hosts with the scan's
+// resolver, which caches the answers for the scan itself.
+func resolveNames(ctx context.Context, cfg *config.Config, hosts []string) map[string][]string {
+	resolver := scanResolver(cfg)
+	if resolver == nil {
+		return nil
+	}
+	resolver.Prefetch(ctx, hosts)
+	resolved := make(map[string][]string)
+	for _, host := range hosts {
+		if net.ParseIP(host) != nil {
+			continue
+		}
+		if addrs, err := resolver.LookupHost(ctx, host); err == nil {
+			resolved[host] = addrs
+		}
+	}
+	return resolved
+}
+
+// confirmScan asks before a scan that is large or reaches public
+// addresses, showing its estimate on stderr. --yes skips the question;
+// without a terminal to ask on, the reasons are printed as a warning and
+// the scan goes ahead.
+func confirmScan(hosts []string, est scanEstimate, cfg *config.Config) error {
+	if viper.GetBool("yes") {
+		return nil
+	}
+	interactive := isTerminal(os.Stdin) && !viper.GetBool("stdin")
+	resolved := resolveNames(context.Background(), cfg, hosts)
+	risks := scanRisks(hosts, resolved, est)
+	if len(risks) > 0 && cfg.OwnerLookups() {
+		if owners, more := publicOwners(context.Background(), cfg, hosts, resolved); more {
+			risks = append(risks, i18n.T("confirm.risks.owners_first", strings.Join(owners, ", "), ownerPreviewLimit))
+		} else if len(owners) > 0 {
+			risks = append(risks, i18n.T("confirm.risks.owners", strings.Join(owners, ", ")))
+		}
+	}
+	return promptScan(os.Stdin, os.Stderr, interactive, risks, est, cfg)
+}
+
+// promptScan asks on out about risks and reads the answer from in.
+func promptScan(in io.Reader, out io.Writer, interactive bool, risks []string, est scanEstimate, cfg *config.Config) error {
+	if len(risks) == 0 {
+		return nil
+	}
+	if !interactive {
+		fmt.Fprintln(out, i18n.T("confirm.warning", strings.Join(risks, "; ")))
+		return nil
+	}
+
+	fmt.Fprintln(out, i18n.T("confirm.includes"))
+	for _, risk := range risks {
+		fmt.Fprintf(out, "  - %s\n", risk)
+	}
+	fmt.Fprintln(out, i18n.T("confirm.targets",
+		formatCount(est.Hosts), formatCount(est.Probes), formatEstimate(est.Duration), cfg.Rate))
+	fmt.Fprint(out, i18n.T("confirm.prompt")+" ")
+
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	for _, yes := range strings.Split(i18n.T("confirm.answers"), ",") {
+		if answer != "" && answer == yes {
+			return nil
+		}
+	}
+	return &errors.UserError{
+		Code:       "SCAN_DECLINED",
+		Message:    i18n.T("errors.scan_declined.message"),
+		Details:    i18n.T("errors.scan_declined.details"),
+		Suggestion: i18n.T("errors.scan_declined.suggestion"),
+	}
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"bytes"
+	stdErrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/spf13/viper"
+)
+
+func TestScanRisks(t *testing.T) {
+	small := scanEstimate{Probes: 1000}
+	private := map[string][]string{"localhost": {"127.0.0.1", "::1"}, "db.internal": {"10.2.0.7"}}
+	if risks := scanRisks([]string{"10.0.0.1", "192.168.1.1", "127.0.0.1", "localhost", "db.internal", "100.64.1.1", "fd00::1"}, private, small); len(risks) != 0 {
+		t.Errorf("private targets in a small scan need no confirmation, got %q", risks)
+	}
+
+	risks := scanRisks([]string{"10.0.0.1"}, nil, scanEstimate{Probes: confirmProbeThreshold + 1})
+	if len(risks) != 1 || !strings.Contains(risks[0], "100,001 probes") {
+		t.Errorf("large scan: risks = %q", risks)
+	}
+
+	resolved := map[string][]string{"example.com": {"10.0.0.9", "93.184.215.14"}}
+	risks = scanRisks([]string{"8.8.8.8", "1.1.1.1", "example.com", "unresolvable.invalid"}, resolved, small)
+	if len(risks) != 2 || !strings.Contains(risks[0], "2 public") || !strings.Contains(risks[1], "1 hostname") {
+		t.Errorf("public targets: risks = %q", risks)
+	}
+}
+
+func TestPromptScan(t *testing.T) {
+	cfg := &config.Config{Rate: 1000}
+	est := scanEstimate{Hosts: 1, Probes: 100}
+	risks := scanRisks([]string{"8.8.8.8"}, nil, est)
+
+	var out bytes.Buffer
+	if err := promptScan(strings.NewReader("y\n"), &out, true, risks, est, cfg); err != nil {
+		t.Errorf("answering y should proceed: %v", err)
+	}
+	if !strings.Contains(out.String(), "public internet") || !strings.Contains(out.String(), "Proceed? [y/N]") {
+		t.Errorf("prompt should list the risks and ask:\n%s", out.String())
+	}
+
+	var userErr *errors.UserError
+	for _, answer := range []string{"\n", "n\n", ""} {
+		err := promptScan(strings.NewReader(answer), &out, true, risks, est, cfg)
+		if !stdErrors.As(err, &userErr) || userErr.Code != "SCAN_DECLINED" {
+			t.Errorf("answer %q: err = %v; want SCAN_DECLINED", answer, err)
+		}
+	}
+
+	// Without a terminal the scan goes ahead with a warning.
+	out.Reset()
+	err := promptScan(strings.NewReader(""), &out, false, risks, est, cfg)
+	if err != nil || !strings.Contains(out.String(), "warning: this scan includes 1 public") || !strings.Contains(out.String(), "--yes") {
+		t.Errorf("non-interactive: err = %v, output %q; want a warning suggesting --yes", err, out.String())
+	}
+
+	out.Reset()
+	if err := promptScan(strings.NewReader(""), &out, true, nil, est, cfg); err != nil || out.Len() != 0 {
+		t.Errorf("a scan without risks should not prompt: %v %q", err, out.String())
+	}
+}
+
+func TestConfirmScanYes(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("yes", true)
+	if err := confirmScan([]string{"8.8.8.8"}, scanEstimate{Probes: 1}, &config.Config{}); err != nil {
+		t.Errorf("--yes should skip confirmation: %v", err)
+	}
+}
+
+func TestPromptScanTranslated(t *testing.T) {
+	i18n.SetLanguage("es")
+	defer i18n.SetLanguage(i18n.Default)
+
+	est := scanEstimate{Hosts: 1, Probes: 100}
+	var out bytes.Buffer
+	err := promptScan(strings.NewReader("s\n"), &out, true, scanRisks([]string{"8.8.8.8"}, nil, est), est, &config.Config{Rate: 1000})
+	if err != nil {
+		t.Errorf("answering s in Spanish should proceed: %v", err)
+	}
+	if !strings.Contains(out.String(), "¿Continuar? [s/N]") || !strings.Contains(out.String(), "pública") {
+		t.Errorf("prompt not translated:\n%s", out.String())
+	}
+}
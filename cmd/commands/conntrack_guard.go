@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/spf13/viper"
+)
+
+// newConntrackGuard returns the --conntrack-limit guard pausing control,
+// or nil when the limit is off or the host has no conntrack table to
+// watch. Outside the TUI, unless --quiet is set, the table nearing its
+// limit and pauses are noted on stderr, pauses at most every
+// bandwidthNoticeInterval.
+func newConntrackGuard(cfg *config.Config, control core.Pausable) *core.ConntrackGuard {
+	if cfg.ConntrackLimit <= 0 {
+		return nil
+	}
+	if window := cfg.GetScanWindow(); window != nil {
+		control = windowedControl{Pausable: control, window: *window}
+	}
+	opts := core.ConntrackOptions{Limit: float64(cfg.ConntrackLimit) / 100}
+	if !viper.GetBool("quiet") && outputFormat(cfg) != "" {
+		opts.OnWarn = func(usage core.ConntrackUsage) {
+			fmt.Fprintf(os.Stderr, "Connection tracking table is %s; the scan pauses at %d%%. Filtered results may be dropped connections, not firewall rules\n",
+				conntrackUsage(usage), cfg.ConntrackLimit)
+		}
+		var lastNotice time.Time
+		opts.OnChange = func(paused bool, usage core.ConntrackUsage) {
+			if paused && time.Since(lastNotice) >= bandwidthNoticeInterval {
+				lastNotice = time.Now()
+				fmt.Fprintf(os.Stderr, "Connection tracking table is %s; pausing the scan until entries expire\n", conntrackUsage(usage))
+			}
+		}
+	}
+	guard, err := core.NewConntrackGuard(control, opts)
+	if err != nil {
+		return nil
+	}
+	return guard
+}
+
+// conntrackSummary describes the conntrack guard for dry runs, or returns
+// "" when it is off or has nothing to watch.
+func conntrackSummary(cfg *config.Config) string {
+	if cfg.ConntrackLimit <= 0 {
+		return ""
+	}
+	usage, err := core.ReadConntrack()
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("pause at %d%%, now %s", cfg.ConntrackLimit, conntrackUsage(usage))
+}
+
+// conntrackUsage renders the table's fill, e.g. "91% full (238000/262144)".
+func conntrackUsage(usage core.ConntrackUsage) string {
+	return fmt.Sprintf("%.0f%% full (%d/%d)", usage.Fraction()*100, usage.Count, usage.Max)
+}
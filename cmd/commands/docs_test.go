@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGenDocs_Markdown verifies one linked markdown file per command
+func TestGenDocs_Markdown(t *testing.T) {
+	dir := t.TempDir()
+	written, err := genDocs(rootCmd, "markdown", dir, time.Now())
+	if err != nil {
+		t.Fatalf("genDocs: %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if written != len(entries) {
+		t.Errorf("reported %d files, wrote %d", written, len(entries))
+	}
+
+	for _, name := range []string{"portscan.md", "portscan_scan.md", "portscan_themes_list.md"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s not written: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "portscan_help.md")); err == nil {
+		t.Error("the help command should not be documented")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "portscan_scan.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := string(data)
+	for _, want := range []string{"## portscan scan", "### Options", "--ports string", "### Options inherited from parent commands", "--no-color", "* [portscan](portscan.md)"} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("portscan_scan.md missing %q", want)
+		}
+	}
+	if strings.Contains(doc, "Auto generated") {
+		t.Error("markdown should not carry the generation date")
+	}
+}
+
+// TestGenDocs_Man verifies the man pages' header and sections
+func TestGenDocs_Man(t *testing.T) {
+	dir := t.TempDir()
+	written, err := genDocs(rootCmd, "man", dir, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("genDocs: %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if written != len(entries) {
+		t.Errorf("reported %d files, wrote %d", written, len(entries))
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "portscan-import.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(data)
+	for _, want := range []string{
+		`.TH "PORTSCAN-IMPORT" "1" "May 2024" "portscan ` + version + `" "Portscan Manual"`,
+		".SH NAME\nportscan-import - Convert",
+		".SH SYNOPSIS\n\\fBportscan import FILE [flags]\\fP",
+		"\\fB-o\\fP, \\fB--output\\fP=\"json\"",
+		".SH OPTIONS INHERITED FROM PARENT COMMANDS",
+		".SH SEE ALSO\n\\fBportscan(1)\\fP",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("man page missing %q:\n%s", want, page)
+		}
+	}
+}
+
+// TestGenDocs_UnknownFormat verifies the format is checked before writing
+func TestGenDocs_UnknownFormat(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "docs")
+	if _, err := genDocs(rootCmd, "html", dir, time.Now()); err == nil || !strings.Contains(err.Error(), "unsupported docs format") {
+		t.Errorf("err = %v, want an unsupported format error", err)
+	}
+	if _, err := os.Stat(dir); err == nil {
+		t.Error("no directory should be created for an unknown format")
+	}
+}
+
+// TestDocsDate verifies SOURCE_DATE_EPOCH pins the man page date
+func TestDocsDate(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1714521600")
+	if got := docsDate(); !got.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("docsDate() = %v, want 2024-05-01", got)
+	}
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/internal/doctor"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check system limits and settings before a large scan",
+	Long: `Check the host for limits that commonly break or slow down large scans and
+print a fix for each problem found:
+
+  file descriptors  the open file limit (ulimit -n) against the workers
+  raw sockets       whether CAP_NET_RAW is available (Linux)
+  conntrack table   nf_conntrack sizing and current usage (Linux)
+  local firewall    outbound iptables/nft rules that drop probes (Linux)
+
+Exits non-zero if any check fails.
+
+Examples:
+  portscan doctor
+  portscan doctor --workers 1000 --banners`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().IntP("workers", "w", 0, "worker count to check the open file limit against (0=auto-detect)")
+	doctorCmd.Flags().BoolP("banners", "b", false, "include the banner pool in the file descriptor check")
+	doctorCmd.Flags().Int("banner-workers", core.DefaultBannerWorkers, "banner pool size for the file descriptor check")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	opts := doctorOptions(cmd)
+	checks := doctor.Run(opts)
+	return reportDoctor(cmd.OutOrStdout(), opts, checks)
+}
+
+func doctorOptions(cmd *cobra.Command) doctor.Options {
+	workers, _ := cmd.Flags().GetInt("workers")
+	if workers <= 0 {
+		workers = getOptimalWorkerCount()
+	}
+	opts := doctor.Options{Workers: workers}
+	if banners, _ := cmd.Flags().GetBool("banners"); banners {
+		opts.BannerWorkers, _ = cmd.Flags().GetInt("banner-workers")
+	}
+	return opts
+}
+
+// reportDoctor prints the checks and returns an error if any failed.
+func reportDoctor(w io.Writer, opts doctor.Options, checks []doctor.Check) error {
+	fmt.Fprintf(w, "Checking for %d workers", opts.Workers)
+	if opts.BannerWorkers > 0 {
+		fmt.Fprintf(w, " and %d banner workers", opts.BannerWorkers)
+	}
+	fmt.Fprint(w, "\n\n")
+	doctor.Print(w, checks)
+
+	if failed := doctor.Failed(checks); failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	fmt.Fprintln(w, "\nNo blocking problems found.")
+	return nil
+}
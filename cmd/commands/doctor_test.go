@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/internal/doctor"
+)
+
+func TestDoctorOptions(t *testing.T) {
+	cmd := doctorCmd
+	t.Cleanup(func() {
+		_ = cmd.Flags().Set("workers", "0")
+		_ = cmd.Flags().Set("banners", "false")
+	})
+
+	if opts := doctorOptions(cmd); opts.Workers != getOptimalWorkerCount() || opts.BannerWorkers != 0 {
+		t.Errorf("defaults = %+v; want auto-detected workers and no banner pool", opts)
+	}
+
+	_ = cmd.Flags().Set("workers", "800")
+	_ = cmd.Flags().Set("banners", "true")
+	opts := doctorOptions(cmd)
+	if opts.Workers != 800 || opts.BannerWorkers != 50 {
+		t.Errorf("opts = %+v; want 800 workers and 50 banner workers", opts)
+	}
+}
+
+func TestReportDoctor(t *testing.T) {
+	var buf bytes.Buffer
+	ok := []doctor.Check{{Name: "raw sockets", Status: doctor.StatusOK}}
+	if err := reportDoctor(&buf, doctor.Options{Workers: 100}, ok); err != nil {
+		t.Fatalf("passing checks should not error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Checking for 100 workers") {
+		t.Errorf("report should name the worker count:\n%s", buf.String())
+	}
+
+	failed := append(ok, doctor.Check{Name: "file descriptors", Status: doctor.StatusFail})
+	err := reportDoctor(&buf, doctor.Options{Workers: 100}, failed)
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 checks failed") {
+		t.Errorf("err = %v; want a failed-check count", err)
+	}
+}
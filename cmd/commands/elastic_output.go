@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/spf13/viper"
+)
+
+// elasticTemplateName is the index template installed for --output elastic.
+const elasticTemplateName = "portscan"
+
+// elasticOutput adapts the bulk exporter to the streaming output path and
+// reports how many results were indexed when it closes.
+type elasticOutput struct {
+	*exporter.ElasticExporter
+	index string
+}
+
+// newElasticExporter builds the Elasticsearch/OpenSearch bulk exporter for
+// --output elastic. The API key comes from config or
+// PORTSCAN_ELASTIC_API_KEY so it stays out of the process list.
+func newElasticExporter(cfg *config.Config) (*elasticOutput, error) {
+	exp, err := exporter.NewElasticExporter(exporter.ElasticOptions{
+		URL:            cfg.ElasticURL,
+		Index:          cfg.ElasticIndex,
+		APIKey:         cfg.ElasticAPIKey,
+		TemplateName:   elasticTemplateName,
+		BatchSize:      cfg.ElasticBatch,
+		BannerEncoding: viper.GetString("banner_encoding"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &elasticOutput{ElasticExporter: exp, index: cfg.ElasticIndex}, nil
+}
+
+// Close flushes the last batch and reports the indexed count.
+func (o *elasticOutput) Close() error {
+	if err := o.ElasticExporter.Close(); err != nil {
+		return err
+	}
+	if !viper.GetBool("quiet") {
+		fmt.Fprintf(os.Stderr, "Indexed %d results into %s\n", o.Indexed(), o.index)
+	}
+	return nil
+}
+
+// redactURL hides the password in a URL before it is printed.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return u.Redacted()
+}
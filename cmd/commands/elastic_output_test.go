@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/spf13/viper"
+)
+
+func TestHandleScanOutput_Elastic(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("quiet", true)
+
+	var mu sync.Mutex
+	var paths, bulk []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/_bulk" {
+			bulk = append(bulk, string(body))
+		}
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"errors":false,"items":[]}`))
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Output: "elastic", ElasticURL: srv.URL, ElasticIndex: "scans"}
+	events := make(chan core.Event, 2)
+	events <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen})
+	close(events)
+
+	if err := handleScanOutput(context.Background(), cfg, events, 1, exporter.ScanMetadata{}, scanHandle{}); err != nil {
+		t.Fatalf("handleScanOutput: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(paths) != 2 || paths[0] != "PUT /_index_template/portscan" || paths[1] != "POST /_bulk" {
+		t.Fatalf("requests = %v, want the index template then one bulk request", paths)
+	}
+	if !strings.Contains(bulk[0], `"_index":"scans"`) || !strings.Contains(bulk[0], `"port":22`) {
+		t.Errorf("bulk body = %q", bulk[0])
+	}
+}
+
+func TestValidateInputs_Elastic(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	cfg := &config.Config{Ports: "80", Rate: 100, TimeoutMs: 200, Output: "elastic"}
+	err := validateInputs(cfg)
+	if err == nil || !strings.Contains(err.Error(), "Elasticsearch") {
+		t.Fatalf("validateInputs() = %v, want an Elasticsearch error", err)
+	}
+
+	cfg.ElasticURL = "http://localhost:9200"
+	if err := validateInputs(cfg); err != nil {
+		t.Errorf("validateInputs() = %v", err)
+	}
+}
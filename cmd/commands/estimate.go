@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+	"unsafe"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/internal/ui"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+// Estimate warning thresholds.
+const (
+	largeScanProbes   = 1_000_000
+	longScanDuration  = time.Hour
+	estimatePreview   = 10
+	resultEventSize   = int(unsafe.Sizeof(core.ResultEvent{}))
+	estimateHostBytes = 16 // typical IPv4 host string
+)
+
+// scanEstimate is the expected cost of a scan.
+type scanEstimate struct {
+	Hosts     int
+	Probes    int
+	Protocols int
+	// Duration is the time to send every probe at the configured rate.
+	Duration time.Duration
+	// WorstCase is the time when every probe times out and is retried,
+	// bounded by the number of concurrent workers.
+	WorstCase time.Duration
+	// BufferResults and BufferBytes describe the TUI result buffer; both
+	// are zero for streaming output, which keeps no results in memory.
+	BufferResults int
+	BufferBytes   int64
+	Warnings      []string
+}
+
+// estimateScan estimates a scan of probes host/port pairs across hosts.
+func estimateScan(hosts, probes int, cfg *config.Config) scanEstimate {
+	est := scanEstimate{Hosts: hosts, Probes: probes, Protocols: 1}
+	if normalizeProtocol(cfg.Protocol) == "both" && cfg.FromMasscan == "" {
+		est.Protocols = 2
+		est.Probes *= 2
+	}
+
+	if cfg.Rate > 0 {
+		est.Duration = time.Duration(float64(est.Probes) / float64(cfg.Rate) * float64(time.Second))
+	}
+	workers := cfg.Workers
+	if cfg.PerHostLimit > 0 && hosts*cfg.PerHostLimit < workers {
+		workers = hosts * cfg.PerHostLimit
+	}
+	if workers > 0 {
+		attempts := scanRetries(cfg.Retries) + 1
+		est.WorstCase = time.Duration(est.Probes) * time.Duration(attempts) * cfg.GetTimeout() / time.Duration(workers)
+	}
+	est.WorstCase = max(est.WorstCase, est.Duration)
+
+	if outputFormat(cfg) == "" {
+		// The TUI allocates its whole ring buffer up front.
+		capacity := cfg.UI.ResultBufferSize
+		if capacity <= 0 {
+			capacity = ui.DefaultResultBufferSize
+		}
+		est.BufferResults = capacity
+		est.BufferBytes = int64(capacity)*int64(resultEventSize) + int64(min(est.Probes, capacity))*estimateHostBytes
+		if est.Probes > capacity && !cfg.UI.SpillOverflow {
+			est.Warnings = append(est.Warnings, fmt.Sprintf(
+				"%s results exceed the TUI buffer of %s; older results will scroll out (set ui.spill_overflow, raise ui.result_buffer_size, or use --output-file)",
+				formatCount(est.Probes), formatCount(capacity)))
+		}
+	}
+
+	if est.Probes > largeScanProbes {
+		est.Warnings = append(est.Warnings, fmt.Sprintf(
+			"%s probes is over %s; consider narrowing --ports or the target ranges",
+			formatCount(est.Probes), formatCount(largeScanProbes)))
+	}
+	if est.Duration > longScanDuration {
+		est.Warnings = append(est.Warnings, fmt.Sprintf(
+			"the scan takes about %s at %d pps; consider a higher --rate or --scan-window",
+			formatEstimate(est.Duration), cfg.Rate))
+	}
+	return est
+}
+
+// showEstimate prints the target preview and scan estimate for --estimate.
+func showEstimate(w io.Writer, targets []string, portCount int, est scanEstimate, cfg *config.Config) {
+	fmt.Fprintln(w, "=== SCAN ESTIMATE ===")
+	fmt.Fprintf(w, "Targets:       %s hosts\n", formatCount(len(targets)))
+	for i, t := range targets {
+		if i == estimatePreview {
+			fmt.Fprintf(w, "               ... and %s more\n", formatCount(len(targets)-estimatePreview))
+			break
+		}
+		fmt.Fprintf(w, "               %s\n", t)
+	}
+	if cfg.FromMasscan != "" {
+		fmt.Fprintf(w, "Ports:         open ports listed in %s\n", cfg.FromMasscan)
+	} else {
+		fmt.Fprintf(w, "Ports:         %s per host\n", formatCount(portCount))
+	}
+	fmt.Fprintf(w, "Probes:        %s", formatCount(est.Probes))
+	if est.Protocols > 1 {
+		fmt.Fprint(w, " (TCP and UDP)")
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Duration:      ~%s at %d pps", formatEstimate(est.Duration), cfg.Rate)
+	if est.WorstCase > est.Duration {
+		fmt.Fprintf(w, ", up to %s if every port times out", formatEstimate(est.WorstCase))
+	}
+	fmt.Fprintln(w)
+	if est.BufferResults > 0 {
+		fmt.Fprintf(w, "Result Buffer: %s results, ~%s (TUI)\n", formatCount(est.BufferResults), formatBytes(uint64(est.BufferBytes)))
+	} else {
+		fmt.Fprintln(w, "Result Buffer: none, results are streamed")
+	}
+	if len(est.Warnings) > 0 {
+		fmt.Fprintln(w, "\nWarnings:")
+		for _, warning := range est.Warnings {
+			fmt.Fprintf(w, "  - %s\n", warning)
+		}
+	}
+	fmt.Fprintln(w, "\nRemove --estimate to run the scan.")
+}
+
+// formatCount renders n with thousands separators.
+func formatCount(n int) string {
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// formatEstimate rounds a duration to a readable precision.
+func formatEstimate(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return "<1s"
+	case d < time.Minute:
+		return d.Round(time.Second).String()
+	default:
+		return d.Round(time.Minute).String()
+	}
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+// TestEstimateScan verifies probe counts, durations and the TUI buffer estimate
+func TestEstimateScan(t *testing.T) {
+	cfg := &config.Config{Rate: 1000, Workers: 100, TimeoutMs: 200, Retries: 1, Protocol: "tcp"}
+	est := estimateScan(256, 256*100, cfg)
+
+	if est.Probes != 25600 || est.Protocols != 1 {
+		t.Errorf("probes = %d over %d protocols, want 25600 over 1", est.Probes, est.Protocols)
+	}
+	if est.Duration != 25600*time.Millisecond {
+		t.Errorf("duration = %v, want 25.6s", est.Duration)
+	}
+	// 25600 probes * 2 attempts * 200ms / 100 workers
+	if est.WorstCase != 102400*time.Millisecond {
+		t.Errorf("worst case = %v, want 1m42.4s", est.WorstCase)
+	}
+	if est.BufferResults == 0 || est.BufferBytes <= int64(est.BufferResults) {
+		t.Errorf("buffer = %d results, %d bytes; want the TUI buffer", est.BufferResults, est.BufferBytes)
+	}
+	if len(est.Warnings) != 1 || !strings.Contains(est.Warnings[0], "TUI buffer") {
+		t.Errorf("warnings = %q, want only the buffer overflow", est.Warnings)
+	}
+
+	cfg.Protocol = "both"
+	if both := estimateScan(256, 256*100, cfg); both.Probes != 51200 || both.Protocols != 2 {
+		t.Errorf("both: probes = %d over %d protocols, want 51200 over 2", both.Probes, both.Protocols)
+	}
+}
+
+// TestEstimateScan_Warnings verifies large and slow scans are flagged
+func TestEstimateScan_Warnings(t *testing.T) {
+	cfg := &config.Config{Rate: 100, Workers: 100, TimeoutMs: 200, Output: "json"}
+	est := estimateScan(65536, 65536*100, cfg)
+
+	if est.BufferResults != 0 {
+		t.Errorf("streaming output should not report a buffer, got %d", est.BufferResults)
+	}
+	warnings := strings.Join(est.Warnings, "\n")
+	for _, want := range []string{"6,553,600 probes", "--rate"} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("warnings %q missing %q", warnings, want)
+		}
+	}
+
+	cfg.UI.SpillOverflow = true
+	cfg.Output = ""
+	if small := estimateScan(1, 10, cfg); len(small.Warnings) != 0 {
+		t.Errorf("small scan warnings = %q, want none", small.Warnings)
+	}
+}
+
+// TestEstimateScan_PerHostLimit verifies per-host limits bound the worst case
+func TestEstimateScan_PerHostLimit(t *testing.T) {
+	cfg := &config.Config{Rate: 10000, Workers: 100, TimeoutMs: 1000, Retries: 1, PerHostLimit: 2, Output: "json"}
+	est := estimateScan(1, 10, cfg)
+	// 10 probes * 2 attempts * 1s / 2 workers
+	if est.WorstCase != 10*time.Second {
+		t.Errorf("worst case = %v, want 10s", est.WorstCase)
+	}
+}
+
+// TestShowEstimate verifies the preview is truncated and the totals printed
+func TestShowEstimate(t *testing.T) {
+	var targets []string
+	for i := 1; i <= 12; i++ {
+		targets = append(targets, fmt.Sprintf("10.0.0.%d", i))
+	}
+	cfg := &config.Config{Rate: 1000, Workers: 100, TimeoutMs: 200, Output: "json"}
+	est := estimateScan(len(targets), len(targets)*3, cfg)
+
+	var buf bytes.Buffer
+	showEstimate(&buf, targets, 3, est, cfg)
+	out := buf.String()
+	for _, want := range []string{"12 hosts", "10.0.0.10", "... and 2 more", "3 per host", "Probes:        36", "results are streamed", "Remove --estimate"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "10.0.0.11") {
+		t.Errorf("preview should stop after %d targets:\n%s", estimatePreview, out)
+	}
+}
+
+// TestFormatCount verifies thousands separators
+func TestFormatCount(t *testing.T) {
+	tests := map[int]string{0: "0", 999: "999", 1000: "1,000", 65536: "65,536", 1234567: "1,234,567", -4200: "-4,200"}
+	for n, want := range tests {
+		if got := formatCount(n); got != want {
+			t.Errorf("formatCount(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
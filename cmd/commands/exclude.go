@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/targets"
+	"github.com/spf13/viper"
+)
+
+// excludeTargets drops the targets --exclude-rdns or --exclude-tag match
+// from plans once targets are expanded and before anything is probed. A
+// target is excluded when the name it was given or a PTR name of one of
+// its addresses matches a name pattern, or when one of its tags matches a
+// tag pattern. It fails when no target is left.
+func excludeTargets(ctx context.Context, cfg *config.Config, plans []scanPlan) error {
+	exclusion, err := cfg.GetExclusion()
+	if err != nil || exclusion == nil {
+		// validateInputs reports invalid patterns.
+		return nil
+	}
+	var resolver *targets.Resolver
+	if len(exclusion.Names) > 0 {
+		if resolver = scanResolver(cfg); resolver != nil {
+			prefetchReverseNames(ctx, resolver, plans)
+		}
+	}
+
+	reasons := make(map[string]string) // excluded host -> what matched
+	checked := make(map[string]bool)
+	var excluded []string
+	left := 0
+	for i, plan := range plans {
+		kept := make([]core.ScanTarget, 0, len(plan.targets))
+		for _, t := range plan.targets {
+			if !checked[t.Host] {
+				checked[t.Host] = true
+				if match, ok := exclusionMatch(ctx, exclusion, resolver, cfg, t); ok {
+					reasons[t.Host] = match
+					excluded = append(excluded, excludedName(t.Host, match))
+				}
+			}
+			if _, ok := reasons[t.Host]; !ok {
+				kept = append(kept, t)
+			}
+		}
+		plans[i].targets = kept
+		left += len(kept)
+	}
+	if len(excluded) == 0 {
+		return nil
+	}
+	if left == 0 {
+		return errors.AllTargetsExcludedError(excluded, scopeListLimit)
+	}
+	if !viper.GetBool("quiet") {
+		named := excluded
+		if len(named) > scopeListLimit {
+			named = named[:scopeListLimit]
+		}
+		fmt.Fprintf(os.Stderr, "Excluded %d target(s) matching --exclude-rdns or --exclude-tag: %s", len(excluded), strings.Join(named, ", "))
+		if len(excluded) > len(named) {
+			fmt.Fprintf(os.Stderr, ", and %d more", len(excluded)-len(named))
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+	return nil
+}
+
+// prefetchReverseNames resolves the targets' hostnames and then the PTR
+// records of their addresses concurrently, so a large network is checked
+// without a lookup per host in turn.
+func prefetchReverseNames(ctx context.Context, resolver *targets.Resolver, plans []scanPlan) {
+	var hosts []string
+	seen := make(map[string]bool)
+	for _, plan := range plans {
+		for _, t := range plan.targets {
+			if !seen[t.Host] {
+				seen[t.Host] = true
+				hosts = append(hosts, t.Host)
+			}
+		}
+	}
+	resolver.Prefetch(ctx, hosts)
+
+	var addrs []string
+	for _, host := range hosts {
+		resolved, err := resolver.LookupHost(ctx, host)
+		if err == nil {
+			addrs = append(addrs, resolved...)
+		}
+	}
+	resolver.PrefetchAddrs(ctx, addrs)
+}
+
+// exclusionMatch returns the name or tag of t that exclusion matches.
+// Addresses are reverse-resolved only with a resolver; hostnames that fail
+// to resolve are checked by name alone.
+func exclusionMatch(ctx context.Context, exclusion *targets.Exclusion, resolver *targets.Resolver, cfg *config.Config, t core.ScanTarget) (string, bool) {
+	for _, name := range []string{t.Host, t.Hostname} {
+		if name != "" && net.ParseIP(name) == nil && exclusion.MatchName(name) {
+			return name, true
+		}
+	}
+	if len(exclusion.Names) > 0 && resolver != nil {
+		addrs, err := resolver.LookupHost(ctx, t.Host)
+		if err == nil {
+			for _, addr := range addrs {
+				names, _ := resolver.LookupAddr(ctx, addr)
+				for _, name := range names {
+					if exclusion.MatchName(name) {
+						return name, true
+					}
+				}
+			}
+		}
+	}
+	tags, ok := cfg.HostTags[t.Host]
+	if !ok && t.Hostname != "" {
+		tags = cfg.HostTags[t.Hostname]
+	}
+	return exclusion.MatchTags(append(cfg.GetTags(), tags...))
+}
+
+// excludedName names an excluded host with what matched it.
+func excludedName(host, match string) string {
+	if match == host {
+		return host
+	}
+	return fmt.Sprintf("%s (%s)", host, match)
+}
+
+// exclusionSummary describes the exclusion patterns for dry runs.
+func exclusionSummary(exclusion *targets.Exclusion) string {
+	var parts []string
+	if len(exclusion.Names) > 0 {
+		parts = append(parts, "rDNS "+strings.Join(exclusion.Names, ", "))
+	}
+	if len(exclusion.Tags) > 0 {
+		parts = append(parts, "tags "+strings.Join(exclusion.Tags, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
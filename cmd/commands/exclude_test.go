@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	stdErrors "errors"
+	"reflect"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+func TestExcludeTargets(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("quiet", true)
+
+	// Nothing listens on the DNS server, so PTR lookups fail fast and only
+	// target names and tags can match.
+	cfg := &config.Config{
+		DNSServer:    "127.0.0.1:1",
+		DNSTimeoutMs: 200,
+		ExcludeRDNS:  "*.scada.corp",
+		ExcludeTags:  "role=plc*",
+		HostTags: map[string][]string{
+			"10.0.0.7":  {"role=PLC-7"},
+			"10.0.0.8":  {"role=hmi"},
+			"hmi.local": {"role=plc"},
+		},
+	}
+	scanTargets := []core.ScanTarget{
+		{Host: "10.0.0.7"},
+		{Host: "10.0.0.8"},
+		{Host: "plc1.scada.corp"},
+		{Host: "10.0.0.9", Hostname: "hmi.local"},
+		{Host: "10.0.0.10", Hostname: "hmi2.SCADA.corp"},
+	}
+	plans := []scanPlan{{targets: scanTargets}, {targets: scanTargets}}
+	if err := excludeTargets(context.Background(), cfg, plans); err != nil {
+		t.Fatalf("excludeTargets: %v", err)
+	}
+	for i, plan := range plans {
+		if want := []core.ScanTarget{{Host: "10.0.0.8"}}; !reflect.DeepEqual(plan.targets, want) {
+			t.Errorf("plan %d targets = %+v, want %+v", i, plan.targets, want)
+		}
+	}
+	if scanTargets[0].Host != "10.0.0.7" {
+		t.Error("plans sharing a target slice should not have it filtered in place")
+	}
+
+	plans = []scanPlan{{targets: []core.ScanTarget{{Host: "10.0.0.7"}}}}
+	err := excludeTargets(context.Background(), cfg, plans)
+	var userErr *errors.UserError
+	if !stdErrors.As(err, &userErr) || userErr.Code != "ALL_TARGETS_EXCLUDED" {
+		t.Fatalf("err = %v; want ALL_TARGETS_EXCLUDED", err)
+	}
+
+	plans = []scanPlan{{targets: scanTargets}}
+	if err := excludeTargets(context.Background(), &config.Config{}, plans); err != nil || len(plans[0].targets) != len(scanTargets) {
+		t.Errorf("without patterns every target is kept: %v, %+v", err, plans[0].targets)
+	}
+}
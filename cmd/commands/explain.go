@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain [CODE]",
+	Short: "Explain an error code and how to fix it",
+	Long: `Print the catalog entry for an error code, as shown in error messages,
+--error-format json output and logs: what it means and steps to resolve it.
+Without a code, list every code in the catalog.`,
+	Example: `  portscan explain RATE_LIMIT_HIGH
+  portscan explain`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExplain,
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		printErrorCatalog(os.Stdout)
+		return nil
+	}
+	entry, ok := errors.Explain(args[0])
+	if !ok {
+		return errors.UnknownCodeError(args[0])
+	}
+	printCatalogEntry(os.Stdout, entry)
+	return nil
+}
+
+// printErrorCatalog lists every code with its title.
+func printErrorCatalog(w io.Writer) {
+	for _, code := range errors.CatalogCodes() {
+		entry, _ := errors.Explain(code)
+		fmt.Fprintf(w, "  %-26s %s\n", code, entry.Title)
+	}
+	fmt.Fprintln(w, "\nRun 'portscan explain CODE' for details.")
+}
+
+// printCatalogEntry writes an entry's explanation and numbered remediation
+// steps.
+func printCatalogEntry(w io.Writer, entry errors.CatalogEntry) {
+	fmt.Fprintf(w, "%s: %s\n\n", entry.Code, entry.Title)
+	for _, line := range strings.Split(entry.Explanation, "\n") {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+	fmt.Fprintln(w, "\nTo fix:")
+	for i, step := range entry.Remediation {
+		fmt.Fprintf(w, "  %d. %s\n", i+1, step)
+	}
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+)
+
+func TestPrintCatalogEntry(t *testing.T) {
+	entry, ok := errors.Explain("RATE_LIMIT_HIGH")
+	if !ok {
+		t.Fatal("RATE_LIMIT_HIGH missing from the catalog")
+	}
+	var buf bytes.Buffer
+	printCatalogEntry(&buf, entry)
+
+	out := buf.String()
+	for _, want := range []string{"RATE_LIMIT_HIGH: ", "To fix:", "  1. "} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunExplain_UnknownCode(t *testing.T) {
+	err := runExplain(explainCmd, []string{"NOT_A_CODE"})
+	if err == nil || !strings.Contains(err.Error(), "NOT_A_CODE") {
+		t.Errorf("expected unknown code error, got %v", err)
+	}
+}
+
+// TestErrorCatalog_CoversSource fails when a UserError code used anywhere in
+// the tree has no catalog entry for 'portscan explain'.
+func TestErrorCatalog_CoversSource(t *testing.T) {
+	codePattern := regexp.MustCompile(`(?:Code:\s+|\.Code = )"([A-Z][A-Z_]+)"`)
+	for _, dir := range []string{"../../cmd", "../../internal", "../../pkg"} {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			for _, m := range codePattern.FindAllSubmatch(data, -1) {
+				if _, ok := errors.Explain(string(m[1])); !ok {
+					t.Errorf("%s: code %s has no entry in pkg/errors/catalog.yaml", path, m[1])
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
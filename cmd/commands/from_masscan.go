@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"sort"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/lucchesi-sec/portscan/pkg/importer"
+	"github.com/spf13/viper"
+)
+
+// runFromMasscan scans the open ports listed in a masscan report instead
+// of target arguments, so masscan's discovery can be verified and enriched
+// with banners. Each host is probed only on its own open ports, with the
+// protocol masscan found them on.
+func runFromMasscan(args []string, cfg *config.Config) error {
+	if len(args) > 0 || viper.GetBool("stdin") {
+		return &errors.UserError{
+			Code:       "INVALID_IMPORT",
+			Message:    i18n.T("errors.import_with_targets.message"),
+			Details:    i18n.T("errors.import_with_targets.details"),
+			Suggestion: i18n.T("errors.import_with_targets.suggestion"),
+		}
+	}
+
+	byProtocol, err := loadMasscanTargets(cfg.FromMasscan)
+	if err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_IMPORT",
+			Message:    i18n.T("errors.import_unreadable.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.import_unreadable.suggestion"),
+		}
+	}
+	hosts, ports := masscanHostsAndPorts(byProtocol)
+	if len(hosts) == 0 {
+		return &errors.UserError{
+			Code:       "NO_TARGET",
+			Message:    i18n.T("errors.import_no_open_ports.message"),
+			Details:    i18n.T("errors.import_no_open_ports.details", cfg.FromMasscan),
+			Suggestion: i18n.T("errors.import_no_open_ports.suggestion"),
+		}
+	}
+	if err := validateRawTargets(hosts, targetPolicy(cfg)); err != nil {
+		return err
+	}
+
+	if viper.GetBool("estimate") {
+		est := estimateScan(len(hosts), countProbes(byProtocol), cfg)
+		showEstimate(os.Stdout, hosts, len(ports), est, cfg)
+		return nil
+	}
+	if viper.GetBool("dry_run") {
+		showDryRun(hosts, ports, cfg)
+		return nil
+	}
+	if err := confirmScan(hosts, estimateScan(len(hosts), countProbes(byProtocol), cfg), cfg); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	cleanupInterrupts := monitorInterrupts(cancel)
+	defer cleanupInterrupts()
+
+	return executeTargetScan(ctx, byProtocol, cfg)
+}
+
+// loadMasscanTargets reads a masscan report and groups its open ports by
+// protocol and host.
+func loadMasscanTargets(path string) (map[string][]core.ScanTarget, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	report, err := importer.Parse(importer.FormatAuto, f)
+	if err != nil {
+		return nil, err
+	}
+
+	byProtocol := make(map[string][]core.ScanTarget)
+	index := make(map[string]int)
+	for _, r := range report.Results {
+		if r.State != core.StateOpen {
+			continue
+		}
+		key := r.Protocol + "/" + r.Host
+		i, ok := index[key]
+		if !ok {
+			i = len(byProtocol[r.Protocol])
+			index[key] = i
+			byProtocol[r.Protocol] = append(byProtocol[r.Protocol], core.ScanTarget{Host: r.Host})
+		}
+		target := &byProtocol[r.Protocol][i]
+		target.Ports = append(target.Ports, r.Port)
+	}
+	return byProtocol, nil
+}
+
+// masscanHostsAndPorts returns every host and the sorted union of ports,
+// for validation and the dry run.
+func masscanHostsAndPorts(byProtocol map[string][]core.ScanTarget) ([]string, []uint16) {
+	var hosts []string
+	var ports []uint16
+	seenHost := make(map[string]bool)
+	seenPort := make(map[uint16]bool)
+	for _, protocol := range []string{"tcp", "udp"} {
+		for _, t := range byProtocol[protocol] {
+			if !seenHost[t.Host] {
+				seenHost[t.Host] = true
+				hosts = append(hosts, t.Host)
+			}
+			for _, p := range t.Ports {
+				if !seenPort[p] {
+					seenPort[p] = true
+					ports = append(ports, p)
+				}
+			}
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	return hosts, ports
+}
+
+// countProbes returns how many host/port pairs the targets cover.
+func countProbes(byProtocol map[string][]core.ScanTarget) int {
+	probes := 0
+	for _, targets := range byProtocol {
+		for _, t := range targets {
+			probes += len(t.Ports)
+		}
+	}
+	return probes
+}
+
+// executeTargetScan scans per-host port lists, running a TCP and a UDP
+// scanner for the protocols that have targets, plus one per matching
+// config override.
+func executeTargetScan(ctx context.Context, byProtocol map[string][]core.ScanTarget, cfg *config.Config) error {
+	cfg = withSpreadRate(cfg, countProbes(byProtocol))
+
+	budget := core.NewRateBudget(cfg.Rate)
+	if budget != nil {
+		defer budget.Stop()
+	}
+	var plans []scanPlan
+	for _, protocol := range []string{"tcp", "udp"} {
+		if len(byProtocol[protocol]) == 0 {
+			continue
+		}
+		protocolPlans, err := planScans(ctx, cfg, protocol, byProtocol[protocol], budgetFactory(budget))
+		if err != nil {
+			return err
+		}
+		plans = append(plans, protocolPlans...)
+	}
+	if len(plans) == 0 {
+		return errors.NoTargetError()
+	}
+	return runScanPlans(ctx, plans, cfg)
+}
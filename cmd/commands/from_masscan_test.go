@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/spf13/viper"
+)
+
+func writeMasscanList(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "masscan.txt")
+	content := "#masscan\n" + strings.Join(lines, "\n") + "\n# end\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestLoadMasscanTargets verifies open ports are grouped by protocol and host
+func TestLoadMasscanTargets(t *testing.T) {
+	path := writeMasscanList(t,
+		"open tcp 22 10.0.0.1 1714564800",
+		"open tcp 80 10.0.0.1 1714564800",
+		"open udp 53 10.0.0.1 1714564800",
+		"closed tcp 23 10.0.0.2 1714564800",
+		"open tcp 443 10.0.0.3 1714564800",
+	)
+	byProtocol, err := loadMasscanTargets(path)
+	if err != nil {
+		t.Fatalf("loadMasscanTargets: %v", err)
+	}
+
+	tcp := byProtocol["tcp"]
+	if len(tcp) != 2 || tcp[0].Host != "10.0.0.1" || len(tcp[0].Ports) != 2 || tcp[1].Host != "10.0.0.3" {
+		t.Errorf("tcp targets = %+v", tcp)
+	}
+	if udp := byProtocol["udp"]; len(udp) != 1 || udp[0].Ports[0] != 53 {
+		t.Errorf("udp targets = %+v", udp)
+	}
+
+	hosts, ports := masscanHostsAndPorts(byProtocol)
+	if fmt.Sprint(hosts) != "[10.0.0.1 10.0.0.3]" || fmt.Sprint(ports) != "[22 53 80 443]" {
+		t.Errorf("hosts %v, ports %v", hosts, ports)
+	}
+}
+
+// TestRunFromMasscan_Errors verifies target arguments and empty results are rejected
+func TestRunFromMasscan_Errors(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	path := writeMasscanList(t, "open tcp 22 10.0.0.1 1714564800")
+	if err := runFromMasscan([]string{"10.0.0.2"}, &config.Config{FromMasscan: path}); err == nil {
+		t.Error("targets together with --from-masscan should be rejected")
+	}
+
+	empty := writeMasscanList(t, "closed tcp 22 10.0.0.1 1714564800")
+	if err := runFromMasscan(nil, &config.Config{FromMasscan: empty}); err == nil || !strings.Contains(err.Error(), "No open ports") {
+		t.Errorf("err = %v, want no open ports", err)
+	}
+	if err := runFromMasscan(nil, &config.Config{FromMasscan: filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Error("a missing file should be rejected")
+	}
+}
+
+// TestExecuteTargetScan_OnlyListedPorts verifies each host is probed only on
+// the ports masscan reported
+func TestExecuteTargetScan_OnlyListedPorts(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("quiet", true)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			fmt.Fprint(conn, "SSH-2.0-Test\r\n")
+			conn.Close()
+		}
+	}()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	path := writeMasscanList(t, fmt.Sprintf("open tcp %d 127.0.0.1 1714564800", port))
+	byProtocol, err := loadMasscanTargets(path)
+	if err != nil {
+		t.Fatalf("loadMasscanTargets: %v", err)
+	}
+
+	out := filepath.Join(t.TempDir(), "verified.ndjson")
+	cfg := &config.Config{Workers: 4, TimeoutMs: 500, Rate: 100, Banners: true, OutputFile: out, AllowLocalhost: true}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := executeTargetScan(ctx, byProtocol, cfg); err != nil {
+		t.Fatalf("executeTargetScan: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("output not written: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], fmt.Sprintf(`"port":%d`, port)) || !strings.Contains(lines[0], "SSH-2.0-Test") {
+		t.Errorf("output = %q, want one open result with its banner", data)
+	}
+}
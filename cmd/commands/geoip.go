@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/geoip"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+)
+
+// openGeoIP opens the --geoip-db files.
+func openGeoIP(paths []string) (*geoip.DB, error) {
+	db, err := geoip.Open(paths...)
+	if err != nil {
+		return nil, &errors.UserError{
+			Code:       "INVALID_GEOIP_DB",
+			Message:    i18n.T("errors.invalid_geoip_db.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_geoip_db.suggestion"),
+			WrappedErr: err,
+		}
+	}
+	return db, nil
+}
+
+// locateResults sets Country, ASN and ASOrg on every result from locate,
+// which is called once per host. A hostname result is located by its
+// first address, resolved through resolver or, when it is nil, the system
+// resolver.
+func locateResults(ctx context.Context, events <-chan core.Event, locate func(netip.Addr) geoip.Location, resolver core.HostResolver) <-chan core.Event {
+	out := make(chan core.Event, cap(events))
+	go func() {
+		defer close(out)
+		located := make(map[string]geoip.Location)
+		for event := range events {
+			if event.Kind == core.EventKindResult && event.Result != nil {
+				host := event.Result.Host
+				loc, ok := located[host]
+				if !ok {
+					loc = locateHost(ctx, host, locate, resolver)
+					located[host] = loc
+				}
+				event.Result.Country, event.Result.ASN, event.Result.ASOrg = loc.Country, loc.ASN, loc.ASOrg
+			}
+			out <- event
+		}
+	}()
+	return out
+}
+
+// locateHost looks up host's address, resolving a hostname first.
+func locateHost(ctx context.Context, host string, locate func(netip.Addr) geoip.Location, resolver core.HostResolver) geoip.Location {
+	addr := host
+	if net.ParseIP(host) == nil {
+		var addrs []string
+		var err error
+		if resolver != nil {
+			addrs, err = resolver.LookupHost(ctx, host)
+		} else {
+			addrs, err = net.DefaultResolver.LookupHost(ctx, host)
+		}
+		if err != nil || len(addrs) == 0 {
+			return geoip.Location{}
+		}
+		addr = addrs[0]
+	}
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return geoip.Location{}
+	}
+	return locate(ip.WithZone(""))
+}
+
+// geoipSummary lists the --geoip-db files for dry runs.
+func geoipSummary(paths []string) string {
+	return strings.Join(paths, ", ")
+}
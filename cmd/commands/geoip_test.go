@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/geoip"
+)
+
+// staticResolver resolves hostnames from a fixed table.
+type staticResolver map[string][]string
+
+func (r staticResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	return r[host], nil
+}
+
+func TestLocateResults(t *testing.T) {
+	google := geoip.Location{Country: "US", CountryName: "United States", ASN: 15169, ASOrg: "GOOGLE"}
+	lookups := map[netip.Addr]int{}
+	locate := func(ip netip.Addr) geoip.Location {
+		lookups[ip]++
+		if ip == netip.MustParseAddr("8.8.8.8") {
+			return google
+		}
+		return geoip.Location{}
+	}
+
+	events := make(chan core.Event, 4)
+	events <- core.NewResultEvent(core.ResultEvent{Host: "8.8.8.8", Port: 53, State: core.StateOpen})
+	events <- core.NewResultEvent(core.ResultEvent{Host: "8.8.8.8", Port: 443, State: core.StateOpen})
+	events <- core.NewResultEvent(core.ResultEvent{Host: "dns.example.test", Port: 53, State: core.StateOpen})
+	events <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen})
+	close(events)
+
+	resolver := staticResolver{"dns.example.test": {"8.8.8.8"}}
+	var results []core.ResultEvent
+	for event := range locateResults(context.Background(), events, locate, resolver) {
+		results = append(results, *event.Result)
+	}
+
+	for _, r := range results[:3] {
+		if r.Country != "US" || r.ASN != 15169 || r.ASOrg != "GOOGLE" {
+			t.Errorf("%s:%d located as %q AS%d %q, want US AS15169 GOOGLE", r.Host, r.Port, r.Country, r.ASN, r.ASOrg)
+		}
+	}
+	if r := results[3]; r.Country != "" || r.ASN != 0 {
+		t.Errorf("private host located as %q AS%d", r.Country, r.ASN)
+	}
+	if n := lookups[netip.MustParseAddr("8.8.8.8")]; n != 2 {
+		t.Errorf("8.8.8.8 looked up %d times, want once per host", n)
+	}
+}
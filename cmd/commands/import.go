@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/lucchesi-sec/portscan/pkg/importer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Convert another scanner's results into portscan output",
+	Long: `Read results saved by another scanner and write them in a portscan export
+format, so archived scans can be compared with new portscan runs or fed to the
+same tooling. Use - to read from stdin.
+
+Supported formats:
+  nmap-xml       nmap -oX output (also the .xml file of -oA) and masscan -oX
+  masscan-json   masscan -oJ or -oD output
+  masscan-list   masscan -oL output
+
+The format is detected from the content unless --format is given.
+
+Examples:
+  portscan import scan.xml > scan.ndjson
+  portscan import --output csv --only-open archive/2023-01.xml
+  portscan import scan.xml --output-file scan.ndjson.gz
+  portscan import --format masscan-list masscan.txt`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	addImportFlags(importCmd)
+	registerImportCompletions(importCmd)
+}
+
+// addImportFlags registers the input and output flags on cmd.
+func addImportFlags(cmd *cobra.Command) {
+	cmd.Flags().String("format", importer.FormatAuto, "input format: auto, nmap-xml, masscan-json, or masscan-list")
+	cmd.Flags().StringP("output", "o", "json", "output format: json, csv, html, or table")
+	cmd.Flags().String("output-file", "", "write results to a file atomically instead of stdout; a .gz or .zst name compresses it")
+	cmd.Flags().String("compress", "", "compress the output: gzip, zstd or none (default: by --output-file suffix)")
+	cmd.Flags().Bool("only-open", false, "import only open ports")
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	format, _ := flags.GetString("format")
+	onlyOpen, _ := flags.GetBool("only-open")
+	cfg, err := importOutputConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	report, err := readImport(args[0], format, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	cleanupInterrupts := monitorInterrupts(cancel)
+	defer cleanupInterrupts()
+	written, err := writeImport(ctx, cfg, report, onlyOpen)
+	if err != nil {
+		return err
+	}
+	if interrupted(ctx) {
+		return &errors.ExitError{Code: errors.ExitAborted, Err: errInterrupted}
+	}
+	if !viper.GetBool("quiet") {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Imported %d results for %d hosts from %s", written, len(report.Targets), report.Scanner)
+		if report.Skipped > 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), " (%d ports skipped)", report.Skipped)
+		}
+		fmt.Fprintln(cmd.ErrOrStderr())
+	}
+	return nil
+}
+
+// importOutputConfig maps the output flags onto the config fields the
+// shared output path reads.
+func importOutputConfig(cmd *cobra.Command) (*config.Config, error) {
+	flags := cmd.Flags()
+	output, _ := flags.GetString("output")
+	outputFile, _ := flags.GetString("output-file")
+	compress, _ := flags.GetString("compress")
+
+	switch output {
+	case "json", "csv", "html", "table":
+	default:
+		return nil, fmt.Errorf("unsupported output format %q: use json, csv, html, or table", output)
+	}
+	switch compress {
+	case "", exporter.CompressionNone, exporter.CompressionGzip, exporter.CompressionZstd:
+	default:
+		return nil, fmt.Errorf("unsupported compression %q: use gzip, zstd or none", compress)
+	}
+	return &config.Config{Output: output, OutputFile: outputFile, Compress: compress}, nil
+}
+
+// readImport parses path, or stdin for "-".
+func readImport(path, format string, stdin io.Reader) (*importer.Report, error) {
+	if path == "-" {
+		return importer.Parse(format, stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	report, err := importer.Parse(format, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return report, nil
+}
+
+// writeImport streams the report's results through the configured exporter
+// and returns how many were written.
+func writeImport(ctx context.Context, cfg *config.Config, report *importer.Report, onlyOpen bool) (int, error) {
+	out, finish, err := openScanOutput(cfg)
+	if err != nil {
+		return 0, err
+	}
+	metadata := exporter.ScanMetadata{Targets: report.Targets}
+	exp := newStreamExporter(ctx, cfg, cfg.Output, out, metadata)
+
+	events := make(chan core.Event)
+	written := 0
+	go func() {
+		defer close(events)
+		for _, r := range report.Results {
+			if onlyOpen && r.State != core.StateOpen {
+				continue
+			}
+			select {
+			case events <- core.NewResultEvent(r):
+				written++
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	err = finish(streamEvents(ctx, events, exp.Export, exp.Close))
+	return written, err
+}
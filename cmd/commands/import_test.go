@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const importTestXML = `<?xml version="1.0"?>
+<nmaprun scanner="nmap" version="7.94" start="1714564800">
+<host><status state="up"/><address addr="10.0.0.1" addrtype="ipv4"/>
+<ports>
+<port protocol="tcp" portid="22"><state state="open"/><service name="ssh" product="OpenSSH" version="9.6"/></port>
+<port protocol="tcp" portid="23"><state state="closed"/></port>
+</ports></host>
+</nmaprun>`
+
+// newImportTestCommand returns a fresh import command so flag values do not
+// leak between tests.
+func newImportTestCommand(args ...string) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{Use: "import", Args: cobra.ExactArgs(1), RunE: runImport}
+	addImportFlags(cmd)
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+	cmd.SetArgs(args)
+	return cmd, &stderr
+}
+
+// TestImportCommand verifies import is registered with its flags
+func TestImportCommand(t *testing.T) {
+	found := false
+	for _, sub := range rootCmd.Commands() {
+		if sub == importCmd {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("import should be registered under root")
+	}
+	for _, name := range []string{"format", "output", "output-file", "compress", "only-open"} {
+		if importCmd.Flags().Lookup(name) == nil {
+			t.Errorf("missing --%s flag", name)
+		}
+	}
+}
+
+// TestRunImport_NmapToCSV verifies nmap results are written as portscan CSV
+func TestRunImport_NmapToCSV(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	dir := t.TempDir()
+	in := filepath.Join(dir, "scan.xml")
+	if err := os.WriteFile(in, []byte(importTestXML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "scan.csv")
+
+	cmd, stderr := newImportTestCommand(in, "--output", "csv", "--output-file", out, "--only-open")
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("output not written: %v", err)
+	}
+	csv := string(data)
+	if !strings.Contains(csv, "10.0.0.1,22,") || !strings.Contains(csv, "OpenSSH 9.6") {
+		t.Errorf("CSV = %q, want the open SSH port", csv)
+	}
+	if strings.Contains(csv, ",23,") {
+		t.Errorf("--only-open should drop the closed port: %q", csv)
+	}
+	if !strings.Contains(stderr.String(), "Imported 1 results for 1 hosts from nmap 7.94") {
+		t.Errorf("summary = %q", stderr.String())
+	}
+}
+
+// TestRunImport_Errors verifies bad input and output settings are reported
+func TestRunImport_Errors(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	dir := t.TempDir()
+	notXML := filepath.Join(dir, "scan.txt")
+	if err := os.WriteFile(notXML, []byte("10.0.0.1:22\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := [][]string{
+		{filepath.Join(dir, "missing.xml")},
+		{notXML},
+		{notXML, "--output", "kafka"},
+	}
+	for _, args := range tests {
+		cmd, _ := newImportTestCommand(args...)
+		cmd.SilenceUsage = true
+		if err := cmd.Execute(); err == nil {
+			t.Errorf("import %v should fail", args)
+		}
+	}
+}
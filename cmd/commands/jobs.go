@@ -0,0 +1,381 @@
+package commands
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/lucchesi-sec/portscan/pkg/parser"
+	"github.com/lucchesi-sec/portscan/pkg/profiles"
+	"github.com/spf13/viper"
+)
+
+// scanJobRun is a --job ready to run: its resolved targets and ports, and
+// the config its scan and export use.
+type scanJobRun struct {
+	name   string
+	hosts  []string
+	ports  []uint16
+	format string // json, csv, html, or table
+	cfg    *config.Config
+}
+
+// runJobs runs every --job concurrently, each with its own targets, ports
+// and export file, while all of them draw probes from one --rate budget.
+func runJobs(args []string, cfg *config.Config) error {
+	if len(args) > 0 || viper.GetBool("stdin") {
+		return &errors.UserError{
+			Code:       "INVALID_JOB",
+			Message:    i18n.T("errors.job_with_targets.message"),
+			Details:    i18n.T("errors.job_with_targets.details"),
+			Suggestion: i18n.T("errors.job_with_targets.suggestion"),
+		}
+	}
+
+	specs, err := cfg.GetJobs()
+	if err != nil {
+		return err
+	}
+	jobs := make([]scanJobRun, 0, len(specs))
+	files := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		job, err := loadJob(spec, cfg)
+		if err != nil {
+			return fmt.Errorf("job %s: %w", spec.Name, err)
+		}
+		if other, ok := files[job.cfg.OutputFile]; ok {
+			return &errors.UserError{
+				Code:       "INVALID_JOB",
+				Message:    i18n.T("errors.job_same_file.message"),
+				Details:    i18n.T("errors.job_same_file.details", other, job.name, job.cfg.OutputFile),
+				Suggestion: i18n.T("errors.job_same_file.suggestion"),
+			}
+		}
+		files[job.cfg.OutputFile] = job.name
+		jobs = append(jobs, job)
+	}
+
+	if viper.GetBool("estimate") {
+		showJobsEstimate(os.Stdout, jobs, cfg)
+		return nil
+	}
+	if viper.GetBool("dry_run") {
+		showJobsDryRun(os.Stdout, jobs, cfg)
+		return nil
+	}
+	if err := confirmJobs(jobs, cfg); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	cleanupInterrupts := monitorInterrupts(cancel)
+	defer cleanupInterrupts()
+
+	return runJobsConcurrently(ctx, jobs, cfg.Rate)
+}
+
+// runJobsConcurrently runs the jobs side by side under a shared rate of
+// rate probes per second and returns their combined result.
+func runJobsConcurrently(ctx context.Context, jobs []scanJobRun, rate int) error {
+	budget := core.NewRateBudget(rate)
+	if budget != nil {
+		defer budget.Stop()
+	}
+
+	errs := make([]error, len(jobs))
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = runJob(ctx, job, budget)
+		}()
+	}
+	wg.Wait()
+	return jobsError(ctx, jobs, errs)
+}
+
+// loadJob reads a job's targets file and resolves its ports, format and
+// export file against the scan's config.
+func loadJob(spec parser.JobSpec, cfg *config.Config) (scanJobRun, error) {
+	raw, err := readJobTargets(spec.TargetsFile)
+	if err != nil {
+		return scanJobRun{}, &errors.UserError{
+			Code:       "INVALID_JOB",
+			Message:    i18n.T("errors.job_targets_unreadable.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.job_targets_unreadable.suggestion"),
+		}
+	}
+	if len(raw) == 0 {
+		return scanJobRun{}, errors.NoTargetError()
+	}
+	if err := validateRawTargets(raw, targetPolicy(cfg)); err != nil {
+		return scanJobRun{}, err
+	}
+	hosts, err := resolveTargetList(raw)
+	if err != nil {
+		return scanJobRun{}, errors.InvalidTargetListError(err)
+	}
+	ports, err := jobPorts(spec, cfg)
+	if err != nil {
+		return scanJobRun{}, err
+	}
+
+	format := jobFormat(spec, cfg)
+	jobCfg := *cfg
+	jobCfg.Jobs = nil
+	jobCfg.Output = format
+	jobCfg.OutputFile = jobFile(spec, format, cfg)
+	if spec.Protocol != "" {
+		jobCfg.Protocol = spec.Protocol
+	}
+	return scanJobRun{name: spec.Name, hosts: hosts, ports: ports, format: format, cfg: &jobCfg}, nil
+}
+
+// readJobTargets reads the whitespace-separated targets in path, ignoring
+// '#' comments.
+func readJobTargets(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		raw = append(raw, strings.Fields(line)...)
+	}
+	return raw, nil
+}
+
+// jobPorts returns the job's profile or port list, falling back to the
+// scan's --profile or --ports.
+func jobPorts(spec parser.JobSpec, cfg *config.Config) ([]uint16, error) {
+	switch {
+	case spec.Profile != "":
+		ports := profiles.GetProfile(spec.Profile)
+		if ports == nil {
+			return nil, fmt.Errorf("unknown profile '%s'. Available: quick, web, database, full", spec.Profile)
+		}
+		return ports, nil
+	case spec.Ports != "":
+		ports, err := parser.ParsePorts(spec.Ports)
+		if err != nil {
+			return nil, errors.InvalidPortError(spec.Ports, err)
+		}
+		return ports, nil
+	default:
+		return selectPortList(cfg)
+	}
+}
+
+// jobFormat returns the job's export format: its own output, else the
+// scan's when that writes a file, else json.
+func jobFormat(spec parser.JobSpec, cfg *config.Config) string {
+	if spec.Output != "" {
+		return spec.Output
+	}
+	switch format := outputFormat(cfg); format {
+	case "json", "csv", "html", "table":
+		return format
+	}
+	return "json"
+}
+
+// jobFile returns the job's export file: its own file, else the job name
+// with the format's extension.
+func jobFile(spec parser.JobSpec, format string, cfg *config.Config) string {
+	if spec.File != "" {
+		return spec.File
+	}
+	ext := "." + format
+	switch format {
+	case "json":
+		if !viper.GetBool("json_array") && !viper.GetBool("json_object") {
+			ext = ".ndjson"
+		}
+	case "table":
+		ext = ".txt"
+	}
+	return spec.Name + ext + exporter.CompressionExtension(cfg.Compress)
+}
+
+// runJob scans one job's targets, drawing probes from budget, and writes
+// its results to the job's file.
+func runJob(ctx context.Context, job scanJobRun, budget *core.RateBudget) error {
+	protocols := []string{"tcp"}
+	switch normalizeProtocol(job.cfg.Protocol) {
+	case "udp":
+		protocols = []string{"udp"}
+	case "both":
+		protocols = []string{"tcp", "udp"}
+	}
+
+	scanTargets := buildScanTargets(job.hosts, job.ports)
+	plans := make([]scanPlan, 0, len(protocols))
+	for _, protocol := range protocols {
+		protocolPlans, err := planScans(ctx, job.cfg, protocol, scanTargets, budgetFactory(budget))
+		if err != nil {
+			return err
+		}
+		plans = append(plans, protocolPlans...)
+	}
+
+	start := time.Now()
+	open := 0
+	err := runScanPlansTo(ctx, plans, job.cfg, exportJob(job.format, &open))
+	if !viper.GetBool("quiet") {
+		fmt.Fprintf(os.Stderr, "job %s: %d hosts, %d open ports in %s, results in %s\n",
+			job.name, len(job.hosts), open, time.Since(start).Round(time.Millisecond), job.cfg.OutputFile)
+	}
+	return err
+}
+
+// exportJob returns the output for a job: its events are written to the
+// job's file without a progress line, which concurrent jobs would garble,
+// and open counts the open ports written.
+func exportJob(format string, open *int) scanOutputFunc {
+	return func(ctx context.Context, cfg *config.Config, events <-chan core.Event, _ int, metadata exporter.ScanMetadata, _ scanHandle) error {
+		out, finish, err := openScanOutput(cfg)
+		if err != nil {
+			return err
+		}
+		exp := newStreamExporter(ctx, cfg, format, out, metadata)
+		counted := make(chan core.Event, cap(events))
+		go func() {
+			defer close(counted)
+			for event := range events {
+				if event.Kind == core.EventKindResult && event.Result.State == core.StateOpen {
+					*open++
+				}
+				counted <- event
+			}
+		}()
+		return finish(streamEvents(ctx, counted, exp.Export, exp.Close))
+	}
+}
+
+// jobsError combines the jobs' errors into one, each prefixed with its job
+// name. The exit code is the most severe: an interrupt, then an error that
+// stopped a job, then a --fail-on violation, then failed probes.
+func jobsError(ctx context.Context, jobs []scanJobRun, errs []error) error {
+	if interrupted(ctx) {
+		return &errors.ExitError{Code: errors.ExitAborted, Err: errInterrupted}
+	}
+	var failed []error
+	code := errors.ExitOK
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		failed = append(failed, fmt.Errorf("job %s: %w", jobs[i].name, err))
+		if c := errors.ExitCode(err); code == errors.ExitOK || exitSeverity(c) > exitSeverity(code) {
+			code = c
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return &errors.ExitError{Code: code, Err: stdErrors.Join(failed...)}
+}
+
+// exitSeverity ranks exit codes for jobsError.
+func exitSeverity(code int) int {
+	switch code {
+	case errors.ExitAborted:
+		return 4
+	case errors.ExitPolicy:
+		return 2
+	case errors.ExitPartial:
+		return 1
+	default:
+		return 3
+	}
+}
+
+// showJobsDryRun prints what each job would scan.
+func showJobsDryRun(w io.Writer, jobs []scanJobRun, cfg *config.Config) {
+	fmt.Fprintln(w, "=== DRY RUN MODE ===")
+	fmt.Fprintf(w, "Jobs:          %d sharing %d pps\n", len(jobs), cfg.Rate)
+	for _, job := range jobs {
+		fmt.Fprintf(w, "  %-12s %d targets, %d ports, %s -> %s (%s)\n",
+			job.name, len(job.hosts), len(job.ports), normalizeProtocol(job.cfg.Protocol), job.cfg.OutputFile, job.format)
+	}
+	fmt.Fprintf(w, "Workers:       %d per job\n", cfg.Workers)
+	fmt.Fprintf(w, "Timeout:       %dms\n", cfg.TimeoutMs)
+	fmt.Fprintf(w, "Banner Grab:   %v\n", cfg.Banners)
+}
+
+// showJobsEstimate prints each job's probes and the cost of running them
+// all under the shared rate.
+// confirmJobs asks before running jobs that together are large or reach
+// public addresses.
+func confirmJobs(jobs []scanJobRun, cfg *config.Config) error {
+	var hosts []string
+	for _, job := range jobs {
+		hosts = append(hosts, job.hosts...)
+	}
+	totalCfg := *cfg
+	totalCfg.Protocol, totalCfg.Output = "tcp", "json"
+	return confirmScan(hosts, estimateScan(len(hosts), jobsProbes(jobs), &totalCfg), &totalCfg)
+}
+
+// jobsProbes counts the probes of every job, per protocol.
+func jobsProbes(jobs []scanJobRun) int {
+	probes := 0
+	for _, job := range jobs {
+		probes += jobProbes(job)
+	}
+	return probes
+}
+
+// jobProbes counts a job's probes, twice for TCP and UDP.
+func jobProbes(job scanJobRun) int {
+	probes := len(job.hosts) * len(job.ports)
+	if normalizeProtocol(job.cfg.Protocol) == "both" {
+		probes *= 2
+	}
+	return probes
+}
+
+func showJobsEstimate(w io.Writer, jobs []scanJobRun, cfg *config.Config) {
+	hosts, probes := 0, 0
+	fmt.Fprintln(w, "=== SCAN ESTIMATE ===")
+	fmt.Fprintf(w, "Jobs:          %d sharing %d pps\n", len(jobs), cfg.Rate)
+	for _, job := range jobs {
+		hosts += len(job.hosts)
+		probes += jobProbes(job)
+		fmt.Fprintf(w, "  %-12s %s hosts, %s probes\n", job.name, formatCount(len(job.hosts)), formatCount(jobProbes(job)))
+	}
+
+	// Probes are already counted per protocol, and jobs stream to files.
+	totalCfg := *cfg
+	totalCfg.Protocol, totalCfg.Output = "tcp", "json"
+	est := estimateScan(hosts, probes, &totalCfg)
+	fmt.Fprintf(w, "Probes:        %s\n", formatCount(est.Probes))
+	fmt.Fprintf(w, "Duration:      ~%s at %d pps", formatEstimate(est.Duration), cfg.Rate)
+	if est.WorstCase > est.Duration {
+		fmt.Fprintf(w, ", up to %s if every port times out", formatEstimate(est.WorstCase))
+	}
+	fmt.Fprintln(w)
+	if len(est.Warnings) > 0 {
+		fmt.Fprintln(w, "\nWarnings:")
+		for _, warning := range est.Warnings {
+			fmt.Fprintf(w, "  - %s\n", warning)
+		}
+	}
+	fmt.Fprintln(w, "\nRemove --estimate to run the jobs.")
+}
@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"context"
+	stdErrors "errors"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/parser"
+)
+
+func TestReadJobTargets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "targets.txt")
+	data := "# web tier\n10.0.0.1 10.0.0.2\n\nweb.example.com # primary\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readJobTargets(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"10.0.0.1", "10.0.0.2", "web.example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readJobTargets() = %v, want %v", got, want)
+	}
+}
+
+func TestJobFormatAndFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       parser.JobSpec
+		cfg        config.Config
+		wantFormat string
+		wantFile   string
+	}{
+		{name: "defaults to ndjson", spec: parser.JobSpec{Name: "web"}, wantFormat: "json", wantFile: "web.ndjson"},
+		{name: "scan format", spec: parser.JobSpec{Name: "db"}, cfg: config.Config{Output: "csv"}, wantFormat: "csv", wantFile: "db.csv"},
+		{name: "streaming scan format", spec: parser.JobSpec{Name: "db"}, cfg: config.Config{Output: "kafka"}, wantFormat: "json", wantFile: "db.ndjson"},
+		{name: "job format", spec: parser.JobSpec{Name: "web", Output: "table"}, cfg: config.Config{Output: "csv"}, wantFormat: "table", wantFile: "web.txt"},
+		{name: "compressed", spec: parser.JobSpec{Name: "web", Output: "html"}, cfg: config.Config{Compress: "gzip"}, wantFormat: "html", wantFile: "web.html.gz"},
+		{name: "zstd", spec: parser.JobSpec{Name: "web"}, cfg: config.Config{Compress: "zstd"}, wantFormat: "json", wantFile: "web.ndjson.zst"},
+		{name: "job file", spec: parser.JobSpec{Name: "web", File: "out/web.json"}, wantFormat: "json", wantFile: "out/web.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format := jobFormat(tt.spec, &tt.cfg)
+			if format != tt.wantFormat {
+				t.Errorf("jobFormat() = %q, want %q", format, tt.wantFormat)
+			}
+			if file := jobFile(tt.spec, format, &tt.cfg); file != tt.wantFile {
+				t.Errorf("jobFile() = %q, want %q", file, tt.wantFile)
+			}
+		})
+	}
+}
+
+// TestJobsError verifies job errors are named and the most severe exit
+// code wins
+func TestJobsError(t *testing.T) {
+	jobs := []scanJobRun{{name: "web"}, {name: "db"}, {name: "mail"}}
+	policy := &errors.ExitError{Code: errors.ExitPolicy, Err: stdErrors.New("--fail-on matched")}
+	partial := &errors.ExitError{Code: errors.ExitPartial, Err: stdErrors.New("probes failed")}
+
+	if err := jobsError(context.Background(), jobs, make([]error, 3)); err != nil {
+		t.Errorf("clean jobs: err = %v", err)
+	}
+	err := jobsError(context.Background(), jobs, []error{partial, nil, policy})
+	if errors.ExitCode(err) != errors.ExitPolicy {
+		t.Errorf("exit code = %d, want %d", errors.ExitCode(err), errors.ExitPolicy)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "job web: probes failed") || !strings.Contains(msg, "job mail: --fail-on") {
+		t.Errorf("message = %q", msg)
+	}
+	if err := jobsError(context.Background(), jobs, []error{policy, stdErrors.New("disk full"), nil}); errors.ExitCode(err) != errors.ExitUsage {
+		t.Errorf("failed job: exit code = %d, want %d", errors.ExitCode(err), errors.ExitUsage)
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errInterrupted)
+	if err := jobsError(ctx, jobs, []error{policy, nil, nil}); errors.ExitCode(err) != errors.ExitAborted {
+		t.Errorf("interrupted: exit code = %d, want %d", errors.ExitCode(err), errors.ExitAborted)
+	}
+}
+
+// TestRunJobsConcurrently runs two jobs against a local listener and checks
+// each writes only its own results to its own file
+func TestRunJobsConcurrently(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	port := uint16(ln.Addr().(*net.TCPAddr).Port)
+
+	dir := t.TempDir()
+	targets := filepath.Join(dir, "targets.txt")
+	if err := os.WriteFile(targets, []byte("127.0.0.1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Workers: 4, TimeoutMs: 500, Rate: 200, AllowLocalhost: true}
+	var jobs []scanJobRun
+	for _, spec := range []string{
+		"open:" + targets + ":ports=" + strconv.Itoa(int(port)) + ":file=" + filepath.Join(dir, "open.ndjson"),
+		"csv:" + targets + ":ports=" + strconv.Itoa(int(port)) + ":output=csv:file=" + filepath.Join(dir, "open.csv"),
+	} {
+		job, err := parser.ParseJob(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		run, err := loadJob(job, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		jobs = append(jobs, run)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := runJobsConcurrently(ctx, jobs, cfg.Rate); err != nil {
+		t.Fatalf("runJobsConcurrently() = %v", err)
+	}
+
+	ndjson, err := os.ReadFile(filepath.Join(dir, "open.ndjson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(ndjson), `"state":"open"`) {
+		t.Errorf("open.ndjson = %q, want an open result", ndjson)
+	}
+	csv, err := os.ReadFile(filepath.Join(dir, "open.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(csv), strconv.Itoa(int(port))) || strings.Contains(string(csv), "{") {
+		t.Errorf("open.csv = %q, want the CSV result", csv)
+	}
+}
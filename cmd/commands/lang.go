@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// applyLanguage selects the message language from --lang (or the lang
+// config key) and the locale environment, then localizes command help.
+// An unsupported --lang is reported to warn, when not nil, and English is
+// used.
+func applyLanguage(warn io.Writer) {
+	lang := viper.GetString("lang")
+	if lang != "" && !i18n.Supported(lang) && warn != nil {
+		fmt.Fprintf(warn, "warning: unsupported --lang %q (available: %s); using %s\n",
+			lang, strings.Join(i18n.Languages(), ", "), i18n.Default)
+	}
+	i18n.SetLanguage(i18n.Detect(lang))
+	localizeCommands(rootCmd)
+}
+
+// localizeCommands replaces the help text of cmd and its subcommands, and
+// the usage of their flags, with their translations. Commands are keyed by
+// path, e.g. commands.portscan.config.init.short, and so are their own
+// flags, e.g. commands.portscan.scan.flags.ports; the root's persistent
+// flags are keyed flags.NAME.
+func localizeCommands(cmd *cobra.Command) {
+	key := "commands." + commandKey(cmd)
+	if short, ok := translation(key + ".short"); ok {
+		cmd.Short = short
+	}
+	if long, ok := translation(key + ".long"); ok {
+		cmd.Long = long
+	}
+	cmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if usage, ok := translation("flags." + f.Name); ok {
+			f.Usage = usage
+		}
+	})
+	cmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if usage, ok := translation(key + ".flags." + f.Name); ok {
+			f.Usage = usage
+		}
+	})
+	for _, sub := range cmd.Commands() {
+		localizeCommands(sub)
+	}
+}
+
+// commandKey returns the command's path joined with dots.
+func commandKey(cmd *cobra.Command) string {
+	if cmd.HasParent() {
+		return commandKey(cmd.Parent()) + "." + cmd.Name()
+	}
+	return cmd.Name()
+}
+
+// translation returns the message for key, or false if no catalog has it.
+func translation(key string) (string, bool) {
+	message := i18n.T(key)
+	return message, message != key
+}
+
+// localizedHelp wraps a help function so help rendered for --help, which
+// cobra shows before initConfig runs, is also translated.
+func localizedHelp(help func(*cobra.Command, []string)) func(*cobra.Command, []string) {
+	return func(cmd *cobra.Command, args []string) {
+		applyLanguage(nil)
+		help(cmd, args)
+	}
+}
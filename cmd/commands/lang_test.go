@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+func TestApplyLanguage_LocalizesHelp(t *testing.T) {
+	viper.Reset()
+	defer func() {
+		viper.Reset()
+		applyLanguage(nil)
+	}()
+
+	viper.Set("lang", "es")
+	applyLanguage(nil)
+	if i18n.Language() != "es" {
+		t.Fatalf("language = %q, want es", i18n.Language())
+	}
+	if configInitCmd.Short != "Crea un archivo de configuración predeterminado" {
+		t.Errorf("config init help not translated: %q", configInitCmd.Short)
+	}
+	if usage := rootCmd.PersistentFlags().Lookup("quiet").Usage; usage != "omite la salida no esencial" {
+		t.Errorf("--quiet usage not translated: %q", usage)
+	}
+
+	if usage := scanCmd.Flags().Lookup("ports").Usage; !strings.Contains(usage, "puertos a escanear") {
+		t.Errorf("scan --ports usage not translated: %q", usage)
+	}
+	if !strings.HasPrefix(scanCmd.Long, "Realiza un escaneo") {
+		t.Errorf("scan long help not translated: %q", scanCmd.Long)
+	}
+
+	viper.Set("lang", "en")
+	applyLanguage(nil)
+	if configInitCmd.Short != "Create a default configuration file" {
+		t.Errorf("switching back to en left %q", configInitCmd.Short)
+	}
+	if usage := scanCmd.Flags().Lookup("ports").Usage; usage != "ports to scan (e.g., '80,443', '1-1024,!22', '1000-2000/2' or 'ssh,https')" {
+		t.Errorf("switching back to en left --ports usage %q", usage)
+	}
+}
+
+func TestApplyLanguage_WarnsOnUnsupported(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	viper.Set("lang", "tlh")
+	var warn bytes.Buffer
+	applyLanguage(&warn)
+	if !strings.Contains(warn.String(), `unsupported --lang "tlh"`) {
+		t.Errorf("expected a warning, got %q", warn.String())
+	}
+	if i18n.Language() != i18n.Default {
+		t.Errorf("language = %q, want %s", i18n.Language(), i18n.Default)
+	}
+}
+
+// TestCommandCatalog_CoversCommands keeps every command's help and flag
+// usage translatable.
+func TestCommandCatalog_CoversCommands(t *testing.T) {
+	var check func(cmd *cobra.Command)
+	check = func(cmd *cobra.Command) {
+		if cmd.Name() == "help" { // added by cobra, which translates nothing
+			return
+		}
+		key := "commands." + commandKey(cmd)
+		if _, ok := translation(key + ".short"); !ok {
+			t.Errorf("%s has no catalog entry %s.short", cmd.CommandPath(), key)
+		}
+		if _, ok := translation(key + ".long"); cmd.Long != "" && !ok {
+			t.Errorf("%s has no catalog entry %s.long", cmd.CommandPath(), key)
+		}
+		cmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+			if _, ok := translation(key + ".flags." + f.Name); !ok && f.Name != "help" {
+				t.Errorf("%s --%s has no catalog entry", cmd.CommandPath(), f.Name)
+			}
+		})
+		for _, sub := range cmd.Commands() {
+			check(sub)
+		}
+	}
+	check(rootCmd)
+}
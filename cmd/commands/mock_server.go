@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/lucchesi-sec/portscan/internal/testserver"
+	"github.com/spf13/cobra"
+)
+
+// defaultMockServices are served when no --service flag is given.
+var defaultMockServices = []string{
+	`tcp/0,banner=SSH-2.0-OpenSSH_9.6 portscan-mock\r\n`,
+	`tcp/0,banner=HTTP/1.0 200 OK\r\nServer: portscan-mock\r\n\r\n,delay=100ms`,
+	"tcp/0,reset",
+	"udp/0,banner=portscan-mock",
+}
+
+var mockServerCmd = &cobra.Command{
+	Use:    "mock-server",
+	Short:  "Run mock TCP/UDP targets for testing",
+	Hidden: true,
+	Long: `Run mock TCP and UDP listeners with configurable banners, delays, and
+resets, then scan them to validate the scanner, exporters, and TUI end to end.
+
+Each --service is PROTO/PORT[,banner=TEXT][,delay=DURATION][,reset][,drop=RATE].
+Port 0 picks a free port. Banners accept escapes such as \r\n.
+
+Examples:
+  portscan mock-server
+  portscan mock-server --service 'tcp/2222,banner=SSH-2.0-mock\r\n' --service udp/5353,reset`,
+	RunE: runMockServer,
+}
+
+func init() {
+	rootCmd.AddCommand(mockServerCmd)
+	mockServerCmd.Flags().String("host", "127.0.0.1", "Address to listen on")
+	mockServerCmd.Flags().StringArray("service", nil, "Service spec PROTO/PORT[,banner=TEXT][,delay=DURATION][,reset][,drop=RATE] (repeatable)")
+}
+
+func runMockServer(cmd *cobra.Command, args []string) error {
+	host, _ := cmd.Flags().GetString("host")
+	specs, _ := cmd.Flags().GetStringArray("service")
+
+	services, err := parseMockServices(specs)
+	if err != nil {
+		return err
+	}
+	server, err := testserver.Start(host, services)
+	if err != nil {
+		return err
+	}
+	defer server.Close()
+
+	printMockServices(cmd.OutOrStdout(), server)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	return nil
+}
+
+// parseMockServices parses service specs, falling back to the defaults.
+func parseMockServices(specs []string) ([]testserver.Service, error) {
+	if len(specs) == 0 {
+		specs = defaultMockServices
+	}
+	services := make([]testserver.Service, 0, len(specs))
+	for _, spec := range specs {
+		svc, err := testserver.ParseService(spec)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, svc)
+	}
+	return services, nil
+}
+
+// printMockServices lists the running services and a scan command for them.
+func printMockServices(w io.Writer, server *testserver.Server) {
+	fmt.Fprintf(w, "Mock targets listening on %s:\n", server.Host)
+	for _, svc := range server.Services() {
+		fmt.Fprintf(w, "  %s\n", svc)
+	}
+
+	for _, proto := range []string{"tcp", "udp"} {
+		ports := server.Ports(proto)
+		if len(ports) == 0 {
+			continue
+		}
+		list := fmt.Sprint(ports[0])
+		for _, p := range ports[1:] {
+			list += fmt.Sprintf(",%d", p)
+		}
+		fmt.Fprintf(w, "\nScan with: portscan scan %s --protocol %s --ports %s --banners", server.Host, proto, list)
+	}
+	fmt.Fprintln(w, "\n\nPress Ctrl+C to stop.")
+}
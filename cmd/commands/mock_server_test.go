@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/internal/testserver"
+)
+
+// TestMockServerCommand verifies the command is registered but hidden
+func TestMockServerCommand(t *testing.T) {
+	if !mockServerCmd.Hidden {
+		t.Error("mock-server should be hidden")
+	}
+	if mockServerCmd.Flags().Lookup("service") == nil || mockServerCmd.Flags().Lookup("host") == nil {
+		t.Error("mock-server should define --service and --host")
+	}
+}
+
+// TestParseMockServices verifies defaults and explicit specs
+func TestParseMockServices(t *testing.T) {
+	defaults, err := parseMockServices(nil)
+	if err != nil {
+		t.Fatalf("default services: %v", err)
+	}
+	if len(defaults) != len(defaultMockServices) {
+		t.Errorf("defaults = %d services, want %d", len(defaults), len(defaultMockServices))
+	}
+
+	services, err := parseMockServices([]string{"udp/5353,reset"})
+	if err != nil || len(services) != 1 || services[0].Protocol != "udp" || !services[0].Reset {
+		t.Errorf("parseMockServices = %+v, %v", services, err)
+	}
+
+	if _, err := parseMockServices([]string{"tcp/nope"}); err == nil {
+		t.Error("invalid spec should fail")
+	}
+}
+
+// TestPrintMockServices verifies the listing includes a ready-to-run scan command
+func TestPrintMockServices(t *testing.T) {
+	services, _ := parseMockServices(nil)
+	server, err := testserver.Start("127.0.0.1", services)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer server.Close()
+
+	var out bytes.Buffer
+	printMockServices(&out, server)
+	for _, want := range []string{"Mock targets listening on 127.0.0.1", "banner=SSH-2.0", "--protocol tcp --ports", "--protocol udp --ports"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/spf13/viper"
+)
+
+// outputFormat returns the streaming export format for the scan: "json",
+// "csv", "html", "table", "elastic", "syslog", "kafka", "nats", or
+// "inventory", or "" for the interactive TUI. An output file or upload
+// without an explicit format is written as NDJSON.
+func outputFormat(cfg *config.Config) string {
+	switch {
+	case viper.GetBool("json") || cfg.Output == "json":
+		return "json"
+	case cfg.Output == "csv", cfg.Output == "html", cfg.Output == "table", cfg.Output == "elastic", cfg.Output == "syslog",
+		cfg.Output == "kafka", cfg.Output == "nats", cfg.Output == "inventory":
+		return cfg.Output
+	case cfg.OutputFile != "", cfg.Upload != "":
+		return "json"
+	default:
+		return ""
+	}
+}
+
+// openScanOutput returns the writer exporters stream to and a finish func
+// to call with the export's result. Without --output-file or --upload that
+// is stdout, compressed with --compress. Otherwise output goes to an atomic
+// exporter.FileWriter that is committed when the export succeeds and
+// discarded when it fails. With --upload the committed file is then
+// uploaded; without --output-file it is a temporary file removed once the
+// upload succeeds.
+func openScanOutput(cfg *config.Config) (io.Writer, func(error) error, error) {
+	if cfg.OutputFile == "" && cfg.Upload == "" {
+		return openStdoutOutput(cfg.Compress)
+	}
+
+	path := cfg.OutputFile
+	compression := cfg.Compress
+	var report *reportUpload
+	if cfg.Upload != "" {
+		var err error
+		if report, err = newReportUpload(cfg, time.Now()); err != nil {
+			return nil, nil, err
+		}
+		if path == "" {
+			if path, err = reserveTempFile(); err != nil {
+				return nil, nil, err
+			}
+			if compression == "" {
+				compression = exporter.CompressionForPath(report.key)
+			}
+		}
+	}
+	if compression == "" {
+		compression = exporter.CompressionForPath(path)
+	}
+
+	file, err := exporter.CreateFile(path, exporter.FileOptions{
+		MaxBytes:    cfg.GetOutputMaxBytes(),
+		Keep:        cfg.OutputKeep,
+		Compression: compression,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	finish := func(exportErr error) error {
+		if exportErr != nil {
+			file.Abort()
+			if cfg.OutputFile == "" {
+				_ = os.Remove(path)
+			}
+			return exportErr
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+		if cfg.OutputFile != "" && !viper.GetBool("quiet") {
+			fmt.Fprintf(os.Stderr, "Results written to %s\n", cfg.OutputFile)
+		}
+		if report == nil {
+			return nil
+		}
+		if err := report.send(path, compression); err != nil {
+			if cfg.OutputFile == "" {
+				return fmt.Errorf("%w (results kept at %s)", err, path)
+			}
+			return err
+		}
+		if cfg.OutputFile == "" {
+			_ = os.Remove(path)
+		}
+		return nil
+	}
+	return file, finish, nil
+}
+
+// reserveTempFile creates an empty temporary file for an export that is
+// only uploaded, so the FileWriter has a unique path to commit to.
+func reserveTempFile() (string, error) {
+	f, err := os.CreateTemp("", "portscan-upload-*")
+	if err != nil {
+		return "", fmt.Errorf("create temporary export file: %w", err)
+	}
+	name := f.Name()
+	if err := f.Close(); err != nil {
+		_ = os.Remove(name)
+		return "", err
+	}
+	return name, nil
+}
+
+// openStdoutOutput wraps stdout in the requested compression. Compressed
+// bytes are refused on a terminal, where they would only garble the screen.
+func openStdoutOutput(compression string) (io.Writer, func(error) error, error) {
+	if compression == "" || compression == exporter.CompressionNone {
+		return os.Stdout, func(err error) error { return err }, nil
+	}
+	if isTerminal(os.Stdout) {
+		return nil, nil, fmt.Errorf("refusing to write %s-compressed output to a terminal; redirect stdout or use --output-file", compression)
+	}
+	w, err := exporter.NewCompressWriter(os.Stdout, compression)
+	if err != nil {
+		return nil, nil, err
+	}
+	finish := func(exportErr error) error {
+		if err := w.Close(); err != nil && exportErr == nil {
+			return err
+		}
+		return exportErr
+	}
+	return w, finish, nil
+}
+
+// rotatableOutput reports whether the selected output can be split across
+// rotated files, which needs a line-oriented format without a header or a
+// closing bracket.
+func rotatableOutput(cfg *config.Config) bool {
+	return outputFormat(cfg) == "json" && !viper.GetBool("json_array") && !viper.GetBool("json_object")
+}
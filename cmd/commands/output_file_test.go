@@ -0,0 +1,153 @@
+package commands
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/spf13/viper"
+)
+
+func TestOutputFormat(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	tests := []struct {
+		cfg  config.Config
+		want string
+	}{
+		{cfg: config.Config{}, want: ""},
+		{cfg: config.Config{Output: "csv"}, want: "csv"},
+		{cfg: config.Config{Output: "table", OutputFile: "out.txt"}, want: "table"},
+		{cfg: config.Config{OutputFile: "out.ndjson"}, want: "json"},
+		{cfg: config.Config{Output: "html", Upload: "s3://reports/scan.html"}, want: "html"},
+		{cfg: config.Config{Upload: "s3://reports/scan.json"}, want: "json"},
+		{cfg: config.Config{Output: "inventory"}, want: "inventory"},
+	}
+	for _, tt := range tests {
+		if got := outputFormat(&tt.cfg); got != tt.want {
+			t.Errorf("outputFormat(%+v) = %q, want %q", tt.cfg, got, tt.want)
+		}
+	}
+}
+
+func TestHandleScanOutput_File(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("quiet", true)
+
+	path := filepath.Join(t.TempDir(), "results.ndjson")
+	cfg := &config.Config{OutputFile: path}
+
+	events := make(chan core.Event, 2)
+	events <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen})
+	close(events)
+
+	if err := handleScanOutput(context.Background(), cfg, events, 1, exporter.ScanMetadata{}, scanHandle{}); err != nil {
+		t.Fatalf("handleScanOutput: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("output file not written: %v", err)
+	}
+	if !strings.Contains(string(data), `"port":22`) {
+		t.Errorf("output file = %q, want the NDJSON result", data)
+	}
+}
+
+func TestHandleScanOutput_Inventory(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("quiet", true)
+
+	path := filepath.Join(t.TempDir(), "inventory.csv")
+	cfg := &config.Config{Output: "inventory", InventoryFmt: exporter.InventoryCSV, OutputFile: path}
+
+	events := make(chan core.Event, 3)
+	events <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen})
+	events <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 80, State: core.StateOpen})
+	close(events)
+
+	if err := handleScanOutput(context.Background(), cfg, events, 2, exporter.ScanMetadata{}, scanHandle{}); err != nil {
+		t.Fatalf("handleScanOutput: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("output file not written: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 || !strings.Contains(lines[1], "22/tcp;80/tcp") {
+		t.Errorf("inventory = %q, want one row for the host", data)
+	}
+}
+
+func TestHandleScanOutput_CompressedFile(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("quiet", true)
+
+	path := filepath.Join(t.TempDir(), "results.ndjson")
+	cfg := &config.Config{OutputFile: path, Compress: exporter.CompressionGzip}
+
+	events := make(chan core.Event, 2)
+	events <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 443, State: core.StateOpen})
+	close(events)
+
+	if err := handleScanOutput(context.Background(), cfg, events, 1, exporter.ScanMetadata{}, scanHandle{}); err != nil {
+		t.Fatalf("handleScanOutput: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("output file not written: %v", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("output file is not gzip: %v", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("decompressing output: %v", err)
+	}
+	if !strings.Contains(string(data), `"port":443`) {
+		t.Errorf("decompressed output = %q, want the NDJSON result", data)
+	}
+}
+
+func TestValidateInputs_OutputRotation(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	base := config.Config{Ports: "80", Rate: 5000, TimeoutMs: 200, Workers: 50, UDPWorkerRatio: 0.5}
+
+	cfg := base
+	cfg.OutputMaxSize = "100MB"
+	if err := validateInputs(&cfg); err == nil {
+		t.Error("rotation without an output file should be rejected")
+	}
+
+	cfg.OutputFile = "scan.csv"
+	cfg.Output = "csv"
+	if err := validateInputs(&cfg); err == nil || !strings.Contains(err.Error(), "NDJSON") {
+		t.Errorf("rotating CSV output should be rejected, got %v", err)
+	}
+
+	cfg.Output = ""
+	cfg.OutputFile = "scan.ndjson.gz"
+	if err := validateInputs(&cfg); err != nil {
+		t.Errorf("rotating NDJSON output rejected: %v", err)
+	}
+
+	cfg.OutputMaxSize = "lots"
+	if err := validateInputs(&cfg); err == nil {
+		t.Error("a malformed size should be rejected")
+	}
+}
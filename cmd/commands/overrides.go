@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/targets"
+)
+
+// splitByOverride groups targets by the first config override matching
+// each. Group i holds the targets of override i; the last group holds the
+// targets no override matches. A hostname target is matched by its name
+// and by the address it resolves to, so a network override also covers
+// names that point into it.
+func splitByOverride(ctx context.Context, cfg *config.Config, scanTargets []core.ScanTarget) [][]core.ScanTarget {
+	matches := cfg.GetOverrideMatches()
+	addrs := overrideAddrs(ctx, cfg, matches, scanTargets)
+	groups := make([][]core.ScanTarget, len(matches)+1)
+	for _, t := range scanTargets {
+		host, hostname := t.Host, t.Hostname
+		if addr, ok := addrs[t.Host]; ok {
+			host, hostname = addr, t.Host
+		}
+		group := len(matches)
+		for i, match := range matches {
+			if match.Allows(host, hostname) {
+				group = i
+				break
+			}
+		}
+		groups[group] = append(groups[group], t)
+	}
+	return groups
+}
+
+// overrideAddrs resolves the hostname targets when an override matches a
+// network, returning the address each will be probed at. Hostnames that
+// fail to resolve are matched by name only.
+func overrideAddrs(ctx context.Context, cfg *config.Config, matches []*targets.Scope, scanTargets []core.ScanTarget) map[string]string {
+	networks := false
+	for _, match := range matches {
+		networks = networks || len(match.Networks) > 0
+	}
+	resolver := scanResolver(cfg)
+	if !networks || resolver == nil {
+		return nil
+	}
+
+	var hosts []string
+	for _, t := range scanTargets {
+		if net.ParseIP(t.Host) == nil {
+			hosts = append(hosts, t.Host)
+		}
+	}
+	resolver.Prefetch(ctx, hosts)
+	addrs := make(map[string]string, len(hosts))
+	for _, host := range hosts {
+		// The scanner probes a hostname at its first address.
+		if resolved, err := resolver.LookupHost(ctx, host); err == nil {
+			addrs[host] = resolved[0]
+		}
+	}
+	return addrs
+}
+
+// planScans creates the scanners that probe scanTargets over protocol.
+// Targets matching a config override get a scanner of their own, built
+// from the config with the override applied, so a sensitive subnet is
+// probed at its own rate and timeout alongside the rest of the scan. The
+// scan's workers are split between the scanners. newFactory builds the
+// factory for a config.
+func planScans(ctx context.Context, cfg *config.Config, protocol string, scanTargets []core.ScanTarget, newFactory func(*config.Config) *ScannerFactory) ([]scanPlan, error) {
+	groups := splitByOverride(ctx, cfg, scanTargets)
+	workers := splitWorkers(cfg.Workers, groups)
+	var plans []scanPlan
+	for i, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		var groupCfg *config.Config
+		if i < len(cfg.Overrides) {
+			groupCfg = cfg.Overrides[i].Apply(cfg)
+		} else {
+			copied := *cfg
+			groupCfg = &copied
+		}
+		// An override's own worker count still caps its share.
+		groupCfg.Workers = min(groupCfg.Workers, workers[i])
+		scanner, err := newFactory(groupCfg).CreateScanner(protocol)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, scanPlan{scanner: scanner, targets: group})
+	}
+	return plans, nil
+}
+
+// splitWorkers divides workers between groups in proportion to the probes
+// each holds, so the scanners together stay within the worker count. A
+// group with targets gets at least one worker.
+func splitWorkers(workers int, groups [][]core.ScanTarget) []int {
+	shares := make([]int, len(groups))
+	probes := make([]int, len(groups))
+	total := 0
+	for i, group := range groups {
+		for _, t := range group {
+			probes[i] += len(t.Ports)
+		}
+		total += probes[i]
+	}
+	for i, group := range groups {
+		switch {
+		case workers <= 0 || total == 0:
+			shares[i] = workers
+		case len(group) > 0:
+			shares[i] = max(workers*probes[i]/total, 1)
+		}
+	}
+	return shares
+}
+
+// budgetFactory returns a newFactory for planScans whose scanners all
+// draw from budget, so the scanners of one scan stay within --rate
+// together. An override's lower rate still applies to its own scanner.
+func budgetFactory(budget *core.RateBudget) func(*config.Config) *ScannerFactory {
+	return func(cfg *config.Config) *ScannerFactory {
+		return NewScannerFactory(cfg).WithRateBudget(budget)
+	}
+}
+
+// executeOverrideScan is executeScan for configs with overrides: each
+// protocol's targets are split between the overrides' scanners and the
+// scan's own.
+func executeOverrideScan(ctx context.Context, protocol string, hosts []string, ports []uint16, cfg *config.Config) error {
+	if len(hosts) == 0 {
+		return errors.NoTargetError()
+	}
+	protocols := []string{"tcp"}
+	switch protocol {
+	case "udp":
+		protocols = []string{"udp"}
+	case "both":
+		protocols = []string{"tcp", "udp"}
+	}
+
+	budget := core.NewRateBudget(cfg.Rate)
+	if budget != nil {
+		defer budget.Stop()
+	}
+	scanTargets := buildScanTargets(hosts, ports)
+	var plans []scanPlan
+	for _, p := range protocols {
+		protocolPlans, err := planScans(ctx, cfg, p, scanTargets, budgetFactory(budget))
+		if err != nil {
+			return err
+		}
+		plans = append(plans, protocolPlans...)
+	}
+	return runScanPlans(ctx, plans, cfg)
+}
+
+// showOverrides prints, for dry runs, how many targets each override
+// matches and the settings it applies.
+func showOverrides(w io.Writer, cfg *config.Config, scanTargets []core.ScanTarget) {
+	if len(cfg.Overrides) == 0 {
+		return
+	}
+	groups := splitByOverride(context.Background(), cfg, scanTargets)
+	fmt.Fprintln(w, "Overrides:")
+	for i, o := range cfg.Overrides {
+		applied := o.Apply(cfg)
+		fmt.Fprintf(w, "  %-20s %d targets at %d pps, %dms timeout\n",
+			o.Match, len(groups[i]), applied.Rate, applied.TimeoutMs)
+	}
+}
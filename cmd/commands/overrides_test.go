@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+func overrideTestConfig() *config.Config {
+	return &config.Config{
+		Rate:      7500,
+		TimeoutMs: 200,
+		Workers:   10,
+		Overrides: []config.TargetOverride{
+			{Match: "10.2.0.0/16", Rate: 500, TimeoutMs: 800},
+			{Match: "*.scada.example.net", PerHostLimit: 1},
+			{Match: "10.0.0.0/8", Rate: 2000},
+		},
+	}
+}
+
+func TestSplitByOverride(t *testing.T) {
+	scanTargets := buildScanTargets([]string{
+		"10.2.5.1",              // first override, though the third matches too
+		"10.9.0.1",              // third override
+		"plc.scada.example.net", // second override
+		"192.0.2.1",             // no override
+	}, []uint16{80})
+
+	groups := splitByOverride(context.Background(), overrideTestConfig(), scanTargets)
+	if len(groups) != 4 {
+		t.Fatalf("got %d groups, want 3 overrides and the rest", len(groups))
+	}
+	want := [][]string{{"10.2.5.1"}, {"plc.scada.example.net"}, {"10.9.0.1"}, {"192.0.2.1"}}
+	for i, group := range groups {
+		var hosts []string
+		for _, t := range group {
+			hosts = append(hosts, t.Host)
+		}
+		if strings.Join(hosts, ",") != strings.Join(want[i], ",") {
+			t.Errorf("group %d = %v; want %v", i, hosts, want[i])
+		}
+	}
+}
+
+func TestSplitByOverrideMatchesResolvedAddress(t *testing.T) {
+	addrs, err := net.LookupHost("localhost")
+	if err != nil || !strings.HasPrefix(addrs[0], "127.") {
+		t.Skip("localhost does not resolve to 127.0.0.0/8 first here")
+	}
+	cfg := &config.Config{Overrides: []config.TargetOverride{{Match: "127.0.0.0/8", Rate: 10}}}
+	scanTargets := buildScanTargets([]string{"localhost", "192.0.2.1"}, []uint16{80})
+
+	groups := splitByOverride(context.Background(), cfg, scanTargets)
+	if len(groups[0]) != 1 || groups[0][0].Host != "localhost" {
+		t.Errorf("override group = %v; want the hostname resolving into 127/8", groups[0])
+	}
+	if len(groups[1]) != 1 || groups[1][0].Host != "192.0.2.1" {
+		t.Errorf("unmatched group = %v; want 192.0.2.1", groups[1])
+	}
+}
+
+func TestPlanScansBuildsScannerPerOverride(t *testing.T) {
+	cfg := overrideTestConfig()
+	scanTargets := buildScanTargets([]string{"10.2.5.1", "10.2.5.2", "192.0.2.1"}, []uint16{80})
+
+	var rates []int
+	newFactory := func(c *config.Config) *ScannerFactory {
+		rates = append(rates, c.Rate)
+		return NewScannerFactory(c)
+	}
+	plans, err := planScans(context.Background(), cfg, "tcp", scanTargets, newFactory)
+	if err != nil {
+		t.Fatalf("planScans: %v", err)
+	}
+	// Overrides without targets get no scanner.
+	if len(plans) != 2 {
+		t.Fatalf("got %d plans, want the 10.2/16 override and the rest", len(plans))
+	}
+	if len(plans[0].targets) != 2 || len(plans[1].targets) != 1 {
+		t.Errorf("plan targets = %d and %d; want 2 and 1", len(plans[0].targets), len(plans[1].targets))
+	}
+	if len(rates) != 2 || rates[0] != 500 || rates[1] != 7500 {
+		t.Errorf("scanner rates = %v; want [500 7500]", rates)
+	}
+}
+
+func TestPlanScansSplitsWorkers(t *testing.T) {
+	cfg := overrideTestConfig()
+	cfg.Overrides[2].Workers = 2
+	scanTargets := buildScanTargets([]string{"10.2.5.1", "10.9.0.1", "192.0.2.1", "192.0.2.2"}, []uint16{80})
+
+	var workers []int
+	newFactory := func(c *config.Config) *ScannerFactory {
+		workers = append(workers, c.Workers)
+		return NewScannerFactory(c)
+	}
+	if _, err := planScans(context.Background(), cfg, "tcp", scanTargets, newFactory); err != nil {
+		t.Fatalf("planScans: %v", err)
+	}
+	// 10 workers over 1, 1 and 2 targets; the 10/8 override caps its
+	// share at 2.
+	if len(workers) != 3 || workers[0] != 2 || workers[1] != 2 || workers[2] != 5 {
+		t.Errorf("scanner workers = %v; want [2 2 5]", workers)
+	}
+	if cfg.Workers != 10 {
+		t.Errorf("planScans changed the scan's workers to %d", cfg.Workers)
+	}
+}
+
+func TestPlanScansWithoutOverrides(t *testing.T) {
+	cfg := &config.Config{Rate: 100, TimeoutMs: 200, Workers: 1}
+	scanTargets := []core.ScanTarget{{Host: "192.0.2.1", Ports: []uint16{80}}}
+
+	plans, err := planScans(context.Background(), cfg, "udp", scanTargets, NewScannerFactory)
+	if err != nil {
+		t.Fatalf("planScans: %v", err)
+	}
+	if len(plans) != 1 || len(plans[0].targets) != 1 {
+		t.Fatalf("plans = %+v; want one plan with every target", plans)
+	}
+}
+
+func TestShowOverrides(t *testing.T) {
+	var buf bytes.Buffer
+	scanTargets := buildScanTargets([]string{"10.2.5.1", "192.0.2.1"}, []uint16{80})
+	showOverrides(&buf, overrideTestConfig(), scanTargets)
+
+	out := buf.String()
+	if !strings.Contains(out, "10.2.0.0/16") || !strings.Contains(out, "1 targets at 500 pps, 800ms timeout") {
+		t.Errorf("showOverrides output missing the 10.2/16 override:\n%s", out)
+	}
+	if !strings.Contains(out, "*.scada.example.net") || !strings.Contains(out, "0 targets at 7500 pps") {
+		t.Errorf("showOverrides output missing the unmatched override:\n%s", out)
+	}
+}
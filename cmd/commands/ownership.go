@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/targets"
+	"github.com/spf13/viper"
+)
+
+// ownerPreviewLimit caps how many public targets the confirmation looks
+// up owners for, so a large public range is not queried before the user
+// has agreed to scan it.
+const ownerPreviewLimit = 20
+
+// ownershipKey identifies an ownership lookup by the settings it uses.
+type ownershipKey struct {
+	cache    string
+	offline  bool
+	resolver *targets.Resolver
+}
+
+// ownershipLookup pairs a lookup with the cache it fills.
+type ownershipLookup struct {
+	lookup *core.OwnershipLookup
+	cache  *core.OwnershipCache // nil when uncached
+}
+
+var (
+	ownershipMu      sync.Mutex
+	ownershipLookups = make(map[ownershipKey]*ownershipLookup)
+)
+
+// scanOwnership returns the --owner-lookup lookup, shared by the
+// confirmation and the scan so each address is looked up once. A cache
+// file that cannot be read is noted on stderr, unless --quiet is set, and
+// left alone.
+func scanOwnership(cfg *config.Config) *ownershipLookup {
+	key := ownershipKey{
+		cache:    cfg.GetOwnerCache(),
+		offline:  cfg.OwnerOffline,
+		resolver: scanResolver(cfg),
+	}
+	ownershipMu.Lock()
+	defer ownershipMu.Unlock()
+	if l, ok := ownershipLookups[key]; ok {
+		return l
+	}
+	l := &ownershipLookup{}
+	if key.cache != "" {
+		cache, err := core.LoadOwnershipCache(key.cache)
+		if err == nil {
+			l.cache = cache
+		} else if !viper.GetBool("quiet") {
+			fmt.Fprintf(os.Stderr, "Ignoring owner cache %s: %v\n", key.cache, err)
+		}
+	}
+	opts := core.OwnershipOptions{Cache: l.cache, Offline: cfg.OwnerOffline}
+	if key.resolver != nil {
+		opts.Resolver = key.resolver
+	}
+	l.lookup = core.NewOwnershipLookup(opts)
+	ownershipLookups[key] = l
+	return l
+}
+
+// saveOwnership writes the answers new to the --owner-lookup cache back
+// to its file. A failure is noted on stderr, unless --quiet is set; the
+// scan's results are unaffected.
+func saveOwnership(cfg *config.Config) {
+	l := scanOwnership(cfg)
+	if l.cache == nil {
+		return
+	}
+	if err := l.cache.Save(); err != nil && !viper.GetBool("quiet") {
+		fmt.Fprintf(os.Stderr, "Could not save owner cache %s: %v\n", cfg.GetOwnerCache(), err)
+	}
+}
+
+// publicOwners names who holds the networks of the public targets among
+// hosts, with how many targets each holds, for the scan confirmation.
+// resolved holds the addresses of the hostnames among hosts. Only the
+// first ownerPreviewLimit public targets are looked up; more reports
+// whether there were others.
+func publicOwners(ctx context.Context, cfg *config.Config, hosts []string, resolved map[string][]string) (owners []string, more bool) {
+	lookup := scanOwnership(cfg).lookup
+	counts := make(map[string]int)
+	checked := 0
+	for _, host := range hosts {
+		addr := host
+		if net.ParseIP(host) == nil {
+			if len(resolved[host]) == 0 {
+				continue
+			}
+			addr = resolved[host][0]
+		}
+		if ip := net.ParseIP(addr); ip == nil || !core.IsPublicIP(ip) {
+			continue
+		}
+		if checked == ownerPreviewLimit {
+			more = true
+			break
+		}
+		checked++
+		name := "unknown"
+		if owner := lookup.Lookup(ctx, addr); owner != nil && owner.Org != "" {
+			name = owner.Org
+		}
+		counts[name]++
+	}
+	for name := range counts {
+		owners = append(owners, name)
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		if counts[owners[i]] != counts[owners[j]] {
+			return counts[owners[i]] > counts[owners[j]]
+		}
+		return owners[i] < owners[j]
+	})
+	for i, name := range owners {
+		owners[i] = fmt.Sprintf("%s (%d)", name, counts[name])
+	}
+	return owners, more
+}
+
+// ownerSummary describes --owner-lookup for dry runs.
+func ownerSummary(cfg *config.Config) string {
+	summary := "RDAP"
+	if cfg.OwnerOffline {
+		summary = "cached answers only"
+	}
+	if cache := cfg.GetOwnerCache(); cache != "" {
+		summary += ", cache " + cache
+	}
+	return summary
+}
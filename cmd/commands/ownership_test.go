@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"context"
+	"net/netip"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+func TestPublicOwners(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rdap.json")
+	cache, err := core.LoadOwnershipCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Store(netip.MustParseAddr("203.0.113.0"), netip.MustParseAddr("203.0.113.255"), &core.Ownership{Org: "Example Hosting Inc."}, time.Now())
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{OwnerOffline: true, OwnerCache: path}
+	hosts := []string{"203.0.113.5", "203.0.113.6", "10.0.0.1", "www.example.test", "192.0.2.9"}
+	resolved := map[string][]string{"www.example.test": {"203.0.113.80"}}
+	owners, more := publicOwners(context.Background(), cfg, hosts, resolved)
+	if want := []string{"Example Hosting Inc. (3)", "unknown (1)"}; !reflect.DeepEqual(owners, want) || more {
+		t.Errorf("publicOwners = %q, %v; want %q, false", owners, more, want)
+	}
+
+	many := make([]string, ownerPreviewLimit+1)
+	for i := range many {
+		many[i] = netip.AddrFrom4([4]byte{203, 0, 113, byte(i + 1)}).String()
+	}
+	owners, more = publicOwners(context.Background(), cfg, many, nil)
+	if want := []string{"Example Hosting Inc. (20)"}; !reflect.DeepEqual(owners, want) || !more {
+		t.Errorf("publicOwners over the limit = %q, %v; want %q, true", owners, more, want)
+	}
+}
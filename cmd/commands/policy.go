@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/targets"
+)
+
+// enforcePolicy refuses the scan when a target resolves into a range
+// --allow-localhost=false or --allow-private=false rules out.
+// validateRawTargets only sees what was typed, so a hostname pointing at
+// 10.0.0.5, or an address --all-ips added, is checked here, once targets
+// are expanded and before anything is probed. Hostnames that fail to
+// resolve are left for their probes to report.
+func enforcePolicy(ctx context.Context, cfg *config.Config, plans []scanPlan) error {
+	policy := targetPolicy(cfg)
+	if policy.AllowLocalhost && policy.AllowPrivate {
+		return nil
+	}
+	resolver := scanResolver(cfg)
+
+	var hosts []string
+	seen := make(map[string]bool)
+	for _, plan := range plans {
+		for _, t := range plan.targets {
+			if !seen[t.Host] {
+				seen[t.Host] = true
+				hosts = append(hosts, t.Host)
+			}
+		}
+	}
+	if resolver != nil {
+		resolver.Prefetch(ctx, hosts)
+	}
+
+	for _, plan := range plans {
+		for _, t := range plan.targets {
+			addrs := []string{t.Host}
+			if net.ParseIP(t.Host) == nil && resolver != nil {
+				resolved, err := resolver.LookupHost(ctx, t.Host)
+				if err != nil {
+					continue
+				}
+				addrs = resolved
+			}
+			for _, addr := range addrs {
+				if err := targets.ValidatePolicy(addr, policy); err != nil {
+					return errors.ScanPolicyError(policyTargetName(t.Host, t.Hostname, addr), err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// policyTargetName names a refused target with the address that put it
+// out of bounds, or the hostname --all-ips expanded it from.
+func policyTargetName(host, hostname, addr string) string {
+	switch {
+	case hostname != "":
+		return fmt.Sprintf("%s (%s)", host, hostname)
+	case addr != host:
+		return fmt.Sprintf("%s (%s)", host, addr)
+	}
+	return host
+}
@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/spf13/viper"
+)
+
+const (
+	// progressLineInterval throttles redraws of the in-place progress line.
+	progressLineInterval = 250 * time.Millisecond
+	// progressLogInterval throttles progress lines when stderr is a log file
+	// or CI console, where every update becomes a new line.
+	progressLogInterval = 5 * time.Second
+)
+
+// progressLine reports scan progress on stderr while results stream to
+// stdout. On a terminal it redraws a single line in place; otherwise it
+// prints a line every progressLogInterval.
+type progressLine struct {
+	w        io.Writer
+	inPlace  bool
+	interval time.Duration
+	now      func() time.Time
+	lastDraw time.Time
+	open     int
+	errors   int
+	drawn    bool
+}
+
+func newProgressLine(w io.Writer, inPlace bool) *progressLine {
+	interval := progressLogInterval
+	if inPlace {
+		interval = progressLineInterval
+	}
+	return &progressLine{w: w, inPlace: inPlace, interval: interval, now: time.Now}
+}
+
+// isTerminal reports whether f is attached to a character device.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// withProgress wraps events with a stderr progress line unless --quiet is
+// set. The line redraws in place only when stderr is a terminal and stdout
+// is not, so it never interleaves with results printed to the same screen.
+func withProgress(events <-chan core.Event) <-chan core.Event {
+	if viper.GetBool("quiet") {
+		return events
+	}
+	inPlace := isTerminal(os.Stderr) && !isTerminal(os.Stdout)
+	return tapProgress(events, newProgressLine(os.Stderr, inPlace))
+}
+
+// observe records an event and redraws the line for progress events.
+func (p *progressLine) observe(event core.Event) {
+	switch event.Kind {
+	case core.EventKindResult:
+		if event.Result.State == core.StateOpen {
+			p.open++
+		}
+	case core.EventKindError:
+		p.errors++
+	case core.EventKindProgress:
+		now := p.now()
+		finished := event.Progress.Total > 0 && event.Progress.Completed >= event.Progress.Total
+		if !finished && p.drawn && now.Sub(p.lastDraw) < p.interval {
+			return
+		}
+		p.lastDraw = now
+		p.draw(*event.Progress)
+	}
+}
+
+func (p *progressLine) draw(progress core.ProgressEvent) {
+	if progress.Total == 0 {
+		return
+	}
+	percent := float64(progress.Completed) / float64(progress.Total) * 100
+	line := fmt.Sprintf("%5.1f%% %d/%d • %.0f pps • ETA %s • %d open",
+		percent, progress.Completed, progress.Total, progress.Rate, progressETA(progress), p.open)
+	if p.errors > 0 {
+		line += fmt.Sprintf(" • %d errors", p.errors)
+	}
+	if progress.Paused {
+		line += " • paused"
+	}
+	if p.inPlace {
+		// \033[K clears leftovers from a longer previous line.
+		fmt.Fprintf(p.w, "\r%s\033[K", line)
+	} else {
+		fmt.Fprintln(p.w, line)
+	}
+	p.drawn = true
+}
+
+// finish ends the in-place line so later output starts on a fresh line.
+func (p *progressLine) finish() {
+	if p.inPlace && p.drawn {
+		fmt.Fprintln(p.w)
+	}
+}
+
+// progressETA estimates the remaining time from the recent rate, falling
+// back to the whole-scan rate before the window has filled. A paused scan
+// has no ETA.
+func progressETA(progress core.ProgressEvent) string {
+	remaining := progress.Total - progress.Completed
+	if remaining <= 0 {
+		return "0s"
+	}
+	rate := progress.RecentRate
+	if rate <= 0 {
+		rate = progress.Rate
+	}
+	if progress.Paused || rate <= 0 {
+		return "--"
+	}
+	eta := time.Duration(float64(remaining) / rate * float64(time.Second))
+	return eta.Round(time.Second).String()
+}
+
+// tapProgress forwards events unchanged while feeding them to p.
+func tapProgress(events <-chan core.Event, p *progressLine) <-chan core.Event {
+	out := make(chan core.Event, cap(events))
+	go func() {
+		defer close(out)
+		defer p.finish()
+		for event := range events {
+			p.observe(event)
+			out <- event
+		}
+	}()
+	return out
+}
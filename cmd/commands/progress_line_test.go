@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+func TestProgressLineLogMode(t *testing.T) {
+	var buf bytes.Buffer
+	p := newProgressLine(&buf, false)
+	now := time.Unix(0, 0)
+	p.now = func() time.Time { return now }
+
+	p.observe(core.NewResultEvent(core.ResultEvent{State: core.StateOpen}))
+	p.observe(core.NewProgressEvent(core.ProgressEvent{Total: 100, Completed: 50, Rate: 10}))
+	// Throttled: within the log interval and not finished.
+	now = now.Add(time.Second)
+	p.observe(core.NewProgressEvent(core.ProgressEvent{Total: 100, Completed: 60, Rate: 10}))
+	// Completion is always reported.
+	p.observe(core.NewProgressEvent(core.ProgressEvent{Total: 100, Completed: 100, Rate: 10}))
+	p.finish()
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 progress lines, got %q", buf.String())
+	}
+	if want := " 50.0% 50/100 • 10 pps • ETA 5s • 1 open"; lines[0] != want {
+		t.Errorf("line = %q, want %q", lines[0], want)
+	}
+	if !strings.Contains(lines[1], "ETA 0s") {
+		t.Errorf("final line should report no remaining time, got %q", lines[1])
+	}
+}
+
+func TestProgressLineInPlace(t *testing.T) {
+	var buf bytes.Buffer
+	p := newProgressLine(&buf, true)
+	p.observe(core.NewProgressEvent(core.ProgressEvent{Total: 10, Completed: 1}))
+	p.finish()
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "\r") || !strings.HasSuffix(out, "\n") {
+		t.Errorf("in-place line should start with CR and end with a newline, got %q", out)
+	}
+	if !strings.Contains(out, "ETA --") {
+		t.Errorf("unknown rate should show no ETA, got %q", out)
+	}
+}
+
+func TestTapProgressForwardsEvents(t *testing.T) {
+	var buf bytes.Buffer
+	events := make(chan core.Event, 2)
+	events <- core.NewResultEvent(core.ResultEvent{Port: 22, State: core.StateOpen})
+	events <- core.NewProgressEvent(core.ProgressEvent{Total: 1, Completed: 1})
+	close(events)
+
+	var forwarded int
+	for range tapProgress(events, newProgressLine(&buf, false)) {
+		forwarded++
+	}
+	if forwarded != 2 {
+		t.Errorf("forwarded %d events, want 2", forwarded)
+	}
+	if !strings.Contains(buf.String(), "1 open") {
+		t.Errorf("expected open count in progress, got %q", buf.String())
+	}
+}
+
+func TestProgressLineCountsErrors(t *testing.T) {
+	var buf bytes.Buffer
+	p := newProgressLine(&buf, false)
+	p.observe(core.NewErrorEvent(&core.ScanError{Host: "x.invalid", Port: 80, Protocol: "tcp"}))
+	p.observe(core.NewProgressEvent(core.ProgressEvent{Total: 2, Completed: 1}))
+
+	if !strings.HasSuffix(strings.TrimSpace(buf.String()), "• 1 errors") {
+		t.Errorf("progress line should report errors, got %q", buf.String())
+	}
+}
+
+func TestProgressETA(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress core.ProgressEvent
+		want     string
+	}{
+		{"average rate", core.ProgressEvent{Total: 100, Completed: 40, Rate: 10}, "6s"},
+		{"recent rate wins", core.ProgressEvent{Total: 100, Completed: 40, Rate: 10, RecentRate: 20}, "3s"},
+		{"paused", core.ProgressEvent{Total: 100, Completed: 40, Rate: 10, RecentRate: 20, Paused: true}, "--"},
+		{"done", core.ProgressEvent{Total: 100, Completed: 100, Paused: true}, "0s"},
+	}
+	for _, tt := range tests {
+		if got := progressETA(tt.progress); got != tt.want {
+			t.Errorf("%s: progressETA() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/spf13/viper"
+)
+
+// publishOutput adapts the broker exporter to the streaming output path and
+// reports how many results were published when it closes.
+type publishOutput struct {
+	*exporter.PublishExporter
+	target string
+}
+
+// newPublishExporter connects to the Kafka brokers or NATS servers for
+// --output kafka or nats and returns an exporter publishing each result.
+func newPublishExporter(cfg *config.Config) (*publishOutput, error) {
+	tlsConfig, err := cfg.GetBrokerTLS()
+	if err != nil {
+		return nil, err
+	}
+	var pub exporter.Publisher
+	switch cfg.Output {
+	case "nats":
+		pub, err = exporter.NewNATSPublisher(exporter.NATSOptions{
+			Servers:   cfg.GetBrokers(),
+			Subject:   cfg.Topic,
+			TLS:       tlsConfig,
+			CredsFile: cfg.NATSCreds,
+			Username:  cfg.BrokerUser,
+			Password:  cfg.BrokerPassword,
+		})
+	default:
+		pub, err = exporter.NewKafkaPublisher(exporter.KafkaOptions{
+			Brokers:     cfg.GetBrokers(),
+			Topic:       cfg.Topic,
+			Partitioner: cfg.Partitioner,
+			TLS:         tlsConfig,
+			SASL:        cfg.BrokerSASL,
+			Username:    cfg.BrokerUser,
+			Password:    cfg.BrokerPassword,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	exp, err := exporter.NewPublishExporter(pub, exporter.PublishOptions{
+		Key:            cfg.MessageKey,
+		BannerEncoding: viper.GetString("banner_encoding"),
+	})
+	if err != nil {
+		_ = pub.Close()
+		return nil, err
+	}
+	return &publishOutput{PublishExporter: exp, target: cfg.Output + " " + cfg.Topic}, nil
+}
+
+// Close publishes the last batch and reports the published count.
+func (o *publishOutput) Close() error {
+	if err := o.PublishExporter.Close(); err != nil {
+		return err
+	}
+	if !viper.GetBool("quiet") {
+		fmt.Fprintf(os.Stderr, "Published %d results to %s\n", o.Published(), o.target)
+	}
+	return nil
+}
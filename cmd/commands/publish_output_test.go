@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/spf13/viper"
+)
+
+func TestValidateInputs_Publish(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	cfg := &config.Config{Ports: "80", Rate: 100, TimeoutMs: 200, Output: "kafka", Topic: "scans"}
+	err := validateInputs(cfg)
+	if err == nil || !strings.Contains(err.Error(), "broker") {
+		t.Fatalf("validateInputs() = %v, want a broker error", err)
+	}
+
+	cfg.Brokers = "localhost:9092"
+	if err := validateInputs(cfg); err != nil {
+		t.Errorf("validateInputs() = %v", err)
+	}
+}
+
+func TestNewPublishExporterUnreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	for _, output := range []string{"kafka", "nats"} {
+		cfg := &config.Config{Output: output, Brokers: addr, Topic: "scans"}
+		if _, err := newPublishExporter(cfg); err == nil || !strings.Contains(err.Error(), output) {
+			t.Errorf("%s: err = %v, want a connection error", output, err)
+		}
+	}
+}
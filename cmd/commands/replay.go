@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/lucchesi-sec/portscan/pkg/replay"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay FILE",
+	Short: "Play back a scan recorded with --record",
+	Long: `Play back the events of a scan recorded with 'portscan scan --record FILE'
+into the TUI or an exporter, without touching the network. The recorded pacing
+is kept, scaled by --speed, so UI work, demos and bug reports can reuse one
+real scan. Recordings saved with a .gz or .zst name are decompressed.
+
+Examples:
+  portscan scan 10.0.0.0/24 --record events.bin
+  portscan replay events.bin --speed 10x
+  portscan replay events.bin --speed max --output json > results.ndjson`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+	addReplayFlags(replayCmd)
+	registerReplayCompletions(replayCmd)
+}
+
+// addReplayFlags registers the playback and output flags on cmd.
+func addReplayFlags(cmd *cobra.Command) {
+	cmd.Flags().String("speed", "1x", "playback speed relative to the recording, e.g. 10x or 0.5x; max skips the waits")
+	cmd.Flags().StringP("output", "o", "", "output format: json, csv, html, or table (default: the TUI)")
+	cmd.Flags().String("output-file", "", "write results to a file atomically instead of stdout; a .gz or .zst name compresses it")
+	cmd.Flags().String("compress", "", "compress the output: gzip, zstd or none (default: by --output-file suffix)")
+	cmd.Flags().Bool("json-array", false, "output JSON as a single array instead of NDJSON stream")
+	cmd.Flags().Bool("json-object", false, "output a single JSON object with scan_info and results[]")
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	speedFlag, _ := cmd.Flags().GetString("speed")
+	speed, err := replay.ParseSpeed(speedFlag)
+	if err != nil {
+		return err
+	}
+	cfg, err := replayOutputConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	in, err := decompressRecording(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	reader, err := replay.NewReader(in)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	cleanupInterrupts := monitorInterrupts(cancel)
+	defer cleanupInterrupts()
+	playCtx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	player := replay.NewPlayer(reader, speed)
+	header := reader.Header
+	metadata := exporter.ScanMetadata{Targets: header.Targets, TotalPorts: header.TotalPorts}
+	handle := scanHandle{control: player, cancel: stop, offline: true}
+	if err := handleScanOutput(ctx, cfg, player.Play(playCtx), header.TotalPorts, metadata, handle); err != nil {
+		return err
+	}
+	if interrupted(ctx) {
+		return &errors.ExitError{Code: errors.ExitAborted, Err: errInterrupted}
+	}
+	if err := player.Err(); err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	return nil
+}
+
+// replayOutputConfig maps the output flags onto the config fields the
+// shared output path reads. Without --output or --output-file the
+// recording plays in the TUI.
+func replayOutputConfig(cmd *cobra.Command) (*config.Config, error) {
+	flags := cmd.Flags()
+	output, _ := flags.GetString("output")
+	outputFile, _ := flags.GetString("output-file")
+	compress, _ := flags.GetString("compress")
+
+	switch output {
+	case "", "json", "csv", "html", "table":
+	default:
+		return nil, fmt.Errorf("unsupported output format %q: use json, csv, html, or table", output)
+	}
+	switch compress {
+	case "", exporter.CompressionNone, exporter.CompressionGzip, exporter.CompressionZstd:
+	default:
+		return nil, fmt.Errorf("unsupported compression %q: use gzip, zstd or none", compress)
+	}
+	// The JSON exporter reads its layout from viper, as for scans.
+	_ = viper.BindPFlag("json_array", flags.Lookup("json-array"))
+	_ = viper.BindPFlag("json_object", flags.Lookup("json-object"))
+
+	// The config file still supplies the TUI settings.
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	cfg.Output, cfg.OutputFile, cfg.Compress, cfg.Upload = output, outputFile, compress, ""
+	return cfg, nil
+}
+
+// decompressRecording undoes gzip or zstd compression, recognized by the
+// stream's magic bytes, so a recording saved as events.bin.gz replays.
+func decompressRecording(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(4)
+	switch {
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		return gzip.NewReader(br)
+	case bytes.Equal(head, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		dec, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	}
+	return br, nil
+}
+
+// openRecording starts recording the scan's events to path for --record.
+// The returned finish func commits the file, or discards it when the scan
+// failed.
+func openRecording(path string, header replay.Header) (*replay.Recorder, func(error) error, error) {
+	file, err := exporter.CreateFile(path, exporter.FileOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	rec, err := replay.NewRecorder(file, header)
+	if err != nil {
+		file.Abort()
+		return nil, nil, err
+	}
+	finish := func(scanErr error) error {
+		if scanErr == nil {
+			scanErr = rec.Err()
+		}
+		if scanErr != nil {
+			file.Abort()
+			return scanErr
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+		if !viper.GetBool("quiet") {
+			fmt.Fprintf(os.Stderr, "Events recorded to %s\n", path)
+		}
+		return nil
+	}
+	return rec, finish, nil
+}
+
+// recordingHeader describes the scan about to be recorded.
+func recordingHeader(cfg *config.Config, hosts []string, totalPorts int) replay.Header {
+	return replay.Header{Targets: hosts, TotalPorts: totalPorts, Protocol: normalizeProtocol(cfg.Protocol)}
+}
@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/lucchesi-sec/portscan/pkg/replay"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// newReplayTestCommand returns a fresh replay command so flag values do not
+// leak between tests.
+func newReplayTestCommand(args ...string) *cobra.Command {
+	cmd := &cobra.Command{Use: "replay", Args: cobra.ExactArgs(1), RunE: runReplay}
+	addReplayFlags(cmd)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(args)
+	return cmd
+}
+
+func TestReplayCommand(t *testing.T) {
+	found := false
+	for _, sub := range rootCmd.Commands() {
+		if sub == replayCmd {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("replay should be registered under root")
+	}
+	for _, name := range []string{"speed", "output", "output-file", "compress", "json-array", "json-object"} {
+		if replayCmd.Flags().Lookup(name) == nil {
+			t.Errorf("missing --%s flag", name)
+		}
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("quiet", true)
+
+	dir := t.TempDir()
+	recording := filepath.Join(dir, "events.bin.gz")
+	rec, finish, err := openRecording(recording, replay.Header{Targets: []string{"10.0.0.1"}, TotalPorts: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Observe(core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen, Protocol: "tcp"}))
+	rec.Observe(core.NewErrorEvent(&core.ScanError{Host: "10.0.0.1", Port: 23, Protocol: "tcp", Err: errors.New("no route")}))
+	if err := finish(nil); err != nil {
+		t.Fatalf("finish recording: %v", err)
+	}
+
+	out := filepath.Join(dir, "replayed.json")
+	cmd := newReplayTestCommand(recording, "--speed", "max", "--output", "json", "--json-object", "--output-file", out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("output not written: %v", err)
+	}
+	for _, want := range []string{`"port":22`, `"error":"no route"`, `"targets":["10.0.0.1"]`, `"total_ports":2`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("replayed document lacks %s:\n%s", want, data)
+		}
+	}
+}
+
+func TestReplayRejectsBadInput(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	notRecording := filepath.Join(t.TempDir(), "scan.json")
+	if err := os.WriteFile(notRecording, []byte(`{"results":[]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := newReplayTestCommand(notRecording, "--output", "json").Execute(); err == nil || !strings.Contains(err.Error(), "not a portscan event recording") {
+		t.Errorf("replay of a JSON file: error = %v", err)
+	}
+	if err := newReplayTestCommand(notRecording, "--speed", "fast").Execute(); err == nil || !strings.Contains(err.Error(), "invalid speed") {
+		t.Errorf("replay with a bad speed: error = %v", err)
+	}
+}
+
+func TestValidateInputs_SessionRecording(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	cfg := config.Config{Ports: "80", Rate: 5000, TimeoutMs: 200, Workers: 50, UDPWorkerRatio: 0.5, RecordSession: "session.cast"}
+	if err := validateInputs(&cfg); err != nil {
+		t.Errorf("recording the TUI should be accepted, got %v", err)
+	}
+
+	cfg.Output = "csv"
+	if err := validateInputs(&cfg); err == nil || !strings.Contains(err.Error(), "TUI") {
+		t.Errorf("a session recording without the TUI should be rejected, got %v", err)
+	}
+
+	cfg.RecordSession = ""
+	cfg.Record = "events.bin.gz"
+	if err := validateInputs(&cfg); err != nil {
+		t.Errorf("recording events with an export should be accepted, got %v", err)
+	}
+
+	// --record keeps recording events whatever the file is called.
+	cfg.Record = "events.cast"
+	if err := validateInputs(&cfg); err != nil {
+		t.Errorf("--record with a .cast name should record events, got %v", err)
+	}
+}
+
+// idleScanner is a PortScanner that signals when a scan is started.
+type idleScanner struct {
+	results chan core.Event
+	started chan struct{}
+}
+
+func (s *idleScanner) Results() <-chan core.Event                     { return s.results }
+func (s *idleScanner) ScanRange(context.Context, string, []uint16)    { close(s.started) }
+func (s *idleScanner) ScanTargets(context.Context, []core.ScanTarget) { close(s.started) }
+
+func TestRunScanPlans_BadRecordPathFailsBeforeScanning(t *testing.T) {
+	scanner := &idleScanner{results: make(chan core.Event), started: make(chan struct{})}
+	plans := []scanPlan{{scanner: scanner, targets: buildScanTargets([]string{"127.0.0.1"}, []uint16{22})}}
+	cfg := &config.Config{AllowLocalhost: true, Record: filepath.Join(t.TempDir(), "missing", "scan.events.gz")}
+
+	err := runScanPlansTo(context.Background(), plans, cfg, func(context.Context, *config.Config, <-chan core.Event, int, exporter.ScanMetadata, scanHandle) error {
+		t.Error("output should not run when the recording cannot be opened")
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("err = %v, want the recording's path error", err)
+	}
+	select {
+	case <-scanner.started:
+		t.Error("no probe should go out before the recording is open")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
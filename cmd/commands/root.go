@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -23,16 +25,23 @@ Scans thousands of ports per second with real-time visualization.`,
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	if err != nil {
+		printError(os.Stderr, err)
+	}
+	return err
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
+	rootCmd.SilenceErrors = true // Execute prints errors so they honor --no-color
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.portscan.yaml)")
 	rootCmd.PersistentFlags().BoolVar(&quiet, "quiet", false, "suppress non-essential output")
-	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output (also set by NO_COLOR)")
 	rootCmd.PersistentFlags().BoolVar(&logJSON, "log-json", false, "output logs in JSON format")
+	rootCmd.PersistentFlags().String("error-format", "text", "format of errors on stderr: text or json (code, message, details, suggestion)")
+	rootCmd.PersistentFlags().String("lang", "", "language for messages: en or es (default: from LC_ALL, LC_MESSAGES or LANG)")
 
 	rootCmd.PersistentFlags().Bool("profile", false, "enable pprof profiling")
 	rootCmd.PersistentFlags().Bool("trace", false, "enable execution tracing")
@@ -42,6 +51,10 @@ func init() {
 	_ = viper.BindPFlag("quiet", rootCmd.PersistentFlags().Lookup("quiet"))
 	_ = viper.BindPFlag("no_color", rootCmd.PersistentFlags().Lookup("no-color"))
 	_ = viper.BindPFlag("log_json", rootCmd.PersistentFlags().Lookup("log-json"))
+	_ = viper.BindPFlag("error_format", rootCmd.PersistentFlags().Lookup("error-format"))
+	_ = viper.BindPFlag("lang", rootCmd.PersistentFlags().Lookup("lang"))
+
+	rootCmd.SetHelpFunc(localizedHelp(rootCmd.HelpFunc()))
 }
 
 func initConfig() {
@@ -63,9 +76,26 @@ func initConfig() {
 	viper.SetEnvPrefix("PORTSCAN")
 	viper.AutomaticEnv()
 
+	var expandErr error
 	if err := viper.ReadInConfig(); err == nil {
 		if !quiet {
 			fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 		}
+		expandErr = config.ExpandFile(viper.GetViper())
+	}
+
+	applyColorMode()
+	applyLanguage(os.Stderr)
+
+	// A missing include or an include cycle leaves the configuration
+	// incomplete, so nothing should run with it.
+	if expandErr != nil {
+		printError(os.Stderr, errors.ConfigLoadError(viper.ConfigFileUsed(), expandErr))
+		os.Exit(1)
+	}
+
+	// Keep stderr a single JSON object for callers parsing errors.
+	if viper.GetString("error_format") == "json" {
+		rootCmd.SilenceUsage = true
 	}
 }
@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lucchesi-sec/portscan/internal/privdrop"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// dropPrivileges switches to the --run-as user once privileged setup, such
+// as raising the open file limit, is done and before any target is read or
+// probed. An empty runAs keeps the current user.
+func dropPrivileges(runAs string) error {
+	if runAs == "" {
+		return nil
+	}
+	creds, err := privdrop.Lookup(runAs)
+	if err != nil {
+		return errors.PrivilegeDropError(runAs, err)
+	}
+	if err := privdrop.Drop(creds); err != nil {
+		return errors.PrivilegeDropError(runAs, err)
+	}
+	if viper.GetBool("verbose") {
+		fmt.Fprintf(os.Stderr, "Running as %s (uid %d, gid %d)\n", runAs, creds.UID, creds.GID)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package commands
+
+import (
+	stdErrors "errors"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+)
+
+func TestDropPrivileges(t *testing.T) {
+	if err := dropPrivileges(""); err != nil {
+		t.Errorf("an empty --run-as should keep the current user: %v", err)
+	}
+
+	err := dropPrivileges("no-such-user-portscan")
+	var userErr *errors.UserError
+	if !stdErrors.As(err, &userErr) || userErr.Code != "PRIVILEGE_DROP_FAILED" {
+		t.Errorf("unknown user: err = %v; want PRIVILEGE_DROP_FAILED", err)
+	}
+}
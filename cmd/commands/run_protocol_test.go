@@ -12,6 +12,7 @@ import (
 
 	"github.com/lucchesi-sec/portscan/internal/core"
 	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
 	"github.com/spf13/viper"
 )
 
@@ -49,6 +50,7 @@ func TestRunProtocolScanJSONOutput(t *testing.T) {
 		TimeoutMs:      200,
 		Banners:        false,
 		UDPWorkerRatio: 0.5,
+		AllowLocalhost: true,
 	}
 
 	scannerCfg := &core.Config{
@@ -137,3 +139,132 @@ func TestRunProtocolScanJSONOutput(t *testing.T) {
 		t.Fatalf("expected total_ports to be 1, got %d", parsed.ScanInfo.TotalPorts)
 	}
 }
+
+func TestRunScannersBothMergesProtocols(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+	port := uint16(ln.Addr().(*net.TCPAddr).Port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	cfg := &config.Config{Rate: 1000, Workers: 4, TimeoutMs: 200, UDPWorkerRatio: 0.5, AllowLocalhost: true}
+	factory := NewScannerFactory(cfg)
+	tcpScanner, _ := factory.CreateScanner("tcp")
+	udpScanner, _ := factory.CreateScanner("udp")
+
+	viper.Set("json", true)
+	viper.Set("json_array", true)
+	viper.Set("quiet", true)
+
+	origStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+	os.Stdout = w
+	defer func() { os.Stdout = origStdout }()
+	var buf bytes.Buffer
+	readDone := make(chan struct{})
+	go func() {
+		_, _ = buf.ReadFrom(r)
+		close(readDone)
+	}()
+
+	err = runScanners(ctx, []core.PortScanner{tcpScanner, udpScanner}, []string{"127.0.0.1"}, []uint16{port}, cfg)
+	_ = w.Close()
+	<-readDone
+	if err != nil {
+		t.Fatalf("runScanners returned error: %v", err)
+	}
+
+	var results []struct {
+		Port     uint16 `json:"port"`
+		Protocol string `json:"protocol"`
+		State    string `json:"state"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &results); err != nil {
+		t.Fatalf("failed to decode JSON output: %v\n%s", err, buf.String())
+	}
+
+	protocols := make(map[string]string)
+	for _, res := range results {
+		protocols[res.Protocol] = res.State
+	}
+	if len(results) != 2 || protocols["tcp"] != string(core.StateOpen) || protocols["udp"] == "" {
+		t.Fatalf("expected one open TCP and one UDP result, got %+v", results)
+	}
+}
+
+type fakePausable struct{ paused bool }
+
+func (f *fakePausable) Pause()       { f.paused = true }
+func (f *fakePausable) Resume()      { f.paused = false }
+func (f *fakePausable) Paused() bool { return f.paused }
+
+func TestPauseGroup(t *testing.T) {
+	a, b := &fakePausable{}, &fakePausable{}
+	group := pauseGroup{a, b}
+
+	group.Pause()
+	if !a.paused || !b.paused || !group.Paused() {
+		t.Fatal("Pause should pause every scanner")
+	}
+	group.Resume()
+	if a.paused || b.paused || group.Paused() {
+		t.Fatal("Resume should resume every scanner")
+	}
+}
+
+// TestRunScanPlans_BothProtocolsRateChangesBudget checks that a
+// --protocol both scan, whose scanners are paced by a shared budget alone,
+// still gives the TUI a rate to change, and that changes reach the budget.
+func TestRunScanPlans_BothProtocolsRateChangesBudget(t *testing.T) {
+	cfg := &config.Config{Rate: 100, Workers: 2, TimeoutMs: 50, UDPWorkerRatio: 0.5, AllowLocalhost: true}
+	budget := core.NewRateBudget(cfg.Rate)
+	defer budget.Stop()
+	factory := NewScannerFactory(cfg).WithRateBudget(budget)
+	tcpScanner, _ := factory.CreateScanner("tcp")
+	udpScanner, _ := factory.CreateScanner("udp")
+	targets := buildScanTargets([]string{"127.0.0.1"}, []uint16{1})
+	plans := []scanPlan{{scanner: tcpScanner, targets: targets}, {scanner: udpScanner, targets: targets}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var before, applied int
+	err := runScanPlansTo(ctx, plans, cfg, func(_ context.Context, _ *config.Config, events <-chan core.Event, _ int, _ exporter.ScanMetadata, handle scanHandle) error {
+		if handle.rate == nil {
+			t.Fatal("both-protocol scan has no rate control")
+		}
+		before = handle.rate.Rate()
+		applied = handle.rate.SetRate(250)
+		for range events {
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("runScanPlansTo: %v", err)
+	}
+	if before != 100 {
+		t.Errorf("Rate() = %d, want the budget's 100", before)
+	}
+	if applied != 250 || budget.Rate() != 250 {
+		t.Errorf("SetRate(250) = %d, budget rate %d; want both 250", applied, budget.Rate())
+	}
+}
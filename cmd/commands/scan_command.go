@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -51,25 +52,107 @@ It provides real-time progress updates and can export results in various formats
 func init() {
 	rootCmd.AddCommand(scanCmd)
 
-	scanCmd.Flags().StringP("ports", "p", "1-1024", "ports to scan (e.g., '80,443,8080' or '1-1024')")
+	scanCmd.Flags().StringP("ports", "p", "1-1024", "ports to scan (e.g., '80,443', '1-1024,!22', '1000-2000/2' or 'ssh,https')")
 	scanCmd.Flags().StringP("profile", "P", "", "scan profile: quick, web, database, gateway, udp-common, voip, full")
 	scanCmd.Flags().StringP("protocol", "u", "tcp", "protocol to scan: tcp (default), udp, or both")
 	scanCmd.Flags().IntP("rate", "r", 7500, "packets per second rate limit")
 	scanCmd.Flags().IntP("timeout", "t", 200, "connection timeout in milliseconds")
-	scanCmd.Flags().IntP("workers", "w", 0, "number of concurrent workers (0=auto-detect)")
+	scanCmd.Flags().String("port-timeouts", "", "per-port timeout overrides in ms (e.g., '443=1000,3306=500')")
+	scanCmd.Flags().IntP("workers", "w", 0, "number of concurrent workers (0=auto-detect); capped to the open file limit")
+	scanCmd.Flags().Bool("raise-fd-limit", false, "raise the soft open file limit (ulimit -n) to fit the workers before scanning")
+	scanCmd.Flags().String("scope", "", "allowlist YAML of networks and domains; refuse to scan if any expanded target is outside it")
+	scanCmd.Flags().String("scope-key", "", "Ed25519 public key (PEM or base64) that must have signed the --scope file into FILE.sig")
+	scanCmd.Flags().Bool("allow-localhost", true, "permit loopback targets and localhost (--allow-localhost=false refuses them)")
+	scanCmd.Flags().Bool("allow-private", true, "permit private and link-local targets (--allow-private=false refuses them)")
+	scanCmd.Flags().String("exclude-rdns", "", "skip targets whose PTR names match these comma-separated globs (e.g., '*.scada.corp')")
+	scanCmd.Flags().String("exclude-tag", "", "skip targets with a tag matching these comma-separated key=value globs (e.g., 'role=plc*')")
+	scanCmd.Flags().String("run-as", "", "when started as root, drop privileges to USER[:GROUP] after setup and before scanning")
+	scanCmd.Flags().StringP("timing", "T", "", "timing template T0-T5 (paranoid, sneaky, polite, normal, aggressive, insane); explicit flags override it")
+	scanCmd.Flags().Int("retries", 2, "retry attempts for ports that time out")
+	scanCmd.Flags().Int("jitter", 0, "random delay of up to this many milliseconds before each probe")
+	scanCmd.Flags().Int("host-parallelism", 0, "maximum concurrent probes per host (0=unlimited)")
+	scanCmd.Flags().String("max-bandwidth", "", "pause the scan while this host sends more than this many bits per second (e.g., '100Mbit')")
+	scanCmd.Flags().Int("max-pps", 0, "pause the scan while this host sends more than this many packets per second (0=no limit)")
+	scanCmd.Flags().String("bandwidth-iface", "", "interface whose traffic --max-bandwidth and --max-pps measure (default: all but loopback)")
+	scanCmd.Flags().Int("conntrack-limit", 90, "pause the scan while the Linux conntrack table is this percent full (0=off)")
+	scanCmd.Flags().String("dns-server", "", "resolve target hostnames through this DNS server: host[:port], tls://host[:port] (DoT) or https://host/path (DoH)")
+	scanCmd.Flags().Int("dns-timeout", 0, "timeout for each hostname lookup in milliseconds (0=5000)")
+	scanCmd.Flags().Bool("all-ips", false, "scan every address a hostname resolves to, not only the first; results keep the hostname")
 	scanCmd.Flags().Float64("udp-worker-ratio", 0.5, "ratio of workers to use for UDP scanning (0.0-1.0)")
+	scanCmd.Flags().String("udp-probes", "", "UDP probe packs replacing the built-in payloads: comma-separated YAML files, or 'extended' for the bundled pack")
+	scanCmd.Flags().String("snmp-communities", "", "try these SNMP communities on UDP 161 and report sysDescr/sysName (bare flag: public)")
+	scanCmd.Flags().Lookup("snmp-communities").NoOptDefVal = "public"
 	scanCmd.Flags().BoolP("banners", "b", false, "grab service banners")
+	scanCmd.Flags().Int("banner-max-bytes", 512, "maximum bytes read from each banner")
+	scanCmd.Flags().Int("banner-timeout", 1000, "banner read timeout in milliseconds")
+	scanCmd.Flags().Int("banner-workers", 50, "concurrent banner reads, separate from --workers")
+	scanCmd.Flags().String("banner-encoding", "text", "JSON banner encoding: text or base64 (byte-exact)")
+	scanCmd.Flags().Bool("verify-open", false, "re-connect to open TCP ports before reporting to drop accept-then-reset false positives")
+	scanCmd.Flags().Bool("detect-edge", false, "identify CDN/WAF front ends (Cloudflare, Akamai, Fastly, ...) on open web ports from headers and certificates")
+	scanCmd.Flags().Bool("local-discovery", false, "name devices on RFC 1918 hosts with mDNS, NetBIOS and SSDP queries")
+	scanCmd.Flags().Bool("check-ntp", false, "flag open NTP servers that answer mode 6/7 (monlist) queries, an amplification risk")
+	scanCmd.Flags().Bool("tls-inspect", false, "record the TLS version, cipher and certificate of TLS ports, upgrading SMTP, IMAP, POP3, FTP and LDAP with STARTTLS")
+	scanCmd.Flags().Bool("http-audit", false, "grade the security headers (HSTS, CSP, X-Frame-Options, ...) and record the redirects of open web ports")
+	scanCmd.Flags().Bool("owner-lookup", false, "record the organization and abuse contact registered for public targets' networks (RDAP)")
+	scanCmd.Flags().Bool("owner-offline", false, "answer --owner-lookup from the cache alone, sending no RDAP queries")
+	scanCmd.Flags().String("owner-cache", "", "file RDAP answers are cached in (default: rdap.json in the user cache directory)")
+	scanCmd.Flags().String("geoip-db", "", "MaxMind DB files (comma-separated, e.g. GeoLite2-Country.mmdb,GeoLite2-ASN.mmdb) adding each host's country and ASN")
+	scanCmd.Flags().String("fail-on", "", "exit with code 3 if any of these ports is found open (e.g. '23,3389'), or 'any' for any open port")
+	scanCmd.Flags().Bool("strict", false, "exit with code 4 if any probe fails, not only when a host has no results")
+	scanCmd.Flags().StringArray("tag", nil, "attach a key=value tag to the scan and every result, e.g. env=prod (repeatable)")
+	scanCmd.Flags().StringArray("job", nil, "run a scan job NAME:TARGETS_FILE[:profile=P][:ports=P][:protocol=P][:output=F][:file=PATH] (repeatable; jobs share --rate)")
+	scanCmd.Flags().String("from-masscan", "", "scan the open ports in a masscan -oJ/-oD/-oL/-oX file instead of targets (e.g., with --banners)")
+	scanCmd.Flags().String("scan-window", "", "only scan during a daily local-time window, pausing outside it (e.g., '22:00-06:00')")
+	scanCmd.Flags().Bool("spread", false, "lower the rate so the scan is spread across the remaining --scan-window")
 
-	scanCmd.Flags().StringP("output", "o", "", "output format (json, csv, prometheus, table)")
+	scanCmd.Flags().StringP("output", "o", "", "output format (json, csv, html, prometheus, table, elastic, syslog, kafka, nats, inventory)")
+	scanCmd.Flags().String("output-file", "", "write results to a file atomically instead of stdout; a .gz or .zst name compresses it")
+	scanCmd.Flags().String("record", "", "record the scan's event stream to this file for 'portscan replay'")
+	scanCmd.Flags().String("record-session", "", "record the TUI session to this file as an asciinema cast (e.g. session.cast)")
+	scanCmd.Flags().String("timeline-file", "", "write a JSON timeline of the scan (start, hosts starting and finishing, rate changes, pauses, errors) to this file")
+	scanCmd.Flags().String("output-max-size", "", "rotate the NDJSON output file at this size (e.g., '100MB')")
+	scanCmd.Flags().Int("output-keep", exporter.DefaultRotateKeep, "number of rotated output files to keep")
+	scanCmd.Flags().String("compress", "", "compress exported output as it streams: gzip, zstd or none (default: by --output-file suffix)")
+	scanCmd.Flags().String("elastic-url", "", "Elasticsearch/OpenSearch URL for --output elastic (credentials may be in the URL)")
+	scanCmd.Flags().String("index", exporter.DefaultElasticIndex, "Elasticsearch index; %{+yyyy.MM.dd} expands to the UTC date")
+	scanCmd.Flags().Int("elastic-batch-size", exporter.DefaultElasticBatchSize, "documents per Elasticsearch bulk request")
+	scanCmd.Flags().String("syslog-addr", "", "syslog collector for --output syslog: udp://, tcp:// or tls://host:port (default: stdout)")
+	scanCmd.Flags().String("syslog-format", exporter.SyslogFormatRFC5424, "syslog message format: rfc5424 or cef")
+	scanCmd.Flags().String("inventory-format", exporter.InventoryJSON, "per-host inventory format for --output inventory: json or csv")
+	scanCmd.Flags().String("brokers", "", "comma-separated Kafka brokers (host:9092) or NATS servers (nats://host:4222) for --output kafka/nats")
+	scanCmd.Flags().String("topic", "portscan.results", "Kafka topic or NATS subject to publish results to")
+	scanCmd.Flags().String("message-key", exporter.MessageKeyHost, "key for published results: host, host_port, or none")
+	scanCmd.Flags().String("partitioner", exporter.PartitionerHash, "Kafka partitioner: hash (by key) or round-robin")
+	scanCmd.Flags().Bool("broker-tls", false, "connect to Kafka brokers or NATS servers over TLS")
+	scanCmd.Flags().String("broker-ca", "", "PEM CA bundle for broker certificates (implies --broker-tls)")
+	scanCmd.Flags().String("broker-cert", "", "PEM client certificate for the brokers (implies --broker-tls)")
+	scanCmd.Flags().String("broker-key", "", "PEM private key for --broker-cert")
+	scanCmd.Flags().String("broker-sasl", "", "Kafka SASL mechanism: plain, scram-sha-256, or scram-sha-512")
+	scanCmd.Flags().String("broker-user", "", "Kafka SASL or NATS user; set the password with PORTSCAN_BROKER_PASSWORD")
+	scanCmd.Flags().String("nats-creds", "", "NATS credentials file holding a user JWT and nkey seed")
+	scanCmd.Flags().String("upload", "", "upload the finished export to s3://bucket/key or gs://bucket/key; the key may use {date}, {time}, {scan_id}, {format}")
+	scanCmd.Flags().String("upload-endpoint", "", "S3-compatible endpoint for --upload, e.g. http://localhost:9000 for MinIO")
+	scanCmd.Flags().String("upload-region", "", "S3 region for --upload (default: the bucket's region)")
+	scanCmd.Flags().String("otel-endpoint", "", "send OpenTelemetry traces (scan, hosts, export) and probe metrics to this OTLP/HTTP collector, e.g. http://localhost:4318")
 	scanCmd.Flags().BoolP("stdin", "s", false, "read targets from stdin")
+	scanCmd.Flags().String("targets-csv", "", "read targets from a CSV or JSON asset inventory, e.g. an export from a CMDB")
+	scanCmd.Flags().String("host-field", "ip", "asset field holding each target's host, address or CIDR")
+	scanCmd.Flags().String("tag-fields", "", "comma-separated asset fields attached to each host's results as field=value tags (e.g. owner,env)")
+	scanCmd.Flags().String("asn", "", "scan the prefixes these comma-separated autonomous systems announce (e.g. AS13335)")
+	scanCmd.Flags().String("asn-data", "", "prefix-to-AS dataset --asn is looked up in, e.g. a RouteViews pfx2as file")
 	scanCmd.Flags().Bool("json", false, "output results as JSON")
 	scanCmd.Flags().Bool("json-array", false, "output JSON as a single array instead of NDJSON stream")
 	scanCmd.Flags().Bool("json-object", false, "output a single JSON object with scan_info and results[]")
 	scanCmd.Flags().Bool("only-open", false, "show only open ports in UI/table outputs")
 
-	scanCmd.Flags().String("ui.theme", "default", "UI theme (default, dracula, monokai)")
+	scanCmd.Flags().String("ui.theme", "default", "UI theme; auto follows the terminal background (see: portscan themes list)")
+	scanCmd.Flags().Bool("accessible", false, "colorblind-safe palette with glyphs for port states")
+	scanCmd.Flags().Bool("screen-reader", false, "announce open ports and progress as plain lines for screen readers instead of the TUI")
+	scanCmd.Flags().Bool("ascii", false, "draw the TUI with ASCII symbols instead of emoji and block glyphs (automatic on the legacy Windows console)")
 
 	scanCmd.Flags().Bool("dry-run", false, "validate parameters without scanning")
+	scanCmd.Flags().BoolP("yes", "y", false, "skip the confirmation asked before scans over 100,000 probes or of public addresses")
+	scanCmd.Flags().Bool("estimate", false, "expand targets and ports, then print the probe count, expected duration, memory use and warnings without scanning")
 	scanCmd.Flags().Bool("examples", false, "show extended examples and exit")
 	scanCmd.Flags().Bool("verbose", false, "enable verbose output for debugging")
 
@@ -78,16 +161,97 @@ func init() {
 	_ = viper.BindPFlag("protocol", scanCmd.Flags().Lookup("protocol"))
 	_ = viper.BindPFlag("rate", scanCmd.Flags().Lookup("rate"))
 	_ = viper.BindPFlag("timeout_ms", scanCmd.Flags().Lookup("timeout"))
+	_ = viper.BindPFlag("port_timeouts", scanCmd.Flags().Lookup("port-timeouts"))
 	_ = viper.BindPFlag("workers", scanCmd.Flags().Lookup("workers"))
+	_ = viper.BindPFlag("raise_fd_limit", scanCmd.Flags().Lookup("raise-fd-limit"))
+	_ = viper.BindPFlag("run_as", scanCmd.Flags().Lookup("run-as"))
+	_ = viper.BindPFlag("scope", scanCmd.Flags().Lookup("scope"))
+	_ = viper.BindPFlag("scope_key", scanCmd.Flags().Lookup("scope-key"))
+	_ = viper.BindPFlag("allow_localhost", scanCmd.Flags().Lookup("allow-localhost"))
+	_ = viper.BindPFlag("allow_private", scanCmd.Flags().Lookup("allow-private"))
+	_ = viper.BindPFlag("exclude_rdns", scanCmd.Flags().Lookup("exclude-rdns"))
+	_ = viper.BindPFlag("exclude_tags", scanCmd.Flags().Lookup("exclude-tag"))
+	_ = viper.BindPFlag("timing", scanCmd.Flags().Lookup("timing"))
+	_ = viper.BindPFlag("retries", scanCmd.Flags().Lookup("retries"))
+	_ = viper.BindPFlag("jitter_ms", scanCmd.Flags().Lookup("jitter"))
+	_ = viper.BindPFlag("host_parallelism", scanCmd.Flags().Lookup("host-parallelism"))
+	_ = viper.BindPFlag("max_bandwidth", scanCmd.Flags().Lookup("max-bandwidth"))
+	_ = viper.BindPFlag("max_pps", scanCmd.Flags().Lookup("max-pps"))
+	_ = viper.BindPFlag("bandwidth_iface", scanCmd.Flags().Lookup("bandwidth-iface"))
+	_ = viper.BindPFlag("conntrack_limit", scanCmd.Flags().Lookup("conntrack-limit"))
+	_ = viper.BindPFlag("dns_server", scanCmd.Flags().Lookup("dns-server"))
+	_ = viper.BindPFlag("dns_timeout_ms", scanCmd.Flags().Lookup("dns-timeout"))
+	_ = viper.BindPFlag("all_ips", scanCmd.Flags().Lookup("all-ips"))
 	_ = viper.BindPFlag("udp_worker_ratio", scanCmd.Flags().Lookup("udp-worker-ratio"))
+	_ = viper.BindPFlag("udp_probes", scanCmd.Flags().Lookup("udp-probes"))
+	_ = viper.BindPFlag("snmp_communities", scanCmd.Flags().Lookup("snmp-communities"))
 	_ = viper.BindPFlag("banners", scanCmd.Flags().Lookup("banners"))
+	_ = viper.BindPFlag("banner_max_bytes", scanCmd.Flags().Lookup("banner-max-bytes"))
+	_ = viper.BindPFlag("banner_timeout_ms", scanCmd.Flags().Lookup("banner-timeout"))
+	_ = viper.BindPFlag("banner_workers", scanCmd.Flags().Lookup("banner-workers"))
+	_ = viper.BindPFlag("banner_encoding", scanCmd.Flags().Lookup("banner-encoding"))
+	_ = viper.BindPFlag("verify_open", scanCmd.Flags().Lookup("verify-open"))
+	_ = viper.BindPFlag("detect_edge", scanCmd.Flags().Lookup("detect-edge"))
+	_ = viper.BindPFlag("local_discovery", scanCmd.Flags().Lookup("local-discovery"))
+	_ = viper.BindPFlag("check_ntp", scanCmd.Flags().Lookup("check-ntp"))
+	_ = viper.BindPFlag("tls_inspect", scanCmd.Flags().Lookup("tls-inspect"))
+	_ = viper.BindPFlag("http_audit", scanCmd.Flags().Lookup("http-audit"))
+	_ = viper.BindPFlag("owner_lookup", scanCmd.Flags().Lookup("owner-lookup"))
+	_ = viper.BindPFlag("owner_offline", scanCmd.Flags().Lookup("owner-offline"))
+	_ = viper.BindPFlag("owner_cache", scanCmd.Flags().Lookup("owner-cache"))
+	_ = viper.BindPFlag("geoip_db", scanCmd.Flags().Lookup("geoip-db"))
+	_ = viper.BindPFlag("fail_on", scanCmd.Flags().Lookup("fail-on"))
+	_ = viper.BindPFlag("strict", scanCmd.Flags().Lookup("strict"))
+	_ = viper.BindPFlag("from_masscan", scanCmd.Flags().Lookup("from-masscan"))
+	_ = viper.BindPFlag("scan_window", scanCmd.Flags().Lookup("scan-window"))
+	_ = viper.BindPFlag("spread", scanCmd.Flags().Lookup("spread"))
 	_ = viper.BindPFlag("output", scanCmd.Flags().Lookup("output"))
+	_ = viper.BindPFlag("output_file", scanCmd.Flags().Lookup("output-file"))
+	_ = viper.BindPFlag("record", scanCmd.Flags().Lookup("record"))
+	_ = viper.BindPFlag("record_session", scanCmd.Flags().Lookup("record-session"))
+	_ = viper.BindPFlag("timeline_file", scanCmd.Flags().Lookup("timeline-file"))
+	_ = viper.BindPFlag("output_max_size", scanCmd.Flags().Lookup("output-max-size"))
+	_ = viper.BindPFlag("output_keep", scanCmd.Flags().Lookup("output-keep"))
+	_ = viper.BindPFlag("compress", scanCmd.Flags().Lookup("compress"))
+	_ = viper.BindPFlag("elastic_url", scanCmd.Flags().Lookup("elastic-url"))
+	_ = viper.BindPFlag("elastic_index", scanCmd.Flags().Lookup("index"))
+	_ = viper.BindPFlag("elastic_batch_size", scanCmd.Flags().Lookup("elastic-batch-size"))
+	_ = viper.BindPFlag("syslog_addr", scanCmd.Flags().Lookup("syslog-addr"))
+	_ = viper.BindPFlag("syslog_format", scanCmd.Flags().Lookup("syslog-format"))
+	_ = viper.BindPFlag("inventory_format", scanCmd.Flags().Lookup("inventory-format"))
+	_ = viper.BindPFlag("brokers", scanCmd.Flags().Lookup("brokers"))
+	_ = viper.BindPFlag("topic", scanCmd.Flags().Lookup("topic"))
+	_ = viper.BindPFlag("message_key", scanCmd.Flags().Lookup("message-key"))
+	_ = viper.BindPFlag("partitioner", scanCmd.Flags().Lookup("partitioner"))
+	_ = viper.BindPFlag("broker_tls", scanCmd.Flags().Lookup("broker-tls"))
+	_ = viper.BindPFlag("broker_ca", scanCmd.Flags().Lookup("broker-ca"))
+	_ = viper.BindPFlag("broker_cert", scanCmd.Flags().Lookup("broker-cert"))
+	_ = viper.BindPFlag("broker_key", scanCmd.Flags().Lookup("broker-key"))
+	_ = viper.BindPFlag("broker_sasl", scanCmd.Flags().Lookup("broker-sasl"))
+	_ = viper.BindPFlag("broker_user", scanCmd.Flags().Lookup("broker-user"))
+	_ = viper.BindPFlag("nats_creds", scanCmd.Flags().Lookup("nats-creds"))
+	_ = viper.BindPFlag("upload", scanCmd.Flags().Lookup("upload"))
+	_ = viper.BindPFlag("upload_endpoint", scanCmd.Flags().Lookup("upload-endpoint"))
+	_ = viper.BindPFlag("upload_region", scanCmd.Flags().Lookup("upload-region"))
+	_ = viper.BindPFlag("otel_endpoint", scanCmd.Flags().Lookup("otel-endpoint"))
 	_ = viper.BindPFlag("stdin", scanCmd.Flags().Lookup("stdin"))
+	_ = viper.BindPFlag("targets_csv", scanCmd.Flags().Lookup("targets-csv"))
+	_ = viper.BindPFlag("host_field", scanCmd.Flags().Lookup("host-field"))
+	_ = viper.BindPFlag("tag_fields", scanCmd.Flags().Lookup("tag-fields"))
+	_ = viper.BindPFlag("asn", scanCmd.Flags().Lookup("asn"))
+	_ = viper.BindPFlag("asn_data", scanCmd.Flags().Lookup("asn-data"))
 	_ = viper.BindPFlag("json", scanCmd.Flags().Lookup("json"))
 	_ = viper.BindPFlag("json_array", scanCmd.Flags().Lookup("json-array"))
 	_ = viper.BindPFlag("json_object", scanCmd.Flags().Lookup("json-object"))
 	_ = viper.BindPFlag("ui.theme", scanCmd.Flags().Lookup("ui.theme"))
+	_ = viper.BindPFlag("ui.accessible", scanCmd.Flags().Lookup("accessible"))
+	_ = viper.BindPFlag("ui.screen_reader", scanCmd.Flags().Lookup("screen-reader"))
+	_ = viper.BindPFlag("ui.ascii", scanCmd.Flags().Lookup("ascii"))
 	_ = viper.BindPFlag("dry_run", scanCmd.Flags().Lookup("dry-run"))
+	_ = viper.BindPFlag("yes", scanCmd.Flags().Lookup("yes"))
+	_ = viper.BindPFlag("estimate", scanCmd.Flags().Lookup("estimate"))
 	_ = viper.BindPFlag("verbose", scanCmd.Flags().Lookup("verbose"))
 	_ = viper.BindPFlag("only_open", scanCmd.Flags().Lookup("only-open"))
+
+	registerScanCompletions(scanCmd)
 }
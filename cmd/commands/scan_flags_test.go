@@ -24,17 +24,87 @@ func TestFlagBindings_AllFlags(t *testing.T) {
 		{"json-array", "bool"},
 		{"json-object", "bool"},
 		{"banners", "bool"},
+		{"banner-max-bytes", "int"},
+		{"banner-timeout", "int"},
+		{"banner-workers", "int"},
+		{"raise-fd-limit", "bool"},
+		{"run-as", "string"},
+		{"scope", "string"},
+		{"scope-key", "string"},
+		{"allow-localhost", "bool"},
+		{"allow-private", "bool"},
+		{"exclude-rdns", "string"},
+		{"exclude-tag", "string"},
+		{"banner-encoding", "string"},
+		{"verify-open", "bool"},
+		{"detect-edge", "bool"},
+		{"local-discovery", "bool"},
+		{"check-ntp", "bool"},
+		{"tls-inspect", "bool"},
+		{"http-audit", "bool"},
+		{"owner-lookup", "bool"},
+		{"owner-offline", "bool"},
+		{"owner-cache", "string"},
+		{"geoip-db", "string"},
+		{"fail-on", "string"},
+		{"strict", "bool"},
+		{"tag", "stringArray"},
+		{"job", "stringArray"},
+		{"from-masscan", "string"},
+		{"targets-csv", "string"},
+		{"host-field", "string"},
+		{"tag-fields", "string"},
+		{"asn", "string"},
+		{"asn-data", "string"},
+		{"scan-window", "string"},
+		{"spread", "bool"},
 		{"dry-run", "bool"},
+		{"yes", "bool"},
+		{"estimate", "bool"},
 		{"verbose", "bool"},
 		{"only-open", "bool"},
 		{"ports", "string"},
 		{"profile", "string"},
 		{"protocol", "string"},
 		{"output", "string"},
+		{"output-file", "string"},
+		{"timeline-file", "string"},
+		{"record", "string"},
+		{"output-max-size", "string"},
+		{"output-keep", "int"},
+		{"compress", "string"},
+		{"elastic-url", "string"},
+		{"index", "string"},
+		{"elastic-batch-size", "int"},
+		{"syslog-addr", "string"},
+		{"syslog-format", "string"},
+		{"inventory-format", "string"},
+		{"brokers", "string"},
+		{"topic", "string"},
+		{"message-key", "string"},
+		{"partitioner", "string"},
+		{"upload", "string"},
+		{"upload-endpoint", "string"},
+		{"upload-region", "string"},
+		{"otel-endpoint", "string"},
 		{"rate", "int"},
 		{"timeout", "int"},
+		{"port-timeouts", "string"},
 		{"workers", "int"},
+		{"timing", "string"},
+		{"retries", "int"},
+		{"jitter", "int"},
+		{"host-parallelism", "int"},
+		{"max-bandwidth", "string"},
+		{"max-pps", "int"},
+		{"bandwidth-iface", "string"},
+		{"conntrack-limit", "int"},
+		{"dns-server", "string"},
+		{"dns-timeout", "int"},
+		{"all-ips", "bool"},
 		{"udp-worker-ratio", "float64"},
+		{"udp-probes", "string"},
+		{"snmp-communities", "string"},
 		{"ui.theme", "string"},
 	}
 
@@ -152,7 +222,7 @@ func TestBooleanFlagDefaults(t *testing.T) {
 
 	boolFlags := []string{
 		"stdin", "json", "json-array", "json-object",
-		"banners", "dry-run", "verbose", "only-open",
+		"banners", "dry-run", "verbose", "only-open", "yes",
 	}
 
 	for _, flagName := range boolFlags {
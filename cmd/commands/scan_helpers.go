@@ -120,6 +120,20 @@ func getOptimalWorkerCount() int {
 func showDryRun(targets []string, ports []uint16, cfg *config.Config) {
 	fmt.Println("=== DRY RUN MODE ===")
 	fmt.Printf("Targets:       %d\n", len(targets))
+	if cfg.FromMasscan != "" {
+		fmt.Printf("From masscan:  %s (listed open ports only)\n", cfg.FromMasscan)
+	}
+	if cfg.TargetsCSV != "" {
+		fmt.Printf("Targets CSV:   %s (host field %s", cfg.TargetsCSV, cfg.HostField)
+		if fields := cfg.GetTagFields(); len(fields) > 0 {
+			fmt.Printf(", tags %s", strings.Join(fields, ", "))
+		}
+		fmt.Println(")")
+	}
+	if cfg.ASN != "" {
+		asns, _ := cfg.GetASNs()
+		fmt.Printf("ASN:           %s (prefixes from %s)\n", asnSummary(asns), cfg.ASNData)
+	}
 	if len(targets) > 0 && len(targets) <= 5 {
 		fmt.Printf("Targets list: %v\n", targets)
 	}
@@ -130,10 +144,118 @@ func showDryRun(targets []string, ports []uint16, cfg *config.Config) {
 	fmt.Println()
 	fmt.Printf("Total sockets: %d\n", len(ports)*len(targets))
 	fmt.Printf("Workers:       %d\n", cfg.Workers)
+	if !cfg.AllowLocalhost || !cfg.AllowPrivate {
+		fmt.Printf("Allow:         localhost %v, private %v\n", cfg.AllowLocalhost, cfg.AllowPrivate)
+	}
+	if exclusion, _ := cfg.GetExclusion(); exclusion != nil {
+		fmt.Printf("Exclude:       %s\n", exclusionSummary(exclusion))
+	}
+	if cfg.RunAs != "" {
+		fmt.Printf("Run As:        %s\n", cfg.RunAs)
+	}
+	if cfg.Scope != "" {
+		fmt.Printf("Scope:         %s\n", scopeSummary(cfg))
+	}
 	fmt.Printf("Rate Limit:    %d pps\n", cfg.Rate)
+	if cfg.BandwidthGuarded() {
+		fmt.Printf("Bandwidth:     %s\n", bandwidthGuardSummary(cfg))
+	}
+	if summary := conntrackSummary(cfg); summary != "" {
+		fmt.Printf("Conntrack:     %s\n", summary)
+	}
 	fmt.Printf("Timeout:       %dms\n", cfg.TimeoutMs)
+	if cfg.Timing != "" {
+		fmt.Printf("Timing:        %s (retries %d, jitter %dms, per-host %d)\n",
+			cfg.Timing, scanRetries(cfg.Retries), cfg.JitterMs, cfg.PerHostLimit)
+	}
+	if cfg.PortTimeouts != "" {
+		fmt.Printf("Port Timeouts: %s\n", cfg.PortTimeouts)
+	}
+	showOverrides(os.Stdout, cfg, buildScanTargets(targets, ports))
+	if cfg.DNSServer != "" {
+		fmt.Printf("DNS Server:    %s\n", cfg.DNSServer)
+	}
+	if cfg.AllIPs {
+		fmt.Println("All IPs:       true")
+	}
+	if cfg.UDPProbes != "" {
+		fmt.Printf("UDP Probes:    %s\n", cfg.UDPProbes)
+	}
+	if communities := cfg.GetSNMPCommunities(); len(communities) > 0 {
+		fmt.Printf("SNMP:          communities %s on UDP 161\n", strings.Join(communities, ", "))
+	}
+	if tags := cfg.GetTags(); len(tags) > 0 {
+		fmt.Printf("Tags:          %s\n", strings.Join(tags, ", "))
+	}
 	fmt.Printf("Banner Grab:   %v\n", cfg.Banners)
+	if cfg.Banners {
+		fmt.Printf("Banner Pool:   %d workers, %dms timeout\n", cfg.BannerWorkers, cfg.BannerTimeout)
+	}
+	if cfg.VerifyOpen {
+		fmt.Println("Verify Open:   true")
+	}
+	if cfg.DetectEdge {
+		fmt.Println("Detect Edge:   true")
+	}
+	if cfg.LocalDiscovery {
+		fmt.Println("Discovery:     mDNS, NetBIOS, SSDP")
+	}
+	if cfg.CheckNTP {
+		fmt.Println("Check NTP:     mode 6/7 on UDP 123")
+	}
+	if cfg.TLSInspect {
+		fmt.Println("TLS Inspect:   TLS ports, STARTTLS on 21/25/110/143/389/587")
+	}
+	if cfg.HTTPAudit {
+		fmt.Println("HTTP Audit:    security headers on 80/443/8080/8443")
+	}
+	if cfg.OwnerLookups() {
+		fmt.Printf("Owner Lookup:  %s\n", ownerSummary(cfg))
+	}
+	if paths := cfg.GetGeoIPDBs(); len(paths) > 0 {
+		fmt.Printf("GeoIP:         %s\n", geoipSummary(paths))
+	}
+	if cfg.FailOn != "" {
+		fmt.Printf("Fail On:       %s\n", cfg.FailOn)
+	}
+	if cfg.Strict {
+		fmt.Println("Strict:        true")
+	}
+	if cfg.ScanWindow != "" {
+		fmt.Printf("Scan Window:   %s", cfg.ScanWindow)
+		if cfg.Spread {
+			fmt.Print(" (spread)")
+		}
+		fmt.Println()
+	}
 	fmt.Printf("Output Format: %s\n", cfg.Output)
+	if cfg.OutputFile != "" {
+		fmt.Printf("Output File:   %s\n", cfg.OutputFile)
+	}
+	if cfg.Record != "" {
+		fmt.Printf("Record To:     %s\n", cfg.Record)
+	}
+	if cfg.TimelineFile != "" {
+		fmt.Printf("Timeline File: %s\n", cfg.TimelineFile)
+	}
+	if cfg.Upload != "" {
+		fmt.Printf("Upload To:     %s\n", cfg.Upload)
+	}
+	if cfg.OTelEndpoint != "" {
+		fmt.Printf("OTel Endpoint: %s\n", cfg.OTelEndpoint)
+	}
+	if cfg.Output == "kafka" || cfg.Output == "nats" {
+		fmt.Printf("Publish To:    %s %s (key: %s)\n", cfg.Brokers, cfg.Topic, cfg.MessageKey)
+	}
+	if cfg.Output == "syslog" && cfg.SyslogAddr != "" {
+		fmt.Printf("Syslog:        %s (%s)\n", cfg.SyslogAddr, cfg.SyslogFormat)
+	}
+	if cfg.Output == "inventory" {
+		fmt.Printf("Inventory:     per host (%s)\n", cfg.InventoryFmt)
+	}
+	if cfg.Output == "elastic" {
+		fmt.Printf("Elasticsearch: %s (index %s)\n", redactURL(cfg.ElasticURL), cfg.ElasticIndex)
+	}
 	if cfg.Output == "" {
 		fmt.Print(" (TUI)")
 	}
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+)
+
+// errInterrupted is the cancel cause when SIGINT or SIGTERM stops a scan.
+var errInterrupted = stdErrors.New("interrupted before finishing; the output holds the results so far")
+
+// interrupted reports whether ctx was cancelled by SIGINT or SIGTERM.
+func interrupted(ctx context.Context) bool {
+	return stdErrors.Is(context.Cause(ctx), errInterrupted)
+}
+
+// outcomeListLimit caps how many hosts or ports an exit message lists.
+const outcomeListLimit = 5
+
+// scanOutcome tallies what a scan produced to pick its exit code: open
+// ports matched by --fail-on, and probes that failed without a result.
+type scanOutcome struct {
+	failOn    map[uint16]bool
+	failOnAny bool
+	strict    bool
+
+	mu          sync.Mutex
+	probeErrors int
+	results     map[string]int // results per host
+	failed      map[string]int // failed probes per host
+	violations  []string
+}
+
+func newScanOutcome(cfg *config.Config) *scanOutcome {
+	failOn, failOnAny := cfg.GetFailOnPorts()
+	return &scanOutcome{
+		failOn:    failOn,
+		failOnAny: failOnAny,
+		strict:    cfg.Strict,
+		results:   make(map[string]int),
+		failed:    make(map[string]int),
+	}
+}
+
+// observe records one scan event.
+func (o *scanOutcome) observe(event core.Event) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	switch event.Kind {
+	case core.EventKindResult:
+		r := event.Result
+		o.results[r.Host]++
+		if r.State == core.StateOpen && (o.failOnAny || o.failOn[r.Port]) {
+			o.violations = append(o.violations, net.JoinHostPort(r.Host, strconv.Itoa(int(r.Port)))+"/"+r.Protocol)
+		}
+	case core.EventKindError:
+		o.probeErrors++
+		var scanErr *core.ScanError
+		if stdErrors.As(event.Error, &scanErr) {
+			o.failed[scanErr.Host]++
+		}
+	}
+}
+
+// track records every event on its way to the output.
+func (o *scanOutcome) track(events <-chan core.Event) <-chan core.Event {
+	out := make(chan core.Event, cap(events))
+	go func() {
+		defer close(out)
+		for event := range events {
+			o.observe(event)
+			out <- event
+		}
+	}()
+	return out
+}
+
+// err returns the ExitError for a finished scan, or nil when it completed
+// cleanly. A policy violation outranks failed probes. Failed probes fail
+// the scan when a host produced no results at all, or with --strict.
+func (o *scanOutcome) err() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(o.violations) > 0 {
+		return &errors.ExitError{
+			Code: errors.ExitPolicy,
+			Err:  fmt.Errorf("--fail-on matched %d open ports: %s", len(o.violations), truncatedList(o.violations)),
+		}
+	}
+
+	var unscanned []string
+	for host := range o.failed {
+		if o.results[host] == 0 {
+			unscanned = append(unscanned, host)
+		}
+	}
+	sort.Strings(unscanned)
+	switch {
+	case len(unscanned) > 0:
+		return &errors.ExitError{
+			Code: errors.ExitPartial,
+			Err: fmt.Errorf("%d probes failed and %d hosts have no results: %s",
+				o.probeErrors, len(unscanned), truncatedList(unscanned)),
+		}
+	case o.strict && o.probeErrors > 0:
+		return &errors.ExitError{
+			Code: errors.ExitPartial,
+			Err:  fmt.Errorf("%d probes failed; results are incomplete (--strict)", o.probeErrors),
+		}
+	}
+	return nil
+}
+
+// truncatedList joins the first few items and counts the rest.
+func truncatedList(items []string) string {
+	if len(items) <= outcomeListLimit {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(items[:outcomeListLimit], ", "), len(items)-outcomeListLimit)
+}
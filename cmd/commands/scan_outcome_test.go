@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+)
+
+func outcomeResult(host string, port uint16, state core.ScanState) core.Event {
+	return core.NewResultEvent(core.ResultEvent{Host: host, Port: port, State: state, Protocol: "tcp"})
+}
+
+func outcomeError(host string, port uint16) core.Event {
+	return core.NewErrorEvent(&core.ScanError{Host: host, Port: port, Protocol: "tcp", Err: stdErrors.New("no such host")})
+}
+
+// TestScanOutcome verifies the exit code chosen for each kind of scan result
+func TestScanOutcome(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    config.Config
+		events []core.Event
+		want   int
+	}{
+		{
+			name:   "clean scan",
+			events: []core.Event{outcomeResult("10.0.0.1", 22, core.StateOpen), outcomeResult("10.0.0.1", 23, core.StateClosed)},
+			want:   errors.ExitOK,
+		},
+		{
+			name:   "fail-on matches an open port",
+			cfg:    config.Config{FailOn: "23,3389"},
+			events: []core.Event{outcomeResult("10.0.0.1", 22, core.StateOpen), outcomeResult("10.0.0.1", 3389, core.StateOpen)},
+			want:   errors.ExitPolicy,
+		},
+		{
+			name:   "fail-on ignores closed ports",
+			cfg:    config.Config{FailOn: "23"},
+			events: []core.Event{outcomeResult("10.0.0.1", 23, core.StateClosed)},
+			want:   errors.ExitOK,
+		},
+		{
+			name:   "fail-on any",
+			cfg:    config.Config{FailOn: "any"},
+			events: []core.Event{outcomeResult("10.0.0.1", 8080, core.StateOpen)},
+			want:   errors.ExitPolicy,
+		},
+		{
+			name:   "host with no results",
+			events: []core.Event{outcomeResult("10.0.0.1", 22, core.StateOpen), outcomeError("db.invalid", 22), outcomeError("db.invalid", 80)},
+			want:   errors.ExitPartial,
+		},
+		{
+			name:   "scattered probe errors are tolerated",
+			events: []core.Event{outcomeResult("10.0.0.1", 22, core.StateOpen), outcomeError("10.0.0.1", 80)},
+			want:   errors.ExitOK,
+		},
+		{
+			name:   "strict fails on any probe error",
+			cfg:    config.Config{Strict: true},
+			events: []core.Event{outcomeResult("10.0.0.1", 22, core.StateOpen), outcomeError("10.0.0.1", 80)},
+			want:   errors.ExitPartial,
+		},
+		{
+			name:   "policy outranks failed probes",
+			cfg:    config.Config{FailOn: "22", Strict: true},
+			events: []core.Event{outcomeResult("10.0.0.1", 22, core.StateOpen), outcomeError("10.0.0.1", 80)},
+			want:   errors.ExitPolicy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome := newScanOutcome(&tt.cfg)
+			for _, event := range tt.events {
+				outcome.observe(event)
+			}
+			if got := errors.ExitCode(outcome.err()); got != tt.want {
+				t.Errorf("exit code = %d, want %d (err: %v)", got, tt.want, outcome.err())
+			}
+		})
+	}
+}
+
+// TestScanOutcome_Message verifies long host lists are truncated
+func TestScanOutcome_Message(t *testing.T) {
+	outcome := newScanOutcome(&config.Config{})
+	for i := 1; i <= 7; i++ {
+		outcome.observe(outcomeError(fmt.Sprintf("host%d.invalid", i), 22))
+	}
+	msg := outcome.err().Error()
+	if !strings.Contains(msg, "7 hosts have no results") || !strings.Contains(msg, "and 2 more") {
+		t.Errorf("message = %q", msg)
+	}
+}
+
+// TestRunScanPlans_ExitCodes verifies interrupted and policy-violating
+// scans return their exit codes once output is written
+func TestRunScanPlans_ExitCodes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	port := uint16(ln.Addr().(*net.TCPAddr).Port)
+
+	run := func(ctx context.Context, cfg *config.Config) error {
+		cfg.Workers, cfg.TimeoutMs, cfg.Rate = 4, 500, 100
+		cfg.AllowLocalhost = true
+		cfg.OutputFile = filepath.Join(t.TempDir(), "scan.ndjson")
+		scanner, err := NewScannerFactory(cfg).CreateScanner("tcp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return runScanners(ctx, []core.PortScanner{scanner}, []string{"127.0.0.1"}, []uint16{port}, cfg)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := run(ctx, &config.Config{FailOn: fmt.Sprint(port)}); errors.ExitCode(err) != errors.ExitPolicy {
+		t.Errorf("fail-on: err = %v, want exit %d", err, errors.ExitPolicy)
+	}
+
+	interrupted, stop := context.WithCancelCause(context.Background())
+	stop(errInterrupted)
+	if err := run(interrupted, &config.Config{}); errors.ExitCode(err) != errors.ExitAborted {
+		t.Errorf("interrupted: err = %v, want exit %d", err, errors.ExitAborted)
+	}
+}
@@ -4,17 +4,29 @@ import (
 	"context"
 	stdErrors "errors"
 	"fmt"
+	"io"
+	"net"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/lucchesi-sec/portscan/internal/core"
 	"github.com/lucchesi-sec/portscan/internal/ui"
 	"github.com/lucchesi-sec/portscan/pkg/config"
 	"github.com/lucchesi-sec/portscan/pkg/errors"
 	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/lucchesi-sec/portscan/pkg/geoip"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/lucchesi-sec/portscan/pkg/probes"
+	"github.com/lucchesi-sec/portscan/pkg/replay"
+	"github.com/lucchesi-sec/portscan/pkg/services"
 	"github.com/lucchesi-sec/portscan/pkg/targets"
+	"github.com/lucchesi-sec/portscan/pkg/telemetry"
+	"github.com/lucchesi-sec/portscan/pkg/theme"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -30,27 +42,76 @@ func runScan(cmd *cobra.Command, args []string) error {
 		return errors.ConfigLoadError(viper.ConfigFileUsed(), err)
 	}
 
+	if err := cfg.ApplyTimingTemplate(flagsChanged(cmd, timingFlags)); err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_TIMING",
+			Message:    i18n.T("errors.invalid_timing.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_timing.suggestion"),
+		}
+	}
+
+	// --job and --tag are not bound to viper, which would split their
+	// values on commas.
+	if jobs, _ := cmd.Flags().GetStringArray("job"); len(jobs) > 0 {
+		cfg.Jobs = jobs
+	}
+	if tags, _ := cmd.Flags().GetStringArray("tag"); len(tags) > 0 {
+		cfg.Tags = tags
+	}
+
 	// Validate all user inputs before processing
 	if err := validateInputs(cfg); err != nil {
 		return err
 	}
 
 	ensureWorkersConfigured(cfg)
+	if err := services.OverrideError(); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v; using built-in service names\n", err)
+	}
 
 	if err := enforceRateSafety(cfg.Rate); err != nil {
 		return err
 	}
+	// The flags are valid; later errors concern targets or the scan itself,
+	// where the usage text would only bury the message.
+	cmd.SilenceUsage = true
+
+	if err := dropPrivileges(cfg.RunAs); err != nil {
+		return err
+	}
+
+	if cfg.FromMasscan != "" {
+		return runFromMasscan(args, cfg)
+	}
+	if len(cfg.Jobs) > 0 {
+		return runJobs(args, cfg)
+	}
 
 	rawTargets, err := collectTargetInputs(args)
 	if err != nil {
 		return err
 	}
+	if cfg.TargetsCSV != "" {
+		assetHosts, err := loadAssetTargets(cfg)
+		if err != nil {
+			return err
+		}
+		rawTargets = append(rawTargets, assetHosts...)
+	}
+	if cfg.ASN != "" {
+		asnTargets, err := loadASNTargets(cfg)
+		if err != nil {
+			return err
+		}
+		rawTargets = append(rawTargets, asnTargets...)
+	}
 	if len(rawTargets) == 0 {
 		return errors.NoTargetError()
 	}
 
 	// Validate each raw target before resolution
-	if err := validateRawTargets(rawTargets); err != nil {
+	if err := validateRawTargets(rawTargets, targetPolicy(cfg)); err != nil {
 		return err
 	}
 
@@ -64,13 +125,22 @@ func runScan(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if viper.GetBool("estimate") {
+		est := estimateScan(len(resolvedTargets), len(resolvedTargets)*len(ports), cfg)
+		showEstimate(os.Stdout, resolvedTargets, len(ports), est, cfg)
+		return nil
+	}
 	if viper.GetBool("dry_run") {
 		showDryRun(resolvedTargets, ports, cfg)
 		return nil
 	}
+	est := estimateScan(len(resolvedTargets), len(resolvedTargets)*len(ports), cfg)
+	if err := confirmScan(resolvedTargets, est, cfg); err != nil {
+		return err
+	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
 
 	cleanupInterrupts := monitorInterrupts(cancel)
 	defer cleanupInterrupts()
@@ -80,29 +150,319 @@ func runScan(cmd *cobra.Command, args []string) error {
 }
 
 func runProtocolScan(ctx context.Context, scanner core.PortScanner, hosts []string, ports []uint16, cfg *config.Config, _ string) error {
+	return runScanners(ctx, []core.PortScanner{scanner}, hosts, ports, cfg)
+}
+
+// runScanners runs every scanner concurrently against the same targets and
+// feeds their merged event stream to a single output, so a "both" scan
+// produces one TUI session or one export containing TCP and UDP results.
+func runScanners(ctx context.Context, scanners []core.PortScanner, hosts []string, ports []uint16, cfg *config.Config) error {
 	if len(hosts) == 0 {
 		return errors.NoTargetError()
 	}
 
 	scanTargets := buildScanTargets(hosts, ports)
-	events := scanner.Results()
-	go scanner.ScanTargets(ctx, scanTargets)
+	plans := make([]scanPlan, len(scanners))
+	for i, scanner := range scanners {
+		plans[i] = scanPlan{scanner: scanner, targets: scanTargets}
+	}
+	return runScanPlans(ctx, plans, cfg)
+}
+
+// scanPlan pairs a scanner with the targets it probes.
+type scanPlan struct {
+	scanner core.PortScanner
+	targets []core.ScanTarget
+}
 
-	totalPorts := len(ports) * len(hosts)
-	metadata := exporter.ScanMetadata{Targets: hosts, TotalPorts: totalPorts, Rate: cfg.Rate}
+// scanOutputFunc consumes a running scan's events, such as handleScanOutput.
+type scanOutputFunc func(ctx context.Context, cfg *config.Config, events <-chan core.Event, totalPorts int, metadata exporter.ScanMetadata, handle scanHandle) error
 
-	return handleScanOutput(ctx, cfg, events, totalPorts, metadata)
+// runScanPlans runs each plan's scanner concurrently against its own
+// targets and feeds the merged event stream to a single output.
+func runScanPlans(ctx context.Context, plans []scanPlan, cfg *config.Config) error {
+	return runScanPlansTo(ctx, plans, cfg, handleScanOutput)
 }
 
-func selectJSONExporter(meta exporter.ScanMetadata) *exporter.JSONExporter {
+// runScanPlansTo is runScanPlans with the events sent to output.
+func runScanPlansTo(ctx context.Context, plans []scanPlan, cfg *config.Config, output scanOutputFunc) error {
+	if resolver := scanResolver(cfg); cfg.AllIPs && resolver != nil {
+		for i := range plans {
+			plans[i].targets = expandAllIPs(ctx, resolver, plans[i].targets)
+		}
+	}
+
+	if err := excludeTargets(ctx, cfg, plans); err != nil {
+		return err
+	}
+	if err := enforceScope(cfg, plans); err != nil {
+		return err
+	}
+	if err := enforcePolicy(ctx, cfg, plans); err != nil {
+		return err
+	}
+
+	var hosts []string
+	seen := make(map[string]bool)
+	totalPorts := 0
+	for _, plan := range plans {
+		for _, t := range plan.targets {
+			totalPorts += len(t.Ports)
+			if !seen[t.Host] {
+				seen[t.Host] = true
+				hosts = append(hosts, t.Host)
+			}
+		}
+	}
+
+	// The recording is opened before any probe goes out, so a bad path
+	// fails the scan before it starts. Later setup errors discard it.
+	var rec *replay.Recorder
+	finishRecording := func(err error) error { return err }
+	if cfg.Record != "" {
+		var err error
+		if rec, finishRecording, err = openRecording(cfg.Record, recordingHeader(cfg, hosts, totalPorts)); err != nil {
+			return err
+		}
+	}
+
+	var tracing *telemetry.Recorder
+	if cfg.OTelEndpoint != "" {
+		var err error
+		if tracing, err = newTelemetryRecorder(cfg, plans); err != nil {
+			return finishRecording(err)
+		}
+	}
+
+	var geo *geoip.DB
+	if paths := cfg.GetGeoIPDBs(); len(paths) > 0 {
+		var err error
+		if geo, err = openGeoIP(paths); err != nil {
+			return finishRecording(err)
+		}
+	}
+
+	scanCtx, cancelScan := context.WithCancel(ctx)
+	defer cancelScan()
+
+	streams := make([]<-chan core.Event, len(plans))
+	var control pauseGroup
+	var rates rateGroup
+	var budget *core.RateBudget
+	for i, plan := range plans {
+		streams[i] = plan.scanner.Results()
+		if p, ok := plan.scanner.(core.Pausable); ok {
+			control = append(control, p)
+		}
+		if b, ok := plan.scanner.(core.RateBudgeted); ok && b.RateBudget() != nil {
+			budget = b.RateBudget()
+		}
+		if r, ok := plan.scanner.(core.RateAdjustable); ok && r.Rate() > 0 {
+			rates = append(rates, r)
+		}
+	}
+	// Scanners sharing a budget are paced by it, so rate changes go to the
+	// budget first; it also reports the group's rate.
+	if budget != nil {
+		rates = append(rateGroup{budget}, rates...)
+	}
+	// Close the gate before any probe goes out when starting outside the
+	// scan window.
+	if window := cfg.GetScanWindow(); window != nil && len(control) > 0 {
+		applyScanWindow(*window, control, time.Now())
+	}
+	if cfg.BandwidthGuarded() && len(control) > 0 {
+		guard, err := newBandwidthGuard(cfg, control)
+		if err != nil {
+			return finishRecording(err)
+		}
+		go guard.Run(scanCtx)
+	}
+	if len(control) > 0 {
+		if guard := newConntrackGuard(cfg, control); guard != nil {
+			go guard.Run(scanCtx)
+		}
+	}
+	for _, plan := range plans {
+		go plan.scanner.ScanTargets(scanCtx, plan.targets)
+	}
+	if resolver := scanResolver(cfg); resolver != nil {
+		go resolver.Prefetch(scanCtx, hosts)
+	}
+	events := core.MergeEvents(streams...)
+	if cfg.VerifyOpen {
+		verify := core.VerifyOptions{Timeout: cfg.GetTimeout()}
+		if resolver := scanResolver(cfg); resolver != nil {
+			verify.Resolver = resolver
+		}
+		events = core.VerifyOpen(scanCtx, events, verify)
+	}
+	if cfg.DetectEdge {
+		edge := core.EdgeOptions{Timeout: max(cfg.GetTimeout(), core.DefaultEdgeTimeout)}
+		if resolver := scanResolver(cfg); resolver != nil {
+			edge.Resolver = resolver
+		}
+		events = core.DetectEdge(scanCtx, events, edge)
+	}
+	if cfg.LocalDiscovery {
+		discovery := core.DiscoveryOptions{Timeout: max(cfg.GetTimeout(), core.DefaultDiscoveryTimeout)}
+		events = core.DiscoverLocal(scanCtx, events, discovery)
+	}
+	if cfg.CheckNTP {
+		ntp := core.NTPCheckOptions{Timeout: max(cfg.GetTimeout(), core.DefaultNTPCheckTimeout)}
+		if resolver := scanResolver(cfg); resolver != nil {
+			ntp.Resolver = resolver
+		}
+		events = core.CheckNTPExposure(scanCtx, events, ntp)
+	}
+	if cfg.TLSInspect {
+		inspect := core.TLSInspectOptions{Timeout: max(cfg.GetTimeout(), core.DefaultTLSInspectTimeout)}
+		if resolver := scanResolver(cfg); resolver != nil {
+			inspect.Resolver = resolver
+		}
+		events = core.InspectTLS(scanCtx, events, inspect)
+	}
+	if cfg.HTTPAudit {
+		audit := core.HTTPAuditOptions{Timeout: max(cfg.GetTimeout(), core.DefaultHTTPAuditTimeout)}
+		if resolver := scanResolver(cfg); resolver != nil {
+			audit.Resolver = resolver
+		}
+		events = core.AuditHTTP(scanCtx, events, audit)
+	}
+	if cfg.OwnerLookups() {
+		events = core.AttachOwnership(scanCtx, events, scanOwnership(cfg).lookup)
+	}
+	if geo != nil {
+		var resolver core.HostResolver
+		if r := scanResolver(cfg); r != nil {
+			resolver = r
+		}
+		events = locateResults(scanCtx, events, geo.Lookup, resolver)
+	}
+	tags := cfg.GetTags()
+	if len(tags) > 0 {
+		events = tagResults(events, tags)
+	}
+	if len(cfg.HostTags) > 0 {
+		events = tagHostResults(events, cfg.HostTags)
+	}
+	outcome := newScanOutcome(cfg)
+	events = outcome.track(events)
+	var timeline *core.Timeline
+	if cfg.TimelineFile != "" {
+		timeline = core.NewTimeline()
+		events = observeEvents(events, timeline)
+	}
+	if tracing != nil {
+		events = observeEvents(events, tracing)
+	}
+	if rec != nil {
+		events = observeEvents(events, rec)
+	}
+
+	metadata := exporter.ScanMetadata{Targets: hosts, TotalPorts: totalPorts, Rate: cfg.Rate, Tags: tags}
+
+	handle := scanHandle{cancel: cancelScan}
+	if len(control) > 0 {
+		handle.control = control
+	}
+	if len(rates) > 0 {
+		handle.rate = rates
+	}
+	if window := cfg.GetScanWindow(); window != nil && handle.control != nil {
+		announceScanWindow(*window, time.Now())
+		go enforceScanWindow(scanCtx, *window, handle.control)
+	}
+	exportStart := time.Now()
+	err := output(ctx, cfg, events, totalPorts, metadata, handle)
+	if cfg.OwnerLookups() {
+		saveOwnership(cfg)
+	}
+	if tracing != nil {
+		tracing.ObserveExport(telemetryOutputName(cfg), exportStart, err)
+		flushTelemetry(ctx, tracing)
+	}
+	if err := finishRecording(err); err != nil {
+		return err
+	}
+	if timeline != nil {
+		if err := writeTimelineFile(cfg.TimelineFile, timeline); err != nil {
+			return err
+		}
+	}
+	if interrupted(ctx) {
+		return &errors.ExitError{Code: errors.ExitAborted, Err: errInterrupted}
+	}
+	return outcome.err()
+}
+
+// scanHandle lets interactive output control the running scan.
+type scanHandle struct {
+	control core.Pausable       // nil when the scanner cannot pause
+	rate    core.RateAdjustable // nil when the scan's rate cannot change
+	cancel  context.CancelFunc  // stops the scan without exiting the TUI
+	offline bool                // replaying a recording; the TUI must not rescan hosts
+}
+
+// pauseGroup pauses and resumes scanners running side by side together.
+type pauseGroup []core.Pausable
+
+func (g pauseGroup) Pause() {
+	for _, p := range g {
+		p.Pause()
+	}
+}
+
+func (g pauseGroup) Resume() {
+	for _, p := range g {
+		p.Resume()
+	}
+}
+
+// Paused reports whether any scanner in the group is paused.
+func (g pauseGroup) Paused() bool {
+	for _, p := range g {
+		if p.Paused() {
+			return true
+		}
+	}
+	return false
+}
+
+// rateGroup changes the rates of scanners running side by side together.
+type rateGroup []core.RateAdjustable
+
+// SetRate sets every scanner's rate and returns the first one's.
+func (g rateGroup) SetRate(pps int) int {
+	applied := 0
+	for _, r := range g {
+		if rate := r.SetRate(pps); applied == 0 {
+			applied = rate
+		}
+	}
+	return applied
+}
+
+// Rate returns the first scanner's rate.
+func (g rateGroup) Rate() int {
+	if len(g) == 0 {
+		return 0
+	}
+	return g[0].Rate()
+}
+
+func selectJSONExporter(w io.Writer, meta exporter.ScanMetadata) *exporter.JSONExporter {
+	var exp *exporter.JSONExporter
 	switch {
 	case viper.GetBool("json_object"):
-		return exporter.NewJSONExporterObjectWithMetadata(os.Stdout, meta)
+		exp = exporter.NewJSONExporterObjectWithMetadata(w, meta)
 	case viper.GetBool("json_array"):
-		return exporter.NewJSONExporterArray(os.Stdout)
+		exp = exporter.NewJSONExporterArray(w)
 	default:
-		return exporter.NewJSONExporter(os.Stdout)
+		exp = exporter.NewJSONExporter(w)
 	}
+	exp.SetBannerEncoding(viper.GetString("banner_encoding"))
+	return exp
 }
 
 func streamEvents(ctx context.Context, events <-chan core.Event, export func(<-chan core.Event), closeFn func() error) error {
@@ -128,13 +488,71 @@ func streamEvents(ctx context.Context, events <-chan core.Event, export func(<-c
 }
 
 func ensureWorkersConfigured(cfg *config.Config) {
-	if cfg.Workers != 0 {
+	auto := cfg.Workers == 0
+	if auto {
+		cfg.Workers = getOptimalWorkerCount()
+		if viper.GetBool("verbose") {
+			fmt.Printf("Auto-detected optimal workers: %d (based on %d CPU cores)\n", cfg.Workers, runtime.NumCPU())
+		}
+	}
+
+	soft, _, ok := core.FileLimit()
+	if !ok {
 		return
 	}
-	cfg.Workers = getOptimalWorkerCount()
-	if viper.GetBool("verbose") {
-		fmt.Printf("Auto-detected optimal workers: %d (based on %d CPU cores)\n", cfg.Workers, runtime.NumCPU())
+	if cfg.RaiseFDLimit {
+		want := uint64(cfg.Workers + fdReservedSockets(cfg) + core.FDReserve)
+		raised, err := core.RaiseFileLimit(want)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not raise the open file limit: %v\n", err)
+		} else {
+			soft = raised
+		}
 	}
+	capWorkersToFileLimit(cfg, soft, auto, os.Stderr)
+}
+
+// fdReservedSockets counts the sockets a scan holds beyond one per
+// worker: open connections waiting in the banner pool, and the UDP
+// workers that run beside the TCP ones when both protocols are scanned.
+func fdReservedSockets(cfg *config.Config) int {
+	reserved := 0
+	if cfg.Banners {
+		reserved = core.DefaultBannerWorkers
+		if cfg.BannerWorkers > 0 {
+			reserved = cfg.BannerWorkers
+		}
+	}
+	if normalizeProtocol(cfg.Protocol) == "both" {
+		reserved += udpWorkerCount(cfg)
+	}
+	return reserved
+}
+
+// udpWorkerCount mirrors how the UDP scanner sizes its pool from the
+// worker count and --udp-worker-ratio.
+func udpWorkerCount(cfg *config.Config) int {
+	ratio := cfg.UDPWorkerRatio
+	if ratio <= 0 {
+		ratio = core.DefaultUDPWorkerRatio
+	}
+	return max(int(float64(cfg.Workers)*ratio), 1)
+}
+
+// capWorkersToFileLimit lowers cfg.Workers so the scan fits within the soft
+// open file limit, instead of failing with "too many open files" once every
+// worker holds a socket. Lowering a worker count the user chose is reported
+// on w; an auto-detected one is only reported with --verbose.
+func capWorkersToFileLimit(cfg *config.Config, soft uint64, auto bool, w io.Writer) {
+	workers := core.WorkersForFileLimit(cfg.Workers, fdReservedSockets(cfg), soft)
+	if workers == cfg.Workers {
+		return
+	}
+	if !auto || viper.GetBool("verbose") {
+		fmt.Fprintf(w, "warning: %d workers exceed the open file limit of %d; using %d (raise it with ulimit -n or --raise-fd-limit)\n",
+			cfg.Workers, soft, workers)
+	}
+	cfg.Workers = workers
 }
 
 func enforceRateSafety(rate int) error {
@@ -144,7 +562,9 @@ func enforceRateSafety(rate int) error {
 	return nil
 }
 
-func monitorInterrupts(cancel context.CancelFunc) func() {
+// monitorInterrupts cancels the scan with errInterrupted on SIGINT or
+// SIGTERM, so the command can exit with ExitAborted once output is flushed.
+func monitorInterrupts(cancel context.CancelCauseFunc) func() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 	stop := make(chan struct{})
@@ -155,7 +575,7 @@ func monitorInterrupts(cancel context.CancelFunc) func() {
 			case <-stop:
 				return
 			case <-sigChan:
-				cancel()
+				cancel(errInterrupted)
 			}
 		}
 	}()
@@ -167,14 +587,79 @@ func monitorInterrupts(cancel context.CancelFunc) func() {
 }
 
 func buildScannerConfig(cfg *config.Config) *core.Config {
-	return &core.Config{
+	scannerCfg := &core.Config{
 		Workers:        cfg.Workers,
 		Timeout:        cfg.GetTimeout(),
+		PortTimeouts:   cfg.GetPortTimeouts(),
 		RateLimit:      cfg.Rate,
 		BannerGrab:     cfg.Banners,
-		MaxRetries:     2,
+		BannerMaxBytes: cfg.BannerMaxBytes,
+		BannerTimeout:  time.Duration(cfg.BannerTimeout) * time.Millisecond,
+		BannerWorkers:  cfg.BannerWorkers,
+		MaxRetries:     scanRetries(cfg.Retries),
 		UDPWorkerRatio: cfg.UDPWorkerRatio,
+		// The probe jitter also bounds the UDP scanner's built-in jitter.
+		UDPJitterMaxMs:     cfg.JitterMs,
+		ProbeJitter:        time.Duration(cfg.JitterMs) * time.Millisecond,
+		MaxHostParallelism: cfg.PerHostLimit,
+		UDPProbes:          scanUDPProbes(cfg),
+		SNMPCommunities:    cfg.GetSNMPCommunities(),
+	}
+	if resolver := scanResolver(cfg); resolver != nil {
+		scannerCfg.Resolver = resolver
+	}
+	return scannerCfg
+}
+
+var (
+	resolversMu sync.Mutex
+	resolvers   = make(map[targets.ResolverOptions]*targets.Resolver)
+)
+
+// scanResolver returns the resolver for the configured DNS server and
+// timeout, shared by every scanner in the process so each hostname is
+// looked up once. It returns nil if the server is invalid, which
+// validateInputs reports first.
+func scanResolver(cfg *config.Config) *targets.Resolver {
+	opts := targets.ResolverOptions{Server: cfg.DNSServer, Timeout: cfg.GetDNSTimeout()}
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+	if resolver, ok := resolvers[opts]; ok {
+		return resolver
+	}
+	resolver, err := targets.NewResolver(opts)
+	if err != nil {
+		return nil
 	}
+	resolvers[opts] = resolver
+	return resolver
+}
+
+// timingFlags maps the config keys a timing template sets to their flags.
+var timingFlags = map[string]string{
+	"rate":             "rate",
+	"timeout_ms":       "timeout",
+	"retries":          "retries",
+	"jitter_ms":        "jitter",
+	"host_parallelism": "host-parallelism",
+}
+
+// flagsChanged reports whether the flag for a config key was set on the
+// command line, so explicit flags win over a timing template.
+func flagsChanged(cmd *cobra.Command, flags map[string]string) func(key string) bool {
+	return func(key string) bool {
+		name, ok := flags[key]
+		return ok && cmd.Flags().Changed(name)
+	}
+}
+
+// scanRetries maps the configured retry count to the scanner's, treating
+// zero as "use the default".
+func scanRetries(retries int) int {
+	if retries <= 0 {
+		return core.DefaultMaxRetries
+	}
+	return retries
 }
 
 // normalizeProtocol ensures the protocol string is valid and defaults to "tcp".
@@ -194,8 +679,65 @@ func buildScanTargets(hosts []string, ports []uint16) []core.ScanTarget {
 	return scanTargets
 }
 
+// expandAllIPs replaces each hostname target with one target per address
+// it resolves to, named after the hostname, so --all-ips scans every A and
+// AAAA record. An address listed more than once is scanned once, under the
+// first name. A hostname that fails to resolve is kept so its probes report
+// the failure.
+func expandAllIPs(ctx context.Context, resolver *targets.Resolver, scanTargets []core.ScanTarget) []core.ScanTarget {
+	hosts := make([]string, len(scanTargets))
+	for i, t := range scanTargets {
+		hosts[i] = t.Host
+	}
+	resolver.Prefetch(ctx, hosts)
+
+	expanded := make([]core.ScanTarget, 0, len(scanTargets))
+	seen := make(map[string]bool, len(scanTargets))
+	add := func(t core.ScanTarget) {
+		if !seen[t.Host] {
+			seen[t.Host] = true
+			expanded = append(expanded, t)
+		}
+	}
+	for _, t := range scanTargets {
+		if net.ParseIP(t.Host) != nil || t.Hostname != "" {
+			add(t)
+			continue
+		}
+		addrs, err := resolver.LookupHost(ctx, t.Host)
+		if err != nil {
+			add(t)
+			continue
+		}
+		for _, addr := range addrs {
+			add(core.ScanTarget{Host: addr, Ports: t.Ports, Hostname: t.Host})
+		}
+	}
+	return expanded
+}
+
+// tagResults attaches the --tag labels to every result passing through.
+func tagResults(events <-chan core.Event, tags []string) <-chan core.Event {
+	out := make(chan core.Event, cap(events))
+	go func() {
+		defer close(out)
+		for event := range events {
+			if event.Kind == core.EventKindResult && event.Result != nil {
+				event.Result.Tags = append(event.Result.Tags, tags...)
+			}
+			out <- event
+		}
+	}()
+	return out
+}
+
 // executeScan executes the scan based on the protocol (tcp, udp, or both).
+// With --spread the rate is lowered so the scan fills its window.
 func executeScan(ctx context.Context, protocol string, hosts []string, ports []uint16, cfg *config.Config) error {
+	cfg = withSpreadRate(cfg, len(hosts)*len(ports))
+	if len(cfg.Overrides) > 0 {
+		return executeOverrideScan(ctx, protocol, hosts, ports, cfg)
+	}
 	factory := NewScannerFactory(cfg)
 
 	switch protocol {
@@ -207,19 +749,22 @@ func executeScan(ctx context.Context, protocol string, hosts []string, ports []u
 		return runProtocolScan(ctx, scanner, hosts, ports, cfg, "udp")
 
 	case "both":
+		// The TCP and UDP scanners draw from one budget so that together
+		// they stay within --rate.
+		budget := core.NewRateBudget(cfg.Rate)
+		if budget != nil {
+			defer budget.Stop()
+		}
+		factory.WithRateBudget(budget)
 		tcpScanner, err := factory.CreateScanner("tcp")
 		if err != nil {
 			return err
 		}
-		if err := runProtocolScan(ctx, tcpScanner, hosts, ports, cfg, "tcp"); err != nil {
-			return err
-		}
-
 		udpScanner, err := factory.CreateScanner("udp")
 		if err != nil {
 			return err
 		}
-		return runProtocolScan(ctx, udpScanner, hosts, ports, cfg, "udp")
+		return runScanners(ctx, []core.PortScanner{tcpScanner, udpScanner}, hosts, ports, cfg)
 
 	default:
 		scanner, err := factory.CreateScanner("tcp")
@@ -230,19 +775,185 @@ func executeScan(ctx context.Context, protocol string, hosts []string, ports []u
 	}
 }
 
-// handleScanOutput routes scan results to the appropriate output handler (TUI, JSON, CSV).
-func handleScanOutput(ctx context.Context, cfg *config.Config, events <-chan core.Event, totalPorts int, metadata exporter.ScanMetadata) error {
-	switch {
-	case viper.GetBool("json") || cfg.Output == "json":
-		exporter := selectJSONExporter(metadata)
-		return streamEvents(ctx, events, exporter.Export, exporter.Close)
-	case cfg.Output == "csv":
-		exporter := exporter.NewCSVExporter(os.Stdout)
-		return streamEvents(ctx, events, exporter.Export, exporter.Close)
+// withSpreadRate returns cfg with the rate lowered so that probes fill the
+// rest of the scan window when --spread is set.
+func withSpreadRate(cfg *config.Config, probes int) *config.Config {
+	window := cfg.GetScanWindow()
+	if window == nil || !cfg.Spread {
+		return cfg
+	}
+	spread := *cfg
+	spread.Rate = spreadRate(*window, time.Now(), probes, cfg.Rate)
+	return &spread
+}
+
+// handleScanOutput routes scan results to the appropriate output handler (TUI, JSON, CSV, table).
+// The scan handle lets the TUI pause, resume, and cancel the scanner.
+func handleScanOutput(ctx context.Context, cfg *config.Config, events <-chan core.Event, totalPorts int, metadata exporter.ScanMetadata, handle scanHandle) error {
+	format := outputFormat(cfg)
+	if format == "elastic" {
+		exp, err := newElasticExporter(cfg)
+		if err != nil {
+			return err
+		}
+		return streamEvents(ctx, withProgress(events), exp.Export, exp.Close)
+	}
+	if format == "kafka" || format == "nats" {
+		exp, err := newPublishExporter(cfg)
+		if err != nil {
+			return err
+		}
+		return streamEvents(ctx, withProgress(events), exp.Export, exp.Close)
+	}
+	if format == "syslog" && cfg.SyslogAddr != "" {
+		exp, finish, err := openSyslogOutput(cfg)
+		if err != nil {
+			return err
+		}
+		return finish(streamEvents(ctx, withProgress(events), exp.Export, exp.Close))
+	}
+	if format != "" {
+		out, finish, err := openScanOutput(cfg)
+		if err != nil {
+			return err
+		}
+		exp := newStreamExporter(ctx, cfg, format, out, metadata)
+		return finish(streamEvents(ctx, withProgress(events), exp.Export, exp.Close))
+	}
+
+	if cfg.UI.ScreenReader {
+		exp := exporter.NewAnnounceExporter(os.Stdout)
+		return streamEvents(ctx, events, exp.Export, exp.Close)
+	}
+
+	if cfg.UI.ASCII || theme.LegacyConsole() {
+		cfg.UI.ASCII = true
+		i18n.SetTransform(theme.ASCIIGlyphs.Text)
+	}
+
+	onlyOpen := viper.GetBool("only_open")
+	tui := ui.NewScanUI(cfg, totalPorts, events, onlyOpen)
+	if !handle.offline {
+		tui.SetRescanFunc(newRescanFunc(ctx, cfg))
+	}
+	if handle.control != nil {
+		tui.SetScanControl(handle.control)
+	}
+	if handle.rate != nil {
+		tui.SetRateControl(handle.rate)
+	}
+	if handle.cancel != nil {
+		tui.SetCancelFunc(handle.cancel)
+	}
+	tui.SetSettingsSaver(saveUISetting)
+	if cfg.RecordsSession() {
+		return runRecordedTUI(tui, cfg.RecordSession)
+	}
+	return tui.Run()
+}
+
+// runRecordedTUI runs the TUI recording the session to path in asciinema
+// format. The file is committed when the TUI exits cleanly and discarded
+// otherwise.
+func runRecordedTUI(tui *ui.ScanUI, path string) error {
+	file, err := exporter.CreateFile(path, exporter.FileOptions{})
+	if err != nil {
+		return err
+	}
+	tui.RecordSession(file)
+	if err := tui.Run(); err != nil {
+		file.Abort()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if !viper.GetBool("quiet") {
+		fmt.Fprintf(os.Stderr, "Session recorded to %s\n", path)
+	}
+	return nil
+}
+
+// saveUISetting writes a preference changed in the TUI to the config file
+// in use, or to ~/.portscan.yaml when none was found.
+func saveUISetting(key string, value interface{}) error {
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(home, ".portscan.yaml")
+	}
+	return config.SaveSetting(path, key, value)
+}
+
+// newStreamExporter returns the exporter for a file-like format writing
+// to out: json, csv, html, syslog, the per-host inventory, or the plain
+// table. A JSON document finalized after ctx is interrupted is marked as
+// aborted.
+func newStreamExporter(ctx context.Context, cfg *config.Config, format string, out io.Writer, metadata exporter.ScanMetadata) exporter.Exporter {
+	switch format {
+	case "json":
+		exp := selectJSONExporter(out, metadata)
+		exp.SetAbortCheck(func() bool { return interrupted(ctx) })
+		return exp
+	case "csv":
+		return exporter.NewCSVExporter(out)
+	case "html":
+		return exporter.NewHTMLExporter(out)
+	case "syslog":
+		return exporter.NewSyslogExporter(out, exporter.SyslogOptions{Format: cfg.SyslogFormat})
+	case "inventory":
+		return exporter.NewInventoryExporter(out, cfg.InventoryFmt)
 	default:
-		onlyOpen := viper.GetBool("only_open")
-		tui := ui.NewScanUI(cfg, totalPorts, events, onlyOpen)
-		return tui.Run()
+		opts := tableOptions()
+		opts.Color = opts.Color && cfg.OutputFile == "" && cfg.Upload == ""
+		return exporter.NewTableExporter(out, opts)
+	}
+}
+
+// tableOptions builds the plain table settings from flags: color follows
+// --no-color/NO_COLOR and --only-open filters rows.
+func tableOptions() exporter.TableOptions {
+	return exporter.TableOptions{
+		Color:    colorEnabled(),
+		OnlyOpen: viper.GetBool("only_open"),
+	}
+}
+
+// newRescanFunc returns the TUI hook that re-probes selected ports with
+// banner grabbing enabled, using a fresh scanner per request.
+func newRescanFunc(parent context.Context, cfg *config.Config) ui.RescanFunc {
+	return func(ctx context.Context, protocol string, scanTargets []core.ScanTarget, timeout time.Duration) ([]core.ResultEvent, error) {
+		rescanCfg := *cfg
+		rescanCfg.Banners = true
+		if timeout > 0 {
+			rescanCfg.TimeoutMs = int(timeout.Milliseconds())
+		}
+
+		scanner, err := NewScannerFactory(&rescanCfg).CreateScanner(protocol)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stop := context.AfterFunc(parent, cancel)
+		defer stop()
+
+		go scanner.ScanTargets(ctx, scanTargets)
+
+		var results []core.ResultEvent
+		for event := range scanner.Results() {
+			switch event.Kind {
+			case core.EventKindResult:
+				results = append(results, *event.Result)
+			case core.EventKindError:
+				return results, event.Error
+			}
+		}
+		return results, ctx.Err()
 	}
 }
 
@@ -257,9 +968,9 @@ func validateInputs(cfg *config.Config) error {
 	if err := targets.ValidateRateLimit(cfg.Rate); err != nil {
 		return &errors.UserError{
 			Code:       "INVALID_RATE",
-			Message:    "Invalid rate limit",
+			Message:    i18n.T("errors.invalid_rate.message"),
 			Details:    err.Error(),
-			Suggestion: fmt.Sprintf("Use a rate between 1 and %d pps. Start with 5000-10000 for normal scans.", core.MaxSafeRateLimit),
+			Suggestion: i18n.T("errors.invalid_rate.suggestion", core.MaxSafeRateLimit),
 		}
 	}
 
@@ -267,9 +978,182 @@ func validateInputs(cfg *config.Config) error {
 	if err := targets.ValidateTimeout(cfg.TimeoutMs); err != nil {
 		return &errors.UserError{
 			Code:       "INVALID_TIMEOUT",
-			Message:    "Invalid timeout value",
+			Message:    i18n.T("errors.invalid_timeout.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_timeout.suggestion"),
+		}
+	}
+
+	// Validate per-port timeout overrides
+	if err := cfg.ValidatePortTimeouts(); err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_PORT_TIMEOUTS",
+			Message:    i18n.T("errors.invalid_port_timeouts.message"),
 			Details:    err.Error(),
-			Suggestion: "Use a timeout between 1ms and 60000ms. Default is 200ms.",
+			Suggestion: i18n.T("errors.invalid_port_timeouts.suggestion"),
+		}
+	}
+
+	// Validate output file rotation
+	if err := cfg.ValidateOutputMaxSize(); err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_OUTPUT_ROTATION",
+			Message:    i18n.T("errors.invalid_output_rotation.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_output_rotation.suggestion"),
+		}
+	}
+	if cfg.OutputMaxSize != "" && !rotatableOutput(cfg) {
+		return &errors.UserError{
+			Code:       "INVALID_OUTPUT_ROTATION",
+			Message:    i18n.T("errors.output_rotation_format.message"),
+			Details:    i18n.T("errors.output_rotation_format.details"),
+			Suggestion: i18n.T("errors.output_rotation_format.suggestion"),
+		}
+	}
+
+	// A session recording needs the TUI to record
+	if cfg.RecordsSession() && (outputFormat(cfg) != "" || len(cfg.Jobs) > 0 || cfg.UI.ScreenReader) {
+		return &errors.UserError{
+			Code:       "INVALID_RECORD",
+			Message:    i18n.T("errors.record_session_output.message"),
+			Details:    i18n.T("errors.record_session_output.details"),
+			Suggestion: i18n.T("errors.record_session_output.suggestion"),
+		}
+	}
+
+	// Validate scan window and spreading
+	if err := cfg.ValidateScanWindow(); err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_SCAN_WINDOW",
+			Message:    i18n.T("errors.invalid_scan_window.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_scan_window.suggestion"),
+		}
+	}
+
+	// Validate Elasticsearch output
+	if err := cfg.ValidateElastic(); err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_ELASTIC",
+			Message:    i18n.T("errors.invalid_elastic.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_elastic.suggestion"),
+		}
+	}
+
+	// Validate syslog output
+	if err := cfg.ValidateSyslog(); err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_SYSLOG",
+			Message:    i18n.T("errors.invalid_syslog.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_syslog.suggestion"),
+		}
+	}
+
+	// Validate Kafka/NATS output
+	if err := cfg.ValidatePublish(); err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_PUBLISH",
+			Message:    i18n.T("errors.invalid_publish.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_publish.suggestion"),
+		}
+	}
+
+	// Validate --fail-on policy
+	if err := cfg.ValidateFailOn(); err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_FAIL_ON",
+			Message:    i18n.T("errors.invalid_fail_on.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_fail_on.suggestion"),
+		}
+	}
+
+	// Validate the outbound bandwidth guard
+	if err := cfg.ValidateBandwidthGuard(); err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_BANDWIDTH_GUARD",
+			Message:    i18n.T("errors.invalid_bandwidth_guard.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_bandwidth_guard.suggestion"),
+		}
+	}
+
+	// Validate target exclusion patterns
+	if err := cfg.ValidateExclusion(); err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_EXCLUDE",
+			Message:    i18n.T("errors.invalid_exclude.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_exclude.suggestion"),
+		}
+	}
+
+	// Validate the asset inventory options
+	if err := cfg.ValidateTargetsCSV(); err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_TARGETS_CSV",
+			Message:    i18n.T("errors.invalid_targets_csv.message", cfg.TargetsCSV),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_targets_csv.suggestion"),
+		}
+	}
+
+	// Validate the ASN target options
+	if err := cfg.ValidateASN(); err != nil {
+		return asnError(err)
+	}
+
+	// Validate scan jobs
+	if err := cfg.ValidateJobs(); err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_JOB",
+			Message:    i18n.T("errors.invalid_job.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_job.suggestion"),
+		}
+	}
+
+	// Validate per-target overrides
+	if err := cfg.ValidateOverrides(); err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_OVERRIDE",
+			Message:    i18n.T("errors.invalid_override.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_override.suggestion"),
+		}
+	}
+
+	// Validate DNS resolver
+	if err := cfg.ValidateDNSServer(); err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_DNS_SERVER",
+			Message:    i18n.T("errors.invalid_dns_server.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_dns_server.suggestion"),
+		}
+	}
+
+	// Validate scan tags
+	if err := cfg.ValidateTags(); err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_TAG",
+			Message:    i18n.T("errors.invalid_tag.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_tag.suggestion"),
+		}
+	}
+
+	// Validate report upload
+	if err := cfg.ValidateUpload(); err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_UPLOAD",
+			Message:    i18n.T("errors.invalid_upload.message"),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_upload.suggestion"),
 		}
 	}
 
@@ -277,9 +1161,9 @@ func validateInputs(cfg *config.Config) error {
 	if err := targets.ValidateWorkers(cfg.Workers); err != nil {
 		return &errors.UserError{
 			Code:       "INVALID_WORKERS",
-			Message:    "Invalid worker count",
+			Message:    i18n.T("errors.invalid_workers.message"),
 			Details:    err.Error(),
-			Suggestion: "Use 0 for auto-detect, or 1-1000 workers. Default auto-detection works well.",
+			Suggestion: i18n.T("errors.invalid_workers.suggestion"),
 		}
 	}
 
@@ -287,21 +1171,42 @@ func validateInputs(cfg *config.Config) error {
 	if err := targets.ValidateUDPWorkerRatio(cfg.UDPWorkerRatio); err != nil {
 		return &errors.UserError{
 			Code:       "INVALID_UDP_RATIO",
-			Message:    "Invalid UDP worker ratio",
+			Message:    i18n.T("errors.invalid_udp_ratio.message"),
 			Details:    err.Error(),
-			Suggestion: "Use a ratio between 0.0 and 1.0. Default is 0.5 (half workers for UDP).",
+			Suggestion: i18n.T("errors.invalid_udp_ratio.suggestion"),
+		}
+	}
+
+	if cfg.UDPProbes != "" {
+		if _, err := probes.Load(cfg.UDPProbes); err != nil {
+			return &errors.UserError{
+				Code:       "INVALID_UDP_PROBES",
+				Message:    i18n.T("errors.invalid_udp_probes.message"),
+				Details:    err.Error(),
+				Suggestion: i18n.T("errors.invalid_udp_probes.suggestion"),
+			}
 		}
 	}
 
 	return nil
 }
 
-// validateRawTargets validates each target before resolution.
-func validateRawTargets(rawTargets []string) error {
+// validateRawTargets validates each target before resolution and checks
+// it against the localhost and private address policy.
+func validateRawTargets(rawTargets []string, policy targets.Policy) error {
 	for _, target := range rawTargets {
 		if err := targets.ValidateHost(target); err != nil {
 			return errors.InvalidTargetError(target, err)
 		}
+		if err := targets.ValidatePolicy(target, policy); err != nil {
+			return errors.ScanPolicyError(target, err)
+		}
 	}
 	return nil
 }
+
+// targetPolicy returns the address policy set by --allow-localhost and
+// --allow-private.
+func targetPolicy(cfg *config.Config) targets.Policy {
+	return targets.Policy{AllowLocalhost: cfg.AllowLocalhost, AllowPrivate: cfg.AllowPrivate}
+}
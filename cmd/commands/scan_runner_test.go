@@ -3,13 +3,22 @@ package commands
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	stdErrors "errors"
 	"io"
+	"net"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/lucchesi-sec/portscan/internal/core"
 	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
 	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/lucchesi-sec/portscan/pkg/targets"
+	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
@@ -106,6 +115,44 @@ func TestEnsureWorkersConfigured(t *testing.T) {
 	}
 }
 
+func TestCapWorkersToFileLimit(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	cfg := &config.Config{Workers: 500, Banners: true, BannerWorkers: 36}
+	var out bytes.Buffer
+	capWorkersToFileLimit(cfg, 400, false, &out)
+	if want := 400 - core.FDReserve - 36; cfg.Workers != want {
+		t.Errorf("Workers = %d, want %d", cfg.Workers, want)
+	}
+	if !strings.Contains(out.String(), "open file limit of 400") {
+		t.Errorf("expected a warning for an explicit worker count, got %q", out.String())
+	}
+
+	cfg = &config.Config{Workers: 500}
+	out.Reset()
+	capWorkersToFileLimit(cfg, 256, true, &out)
+	if cfg.Workers != 256-core.FDReserve {
+		t.Errorf("Workers = %d, want %d", cfg.Workers, 256-core.FDReserve)
+	}
+	if out.Len() != 0 {
+		t.Errorf("auto-detected workers should be capped quietly, got %q", out.String())
+	}
+
+	// UDP workers run beside the TCP ones and hold sockets of their own.
+	cfg = &config.Config{Workers: 500, Protocol: "both", UDPWorkerRatio: 0.5}
+	capWorkersToFileLimit(cfg, 400, true, &out)
+	if want := 400 - core.FDReserve - 250; cfg.Workers != want {
+		t.Errorf("Workers = %d, want %d with UDP workers reserved", cfg.Workers, want)
+	}
+
+	cfg = &config.Config{Workers: 100}
+	capWorkersToFileLimit(cfg, 1024, false, &out)
+	if cfg.Workers != 100 {
+		t.Errorf("Workers = %d, want 100 under a roomy limit", cfg.Workers)
+	}
+}
+
 func TestEnforceRateSafety_EdgeCases(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -140,6 +187,7 @@ func TestBuildScannerConfig(t *testing.T) {
 		TimeoutMs:      250,
 		Rate:           5000,
 		Banners:        true,
+		BannerWorkers:  20,
 		UDPWorkerRatio: 0.6,
 	}
 
@@ -161,6 +209,10 @@ func TestBuildScannerConfig(t *testing.T) {
 		t.Error("BannerGrab should be true")
 	}
 
+	if scannerCfg.BannerWorkers != 20 {
+		t.Errorf("BannerWorkers = %d; want 20", scannerCfg.BannerWorkers)
+	}
+
 	if scannerCfg.MaxRetries != 2 {
 		t.Errorf("MaxRetries = %d; want 2", scannerCfg.MaxRetries)
 	}
@@ -221,7 +273,7 @@ func TestSelectJSONExporter(t *testing.T) {
 			tt.setFlags()
 			defer viper.Reset() // Clean up after each test
 
-			exporter := selectJSONExporter(metadata)
+			exporter := selectJSONExporter(os.Stdout, metadata)
 			if exporter == nil {
 				t.Fatal("exporter should not be nil")
 			}
@@ -397,8 +449,8 @@ func TestMonitorInterrupts(t *testing.T) {
 	// Test that monitorInterrupts sets up signal handling
 	// We can't easily test the actual signal handling without sending real signals,
 	// but we can verify the function doesn't panic and creates the channel
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
 
 	// This should not panic
 	cleanup := monitorInterrupts(cancel)
@@ -410,7 +462,7 @@ func TestMonitorInterrupts(t *testing.T) {
 	// without more complex setup, but we've verified it doesn't crash
 
 	// Verify context can still be cancelled manually
-	cancel()
+	cancel(nil)
 	select {
 	case <-ctx.Done():
 		// Context was cancelled successfully
@@ -513,6 +565,51 @@ func TestValidateInputs_InvalidPorts(t *testing.T) {
 	}
 }
 
+func TestBuildScannerConfig_BannerLimits(t *testing.T) {
+	cfg := &config.Config{TimeoutMs: 200, Banners: true, BannerMaxBytes: 2048, BannerTimeout: 2500}
+
+	scannerCfg := buildScannerConfig(cfg)
+
+	if scannerCfg.BannerMaxBytes != 2048 {
+		t.Errorf("BannerMaxBytes = %d; want 2048", scannerCfg.BannerMaxBytes)
+	}
+	if scannerCfg.BannerTimeout != 2500*time.Millisecond {
+		t.Errorf("BannerTimeout = %v; want 2.5s", scannerCfg.BannerTimeout)
+	}
+}
+
+func TestValidateInputs_InvalidPortTimeouts(t *testing.T) {
+	cfg := &config.Config{
+		Ports:          "80,443",
+		Rate:           5000,
+		TimeoutMs:      200,
+		PortTimeouts:   "443=slow",
+		Workers:        50,
+		UDPWorkerRatio: 0.5,
+	}
+
+	err := validateInputs(cfg)
+	if err == nil || !strings.Contains(err.Error(), "per-port timeouts") {
+		t.Errorf("expected per-port timeout error, got %v", err)
+	}
+}
+
+func TestBuildScannerConfig_PortTimeouts(t *testing.T) {
+	cfg := &config.Config{TimeoutMs: 200, PortTimeouts: "443=1000,3306=500"}
+
+	scannerCfg := buildScannerConfig(cfg)
+
+	if got := scannerCfg.PortTimeouts[443]; got != time.Second {
+		t.Errorf("PortTimeouts[443] = %v; want 1s", got)
+	}
+	if got := scannerCfg.PortTimeouts[3306]; got != 500*time.Millisecond {
+		t.Errorf("PortTimeouts[3306] = %v; want 500ms", got)
+	}
+	if _, ok := scannerCfg.PortTimeouts[80]; ok {
+		t.Error("ports without an override should use the global timeout")
+	}
+}
+
 func TestValidateInputs_InvalidRate(t *testing.T) {
 	tests := []struct {
 		name string
@@ -611,10 +708,13 @@ func TestValidateInputs_InvalidUDPRatio(t *testing.T) {
 	}
 }
 
+// allowAll is the default policy, permitting localhost and private targets.
+var allowAll = targets.Policy{AllowLocalhost: true, AllowPrivate: true}
+
 func TestValidateRawTargets_ValidTargets(t *testing.T) {
 	targets := []string{"localhost", "192.168.1.1", "example.com", "192.168.0.0/24"}
 
-	err := validateRawTargets(targets)
+	err := validateRawTargets(targets, allowAll)
 	if err != nil {
 		t.Errorf("validateRawTargets failed for valid targets: %v", err)
 	}
@@ -623,7 +723,7 @@ func TestValidateRawTargets_ValidTargets(t *testing.T) {
 func TestValidateRawTargets_EmptyTarget(t *testing.T) {
 	targets := []string{""}
 
-	err := validateRawTargets(targets)
+	err := validateRawTargets(targets, allowAll)
 	if err == nil {
 		t.Error("expected error for empty target")
 	}
@@ -641,7 +741,7 @@ func TestValidateRawTargets_InvalidTarget(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateRawTargets([]string{tt.target})
+			err := validateRawTargets([]string{tt.target}, allowAll)
 			if err == nil {
 				t.Errorf("expected error for invalid target: %s", tt.target)
 			}
@@ -649,6 +749,22 @@ func TestValidateRawTargets_InvalidTarget(t *testing.T) {
 	}
 }
 
+func TestValidateRawTargets_Policy(t *testing.T) {
+	err := validateRawTargets([]string{"example.com", "10.0.0.0/24"}, targets.Policy{AllowLocalhost: true})
+	var userErr *errors.UserError
+	if !stdErrors.As(err, &userErr) || userErr.Code != "PRIVATE_IP_DISABLED" {
+		t.Fatalf("expected PRIVATE_IP_DISABLED, got %v", err)
+	}
+	if !strings.Contains(userErr.Suggestion, "--allow-private") {
+		t.Errorf("suggestion should name --allow-private: %q", userErr.Suggestion)
+	}
+
+	err = validateRawTargets([]string{"localhost"}, targets.Policy{AllowPrivate: true})
+	if !stdErrors.Is(err, errors.ErrLocalhostScanningDisabled) {
+		t.Errorf("expected localhost to be refused, got %v", err)
+	}
+}
+
 func TestNormalizeProtocol(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -713,3 +829,220 @@ func TestBuildScanTargets_Empty(t *testing.T) {
 		t.Errorf("expected 0 ports, got %d", len(targets[0].Ports))
 	}
 }
+
+func TestNewRescanFunc_GrabsBanners(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("SSH-2.0-Test\r\n"))
+			_ = conn.Close()
+		}
+	}()
+
+	port := uint16(listener.Addr().(*net.TCPAddr).Port)
+	cfg := &config.Config{Workers: 1, TimeoutMs: 500, Banners: false}
+	rescan := newRescanFunc(context.Background(), cfg)
+
+	results, err := rescan(context.Background(), "tcp", []core.ScanTarget{{Host: "127.0.0.1", Ports: []uint16{port}}}, time.Second)
+	if err != nil {
+		t.Fatalf("rescan returned error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].State != core.StateOpen || results[0].Banner == "" {
+		t.Errorf("expected open port with banner, got %+v", results[0])
+	}
+	if cfg.Banners {
+		t.Error("rescan must not modify the caller's config")
+	}
+
+	if _, err := rescan(context.Background(), "sctp", nil, 0); err == nil {
+		t.Error("expected error for unsupported protocol")
+	}
+}
+
+func TestTableOptions(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	t.Setenv("NO_COLOR", "")
+
+	opts := tableOptions()
+	if !opts.Color || opts.OnlyOpen {
+		t.Errorf("unexpected defaults %+v", opts)
+	}
+
+	viper.Set("no_color", true)
+	viper.Set("only_open", true)
+	opts = tableOptions()
+	if opts.Color || !opts.OnlyOpen {
+		t.Errorf("flags not applied: %+v", opts)
+	}
+}
+
+func TestBuildScannerConfig_Timing(t *testing.T) {
+	cfg := &config.Config{TimeoutMs: 200, Retries: 3, JitterMs: 250, PerHostLimit: 2}
+	scannerCfg := buildScannerConfig(cfg)
+
+	if scannerCfg.MaxRetries != 3 {
+		t.Errorf("MaxRetries = %d; want 3", scannerCfg.MaxRetries)
+	}
+	if scannerCfg.ProbeJitter != 250*time.Millisecond || scannerCfg.UDPJitterMaxMs != 250 {
+		t.Errorf("jitter = %v / %dms; want 250ms", scannerCfg.ProbeJitter, scannerCfg.UDPJitterMaxMs)
+	}
+	if scannerCfg.MaxHostParallelism != 2 {
+		t.Errorf("MaxHostParallelism = %d; want 2", scannerCfg.MaxHostParallelism)
+	}
+}
+
+func TestFlagsChanged(t *testing.T) {
+	cmd := &cobra.Command{Use: "scan"}
+	cmd.Flags().IntP("rate", "r", 7500, "")
+	cmd.Flags().IntP("timeout", "t", 200, "")
+	if err := cmd.Flags().Parse([]string{"--rate", "50"}); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	cfg := &config.Config{Timing: "T2", Rate: 50, TimeoutMs: 200}
+	if err := cfg.ApplyTimingTemplate(flagsChanged(cmd, timingFlags)); err != nil {
+		t.Fatalf("ApplyTimingTemplate: %v", err)
+	}
+	if cfg.Rate != 50 {
+		t.Errorf("Rate = %d; want the explicit 50", cfg.Rate)
+	}
+	if polite := config.TimingTemplates[2]; cfg.TimeoutMs != polite.TimeoutMs {
+		t.Errorf("TimeoutMs = %d; want the polite template's %d", cfg.TimeoutMs, polite.TimeoutMs)
+	}
+}
+
+// TestRunScanners_InterruptFinalizesJSONObject verifies an interrupted scan
+// still writes a complete JSON document marked as aborted
+func TestRunScanners_InterruptFinalizesJSONObject(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("quiet", true)
+	viper.Set("json_object", true)
+
+	out := filepath.Join(t.TempDir(), "scan.json")
+	cfg := &config.Config{Workers: 4, TimeoutMs: 100, Rate: 200, Output: "json", OutputFile: out, AllowLocalhost: true}
+	scanner, err := NewScannerFactory(cfg).CreateScanner("tcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ports := make([]uint16, 2000)
+	for i := range ports {
+		ports[i] = uint16(20000 + i)
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	time.AfterFunc(300*time.Millisecond, func() { cancel(errInterrupted) })
+	err = runScanners(ctx, []core.PortScanner{scanner}, []string{"127.0.0.1"}, ports, cfg)
+	if code := errors.ExitCode(err); code != errors.ExitAborted {
+		t.Errorf("exit code = %d (%v), want %d", code, err, errors.ExitAborted)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("output not written: %v", err)
+	}
+	var doc struct {
+		Results  []map[string]interface{} `json:"results"`
+		ScanInfo map[string]interface{}   `json:"scan_info"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("interrupted output is not valid JSON: %v\n%s", err, data)
+	}
+	if doc.ScanInfo["aborted"] != true {
+		t.Errorf("scan_info = %+v, want aborted", doc.ScanInfo)
+	}
+	if len(doc.Results) == 0 || len(doc.Results) >= len(ports) {
+		t.Errorf("got %d results, want a partial scan of %d ports", len(doc.Results), len(ports))
+	}
+}
+
+// TestScanResolver verifies scanners share one resolver per DNS setting
+func TestScanResolver(t *testing.T) {
+	a := scanResolver(&config.Config{DNSServer: "10.0.0.53"})
+	if a == nil || scanResolver(&config.Config{DNSServer: "10.0.0.53"}) != a {
+		t.Error("the same DNS server should share a resolver and its cache")
+	}
+	if scanResolver(&config.Config{DNSServer: "10.0.0.53", DNSTimeoutMs: 500}) == a {
+		t.Error("a different timeout should get its own resolver")
+	}
+	if scanResolver(&config.Config{DNSServer: "quic://dns.example.com"}) != nil {
+		t.Error("an invalid DNS server should have no resolver")
+	}
+	if buildScannerConfig(&config.Config{}).Resolver == nil {
+		t.Error("scanners should resolve through the caching system resolver by default")
+	}
+}
+
+// TestExpandAllIPs verifies hostnames fan out to their addresses, named
+// after the hostname, without scanning an address twice
+func TestExpandAllIPs(t *testing.T) {
+	resolver, err := targets.NewResolver(targets.ResolverOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ports := []uint16{22, 80}
+	got := expandAllIPs(context.Background(), resolver, []core.ScanTarget{
+		{Host: "localhost", Ports: ports},
+		{Host: "127.0.0.1", Ports: ports},
+		{Host: "10.0.0.1", Ports: ports},
+		{Host: "portscan-test.invalid", Ports: ports},
+	})
+
+	byHost := make(map[string]core.ScanTarget)
+	for _, target := range got {
+		if _, dup := byHost[target.Host]; dup {
+			t.Errorf("address %s scanned twice", target.Host)
+		}
+		byHost[target.Host] = target
+	}
+	if byHost["127.0.0.1"].Hostname != "localhost" {
+		t.Errorf("127.0.0.1 hostname = %q, want localhost", byHost["127.0.0.1"].Hostname)
+	}
+	if target, ok := byHost["10.0.0.1"]; !ok || target.Hostname != "" {
+		t.Errorf("IP target = %+v, want it kept without a hostname", target)
+	}
+	if _, ok := byHost["portscan-test.invalid"]; !ok {
+		t.Error("an unresolvable hostname should be kept so its probes fail")
+	}
+	if _, ok := byHost["localhost"]; ok {
+		t.Error("a resolved hostname should be replaced by its addresses")
+	}
+}
+
+func TestTagResults(t *testing.T) {
+	events := make(chan core.Event, 2)
+	events <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen})
+	events <- core.NewErrorEvent(io.ErrUnexpectedEOF)
+	close(events)
+
+	var got []core.Event
+	for event := range tagResults(events, []string{"env=prod", "ticket=SEC-123"}) {
+		got = append(got, event)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d events, want 2", len(got))
+	}
+	if tags := got[0].Result.Tags; len(tags) != 2 || tags[0] != "env=prod" {
+		t.Errorf("result tags = %q", tags)
+	}
+	if got[1].Kind != core.EventKindError {
+		t.Error("error events should pass through")
+	}
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/parser"
+	"github.com/spf13/viper"
+)
+
+// enforceScanWindow pauses the scan whenever the clock is outside window
+// and resumes it when the window reopens, until ctx is done. Manual pauses
+// and resumes hold until the next window boundary.
+func enforceScanWindow(ctx context.Context, window parser.ScanWindow, control core.Pausable) {
+	for {
+		next := applyScanWindow(window, control, time.Now())
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// applyScanWindow pauses or resumes control for the time now and returns
+// when the window next opens or closes.
+func applyScanWindow(window parser.ScanWindow, control core.Pausable, now time.Time) time.Time {
+	if window.Contains(now) {
+		control.Resume()
+	} else {
+		control.Pause()
+	}
+	return window.NextChange(now)
+}
+
+// spreadRate paces probes so the scan finishes near the end of the window
+// instead of bursting at its start. The result never exceeds maxRate.
+func spreadRate(window parser.ScanWindow, now time.Time, probes, maxRate int) int {
+	seconds := window.Remaining(now).Seconds()
+	if probes <= 0 || seconds <= 0 {
+		return maxRate
+	}
+	rate := int(math.Ceil(float64(probes) / seconds))
+	return max(1, min(rate, maxRate))
+}
+
+// announceScanWindow tells the user when a scan starts outside its window.
+func announceScanWindow(window parser.ScanWindow, now time.Time) {
+	if viper.GetBool("quiet") || window.Contains(now) {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Outside scan window %s; waiting until %s\n",
+		window, window.NextChange(now).Format("Mon 15:04"))
+}
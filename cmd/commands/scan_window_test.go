@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/parser"
+)
+
+func TestApplyScanWindow(t *testing.T) {
+	window := parser.ScanWindow{Start: 22 * time.Hour, End: 6 * time.Hour}
+	control := &fakePausable{}
+
+	noon := time.Date(2024, 3, 10, 12, 0, 0, 0, time.Local)
+	next := applyScanWindow(window, control, noon)
+	if !control.paused {
+		t.Error("scan should pause outside the window")
+	}
+	if want := time.Date(2024, 3, 10, 22, 0, 0, 0, time.Local); !next.Equal(want) {
+		t.Errorf("next change = %v, want %v", next, want)
+	}
+
+	late := time.Date(2024, 3, 10, 23, 0, 0, 0, time.Local)
+	next = applyScanWindow(window, control, late)
+	if control.paused {
+		t.Error("scan should resume inside the window")
+	}
+	if want := time.Date(2024, 3, 11, 6, 0, 0, 0, time.Local); !next.Equal(want) {
+		t.Errorf("next change = %v, want %v", next, want)
+	}
+}
+
+func TestSpreadRate(t *testing.T) {
+	window := parser.ScanWindow{Start: 22 * time.Hour, End: 6 * time.Hour}
+	midnight := time.Date(2024, 3, 11, 0, 0, 0, 0, time.Local) // 6h left
+
+	if got := spreadRate(window, midnight, 6*3600*10, 7500); got != 10 {
+		t.Errorf("spreadRate = %d, want 10 pps over the remaining 6h", got)
+	}
+	if got := spreadRate(window, midnight, 10, 7500); got != 1 {
+		t.Errorf("spreadRate for a tiny scan = %d, want the 1 pps floor", got)
+	}
+	if got := spreadRate(window, midnight, 1<<30, 7500); got != 7500 {
+		t.Errorf("spreadRate = %d, want it capped at the configured rate", got)
+	}
+
+	noon := time.Date(2024, 3, 10, 12, 0, 0, 0, time.Local) // outside: full 8h
+	if got := spreadRate(window, noon, 8*3600*5, 7500); got != 5 {
+		t.Errorf("spreadRate outside the window = %d, want 5", got)
+	}
+}
+
+func TestValidateInputs_InvalidScanWindow(t *testing.T) {
+	base := config.Config{Ports: "80", Rate: 5000, TimeoutMs: 200, Workers: 50, UDPWorkerRatio: 0.5}
+
+	cfg := base
+	cfg.ScanWindow = "10pm-6am"
+	if err := validateInputs(&cfg); err == nil || !strings.Contains(err.Error(), "scan window") {
+		t.Errorf("expected scan window error, got %v", err)
+	}
+
+	cfg = base
+	cfg.Spread = true
+	if err := validateInputs(&cfg); err == nil || !strings.Contains(err.Error(), "scan window") {
+		t.Errorf("expected spread to require a window, got %v", err)
+	}
+
+	cfg = base
+	cfg.ScanWindow = "22:00-06:00"
+	cfg.Spread = true
+	if err := validateInputs(&cfg); err != nil {
+		t.Errorf("valid window rejected: %v", err)
+	}
+}
@@ -19,6 +19,14 @@ func NewScannerFactory(cfg *config.Config) *ScannerFactory {
 	}
 }
 
+// WithRateBudget makes every scanner the factory creates draw its probes
+// from budget, shared with scanners from other factories, instead of its
+// own rate limit.
+func (f *ScannerFactory) WithRateBudget(budget *core.RateBudget) *ScannerFactory {
+	f.config.RateBudget = budget
+	return f
+}
+
 // CreateScanner creates a scanner instance for the specified protocol.
 // Supported protocols: "tcp", "udp"
 func (f *ScannerFactory) CreateScanner(protocol string) (core.PortScanner, error) {
@@ -3,6 +3,7 @@ package commands
 import (
 	"testing"
 
+	"github.com/lucchesi-sec/portscan/internal/core"
 	"github.com/lucchesi-sec/portscan/pkg/config"
 )
 
@@ -147,3 +148,15 @@ func TestScannerFactory_MultipleScanners(t *testing.T) {
 		t.Error("TCP and UDP scanners should be different instances")
 	}
 }
+
+// TestScannerFactory_WithRateBudget verifies the shared budget reaches the
+// scanner config
+func TestScannerFactory_WithRateBudget(t *testing.T) {
+	budget := core.NewRateBudget(100)
+	defer budget.Stop()
+
+	factory := NewScannerFactory(&config.Config{Workers: 10, TimeoutMs: 200, Rate: 5000}).WithRateBudget(budget)
+	if factory.config.RateBudget != budget {
+		t.Error("factory should pass the rate budget to its scanners")
+	}
+}
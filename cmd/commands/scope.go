@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/lucchesi-sec/portscan/pkg/targets"
+)
+
+// scopeListLimit is how many out-of-scope targets the error names.
+const scopeListLimit = 5
+
+// enforceScope refuses the scan when --scope is set and any expanded
+// target falls outside it. Nothing has been probed when it fails.
+func enforceScope(cfg *config.Config, plans []scanPlan) error {
+	if cfg.Scope == "" {
+		return nil
+	}
+	scope, err := targets.LoadScope(cfg.Scope, cfg.ScopeKey)
+	if err != nil {
+		return &errors.UserError{
+			Code:       "INVALID_SCOPE",
+			Message:    i18n.T("errors.invalid_scope.message", cfg.Scope),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_scope.suggestion"),
+		}
+	}
+
+	var outside []string
+	seen := make(map[string]bool)
+	for _, plan := range plans {
+		outside = appendOutOfScope(outside, seen, scope, plan.targets)
+	}
+	if len(outside) == 0 {
+		return nil
+	}
+	return errors.OutOfScopeError(cfg.Scope, outside, scopeListLimit)
+}
+
+// appendOutOfScope appends each target host outside scope to outside once.
+func appendOutOfScope(outside []string, seen map[string]bool, scope *targets.Scope, scanTargets []core.ScanTarget) []string {
+	for _, t := range scanTargets {
+		if seen[t.Host] || scope.Allows(t.Host, t.Hostname) {
+			continue
+		}
+		seen[t.Host] = true
+		name := t.Host
+		if t.Hostname != "" {
+			name = fmt.Sprintf("%s (%s)", t.Host, t.Hostname)
+		}
+		outside = append(outside, name)
+	}
+	return outside
+}
+
+// scopeSummary describes the --scope setting for dry runs.
+func scopeSummary(cfg *config.Config) string {
+	parts := []string{cfg.Scope}
+	if cfg.ScopeKey != "" {
+		parts = append(parts, "(signed)")
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"context"
+	stdErrors "errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+)
+
+func TestEnforceScope(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scope.yaml")
+	if err := os.WriteFile(path, []byte("networks: [10.0.0.0/24]\ndomains: [example.com]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Scope: path}
+
+	inScope := []scanPlan{{targets: []core.ScanTarget{
+		{Host: "10.0.0.5"},
+		{Host: "example.com"},
+		{Host: "93.184.215.14", Hostname: "example.com"},
+	}}}
+	if err := enforceScope(cfg, inScope); err != nil {
+		t.Fatalf("in-scope targets refused: %v", err)
+	}
+
+	outOfScope := append(inScope, scanPlan{targets: []core.ScanTarget{
+		{Host: "10.0.1.5"},
+		{Host: "10.0.1.5"},
+		{Host: "www.example.com"},
+	}})
+	err := enforceScope(cfg, outOfScope)
+	var userErr *errors.UserError
+	if !stdErrors.As(err, &userErr) || userErr.Code != "OUT_OF_SCOPE" {
+		t.Fatalf("err = %v; want OUT_OF_SCOPE", err)
+	}
+	if !strings.Contains(userErr.Details, "2 target(s)") || !strings.Contains(userErr.Details, "www.example.com") {
+		t.Errorf("details should list each out-of-scope host once: %s", userErr.Details)
+	}
+
+	if err := enforceScope(&config.Config{}, outOfScope); err != nil {
+		t.Errorf("without --scope every target is allowed: %v", err)
+	}
+
+	err = enforceScope(&config.Config{Scope: filepath.Join(t.TempDir(), "missing.yaml")}, inScope)
+	if !stdErrors.As(err, &userErr) || userErr.Code != "INVALID_SCOPE" {
+		t.Errorf("err = %v; want INVALID_SCOPE", err)
+	}
+}
+
+func TestEnforcePolicyChecksResolvedAddresses(t *testing.T) {
+	ctx := context.Background()
+	plan := func(scanTargets ...core.ScanTarget) []scanPlan {
+		return []scanPlan{{targets: scanTargets}}
+	}
+	noLocal := &config.Config{AllowLocalhost: false, AllowPrivate: true}
+
+	err := enforcePolicy(ctx, noLocal, plan(core.ScanTarget{Host: "localhost", Ports: []uint16{80}}))
+	if err == nil || !strings.Contains(err.Error(), "localhost") {
+		t.Errorf("a hostname resolving to loopback should be refused, got %v", err)
+	}
+
+	// --all-ips targets carry the hostname they were expanded from.
+	noPrivate := &config.Config{AllowLocalhost: true, AllowPrivate: false}
+	expanded := core.ScanTarget{Host: "10.2.0.7", Hostname: "db.internal", Ports: []uint16{5432}}
+	var userErr *errors.UserError
+	err = enforcePolicy(ctx, noPrivate, plan(expanded))
+	if !stdErrors.As(err, &userErr) || userErr.Code != "PRIVATE_IP_DISABLED" || !strings.Contains(userErr.Message, "db.internal") {
+		t.Errorf("an expanded private address should be refused naming its hostname, got %v", err)
+	}
+
+	if err := enforcePolicy(ctx, noPrivate, plan(core.ScanTarget{Host: "192.0.2.1", Ports: []uint16{80}})); err != nil {
+		t.Errorf("a public address should pass, got %v", err)
+	}
+}
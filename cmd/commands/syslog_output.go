@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+)
+
+// syslogDialTimeout bounds connecting to the syslog collector.
+const syslogDialTimeout = 10 * time.Second
+
+// openSyslogOutput connects to the --syslog-addr collector and returns an
+// exporter writing to it, plus a finish func that closes the connection.
+// Stream transports (TCP, TLS) use RFC 6587 octet counting.
+func openSyslogOutput(cfg *config.Config) (exporter.Exporter, func(error) error, error) {
+	conn, stream, err := exporter.DialSyslog(cfg.SyslogAddr, syslogDialTimeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	exp := exporter.NewSyslogExporter(conn, exporter.SyslogOptions{
+		Format:        cfg.SyslogFormat,
+		OctetCounting: stream,
+	})
+	finish := func(exportErr error) error {
+		if err := conn.Close(); err != nil && exportErr == nil {
+			return err
+		}
+		return exportErr
+	}
+	return exp, finish, nil
+}
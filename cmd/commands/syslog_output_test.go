@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/spf13/viper"
+)
+
+func TestHandleScanOutput_SyslogUDP(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	collector, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer collector.Close()
+
+	cfg := &config.Config{Output: "syslog", SyslogAddr: "udp://" + collector.LocalAddr().String(), SyslogFormat: exporter.SyslogFormatCEF}
+	events := make(chan core.Event, 2)
+	events <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 443, State: core.StateOpen})
+	close(events)
+
+	if err := handleScanOutput(context.Background(), cfg, events, 1, exporter.ScanMetadata{}, scanHandle{}); err != nil {
+		t.Fatalf("handleScanOutput: %v", err)
+	}
+
+	buf := make([]byte, 2048)
+	_ = collector.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := collector.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("collector received nothing: %v", err)
+	}
+	msg := string(buf[:n])
+	if !strings.Contains(msg, "CEF:0|lucchesi-sec|portscan|") || !strings.Contains(msg, "dpt=443") {
+		t.Errorf("datagram = %q", msg)
+	}
+}
+
+func TestValidateInputs_Syslog(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	cfg := &config.Config{Ports: "80", Rate: 100, TimeoutMs: 200, Output: "syslog", SyslogAddr: "collector:514"}
+	err := validateInputs(cfg)
+	if err == nil || !strings.Contains(err.Error(), "syslog") {
+		t.Fatalf("validateInputs() = %v, want a syslog error", err)
+	}
+}
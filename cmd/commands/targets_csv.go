@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/lucchesi-sec/portscan/pkg/targets"
+	"github.com/spf13/viper"
+)
+
+// loadAssetTargets reads the --targets-csv inventory and returns its
+// hosts. The tag fields of each record are stored in cfg.HostTags under
+// every host the record's target expands to.
+func loadAssetTargets(cfg *config.Config) ([]string, error) {
+	assets, err := readAssetFile(cfg)
+	if err != nil {
+		return nil, &errors.UserError{
+			Code:       "INVALID_TARGETS_CSV",
+			Message:    i18n.T("errors.invalid_targets_csv.message", cfg.TargetsCSV),
+			Details:    err.Error(),
+			Suggestion: i18n.T("errors.invalid_targets_csv.suggestion"),
+		}
+	}
+	hosts := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		hosts = append(hosts, asset.Host)
+		if len(asset.Tags) == 0 {
+			continue
+		}
+		// Invalid targets are reported by target validation.
+		expanded, err := targets.Resolve([]string{asset.Host}, targets.Options{})
+		if err != nil {
+			continue
+		}
+		if cfg.HostTags == nil {
+			cfg.HostTags = make(map[string][]string)
+		}
+		for _, host := range expanded {
+			for _, tag := range asset.Tags {
+				if !slices.Contains(cfg.HostTags[host], tag) {
+					cfg.HostTags[host] = append(cfg.HostTags[host], tag)
+				}
+			}
+		}
+	}
+	return hosts, nil
+}
+
+func readAssetFile(cfg *config.Config) ([]targets.Asset, error) {
+	f, err := os.Open(cfg.TargetsCSV)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	assets, skipped, err := targets.ReadAssets(f, targets.AssetOptions{
+		HostField: cfg.HostField,
+		TagFields: cfg.GetTagFields(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if skipped > 0 && !viper.GetBool("quiet") {
+		fmt.Fprintf(os.Stderr, "warning: skipped %d records in %s with no %s\n", skipped, cfg.TargetsCSV, cfg.HostField)
+	}
+	return assets, nil
+}
+
+// tagHostResults attaches each host's asset tags to its results, matching
+// the result's address or, for --all-ips scans, the name it resolved from.
+func tagHostResults(events <-chan core.Event, hostTags map[string][]string) <-chan core.Event {
+	out := make(chan core.Event, cap(events))
+	go func() {
+		defer close(out)
+		for event := range events {
+			if event.Kind == core.EventKindResult && event.Result != nil {
+				tags, ok := hostTags[event.Result.Host]
+				if !ok && event.Result.Hostname != "" {
+					tags = hostTags[event.Result.Hostname]
+				}
+				event.Result.Tags = append(event.Result.Tags, tags...)
+			}
+			out <- event
+		}
+	}()
+	return out
+}
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	stdErrors "errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+func TestLoadAssetTargets(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("quiet", true)
+
+	path := filepath.Join(t.TempDir(), "assets.csv")
+	csv := "ip,owner,env\n10.0.0.1,alice,prod\n10.0.1.0/31,,dev\ndb.internal,bob,\n,carol,prod\n10.0.0.1,alice,pci\n"
+	if err := os.WriteFile(path, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{TargetsCSV: path, HostField: "ip", TagFields: "owner,env"}
+	hosts, err := loadAssetTargets(cfg)
+	if err != nil {
+		t.Fatalf("loadAssetTargets: %v", err)
+	}
+	if want := []string{"10.0.0.1", "10.0.1.0/31", "db.internal", "10.0.0.1"}; !reflect.DeepEqual(hosts, want) {
+		t.Errorf("hosts = %q, want %q", hosts, want)
+	}
+	want := map[string][]string{
+		"10.0.0.1":    {"owner=alice", "env=prod", "env=pci"},
+		"10.0.1.0":    {"env=dev"},
+		"10.0.1.1":    {"env=dev"},
+		"db.internal": {"owner=bob"},
+	}
+	if !reflect.DeepEqual(cfg.HostTags, want) {
+		t.Errorf("HostTags = %v, want %v", cfg.HostTags, want)
+	}
+}
+
+func TestLoadAssetTargets_Unreadable(t *testing.T) {
+	cfg := &config.Config{TargetsCSV: filepath.Join(t.TempDir(), "missing.csv"), HostField: "ip"}
+	_, err := loadAssetTargets(cfg)
+	var userErr *errors.UserError
+	if !stdErrors.As(err, &userErr) || userErr.Code != "INVALID_TARGETS_CSV" {
+		t.Errorf("loadAssetTargets error = %v, want INVALID_TARGETS_CSV", err)
+	}
+}
+
+func TestTagHostResults(t *testing.T) {
+	events := make(chan core.Event, 3)
+	events <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen, Tags: []string{"scan=nightly"}})
+	events <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.9", Hostname: "db.internal", Port: 5432, State: core.StateOpen})
+	events <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.2", Port: 80, State: core.StateClosed})
+	close(events)
+
+	hostTags := map[string][]string{"10.0.0.1": {"owner=alice"}, "db.internal": {"owner=bob"}}
+	var got [][]string
+	for event := range tagHostResults(events, hostTags) {
+		got = append(got, event.Result.Tags)
+	}
+	want := [][]string{{"scan=nightly", "owner=alice"}, {"owner=bob"}, nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tags = %q, want %q", got, want)
+	}
+}
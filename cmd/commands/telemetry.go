@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/telemetry"
+	"go.opentelemetry.io/otel"
+)
+
+// newTelemetryRecorder builds the OpenTelemetry recorder for
+// --otel-endpoint. The scan span is tagged with the protocol, probe count
+// and the scan's --tag values.
+func newTelemetryRecorder(cfg *config.Config, plans []scanPlan) (*telemetry.Recorder, error) {
+	probes := 0
+	for _, plan := range plans {
+		for _, t := range plan.targets {
+			probes += len(t.Ports)
+		}
+	}
+	attrs := map[string]string{
+		"portscan.protocol": normalizeProtocol(cfg.Protocol),
+		"portscan.probes":   strconv.Itoa(probes),
+	}
+	for _, tag := range cfg.GetTags() {
+		if key, value, ok := strings.Cut(tag, "="); ok {
+			attrs["portscan.tag."+key] = value
+		}
+	}
+	// Flush reports export failures; the SDK's default handler would also
+	// log each one to stderr, over the TUI.
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(error) {}))
+	return telemetry.NewRecorder(telemetry.Options{Endpoint: cfg.OTelEndpoint, Attributes: attrs})
+}
+
+// telemetryOutputName names the output stage for its export span.
+func telemetryOutputName(cfg *config.Config) string {
+	if format := outputFormat(cfg); format != "" {
+		return format
+	}
+	return "tui"
+}
+
+// flushTelemetry sends the recorded spans and metrics. The scan itself has
+// finished, so a collector that cannot be reached only earns a warning.
+// An interrupted scan is still reported, within telemetry.DefaultTimeout.
+func flushTelemetry(ctx context.Context, rec *telemetry.Recorder) {
+	flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), telemetry.DefaultTimeout)
+	defer cancel()
+	if err := rec.Flush(flushCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not send telemetry: %v\n", err)
+	}
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lucchesi-sec/portscan/pkg/theme"
+	"github.com/spf13/cobra"
+)
+
+var themesCmd = &cobra.Command{
+	Use:   "themes",
+	Short: "Manage UI color themes",
+	Long:  `List built-in themes and custom themes loaded from the user theme directory.`,
+}
+
+var themesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available themes",
+	Long: `List built-in and user themes. Custom themes are YAML files in
+~/.config/portscan/themes (or $XDG_CONFIG_HOME/portscan/themes).`,
+	RunE: runThemesList,
+}
+
+func init() {
+	rootCmd.AddCommand(themesCmd)
+	themesCmd.AddCommand(themesListCmd)
+}
+
+func runThemesList(cmd *cobra.Command, args []string) error {
+	printThemeList(os.Stdout, os.Stderr)
+	return nil
+}
+
+// printThemeList writes available themes to out and any theme files that
+// failed to load to errOut.
+func printThemeList(out, errOut io.Writer) {
+	for _, name := range theme.Names() {
+		t, _ := theme.Lookup(name)
+		source := "built-in"
+		if t.Source != "" {
+			source = t.Source
+		}
+		fmt.Fprintf(out, "  %-16s %s\n", name, source)
+	}
+
+	if dir, err := theme.UserThemeDir(); err == nil {
+		fmt.Fprintf(out, "\nUser theme directory: %s\n", dir)
+	}
+
+	for _, err := range theme.UserThemeErrors() {
+		fmt.Fprintf(errOut, "warning: %v\n", err)
+	}
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestThemesListCommand verifies the themes command structure
+func TestThemesListCommand(t *testing.T) {
+	if themesListCmd.Use != "list" {
+		t.Errorf("Use = %q; want 'list'", themesListCmd.Use)
+	}
+	if themesListCmd.RunE == nil {
+		t.Error("RunE should be set")
+	}
+
+	found := false
+	for _, sub := range themesCmd.Commands() {
+		if sub == themesListCmd {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("list should be registered under themes")
+	}
+}
+
+// TestPrintThemeList verifies built-in themes are listed
+func TestPrintThemeList(t *testing.T) {
+	var out, errOut bytes.Buffer
+	printThemeList(&out, &errOut)
+
+	for _, name := range []string{"default", "dracula", "monokai", "high-contrast"} {
+		if !strings.Contains(out.String(), name) {
+			t.Errorf("output should list %q, got:\n%s", name, out.String())
+		}
+	}
+	if !strings.Contains(out.String(), "built-in") {
+		t.Error("built-in themes should be labelled")
+	}
+}
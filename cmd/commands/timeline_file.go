@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/spf13/viper"
+)
+
+// eventObserver records scan events, like core.Timeline.
+type eventObserver interface {
+	Observe(core.Event)
+}
+
+// observeEvents passes events through unchanged, showing each to obs on
+// the way.
+func observeEvents(events <-chan core.Event, obs eventObserver) <-chan core.Event {
+	out := make(chan core.Event, cap(events))
+	go func() {
+		defer close(out)
+		for event := range events {
+			obs.Observe(event)
+			out <- event
+		}
+	}()
+	return out
+}
+
+// writeTimelineFile writes the timeline to path as a JSON array. Like
+// --output-file the file is replaced atomically, and a .gz or .zst name
+// compresses it.
+func writeTimelineFile(path string, timeline *core.Timeline) error {
+	file, err := exporter.CreateFile(path, exporter.FileOptions{})
+	if err != nil {
+		return err
+	}
+	if err := exporter.WriteTimeline(file, timeline.Finish()); err != nil {
+		file.Abort()
+		return fmt.Errorf("write timeline: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if !viper.GetBool("quiet") {
+		fmt.Fprintf(os.Stderr, "Timeline written to %s\n", path)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+func TestTimelineFile(t *testing.T) {
+	events := make(chan core.Event, 2)
+	events <- core.NewTimelineEvent(core.TimelineEntry{Kind: core.TimelineHostStart, Host: "10.0.0.1"})
+	events <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22})
+	close(events)
+
+	timeline := core.NewTimeline()
+	passed := 0
+	for range observeEvents(events, timeline) {
+		passed++
+	}
+	if passed != 2 {
+		t.Fatalf("observeEvents passed %d events; want 2", passed)
+	}
+
+	path := filepath.Join(t.TempDir(), "timeline.json")
+	if err := writeTimelineFile(path, timeline); err != nil {
+		t.Fatalf("writeTimelineFile: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []map[string]interface{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("timeline file is not a JSON array: %v\n%s", err, data)
+	}
+	if len(entries) != 3 || entries[1]["event"] != "host_start" || entries[2]["event"] != "scan_finish" {
+		t.Errorf("timeline file = %s", data)
+	}
+}
@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/probes"
+)
+
+// scanUDPProbes loads the --udp-probes packs as scanner probes by port; a
+// port listed in several packs gets the last one's probe. It returns nil
+// if no packs are set or one fails to load, which validateInputs reports
+// first.
+func scanUDPProbes(cfg *config.Config) map[uint16]core.UDPProbe {
+	if cfg.UDPProbes == "" {
+		return nil
+	}
+	pack, err := probes.Load(cfg.UDPProbes)
+	if err != nil {
+		return nil
+	}
+	byPort := make(map[uint16]core.UDPProbe)
+	for _, p := range pack {
+		for _, port := range p.Ports {
+			byPort[port] = core.UDPProbe{Service: p.Name, Payload: p.Payload, Match: p.Match}
+		}
+	}
+	return byPort
+}
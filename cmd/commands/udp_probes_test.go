@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+func TestScanUDPProbes(t *testing.T) {
+	if got := scanUDPProbes(&config.Config{}); got != nil {
+		t.Errorf("no packs should give nil probes, got %d", len(got))
+	}
+
+	path := filepath.Join(t.TempDir(), "probes.yaml")
+	pack := "probes:\n  - name: ssdp-custom\n    ports: [1900, 1901]\n    text: M-SEARCH\n"
+	if err := os.WriteFile(path, []byte(pack), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	byPort := buildScannerConfig(&config.Config{UDPProbes: "extended," + path}).UDPProbes
+	if byPort[1900].Service != "ssdp-custom" || byPort[1901].Service != "ssdp-custom" {
+		t.Errorf("the later pack should win for 1900 and add 1901, got %q and %q", byPort[1900].Service, byPort[1901].Service)
+	}
+	if byPort[500].Service != "IKE/IPSec" {
+		t.Errorf("port 500 probe = %q; want the bundled IKE probe", byPort[500].Service)
+	}
+
+	if got := scanUDPProbes(&config.Config{UDPProbes: path + ".missing"}); got != nil {
+		t.Error("a pack that fails to load should give nil probes")
+	}
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/lucchesi-sec/portscan/pkg/upload"
+	"github.com/spf13/viper"
+)
+
+// reportUpload uploads the finished export file for --upload.
+type reportUpload struct {
+	dest   upload.Destination
+	key    string
+	format string
+	opts   upload.Options
+}
+
+// newReportUpload resolves the object key for this scan run. The key's
+// {date} and {time} come from the scan start.
+func newReportUpload(cfg *config.Config, start time.Time) (*reportUpload, error) {
+	dest, err := upload.ParseDestination(cfg.Upload)
+	if err != nil {
+		return nil, err
+	}
+	format := outputFormat(cfg)
+	return &reportUpload{
+		dest:   dest,
+		key:    dest.ObjectKey(upload.KeyVars{Time: start, ScanID: newScanID(start), Format: format}),
+		format: format,
+		opts: upload.Options{
+			Endpoint: cfg.UploadEndpoint,
+			Region:   cfg.UploadRegion,
+		},
+	}, nil
+}
+
+// send uploads the export at path. An empty export leaves no file behind,
+// so it is uploaded as an empty object.
+func (u *reportUpload) send(path, compression string) error {
+	body := io.ReadSeeker(strings.NewReader(""))
+	f, err := os.Open(path)
+	switch {
+	case err == nil:
+		defer f.Close()
+		body = f
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	opts := u.opts
+	opts.ContentType = uploadContentType(u.format, compression)
+	if err := upload.Upload(context.Background(), u.dest, u.key, body, opts); err != nil {
+		return err
+	}
+	if !viper.GetBool("quiet") {
+		fmt.Fprintf(os.Stderr, "Uploaded results to %s\n", u.dest.URL(u.key))
+	}
+	return nil
+}
+
+// uploadContentType returns the stored content type for an export.
+func uploadContentType(format, compression string) string {
+	switch {
+	case compression != "" && compression != exporter.CompressionNone:
+		return exporter.CompressionMediaType(compression)
+	case format == "json" && (viper.GetBool("json_array") || viper.GetBool("json_object")):
+		return "application/json"
+	case format == "json":
+		return "application/x-ndjson"
+	case format == "csv":
+		return "text/csv; charset=utf-8"
+	case format == "html":
+		return "text/html; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// newScanID returns an identifier for a scan run that sorts by start time,
+// e.g. "20240501T134502Z-9f3a1c".
+func newScanID(start time.Time) string {
+	suffix := make([]byte, 3)
+	_, _ = rand.Read(suffix)
+	return start.UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix)
+}
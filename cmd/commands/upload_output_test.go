@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/spf13/viper"
+)
+
+// objectStore records uploaded objects by path, failing with status when
+// set. It takes S3 PUTs and Cloud Storage JSON API multipart uploads.
+type objectStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+	status  int
+}
+
+func newObjectStore(t *testing.T) (*objectStore, string) {
+	s := &objectStore{objects: map[string][]byte{}, types: map[string]string{}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.status != 0 {
+			w.WriteHeader(s.status)
+			return
+		}
+		switch {
+		case r.Method == http.MethodHead:
+		case r.Method == http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			s.objects[r.URL.Path] = body
+			s.types[r.URL.Path] = r.Header.Get("Content-Type")
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/upload/storage/v1/b/"):
+			bucket := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/upload/storage/v1/b/"), "/o")
+			_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			parts := multipart.NewReader(r.Body, params["boundary"])
+			var metadata struct {
+				Name        string `json:"name"`
+				ContentType string `json:"contentType"`
+			}
+			if part, err := parts.NextPart(); err == nil {
+				_ = json.NewDecoder(part).Decode(&metadata)
+			}
+			if part, err := parts.NextPart(); err == nil {
+				path := "/" + bucket + "/" + metadata.Name
+				s.objects[path], _ = io.ReadAll(part)
+				s.types[path] = metadata.ContentType
+			}
+			json.NewEncoder(w).Encode(map[string]string{"bucket": bucket, "name": metadata.Name})
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	dir := t.TempDir()
+	for k, v := range map[string]string{
+		"AWS_ACCESS_KEY_ID":           "AKID",
+		"AWS_SECRET_ACCESS_KEY":       "secret",
+		"AWS_SESSION_TOKEN":           "",
+		"AWS_REGION":                  "",
+		"AWS_PROFILE":                 "",
+		"AWS_CONFIG_FILE":             filepath.Join(dir, "config"),
+		"AWS_SHARED_CREDENTIALS_FILE": filepath.Join(dir, "credentials"),
+		"AWS_EC2_METADATA_DISABLED":   "true",
+	} {
+		t.Setenv(k, v)
+	}
+	return s, srv.URL
+}
+
+func uploadTestEvents() <-chan core.Event {
+	events := make(chan core.Event, 1)
+	events <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 443, State: core.StateOpen})
+	close(events)
+	return events
+}
+
+func TestHandleScanOutput_UploadWithoutOutputFile(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("quiet", true)
+	store, endpoint := newObjectStore(t)
+	t.Setenv("TMPDIR", t.TempDir())
+
+	cfg := &config.Config{Upload: "s3://reports/scans/{date}/{scan_id}.{format}.gz", UploadEndpoint: endpoint}
+	if err := handleScanOutput(context.Background(), cfg, uploadTestEvents(), 1, exporter.ScanMetadata{}, scanHandle{}); err != nil {
+		t.Fatalf("handleScanOutput: %v", err)
+	}
+
+	if len(store.objects) != 1 {
+		t.Fatalf("uploaded %d objects, want 1", len(store.objects))
+	}
+	keyPattern := regexp.MustCompile(`^/reports/scans/\d{4}-\d{2}-\d{2}/\d{8}T\d{6}Z-[0-9a-f]{6}\.json\.gz$`)
+	for path, data := range store.objects {
+		if !keyPattern.MatchString(path) {
+			t.Errorf("object path = %s", path)
+		}
+		if store.types[path] != "application/gzip" {
+			t.Errorf("content type = %q", store.types[path])
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("object is not gzip: %v", err)
+		}
+		if plain, _ := io.ReadAll(zr); !strings.Contains(string(plain), `"port":443`) {
+			t.Errorf("object = %q, want the NDJSON result", plain)
+		}
+	}
+	if leftover, _ := filepath.Glob(filepath.Join(os.TempDir(), "portscan-upload-*")); len(leftover) != 0 {
+		t.Errorf("temporary export not removed: %v", leftover)
+	}
+}
+
+func TestHandleScanOutput_UploadOutputFile(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("quiet", true)
+	store, endpoint := newObjectStore(t)
+
+	path := filepath.Join(t.TempDir(), "scan.csv")
+	cfg := &config.Config{Output: "csv", OutputFile: path, Upload: "gs://reports/latest.csv", UploadEndpoint: endpoint}
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "token")
+	if err := handleScanOutput(context.Background(), cfg, uploadTestEvents(), 1, exporter.ScanMetadata{}, scanHandle{}); err != nil {
+		t.Fatalf("handleScanOutput: %v", err)
+	}
+
+	local, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("output file not kept: %v", err)
+	}
+	if got := store.objects["/reports/latest.csv"]; !bytes.Equal(got, local) || store.types["/reports/latest.csv"] != "text/csv; charset=utf-8" {
+		t.Errorf("uploaded %q as %q, want the local CSV", got, store.types["/reports/latest.csv"])
+	}
+}
+
+func TestHandleScanOutput_UploadFailureKeepsResults(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("quiet", true)
+	store, endpoint := newObjectStore(t)
+	store.status = http.StatusForbidden
+	t.Setenv("TMPDIR", t.TempDir())
+
+	cfg := &config.Config{Upload: "s3://reports/scan.json", UploadEndpoint: endpoint}
+	err := handleScanOutput(context.Background(), cfg, uploadTestEvents(), 1, exporter.ScanMetadata{}, scanHandle{})
+	if err == nil || !strings.Contains(err.Error(), "results kept at") {
+		t.Fatalf("err = %v, want the upload error and the kept file", err)
+	}
+	kept, _ := filepath.Glob(filepath.Join(os.TempDir(), "portscan-upload-*"))
+	if len(kept) != 1 {
+		t.Fatalf("kept files = %v, want the export", kept)
+	}
+	if data, _ := os.ReadFile(kept[0]); !strings.Contains(string(data), `"port":443`) {
+		t.Errorf("kept export = %q", data)
+	}
+}
+
+func TestNewScanID(t *testing.T) {
+	start := time.Date(2024, 5, 1, 13, 45, 2, 0, time.UTC)
+	a, b := newScanID(start), newScanID(start)
+	if !strings.HasPrefix(a, "20240501T134502Z-") || a == b {
+		t.Errorf("newScanID = %q, %q; want time-prefixed distinct IDs", a, b)
+	}
+}
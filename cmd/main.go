@@ -4,10 +4,11 @@ import (
 	"os"
 
 	"github.com/lucchesi-sec/portscan/cmd/commands"
+	"github.com/lucchesi-sec/portscan/pkg/errors"
 )
 
 func main() {
 	if err := commands.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }
@@ -0,0 +1,155 @@
+package bench
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+// Options configures a benchmark run.
+type Options struct {
+	Simulator SimulatorOptions
+	Workers   int
+	Rate      int // probes per second; 0 disables rate limiting
+	Timeout   time.Duration
+	Banners   bool
+	// Duration turns the run into a soak test: rounds repeat against the
+	// same simulator until it elapses. Zero runs a single round.
+	Duration time.Duration
+}
+
+// RoundReport describes one scan of every simulated port.
+type RoundReport struct {
+	Round      int
+	Probes     int
+	Elapsed    time.Duration
+	PPS        float64
+	Open       int
+	Closed     int
+	Filtered   int
+	Correct    int // results matching the simulator's layout
+	Missing    int // simulated ports with no result
+	Errors     int // probes reported as scan errors
+	CPU        time.Duration
+	Allocs     uint64
+	AllocBytes uint64
+}
+
+// Accuracy returns the fraction of probes reported with the expected state.
+func (r RoundReport) Accuracy() float64 {
+	if r.Probes == 0 {
+		return 0
+	}
+	return float64(r.Correct) / float64(r.Probes)
+}
+
+// Summary aggregates every round of a run.
+type Summary struct {
+	Rounds      []RoundReport
+	Elapsed     time.Duration
+	Probes      int
+	PPS         float64
+	MinAccuracy float64
+	CPU         time.Duration
+	Allocs      uint64
+	AllocBytes  uint64
+}
+
+// Run starts a simulator and scans it once, or repeatedly for
+// opts.Duration. onRound, if set, is called after each round.
+func Run(ctx context.Context, opts Options, onRound func(RoundReport)) (Summary, error) {
+	sim, err := StartSimulator(opts.Simulator)
+	if err != nil {
+		return Summary{}, err
+	}
+	defer sim.Close()
+
+	summary := Summary{MinAccuracy: 1}
+	start := time.Now()
+	for round := 1; ; round++ {
+		report, err := runRound(ctx, sim, opts)
+		if err != nil {
+			return summary, err
+		}
+		report.Round = round
+		summary.add(report)
+		if onRound != nil {
+			onRound(report)
+		}
+		if opts.Duration <= 0 || time.Since(start) >= opts.Duration || ctx.Err() != nil {
+			break
+		}
+	}
+	summary.Elapsed = time.Since(start)
+	if secs := summary.Elapsed.Seconds(); secs > 0 {
+		summary.PPS = float64(summary.Probes) / secs
+	}
+	return summary, nil
+}
+
+func (s *Summary) add(r RoundReport) {
+	s.Rounds = append(s.Rounds, r)
+	s.Probes += r.Probes
+	s.CPU += r.CPU
+	s.Allocs += r.Allocs
+	s.AllocBytes += r.AllocBytes
+	if acc := r.Accuracy(); acc < s.MinAccuracy {
+		s.MinAccuracy = acc
+	}
+}
+
+// runRound scans every simulated port with a fresh scanner and checks the
+// results against the simulator's layout.
+func runRound(ctx context.Context, sim *Simulator, opts Options) (RoundReport, error) {
+	scanner := core.NewScanner(&core.Config{
+		Workers:    opts.Workers,
+		Timeout:    opts.Timeout,
+		RateLimit:  opts.Rate,
+		BannerGrab: opts.Banners,
+	})
+	targets := []core.ScanTarget{{Host: sim.Host, Ports: sim.Ports}}
+
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+	cpuBefore := processCPUTime()
+	start := time.Now()
+
+	go scanner.ScanTargets(ctx, targets)
+
+	seen := make(map[uint16]bool, len(sim.Ports))
+	report := RoundReport{Probes: len(sim.Ports)}
+	for event := range scanner.Results() {
+		switch event.Kind {
+		case core.EventKindResult:
+			r := event.Result
+			seen[r.Port] = true
+			switch r.State {
+			case core.StateOpen:
+				report.Open++
+			case core.StateClosed:
+				report.Closed++
+			case core.StateFiltered:
+				report.Filtered++
+			}
+			if open, known := sim.Expected(r.Port); known && open == (r.State == core.StateOpen) {
+				report.Correct++
+			}
+		case core.EventKindError:
+			report.Errors++
+		}
+	}
+
+	report.Elapsed = time.Since(start)
+	report.CPU = processCPUTime() - cpuBefore
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+	report.Allocs = after.Mallocs - before.Mallocs
+	report.AllocBytes = after.TotalAlloc - before.TotalAlloc
+	report.Missing = len(sim.Ports) - len(seen)
+	if secs := report.Elapsed.Seconds(); secs > 0 {
+		report.PPS = float64(len(seen)) / secs
+	}
+	return report, ctx.Err()
+}
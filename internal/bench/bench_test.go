@@ -0,0 +1,61 @@
+package bench
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRun_SingleRound(t *testing.T) {
+	var rounds int
+	summary, err := Run(context.Background(), Options{
+		Simulator: SimulatorOptions{Ports: 40, OpenRatio: 0.25, Seed: 7},
+		Workers:   10,
+		Timeout:   500 * time.Millisecond,
+	}, func(RoundReport) { rounds++ })
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if rounds != 1 || len(summary.Rounds) != 1 {
+		t.Fatalf("rounds = %d (summary %d), want 1", rounds, len(summary.Rounds))
+	}
+
+	r := summary.Rounds[0]
+	if r.Probes != 40 || r.Missing != 0 {
+		t.Errorf("probes = %d missing = %d, want 40 and 0", r.Probes, r.Missing)
+	}
+	if r.Open != 10 {
+		t.Errorf("open = %d, want 10", r.Open)
+	}
+	if r.Accuracy() != 1 {
+		t.Errorf("accuracy = %.2f, want 1", r.Accuracy())
+	}
+	if summary.PPS <= 0 {
+		t.Errorf("pps = %v, want > 0", summary.PPS)
+	}
+}
+
+func TestRun_Soak(t *testing.T) {
+	summary, err := Run(context.Background(), Options{
+		Simulator: SimulatorOptions{Ports: 10, OpenRatio: 0.5, DropRate: 0.5, Banner: "x", Seed: 3},
+		Workers:   5,
+		Timeout:   500 * time.Millisecond,
+		Banners:   true,
+		Duration:  250 * time.Millisecond,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(summary.Rounds) < 2 {
+		t.Errorf("rounds = %d, want several during soak", len(summary.Rounds))
+	}
+	if summary.Probes != 10*len(summary.Rounds) {
+		t.Errorf("probes = %d, want %d", summary.Probes, 10*len(summary.Rounds))
+	}
+}
+
+func TestRoundReport_AccuracyEmpty(t *testing.T) {
+	if got := (RoundReport{}).Accuracy(); got != 0 {
+		t.Errorf("accuracy = %v, want 0", got)
+	}
+}
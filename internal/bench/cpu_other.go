@@ -0,0 +1,10 @@
+//go:build !unix
+
+package bench
+
+import "time"
+
+// processCPUTime is not available on this platform; CPU is reported as zero.
+func processCPUTime() time.Duration {
+	return 0
+}
@@ -0,0 +1,120 @@
+package bench
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/testserver"
+)
+
+// SimulatorOptions configures the local target simulator.
+type SimulatorOptions struct {
+	Ports     int           // total simulated ports
+	OpenRatio float64       // fraction of ports with a listener (0-1)
+	Latency   time.Duration // delay before an open port sends its banner
+	DropRate  float64       // fraction of accepted connections reset without a banner (0-1)
+	Banner    string        // banner sent by open ports
+	Seed      int64         // seeds the open/closed layout; 0 uses the clock
+}
+
+// Simulator serves TCP listeners on loopback through a testserver.Server.
+// Closed ports are reserved and released before the scan so connecting to
+// them is refused.
+type Simulator struct {
+	Host     string
+	Ports    []uint16
+	expected map[uint16]bool // port -> open
+	server   *testserver.Server
+}
+
+// StartSimulator binds the simulated ports and starts accepting connections.
+func StartSimulator(opts SimulatorOptions) (*Simulator, error) {
+	if opts.Ports <= 0 {
+		return nil, fmt.Errorf("simulator needs at least one port")
+	}
+	if opts.OpenRatio < 0 || opts.OpenRatio > 1 {
+		return nil, fmt.Errorf("open ratio must be between 0 and 1, got %v", opts.OpenRatio)
+	}
+	if opts.DropRate < 0 || opts.DropRate > 1 {
+		return nil, fmt.Errorf("drop rate must be between 0 and 1, got %v", opts.DropRate)
+	}
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	sim := &Simulator{Host: "127.0.0.1", expected: make(map[uint16]bool, opts.Ports)}
+
+	openCount := int(float64(opts.Ports)*opts.OpenRatio + 0.5)
+	var reserved []net.Listener
+	for i := openCount; i < opts.Ports; i++ {
+		ln, err := net.Listen("tcp", net.JoinHostPort(sim.Host, "0"))
+		if err != nil {
+			closeAll(reserved)
+			return nil, fmt.Errorf("bind simulated port: %w", err)
+		}
+		port := uint16(ln.Addr().(*net.TCPAddr).Port)
+		sim.Ports = append(sim.Ports, port)
+		sim.expected[port] = false
+		reserved = append(reserved, ln)
+	}
+	// Release closed ports only after every open port is bound so the
+	// kernel cannot hand one back to a listener.
+	defer closeAll(reserved)
+
+	if openCount > 0 {
+		services := make([]testserver.Service, openCount)
+		for i := range services {
+			services[i] = testserver.Service{
+				Protocol: "tcp",
+				Banner:   opts.Banner,
+				Delay:    opts.Latency,
+				DropRate: opts.DropRate,
+			}
+		}
+		server, err := testserver.Start(sim.Host, services)
+		if err != nil {
+			return nil, fmt.Errorf("bind simulated port: %w", err)
+		}
+		sim.server = server
+		for _, port := range server.Ports("tcp") {
+			sim.Ports = append(sim.Ports, port)
+			sim.expected[port] = true
+		}
+	}
+
+	rng := rand.New(rand.NewSource(seed)) // #nosec G404 - simulation only
+	rng.Shuffle(len(sim.Ports), func(i, j int) {
+		sim.Ports[i], sim.Ports[j] = sim.Ports[j], sim.Ports[i]
+	})
+	return sim, nil
+}
+
+func closeAll(listeners []net.Listener) {
+	for _, ln := range listeners {
+		_ = ln.Close()
+	}
+}
+
+// Expected reports whether port should be open.
+func (s *Simulator) Expected(port uint16) (open bool, known bool) {
+	open, known = s.expected[port]
+	return open, known
+}
+
+// OpenPorts reports how many simulated ports have a listener.
+func (s *Simulator) OpenPorts() int {
+	if s.server == nil {
+		return 0
+	}
+	return len(s.server.Ports("tcp"))
+}
+
+// Close stops all listeners and waits for their connections to finish.
+func (s *Simulator) Close() {
+	if s.server != nil {
+		s.server.Close()
+	}
+}
@@ -0,0 +1,78 @@
+package bench
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestStartSimulator_Layout(t *testing.T) {
+	sim, err := StartSimulator(SimulatorOptions{Ports: 20, OpenRatio: 0.25, Seed: 1})
+	if err != nil {
+		t.Fatalf("StartSimulator: %v", err)
+	}
+	defer sim.Close()
+
+	if len(sim.Ports) != 20 {
+		t.Fatalf("ports = %d, want 20", len(sim.Ports))
+	}
+	if sim.OpenPorts() != 5 {
+		t.Errorf("open ports = %d, want 5", sim.OpenPorts())
+	}
+
+	for _, port := range sim.Ports {
+		open, known := sim.Expected(port)
+		if !known {
+			t.Fatalf("port %d not in layout", port)
+		}
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(sim.Host, itoa(port)), time.Second)
+		if open && err != nil {
+			t.Errorf("open port %d refused: %v", port, err)
+		}
+		if !open && err == nil {
+			t.Errorf("closed port %d accepted a connection", port)
+		}
+		if conn != nil {
+			conn.Close()
+		}
+	}
+}
+
+func TestStartSimulator_Banner(t *testing.T) {
+	sim, err := StartSimulator(SimulatorOptions{Ports: 1, OpenRatio: 1, Banner: "HELLO\r\n", Seed: 1})
+	if err != nil {
+		t.Fatalf("StartSimulator: %v", err)
+	}
+	defer sim.Close()
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(sim.Host, itoa(sim.Ports[0])), time.Second)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 16)
+	n, _ := conn.Read(buf)
+	if got := string(buf[:n]); got != "HELLO\r\n" {
+		t.Errorf("banner = %q, want HELLO", got)
+	}
+}
+
+func TestStartSimulator_InvalidOptions(t *testing.T) {
+	cases := []SimulatorOptions{
+		{Ports: 0},
+		{Ports: 1, OpenRatio: 1.5},
+		{Ports: 1, DropRate: -0.1},
+	}
+	for _, opts := range cases {
+		if sim, err := StartSimulator(opts); err == nil {
+			sim.Close()
+			t.Errorf("StartSimulator(%+v) succeeded, want error", opts)
+		}
+	}
+}
+
+func itoa(port uint16) string {
+	return strconv.Itoa(int(port))
+}
@@ -0,0 +1,149 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultBandwidthInterval is how often a BandwidthGuard samples the
+// interface counters.
+const DefaultBandwidthInterval = 500 * time.Millisecond
+
+// TxCounters are cumulative transmit counters of network interfaces.
+type TxCounters struct {
+	Bytes   uint64
+	Packets uint64
+}
+
+// BandwidthLimits are ceilings on the host's outbound traffic. Zero is no
+// limit.
+type BandwidthLimits struct {
+	BitsPerSec    int64
+	PacketsPerSec int64
+}
+
+// BandwidthSample is the outbound traffic measured over one interval.
+type BandwidthSample struct {
+	BitsPerSec    float64
+	PacketsPerSec float64
+}
+
+// Exceeds reports whether s is over either limit.
+func (l BandwidthLimits) Exceeds(s BandwidthSample) bool {
+	return l.BitsPerSec > 0 && s.BitsPerSec > float64(l.BitsPerSec) ||
+		l.PacketsPerSec > 0 && s.PacketsPerSec > float64(l.PacketsPerSec)
+}
+
+// BandwidthOptions configures a BandwidthGuard.
+type BandwidthOptions struct {
+	Limits BandwidthLimits
+	// Interface is the interface to watch; empty sums every interface but
+	// loopback.
+	Interface string
+	// Interval is the sampling period. Defaults to DefaultBandwidthInterval
+	// when zero or negative.
+	Interval time.Duration
+	// OnChange, if set, is called when the guard pauses the scan (over is
+	// true) or resumes it, with the sample that decided it.
+	OnChange func(over bool, sample BandwidthSample)
+
+	read func() (TxCounters, error) // replaces ReadTxCounters in tests
+}
+
+// BandwidthGuard protects a shared host's uplink: it samples the host's
+// outbound traffic, all of it and not only the scan's, and pauses the scan
+// while it is over a limit. Pausing for an interval and resuming once the
+// traffic falls back slows the scan to about the ceiling.
+type BandwidthGuard struct {
+	opts    BandwidthOptions
+	control Pausable
+	read    func() (TxCounters, error)
+	last    TxCounters
+	lastAt  time.Time
+}
+
+// NewBandwidthGuard returns a guard pausing control, or an error if the
+// interface counters cannot be read.
+func NewBandwidthGuard(control Pausable, opts BandwidthOptions) (*BandwidthGuard, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultBandwidthInterval
+	}
+	read := opts.read
+	if read == nil {
+		iface := opts.Interface
+		read = func() (TxCounters, error) { return ReadTxCounters(iface) }
+	}
+	first, err := read()
+	if err != nil {
+		return nil, err
+	}
+	return &BandwidthGuard{opts: opts, control: control, read: read, last: first, lastAt: time.Now()}, nil
+}
+
+// Run samples the counters every interval until ctx is done. A pause the
+// guard holds ends once traffic is back under the limits; pauses made by
+// others are left alone.
+func (g *BandwidthGuard) Run(ctx context.Context) {
+	ticker := time.NewTicker(g.opts.Interval)
+	defer ticker.Stop()
+	hold := guardHold{control: g.control}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		sample, ok := g.sample(time.Now())
+		if !ok {
+			continue
+		}
+		if hold.set(g.opts.Limits.Exceeds(sample)) && g.opts.OnChange != nil {
+			g.opts.OnChange(hold.held, sample)
+		}
+	}
+}
+
+// sample reads the counters and returns the traffic since the last read.
+// It returns false when the counters cannot be read or went backwards, as
+// when an interface is reset.
+func (g *BandwidthGuard) sample(now time.Time) (BandwidthSample, bool) {
+	cur, err := g.read()
+	if err != nil {
+		return BandwidthSample{}, false
+	}
+	last, elapsed := g.last, now.Sub(g.lastAt).Seconds()
+	g.last, g.lastAt = cur, now
+	if cur.Bytes < last.Bytes || cur.Packets < last.Packets || elapsed <= 0 {
+		return BandwidthSample{}, false
+	}
+	return BandwidthSample{
+		BitsPerSec:    float64(cur.Bytes-last.Bytes) * 8 / elapsed,
+		PacketsPerSec: float64(cur.Packets-last.Packets) / elapsed,
+	}, true
+}
+
+// guardHold pauses a scan on behalf of a guard and resumes only the pauses
+// the guard made.
+type guardHold struct {
+	control Pausable
+	held    bool
+}
+
+// set pauses control while over and resumes it once not, returning true
+// when the guard starts or stops holding a pause. A scan resumed by hand
+// while held is paused again.
+func (h *guardHold) set(over bool) bool {
+	switch {
+	case over && !h.control.Paused():
+		h.control.Pause()
+		if !h.held {
+			h.held = true
+			return true
+		}
+	case !over && h.held:
+		h.held = false
+		h.control.Resume()
+		return true
+	}
+	return false
+}
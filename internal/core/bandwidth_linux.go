@@ -0,0 +1,69 @@
+//go:build linux
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// sysClassNet lists the interfaces and their statistics.
+const sysClassNet = "/sys/class/net"
+
+// ReadTxCounters returns the transmit counters of iface, or the sum over
+// every interface but loopback when iface is empty.
+func ReadTxCounters(iface string) (TxCounters, error) {
+	if iface != "" {
+		return readInterfaceTx(iface)
+	}
+	entries, err := os.ReadDir(sysClassNet)
+	if err != nil {
+		return TxCounters{}, fmt.Errorf("read interface counters: %w", err)
+	}
+	var total TxCounters
+	for _, entry := range entries {
+		if isLoopback(entry.Name()) {
+			continue
+		}
+		c, err := readInterfaceTx(entry.Name())
+		if err != nil {
+			continue
+		}
+		total.Bytes += c.Bytes
+		total.Packets += c.Packets
+	}
+	return total, nil
+}
+
+func readInterfaceTx(iface string) (TxCounters, error) {
+	if iface != filepath.Base(iface) {
+		return TxCounters{}, fmt.Errorf("invalid interface name %q", iface)
+	}
+	stats := filepath.Join(sysClassNet, iface, "statistics")
+	bytes, err := readCounter(filepath.Join(stats, "tx_bytes"))
+	if err != nil {
+		return TxCounters{}, fmt.Errorf("interface %s: %w", iface, err)
+	}
+	packets, err := readCounter(filepath.Join(stats, "tx_packets"))
+	if err != nil {
+		return TxCounters{}, fmt.Errorf("interface %s: %w", iface, err)
+	}
+	return TxCounters{Bytes: bytes, Packets: packets}, nil
+}
+
+func readCounter(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// isLoopback reports whether iface has the loopback link type (772).
+func isLoopback(iface string) bool {
+	data, err := os.ReadFile(filepath.Join(sysClassNet, iface, "type"))
+	return err == nil && strings.TrimSpace(string(data)) == "772"
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package core
+
+import "errors"
+
+// ReadTxCounters is not available on this platform.
+func ReadTxCounters(iface string) (TxCounters, error) {
+	return TxCounters{}, errors.New("interface counters are not supported on this platform")
+}
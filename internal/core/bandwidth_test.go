@@ -0,0 +1,104 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBandwidthLimitsExceeds(t *testing.T) {
+	limits := BandwidthLimits{BitsPerSec: 1e6, PacketsPerSec: 1000}
+	tests := []struct {
+		sample BandwidthSample
+		want   bool
+	}{
+		{BandwidthSample{BitsPerSec: 5e5, PacketsPerSec: 500}, false},
+		{BandwidthSample{BitsPerSec: 2e6, PacketsPerSec: 500}, true},
+		{BandwidthSample{BitsPerSec: 5e5, PacketsPerSec: 2000}, true},
+	}
+	for _, tt := range tests {
+		if got := limits.Exceeds(tt.sample); got != tt.want {
+			t.Errorf("Exceeds(%+v) = %v, want %v", tt.sample, got, tt.want)
+		}
+	}
+	if (BandwidthLimits{}).Exceeds(BandwidthSample{BitsPerSec: 1e12, PacketsPerSec: 1e9}) {
+		t.Error("zero limits should never be exceeded")
+	}
+}
+
+func TestBandwidthGuardPausesWhileOverLimit(t *testing.T) {
+	var mu sync.Mutex
+	var counters TxCounters
+	var perRead atomic.Uint64 // packets sent between reads
+	read := func() (TxCounters, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		n := perRead.Load()
+		counters.Packets += n
+		counters.Bytes += n * 100
+		return counters, nil
+	}
+	var changes atomic.Int32
+	gate := NewPauseGate()
+	guard, err := NewBandwidthGuard(gate, BandwidthOptions{
+		Limits:   BandwidthLimits{PacketsPerSec: 1000},
+		Interval: 5 * time.Millisecond,
+		OnChange: func(bool, BandwidthSample) { changes.Add(1) },
+		read:     read,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go guard.Run(ctx)
+
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	perRead.Store(1000) // 1000 packets per 5ms is 200000 pps
+	waitFor("the guard to pause", gate.Paused)
+	perRead.Store(0)
+	waitFor("the guard to resume", func() bool { return !gate.Paused() })
+	if got := changes.Load(); got != 2 {
+		t.Errorf("OnChange called %d times, want 2", got)
+	}
+
+	// A pause the guard did not make is left alone.
+	gate.Pause()
+	time.Sleep(30 * time.Millisecond)
+	if !gate.Paused() {
+		t.Error("the guard resumed a pause it did not make")
+	}
+}
+
+func TestBandwidthGuardSkipsCounterResets(t *testing.T) {
+	readings := []TxCounters{{Bytes: 1000, Packets: 10}, {Bytes: 500, Packets: 5}, {Bytes: 1500, Packets: 15}}
+	read := func() (TxCounters, error) {
+		c := readings[0]
+		readings = readings[1:]
+		return c, nil
+	}
+	guard, err := NewBandwidthGuard(NewPauseGate(), BandwidthOptions{read: read})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := guard.lastAt
+	if _, ok := guard.sample(start.Add(time.Second)); ok {
+		t.Error("a counter that went backwards should not give a sample")
+	}
+	sample, ok := guard.sample(start.Add(2 * time.Second))
+	if !ok || sample.BitsPerSec != 8000 || sample.PacketsPerSec != 10 {
+		t.Errorf("sample = %+v, %v, want 8000 bps and 10 pps", sample, ok)
+	}
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultConntrackInterval is how often a ConntrackGuard reads the
+	// table's fill.
+	DefaultConntrackInterval = time.Second
+	// conntrackResumeGap is how far under its limit, as a fraction of the
+	// table, the fill must fall before a paused scan resumes. Entries of
+	// unanswered probes linger for minutes, so resuming right under the
+	// limit would pause again at once.
+	conntrackResumeGap = 0.1
+)
+
+// ErrConntrackUnavailable reports that the host has no netfilter
+// connection tracking table to watch.
+var ErrConntrackUnavailable = errors.New("netfilter connection tracking is not available")
+
+// ConntrackUsage is the fill of the netfilter connection tracking table.
+type ConntrackUsage struct {
+	Count int
+	Max   int
+}
+
+// Fraction returns the share of the table in use, from 0 to 1.
+func (u ConntrackUsage) Fraction() float64 {
+	if u.Max <= 0 {
+		return 0
+	}
+	return float64(u.Count) / float64(u.Max)
+}
+
+// ConntrackOptions configures a ConntrackGuard.
+type ConntrackOptions struct {
+	// Limit is the fill, from 0 to 1, at which the scan pauses. It resumes
+	// once the fill drops conntrackResumeGap below it.
+	Limit float64
+	// Interval is the sampling period. Defaults to DefaultConntrackInterval
+	// when zero or negative.
+	Interval time.Duration
+	// OnWarn, if set, is called when the fill first reaches the resume
+	// level on its way to the limit, and again after each time it falls
+	// back under it.
+	OnWarn func(usage ConntrackUsage)
+	// OnChange, if set, is called when the guard pauses the scan (paused
+	// is true) or resumes it.
+	OnChange func(paused bool, usage ConntrackUsage)
+
+	read func() (ConntrackUsage, error) // replaces ReadConntrack in tests
+}
+
+// ConntrackGuard keeps a fast scan from exhausting the connection tracking
+// table of a host behind a stateful firewall. Every probe takes an entry,
+// and once the table is full the kernel drops new connections, which the
+// scan would report as filtered ports. The guard pauses the scan while the
+// table is nearly full.
+type ConntrackGuard struct {
+	opts   ConntrackOptions
+	read   func() (ConntrackUsage, error)
+	hold   guardHold
+	warned bool
+}
+
+// NewConntrackGuard returns a guard pausing control, or
+// ErrConntrackUnavailable when there is no table to watch.
+func NewConntrackGuard(control Pausable, opts ConntrackOptions) (*ConntrackGuard, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultConntrackInterval
+	}
+	read := opts.read
+	if read == nil {
+		read = ReadConntrack
+	}
+	if _, err := read(); err != nil {
+		return nil, err
+	}
+	return &ConntrackGuard{opts: opts, read: read, hold: guardHold{control: control}}, nil
+}
+
+// Run samples the table every interval until ctx is done. A pause the
+// guard holds ends once the fill drops back; pauses made by others are
+// left alone.
+func (g *ConntrackGuard) Run(ctx context.Context) {
+	ticker := time.NewTicker(g.opts.Interval)
+	defer ticker.Stop()
+	for {
+		if usage, err := g.read(); err == nil {
+			g.check(usage)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check pauses or resumes the scan for one reading of the table.
+func (g *ConntrackGuard) check(usage ConntrackUsage) {
+	fill := usage.Fraction()
+	resumeAt := g.opts.Limit - conntrackResumeGap
+	if fill < resumeAt {
+		g.warned = false
+	} else if !g.warned {
+		g.warned = true
+		if g.opts.OnWarn != nil {
+			g.opts.OnWarn(usage)
+		}
+	}
+	over := fill >= g.opts.Limit || g.hold.held && fill >= resumeAt
+	if g.hold.set(over) && g.opts.OnChange != nil {
+		g.opts.OnChange(g.hold.held, usage)
+	}
+}
@@ -0,0 +1,40 @@
+//go:build linux
+
+package core
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ReadConntrack returns the netfilter connection tracking table's fill, or
+// ErrConntrackUnavailable when the nf_conntrack module is not loaded.
+func ReadConntrack() (ConntrackUsage, error) {
+	count, err := readProcInt("/proc/sys/net/netfilter/nf_conntrack_count")
+	if err != nil {
+		return ConntrackUsage{}, conntrackError(err)
+	}
+	limit, err := readProcInt("/proc/sys/net/netfilter/nf_conntrack_max")
+	if err != nil {
+		return ConntrackUsage{}, conntrackError(err)
+	}
+	return ConntrackUsage{Count: count, Max: limit}, nil
+}
+
+func conntrackError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrConntrackUnavailable
+	}
+	return err
+}
+
+func readProcInt(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package core
+
+// ReadConntrack reports ErrConntrackUnavailable: connection tracking is
+// only read on Linux.
+func ReadConntrack() (ConntrackUsage, error) {
+	return ConntrackUsage{}, ErrConntrackUnavailable
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestConntrackGuardHysteresis(t *testing.T) {
+	var warnings int
+	var changes []bool
+	gate := NewPauseGate()
+	guard, err := NewConntrackGuard(gate, ConntrackOptions{
+		Limit:    0.9,
+		OnWarn:   func(ConntrackUsage) { warnings++ },
+		OnChange: func(paused bool, _ ConntrackUsage) { changes = append(changes, paused) },
+		read:     func() (ConntrackUsage, error) { return ConntrackUsage{Max: 1000}, nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := []struct {
+		count  int
+		paused bool
+	}{
+		{500, false},
+		{850, false}, // warns
+		{900, true},
+		{850, true}, // held until under 80%
+		{790, false},
+		{850, false}, // warns again
+		{950, true},
+	}
+	for _, step := range steps {
+		guard.check(ConntrackUsage{Count: step.count, Max: 1000})
+		if gate.Paused() != step.paused {
+			t.Fatalf("at %d/1000 paused = %v, want %v", step.count, gate.Paused(), step.paused)
+		}
+	}
+	if warnings != 2 {
+		t.Errorf("OnWarn called %d times, want 2", warnings)
+	}
+	if want := []bool{true, false, true}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("OnChange calls = %v, want %v", changes, want)
+	}
+
+	// A pause the guard did not make is left alone.
+	gate = NewPauseGate()
+	guard.hold = guardHold{control: gate}
+	gate.Pause()
+	guard.check(ConntrackUsage{Count: 10, Max: 1000})
+	if !gate.Paused() {
+		t.Error("the guard resumed a pause it did not make")
+	}
+}
+
+func TestNewConntrackGuardUnavailable(t *testing.T) {
+	_, err := NewConntrackGuard(NewPauseGate(), ConntrackOptions{
+		Limit: 0.9,
+		read:  func() (ConntrackUsage, error) { return ConntrackUsage{}, ErrConntrackUnavailable },
+	})
+	if !errors.Is(err, ErrConntrackUnavailable) {
+		t.Errorf("err = %v, want ErrConntrackUnavailable", err)
+	}
+}
@@ -36,12 +36,20 @@ const (
 
 	// BannerBufferSize is the buffer size for reading service banners
 	BannerBufferSize = 512
+
+	// DefaultBannerWorkers is the default number of concurrent banner reads
+	DefaultBannerWorkers = 50
 )
 
 // Progress reporting configuration
 const (
 	// ProgressReportInterval is how often to report progress updates
 	ProgressReportInterval = 100 * time.Millisecond
+	// ProgressRateWindow is the span of active scanning time that
+	// ProgressEvent.RecentRate averages over.
+	ProgressRateWindow = 10 * time.Second
+	// ProgressHostLimit caps the hosts listed in ProgressEvent.Hosts.
+	ProgressHostLimit = 10
 )
 
 // Retry backoff configuration
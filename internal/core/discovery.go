@@ -0,0 +1,470 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/xml"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDiscoveryTimeout bounds each local discovery query, and how long
+// SSDP replies are collected.
+const DefaultDiscoveryTimeout = 2 * time.Second
+
+var (
+	mdnsGroup = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+	ssdpGroup = &net.UDPAddr{IP: net.IPv4(239, 255, 255, 250), Port: 1900}
+)
+
+// ssdpSearch asks every UPnP root device on the segment to announce its
+// description URL.
+var ssdpSearch = []byte("M-SEARCH * HTTP/1.1\r\nHOST: 239.255.255.250:1900\r\nMAN: \"ssdp:discover\"\r\nMX: 1\r\nST: upnp:rootdevice\r\n\r\n")
+
+// DiscoveryOptions configures local device discovery.
+type DiscoveryOptions struct {
+	Timeout time.Duration // per-query wait; defaults to DefaultDiscoveryTimeout
+	Workers int           // concurrent host lookups; defaults to 16
+}
+
+// deviceInfo is what local discovery learned about one host.
+type deviceInfo struct {
+	name string // mDNS or NetBIOS name, else the UPnP friendly name
+	kind string // UPnP device type, e.g. "MediaRenderer"
+}
+
+// localDiscovery queries each private host once and shares the answer
+// between all of its results.
+type localDiscovery struct {
+	opts DiscoveryOptions
+
+	mu    sync.Mutex
+	hosts map[string]*hostDiscovery
+
+	ssdpOnce sync.Once
+	ssdpDone chan struct{}
+	ssdp     map[string]string // responder address -> description URL
+}
+
+type hostDiscovery struct {
+	once sync.Once
+	info deviceInfo
+}
+
+// DiscoverLocal names the devices behind results for RFC 1918 hosts: it
+// asks each host for its mDNS name (a reverse PTR query to the multicast
+// group) and NetBIOS name (a node status query), and sends one SSDP search
+// whose answers give UPnP device types. Every result for a host carries
+// the same DeviceName and DeviceType; results for other hosts pass through
+// unchanged. The returned channel is closed once events is closed and
+// every lookup has finished.
+func DiscoverLocal(ctx context.Context, events <-chan Event, opts DiscoveryOptions) <-chan Event {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultDiscoveryTimeout
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 16
+	}
+	d := &localDiscovery{
+		opts:     opts,
+		hosts:    make(map[string]*hostDiscovery),
+		ssdpDone: make(chan struct{}),
+		ssdp:     make(map[string]string),
+	}
+
+	out := make(chan Event, cap(events))
+	jobs := make(chan ResultEvent)
+	var wg sync.WaitGroup
+
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for result := range jobs {
+				info := d.lookup(ctx, result.Host)
+				result.DeviceName, result.DeviceType = info.name, info.kind
+				select {
+				case out <- NewResultEvent(result):
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer func() {
+			close(jobs)
+			wg.Wait()
+			close(out)
+		}()
+		for event := range events {
+			if event.Kind == EventKindResult && isRFC1918(event.Result.Host) {
+				select {
+				case jobs <- *event.Result:
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// isRFC1918 reports whether host is an IPv4 address in 10/8, 172.16/12 or
+// 192.168/16, where multicast and broadcast discovery can reach it.
+func isRFC1918(host string) bool {
+	ip := net.ParseIP(host).To4()
+	return ip != nil && ip.IsPrivate()
+}
+
+// lookup returns what is known about host, querying it on first use.
+func (d *localDiscovery) lookup(ctx context.Context, host string) deviceInfo {
+	d.mu.Lock()
+	h, ok := d.hosts[host]
+	if !ok {
+		h = &hostDiscovery{}
+		d.hosts[host] = h
+	}
+	d.mu.Unlock()
+
+	h.once.Do(func() { h.info = d.query(ctx, host) })
+	return h.info
+}
+
+// query asks host for its names and matches it against the SSDP replies.
+// A name from mDNS wins over NetBIOS, and either over the UPnP friendly
+// name.
+func (d *localDiscovery) query(ctx context.Context, host string) deviceInfo {
+	d.ssdpOnce.Do(func() { go d.searchSSDP(ctx) })
+
+	var mdnsName, netbiosName string
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		mdnsName = queryMDNSName(ctx, host, d.opts.Timeout)
+	}()
+	go func() {
+		defer wg.Done()
+		netbiosName = queryNetBIOSName(ctx, host, d.opts.Timeout)
+	}()
+	wg.Wait()
+
+	info := deviceInfo{name: mdnsName}
+	if info.name == "" {
+		info.name = netbiosName
+	}
+
+	select {
+	case <-d.ssdpDone:
+	case <-ctx.Done():
+		return info
+	}
+	if location := d.ssdp[host]; location != "" {
+		friendly, kind := fetchUPnPDescription(ctx, location, d.opts.Timeout)
+		info.kind = kind
+		if info.name == "" {
+			info.name = friendly
+		}
+	}
+	return info
+}
+
+// searchSSDP multicasts one M-SEARCH and records the description URL of
+// each device that answers within the timeout.
+func (d *localDiscovery) searchSSDP(ctx context.Context) {
+	defer close(d.ssdpDone)
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	if _, err := conn.WriteTo(ssdpSearch, ssdpGroup); err != nil {
+		return
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(d.opts.Timeout))
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	buf := make([]byte, 2048)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		host := from.IP.String()
+		if _, seen := d.ssdp[host]; seen {
+			continue
+		}
+		if location := parseSSDPLocation(buf[:n], host); location != "" {
+			d.ssdp[host] = location
+		}
+	}
+}
+
+// parseSSDPLocation returns the LOCATION of an SSDP reply when it is a
+// plain HTTP URL on the responder itself; descriptions hosted elsewhere are
+// not fetched.
+func parseSSDPLocation(reply []byte, from string) string {
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(reply)), nil)
+	if err != nil {
+		return ""
+	}
+	_ = resp.Body.Close()
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || location.Scheme != "http" || location.Hostname() != from {
+		return ""
+	}
+	return location.String()
+}
+
+// upnpDescription is the part of a UPnP device description that discovery
+// reads.
+type upnpDescription struct {
+	Device struct {
+		DeviceType   string `xml:"deviceType"`
+		FriendlyName string `xml:"friendlyName"`
+	} `xml:"device"`
+}
+
+// fetchUPnPDescription downloads a device description and returns its
+// friendly name and short device type.
+func fetchUPnPDescription(ctx context.Context, location string, timeout time.Duration) (name, kind string) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return "", ""
+	}
+	req.Header.Set("User-Agent", "portscan")
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", ""
+	}
+	return parseUPnPDescription(io.LimitReader(resp.Body, 64<<10))
+}
+
+// parseUPnPDescription reads a device description. The device type
+// "urn:schemas-upnp-org:device:MediaRenderer:1" is shortened to
+// "MediaRenderer".
+func parseUPnPDescription(r io.Reader) (name, kind string) {
+	var desc upnpDescription
+	if err := xml.NewDecoder(r).Decode(&desc); err != nil {
+		return "", ""
+	}
+	kind = strings.TrimSpace(desc.Device.DeviceType)
+	if parts := strings.Split(kind, ":"); len(parts) == 5 && parts[2] == "device" {
+		kind = parts[3]
+	}
+	return strings.TrimSpace(desc.Device.FriendlyName), kind
+}
+
+// queryMDNSName asks for host's reverse PTR record, both through the
+// multicast group and directly, and returns the name it answers with,
+// without the ".local" suffix.
+func queryMDNSName(ctx context.Context, host string, timeout time.Duration) string {
+	ip := net.ParseIP(host).To4()
+	if ip == nil {
+		return ""
+	}
+	reverse := strconv.Itoa(int(ip[3])) + "." + strconv.Itoa(int(ip[2])) + "." +
+		strconv.Itoa(int(ip[1])) + "." + strconv.Itoa(int(ip[0])) + ".in-addr.arpa"
+	query := buildMDNSQuery(reverse)
+
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return ""
+	}
+	defer conn.Close()
+	_, _ = conn.WriteTo(query, mdnsGroup)
+	_, _ = conn.WriteTo(query, &net.UDPAddr{IP: ip, Port: 5353})
+	_ = conn.SetReadDeadline(time.Now().Add(timeout))
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	buf := make([]byte, 1500)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return ""
+		}
+		if !from.IP.Equal(ip) {
+			continue
+		}
+		if name := parseMDNSPTR(buf[:n], reverse); name != "" {
+			return name
+		}
+	}
+}
+
+// buildMDNSQuery encodes a PTR question for name. The class requests a
+// unicast reply, so answers come back to the querying socket.
+func buildMDNSQuery(name string) []byte {
+	msg := []byte{
+		0x70, 0x73, // ID, echoed by legacy unicast replies
+		0x00, 0x00, // flags: standard query
+		0x00, 0x01, // one question
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+	for _, label := range strings.Split(name, ".") {
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	return append(msg, 0x00,
+		0x00, 0x0c, // type PTR
+		0x80, 0x01, // class IN, unicast response
+	)
+}
+
+// parseMDNSPTR returns the target of the first PTR answer for name in an
+// mDNS response.
+func parseMDNSPTR(msg []byte, name string) string {
+	if len(msg) < 12 || msg[2]&0x80 == 0 {
+		return ""
+	}
+	questions := int(binary.BigEndian.Uint16(msg[4:6]))
+	answers := int(binary.BigEndian.Uint16(msg[6:8])) + int(binary.BigEndian.Uint16(msg[8:10])) +
+		int(binary.BigEndian.Uint16(msg[10:12]))
+	off := 12
+	for i := 0; i < questions; i++ {
+		_, next, ok := readDNSName(msg, off)
+		if !ok || next+4 > len(msg) {
+			return ""
+		}
+		off = next + 4
+	}
+	for i := 0; i < answers; i++ {
+		owner, next, ok := readDNSName(msg, off)
+		if !ok || next+10 > len(msg) {
+			return ""
+		}
+		rrType := binary.BigEndian.Uint16(msg[next : next+2])
+		length := int(binary.BigEndian.Uint16(msg[next+8 : next+10]))
+		data := next + 10
+		if data+length > len(msg) {
+			return ""
+		}
+		if rrType == 0x0c && strings.EqualFold(owner, name) {
+			if target, _, ok := readDNSName(msg, data); ok {
+				return strings.TrimSuffix(target, ".local")
+			}
+		}
+		off = data + length
+	}
+	return ""
+}
+
+// readDNSName decodes the possibly compressed name at off, returning it
+// without the trailing dot and the offset just past it.
+func readDNSName(msg []byte, off int) (string, int, bool) {
+	var labels []string
+	end := -1
+	for jumps := 0; jumps < 16; {
+		if off >= len(msg) {
+			return "", 0, false
+		}
+		length := int(msg[off])
+		switch {
+		case length == 0:
+			if end < 0 {
+				end = off + 1
+			}
+			return strings.Join(labels, "."), end, true
+		case length&0xc0 == 0xc0:
+			if off+1 >= len(msg) {
+				return "", 0, false
+			}
+			if end < 0 {
+				end = off + 2
+			}
+			off = int(binary.BigEndian.Uint16(msg[off:off+2]) & 0x3fff)
+			jumps++
+		default:
+			if off+1+length > len(msg) {
+				return "", 0, false
+			}
+			labels = append(labels, string(msg[off+1:off+1+length]))
+			off += 1 + length
+		}
+	}
+	return "", 0, false
+}
+
+// queryNetBIOSName sends a node status request to host and returns its
+// workstation name.
+func queryNetBIOSName(ctx context.Context, host string, timeout time.Duration) string {
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "udp4", net.JoinHostPort(host, "137"))
+	if err != nil {
+		return ""
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if _, err := conn.Write(buildNetBIOSProbe()); err != nil {
+		return ""
+	}
+	buf := make([]byte, 1500)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return ""
+	}
+	return parseNetBIOSName(buf[:n])
+}
+
+// parseNetBIOSName returns the first unique workstation (<00>) name in a
+// node status response.
+func parseNetBIOSName(data []byte) string {
+	if len(data) < 12 || data[2]&0x80 == 0 {
+		return ""
+	}
+	off := 12
+	switch {
+	case off < len(data) && data[off] == 0x20:
+		off += 34
+	case off+1 < len(data) && data[off]&0xc0 == 0xc0:
+		off += 2
+	default:
+		return ""
+	}
+	off += 10 // type, class, TTL and data length
+	if off >= len(data) {
+		return ""
+	}
+	count := int(data[off])
+	off++
+	for i := 0; i < count && off+18 <= len(data); i, off = i+1, off+18 {
+		entry := data[off : off+18]
+		group := binary.BigEndian.Uint16(entry[16:18])&0x8000 != 0
+		if entry[15] == 0x00 && !group {
+			return strings.TrimRight(string(entry[:15]), " \x00")
+		}
+	}
+	return ""
+}
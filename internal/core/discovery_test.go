@@ -0,0 +1,130 @@
+package core
+
+import (
+	"context"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestIsRFC1918(t *testing.T) {
+	for host, want := range map[string]bool{
+		"10.1.2.3":    true,
+		"172.16.0.1":  true,
+		"172.32.0.1":  false,
+		"192.168.1.1": true,
+		"8.8.8.8":     false,
+		"fd00::1":     false,
+		"printer.lan": false,
+	} {
+		if got := isRFC1918(host); got != want {
+			t.Errorf("isRFC1918(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestParseNetBIOSName(t *testing.T) {
+	reply := []byte{0x00, 0x00, 0x84, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00}
+	reply = append(reply, buildNetBIOSProbe()[12:46]...)                  // the queried name
+	reply = append(reply, 0x00, 0x21, 0x00, 0x01, 0, 0, 0, 0, 0x00, 0x41) // NBSTAT IN, TTL, length
+	reply = append(reply, 3)
+	for _, entry := range []struct {
+		name   string
+		suffix byte
+		flags  uint16
+	}{
+		{"WORKGROUP", 0x00, 0x8400}, // group name
+		{"FILESRV", 0x20, 0x0400},
+		{"FILESRV", 0x00, 0x0400},
+	} {
+		reply = append(reply, []byte(entry.name+strings.Repeat(" ", 15-len(entry.name)))...)
+		reply = append(reply, entry.suffix)
+		reply = binary.BigEndian.AppendUint16(reply, entry.flags)
+	}
+
+	if got := parseNetBIOSName(reply); got != "FILESRV" {
+		t.Errorf("parseNetBIOSName() = %q, want the unique workstation name", got)
+	}
+	if got := parseNetBIOSName(reply[:60]); got != "" {
+		t.Errorf("truncated reply gave %q", got)
+	}
+}
+
+func TestParseMDNSPTR(t *testing.T) {
+	query := buildMDNSQuery("20.1.168.192.in-addr.arpa")
+	if name, _, ok := readDNSName(query, 12); !ok || name != "20.1.168.192.in-addr.arpa" {
+		t.Fatalf("query name = %q, %v", name, ok)
+	}
+
+	// A reply echoing the question, with an answer whose owner points back
+	// at it.
+	reply := append([]byte(nil), query...)
+	reply[2] = 0x84
+	binary.BigEndian.PutUint16(reply[6:8], 1)
+	reply = append(reply, 0xc0, 12, 0x00, 0x0c, 0x00, 0x01, 0, 0, 0x00, 0x78)
+	target := []byte("\x0bliving-room\x05local\x00")
+	reply = binary.BigEndian.AppendUint16(reply, uint16(len(target)))
+	reply = append(reply, target...)
+
+	if got := parseMDNSPTR(reply, "20.1.168.192.in-addr.arpa"); got != "living-room" {
+		t.Errorf("parseMDNSPTR() = %q, want living-room", got)
+	}
+	if got := parseMDNSPTR(reply, "21.1.168.192.in-addr.arpa"); got != "" {
+		t.Errorf("answer for another name gave %q", got)
+	}
+	if got := parseMDNSPTR(query, "20.1.168.192.in-addr.arpa"); got != "" {
+		t.Errorf("a query is not a reply, got %q", got)
+	}
+
+	loop := []byte{0, 0, 0x84, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0xc0, 12}
+	if _, _, ok := readDNSName(loop, 12); ok {
+		t.Error("a compression loop should not decode")
+	}
+}
+
+func TestParseSSDPLocation(t *testing.T) {
+	reply := "HTTP/1.1 200 OK\r\nCACHE-CONTROL: max-age=1800\r\nLOCATION: http://192.168.1.20:1400/xml/device_description.xml\r\nST: upnp:rootdevice\r\n\r\n"
+	if got := parseSSDPLocation([]byte(reply), "192.168.1.20"); got != "http://192.168.1.20:1400/xml/device_description.xml" {
+		t.Errorf("parseSSDPLocation() = %q", got)
+	}
+	if got := parseSSDPLocation([]byte(reply), "192.168.1.21"); got != "" {
+		t.Errorf("a description on another host should be ignored, got %q", got)
+	}
+	if got := parseSSDPLocation([]byte("NOTIFY * HTTP/1.1\r\n\r\n"), "192.168.1.20"); got != "" {
+		t.Errorf("non-response gave %q", got)
+	}
+}
+
+func TestParseUPnPDescription(t *testing.T) {
+	desc := `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:MediaRenderer:1</deviceType>
+    <friendlyName>Living Room</friendlyName>
+  </device>
+</root>`
+	name, kind := parseUPnPDescription(strings.NewReader(desc))
+	if name != "Living Room" || kind != "MediaRenderer" {
+		t.Errorf("parseUPnPDescription() = %q, %q", name, kind)
+	}
+}
+
+func TestDiscoverLocalPassesPublicHosts(t *testing.T) {
+	events := make(chan Event, 2)
+	events <- NewResultEvent(ResultEvent{Host: "8.8.8.8", Port: 53, State: StateOpen})
+	events <- NewResultEvent(ResultEvent{Host: "dns.example", Port: 53, State: StateOpen})
+	close(events)
+
+	var got []ResultEvent
+	for event := range DiscoverLocal(context.Background(), events, DiscoveryOptions{}) {
+		got = append(got, *event.Result)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d results, want 2", len(got))
+	}
+	for _, r := range got {
+		if r.DeviceName != "" || r.DeviceType != "" {
+			t.Errorf("%s should not be discovered: %+v", r.Host, r)
+		}
+	}
+}
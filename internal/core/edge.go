@@ -0,0 +1,229 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultEdgeTimeout bounds each CDN/WAF detection request.
+const DefaultEdgeTimeout = 3 * time.Second
+
+// edgeTLSPorts are the web ports probed over HTTPS; the other edge ports
+// are probed over plain HTTP.
+var edgeTLSPorts = map[uint16]bool{443: true, 8443: true}
+
+// edgePorts are the web ports checked for a CDN or WAF front end.
+var edgePorts = map[uint16]bool{80: true, 443: true, 8080: true, 8443: true}
+
+// EdgeOptions configures CDN/WAF front-end detection.
+type EdgeOptions struct {
+	Timeout time.Duration // per-request timeout; defaults to DefaultEdgeTimeout
+	Workers int           // concurrent requests; defaults to 16
+	// Resolver picks the address a hostname result's request goes to, so
+	// the front end checked is the one the scan reached; nil dials by name.
+	Resolver HostResolver
+}
+
+// edgeRule recognizes a provider from a response header, optionally
+// requiring its value to contain a substring (compared in lower case).
+type edgeRule struct {
+	provider string
+	header   string
+	contains string
+}
+
+// edgeHeaderRules are checked in order; the first match names the edge.
+var edgeHeaderRules = []edgeRule{
+	{"Cloudflare", "Cf-Ray", ""},
+	{"Cloudflare", "Server", "cloudflare"},
+	{"Akamai", "Server", "akamaighost"},
+	{"Akamai", "Server", "akamainetstorage"},
+	{"Akamai", "Akamai-Grn", ""},
+	{"Akamai", "X-Akamai-Transformed", ""},
+	{"Fastly", "X-Fastly-Request-Id", ""},
+	{"Fastly", "Fastly-Debug-Digest", ""},
+	{"Fastly", "X-Served-By", "cache-"},
+	{"Amazon CloudFront", "X-Amz-Cf-Id", ""},
+	{"Amazon CloudFront", "Via", "cloudfront"},
+	{"Imperva Incapsula", "X-Iinfo", ""},
+	{"Imperva Incapsula", "X-Cdn", "incapsula"},
+	{"Sucuri", "X-Sucuri-Id", ""},
+	{"Sucuri", "Server", "sucuri"},
+	{"Azure Front Door", "X-Azure-Ref", ""},
+}
+
+// edgeSANSuffixes recognize a provider from the names in its default
+// certificates.
+var edgeSANSuffixes = []struct {
+	provider string
+	suffix   string
+}{
+	{"Cloudflare", ".cloudflaressl.com"},
+	{"Akamai", ".akamaized.net"},
+	{"Akamai", ".akamai.net"},
+	{"Akamai", ".edgekey.net"},
+	{"Fastly", ".fastly.net"},
+	{"Fastly", ".fastlylb.net"},
+	{"Amazon CloudFront", ".cloudfront.net"},
+	{"Imperva Incapsula", ".incapdns.net"},
+	{"Azure Front Door", ".azurefd.net"},
+}
+
+// identifyEdge names the CDN or WAF provider revealed by response headers
+// or certificate names, or returns "" when there is no sign of one.
+func identifyEdge(header http.Header, sans []string) string {
+	for _, rule := range edgeHeaderRules {
+		values := header.Values(rule.header)
+		if len(values) == 0 {
+			continue
+		}
+		if rule.contains == "" {
+			return rule.provider
+		}
+		for _, v := range values {
+			if strings.Contains(strings.ToLower(v), rule.contains) {
+				return rule.provider
+			}
+		}
+	}
+	for _, rule := range edgeSANSuffixes {
+		for _, san := range sans {
+			if strings.HasSuffix(strings.ToLower(san), rule.suffix) {
+				return rule.provider
+			}
+		}
+	}
+	return ""
+}
+
+// DetectEdge sends an HTTP request to every open web port (80, 443, 8080
+// and 8443) in events and sets Edge when the response headers or the
+// certificate show a CDN or WAF front end, so users know they reached an
+// edge rather than the origin. Other events pass through unchanged. The
+// returned channel is closed once events is closed and every request has
+// finished.
+func DetectEdge(ctx context.Context, events <-chan Event, opts EdgeOptions) <-chan Event {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultEdgeTimeout
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 16
+	}
+
+	out := make(chan Event, cap(events))
+	jobs := make(chan ResultEvent)
+	var wg sync.WaitGroup
+
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for result := range jobs {
+				result.Edge = probeEdge(ctx, opts, result, edgeTLSPorts[result.Port])
+				select {
+				case out <- NewResultEvent(result):
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer func() {
+			close(jobs)
+			wg.Wait()
+			close(out)
+		}()
+		for event := range events {
+			if event.Kind == EventKindResult && needsEdgeProbe(*event.Result) {
+				select {
+				case jobs <- *event.Result:
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+func needsEdgeProbe(r ResultEvent) bool {
+	return r.State == StateOpen && (r.Protocol == "" || r.Protocol == "tcp") && edgePorts[r.Port]
+}
+
+// probeEdge requests "/" from the result's port, over TLS when useTLS is
+// set, and identifies the edge from the response. A failed request finds
+// no edge.
+func probeEdge(ctx context.Context, opts EdgeOptions, r ResultEvent, useTLS bool) string {
+	address, err := dialAddress(ctx, opts.Resolver, r.Host, r.Port)
+	if err != nil {
+		return ""
+	}
+	// Ask for the name the target was given as, so virtual hosts and SNI
+	// route to the site rather than the edge's default.
+	name := r.Hostname
+	if name == "" {
+		name = r.Host
+	}
+
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, address)
+		},
+		// The certificate is inspected, not trusted: edges commonly
+		// present certificates for other names when reached by address.
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		DisableKeepAlives: true,
+	}
+	defer transport.CloseIdleConnections()
+	if net.ParseIP(name) == nil {
+		transport.TLSClientConfig.ServerName = name
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   opts.Timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+	hostport := net.JoinHostPort(name, strconv.Itoa(int(r.Port)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+hostport+"/", nil)
+	if err != nil {
+		return ""
+	}
+	req.Header.Set("User-Agent", "portscan")
+	resp, err := client.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	var sans []string
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		leaf := resp.TLS.PeerCertificates[0]
+		sans = append(sans, leaf.DNSNames...)
+		sans = append(sans, leaf.Subject.CommonName)
+	}
+	return identifyEdge(resp.Header, sans)
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestIdentifyEdge(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		sans   []string
+		want   string
+	}{
+		{"cloudflare ray", http.Header{"Cf-Ray": {"8a1b2c3d4e5f-AMS"}}, nil, "Cloudflare"},
+		{"cloudflare server", http.Header{"Server": {"cloudflare"}}, nil, "Cloudflare"},
+		{"akamai server", http.Header{"Server": {"AkamaiGHost"}}, nil, "Akamai"},
+		{"fastly cache", http.Header{"X-Served-By": {"cache-ams21042-AMS"}}, nil, "Fastly"},
+		{"cloudfront via", http.Header{"Via": {"1.1 abc.cloudfront.net (CloudFront)"}}, nil, "Amazon CloudFront"},
+		{"certificate name", http.Header{"Server": {"nginx"}}, []string{"a248.e.akamai.net"}, "Akamai"},
+		{"fastly certificate", nil, []string{"*.global.ssl.fastly.net"}, "Fastly"},
+		{"origin", http.Header{"Server": {"nginx/1.25"}, "X-Served-By": {"web01"}}, []string{"www.example.com"}, ""},
+	}
+	for _, tt := range tests {
+		if got := identifyEdge(tt.header, tt.sans); got != tt.want {
+			t.Errorf("%s: identifyEdge() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// serverResult returns an open result for the test server's port.
+func serverResult(t *testing.T, rawURL string) ResultEvent {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("parse %s: %v", rawURL, err)
+	}
+	port, _ := strconv.Atoi(u.Port())
+	return ResultEvent{Host: u.Hostname(), Port: uint16(port), State: StateOpen}
+}
+
+func TestProbeEdge(t *testing.T) {
+	edge := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "cloudflare")
+		w.Header().Set("Location", "https://www.example.com/")
+		w.WriteHeader(http.StatusMovedPermanently)
+	}))
+	defer edge.Close()
+	origin := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "nginx")
+	}))
+	defer origin.Close()
+
+	opts := EdgeOptions{Timeout: time.Second}
+	if got := probeEdge(context.Background(), opts, serverResult(t, edge.URL), false); got != "Cloudflare" {
+		t.Errorf("probeEdge(edge) = %q, want Cloudflare from the redirect response", got)
+	}
+	if got := probeEdge(context.Background(), opts, serverResult(t, origin.URL), true); got != "" {
+		t.Errorf("probeEdge(origin) = %q, want no edge", got)
+	}
+}
+
+func TestDetectEdgePassesOtherResultsThrough(t *testing.T) {
+	events := make(chan Event, 3)
+	events <- NewResultEvent(ResultEvent{Host: "127.0.0.1", Port: 22, State: StateOpen})
+	events <- NewResultEvent(ResultEvent{Host: "127.0.0.1", Port: 443, State: StateClosed})
+	events <- NewResultEvent(ResultEvent{Host: "127.0.0.1", Port: 80, State: StateOpen, Protocol: "udp"})
+	close(events)
+
+	var got []ResultEvent
+	for event := range DetectEdge(context.Background(), events, EdgeOptions{Timeout: 100 * time.Millisecond}) {
+		got = append(got, *event.Result)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d results, want 3", len(got))
+	}
+	for _, r := range got {
+		if r.Edge != "" {
+			t.Errorf("%d/%s should not be probed, got edge %q", r.Port, r.Protocol, r.Edge)
+		}
+	}
+}
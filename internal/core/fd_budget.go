@@ -0,0 +1,137 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"syscall"
+)
+
+// File descriptor budgeting
+const (
+	// FDReserve is the number of descriptors kept free for stdio, DNS
+	// lookups, output files and the TUI when sizing the worker pool.
+	FDReserve = 64
+
+	// fdRecoverAfter is how many successful probes restore one slot after
+	// the throttle has backed off.
+	fdRecoverAfter = 50
+
+	// fdExhaustedRetries is how often a probe that hit the descriptor
+	// limit is retried before its error is reported.
+	fdExhaustedRetries = 3
+)
+
+// WorkersForFileLimit caps workers so that they, plus reserved sockets such
+// as the banner pool's, fit within the soft descriptor limit less
+// FDReserve. A soft limit of zero means unknown and leaves workers as is.
+func WorkersForFileLimit(workers, reserved int, soft uint64) int {
+	if soft == 0 {
+		return workers
+	}
+	budget := int64(soft) - FDReserve - int64(reserved)
+	if budget < 1 {
+		return 1
+	}
+	if int64(workers) > budget {
+		return int(budget)
+	}
+	return workers
+}
+
+// isFDExhausted reports whether err means the process or system ran out
+// of file descriptors.
+func isFDExhausted(err error) bool {
+	return errors.Is(err, syscall.EMFILE) || errors.Is(err, syscall.ENFILE)
+}
+
+// fdThrottle caps how many probes hold a socket at once. When a probe hits
+// the descriptor limit the cap is halved, and it grows back one slot per
+// fdRecoverAfter successful probes, up to the worker count. This keeps a
+// full-range scan from turning into a storm of "too many open files".
+type fdThrottle struct {
+	tokens chan struct{}
+
+	mu       sync.Mutex
+	limit    int // current cap on concurrent probes
+	max      int
+	withhold int // tokens to drop on release to bring the cap down
+	streak   int // successful probes since the cap last changed
+}
+
+func newFDThrottle(max int) *fdThrottle {
+	if max < 1 {
+		max = 1
+	}
+	t := &fdThrottle{tokens: make(chan struct{}, max), limit: max, max: max}
+	for i := 0; i < max; i++ {
+		t.tokens <- struct{}{}
+	}
+	return t
+}
+
+// acquire blocks until a probe may open a socket. It returns false if ctx
+// is cancelled first.
+func (t *fdThrottle) acquire(ctx context.Context) bool {
+	select {
+	case <-t.tokens:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// release returns a slot taken by acquire. exhausted reports whether the
+// probe failed for lack of file descriptors.
+func (t *fdThrottle) release(exhausted bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if exhausted {
+		t.streak = 0
+		if t.limit > 1 {
+			lower := t.limit / 2
+			drop := t.limit - lower
+			t.limit = lower
+			// Idle tokens are taken back at once; the rest are dropped as
+			// the probes holding them release.
+			t.withhold += drop - t.takeIdle(drop)
+		}
+	} else if t.limit < t.max {
+		t.streak++
+		if t.streak >= fdRecoverAfter {
+			t.streak = 0
+			t.limit++
+			if t.withhold > 0 {
+				t.withhold--
+			} else {
+				t.tokens <- struct{}{}
+			}
+		}
+	}
+
+	if t.withhold > 0 {
+		t.withhold--
+		return
+	}
+	t.tokens <- struct{}{}
+}
+
+// takeIdle removes up to n unused tokens and returns how many it took.
+func (t *fdThrottle) takeIdle(n int) int {
+	for taken := 0; taken < n; taken++ {
+		select {
+		case <-t.tokens:
+		default:
+			return taken
+		}
+	}
+	return n
+}
+
+// currentLimit returns the current cap on concurrent probes.
+func (t *fdThrottle) currentLimit() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.limit
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWorkersForFileLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		workers  int
+		reserved int
+		soft     uint64
+		want     int
+	}{
+		{"unknown limit", 500, 0, 0, 500},
+		{"fits", 200, 50, 1024, 200},
+		{"capped", 1000, 0, 256, 256 - FDReserve},
+		{"banner pool counted", 1000, 50, 256, 256 - FDReserve - 50},
+		{"at least one", 100, 500, 256, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WorkersForFileLimit(tt.workers, tt.reserved, tt.soft); got != tt.want {
+				t.Errorf("WorkersForFileLimit(%d, %d, %d) = %d, want %d", tt.workers, tt.reserved, tt.soft, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsFDExhausted(t *testing.T) {
+	if !isFDExhausted(fmt.Errorf("dial tcp: %w", syscall.EMFILE)) {
+		t.Error("EMFILE should count as descriptor exhaustion")
+	}
+	if !isFDExhausted(syscall.ENFILE) {
+		t.Error("ENFILE should count as descriptor exhaustion")
+	}
+	if isFDExhausted(syscall.ECONNREFUSED) || isFDExhausted(nil) {
+		t.Error("other errors are not descriptor exhaustion")
+	}
+}
+
+func TestFDThrottleBacksOffAndRecovers(t *testing.T) {
+	th := newFDThrottle(8)
+	ctx := context.Background()
+
+	if !th.acquire(ctx) {
+		t.Fatal("acquire should succeed")
+	}
+	th.release(true)
+	if got := th.currentLimit(); got != 4 {
+		t.Fatalf("limit after exhaustion = %d, want 4", got)
+	}
+
+	// Only four probes may now run at once.
+	for i := 0; i < 4; i++ {
+		if !th.acquire(ctx) {
+			t.Fatalf("acquire %d should succeed", i)
+		}
+	}
+	blocked, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if th.acquire(blocked) {
+		t.Fatal("a fifth concurrent probe should block")
+	}
+	for i := 0; i < 4; i++ {
+		th.release(false)
+	}
+
+	for i := 0; i < fdRecoverAfter*4; i++ {
+		if !th.acquire(ctx) {
+			t.Fatal("acquire should succeed")
+		}
+		th.release(false)
+	}
+	if got := th.currentLimit(); got != 8 {
+		t.Errorf("limit after recovery = %d, want 8", got)
+	}
+	for i := 0; i < 8; i++ {
+		if !th.acquire(ctx) {
+			t.Fatalf("recovered slot %d should be available", i)
+		}
+	}
+}
+
+func TestFDThrottleFloor(t *testing.T) {
+	th := newFDThrottle(2)
+	for i := 0; i < 3; i++ {
+		if !th.acquire(context.Background()) {
+			t.Fatal("the throttle should never drop below one slot")
+		}
+		th.release(true)
+	}
+	if got := th.currentLimit(); got != 1 {
+		t.Errorf("limit = %d, want 1", got)
+	}
+}
+
+func TestFileLimit(t *testing.T) {
+	soft, hard, ok := FileLimit()
+	if !ok {
+		t.Skip("file descriptor limits are not available on this platform")
+	}
+	if soft == 0 || soft > hard {
+		t.Errorf("FileLimit() = %d, %d; want 0 < soft <= hard", soft, hard)
+	}
+	raised, err := RaiseFileLimit(soft)
+	if err != nil || raised != soft {
+		t.Errorf("RaiseFileLimit(current) = %d, %v; want %d, nil", raised, err, soft)
+	}
+}
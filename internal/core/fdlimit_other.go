@@ -0,0 +1,15 @@
+//go:build !linux && !darwin
+
+package core
+
+import "errors"
+
+// FileLimit is not available on this platform; ok is always false.
+func FileLimit() (soft, hard uint64, ok bool) {
+	return 0, 0, false
+}
+
+// RaiseFileLimit is not available on this platform.
+func RaiseFileLimit(want uint64) (uint64, error) {
+	return 0, errors.New("file descriptor limits are not supported on this platform")
+}
@@ -0,0 +1,42 @@
+//go:build linux || darwin
+
+package core
+
+import "syscall"
+
+// FileLimit returns the process's soft and hard RLIMIT_NOFILE. ok is false
+// when the limit cannot be read.
+func FileLimit() (soft, hard uint64, ok bool) {
+	var lim syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &lim); err != nil {
+		return 0, 0, false
+	}
+	return lim.Cur, lim.Max, true
+}
+
+// RaiseFileLimit raises the soft RLIMIT_NOFILE to want, or as close to it
+// as the hard limit allows, and returns the new soft limit. A privileged
+// process may also raise the hard limit. The limit is never lowered.
+func RaiseFileLimit(want uint64) (uint64, error) {
+	var lim syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &lim); err != nil {
+		return 0, err
+	}
+	if lim.Cur >= want {
+		return lim.Cur, nil
+	}
+	if lim.Max < want {
+		// Only root can lift the hard limit; otherwise settle for it.
+		raised := lim
+		raised.Cur, raised.Max = want, want
+		if err := syscall.Setrlimit(syscall.RLIMIT_NOFILE, &raised); err == nil {
+			return want, nil
+		}
+		want = lim.Max
+	}
+	lim.Cur = want
+	if err := syscall.Setrlimit(syscall.RLIMIT_NOFILE, &lim); err != nil {
+		return 0, err
+	}
+	return want, nil
+}
@@ -0,0 +1,53 @@
+package core
+
+import "context"
+
+// hostLimiter caps how many probes run against a single host at once, so
+// slow timing templates stay gentle on each target even with many workers.
+// A nil limiter imposes no limit.
+type hostLimiter struct {
+	slots map[string]chan struct{}
+}
+
+// newHostLimiter builds a limiter for the targets' hosts. It returns nil
+// when limit is not positive.
+func newHostLimiter(targets []ScanTarget, limit int) *hostLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	l := &hostLimiter{slots: make(map[string]chan struct{}, len(targets))}
+	for _, t := range targets {
+		if _, ok := l.slots[t.Host]; !ok {
+			l.slots[t.Host] = make(chan struct{}, limit)
+		}
+	}
+	return l
+}
+
+// acquire blocks until host has a free slot. It returns false if ctx is
+// cancelled first.
+func (l *hostLimiter) acquire(ctx context.Context, host string) bool {
+	if l == nil {
+		return ctx.Err() == nil
+	}
+	slot, ok := l.slots[host]
+	if !ok {
+		return ctx.Err() == nil
+	}
+	select {
+	case slot <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// release frees a slot taken by acquire.
+func (l *hostLimiter) release(host string) {
+	if l == nil {
+		return
+	}
+	if slot, ok := l.slots[host]; ok {
+		<-slot
+	}
+}
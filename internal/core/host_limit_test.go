@@ -0,0 +1,61 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHostLimiter(t *testing.T) {
+	l := newHostLimiter([]ScanTarget{{Host: "a"}, {Host: "b"}}, 1)
+	ctx := context.Background()
+
+	if !l.acquire(ctx, "a") {
+		t.Fatal("first acquire should succeed")
+	}
+	if !l.acquire(ctx, "b") {
+		t.Fatal("other hosts have their own slots")
+	}
+
+	blocked, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if l.acquire(blocked, "a") {
+		t.Fatal("second acquire for a full host should block until cancelled")
+	}
+
+	l.release("a")
+	if !l.acquire(ctx, "a") {
+		t.Fatal("acquire should succeed after release")
+	}
+}
+
+func TestHostLimiterDisabled(t *testing.T) {
+	l := newHostLimiter([]ScanTarget{{Host: "a"}}, 0)
+	if l != nil {
+		t.Fatal("a zero limit should disable the limiter")
+	}
+	for i := 0; i < 3; i++ {
+		if !l.acquire(context.Background(), "a") {
+			t.Fatal("a nil limiter should never block")
+		}
+	}
+	l.release("a")
+}
+
+func TestWaitProbeJitter(t *testing.T) {
+	s := NewScanner(&Config{ProbeJitter: 10 * time.Millisecond})
+	start := time.Now()
+	if !s.waitProbeJitter(context.Background()) {
+		t.Fatal("jitter wait should complete")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("jitter wait took %v, want under the configured bound", elapsed)
+	}
+
+	s = NewScanner(&Config{ProbeJitter: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if s.waitProbeJitter(ctx) {
+		t.Error("jitter wait should stop when the context is cancelled")
+	}
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// The connect scan runs this code once per probe, so at tens of thousands
+// of probes per second its allocations dominate the profile. Addresses are
+// preformatted per host, per-worker buffers and dialers are reused, and
+// timers come from a pool.
+
+// hostAddrPrefixes maps each target host to the "host:" prefix of its dial
+// address, with IPv6 literals bracketed. Hosts that must be looked up on
+// every probe through a custom resolver are left out.
+func hostAddrPrefixes(targets []ScanTarget, resolver HostResolver) map[string]string {
+	prefixes := make(map[string]string, len(targets))
+	for _, t := range targets {
+		if _, ok := prefixes[t.Host]; ok {
+			continue
+		}
+		if resolver != nil && net.ParseIP(t.Host) == nil {
+			continue
+		}
+		prefixes[t.Host] = net.JoinHostPort(t.Host, "")
+	}
+	return prefixes
+}
+
+// probeScratch is the state a scan worker reuses across its probes.
+type probeScratch struct {
+	dialer      *net.Dialer
+	portDialers map[uint16]*net.Dialer // dialers for PortTimeouts overrides, built on first use
+	addr        []byte
+}
+
+func newProbeScratch(timeout time.Duration) *probeScratch {
+	return &probeScratch{
+		dialer: &net.Dialer{Timeout: timeout},
+		addr:   make([]byte, 0, len("[ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff]:65535")),
+	}
+}
+
+// address appends port to a preformatted host prefix. The returned string
+// is the only allocation.
+func (p *probeScratch) address(prefix string, port uint16) string {
+	p.addr = append(p.addr[:0], prefix...)
+	p.addr = strconv.AppendUint(p.addr, uint64(port), 10)
+	return string(p.addr)
+}
+
+// dialerFor returns the dialer for port, honouring its PortTimeouts
+// override.
+func (p *probeScratch) dialerFor(port uint16, overrides map[uint16]time.Duration) *net.Dialer {
+	timeout, ok := overrides[port]
+	if !ok {
+		return p.dialer
+	}
+	if d, ok := p.portDialers[port]; ok {
+		return d
+	}
+	if p.portDialers == nil {
+		p.portDialers = make(map[uint16]*net.Dialer)
+	}
+	d := &net.Dialer{Timeout: timeout}
+	p.portDialers[port] = d
+	return d
+}
+
+var timerPool sync.Pool
+
+// sleepContext waits for d using a pooled timer. It returns false if ctx
+// is cancelled first.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer, _ := timerPool.Get().(*time.Timer)
+	if timer == nil {
+		timer = time.NewTimer(d)
+	} else {
+		timer.Reset(d)
+	}
+	// Since Go 1.23 a stopped timer's channel holds no stale value, so the
+	// timer can be reset by its next user without draining.
+	defer func() {
+		timer.Stop()
+		timerPool.Put(timer)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// bannerBuffer returns a banner read buffer from the scanner's pool;
+// return it with putBannerBuffer.
+func (s *Scanner) bannerBuffer() *[]byte {
+	if buf, ok := s.bannerBufs.Get().(*[]byte); ok {
+		return buf
+	}
+	buf := make([]byte, s.config.BannerMaxBytes)
+	return &buf
+}
+
+func (s *Scanner) putBannerBuffer(buf *[]byte) {
+	s.bannerBufs.Put(buf)
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHostAddrPrefixes(t *testing.T) {
+	targets := []ScanTarget{
+		{Host: "10.0.0.1"},
+		{Host: "::1"},
+		{Host: "db.test"},
+		{Host: "10.0.0.1"},
+	}
+
+	prefixes := hostAddrPrefixes(targets, nil)
+	want := map[string]string{"10.0.0.1": "10.0.0.1:", "::1": "[::1]:", "db.test": "db.test:"}
+	if len(prefixes) != len(want) {
+		t.Errorf("prefixes = %v, want %v", prefixes, want)
+	}
+	for host, prefix := range want {
+		if prefixes[host] != prefix {
+			t.Errorf("prefix for %s = %q, want %q", host, prefixes[host], prefix)
+		}
+	}
+
+	// Hostnames looked up through a custom resolver are resolved per probe.
+	prefixes = hostAddrPrefixes(targets, &fakeResolver{addrs: map[string][]string{"db.test": {"10.0.0.2"}}})
+	if _, ok := prefixes["db.test"]; ok {
+		t.Error("resolver-backed hostname should not be preformatted")
+	}
+	if prefixes["10.0.0.1"] != "10.0.0.1:" {
+		t.Errorf("IP prefix with resolver = %q", prefixes["10.0.0.1"])
+	}
+}
+
+func TestProbeScratchAddress(t *testing.T) {
+	scratch := newProbeScratch(time.Second)
+	for _, tt := range []struct {
+		host string
+		port uint16
+	}{
+		{"10.0.0.1", 22},
+		{"::1", 65535},
+		{"db.test", 8080},
+	} {
+		got := scratch.address(net.JoinHostPort(tt.host, ""), tt.port)
+		if want := net.JoinHostPort(tt.host, strconv.Itoa(int(tt.port))); got != want {
+			t.Errorf("address(%s, %d) = %q, want %q", tt.host, tt.port, got, want)
+		}
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = scratch.address("10.0.0.1:", 8443)
+	})
+	if allocs > 1 {
+		t.Errorf("address allocs/op = %v, want at most the result string", allocs)
+	}
+}
+
+func TestProbeScratchDialerFor(t *testing.T) {
+	scratch := newProbeScratch(time.Second)
+	overrides := map[uint16]time.Duration{3306: 50 * time.Millisecond}
+
+	if d := scratch.dialerFor(22, overrides); d != scratch.dialer {
+		t.Error("port without override should use the worker dialer")
+	}
+	d := scratch.dialerFor(3306, overrides)
+	if d.Timeout != 50*time.Millisecond {
+		t.Errorf("override dialer timeout = %v, want 50ms", d.Timeout)
+	}
+	if again := scratch.dialerFor(3306, overrides); again != d {
+		t.Error("override dialer should be reused")
+	}
+}
+
+func TestSleepContext(t *testing.T) {
+	if !sleepContext(context.Background(), time.Millisecond) {
+		t.Error("sleepContext should finish when the timer fires")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if sleepContext(ctx, time.Hour) {
+		t.Error("sleepContext should stop when the context is cancelled")
+	}
+	// A timer returned to the pool after cancellation must not fire early.
+	start := time.Now()
+	if !sleepContext(context.Background(), 20*time.Millisecond) || time.Since(start) < 20*time.Millisecond {
+		t.Error("reused timer fired early")
+	}
+
+	allocs := testing.AllocsPerRun(20, func() {
+		sleepContext(context.Background(), time.Microsecond)
+	})
+	if allocs != 0 {
+		t.Errorf("sleepContext allocs/op = %v, want 0", allocs)
+	}
+}
+
+func TestBannerBufferReuse(t *testing.T) {
+	s := NewScanner(&Config{BannerMaxBytes: 32})
+	buf := s.bannerBuffer()
+	if len(*buf) != 32 {
+		t.Errorf("banner buffer length = %d, want 32", len(*buf))
+	}
+	s.putBannerBuffer(buf)
+}
+
+func BenchmarkProbeAddress(b *testing.B) {
+	scratch := newProbeScratch(time.Second)
+	prefixes := hostAddrPrefixes([]ScanTarget{{Host: "192.168.100.200"}}, nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = scratch.address(prefixes["192.168.100.200"], uint16(i))
+	}
+}
+
+func BenchmarkDialAddress(b *testing.B) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = dialAddress(ctx, nil, "192.168.100.200", uint16(i))
+	}
+}
+
+func BenchmarkSleepContext(b *testing.B) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sleepContext(ctx, time.Nanosecond)
+	}
+}
+
+func BenchmarkGrabBanner(b *testing.B) {
+	s := NewScanner(&Config{BannerMaxBytes: 512})
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	banner := []byte("SSH-2.0-OpenSSH_9.6\r\n")
+	go func() {
+		for {
+			if _, err := server.Write(banner); err != nil {
+				return
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = s.grabBanner(client, time.Second)
+	}
+}
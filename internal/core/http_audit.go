@@ -0,0 +1,197 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultHTTPAuditTimeout bounds each audit, redirects included.
+const DefaultHTTPAuditTimeout = 5 * time.Second
+
+// maxAuditRedirects caps how many same-host redirects an audit follows.
+const maxAuditRedirects = 5
+
+// HTTPAuditOptions configures the HTTP security header audit.
+type HTTPAuditOptions struct {
+	Timeout time.Duration // per-port timeout; defaults to DefaultHTTPAuditTimeout
+	Workers int           // concurrent audits; defaults to 16
+	// Resolver gives the address every request of a hostname result's
+	// audit connects to, redirects between ports included; nil dials by
+	// name.
+	Resolver HostResolver
+}
+
+// securityHeader is one audited response header.
+type securityHeader struct {
+	name    string
+	present func(http.Header) bool
+}
+
+// securityHeaders are graded in this order. A CSP frame-ancestors
+// directive stands in for X-Frame-Options, which it supersedes.
+var securityHeaders = []securityHeader{
+	{"Strict-Transport-Security", hasHeader("Strict-Transport-Security")},
+	{"Content-Security-Policy", hasHeader("Content-Security-Policy")},
+	{"X-Frame-Options", func(h http.Header) bool {
+		return h.Get("X-Frame-Options") != "" ||
+			strings.Contains(strings.ToLower(h.Get("Content-Security-Policy")), "frame-ancestors")
+	}},
+	{"X-Content-Type-Options", hasHeader("X-Content-Type-Options")},
+	{"Referrer-Policy", hasHeader("Referrer-Policy")},
+}
+
+func hasHeader(name string) func(http.Header) bool {
+	return func(h http.Header) bool { return strings.TrimSpace(h.Get(name)) != "" }
+}
+
+// AuditHTTP requests "/" from every open web port (80, 443, 8080 and
+// 8443) in events, following redirects on the same host, and records in
+// HTTPAudit which security headers the final response carried, graded A
+// to F. Other events pass through unchanged. The returned channel is
+// closed once events is closed and every audit has finished.
+func AuditHTTP(ctx context.Context, events <-chan Event, opts HTTPAuditOptions) <-chan Event {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultHTTPAuditTimeout
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 16
+	}
+
+	out := make(chan Event, cap(events))
+	jobs := make(chan ResultEvent)
+	var wg sync.WaitGroup
+
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for result := range jobs {
+				result.HTTPAudit = auditHTTP(ctx, opts, result, edgeTLSPorts[result.Port])
+				select {
+				case out <- NewResultEvent(result):
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer func() {
+			close(jobs)
+			wg.Wait()
+			close(out)
+		}()
+		for event := range events {
+			// Web ports are the ones edge detection probes.
+			if event.Kind == EventKindResult && needsEdgeProbe(*event.Result) {
+				select {
+				case jobs <- *event.Result:
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// auditHTTP requests "/" from the result's port, over TLS when useTLS is
+// set, and grades the response. Redirects are followed only while they
+// stay on the scanned host, so an audit never reaches a host outside the
+// scan; the first off-host redirect is recorded and its response audited.
+// A failed request returns nil.
+func auditHTTP(ctx context.Context, opts HTTPAuditOptions, r ResultEvent, useTLS bool) *HTTPAudit {
+	// Ask for the name the target was given as, so virtual hosts route to
+	// the site rather than the server's default.
+	name := r.Hostname
+	if name == "" {
+		name = r.Host
+	}
+
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	transport := &http.Transport{
+		// Every request stays on the scanned host; only the port changes
+		// when a redirect moves between HTTP and HTTPS.
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			_, portText, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+			port, err := strconv.ParseUint(portText, 10, 16)
+			if err != nil {
+				return nil, err
+			}
+			address, err := dialAddress(ctx, opts.Resolver, r.Host, uint16(port))
+			if err != nil {
+				return nil, err
+			}
+			return dialer.DialContext(ctx, network, address)
+		},
+		// Headers are audited, not the certificate.
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		DisableKeepAlives: true,
+	}
+	defer transport.CloseIdleConnections()
+
+	audit := &HTTPAudit{}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   opts.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			audit.Redirects = append(audit.Redirects, req.URL.String())
+			if len(via) > maxAuditRedirects || !strings.EqualFold(req.URL.Hostname(), name) {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+	hostport := net.JoinHostPort(name, strconv.Itoa(int(r.Port)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+hostport+"/", nil)
+	if err != nil {
+		return nil
+	}
+	req.Header.Set("User-Agent", "portscan")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	audit.Status = resp.StatusCode
+	gradeHTTPAudit(audit, resp.Header)
+	return audit
+}
+
+// gradeHTTPAudit sorts the security headers into present and missing and
+// grades the audit: A with none missing, then a letter lower per missing
+// header, and F with four or more missing.
+func gradeHTTPAudit(audit *HTTPAudit, header http.Header) {
+	for _, h := range securityHeaders {
+		if h.present(header) {
+			audit.Present = append(audit.Present, h.name)
+		} else {
+			audit.Missing = append(audit.Missing, h.name)
+		}
+	}
+	audit.Grade = string("ABCDF"[min(len(audit.Missing), 4)])
+}
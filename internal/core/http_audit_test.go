@@ -0,0 +1,85 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGradeHTTPAudit(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  http.Header
+		grade   string
+		missing []string
+	}{
+		{"none", http.Header{}, "F", nil},
+		{"all", http.Header{
+			"Strict-Transport-Security": {"max-age=31536000"},
+			"Content-Security-Policy":   {"default-src 'self'"},
+			"X-Frame-Options":           {"DENY"},
+			"X-Content-Type-Options":    {"nosniff"},
+			"Referrer-Policy":           {"no-referrer"},
+		}, "A", nil},
+		{"frame-ancestors", http.Header{
+			"Strict-Transport-Security": {"max-age=31536000"},
+			"Content-Security-Policy":   {"frame-ancestors 'none'"},
+		}, "C", []string{"X-Content-Type-Options", "Referrer-Policy"}},
+	}
+	for _, tt := range tests {
+		audit := &HTTPAudit{}
+		gradeHTTPAudit(audit, tt.header)
+		if audit.Grade != tt.grade {
+			t.Errorf("%s: grade = %s, want %s", tt.name, audit.Grade, tt.grade)
+		}
+		if tt.missing != nil && !slices.Equal(audit.Missing, tt.missing) {
+			t.Errorf("%s: missing = %v, want %v", tt.name, audit.Missing, tt.missing)
+		}
+		if len(audit.Present)+len(audit.Missing) != len(securityHeaders) {
+			t.Errorf("%s: %d present and %d missing, want %d in all", tt.name, len(audit.Present), len(audit.Missing), len(securityHeaders))
+		}
+	}
+}
+
+func TestAuditHTTPFollowsSameHostRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			http.Redirect(w, r, "/login", http.StatusFound)
+		case "/login":
+			w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+		}
+	}))
+	defer server.Close()
+
+	opts := HTTPAuditOptions{Timeout: time.Second}
+	audit := auditHTTP(context.Background(), opts, serverResult(t, server.URL), false)
+	if audit == nil {
+		t.Fatal("auditHTTP() = nil, want an audit")
+	}
+	if audit.Status != http.StatusOK || len(audit.Redirects) != 1 || audit.Redirects[0] != server.URL+"/login" {
+		t.Errorf("audit = %+v, want the redirect to /login followed", audit)
+	}
+	if audit.Grade != "C" || !slices.Equal(audit.Missing, []string{"Strict-Transport-Security", "Referrer-Policy"}) {
+		t.Errorf("grade %s, missing %v; want C without HSTS and Referrer-Policy", audit.Grade, audit.Missing)
+	}
+}
+
+func TestAuditHTTPStopsAtOtherHosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://elsewhere.example/", http.StatusMovedPermanently)
+	}))
+	defer server.Close()
+
+	audit := auditHTTP(context.Background(), HTTPAuditOptions{Timeout: time.Second}, serverResult(t, server.URL), false)
+	if audit == nil || audit.Status != http.StatusMovedPermanently {
+		t.Fatalf("audit = %+v, want the redirect response audited", audit)
+	}
+	if !slices.Equal(audit.Redirects, []string{"https://elsewhere.example/"}) {
+		t.Errorf("redirects = %v, want the off-host location recorded", audit.Redirects)
+	}
+}
@@ -0,0 +1,105 @@
+package core
+
+import "sync"
+
+// MergeEvents fans in the event streams of scanners running side by side,
+// such as the TCP and UDP halves of a "both" scan. Results and errors are
+// forwarded as they arrive. Progress events are combined so consumers see
+// a single scan: totals, completed probes, and rates are summed, and a host
+// only counts as complete once every stream has finished it. Combined
+// progress is held back until every open stream has reported, so an early
+// partial total never looks finished. The returned channel is closed after
+// all inputs are closed.
+func MergeEvents(streams ...<-chan Event) <-chan Event {
+	if len(streams) == 1 {
+		return streams[0]
+	}
+
+	size := 0
+	for _, s := range streams {
+		size += cap(s)
+	}
+	out := make(chan Event, size)
+
+	var (
+		mu       sync.Mutex
+		latest   = make([]ProgressEvent, len(streams))
+		reported = make([]bool, len(streams)) // sent progress or closed
+		wg       sync.WaitGroup
+	)
+	for i, stream := range streams {
+		wg.Add(1)
+		go func(i int, stream <-chan Event) {
+			defer wg.Done()
+			defer func() {
+				mu.Lock()
+				reported[i] = true
+				mu.Unlock()
+			}()
+			for event := range stream {
+				if event.Kind == EventKindProgress && event.Progress != nil {
+					mu.Lock()
+					latest[i] = *event.Progress
+					reported[i] = true
+					ready := allTrue(reported)
+					event = NewProgressEvent(combineProgress(latest))
+					mu.Unlock()
+					if !ready {
+						continue
+					}
+				}
+				out <- event
+			}
+		}(i, stream)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+func allTrue(flags []bool) bool {
+	for _, f := range flags {
+		if !f {
+			return false
+		}
+	}
+	return true
+}
+
+// combineProgress sums per-stream progress. Streams scan the same hosts,
+// so the host total is the largest seen and completed hosts the fewest.
+// Streams run side by side, so the scan has been active as long as the
+// longest of them. A host's counts are summed across the streams probing
+// it.
+func combineProgress(parts []ProgressEvent) ProgressEvent {
+	var merged ProgressEvent
+	byHost := make(map[string]int)
+	for i, p := range parts {
+		for _, h := range p.Hosts {
+			if j, ok := byHost[h.Host]; ok {
+				merged.Hosts[j].Total += h.Total
+				merged.Hosts[j].Completed += h.Completed
+				merged.Hosts[j].Open += h.Open
+				continue
+			}
+			byHost[h.Host] = len(merged.Hosts)
+			merged.Hosts = append(merged.Hosts, h)
+		}
+		merged.Total += p.Total
+		merged.Completed += p.Completed
+		merged.Errors += p.Errors
+		merged.Rate += p.Rate
+		merged.RecentRate += p.RecentRate
+		merged.Elapsed = max(merged.Elapsed, p.Elapsed)
+		merged.Paused = merged.Paused || p.Paused
+		merged.TotalHosts = max(merged.TotalHosts, p.TotalHosts)
+		if i == 0 || p.CompletedHosts < merged.CompletedHosts {
+			merged.CompletedHosts = p.CompletedHosts
+		}
+	}
+	merged.Hosts = slowestHosts(merged.Hosts)
+	return merged
+}
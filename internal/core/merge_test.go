@@ -0,0 +1,58 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeEvents(t *testing.T) {
+	tcp := make(chan Event, 4)
+	udp := make(chan Event, 4)
+
+	tcp <- NewProgressEvent(ProgressEvent{Total: 10, Completed: 10, Rate: 5, TotalHosts: 1, CompletedHosts: 1, Errors: 2,
+		Hosts: []HostProgress{{Host: "g", Total: 5, Completed: 2, Open: 1}}})
+	tcp <- NewResultEvent(ResultEvent{Host: "h", Port: 22, State: StateOpen, Protocol: "tcp"})
+	close(tcp)
+
+	merged := MergeEvents(tcp, udp)
+
+	// Only the TCP half has reported, so no combined progress yet.
+	first := <-merged
+	if first.Kind != EventKindResult || first.Result.Protocol != "tcp" {
+		t.Fatalf("first event = %+v, want the TCP result", first)
+	}
+
+	udp <- NewResultEvent(ResultEvent{Host: "h", Port: 53, State: StateOpen, Protocol: "udp"})
+	udp <- NewProgressEvent(ProgressEvent{Total: 10, Completed: 4, Rate: 2, TotalHosts: 1, Errors: 1,
+		Hosts: []HostProgress{{Host: "g", Total: 5, Completed: 1}}})
+	close(udp)
+
+	var results int
+	var last *ProgressEvent
+	for event := range merged {
+		switch event.Kind {
+		case EventKindResult:
+			results++
+		case EventKindProgress:
+			last = event.Progress
+		}
+	}
+	if results != 1 {
+		t.Errorf("got %d UDP results, want 1", results)
+	}
+	if last == nil {
+		t.Fatal("expected combined progress once both streams reported")
+	}
+	want := ProgressEvent{Total: 20, Completed: 14, Rate: 7, TotalHosts: 1, CompletedHosts: 0, Errors: 3,
+		Hosts: []HostProgress{{Host: "g", Total: 10, Completed: 3, Open: 1}}}
+	if !reflect.DeepEqual(*last, want) {
+		t.Errorf("combined progress = %+v, want %+v", *last, want)
+	}
+}
+
+func TestMergeEvents_Single(t *testing.T) {
+	events := make(chan Event)
+	if got := MergeEvents(events); got != (<-chan Event)(events) {
+		t.Error("a single stream should be returned unchanged")
+	}
+}
@@ -0,0 +1,229 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultNTPCheckTimeout bounds how long replies to each NTP control
+// query are collected.
+const DefaultNTPCheckTimeout = 2 * time.Second
+
+// ntpPort is the port whose open results are checked.
+const ntpPort = 123
+
+// ntpMaxReplies caps the packets read per query; monlist answers with up
+// to 100.
+const ntpMaxReplies = 100
+
+// Exposure IDs reported by the NTP check.
+const (
+	ExposureNTPMonlist = "ntp_monlist"
+	ExposureNTPMode6   = "ntp_mode6"
+)
+
+// NTPCheckOptions configures the NTP amplification check.
+type NTPCheckOptions struct {
+	Timeout time.Duration // reply window per query; defaults to DefaultNTPCheckTimeout
+	Workers int           // concurrent checks; defaults to 16
+	// Resolver gives the address a hostname result's control queries are
+	// sent to; nil sends them to the name.
+	Resolver HostResolver
+}
+
+// ntpQuery is one control query and how to recognize its replies.
+type ntpQuery struct {
+	id       string
+	severity string
+	request  []byte
+	isReply  func([]byte) bool
+	message  string // format for the request and reply byte counts
+}
+
+// ntpQueries are tried in order. A mode 7 MON_GETLIST_1 (monlist) reply
+// lists up to 600 recent clients, the classic reflection vector; mode 6
+// READVAR answers are smaller but still amplify.
+var ntpQueries = []ntpQuery{
+	{
+		id:       ExposureNTPMonlist,
+		severity: "high",
+		request:  append([]byte{0x17, 0x00, 0x03, 0x2a}, make([]byte, 44)...),
+		isReply:  isNTPMonlistReply,
+		message:  "NTP answers mode 7 monlist queries: %d-byte request, %d bytes in reply (%.1fx amplification)",
+	},
+	{
+		id:       ExposureNTPMode6,
+		severity: "medium",
+		request:  []byte{0x16, 0x02, 0x00, 0x01, 0, 0, 0, 0, 0, 0, 0, 0},
+		isReply:  isNTPReadvarReply,
+		message:  "NTP answers mode 6 READVAR queries: %d-byte request, %d bytes in reply (%.1fx amplification)",
+	},
+}
+
+// CheckNTPExposure sends mode 7 monlist and mode 6 READVAR queries to
+// every open UDP 123 port in events and records an Exposure, plus a
+// severity=LEVEL tag, for each query the server answers, since both let it
+// be used to amplify reflection attacks. Other events pass through
+// unchanged. The returned channel is closed once events is closed and
+// every check has finished.
+func CheckNTPExposure(ctx context.Context, events <-chan Event, opts NTPCheckOptions) <-chan Event {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultNTPCheckTimeout
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 16
+	}
+
+	out := make(chan Event, cap(events))
+	jobs := make(chan ResultEvent)
+	var wg sync.WaitGroup
+
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for result := range jobs {
+				for _, exposure := range checkNTP(ctx, opts, result) {
+					result = withExposure(result, exposure)
+				}
+				select {
+				case out <- NewResultEvent(result):
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer func() {
+			close(jobs)
+			wg.Wait()
+			close(out)
+		}()
+		for event := range events {
+			if event.Kind == EventKindResult && needsNTPCheck(*event.Result) {
+				select {
+				case jobs <- *event.Result:
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+func needsNTPCheck(r ResultEvent) bool {
+	return r.State == StateOpen && r.Protocol == "udp" && r.Port == ntpPort
+}
+
+// withExposure adds e to r, tagging r with the most severe level found.
+// The tags slice is copied, since results share it with the scan's tags.
+func withExposure(r ResultEvent, e Exposure) ResultEvent {
+	r.Exposures = append(r.Exposures, e)
+	tags := make([]string, 0, len(r.Tags)+1)
+	level := e.Severity
+	for _, tag := range r.Tags {
+		if existing, ok := strings.CutPrefix(tag, "severity="); ok {
+			if severityRank(existing) >= severityRank(level) {
+				level = existing
+			}
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	r.Tags = append(tags, "severity="+level)
+	return r
+}
+
+func severityRank(level string) int {
+	switch level {
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	case "low":
+		return 1
+	}
+	return 0
+}
+
+// checkNTP runs each query against the result's port and returns an
+// exposure for each one answered.
+func checkNTP(ctx context.Context, opts NTPCheckOptions, r ResultEvent) []Exposure {
+	address, err := dialAddress(ctx, opts.Resolver, r.Host, r.Port)
+	if err != nil {
+		return nil
+	}
+	var found []Exposure
+	for _, query := range ntpQueries {
+		received := exchangeNTP(ctx, address, query, opts.Timeout)
+		if received == 0 {
+			continue
+		}
+		sent := len(query.request)
+		found = append(found, Exposure{
+			ID:       query.id,
+			Severity: query.severity,
+			Message:  fmt.Sprintf(query.message, sent, received, float64(received)/float64(sent)),
+		})
+	}
+	return found
+}
+
+// exchangeNTP sends query to address and returns the bytes received in
+// replies to it within timeout.
+func exchangeNTP(ctx context.Context, address string, query ntpQuery, timeout time.Duration) int {
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return 0
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if _, err := conn.Write(query.request); err != nil {
+		return 0
+	}
+	received := 0
+	buf := make([]byte, 2048)
+	for i := 0; i < ntpMaxReplies; i++ {
+		n, err := conn.Read(buf)
+		if err != nil {
+			break
+		}
+		if query.isReply(buf[:n]) {
+			received += n
+		}
+	}
+	return received
+}
+
+// isNTPMonlistReply recognizes a successful mode 7 MON_GETLIST_1 response
+// carrying at least one entry.
+func isNTPMonlistReply(data []byte) bool {
+	if len(data) < 8 || data[0]&0x80 == 0 || data[0]&0x07 != 7 || data[3] != 0x2a {
+		return false
+	}
+	errCode := data[4] >> 4
+	items := int(data[4]&0x0f)<<8 | int(data[5])
+	return errCode == 0 && items > 0
+}
+
+// isNTPReadvarReply recognizes a mode 6 READVAR response.
+func isNTPReadvarReply(data []byte) bool {
+	return len(data) >= 12 && data[0]&0x07 == 6 && data[1]&0x80 != 0 && data[1]&0x1f == 0x02 && data[1]&0x40 == 0
+}
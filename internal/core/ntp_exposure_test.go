@@ -0,0 +1,132 @@
+package core
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeNTP answers monlist requests with three 440-byte packets and, when
+// mode6 is set, READVAR requests with one 100-byte packet.
+func fakeNTP(t *testing.T, mode6 bool) uint16 {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			switch {
+			case n == 48 && buf[0] == 0x17 && buf[3] == 0x2a:
+				reply := make([]byte, 440)
+				copy(reply, []byte{0x97, 0x00, 0x03, 0x2a, 0x00, 0x06, 0x00, 0x48})
+				for i := 0; i < 3; i++ {
+					_, _ = conn.WriteTo(reply, addr)
+				}
+			case mode6 && n == 12 && buf[0] == 0x16:
+				reply := make([]byte, 100)
+				copy(reply, []byte{0x16, 0x82, 0x00, 0x01})
+				_, _ = conn.WriteTo(reply, addr)
+			}
+		}
+	}()
+	return uint16(conn.LocalAddr().(*net.UDPAddr).Port)
+}
+
+func TestCheckNTP(t *testing.T) {
+	port := fakeNTP(t, true)
+	r := ResultEvent{Host: "127.0.0.1", Port: port, State: StateOpen, Protocol: "udp"}
+
+	found := checkNTP(context.Background(), NTPCheckOptions{Timeout: 300 * time.Millisecond}, r)
+	if len(found) != 2 {
+		t.Fatalf("checkNTP() = %+v, want monlist and mode 6 exposures", found)
+	}
+	if found[0].ID != ExposureNTPMonlist || found[0].Severity != "high" {
+		t.Errorf("first exposure = %+v, want high-severity monlist", found[0])
+	}
+	if !strings.Contains(found[0].Message, "1320 bytes in reply (27.5x") {
+		t.Errorf("monlist message = %q, want the three replies counted", found[0].Message)
+	}
+	if found[1].ID != ExposureNTPMode6 || found[1].Severity != "medium" {
+		t.Errorf("second exposure = %+v, want medium-severity mode 6", found[1])
+	}
+
+	quiet := fakeNTP(t, false)
+	r.Port = quiet
+	if found := checkNTP(context.Background(), NTPCheckOptions{Timeout: 200 * time.Millisecond}, r); len(found) != 1 {
+		t.Errorf("server without mode 6: checkNTP() = %+v, want only monlist", found)
+	}
+}
+
+func TestNTPReplies(t *testing.T) {
+	if !isNTPMonlistReply([]byte{0x97, 0x00, 0x03, 0x2a, 0x00, 0x06, 0x00, 0x48}) {
+		t.Error("monlist reply with entries rejected")
+	}
+	if isNTPMonlistReply([]byte{0x97, 0x00, 0x03, 0x2a, 0x00, 0x00, 0x00, 0x48}) {
+		t.Error("monlist reply without entries accepted")
+	}
+	if isNTPMonlistReply([]byte{0x97, 0x00, 0x03, 0x2a, 0x40, 0x00, 0x00, 0x00}) {
+		t.Error("monlist error reply accepted")
+	}
+	if isNTPMonlistReply(ntpQueries[0].request) {
+		t.Error("monlist request accepted as a reply")
+	}
+	readvar := append([]byte{0x16, 0x82, 0x00, 0x01}, make([]byte, 8)...)
+	if !isNTPReadvarReply(readvar) {
+		t.Error("READVAR reply rejected")
+	}
+	if isNTPReadvarReply(ntpQueries[1].request) {
+		t.Error("READVAR request accepted as a reply")
+	}
+	readvar[1] |= 0x40 // error bit
+	if isNTPReadvarReply(readvar) {
+		t.Error("READVAR error reply accepted")
+	}
+}
+
+func TestWithExposureTagsSeverity(t *testing.T) {
+	shared := []string{"env=prod"}
+	r := ResultEvent{Tags: shared}
+	r = withExposure(r, Exposure{ID: ExposureNTPMode6, Severity: "medium"})
+	r = withExposure(r, Exposure{ID: ExposureNTPMonlist, Severity: "high"})
+	r = withExposure(r, Exposure{ID: "other", Severity: "low"})
+
+	if want := []string{"env=prod", "severity=high"}; !slices.Equal(r.Tags, want) {
+		t.Errorf("tags = %v, want %v", r.Tags, want)
+	}
+	if len(r.Exposures) != 3 {
+		t.Errorf("got %d exposures, want 3", len(r.Exposures))
+	}
+	if len(shared) != 1 {
+		t.Error("withExposure modified the caller's tags")
+	}
+}
+
+func TestCheckNTPExposurePassesOtherResultsThrough(t *testing.T) {
+	events := make(chan Event, 3)
+	events <- NewResultEvent(ResultEvent{Host: "127.0.0.1", Port: 123, State: StateClosed, Protocol: "udp"})
+	events <- NewResultEvent(ResultEvent{Host: "127.0.0.1", Port: 123, State: StateOpen, Protocol: "tcp"})
+	events <- NewProgressEvent(ProgressEvent{Total: 2, Completed: 2})
+	close(events)
+
+	var count int
+	for event := range CheckNTPExposure(context.Background(), events, NTPCheckOptions{}) {
+		if event.Kind == EventKindResult && len(event.Result.Exposures) > 0 {
+			t.Errorf("unchecked result gained exposures: %+v", event.Result)
+		}
+		count++
+	}
+	if count != 3 {
+		t.Errorf("got %d events, want 3", count)
+	}
+}
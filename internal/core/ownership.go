@@ -0,0 +1,321 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultOwnershipTimeout bounds each RDAP query, redirects included.
+	DefaultOwnershipTimeout = 10 * time.Second
+	// DefaultRDAPEndpoint is the RDAP bootstrap service IP queries are sent
+	// to; it redirects each one to the registry holding the address.
+	DefaultRDAPEndpoint = "https://rdap.org/ip/"
+	// DefaultOwnershipMaxAge is how long a cached registration is used
+	// before it is queried again.
+	DefaultOwnershipMaxAge = 30 * 24 * time.Hour
+)
+
+// sharedAddressSpace is RFC 6598 carrier-grade NAT space, which is not
+// reachable from the internet either.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0).To4(), Mask: net.CIDRMask(10, 32)}
+
+// IsPublicIP reports whether ip is routable on the public internet.
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() ||
+		ip.IsUnspecified() || ip.IsMulticast() || sharedAddressSpace.Contains(ip))
+}
+
+// Ownership is who a regional internet registry lists as holding the
+// network an address belongs to.
+type Ownership struct {
+	Org     string `json:"org,omitempty"`     // registrant organization, e.g. "Google LLC"
+	Abuse   string `json:"abuse,omitempty"`   // abuse contact email
+	Network string `json:"network,omitempty"` // registry name of the network, e.g. "GOGL"
+	Range   string `json:"range,omitempty"`   // first and last address, e.g. "8.8.8.0 - 8.8.8.255"
+}
+
+// OwnershipOptions configures ownership lookups.
+type OwnershipOptions struct {
+	Timeout time.Duration // per-query timeout; defaults to DefaultOwnershipTimeout
+	Workers int           // concurrent lookups; defaults to 4, as registries rate limit
+	// Endpoint is the RDAP base URL the address is appended to; defaults
+	// to DefaultRDAPEndpoint.
+	Endpoint string
+	// Cache, if set, answers lookups for networks queried before and
+	// keeps the answers to new ones.
+	Cache *OwnershipCache
+	// MaxAge is how old a cached answer may be before it is queried
+	// again; defaults to DefaultOwnershipMaxAge. Offline lookups use
+	// cached answers of any age.
+	MaxAge time.Duration
+	// Offline answers from Cache alone, sending no queries.
+	Offline bool
+	// Resolver turns a hostname into the address whose network is looked
+	// up; nil uses the system resolver.
+	Resolver HostResolver
+}
+
+// OwnershipLookup finds who holds the networks of public addresses over
+// RDAP, querying each address once and sharing the answer between all of
+// its results.
+type OwnershipLookup struct {
+	opts   OwnershipOptions
+	client *http.Client
+
+	mu    sync.Mutex
+	addrs map[string]*addrOwnership
+}
+
+type addrOwnership struct {
+	once  sync.Once
+	owner *Ownership
+}
+
+// NewOwnershipLookup returns a lookup using opts.
+func NewOwnershipLookup(opts OwnershipOptions) *OwnershipLookup {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultOwnershipTimeout
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = DefaultRDAPEndpoint
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = DefaultOwnershipMaxAge
+	}
+	return &OwnershipLookup{
+		opts:   opts,
+		client: &http.Client{Timeout: opts.Timeout},
+		addrs:  make(map[string]*addrOwnership),
+	}
+}
+
+// Lookup returns who holds the network of host, resolving a hostname to
+// its first address as the scanner does. It returns nil for private
+// addresses, hosts that do not resolve, and networks the registry or the
+// cache has no answer for.
+func (l *OwnershipLookup) Lookup(ctx context.Context, host string) *Ownership {
+	addr := host
+	if net.ParseIP(host) == nil {
+		var addrs []string
+		var err error
+		if l.opts.Resolver != nil {
+			addrs, err = l.opts.Resolver.LookupHost(ctx, host)
+		} else {
+			addrs, err = net.DefaultResolver.LookupHost(ctx, host)
+		}
+		if err != nil || len(addrs) == 0 {
+			return nil
+		}
+		addr = addrs[0]
+	}
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return nil
+	}
+	ip = ip.Unmap().WithZone("")
+	if !IsPublicIP(net.IP(ip.AsSlice())) {
+		return nil
+	}
+
+	l.mu.Lock()
+	a, ok := l.addrs[ip.String()]
+	if !ok {
+		a = &addrOwnership{}
+		l.addrs[ip.String()] = a
+	}
+	l.mu.Unlock()
+
+	a.once.Do(func() { a.owner = l.find(ctx, ip) })
+	return a.owner
+}
+
+// find answers from the cache when it can, and otherwise queries RDAP and
+// caches the answer.
+func (l *OwnershipLookup) find(ctx context.Context, ip netip.Addr) *Ownership {
+	maxAge := l.opts.MaxAge
+	if l.opts.Offline {
+		maxAge = 0
+	}
+	if l.opts.Cache != nil {
+		if owner, ok := l.opts.Cache.Lookup(ip, maxAge); ok {
+			return owner
+		}
+	}
+	if l.opts.Offline {
+		return nil
+	}
+	owner, first, last, err := l.query(ctx, ip)
+	if err != nil {
+		return nil
+	}
+	if l.opts.Cache != nil {
+		l.opts.Cache.Store(first, last, owner, time.Now())
+	}
+	return owner
+}
+
+// rdapObject is the part of an RDAP IP network or entity response that
+// names the holder.
+type rdapObject struct {
+	Name         string          `json:"name"`
+	StartAddress string          `json:"startAddress"`
+	EndAddress   string          `json:"endAddress"`
+	Roles        []string        `json:"roles"`
+	VCard        json.RawMessage `json:"vcardArray"`
+	Entities     []rdapObject    `json:"entities"`
+}
+
+// query asks the RDAP service who holds the network of ip, returning the
+// network's first and last address for caching.
+func (l *OwnershipLookup) query(ctx context.Context, ip netip.Addr) (owner *Ownership, first, last netip.Addr, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.opts.Endpoint+ip.String(), nil)
+	if err != nil {
+		return nil, first, last, err
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+	req.Header.Set("User-Agent", "portscan")
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, first, last, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, first, last, fmt.Errorf("rdap %s: %s", ip, resp.Status)
+	}
+	var network rdapObject
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&network); err != nil {
+		return nil, first, last, fmt.Errorf("rdap %s: %w", ip, err)
+	}
+	return parseRDAPNetwork(network, ip)
+}
+
+// parseRDAPNetwork takes the holder from the registrant entity, falling
+// back to the network's name, and the abuse contact from the first abuse
+// entity at any depth. A response without a usable range covers ip alone.
+func parseRDAPNetwork(network rdapObject, ip netip.Addr) (owner *Ownership, first, last netip.Addr, err error) {
+	owner = &Ownership{Network: network.Name}
+	if registrant := findRDAPEntity(network.Entities, "registrant"); registrant != nil {
+		owner.Org = vcardField(registrant.VCard, "fn")
+	}
+	if owner.Org == "" {
+		owner.Org = network.Name
+	}
+	if abuse := findRDAPEntity(network.Entities, "abuse"); abuse != nil {
+		owner.Abuse = vcardField(abuse.VCard, "email")
+	}
+
+	first, err1 := netip.ParseAddr(network.StartAddress)
+	last, err2 := netip.ParseAddr(network.EndAddress)
+	if err1 != nil || err2 != nil || first.Is4() != ip.Is4() || first.Compare(ip) > 0 || last.Compare(ip) < 0 {
+		first, last = ip, ip
+	} else {
+		owner.Range = first.String() + " - " + last.String()
+	}
+	if owner.Org == "" && owner.Abuse == "" {
+		return nil, first, last, fmt.Errorf("rdap %s: no holder listed", ip)
+	}
+	return owner, first, last, nil
+}
+
+// findRDAPEntity returns the first entity with role, searching each
+// entity before the ones nested in it.
+func findRDAPEntity(entities []rdapObject, role string) *rdapObject {
+	for i := range entities {
+		for _, r := range entities[i].Roles {
+			if r == role {
+				return &entities[i]
+			}
+		}
+	}
+	for i := range entities {
+		if found := findRDAPEntity(entities[i].Entities, role); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// vcardField returns the text value of the named property of a jCard,
+// ["vcard", [[name, params, type, value], ...]], or "" when it has none.
+func vcardField(vcard json.RawMessage, name string) string {
+	var card []json.RawMessage
+	if json.Unmarshal(vcard, &card) != nil || len(card) < 2 {
+		return ""
+	}
+	var props [][]json.RawMessage
+	if json.Unmarshal(card[1], &props) != nil {
+		return ""
+	}
+	for _, prop := range props {
+		var propName, value string
+		if len(prop) < 4 || json.Unmarshal(prop[0], &propName) != nil || propName != name {
+			continue
+		}
+		if json.Unmarshal(prop[3], &value) == nil && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// AttachOwnership sets Owner on every result for a public host to who
+// holds its network, looking each address up once through lookup. Results
+// for private hosts pass through unchanged, as do other events. The
+// returned channel is closed once events is closed and every lookup has
+// finished.
+func AttachOwnership(ctx context.Context, events <-chan Event, lookup *OwnershipLookup) <-chan Event {
+	out := make(chan Event, cap(events))
+	jobs := make(chan ResultEvent)
+	var wg sync.WaitGroup
+
+	for i := 0; i < lookup.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for result := range jobs {
+				result.Owner = lookup.Lookup(ctx, result.Host)
+				select {
+				case out <- NewResultEvent(result):
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer func() {
+			close(jobs)
+			wg.Wait()
+			close(out)
+		}()
+		for event := range events {
+			if event.Kind == EventKindResult {
+				select {
+				case jobs <- *event.Result:
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// OwnershipCache keeps RDAP answers on disk by network, so addresses in a
+// network queried before, in this scan or an earlier one, are answered
+// without a query. It is safe for concurrent use.
+type OwnershipCache struct {
+	path string
+
+	mu      sync.Mutex
+	entries []ownershipEntry
+	dirty   bool
+}
+
+// ownershipEntry is one cached network.
+type ownershipEntry struct {
+	First   netip.Addr `json:"first"`
+	Last    netip.Addr `json:"last"`
+	Owner   Ownership  `json:"owner"`
+	Fetched time.Time  `json:"fetched"`
+}
+
+// LoadOwnershipCache reads the cache at path. A missing file is an empty
+// cache.
+func LoadOwnershipCache(path string) (*OwnershipCache, error) {
+	c := &OwnershipCache{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// Lookup returns the cached holder of the narrowest network containing
+// ip, ignoring answers older than maxAge unless it is zero.
+func (c *OwnershipCache) Lookup(ip netip.Addr, maxAge time.Duration) (*Ownership, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var best *ownershipEntry
+	for i := range c.entries {
+		e := &c.entries[i]
+		if e.First.Is4() != ip.Is4() || e.First.Compare(ip) > 0 || e.Last.Compare(ip) < 0 {
+			continue
+		}
+		if maxAge > 0 && time.Since(e.Fetched) > maxAge {
+			continue
+		}
+		if best == nil || e.First.Compare(best.First) >= 0 && e.Last.Compare(best.Last) <= 0 {
+			best = e
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+	owner := best.Owner
+	return &owner, true
+}
+
+// Store caches the holder of the network from first to last, replacing an
+// earlier answer for the same network.
+func (c *OwnershipCache) Store(first, last netip.Addr, owner *Ownership, fetched time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := ownershipEntry{First: first, Last: last, Owner: *owner, Fetched: fetched}
+	c.dirty = true
+	for i, e := range c.entries {
+		if e.First == first && e.Last == last {
+			c.entries[i] = entry
+			return
+		}
+	}
+	c.entries = append(c.entries, entry)
+}
+
+// Save writes the cache back to its file if anything was stored,
+// creating the directory if needed. The file is replaced atomically.
+func (c *OwnershipCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
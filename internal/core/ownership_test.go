@@ -0,0 +1,140 @@
+package core
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// rdapResponse is an ARIN-style answer nesting the abuse contact under the
+// registrant.
+const rdapResponse = `{
+  "objectClassName": "ip network",
+  "handle": "NET-203-0-113-0-1",
+  "name": "EXAMPLE-NET",
+  "startAddress": "203.0.113.0",
+  "endAddress": "203.0.113.255",
+  "entities": [{
+    "roles": ["registrant"],
+    "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Hosting Inc."]]],
+    "entities": [{
+      "roles": ["abuse"],
+      "vcardArray": ["vcard", [["fn", {}, "text", "Abuse"], ["email", {}, "text", "abuse@example.net"]]]
+    }]
+  }]
+}`
+
+func TestIsPublicIP(t *testing.T) {
+	for host, want := range map[string]bool{
+		"8.8.8.8":      true,
+		"2606:4700::1": true,
+		"10.1.2.3":     false,
+		"172.16.0.1":   false,
+		"169.254.1.1":  false,
+		"100.100.1.1":  false,
+		"::1":          false,
+	} {
+		if got := IsPublicIP(net.ParseIP(host)); got != want {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestOwnershipLookup(t *testing.T) {
+	var queries atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries.Add(1)
+		if !strings.HasPrefix(r.URL.Path, "/ip/203.0.113.") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rdap+json")
+		_, _ = w.Write([]byte(rdapResponse))
+	}))
+	defer server.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "rdap.json")
+	cache, err := LoadOwnershipCache(cachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := NewOwnershipLookup(OwnershipOptions{Endpoint: server.URL + "/ip/", Cache: cache})
+	ctx := context.Background()
+
+	want := Ownership{Org: "Example Hosting Inc.", Abuse: "abuse@example.net", Network: "EXAMPLE-NET", Range: "203.0.113.0 - 203.0.113.255"}
+	if owner := lookup.Lookup(ctx, "203.0.113.5"); owner == nil || *owner != want {
+		t.Fatalf("Lookup(203.0.113.5) = %+v, want %+v", owner, want)
+	}
+	// Another address in the network comes from the cache.
+	if owner := lookup.Lookup(ctx, "203.0.113.77"); owner == nil || owner.Org != want.Org {
+		t.Errorf("Lookup(203.0.113.77) = %+v, want %q", owner, want.Org)
+	}
+	if got := queries.Load(); got != 1 {
+		t.Errorf("%d RDAP queries, want 1", got)
+	}
+	if owner := lookup.Lookup(ctx, "10.0.0.1"); owner != nil {
+		t.Errorf("private address should not be looked up, got %+v", owner)
+	}
+	if owner := lookup.Lookup(ctx, "198.51.100.1"); owner != nil {
+		t.Errorf("unregistered address = %+v, want nil", owner)
+	}
+
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadOwnershipCache(cachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offline := NewOwnershipLookup(OwnershipOptions{Endpoint: server.URL + "/ip/", Cache: reloaded, Offline: true})
+	queries.Store(0)
+	if owner := offline.Lookup(ctx, "203.0.113.200"); owner == nil || *owner != want {
+		t.Errorf("offline Lookup = %+v, want %+v", owner, want)
+	}
+	if owner := offline.Lookup(ctx, "192.0.2.1"); owner != nil {
+		t.Errorf("offline Lookup of an uncached network = %+v, want nil", owner)
+	}
+	if got := queries.Load(); got != 0 {
+		t.Errorf("offline lookups sent %d RDAP queries", got)
+	}
+}
+
+func TestOwnershipCacheLookup(t *testing.T) {
+	cache, err := LoadOwnershipCache(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	cache.Store(netip.MustParseAddr("203.0.0.0"), netip.MustParseAddr("203.0.255.255"), &Ownership{Org: "Regional ISP"}, now)
+	cache.Store(netip.MustParseAddr("203.0.113.0"), netip.MustParseAddr("203.0.113.255"), &Ownership{Org: "Customer"}, now)
+	cache.Store(netip.MustParseAddr("203.0.114.0"), netip.MustParseAddr("203.0.114.255"), &Ownership{Org: "Old"}, now.Add(-48*time.Hour))
+
+	tests := []struct {
+		ip     string
+		maxAge time.Duration
+		want   string
+	}{
+		{"203.0.113.9", time.Hour, "Customer"},
+		{"203.0.7.1", time.Hour, "Regional ISP"},
+		{"203.0.114.1", time.Hour, "Regional ISP"},
+		{"203.0.114.1", 0, "Old"},
+		{"::ffff:203.1.0.1", 0, ""},
+	}
+	for _, tt := range tests {
+		owner, ok := cache.Lookup(netip.MustParseAddr(tt.ip).Unmap(), tt.maxAge)
+		got := ""
+		if ok {
+			got = owner.Org
+		}
+		if got != tt.want {
+			t.Errorf("Lookup(%s, %v) = %q, want %q", tt.ip, tt.maxAge, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// PauseGate suspends scan workers between probes. While paused, workers stop
+// acquiring rate tokens and do not dial; they resume where they left off.
+type PauseGate struct {
+	mu          sync.Mutex
+	paused      bool
+	resumed     chan struct{} // closed when the current pause ends
+	pausedAt    time.Time
+	pausedTotal time.Duration
+}
+
+// NewPauseGate creates a gate in the running state.
+func NewPauseGate() *PauseGate {
+	return &PauseGate{}
+}
+
+// Pause suspends workers at their next checkpoint. It is a no-op when
+// already paused.
+func (g *PauseGate) Pause() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.paused {
+		return
+	}
+	g.paused = true
+	g.resumed = make(chan struct{})
+	g.pausedAt = time.Now()
+}
+
+// Resume releases any waiting workers. It is a no-op when not paused.
+func (g *PauseGate) Resume() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.paused {
+		return
+	}
+	g.paused = false
+	g.pausedTotal += time.Since(g.pausedAt)
+	close(g.resumed)
+}
+
+// Paused reports whether the gate is currently closed.
+func (g *PauseGate) Paused() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.paused
+}
+
+// PausedDuration returns the total time spent paused, including any pause
+// still in progress.
+func (g *PauseGate) PausedDuration() time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	total := g.pausedTotal
+	if g.paused {
+		total += time.Since(g.pausedAt)
+	}
+	return total
+}
+
+// Wait blocks while the gate is paused. It returns false if the context is
+// cancelled before the scan resumes.
+func (g *PauseGate) Wait(ctx context.Context) bool {
+	g.mu.Lock()
+	if !g.paused {
+		g.mu.Unlock()
+		return ctx.Err() == nil
+	}
+	resumed := g.resumed
+	g.mu.Unlock()
+
+	select {
+	case <-resumed:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestPauseGateWaitBlocksUntilResume(t *testing.T) {
+	gate := NewPauseGate()
+	if !gate.Wait(context.Background()) {
+		t.Fatal("wait on a running gate should return immediately")
+	}
+
+	gate.Pause()
+	gate.Pause() // idempotent
+	if !gate.Paused() {
+		t.Fatal("gate should report paused")
+	}
+
+	released := make(chan bool, 1)
+	go func() { released <- gate.Wait(context.Background()) }()
+
+	select {
+	case <-released:
+		t.Fatal("wait returned while paused")
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	gate.Resume()
+	gate.Resume() // idempotent
+	select {
+	case ok := <-released:
+		if !ok {
+			t.Error("wait should succeed after resume")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("wait did not return after resume")
+	}
+
+	if gate.PausedDuration() < 30*time.Millisecond {
+		t.Errorf("paused duration too short: %v", gate.PausedDuration())
+	}
+}
+
+func TestPauseGateWaitHonorsCancellation(t *testing.T) {
+	gate := NewPauseGate()
+	gate.Pause()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if gate.Wait(ctx) {
+		t.Error("wait should fail once the context is cancelled")
+	}
+}
+
+func TestScannerPauseStopsProbing(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+	port := uint16(listener.Addr().(*net.TCPAddr).Port)
+
+	scanner := NewScanner(&Config{Workers: 1, Timeout: 200 * time.Millisecond})
+	scanner.Pause()
+
+	ports := []uint16{port, port, port}
+	go scanner.ScanRange(context.Background(), "127.0.0.1", ports)
+
+	results := 0
+	timeout := time.After(100 * time.Millisecond)
+waitPaused:
+	for {
+		select {
+		case event := <-scanner.Results():
+			if event.Kind == EventKindResult {
+				results++
+			}
+		case <-timeout:
+			break waitPaused
+		}
+	}
+	if results != 0 {
+		t.Fatalf("paused scanner produced %d results", results)
+	}
+
+	scanner.Resume()
+	for event := range scanner.Results() {
+		if event.Kind == EventKindResult {
+			results++
+		}
+	}
+	if results != len(ports) {
+		t.Errorf("expected %d results after resume, got %d", len(ports), results)
+	}
+}
@@ -3,6 +3,8 @@ package core
 import (
 	"context"
 	"math"
+	"slices"
+	"strings"
 	"sync/atomic"
 	"time"
 )
@@ -11,6 +13,23 @@ import (
 type ProgressReporter struct {
 	completed atomic.Uint64
 	results   chan<- Event
+	pause     *PauseGate // optional; paused time is excluded from the rate
+
+	// hosts counts each host's probes. It is built by TrackTargets before
+	// scanning starts and only read afterwards.
+	hosts          map[string]*hostCounter
+	completedHosts atomic.Int64
+
+	// failed counts probes that ended in a ScanError.
+	failed atomic.Int64
+}
+
+// hostCounter tracks the probes of one host.
+type hostCounter struct {
+	total   int64
+	started atomic.Bool
+	done    atomic.Int64
+	open    atomic.Int64
 }
 
 // NewProgressReporter creates a new progress reporter.
@@ -25,6 +44,92 @@ func (p *ProgressReporter) IncrementCompleted() {
 	p.completed.Add(1)
 }
 
+// TrackTargets records how many ports each host has so progress can
+// report per-host and completed-host counts. It must be called before
+// scanning starts.
+func (p *ProgressReporter) TrackTargets(targets []ScanTarget) {
+	p.hosts = make(map[string]*hostCounter, len(targets))
+	p.completedHosts.Store(0)
+	for _, t := range targets {
+		if len(t.Ports) == 0 {
+			continue
+		}
+		counter, ok := p.hosts[t.Host]
+		if !ok {
+			counter = &hostCounter{}
+			p.hosts[t.Host] = counter
+		}
+		counter.total += int64(len(t.Ports))
+	}
+}
+
+// StartJob reports whether a probe about to start is host's first.
+func (p *ProgressReporter) StartJob(host string) bool {
+	counter, ok := p.hosts[host]
+	return ok && counter.started.CompareAndSwap(false, true)
+}
+
+// CompleteJob records a finished probe for host, marking the host complete
+// when it was the last outstanding port. open reports whether the probe
+// found the port open. It reports whether the host just completed.
+func (p *ProgressReporter) CompleteJob(host string, open bool) bool {
+	p.completed.Add(1)
+	counter, ok := p.hosts[host]
+	if !ok {
+		return false
+	}
+	if open {
+		counter.open.Add(1)
+	}
+	if counter.done.Add(1) == counter.total {
+		p.completedHosts.Add(1)
+		return true
+	}
+	return false
+}
+
+// FailJob records a probe for host that failed with a ScanError. It counts
+// as finished like any other probe, as CompleteJob reports.
+func (p *ProgressReporter) FailJob(host string) bool {
+	p.failed.Add(1)
+	return p.CompleteJob(host, false)
+}
+
+// activeHosts lists the hosts with some but not all ports probed, least
+// complete first, at most ProgressHostLimit of them.
+func (p *ProgressReporter) activeHosts() []HostProgress {
+	var active []HostProgress
+	for host, counter := range p.hosts {
+		done := counter.done.Load()
+		if done == 0 || done >= counter.total {
+			continue
+		}
+		active = append(active, HostProgress{
+			Host:      host,
+			Total:     int(counter.total),
+			Completed: int(done),
+			Open:      int(counter.open.Load()),
+		})
+	}
+	return slowestHosts(active)
+}
+
+// slowestHosts sorts hosts least complete first, by name on ties, and
+// keeps the first ProgressHostLimit.
+func slowestHosts(hosts []HostProgress) []HostProgress {
+	slices.SortFunc(hosts, func(a, b HostProgress) int {
+		// Compare Completed/Total without division.
+		if diff := a.Completed*b.Total - b.Completed*a.Total; diff != 0 {
+			return diff
+		}
+		return strings.Compare(a.Host, b.Host)
+	})
+	if len(hosts) > ProgressHostLimit {
+		hosts = hosts[:ProgressHostLimit]
+	}
+	return hosts
+}
+
 // GetCompleted returns the current completed count.
 func (p *ProgressReporter) GetCompleted() uint64 {
 	return p.completed.Load()
@@ -51,6 +156,8 @@ func (p *ProgressReporter) reportProgress(ctx context.Context, total int) {
 	ticker := time.NewTicker(ProgressReportInterval)
 	defer ticker.Stop()
 	startTime := time.Now()
+	window := &rateWindow{}
+	window.add(0, 0)
 
 	for {
 		select {
@@ -68,13 +175,25 @@ func (p *ProgressReporter) reportProgress(ctx context.Context, total int) {
 			if completed > total {
 				completed = total
 			}
-			elapsed := time.Since(startTime).Seconds()
+			active := time.Since(startTime) - p.pausedDuration()
+			elapsed := active.Seconds()
 			if elapsed <= 0 {
 				elapsed = 0.001
 			}
 			rate := float64(completed) / elapsed
 
-			progress := ProgressEvent{Total: total, Completed: completed, Rate: rate}
+			progress := ProgressEvent{
+				Total:          total,
+				Completed:      completed,
+				Rate:           rate,
+				RecentRate:     window.add(active, completed),
+				Elapsed:        active,
+				Paused:         p.pause != nil && p.pause.Paused(),
+				TotalHosts:     len(p.hosts),
+				CompletedHosts: int(p.completedHosts.Load()),
+				Hosts:          p.activeHosts(),
+				Errors:         int(p.failed.Load()),
+			}
 			select {
 			case p.results <- NewProgressEvent(progress):
 			case <-ctx.Done():
@@ -87,3 +206,42 @@ func (p *ProgressReporter) reportProgress(ctx context.Context, total int) {
 		}
 	}
 }
+
+// pausedDuration returns how long the scan has spent paused.
+func (p *ProgressReporter) pausedDuration() time.Duration {
+	if p.pause == nil {
+		return 0
+	}
+	return p.pause.PausedDuration()
+}
+
+// rateWindow turns progress samples into the rate over the most recent
+// ProgressRateWindow of active scanning time. Samples are keyed by active
+// time, so a pause neither drags the rate down nor ages the window out.
+type rateWindow struct {
+	samples []rateSample
+}
+
+type rateSample struct {
+	active    time.Duration
+	completed int
+}
+
+// add records a sample and returns the probes per second since the oldest
+// sample still inside the window, or 0 until active time has passed.
+func (w *rateWindow) add(active time.Duration, completed int) float64 {
+	w.samples = append(w.samples, rateSample{active, completed})
+	// Keep the newest sample at or before the window start as the baseline.
+	drop := 0
+	for drop+1 < len(w.samples) && active-w.samples[drop+1].active >= ProgressRateWindow {
+		drop++
+	}
+	w.samples = w.samples[drop:]
+
+	base := w.samples[0]
+	span := (active - base.active).Seconds()
+	if span <= 0 {
+		return 0
+	}
+	return float64(completed-base.completed) / span
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestProgressReporterTracksHosts(t *testing.T) {
+	results := make(chan Event, 1)
+	reporter := NewProgressReporter(results)
+	reporter.TrackTargets([]ScanTarget{
+		{Host: "10.0.0.1", Ports: []uint16{22, 80}},
+		{Host: "10.0.0.2", Ports: []uint16{22, 80}},
+		{Host: "10.0.0.3"},
+	})
+
+	reporter.CompleteJob("10.0.0.1", true)
+	reporter.CompleteJob("10.0.0.2", false)
+	reporter.CompleteJob("10.0.0.1", false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	<-reporter.StartReporting(ctx, 4)
+
+	event := <-results
+	progress := event.Progress
+	if progress.Total != 4 || progress.Completed != 3 {
+		t.Errorf("ports = %d/%d, want 3/4", progress.Completed, progress.Total)
+	}
+	if progress.TotalHosts != 2 || progress.CompletedHosts != 1 {
+		t.Errorf("hosts = %d/%d, want 1/2", progress.CompletedHosts, progress.TotalHosts)
+	}
+	want := []HostProgress{{Host: "10.0.0.2", Total: 2, Completed: 1}}
+	if !slices.Equal(progress.Hosts, want) {
+		t.Errorf("active hosts = %+v, want %+v", progress.Hosts, want)
+	}
+}
+
+func TestRateWindow(t *testing.T) {
+	w := &rateWindow{}
+	w.add(0, 0)
+	if rate := w.add(5*time.Second, 500); rate != 100 {
+		t.Errorf("rate = %v, want 100", rate)
+	}
+	// A pause holds active time still; the rate is unchanged.
+	if rate := w.add(5*time.Second, 500); rate != 100 {
+		t.Errorf("rate while paused = %v, want 100", rate)
+	}
+	// Past the window, old samples age out and the rate follows the
+	// slower recent pace.
+	w.add(10*time.Second, 1000)
+	if rate := w.add(20*time.Second, 1100); rate != 10 {
+		t.Errorf("recent rate = %v, want 10", rate)
+	}
+	if len(w.samples) != 2 {
+		t.Errorf("window kept %d samples, want 2", len(w.samples))
+	}
+}
+
+func TestProgressReporterExcludesPauses(t *testing.T) {
+	results := make(chan Event, 16)
+	reporter := NewProgressReporter(results)
+	reporter.pause = NewPauseGate()
+	reporter.pause.Pause()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 350*time.Millisecond)
+	defer cancel()
+	<-reporter.StartReporting(ctx, 10)
+	close(results)
+
+	var last *ProgressEvent
+	for event := range results {
+		last = event.Progress
+	}
+	if last == nil || !last.Paused {
+		t.Fatalf("last progress = %+v, want a paused report", last)
+	}
+	if last.Elapsed > 50*time.Millisecond {
+		t.Errorf("elapsed = %v, want paused time excluded", last.Elapsed)
+	}
+}
+
+func TestSlowestHosts(t *testing.T) {
+	var hosts []HostProgress
+	for i := 0; i < ProgressHostLimit+2; i++ {
+		hosts = append(hosts, HostProgress{Host: fmt.Sprintf("10.0.0.%d", i), Total: 100, Completed: 50})
+	}
+	hosts = append(hosts, HostProgress{Host: "10.0.1.1", Total: 10, Completed: 1})
+
+	got := slowestHosts(hosts)
+	if len(got) != ProgressHostLimit {
+		t.Fatalf("got %d hosts, want %d", len(got), ProgressHostLimit)
+	}
+	if got[0].Host != "10.0.1.1" || got[1].Host != "10.0.0.0" {
+		t.Errorf("order = %s, %s; want the 10%% host first, then by name", got[0].Host, got[1].Host)
+	}
+}
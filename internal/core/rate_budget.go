@@ -0,0 +1,53 @@
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// RateBudget is a probe rate shared by several scanners, so scans running
+// side by side stay within one packets-per-second limit together. Each
+// tick lets exactly one probe, from whichever scanner takes it, go out.
+type RateBudget struct {
+	mu      sync.Mutex // guards rate and stopped
+	rate    int
+	stopped bool
+	ticker  *time.Ticker
+}
+
+// NewRateBudget returns a budget of rate probes per second, or nil for an
+// unlimited rate.
+func NewRateBudget(rate int) *RateBudget {
+	if rate <= 0 {
+		return nil
+	}
+	return &RateBudget{rate: rate, ticker: time.NewTicker(time.Second / time.Duration(rate))}
+}
+
+// SetRate changes the budget for every scanner drawing from it, clamped to
+// 1 through MaxSafeRateLimit, and returns the rate now in effect.
+func (b *RateBudget) SetRate(pps int) int {
+	pps = min(max(pps, 1), MaxSafeRateLimit)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rate = pps
+	if !b.stopped {
+		b.ticker.Reset(time.Second / time.Duration(pps))
+	}
+	return pps
+}
+
+// Rate returns the budget in probes per second.
+func (b *RateBudget) Rate() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rate
+}
+
+// Stop releases the budget once every scanner sharing it has finished.
+func (b *RateBudget) Stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stopped = true
+	b.ticker.Stop()
+}
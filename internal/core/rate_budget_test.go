@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewRateBudgetUnlimited(t *testing.T) {
+	if b := NewRateBudget(0); b != nil {
+		t.Errorf("NewRateBudget(0) = %v, want nil", b)
+	}
+}
+
+// TestRateBudgetSharedAcrossScanners checks that two scanners drawing from
+// one budget take as long as a single scanner probing all their ports, and
+// that the first to finish does not stop the budget for the other.
+func TestRateBudgetSharedAcrossScanners(t *testing.T) {
+	budget := NewRateBudget(100)
+	defer budget.Stop()
+
+	ports := make([]uint16, 15)
+	for i := range ports {
+		ports[i] = uint16(1 + i)
+	}
+	var scanners []*Scanner
+	for i := 0; i < 2; i++ {
+		scanners = append(scanners, NewScanner(&Config{
+			Workers:    4,
+			Timeout:    50 * time.Millisecond,
+			MaxRetries: 1,
+			RateLimit:  1000, // above the budget, so the budget paces
+			RateBudget: budget,
+		}))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, s := range scanners {
+		wg.Add(1)
+		go func(s *Scanner) {
+			defer wg.Done()
+			go s.ScanRange(ctx, "127.0.0.1", ports)
+			for range s.Results() {
+			}
+		}(s)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	if ctx.Err() != nil {
+		t.Fatal("scanners did not finish; the shared budget may have been stopped")
+	}
+	// 30 probes at 100 pps take at least ~300ms; separate 100 pps limits
+	// would finish in about half that.
+	if elapsed < 250*time.Millisecond {
+		t.Errorf("30 probes took %v, want at least 250ms under a shared 100 pps budget", elapsed)
+	}
+}
+
+// TestRateBudgetKeepsLowerRateLimit checks that a scanner whose own rate is
+// below the shared budget's is still paced at its own rate.
+func TestRateBudgetKeepsLowerRateLimit(t *testing.T) {
+	budget := NewRateBudget(1000)
+	defer budget.Stop()
+
+	s := NewScanner(&Config{
+		Workers:    4,
+		Timeout:    50 * time.Millisecond,
+		MaxRetries: 1,
+		RateLimit:  20,
+		RateBudget: budget,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	start := time.Now()
+	go s.ScanRange(ctx, "127.0.0.1", []uint16{1, 2, 3, 4, 5, 6})
+	for range s.Results() {
+	}
+	// 6 probes at 20 pps take at least ~300ms; at the budget's 1000 pps
+	// they would take a few milliseconds.
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("6 probes took %v, want at least 250ms at 20 pps", elapsed)
+	}
+}
+
+func TestRateBudgetSetRate(t *testing.T) {
+	budget := NewRateBudget(100)
+	if got := budget.SetRate(400); got != 400 || budget.Rate() != 400 {
+		t.Errorf("SetRate(400) = %d, Rate() = %d", got, budget.Rate())
+	}
+	if got := budget.SetRate(0); got != 1 {
+		t.Errorf("SetRate(0) = %d, want 1", got)
+	}
+	if got := budget.SetRate(MaxSafeRateLimit + 1); got != MaxSafeRateLimit {
+		t.Errorf("SetRate above the maximum = %d, want %d", got, MaxSafeRateLimit)
+	}
+
+	// A stopped budget must stay stopped.
+	budget.Stop()
+	budget.SetRate(1000)
+	select {
+	case <-budget.ticker.C:
+	default:
+	}
+	select {
+	case <-budget.ticker.C:
+		t.Error("SetRate restarted a stopped budget")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -0,0 +1,30 @@
+package core
+
+import (
+	"context"
+	"net"
+	"strconv"
+)
+
+// HostResolver looks up the addresses of a target hostname. Scanners given
+// one resolve each hostname through it, rather than through the system
+// resolver on every dial, and probe its first address.
+type HostResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// dialAddress returns the host:port to probe. With a resolver, a hostname
+// is replaced by its first address; IP addresses are used as they are.
+func dialAddress(ctx context.Context, resolver HostResolver, host string, port uint16) (string, error) {
+	if resolver != nil && net.ParseIP(host) == nil {
+		addrs, err := resolver.LookupHost(ctx, host)
+		if err != nil {
+			return "", err
+		}
+		if len(addrs) == 0 {
+			return "", &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		host = addrs[0]
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(port))), nil
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeResolver answers from a fixed table and counts lookups.
+type fakeResolver struct {
+	addrs   map[string][]string
+	lookups atomic.Int32
+}
+
+func (r *fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	r.lookups.Add(1)
+	if addrs, ok := r.addrs[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestDialAddress(t *testing.T) {
+	resolver := &fakeResolver{addrs: map[string][]string{"web.test": {"10.0.0.5", "10.0.0.6"}}}
+	tests := []struct {
+		name     string
+		resolver HostResolver
+		host     string
+		want     string
+	}{
+		{"hostname", resolver, "web.test", "10.0.0.5:80"},
+		{"ip", resolver, "10.0.0.9", "10.0.0.9:80"},
+		{"ipv6", resolver, "::1", "[::1]:80"},
+		{"no resolver", nil, "web.test", "web.test:80"},
+	}
+	for _, tt := range tests {
+		got, err := dialAddress(context.Background(), tt.resolver, tt.host, 80)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: dialAddress() = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+	if resolver.lookups.Load() != 1 {
+		t.Errorf("lookups = %d, want only the hostname looked up", resolver.lookups.Load())
+	}
+
+	_, err := dialAddress(context.Background(), resolver, "db.test", 80)
+	if !isProbeError(err) {
+		t.Errorf("unresolvable host: err = %v, want a probe error", err)
+	}
+}
+
+// TestScanner_UsesResolver verifies hostnames are probed at the resolved
+// address and reported under their name
+func TestScanner_UsesResolver(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	port := uint16(ln.Addr().(*net.TCPAddr).Port)
+
+	resolver := &fakeResolver{addrs: map[string][]string{"web.portscan.test": {"127.0.0.1"}}}
+	scanner := NewScanner(&Config{Workers: 1, Timeout: time.Second, Resolver: resolver})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go scanner.ScanRange(ctx, "web.portscan.test", []uint16{port})
+
+	var got *ResultEvent
+	for event := range scanner.Results() {
+		switch event.Kind {
+		case EventKindResult:
+			got = event.Result
+		case EventKindError:
+			var scanErr *ScanError
+			if !errors.As(event.Error, &scanErr) {
+				t.Fatalf("error event = %v", event.Error)
+			}
+			t.Fatalf("unexpected probe error: %v", scanErr)
+		}
+	}
+	if got == nil || got.Host != "web.portscan.test" || got.State != StateOpen {
+		t.Fatalf("result = %+v, want web.portscan.test open", got)
+	}
+}
+
+// TestScanner_AttachesHostname verifies results of a target scanned by
+// address carry the name it was resolved from
+func TestScanner_AttachesHostname(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	port := uint16(ln.Addr().(*net.TCPAddr).Port)
+
+	for _, scanner := range []PortScanner{
+		NewScanner(&Config{Workers: 1, Timeout: time.Second}),
+		NewUDPScanner(&Config{Workers: 1, Timeout: 100 * time.Millisecond, UDPWorkerRatio: 1}),
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		go scanner.ScanTargets(ctx, []ScanTarget{
+			{Host: "127.0.0.1", Ports: []uint16{port}, Hostname: "web.portscan.test"},
+		})
+		var results int
+		for event := range scanner.Results() {
+			if event.Kind != EventKindResult {
+				continue
+			}
+			results++
+			if event.Result.Hostname != "web.portscan.test" {
+				t.Errorf("%s result hostname = %q, want web.portscan.test", event.Result.Protocol, event.Result.Hostname)
+			}
+		}
+		cancel()
+		if results != 1 {
+			t.Errorf("got %d results, want 1", results)
+		}
+	}
+}
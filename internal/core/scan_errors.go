@@ -0,0 +1,67 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// ScanError describes a probe that failed for a reason unrelated to the
+// port's state, such as a failed DNS lookup or the process running out of
+// sockets. The probe produces no result; the error is reported instead.
+type ScanError struct {
+	Host     string
+	Port     uint16
+	Protocol string
+	Time     time.Time
+	Err      error
+}
+
+func (e *ScanError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Protocol, net.JoinHostPort(e.Host, strconv.Itoa(int(e.Port))), e.Err)
+}
+
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+// isProbeError reports whether a dial error reflects a local or resolver
+// failure rather than an answer from the target.
+func isProbeError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	for _, errno := range []syscall.Errno{
+		syscall.EMFILE, syscall.ENFILE, syscall.ENOBUFS,
+		syscall.EADDRNOTAVAIL, syscall.EACCES,
+	} {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
+
+// emitError reports a failed probe. The probe still counts as done for
+// progress so totals reach 100% even when some probes error out.
+func (s *Scanner) emitError(ctx context.Context, job scanJob, protocol string, err error) {
+	evt := NewErrorEvent(&ScanError{
+		Host:     job.host,
+		Port:     job.port,
+		Protocol: protocol,
+		Time:     time.Now(),
+		Err:      err,
+	})
+	select {
+	case s.results <- evt:
+		if s.progressReporter.FailJob(job.host) {
+			s.emitHostEvent(ctx, TimelineHostFinish, job.host)
+		}
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestIsProbeError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"dns", &net.DNSError{Err: "no such host", Name: "x.invalid", IsNotFound: true}, true},
+		{"fd exhaustion", &net.OpError{Op: "dial", Err: os.NewSyscallError("socket", syscall.EMFILE)}, true},
+		{"refused", &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, false},
+		{"other", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		if got := isProbeError(tt.err); got != tt.want {
+			t.Errorf("%s: isProbeError = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestScanError_Format(t *testing.T) {
+	cause := errors.New("too many open files")
+	err := &ScanError{Host: "10.0.0.1", Port: 443, Protocol: "tcp", Err: cause}
+
+	if got := err.Error(); got != "tcp 10.0.0.1:443: too many open files" {
+		t.Errorf("Error() = %q", got)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("ScanError should unwrap to its cause")
+	}
+}
+
+func TestScanner_EmitsErrorEventForUnresolvableHost(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	scanner := NewScanner(&Config{Workers: 1, Timeout: time.Second})
+	go scanner.ScanRange(ctx, "portscan-test.invalid", []uint16{80})
+
+	var results, scanErrors int
+	for event := range scanner.Results() {
+		switch event.Kind {
+		case EventKindResult:
+			results++
+		case EventKindError:
+			scanErrors++
+			var scanErr *ScanError
+			if !errors.As(event.Error, &scanErr) {
+				t.Fatalf("error event = %T, want *ScanError", event.Error)
+			}
+			if scanErr.Host != "portscan-test.invalid" || scanErr.Port != 80 || scanErr.Protocol != "tcp" {
+				t.Errorf("scan error = %+v", scanErr)
+			}
+		}
+	}
+	if results != 0 || scanErrors != 1 {
+		t.Errorf("results = %d, errors = %d; want 0 and 1", results, scanErrors)
+	}
+	if got := scanner.progressReporter.GetCompleted(); got != 1 {
+		t.Errorf("completed = %d, want the errored probe counted", got)
+	}
+	if got := scanner.progressReporter.failed.Load(); got != 1 {
+		t.Errorf("failed = %d, want the errored probe counted for the error rate", got)
+	}
+}
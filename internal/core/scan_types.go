@@ -18,14 +18,107 @@ type ResultEvent struct {
 	State    ScanState
 	Banner   string
 	Duration time.Duration
-	Protocol string // "tcp" or "udp"
+	Protocol string   // "tcp" or "udp"
+	Verified bool     // re-confirmed open by VerifyOpen
+	Hostname string   // name Host was resolved from, when scanned by address
+	Tags     []string // labels such as "env=prod" from --tag or the TUI
+	Note     string   // free-form note added in the TUI
+	Edge     string   // CDN/WAF provider fronting the port, e.g. "Cloudflare"
+
+	// Host-level names from --local-discovery, shared by every result for
+	// the host.
+	DeviceName string // mDNS or NetBIOS name, e.g. "office-printer"
+	DeviceType string // UPnP device type, e.g. "MediaRenderer"
+
+	// Where the host's address is, from --geoip-db, shared by every
+	// result for the host.
+	Country string // ISO 3166-1 alpha-2 code, e.g. "US"
+	ASN     uint32 // autonomous system number; 0 when unknown
+	ASOrg   string // autonomous system organization, e.g. "GOOGLE"
+
+	// Exposures are risks found by opt-in checks such as --check-ntp.
+	Exposures []Exposure
+
+	// TLS holds what --tls-inspect learned from the port's handshake; nil
+	// when the port was not inspected or did not complete one.
+	TLS *TLSInfo
+
+	// HTTPAudit grades the security headers of web ports under
+	// --http-audit; nil when the port was not audited.
+	HTTPAudit *HTTPAudit
+
+	// Owner is who the registry lists as holding a public host's network,
+	// from --owner-lookup; nil for private hosts or when it is unknown.
+	Owner *Ownership
+
+	// Transcript is the raw exchange a UDP banner was parsed from: the
+	// probe sent and the reply's bytes. A TCP banner is read without
+	// sending anything, so Banner is its whole exchange.
+	Transcript []ProbeExchange
 }
 
-// ProgressEvent reports high-level scanning progress.
+// ProbeExchange is one datagram of a probe's raw exchange with a port.
+type ProbeExchange struct {
+	Sent bool   // sent to the port; false for the port's reply
+	Data []byte // bytes as they went over the wire
+}
+
+// TLSInfo describes a TLS handshake and the certificate the server sent.
+type TLSInfo struct {
+	Version  string    // negotiated version, e.g. "TLS 1.3"
+	Cipher   string    // negotiated cipher suite
+	StartTLS string    // protocol upgraded with STARTTLS, e.g. "smtp"; empty for implicit TLS
+	Subject  string    // leaf certificate subject common name
+	Issuer   string    // leaf certificate issuer common name or organization
+	SANs     []string  // DNS names and IP addresses the certificate covers
+	NotAfter time.Time // leaf certificate expiry
+	JA3S     string    // JA3S fingerprint of the ServerHello, hex MD5
+}
+
+// HTTPAudit records which security headers a web port's response carried.
+type HTTPAudit struct {
+	Grade     string   // "A" (every header present) to "F"
+	Status    int      // status code of the audited response
+	Present   []string // security headers sent, e.g. "Content-Security-Policy"
+	Missing   []string // security headers not sent
+	Redirects []string // Location of each redirect, in order
+}
+
+// Exposure is a risk an opt-in check found on a port, such as an NTP
+// server answering monlist queries.
+type Exposure struct {
+	ID       string // e.g. "ntp_monlist"
+	Severity string // "high", "medium" or "low"
+	Message  string
+}
+
+// ProgressEvent reports high-level scanning progress. Total and Completed
+// count host/port probes across all hosts; a host counts as completed once
+// every one of its ports has been probed.
 type ProgressEvent struct {
-	Total     int
-	Completed int
-	Rate      float64
+	Total      int
+	Completed  int
+	Rate       float64 // probes per second over the whole scan, pauses excluded
+	RecentRate float64 // probes per second over the last ProgressRateWindow of active time
+	// Elapsed is the active scanning time: time paused, from the TUI or a
+	// scan window, is not counted.
+	Elapsed        time.Duration
+	Paused         bool
+	TotalHosts     int
+	CompletedHosts int
+	// Hosts lists hosts part way through their ports, least complete
+	// first, so stragglers stand out; at most ProgressHostLimit.
+	Hosts []HostProgress
+	// Errors counts the probes so far that failed with a ScanError.
+	Errors int
+}
+
+// HostProgress counts one host's probes.
+type HostProgress struct {
+	Host      string
+	Total     int // ports to probe
+	Completed int // ports probed
+	Open      int // ports found open
 }
 
 // EventKind identifies the type of event
@@ -35,6 +128,7 @@ const (
 	EventKindResult   EventKind = "result"
 	EventKindProgress EventKind = "progress"
 	EventKindError    EventKind = "error"
+	EventKindTimeline EventKind = "timeline"
 )
 
 // Event is a typed envelope for all scanner events
@@ -43,6 +137,7 @@ type Event struct {
 	Result   *ResultEvent
 	Progress *ProgressEvent
 	Error    error
+	Timeline *TimelineEntry
 }
 
 // Helper constructors
@@ -58,6 +153,10 @@ func NewErrorEvent(err error) Event {
 	return Event{Kind: EventKindError, Error: err}
 }
 
+func NewTimelineEvent(t TimelineEntry) Event {
+	return Event{Kind: EventKindTimeline, Timeline: &t}
+}
+
 // EventType is deprecated, use EventKind instead
 type EventType int
 
@@ -69,8 +168,25 @@ const (
 
 // ScanTarget represents a host with a set of ports to scan.
 type ScanTarget struct {
-	Host  string
-	Ports []uint16
+	Host     string
+	Ports    []uint16
+	Hostname string // name Host was resolved from; set on its results
+}
+
+// targetHostnames maps each target address to the name it was resolved
+// from, or returns nil when no target has one.
+func targetHostnames(targets []ScanTarget) map[string]string {
+	var names map[string]string
+	for _, t := range targets {
+		if t.Hostname == "" {
+			continue
+		}
+		if names == nil {
+			names = make(map[string]string)
+		}
+		names[t.Host] = t.Hostname
+	}
+	return names
 }
 
 // scanJob represents a single host/port pair fed to workers.
@@ -4,7 +4,6 @@ import (
 	"context"
 	"math/rand"
 	"net"
-	"strconv"
 	"sync"
 	"time"
 )
@@ -12,9 +11,28 @@ import (
 type Scanner struct {
 	config           *Config
 	results          chan Event
-	rateTicker       *time.Ticker
+	rateTicker       *time.Ticker // the scanner's own rate; nil when unlimited or set by the budget
+	budgetTicker     *time.Ticker // the shared RateBudget's ticker, which outlives the scan
+	rateMu           sync.Mutex   // guards rate and rateStopped
+	rate             int          // rateTicker's probes per second
+	rateStopped      bool         // rateTicker was stopped at the end of the scan
 	wg               sync.WaitGroup
 	progressReporter *ProgressReporter
+	pause            *PauseGate
+	hostLimit        *hostLimiter      // nil unless MaxHostParallelism is set
+	fds              *fdThrottle       // backs off when probes run out of file descriptors
+	hostnames        map[string]string // target names by address; set before workers start
+	addrPrefixes     map[string]string // preformatted "host:" dial prefixes; set before workers start
+	banners          chan bannerJob    // open connections awaiting a banner read; nil unless BannerGrab
+	bannerWG         sync.WaitGroup
+	bannerBufs       sync.Pool // *[]byte of BannerMaxBytes
+}
+
+// bannerJob is an open connection handed from a scan worker to the banner
+// pool, with the result to report once its banner has been read.
+type bannerJob struct {
+	conn   net.Conn
+	result ResultEvent
 }
 
 type Config struct {
@@ -25,8 +43,34 @@ type Config struct {
 	UDPJitterMaxMs int           // Maximum jitter in milliseconds for UDP scanning
 	RateLimit      int
 	BannerGrab     bool
+	BannerMaxBytes int           // bytes read from a banner; defaults to BannerBufferSize
+	BannerTimeout  time.Duration // banner read deadline; defaults to BannerGrabTimeout
+	// BannerWorkers reads banners in a pool separate from Workers, so slow
+	// banners do not hold up port discovery; defaults to DefaultBannerWorkers.
+	BannerWorkers  int
 	MaxRetries     int
 	UDPWorkerRatio float64 // Ratio of workers to use for UDP scanning (0.5 = half of TCP workers)
+	// PortTimeouts overrides Timeout and the banner/UDP read deadline for
+	// individual ports, so slow services do not slow the whole scan.
+	PortTimeouts map[uint16]time.Duration
+	// ProbeJitter adds a random delay of up to this long before each TCP
+	// probe, breaking up the regular rhythm of rate-limited scans.
+	ProbeJitter time.Duration
+	// MaxHostParallelism caps concurrent probes per host; 0 is unlimited.
+	MaxHostParallelism int
+	// RateBudget, when set, paces probes against a rate shared with other
+	// scanners. A lower RateLimit still applies on top of it.
+	RateBudget *RateBudget
+	// Resolver, when set, resolves target hostnames before each probe;
+	// nil leaves them to the system resolver at dial time.
+	Resolver HostResolver
+	// UDPProbes replaces the UDP scanner's built-in probe for each port
+	// listed, as if added with AddProbe.
+	UDPProbes map[uint16]UDPProbe
+	// SNMPCommunities, when set, are tried against UDP 161 in place of the
+	// built-in probe, and the sysDescr and sysName of the first community
+	// that answers become the banner.
+	SNMPCommunities []string
 }
 
 func NewScanner(cfg *Config) *Scanner {
@@ -52,26 +96,82 @@ func NewScanner(cfg *Config) *Scanner {
 	if cfg.RateLimit < 0 {
 		cfg.RateLimit = 0
 	}
+	if cfg.BannerMaxBytes <= 0 {
+		cfg.BannerMaxBytes = BannerBufferSize
+	}
+	if cfg.BannerTimeout <= 0 {
+		cfg.BannerTimeout = BannerGrabTimeout
+	}
+	if cfg.BannerWorkers <= 0 {
+		cfg.BannerWorkers = DefaultBannerWorkers
+	}
 	// Set default UDP worker ratio if not specified
 	if cfg.UDPWorkerRatio <= 0 {
 		cfg.UDPWorkerRatio = DefaultUDPWorkerRatio
 	}
 
-	var ticker *time.Ticker
-	if cfg.RateLimit > 0 {
+	var ticker, budgetTicker *time.Ticker
+	if cfg.RateBudget != nil {
+		budgetTicker = cfg.RateBudget.ticker
+	}
+	if cfg.RateLimit > 0 && (cfg.RateBudget == nil || cfg.RateLimit < cfg.RateBudget.Rate()) {
 		interval := time.Second / time.Duration(cfg.RateLimit)
 		ticker = time.NewTicker(interval)
 	}
 
 	resultsChan := make(chan Event, ResultChannelBufferSize)
+	pause := NewPauseGate()
+	reporter := NewProgressReporter(resultsChan)
+	reporter.pause = pause
 	return &Scanner{
 		config:           cfg,
 		results:          resultsChan,
 		rateTicker:       ticker,
-		progressReporter: NewProgressReporter(resultsChan),
+		rate:             cfg.RateLimit,
+		budgetTicker:     budgetTicker,
+		progressReporter: reporter,
+		pause:            pause,
 	}
 }
 
+// Pause suspends workers before their next probe until Resume is called.
+func (s *Scanner) Pause() { s.pause.Pause() }
+
+// Resume continues a paused scan.
+func (s *Scanner) Resume() { s.pause.Resume() }
+
+// Paused reports whether the scan is currently paused.
+func (s *Scanner) Paused() bool { return s.pause.Paused() }
+
+// SetRate changes the scanner's own rate. A scanner without one, because
+// its rate is unlimited or set by a RateBudget alone, cannot be changed.
+func (s *Scanner) SetRate(pps int) int {
+	if s.rateTicker == nil {
+		return 0
+	}
+	pps = min(max(pps, 1), MaxSafeRateLimit)
+	s.rateMu.Lock()
+	defer s.rateMu.Unlock()
+	s.rate = pps
+	if !s.rateStopped {
+		s.rateTicker.Reset(time.Second / time.Duration(pps))
+	}
+	return pps
+}
+
+// RateBudget returns the budget the scanner shares with others, or nil.
+func (s *Scanner) RateBudget() *RateBudget { return s.config.RateBudget }
+
+// Rate returns the scanner's own rate, or 0 when it has none.
+func (s *Scanner) Rate() int {
+	if s.rateTicker == nil {
+		return 0
+	}
+	s.rateMu.Lock()
+	defer s.rateMu.Unlock()
+	return s.rate
+}
+
 func (s *Scanner) jobBufferSize(total int) int {
 	if total <= 0 {
 		return 0
@@ -95,23 +195,28 @@ func (s *Scanner) ScanRange(ctx context.Context, host string, ports []uint16) {
 func (s *Scanner) ScanTargets(ctx context.Context, targets []ScanTarget) {
 	totalPorts := totalPortCount(targets)
 	if totalPorts == 0 {
-		if s.rateTicker != nil {
-			s.rateTicker.Stop()
-		}
+		s.stopRate()
 		close(s.results)
 		return
 	}
 
 	s.progressReporter.SetCompleted(0)
+	s.progressReporter.TrackTargets(targets)
+	s.hostLimit = newHostLimiter(targets, s.config.MaxHostParallelism)
+	s.fds = newFDThrottle(s.config.Workers)
+	s.hostnames = targetHostnames(targets)
+	s.addrPrefixes = hostAddrPrefixes(targets, s.config.Resolver)
 
 	jobs := make(chan scanJob, s.jobBufferSize(totalPorts))
 	progressDone := s.progressReporter.StartReporting(ctx, totalPorts)
 
+	s.startBannerWorkers(ctx)
 	s.startWorkers(ctx, jobs)
 
 	go s.feedJobs(ctx, jobs, targets)
 
 	s.wg.Wait()
+	s.stopBannerWorkers()
 
 	s.finishScan(ctx, progressDone)
 }
@@ -123,6 +228,52 @@ func (s *Scanner) startWorkers(ctx context.Context, jobs <-chan scanJob) {
 	}
 }
 
+// startBannerWorkers starts the banner pool when banners are grabbed.
+func (s *Scanner) startBannerWorkers(ctx context.Context) {
+	if !s.config.BannerGrab {
+		return
+	}
+	s.banners = make(chan bannerJob, s.config.BannerWorkers)
+	for i := 0; i < s.config.BannerWorkers; i++ {
+		s.bannerWG.Add(1)
+		go s.bannerWorker(ctx)
+	}
+}
+
+// stopBannerWorkers waits for the banner pool to drain once every scan
+// worker has finished.
+func (s *Scanner) stopBannerWorkers() {
+	if s.banners == nil {
+		return
+	}
+	close(s.banners)
+	s.bannerWG.Wait()
+}
+
+// bannerWorker reads banners from connections found open and reports
+// their results. Connections still queued after cancellation are closed
+// without a read.
+func (s *Scanner) bannerWorker(ctx context.Context) {
+	defer s.bannerWG.Done()
+	for job := range s.banners {
+		if ctx.Err() == nil {
+			job.result.Banner = s.grabBanner(job.conn, s.readTimeoutFor(job.result.Port, s.config.BannerTimeout))
+		}
+		_ = job.conn.Close()
+		s.emitResult(ctx, job.result)
+	}
+}
+
+// queueBanner hands an open connection to the banner pool, closing it if
+// the scan is cancelled first.
+func (s *Scanner) queueBanner(ctx context.Context, conn net.Conn, result ResultEvent) {
+	select {
+	case s.banners <- bannerJob{conn: conn, result: result}:
+	case <-ctx.Done():
+		_ = conn.Close()
+	}
+}
+
 func (s *Scanner) feedJobs(ctx context.Context, jobs chan<- scanJob, targets []ScanTarget) {
 	defer close(jobs)
 	for _, target := range targets {
@@ -143,17 +294,25 @@ func (s *Scanner) finishScan(ctx context.Context, progressDone <-chan struct{})
 	case <-ctx.Done():
 	}
 
+	s.stopRate()
+	close(s.results)
+}
+
+// stopRate stops the scanner's own rate ticker; a shared RateBudget keeps
+// running for the other scanners.
+func (s *Scanner) stopRate() {
 	if s.rateTicker != nil {
+		s.rateMu.Lock()
+		s.rateStopped = true
 		s.rateTicker.Stop()
+		s.rateMu.Unlock()
 	}
-
-	close(s.results)
 }
 
 func (s *Scanner) worker(ctx context.Context, jobs <-chan scanJob) {
 	defer s.wg.Done()
 
-	dialer := &net.Dialer{Timeout: s.config.Timeout}
+	scratch := newProbeScratch(s.config.Timeout)
 
 	for job := range jobs {
 		// Check context cancellation
@@ -161,21 +320,81 @@ func (s *Scanner) worker(ctx context.Context, jobs <-chan scanJob) {
 			return
 		}
 
+		// Hold here while paused so no rate tokens are consumed
+		if !s.pause.Wait(ctx) {
+			return
+		}
+
 		// Rate limiting at worker level
 		if !s.waitForRate(ctx) {
 			return
 		}
 
+		if !s.waitProbeJitter(ctx) {
+			return
+		}
+
+		// A pause requested while waiting for a token takes effect before dialing
+		if !s.pause.Wait(ctx) {
+			return
+		}
+
+		if !s.hostLimit.acquire(ctx, job.host) {
+			return
+		}
+		if s.progressReporter.StartJob(job.host) {
+			s.emitHostEvent(ctx, TimelineHostStart, job.host)
+		}
 		// Scan port inline
-		result := s.performDial(ctx, dialer, job)
-		if result != nil {
-			s.emitResult(ctx, *result)
+		result, ok, err := s.throttledDial(ctx, scratch, job)
+		s.hostLimit.release(job.host)
+		switch {
+		case err != nil:
+			s.emitError(ctx, job, "tcp", err)
+		case ok:
+			s.emitResult(ctx, result)
 		}
 	}
 }
 
-func (s *Scanner) performDial(ctx context.Context, dialer *net.Dialer, job scanJob) *ResultEvent {
-	address := net.JoinHostPort(job.host, strconv.Itoa(int(job.port)))
+// throttledDial runs performDial under the descriptor throttle. A probe
+// that hits the file descriptor limit is retried after a backoff while the
+// throttle lowers concurrency; only repeated failures are reported.
+func (s *Scanner) throttledDial(ctx context.Context, scratch *probeScratch, job scanJob) (ResultEvent, bool, error) {
+	for attempt := 0; ; attempt++ {
+		if !s.fds.acquire(ctx) {
+			return ResultEvent{}, false, nil
+		}
+		result, ok, err := s.performDial(ctx, scratch, job)
+		exhausted := isFDExhausted(err)
+		s.fds.release(exhausted)
+		if !exhausted || attempt >= fdExhaustedRetries {
+			return result, ok, err
+		}
+		if !s.sleepWithJitter(ctx, attempt) {
+			return ResultEvent{}, false, nil
+		}
+	}
+}
+
+// performDial probes one TCP port. It reports ok false when the scan was
+// cancelled or an open port was queued for a banner read, and an error
+// when the dial failed for a reason unrelated to the port's state.
+func (s *Scanner) performDial(ctx context.Context, scratch *probeScratch, job scanJob) (ResultEvent, bool, error) {
+	var address string
+	if prefix, ok := s.addrPrefixes[job.host]; ok {
+		address = scratch.address(prefix, job.port)
+	} else {
+		var err error
+		address, err = dialAddress(ctx, s.config.Resolver, job.host, job.port)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ResultEvent{}, false, nil
+			}
+			return ResultEvent{}, false, err
+		}
+	}
+	dialer := scratch.dialerFor(job.port, s.config.PortTimeouts)
 	maxAttempts := s.config.MaxRetries + 1
 	if maxAttempts <= 0 {
 		maxAttempts = 1
@@ -196,7 +415,10 @@ func (s *Scanner) performDial(ctx context.Context, dialer *net.Dialer, job scanJ
 
 		if err != nil {
 			if ctx.Err() != nil {
-				return nil
+				return ResultEvent{}, false, nil
+			}
+			if isProbeError(err) {
+				return ResultEvent{}, false, err
 			}
 
 			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
@@ -204,7 +426,7 @@ func (s *Scanner) performDial(ctx context.Context, dialer *net.Dialer, job scanJ
 				lastResult = result
 				if attempt < maxAttempts-1 {
 					if !s.sleepWithJitter(ctx, attempt) {
-						return nil
+						return ResultEvent{}, false, nil
 					}
 					continue
 				}
@@ -215,45 +437,46 @@ func (s *Scanner) performDial(ctx context.Context, dialer *net.Dialer, job scanJ
 			}
 		} else {
 			result.State = StateOpen
-			if s.config.BannerGrab {
-				result.Banner = s.grabBanner(conn)
+			if s.banners != nil {
+				// The banner pool reports the result once the banner is read.
+				s.queueBanner(ctx, conn, result)
+				return ResultEvent{}, false, nil
 			}
 			_ = conn.Close()
-			return &result
+			return result, true, nil
 		}
 	}
 
-	return &lastResult
+	return lastResult, true, nil
 }
 
+// waitForRate blocks until the scanner's own rate and then the shared
+// budget allow the next probe. It returns false if ctx is cancelled first.
 func (s *Scanner) waitForRate(ctx context.Context) bool {
-	if s.rateTicker == nil {
-		return true
-	}
-
-	select {
-	case <-ctx.Done():
-		return false
-	case <-s.rateTicker.C:
-		return true
+	for _, ticker := range []*time.Ticker{s.rateTicker, s.budgetTicker} {
+		if ticker == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
 	}
+	return true
 }
 
 func (s *Scanner) sleepWithJitter(ctx context.Context, attempt int) bool {
-	wait := s.retryBackoff(attempt)
-	if wait <= 0 {
-		return true
-	}
-
-	timer := time.NewTimer(wait)
-	defer timer.Stop()
+	return sleepContext(ctx, s.retryBackoff(attempt))
+}
 
-	select {
-	case <-ctx.Done():
-		return false
-	case <-timer.C:
+// waitProbeJitter sleeps for a random fraction of ProbeJitter. It returns
+// false if the context is cancelled while waiting.
+func (s *Scanner) waitProbeJitter(ctx context.Context) bool {
+	if s.config.ProbeJitter <= 0 {
 		return true
 	}
+	return sleepContext(ctx, time.Duration(rand.Int63n(int64(s.config.ProbeJitter))))
 }
 
 func (s *Scanner) retryBackoff(attempt int) time.Duration {
@@ -271,20 +494,35 @@ func (s *Scanner) retryBackoff(attempt int) time.Duration {
 }
 
 func (s *Scanner) emitResult(ctx context.Context, result ResultEvent) {
+	if name, ok := s.hostnames[result.Host]; ok {
+		result.Hostname = name
+	}
 	evt := NewResultEvent(result)
 	select {
 	case s.results <- evt:
-		s.progressReporter.IncrementCompleted()
+		if s.progressReporter.CompleteJob(result.Host, result.State == StateOpen) {
+			s.emitHostEvent(ctx, TimelineHostFinish, result.Host)
+		}
 	case <-ctx.Done():
 	}
 }
 
-func (s *Scanner) grabBanner(conn net.Conn) string {
-	_ = conn.SetReadDeadline(time.Now().Add(BannerGrabTimeout))
-	buffer := make([]byte, BannerBufferSize)
-	n, err := conn.Read(buffer)
+// readTimeoutFor returns the read deadline for port: its override from
+// PortTimeouts, or fallback when it has none.
+func (s *Scanner) readTimeoutFor(port uint16, fallback time.Duration) time.Duration {
+	if timeout, ok := s.config.PortTimeouts[port]; ok {
+		return timeout
+	}
+	return fallback
+}
+
+func (s *Scanner) grabBanner(conn net.Conn, timeout time.Duration) string {
+	_ = conn.SetReadDeadline(time.Now().Add(timeout))
+	buf := s.bannerBuffer()
+	defer s.putBannerBuffer(buf)
+	n, err := conn.Read(*buf)
 	if err != nil || n == 0 {
 		return ""
 	}
-	return string(buffer[:n])
+	return string((*buf)[:n])
 }
@@ -3,8 +3,11 @@ package core
 import (
 	"context"
 	"net"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/testserver"
 )
 
 func TestScannerEndToEnd(t *testing.T) {
@@ -74,3 +77,153 @@ func TestScannerEndToEnd(t *testing.T) {
 		t.Fatalf("expected port %d to be reported closed, got %s", closedPort, state)
 	}
 }
+
+func TestScannerEndToEnd_MockServer(t *testing.T) {
+	server, err := testserver.Start("", []testserver.Service{
+		{Protocol: "tcp", Banner: "SSH-2.0-mock\r\n", Delay: 20 * time.Millisecond},
+		{Protocol: "tcp", Reset: true},
+		{Protocol: "udp", Banner: "pong"},
+	})
+	if err != nil {
+		t.Fatalf("failed to start mock server: %v", err)
+	}
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tcpPorts := server.Ports("tcp")
+	scanner := NewScanner(&Config{Workers: 2, Timeout: 500 * time.Millisecond, BannerGrab: true})
+	go scanner.ScanRange(ctx, server.Host, tcpPorts)
+
+	results := make(map[uint16]*ResultEvent)
+	for event := range scanner.Results() {
+		if event.Kind == EventKindResult {
+			results[event.Result.Port] = event.Result
+		}
+	}
+
+	banner := results[tcpPorts[0]]
+	if banner == nil || banner.State != StateOpen || banner.Banner != "SSH-2.0-mock\r\n" {
+		t.Errorf("banner service result = %+v, want open with SSH-2.0-mock", banner)
+	}
+	if reset := results[tcpPorts[1]]; reset == nil || reset.State != StateOpen || reset.Banner != "" {
+		t.Errorf("reset service result = %+v, want open without banner", reset)
+	}
+
+	udpPort := server.Ports("udp")[0]
+	udp := NewUDPScanner(&Config{Workers: 1, Timeout: 500 * time.Millisecond})
+	go udp.ScanRange(ctx, server.Host, []uint16{udpPort})
+
+	var udpResult *ResultEvent
+	for event := range udp.Results() {
+		if event.Kind == EventKindResult {
+			udpResult = event.Result
+		}
+	}
+	if udpResult == nil || udpResult.State != StateOpen {
+		t.Errorf("udp result = %+v, want open", udpResult)
+	}
+}
+
+func TestScannerPortTimeoutOverride(t *testing.T) {
+	server, err := testserver.Start("", []testserver.Service{
+		{Protocol: "tcp", Banner: "slow", Delay: 150 * time.Millisecond},
+		{Protocol: "tcp", Banner: "slow", Delay: 150 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("failed to start mock server: %v", err)
+	}
+	defer server.Close()
+
+	ports := server.Ports("tcp")
+	scanner := NewScanner(&Config{
+		Workers:      2,
+		Timeout:      500 * time.Millisecond,
+		BannerGrab:   true,
+		PortTimeouts: map[uint16]time.Duration{ports[0]: 20 * time.Millisecond},
+	})
+
+	if got := scanner.readTimeoutFor(ports[0], BannerGrabTimeout); got != 20*time.Millisecond {
+		t.Errorf("override read timeout = %v, want 20ms", got)
+	}
+	if got := scanner.readTimeoutFor(ports[1], BannerGrabTimeout); got != BannerGrabTimeout {
+		t.Errorf("default read timeout = %v, want %v", got, BannerGrabTimeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	go scanner.ScanRange(ctx, server.Host, ports)
+
+	banners := make(map[uint16]string)
+	for event := range scanner.Results() {
+		if event.Kind == EventKindResult {
+			banners[event.Result.Port] = event.Result.Banner
+		}
+	}
+	if banners[ports[0]] != "" {
+		t.Errorf("port with short override read banner %q, want none", banners[ports[0]])
+	}
+	if banners[ports[1]] != "slow" {
+		t.Errorf("port without override banner = %q, want slow", banners[ports[1]])
+	}
+}
+
+func TestScannerBannerMaxBytes(t *testing.T) {
+	banner := strings.Repeat("A", 64) + "\x00\xff"
+	server, err := testserver.Start("", []testserver.Service{{Protocol: "tcp", Banner: banner}})
+	if err != nil {
+		t.Fatalf("failed to start mock server: %v", err)
+	}
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	scanner := NewScanner(&Config{Workers: 1, Timeout: 500 * time.Millisecond, BannerGrab: true, BannerMaxBytes: 16})
+	go scanner.ScanRange(ctx, server.Host, server.Ports("tcp"))
+
+	var got string
+	for event := range scanner.Results() {
+		if event.Kind == EventKindResult {
+			got = event.Result.Banner
+		}
+	}
+	if got != banner[:16] {
+		t.Errorf("banner = %q, want the first 16 bytes", got)
+	}
+}
+
+func TestScannerBannerPoolDoesNotBlockDiscovery(t *testing.T) {
+	const slow = 200 * time.Millisecond
+	services := make([]testserver.Service, 5)
+	for i := range services {
+		services[i] = testserver.Service{Protocol: "tcp", Banner: "slow", Delay: slow}
+	}
+	server, err := testserver.Start("", services)
+	if err != nil {
+		t.Fatalf("failed to start mock server: %v", err)
+	}
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// A single scan worker would take five banner delays reading them inline.
+	scanner := NewScanner(&Config{Workers: 1, Timeout: 500 * time.Millisecond, BannerGrab: true, BannerWorkers: 5})
+	start := time.Now()
+	go scanner.ScanRange(ctx, server.Host, server.Ports("tcp"))
+
+	banners := 0
+	for event := range scanner.Results() {
+		if event.Kind == EventKindResult && event.Result.Banner == "slow" {
+			banners++
+		}
+	}
+	if banners != len(services) {
+		t.Errorf("read %d banners, want %d", banners, len(services))
+	}
+	if elapsed := time.Since(start); elapsed >= 3*slow {
+		t.Errorf("scan took %v; banner reads should overlap instead of holding the scan worker", elapsed)
+	}
+}
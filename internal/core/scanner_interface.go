@@ -16,6 +16,46 @@ type PortScanner interface {
 	ScanTargets(ctx context.Context, targets []ScanTarget)
 }
 
+// Pausable is implemented by scanners that can suspend and resume workers
+// mid-scan without losing queued work.
+type Pausable interface {
+	Pause()
+	Resume()
+	Paused() bool
+}
+
+// RateAdjustable is implemented by scanners whose probe rate can be
+// changed mid-scan.
+type RateAdjustable interface {
+	// SetRate changes the probes per second, clamped to 1 through
+	// MaxSafeRateLimit, and returns the rate now in effect, or 0 when the
+	// scan's rate cannot be changed.
+	SetRate(pps int) int
+	// Rate returns the probes per second, or 0 when the scan's rate cannot
+	// be changed.
+	Rate() int
+}
+
+// RateBudgeted is implemented by scanners that may draw their probes from a
+// RateBudget shared with other scanners.
+type RateBudgeted interface {
+	// RateBudget returns the shared budget, or nil when there is none.
+	RateBudget() *RateBudget
+}
+
 // Ensure Scanner implements PortScanner interface
 var _ PortScanner = (*Scanner)(nil)
 var _ PortScanner = (*UDPScanner)(nil)
+
+// Ensure both scanners can be paused
+var _ Pausable = (*Scanner)(nil)
+var _ Pausable = (*UDPScanner)(nil)
+
+// Ensure both scanners' rates can be adjusted
+var _ RateAdjustable = (*Scanner)(nil)
+var _ RateAdjustable = (*UDPScanner)(nil)
+var _ RateAdjustable = (*RateBudget)(nil)
+
+// Ensure both scanners report a shared budget
+var _ RateBudgeted = (*Scanner)(nil)
+var _ RateBudgeted = (*UDPScanner)(nil)
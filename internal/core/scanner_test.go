@@ -46,6 +46,42 @@ func TestScannerChannels(t *testing.T) {
 	}
 }
 
+func TestScannerSetRate(t *testing.T) {
+	scanner := NewScanner(&Config{Workers: 1, RateLimit: 1000})
+	defer scanner.stopRate()
+
+	if got := scanner.Rate(); got != 1000 {
+		t.Errorf("Rate() = %d, want 1000", got)
+	}
+	for _, tt := range []struct{ set, want int }{
+		{1500, 1500},
+		{0, 1},
+		{MaxSafeRateLimit + 500, MaxSafeRateLimit},
+	} {
+		if got := scanner.SetRate(tt.set); got != tt.want {
+			t.Errorf("SetRate(%d) = %d, want %d", tt.set, got, tt.want)
+		}
+		if got := scanner.Rate(); got != tt.want {
+			t.Errorf("Rate() after SetRate(%d) = %d, want %d", tt.set, got, tt.want)
+		}
+	}
+
+	// Once the scan has stopped its ticker, changes are recorded but the
+	// ticker stays stopped.
+	scanner.stopRate()
+	scanner.SetRate(10000)
+	select {
+	case <-scanner.rateTicker.C:
+		t.Error("SetRate restarted a stopped rate ticker")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	unlimited := NewScanner(&Config{Workers: 1})
+	if got := unlimited.SetRate(500); got != 0 || unlimited.Rate() != 0 {
+		t.Errorf("unlimited scanner SetRate(500) = %d, Rate() = %d; want 0, 0", got, unlimited.Rate())
+	}
+}
+
 func TestConfigValidation(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Timeline entry kinds.
+const (
+	TimelineScanStart  = "scan_start"
+	TimelineScanFinish = "scan_finish"
+	TimelineHostStart  = "host_start"
+	TimelineHostFinish = "host_finish"
+	TimelineRateChange = "rate_change"
+	TimelinePaused     = "paused"
+	TimelineResumed    = "resumed"
+	TimelineError      = "error"
+)
+
+// Timeline limits
+const (
+	// TimelineEntryLimit caps the entries a Timeline keeps so a long scan
+	// with many hosts or errors cannot grow it without bound. The closing
+	// scan_finish entry is always kept.
+	TimelineEntryLimit = 10000
+
+	// timelineRateShift is how far the recent rate must move, as a
+	// fraction of the last recorded rate, to be recorded again.
+	timelineRateShift = 0.25
+)
+
+// TimelineEntry is one moment in a scan's lifecycle.
+type TimelineEntry struct {
+	Time   time.Time
+	Kind   string // one of the Timeline* kinds
+	Host   string
+	Port   uint16
+	Rate   float64 // probes per second, for rate changes
+	Detail string
+}
+
+// Timeline records lifecycle entries from a scan's event stream: the
+// start, hosts starting and finishing, shifts in the recent probe rate,
+// pauses, errors, and the finish. It is safe for concurrent use, so an
+// interactive session can read it while the scan is still draining.
+type Timeline struct {
+	mu      sync.Mutex
+	entries []TimelineEntry
+	dropped int
+
+	rate   float64 // last recorded recent rate
+	paused bool
+	hosts  map[string]int // streams still probing each host
+}
+
+// NewTimeline returns a Timeline whose scan_start entry is now.
+func NewTimeline() *Timeline {
+	t := &Timeline{hosts: make(map[string]int)}
+	t.add(TimelineEntry{Time: time.Now(), Kind: TimelineScanStart})
+	return t
+}
+
+// Observe records what event says about the scan's lifecycle. Results are
+// ignored. When TCP and UDP run together a host starts with its first
+// stream and finishes with its last.
+func (t *Timeline) Observe(event Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch event.Kind {
+	case EventKindTimeline:
+		if event.Timeline != nil {
+			t.observeHost(*event.Timeline)
+		}
+	case EventKindProgress:
+		if event.Progress != nil {
+			t.observeProgress(*event.Progress)
+		}
+	case EventKindError:
+		if event.Error != nil {
+			t.observeError(event.Error)
+		}
+	}
+}
+
+func (t *Timeline) observeHost(entry TimelineEntry) {
+	switch entry.Kind {
+	case TimelineHostStart:
+		t.hosts[entry.Host]++
+		if t.hosts[entry.Host] > 1 {
+			return
+		}
+	case TimelineHostFinish:
+		if t.hosts[entry.Host]--; t.hosts[entry.Host] > 0 {
+			return
+		}
+		delete(t.hosts, entry.Host)
+	}
+	t.add(entry)
+}
+
+func (t *Timeline) observeProgress(p ProgressEvent) {
+	now := time.Now()
+	if p.Paused != t.paused {
+		t.paused = p.Paused
+		kind := TimelineResumed
+		if p.Paused {
+			kind = TimelinePaused
+		}
+		t.add(TimelineEntry{Time: now, Kind: kind})
+	}
+	if p.Paused || p.RecentRate <= 0 {
+		return
+	}
+	if t.rate > 0 && math.Abs(p.RecentRate-t.rate) < t.rate*timelineRateShift {
+		return
+	}
+	t.rate = p.RecentRate
+	t.add(TimelineEntry{Time: now, Kind: TimelineRateChange, Rate: p.RecentRate})
+}
+
+func (t *Timeline) observeError(err error) {
+	entry := TimelineEntry{Time: time.Now(), Kind: TimelineError, Detail: err.Error()}
+	var scanErr *ScanError
+	if errors.As(err, &scanErr) {
+		entry.Host, entry.Port, entry.Detail = scanErr.Host, scanErr.Port, scanErr.Err.Error()
+		if !scanErr.Time.IsZero() {
+			entry.Time = scanErr.Time
+		}
+	}
+	t.add(entry)
+}
+
+// Finish appends the scan_finish entry, noting any entries dropped past
+// TimelineEntryLimit, and returns every entry in order. The Timeline is
+// left as it was, so Finish may be called again.
+func (t *Timeline) Finish() []TimelineEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	finish := TimelineEntry{Time: time.Now(), Kind: TimelineScanFinish}
+	if t.dropped > 0 {
+		finish.Detail = fmt.Sprintf("%d entries dropped", t.dropped)
+	}
+	return append(slices.Clone(t.entries), finish)
+}
+
+func (t *Timeline) add(entry TimelineEntry) {
+	if len(t.entries) >= TimelineEntryLimit {
+		t.dropped++
+		return
+	}
+	t.entries = append(t.entries, entry)
+}
+
+// emitHostEvent sends a host_start or host_finish entry for host.
+func (s *Scanner) emitHostEvent(ctx context.Context, kind, host string) {
+	select {
+	case s.results <- NewTimelineEvent(TimelineEntry{Time: time.Now(), Kind: kind, Host: host}):
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTimelineObserve(t *testing.T) {
+	timeline := NewTimeline()
+	hostEvent := func(kind, host string) Event {
+		return NewTimelineEvent(TimelineEntry{Kind: kind, Host: host})
+	}
+	// TCP and UDP streams each start and finish 10.0.0.1.
+	timeline.Observe(hostEvent(TimelineHostStart, "10.0.0.1"))
+	timeline.Observe(hostEvent(TimelineHostStart, "10.0.0.1"))
+	timeline.Observe(NewProgressEvent(ProgressEvent{RecentRate: 100}))
+	timeline.Observe(NewProgressEvent(ProgressEvent{RecentRate: 110})) // within 25%
+	timeline.Observe(NewProgressEvent(ProgressEvent{RecentRate: 50}))
+	timeline.Observe(NewProgressEvent(ProgressEvent{RecentRate: 50, Paused: true}))
+	timeline.Observe(NewProgressEvent(ProgressEvent{RecentRate: 50}))
+	timeline.Observe(NewErrorEvent(&ScanError{Host: "10.0.0.1", Port: 22, Protocol: "tcp", Err: errors.New("no route")}))
+	timeline.Observe(NewResultEvent(ResultEvent{Host: "10.0.0.1", Port: 80}))
+	timeline.Observe(hostEvent(TimelineHostFinish, "10.0.0.1"))
+	timeline.Observe(hostEvent(TimelineHostFinish, "10.0.0.1"))
+
+	want := []string{
+		TimelineScanStart, TimelineHostStart, TimelineRateChange, TimelineRateChange,
+		TimelinePaused, TimelineResumed, TimelineError, TimelineHostFinish, TimelineScanFinish,
+	}
+	entries := timeline.Finish()
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries %+v; want %v", len(entries), entries, want)
+	}
+	for i, kind := range want {
+		if entries[i].Kind != kind {
+			t.Errorf("entry %d = %s; want %s", i, entries[i].Kind, kind)
+		}
+	}
+	if entries[3].Rate != 50 {
+		t.Errorf("rate change = %v; want 50", entries[3].Rate)
+	}
+	if e := entries[6]; e.Host != "10.0.0.1" || e.Port != 22 || e.Detail != "no route" {
+		t.Errorf("error entry = %+v", e)
+	}
+}
+
+func TestTimelineEntryLimit(t *testing.T) {
+	timeline := NewTimeline()
+	for i := 0; i < TimelineEntryLimit+5; i++ {
+		timeline.Observe(NewErrorEvent(errors.New("boom")))
+	}
+	entries := timeline.Finish()
+	if len(entries) != TimelineEntryLimit+1 {
+		t.Fatalf("got %d entries; want the limit plus scan_finish", len(entries))
+	}
+	if last := entries[len(entries)-1]; last.Kind != TimelineScanFinish || last.Detail != "6 entries dropped" {
+		t.Errorf("last entry = %+v", last)
+	}
+}
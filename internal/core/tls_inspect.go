@@ -0,0 +1,189 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultTLSInspectTimeout bounds each TLS inspection, STARTTLS exchange
+// included.
+const DefaultTLSInspectTimeout = 5 * time.Second
+
+// tlsPorts speak TLS from the first byte.
+var tlsPorts = map[uint16]bool{
+	443:  true, // https
+	465:  true, // smtps
+	636:  true, // ldaps
+	853:  true, // dns over tls
+	990:  true, // ftps
+	993:  true, // imaps
+	995:  true, // pop3s
+	3269: true, // ldaps global catalog
+	5061: true, // sips
+	5986: true, // winrm over https
+	6697: true, // ircs
+	8443: true, // https-alt
+}
+
+// TLSInspectOptions configures TLS inspection.
+type TLSInspectOptions struct {
+	Timeout time.Duration // per-port timeout; defaults to DefaultTLSInspectTimeout
+	Workers int           // concurrent inspections; defaults to 16
+	// Resolver gives the address a hostname result's handshake dials; the
+	// hostname still goes in the SNI. Nil dials by name.
+	Resolver HostResolver
+}
+
+// InspectTLS completes a TLS handshake with every open TCP port in events
+// that serves TLS, either from the start or after a STARTTLS upgrade of
+// SMTP, IMAP, POP3, FTP or LDAP, and records the negotiated parameters and
+// the server's certificate in TLS. Other events pass through unchanged.
+// The returned channel is closed once events is closed and every
+// inspection has finished.
+func InspectTLS(ctx context.Context, events <-chan Event, opts TLSInspectOptions) <-chan Event {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTLSInspectTimeout
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 16
+	}
+
+	out := make(chan Event, cap(events))
+	jobs := make(chan ResultEvent)
+	var wg sync.WaitGroup
+
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for result := range jobs {
+				result.TLS = inspectTLS(ctx, opts, result, startTLSPorts[result.Port])
+				select {
+				case out <- NewResultEvent(result):
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer func() {
+			close(jobs)
+			wg.Wait()
+			close(out)
+		}()
+		for event := range events {
+			if event.Kind == EventKindResult && needsTLSInspection(*event.Result) {
+				select {
+				case jobs <- *event.Result:
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+func needsTLSInspection(r ResultEvent) bool {
+	if r.State != StateOpen || (r.Protocol != "" && r.Protocol != "tcp") {
+		return false
+	}
+	_, upgrade := startTLSPorts[r.Port]
+	return tlsPorts[r.Port] || upgrade
+}
+
+// inspectTLS connects to the result's port, upgrading with STARTTLS when
+// upgrade names a protocol, and describes the handshake. It returns nil
+// when no handshake completes.
+func inspectTLS(ctx context.Context, opts TLSInspectOptions, r ResultEvent, upgrade startTLS) *TLSInfo {
+	address, err := dialAddress(ctx, opts.Resolver, r.Host, r.Port)
+	if err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if upgrade.negotiate != nil {
+		if err := upgrade.negotiate(conn); err != nil {
+			return nil
+		}
+	}
+
+	// Ask for the name the target was given as, so SNI selects the site's
+	// certificate rather than the server's default.
+	name := r.Hostname
+	if name == "" {
+		name = r.Host
+	}
+	config := &tls.Config{
+		// The certificate is inspected, not trusted, and legacy versions
+		// are accepted so old servers can be described too.
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+	}
+	if net.ParseIP(name) == nil {
+		config.ServerName = name
+	}
+	recorder := &helloRecorder{Conn: conn}
+	tlsConn := tls.Client(recorder, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return nil
+	}
+	info := describeTLS(tlsConn.ConnectionState())
+	info.StartTLS = upgrade.name
+	info.JA3S = ja3s(recorder.data)
+	return info
+}
+
+// describeTLS summarizes a completed handshake.
+func describeTLS(state tls.ConnectionState) *TLSInfo {
+	info := &TLSInfo{
+		Version: tls.VersionName(state.Version),
+		Cipher:  tls.CipherSuiteName(state.CipherSuite),
+	}
+	if len(state.PeerCertificates) > 0 {
+		leaf := state.PeerCertificates[0]
+		info.Subject = leaf.Subject.CommonName
+		info.Issuer = certificateIssuer(leaf)
+		info.SANs = append(info.SANs, leaf.DNSNames...)
+		for _, ip := range leaf.IPAddresses {
+			info.SANs = append(info.SANs, ip.String())
+		}
+		info.NotAfter = leaf.NotAfter
+	}
+	return info
+}
+
+// certificateIssuer names a certificate's issuer by common name, falling
+// back to its organization.
+func certificateIssuer(cert *x509.Certificate) string {
+	if cert.Issuer.CommonName != "" {
+		return cert.Issuer.CommonName
+	}
+	if len(cert.Issuer.Organization) > 0 {
+		return cert.Issuer.Organization[0]
+	}
+	return ""
+}
@@ -0,0 +1,155 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTP accepts one connection, answers EHLO and STARTTLS, then
+// handshakes with config.
+func fakeSMTP(t *testing.T, config *tls.Config) ResultEvent {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		_, _ = conn.Write([]byte("220 mail.example.com ESMTP\r\n"))
+		if line, _ := r.ReadString('\n'); !strings.HasPrefix(line, "EHLO") {
+			return
+		}
+		_, _ = conn.Write([]byte("250-mail.example.com\r\n250-PIPELINING\r\n250 STARTTLS\r\n"))
+		if line, _ := r.ReadString('\n'); line != "STARTTLS\r\n" {
+			return
+		}
+		_, _ = conn.Write([]byte("220 2.0.0 Ready to start TLS\r\n"))
+		_ = tls.Server(conn, config).Handshake()
+	}()
+	return ResultEvent{Host: "127.0.0.1", Port: uint16(ln.Addr().(*net.TCPAddr).Port), State: StateOpen}
+}
+
+func TestInspectTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+	opts := TLSInspectOptions{Timeout: 2 * time.Second}
+
+	info := inspectTLS(context.Background(), opts, serverResult(t, server.URL), startTLS{})
+	if info == nil {
+		t.Fatal("inspectTLS(implicit) = nil, want a handshake")
+	}
+	if info.Version != "TLS 1.3" || info.Cipher == "" || info.StartTLS != "" {
+		t.Errorf("implicit TLS = %+v, want TLS 1.3 without STARTTLS", info)
+	}
+	if !slices.Contains(info.SANs, "example.com") || !slices.Contains(info.SANs, "127.0.0.1") {
+		t.Errorf("SANs = %v, want the test certificate's names", info.SANs)
+	}
+	if info.Issuer != "Acme Co" || info.NotAfter.IsZero() {
+		t.Errorf("issuer %q, expiry %v; want the test certificate's", info.Issuer, info.NotAfter)
+	}
+	if len(info.JA3S) != 32 {
+		t.Errorf("JA3S = %q, want an MD5 hex digest", info.JA3S)
+	}
+
+	smtp := fakeSMTP(t, server.TLS)
+	info = inspectTLS(context.Background(), opts, smtp, startTLSPorts[25])
+	if info == nil || info.StartTLS != "smtp" || !slices.Contains(info.SANs, "example.com") {
+		t.Errorf("inspectTLS(smtp) = %+v, want the certificate after STARTTLS", info)
+	}
+}
+
+func TestInspectTLSRefusedUpgrade(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = conn.Write([]byte("* OK IMAP ready\r\n"))
+		_, _ = bufio.NewReader(conn).ReadString('\n')
+		_, _ = conn.Write([]byte("a1 BAD STARTTLS not supported\r\n"))
+	}()
+
+	r := ResultEvent{Host: "127.0.0.1", Port: uint16(ln.Addr().(*net.TCPAddr).Port), State: StateOpen}
+	if info := inspectTLS(context.Background(), TLSInspectOptions{Timeout: time.Second}, r, startTLSPorts[143]); info != nil {
+		t.Errorf("inspectTLS() = %+v, want nil when STARTTLS is refused", info)
+	}
+}
+
+func TestExpectReply(t *testing.T) {
+	tests := []struct {
+		reply string
+		code  string
+		ok    bool
+	}{
+		{"220 ready\r\n", "220", true},
+		{"220-first\r\n continued\r\n220 last\r\n", "220", true},
+		{"554 no service\r\n", "220", false},
+		{"250-first\r\n", "250", false}, // unterminated
+	}
+	for _, tt := range tests {
+		err := expectReply(bufio.NewReader(strings.NewReader(tt.reply)), tt.code)
+		if (err == nil) != tt.ok {
+			t.Errorf("expectReply(%q, %s) error = %v, want ok %v", tt.reply, tt.code, err, tt.ok)
+		}
+	}
+}
+
+func TestLDAPStartTLS(t *testing.T) {
+	request := buildLDAPStartTLS()
+	tag, msg, rest, ok := readBER(request)
+	if !ok || tag != berSequence || len(rest) != 0 || !strings.Contains(string(msg), ldapStartTLSOID) {
+		t.Fatalf("request %x is not a StartTLS ExtendedRequest", request)
+	}
+
+	success := []byte{0x30, 0x0c, 0x02, 0x01, 0x01, 0x78, 0x07, 0x0a, 0x01, 0x00, 0x04, 0x00, 0x04, 0x00}
+	if code, ok := parseLDAPExtendedResult(success); !ok || code != 0 {
+		t.Errorf("parse(success) = %d, %v; want 0, true", code, ok)
+	}
+	unavailable := slices.Clone(success)
+	unavailable[9] = 0x34
+	if code, ok := parseLDAPExtendedResult(unavailable); !ok || code != 52 {
+		t.Errorf("parse(unavailable) = %d, %v; want 52, true", code, ok)
+	}
+	if _, ok := parseLDAPExtendedResult([]byte("220 not ldap\r\n")); ok {
+		t.Error("non-LDAP reply parsed")
+	}
+}
+
+func TestNeedsTLSInspection(t *testing.T) {
+	tests := []struct {
+		r    ResultEvent
+		want bool
+	}{
+		{ResultEvent{Port: 443, State: StateOpen}, true},
+		{ResultEvent{Port: 587, State: StateOpen, Protocol: "tcp"}, true},
+		{ResultEvent{Port: 443, State: StateOpen, Protocol: "udp"}, false},
+		{ResultEvent{Port: 25, State: StateClosed}, false},
+		{ResultEvent{Port: 22, State: StateOpen}, false},
+	}
+	for _, tt := range tests {
+		if got := needsTLSInspection(tt.r); got != tt.want {
+			t.Errorf("needsTLSInspection(%d/%s %s) = %v, want %v", tt.r.Port, tt.r.Protocol, tt.r.State, got, tt.want)
+		}
+	}
+}
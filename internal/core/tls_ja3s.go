@@ -0,0 +1,106 @@
+package core
+
+import (
+	"crypto/md5" // #nosec G501 - JA3S is defined as an MD5 digest
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// maxHelloBytes bounds how much of the server's handshake is kept for
+// fingerprinting; the ServerHello comes first and is far smaller.
+const maxHelloBytes = 16 << 10
+
+// TLS record and handshake types read for JA3S.
+const (
+	tlsRecordHandshake = 22
+	tlsServerHello     = 2
+)
+
+// helloRecorder keeps the first bytes read from the server so the
+// ServerHello, which crypto/tls does not expose, can be fingerprinted.
+type helloRecorder struct {
+	net.Conn
+	data []byte
+}
+
+func (c *helloRecorder) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if room := maxHelloBytes - len(c.data); room > 0 {
+		c.data = append(c.data, p[:min(n, room)]...)
+	}
+	return n, err
+}
+
+// ja3s returns the JA3S fingerprint of the ServerHello at the start of
+// data: the MD5 of "version,cipher,extensions" with the extension types
+// joined by dashes in the order the server sent them. It returns "" when
+// data holds no ServerHello.
+func ja3s(data []byte) string {
+	version, cipher, extensions, ok := parseServerHello(data)
+	if !ok {
+		return ""
+	}
+	types := make([]string, len(extensions))
+	for i, ext := range extensions {
+		types[i] = strconv.Itoa(int(ext))
+	}
+	fields := strconv.Itoa(int(version)) + "," + strconv.Itoa(int(cipher)) + "," + strings.Join(types, "-")
+	sum := md5.Sum([]byte(fields)) // #nosec G401 - fingerprint, not a security use
+	return hex.EncodeToString(sum[:])
+}
+
+// parseServerHello finds the ServerHello in the handshake records at the
+// start of data and returns its legacy version, cipher suite and extension
+// types.
+func parseServerHello(data []byte) (version, cipher uint16, extensions []uint16, ok bool) {
+	// Handshake messages may span records; join the fragments first.
+	var handshake []byte
+	for len(data) >= 5 && data[0] == tlsRecordHandshake {
+		length := int(binary.BigEndian.Uint16(data[3:5]))
+		if len(data) < 5+length {
+			break
+		}
+		handshake = append(handshake, data[5:5+length]...)
+		data = data[5+length:]
+	}
+	if len(handshake) < 4 || handshake[0] != tlsServerHello {
+		return 0, 0, nil, false
+	}
+	length := int(handshake[1])<<16 | int(handshake[2])<<8 | int(handshake[3])
+	if len(handshake) < 4+length {
+		return 0, 0, nil, false
+	}
+	hello := handshake[4 : 4+length]
+
+	// legacy_version(2) random(32) session_id(1+n) cipher(2) compression(1)
+	if len(hello) < 35 {
+		return 0, 0, nil, false
+	}
+	version = binary.BigEndian.Uint16(hello[0:2])
+	off := 35 + int(hello[34])
+	if len(hello) < off+3 {
+		return 0, 0, nil, false
+	}
+	cipher = binary.BigEndian.Uint16(hello[off : off+2])
+	off += 3
+	if len(hello) < off+2 {
+		// A ServerHello may omit extensions entirely.
+		return version, cipher, nil, true
+	}
+	rest := hello[off+2:]
+	if extLen := int(binary.BigEndian.Uint16(hello[off : off+2])); extLen < len(rest) {
+		rest = rest[:extLen]
+	}
+	for len(rest) >= 4 {
+		extensions = append(extensions, binary.BigEndian.Uint16(rest[0:2]))
+		size := int(binary.BigEndian.Uint16(rest[2:4]))
+		if len(rest) < 4+size {
+			return 0, 0, nil, false
+		}
+		rest = rest[4+size:]
+	}
+	return version, cipher, extensions, true
+}
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// serverHelloRecords wraps a TLS 1.3 ServerHello choosing
+// TLS_AES_128_GCM_SHA256 with supported_versions and key_share extensions
+// in handshake records of at most split bytes each.
+func serverHelloRecords(split int) []byte {
+	hello := []byte{0x03, 0x03}
+	hello = append(hello, make([]byte, 32)...) // random
+	hello = append(hello, 0)                   // empty session id
+	hello = append(hello, 0x13, 0x01, 0)       // cipher, compression
+	extensions := []byte{0x00, 0x2b, 0x00, 0x02, 0x03, 0x04, 0x00, 0x33, 0x00, 0x04, 0x00, 0x1d, 0x00, 0x00}
+	hello = binary.BigEndian.AppendUint16(hello, uint16(len(extensions)))
+	hello = append(hello, extensions...)
+
+	handshake := []byte{tlsServerHello, 0, byte(len(hello) >> 8), byte(len(hello))}
+	handshake = append(handshake, hello...)
+
+	var records []byte
+	for len(handshake) > 0 {
+		n := min(split, len(handshake))
+		records = append(records, tlsRecordHandshake, 0x03, 0x03, byte(n>>8), byte(n))
+		records = append(records, handshake[:n]...)
+		handshake = handshake[n:]
+	}
+	return records
+}
+
+func TestJA3S(t *testing.T) {
+	// MD5 of "771,4865,43-51".
+	const want = "f4febc55ea12b31ae17cfb7e614afda8"
+	if got := ja3s(serverHelloRecords(1 << 14)); got != want {
+		t.Errorf("ja3s() = %q, want %q", got, want)
+	}
+	if got := ja3s(serverHelloRecords(20)); got != want {
+		t.Errorf("ja3s(fragmented) = %q, want %q", got, want)
+	}
+
+	truncated := serverHelloRecords(1 << 14)
+	if got := ja3s(truncated[:40]); got != "" {
+		t.Errorf("ja3s(truncated) = %q, want empty", got)
+	}
+	alert := []byte{21, 0x03, 0x03, 0x00, 0x02, 0x02, 0x28}
+	if got := ja3s(alert); got != "" {
+		t.Errorf("ja3s(alert) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"strings"
+)
+
+// startTLS upgrades a plaintext protocol to TLS.
+type startTLS struct {
+	name string
+	// negotiate runs the plaintext exchange up to the point where the
+	// server expects a ClientHello.
+	negotiate func(net.Conn) error
+}
+
+// startTLSPorts maps ports to the upgrade their protocol uses.
+var startTLSPorts = map[uint16]startTLS{
+	21:  {"ftp", startFTP},
+	25:  {"smtp", startSMTP},
+	110: {"pop3", startPOP3},
+	143: {"imap", startIMAP},
+	389: {"ldap", startLDAP},
+	587: {"smtp", startSMTP},
+}
+
+// errStartTLSRefused reports a server that declined the upgrade.
+var errStartTLSRefused = errors.New("starttls refused")
+
+// maxStartTLSLines bounds how many lines of a plaintext reply are read.
+const maxStartTLSLines = 64
+
+// startSMTP greets the server with EHLO and issues STARTTLS (RFC 3207).
+func startSMTP(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	if err := expectReply(r, "220"); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(conn, "EHLO portscan\r\n"); err != nil {
+		return err
+	}
+	if err := expectReply(r, "250"); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(conn, "STARTTLS\r\n"); err != nil {
+		return err
+	}
+	return expectReply(r, "220")
+}
+
+// startFTP issues AUTH TLS (RFC 4217).
+func startFTP(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	if err := expectReply(r, "220"); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(conn, "AUTH TLS\r\n"); err != nil {
+		return err
+	}
+	return expectReply(r, "234")
+}
+
+// startPOP3 issues STLS (RFC 2595).
+func startPOP3(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	if err := expectPOP3OK(r); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(conn, "STLS\r\n"); err != nil {
+		return err
+	}
+	return expectPOP3OK(r)
+}
+
+func expectPOP3OK(r *bufio.Reader) error {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "+OK") {
+		return errStartTLSRefused
+	}
+	return nil
+}
+
+// startIMAP issues a tagged STARTTLS (RFC 3501), skipping untagged
+// responses.
+func startIMAP(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	greeting, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(greeting, "* OK") {
+		return errStartTLSRefused
+	}
+	if _, err := io.WriteString(conn, "a1 STARTTLS\r\n"); err != nil {
+		return err
+	}
+	for i := 0; i < maxStartTLSLines; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(line, "a1 ") {
+			if strings.HasPrefix(line, "a1 OK") {
+				return nil
+			}
+			return errStartTLSRefused
+		}
+	}
+	return errStartTLSRefused
+}
+
+// ldapStartTLSOID names the StartTLS extended operation (RFC 4511).
+const ldapStartTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// BER tags of the LDAP messages used for StartTLS.
+const (
+	ldapExtendedRequest  = 0x77
+	ldapExtendedResponse = 0x78
+	ldapRequestName      = 0x80
+	berEnumerated        = 0x0a
+)
+
+// startLDAP sends a StartTLS extended request and checks its result code.
+func startLDAP(conn net.Conn) error {
+	if _, err := conn.Write(buildLDAPStartTLS()); err != nil {
+		return err
+	}
+	buf := make([]byte, 512)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return err
+	}
+	code, ok := parseLDAPExtendedResult(buf[:n])
+	if !ok {
+		return errors.New("ldap: unexpected reply")
+	}
+	if code != 0 {
+		return errStartTLSRefused
+	}
+	return nil
+}
+
+// buildLDAPStartTLS encodes message 1: a StartTLS ExtendedRequest.
+func buildLDAPStartTLS() []byte {
+	request := berTLV(ldapExtendedRequest, berTLV(ldapRequestName, []byte(ldapStartTLSOID)))
+	return berTLV(berSequence, append(berInt(1), request...))
+}
+
+// parseLDAPExtendedResult returns the result code of an ExtendedResponse.
+func parseLDAPExtendedResult(data []byte) (int, bool) {
+	tag, msg, _, ok := readBER(data)
+	if !ok || tag != berSequence {
+		return 0, false
+	}
+	if tag, _, msg, ok = readBER(msg); !ok || tag != berInteger { // message ID
+		return 0, false
+	}
+	tag, response, _, ok := readBER(msg)
+	if !ok || tag != ldapExtendedResponse {
+		return 0, false
+	}
+	tag, code, _, ok := readBER(response)
+	if !ok || tag != berEnumerated {
+		return 0, false
+	}
+	return berIntValue(code), true
+}
+
+// expectReply reads an SMTP or FTP reply and checks it carries code. A
+// multi-line reply opens with "250-" and runs to the line starting "250 ".
+func expectReply(r *bufio.Reader, code string) error {
+	first, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(first, code) {
+		return errStartTLSRefused
+	}
+	if len(first) < 4 || first[3] != '-' {
+		return nil
+	}
+	for i := 0; i < maxStartTLSLines; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(line, code+" ") {
+			return nil
+		}
+	}
+	return errStartTLSRefused
+}
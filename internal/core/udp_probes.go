@@ -1,5 +1,15 @@
 package core
 
+import "regexp"
+
+// UDPProbe is a payload sent to a UDP port in place of the built-in probe.
+// When Match is set, a response it matches is reported as Service.
+type UDPProbe struct {
+	Service string
+	Payload []byte
+	Match   *regexp.Regexp
+}
+
 // ProbeStats tracks the effectiveness of probes for each port.
 type ProbeStats struct {
 	Sent      int // Number of probes sent
@@ -23,11 +33,28 @@ func initUDPProbes() map[uint16][]byte {
 }
 
 func (s *UDPScanner) AddCustomProbe(port uint16, probe []byte) {
+	s.AddProbe(port, UDPProbe{Payload: probe})
+}
+
+// AddProbe sends probe to port in place of any built-in probe.
+func (s *UDPScanner) AddProbe(port uint16, probe UDPProbe) {
 	s.probeMu.Lock()
 	defer s.probeMu.Unlock()
 	s.customProbes[port] = probe
 }
 
+// matchCustomProbe returns the service named by port's custom probe when
+// its pattern matches data.
+func (s *UDPScanner) matchCustomProbe(port uint16, data []byte) (string, bool) {
+	s.probeMu.RLock()
+	probe, exists := s.customProbes[port]
+	s.probeMu.RUnlock()
+	if !exists || probe.Match == nil || !probe.Match.Match(data) {
+		return "", false
+	}
+	return probe.Service, true
+}
+
 func (s *UDPScanner) GetProbeStats() map[uint16]ProbeStats {
 	s.probeMu.RLock()
 	defer s.probeMu.RUnlock()
@@ -54,7 +81,7 @@ func (s *UDPScanner) getProbeForPort(port uint16) []byte {
 	s.probeMu.RLock()
 	defer s.probeMu.RUnlock()
 	if probe, exists := s.customProbes[port]; exists {
-		return probe
+		return probe.Payload
 	}
 
 	if probe, exists := s.serviceProbes[port]; exists {
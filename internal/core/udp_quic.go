@@ -0,0 +1,453 @@
+package core
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	quicVersion1 = 0x00000001
+	// quicMinDatagram is the size below which servers drop a client's
+	// Initial packet (RFC 9000, 14.1).
+	quicMinDatagram = 1200
+	// quicMaxDatagram bounds the replies the probe reads, and is the
+	// max_udp_payload_size it advertises.
+	quicMaxDatagram = 1500
+	// quicALPN is the only protocol the probe offers, so a server that
+	// answers the ClientHello has accepted HTTP/3.
+	quicALPN = "h3"
+	// quicNoALPN is the CONNECTION_CLOSE code for the TLS
+	// no_application_protocol alert (RFC 9001, 4.8).
+	quicNoALPN = 0x100 + 120
+)
+
+// quicInitialSalt derives the keys protecting QUIC version 1 Initial
+// packets from the client's destination connection ID (RFC 9001, 5.2).
+var quicInitialSalt = []byte{
+	0x38, 0x76, 0x2c, 0xf7, 0xf5, 0x59, 0x34, 0xb3, 0x4d, 0x17,
+	0x9a, 0xe6, 0xa4, 0xc8, 0x0c, 0xad, 0xcc, 0xbb, 0x7f, 0x0a,
+}
+
+// quicPorts are probed with a QUIC Initial unless a custom probe is set.
+var quicPorts = map[uint16]bool{443: true, 8443: true}
+
+var quicVersionNames = map[uint32]string{
+	0x00000001: "v1",
+	0x6b3343cf: "v2",
+	0xff00001d: "draft-29",
+}
+
+// quicProbe is a QUIC Initial packet carrying a TLS ClientHello. It keeps
+// the destination connection ID the server's Initial keys derive from,
+// so the reply can be read.
+type quicProbe struct {
+	dcid   []byte
+	packet []byte
+}
+
+// quicKeys protects the packets of one direction at one encryption level.
+type quicKeys struct {
+	aead cipher.AEAD
+	iv   []byte
+	hp   cipher.Block
+}
+
+// quicProbeFor returns a new QUIC probe for host when port is a QUIC port
+// without a custom probe, and nil otherwise.
+func (s *UDPScanner) quicProbeFor(host string, port uint16) *quicProbe {
+	if !quicPorts[port] {
+		return nil
+	}
+	s.probeMu.RLock()
+	_, custom := s.customProbes[port]
+	s.probeMu.RUnlock()
+	if custom {
+		return nil
+	}
+	probe, err := newQUICProbe(host)
+	if err != nil {
+		return nil
+	}
+	return probe
+}
+
+func newQUICProbe(serverName string) (*quicProbe, error) {
+	dcid := make([]byte, 8)
+	scid := make([]byte, 8)
+	if _, err := rand.Read(dcid); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(scid); err != nil {
+		return nil, err
+	}
+
+	hello, err := quicClientHello(serverName, scid)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := quicInitialKeys(dcid, "client in")
+	if err != nil {
+		return nil, err
+	}
+	frame := []byte{0x06, 0x00} // CRYPTO at offset 0
+	frame = appendQUICVarint(frame, uint64(len(hello)))
+	frame = append(frame, hello...)
+	return &quicProbe{dcid: dcid, packet: sealQUICInitial(keys, dcid, scid, frame)}, nil
+}
+
+// quicClientHello has crypto/tls write a QUIC ClientHello offering only
+// HTTP/3. The handshake is never completed, so the certificate is not
+// checked.
+func quicClientHello(serverName string, scid []byte) ([]byte, error) {
+	conn := tls.QUICClient(&tls.QUICConfig{TLSConfig: &tls.Config{
+		ServerName:         serverName,
+		NextProtos:         []string{quicALPN},
+		MinVersion:         tls.VersionTLS13,
+		InsecureSkipVerify: true,
+		// Classical key shares keep the ClientHello inside one datagram.
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
+	}})
+	defer func() { _ = conn.Close() }()
+
+	var params []byte
+	params = appendQUICParam(params, 0x03, appendQUICVarint(nil, quicMaxDatagram)) // max_udp_payload_size
+	params = appendQUICParam(params, 0x0f, scid)                                   // initial_source_connection_id
+	conn.SetTransportParameters(params)
+	if err := conn.Start(context.Background()); err != nil {
+		return nil, err
+	}
+
+	var hello []byte
+	for {
+		event := conn.NextEvent()
+		switch event.Kind {
+		case tls.QUICNoEvent:
+			if len(hello) == 0 {
+				return nil, errors.New("quic: no ClientHello written")
+			}
+			return hello, nil
+		case tls.QUICWriteData:
+			if event.Level == tls.QUICEncryptionLevelInitial {
+				hello = append(hello, event.Data...)
+			}
+		}
+	}
+}
+
+// quicInitialSecrets derives the Initial packet key, IV and header
+// protection key for label, "client in" or "server in".
+func quicInitialSecrets(dcid []byte, label string) (key, iv, hp []byte, err error) {
+	initial, err := hkdf.Extract(sha256.New, dcid, quicInitialSalt)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	secret, err := quicExpandLabel(initial, label, sha256.Size)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if key, err = quicExpandLabel(secret, "quic key", 16); err != nil {
+		return nil, nil, nil, err
+	}
+	if iv, err = quicExpandLabel(secret, "quic iv", 12); err != nil {
+		return nil, nil, nil, err
+	}
+	if hp, err = quicExpandLabel(secret, "quic hp", 16); err != nil {
+		return nil, nil, nil, err
+	}
+	return key, iv, hp, nil
+}
+
+func quicInitialKeys(dcid []byte, label string) (*quicKeys, error) {
+	key, iv, hpKey, err := quicInitialSecrets(dcid, label)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	hp, err := aes.NewCipher(hpKey)
+	if err != nil {
+		return nil, err
+	}
+	return &quicKeys{aead: aead, iv: iv, hp: hp}, nil
+}
+
+// quicExpandLabel is TLS 1.3's HKDF-Expand-Label with an empty context.
+func quicExpandLabel(secret []byte, label string, length int) ([]byte, error) {
+	full := "tls13 " + label
+	info := []byte{byte(length >> 8), byte(length), byte(len(full))}
+	info = append(info, full...)
+	info = append(info, 0)
+	return hkdf.Expand(sha256.New, secret, string(info), length)
+}
+
+func (k *quicKeys) nonce(pn uint64) []byte {
+	nonce := append([]byte(nil), k.iv...)
+	for i := 0; i < 8; i++ {
+		nonce[len(nonce)-1-i] ^= byte(pn >> (8 * i))
+	}
+	return nonce
+}
+
+// headerMask returns the header protection mask for the ciphertext
+// sample that starts four bytes after the packet number.
+func (k *quicKeys) headerMask(sample []byte) []byte {
+	mask := make([]byte, aes.BlockSize)
+	k.hp.Encrypt(mask, sample)
+	return mask
+}
+
+// sealQUICInitial builds a protected Initial packet with packet number 0,
+// padding the payload so the datagram is at least quicMinDatagram bytes.
+func sealQUICInitial(k *quicKeys, dcid, scid, payload []byte) []byte {
+	const pnLen = 4
+	headerLen := 1 + 4 + 1 + len(dcid) + 1 + len(scid) + 1 + 2 + pnLen
+	if n := quicMinDatagram - headerLen - k.aead.Overhead(); len(payload) < n {
+		payload = append(payload, make([]byte, n-len(payload))...) // PADDING frames
+	}
+
+	header := []byte{0xc0 | (pnLen - 1)}
+	header = binary.BigEndian.AppendUint32(header, quicVersion1)
+	header = append(header, byte(len(dcid)))
+	header = append(header, dcid...)
+	header = append(header, byte(len(scid)))
+	header = append(header, scid...)
+	header = append(header, 0) // no token
+	length := pnLen + len(payload) + k.aead.Overhead()
+	header = append(header, 0x40|byte(length>>8), byte(length))
+	pnOffset := len(header)
+	header = append(header, 0, 0, 0, 0)
+
+	packet := k.aead.Seal(append([]byte(nil), header...), k.nonce(0), payload, header)
+	mask := k.headerMask(packet[pnOffset+4 : pnOffset+4+aes.BlockSize])
+	packet[0] ^= mask[0] & 0x0f
+	for i := 0; i < pnLen; i++ {
+		packet[pnOffset+i] ^= mask[1+i]
+	}
+	return packet
+}
+
+// openQUICInitial removes the protection from the Initial packet at the
+// start of data and returns its frames.
+func openQUICInitial(k *quicKeys, data []byte) ([]byte, error) {
+	errShort := errors.New("quic: truncated Initial packet")
+	off := 5
+	for i := 0; i < 2; i++ { // destination and source connection IDs
+		if off >= len(data) {
+			return nil, errShort
+		}
+		off += 1 + int(data[off])
+	}
+	if off > len(data) {
+		return nil, errShort
+	}
+	tokenLen, n, ok := readQUICVarint(data[off:])
+	if !ok {
+		return nil, errShort
+	}
+	off += n + int(tokenLen)
+	if off > len(data) {
+		return nil, errShort
+	}
+	length, n, ok := readQUICVarint(data[off:])
+	if !ok {
+		return nil, errShort
+	}
+	pnOffset := off + n
+	end := pnOffset + int(length)
+	if pnOffset+4+aes.BlockSize > len(data) || end > len(data) {
+		return nil, errShort
+	}
+
+	header := append([]byte(nil), data[:pnOffset+4]...)
+	mask := k.headerMask(data[pnOffset+4 : pnOffset+4+aes.BlockSize])
+	header[0] ^= mask[0] & 0x0f
+	pnLen := int(header[0]&0x03) + 1
+	var pn uint64
+	for i := 0; i < pnLen; i++ {
+		header[pnOffset+i] ^= mask[1+i]
+		pn = pn<<8 | uint64(header[pnOffset+i])
+	}
+	if int(length) < pnLen+k.aead.Overhead() {
+		return nil, errShort
+	}
+	return k.aead.Open(nil, k.nonce(pn), data[pnOffset+pnLen:end], header[:pnOffset+pnLen])
+}
+
+// parseResponse names the service from the server's reply: a Version
+// Negotiation or Retry packet, or an Initial that either carries the
+// ServerHello, so HTTP/3 was accepted, or closes the connection.
+func (p *quicProbe) parseResponse(data []byte) (string, float64) {
+	if len(data) < 7 || data[0]&0x80 == 0 {
+		return "", 0
+	}
+	version := binary.BigEndian.Uint32(data[1:5])
+	if version == 0 {
+		return "quic/http3, versions " + quicVersionList(data), 0.9
+	}
+	if version != quicVersion1 {
+		return "", 0
+	}
+
+	switch (data[0] >> 4) & 0x03 {
+	case 0x00: // Initial
+	case 0x03:
+		return "quic/http3, retry", 0.9
+	default:
+		return "quic/http3", 0.8
+	}
+
+	keys, err := quicInitialKeys(p.dcid, "server in")
+	if err != nil {
+		return "quic/http3", 0.8
+	}
+	frames, err := openQUICInitial(keys, data)
+	if err != nil {
+		return "quic/http3", 0.8
+	}
+	serverHello, closeCode, closed := scanQUICFrames(frames)
+	switch {
+	case serverHello:
+		return "quic/http3, alpn " + quicALPN, 0.95
+	case closed && closeCode == quicNoALPN:
+		return "quic, " + quicALPN + " refused", 0.9
+	case closed:
+		return fmt.Sprintf("quic, closed 0x%x", closeCode), 0.85
+	}
+	return "quic/http3", 0.85
+}
+
+// describeQUIC is parseUDPResponse for a QUIC probe's reply; replies that
+// are not QUIC are described like any unknown UDP response.
+func describeQUIC(p *quicProbe, port uint16, data []byte) string {
+	if service, confidence := p.parseResponse(data); service != "" {
+		return fmt.Sprintf("%s (%.1f%%)", service, confidence*100)
+	}
+	return describeUnknownUDP(port, data)
+}
+
+// quicVersionList lists the versions in a Version Negotiation packet.
+func quicVersionList(data []byte) string {
+	off := 5
+	for i := 0; i < 2; i++ {
+		if off >= len(data) {
+			return ""
+		}
+		off += 1 + int(data[off])
+	}
+	var versions []string
+	for ; off+4 <= len(data); off += 4 {
+		v := binary.BigEndian.Uint32(data[off:])
+		if name, ok := quicVersionNames[v]; ok {
+			versions = append(versions, name)
+		} else if v&0x0f0f0f0f != 0x0a0a0a0a { // skip greased versions
+			versions = append(versions, fmt.Sprintf("0x%08x", v))
+		}
+	}
+	return strings.Join(versions, " ")
+}
+
+// scanQUICFrames walks the frames of an Initial packet, reporting whether
+// one carries the ServerHello and the error code of any CONNECTION_CLOSE.
+func scanQUICFrames(payload []byte) (serverHello bool, closeCode uint64, closed bool) {
+	for len(payload) > 0 {
+		frameType, n, ok := readQUICVarint(payload)
+		if !ok {
+			return serverHello, 0, false
+		}
+		payload = payload[n:]
+		switch frameType {
+		case 0x00, 0x01: // PADDING, PING
+		case 0x02, 0x03: // ACK: largest, delay, range count, first range
+			rest, rangeCount, ok := skipQUICVarints(payload, 3)
+			if !ok {
+				return serverHello, 0, false
+			}
+			fields := 1 + 2*int(rangeCount)
+			if frameType == 0x03 {
+				fields += 3 // ECN counts
+			}
+			if payload, _, ok = skipQUICVarints(rest, fields); !ok {
+				return serverHello, 0, false
+			}
+		case 0x06: // CRYPTO
+			rest, length, ok := skipQUICVarints(payload, 2)
+			if !ok || uint64(len(rest)) < length {
+				return serverHello, 0, false
+			}
+			if length > 0 && rest[0] == 0x02 { // handshake type server_hello
+				serverHello = true
+			}
+			payload = rest[length:]
+		case 0x1c, 0x1d: // CONNECTION_CLOSE
+			code, _, ok := readQUICVarint(payload)
+			return serverHello, code, ok
+		default:
+			return serverHello, 0, false
+		}
+	}
+	return serverHello, 0, false
+}
+
+// skipQUICVarints skips count variable-length integers, returning the
+// rest of b and the last integer read.
+func skipQUICVarints(b []byte, count int) ([]byte, uint64, bool) {
+	var v uint64
+	for i := 0; i < count; i++ {
+		var n int
+		var ok bool
+		if v, n, ok = readQUICVarint(b); !ok {
+			return nil, 0, false
+		}
+		b = b[n:]
+	}
+	return b, v, true
+}
+
+func readQUICVarint(b []byte) (uint64, int, bool) {
+	if len(b) == 0 {
+		return 0, 0, false
+	}
+	n := 1 << (b[0] >> 6)
+	if len(b) < n {
+		return 0, 0, false
+	}
+	v := uint64(b[0] & 0x3f)
+	for i := 1; i < n; i++ {
+		v = v<<8 | uint64(b[i])
+	}
+	return v, n, true
+}
+
+func appendQUICVarint(b []byte, v uint64) []byte {
+	switch {
+	case v < 1<<6:
+		return append(b, byte(v))
+	case v < 1<<14:
+		return append(b, 0x40|byte(v>>8), byte(v))
+	case v < 1<<30:
+		return append(b, 0x80|byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	default:
+		return binary.BigEndian.AppendUint64(b, v|0xc0<<56)
+	}
+}
+
+func appendQUICParam(b []byte, id uint64, value []byte) []byte {
+	b = appendQUICVarint(b, id)
+	b = appendQUICVarint(b, uint64(len(value)))
+	return append(b, value...)
+}
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQUICInitialSecrets(t *testing.T) {
+	// RFC 9001, Appendix A.1.
+	dcid, _ := hex.DecodeString("8394c8f03e515708")
+	tests := []struct {
+		label       string
+		key, iv, hp string
+	}{
+		{"client in", "1f369613dd76d5467730efcbe3b1a22d", "fa044b2f42a3fd3b46fb255c", "9f50449e04a0e810283a1e9933adedd2"},
+		{"server in", "cf3a5331653c364c88f0f379b6067e37", "0ac1493ca1905853b0bba03e", "c206b8d9b9f0f37644430b490eeaa314"},
+	}
+	for _, tt := range tests {
+		key, iv, hp, err := quicInitialSecrets(dcid, tt.label)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.label, err)
+		}
+		if hex.EncodeToString(key) != tt.key || hex.EncodeToString(iv) != tt.iv || hex.EncodeToString(hp) != tt.hp {
+			t.Errorf("%s secrets = %x %x %x; want %s %s %s", tt.label, key, iv, hp, tt.key, tt.iv, tt.hp)
+		}
+	}
+}
+
+func TestQUICProbePacket(t *testing.T) {
+	probe, err := newQUICProbe("example.com")
+	if err != nil {
+		t.Fatalf("newQUICProbe: %v", err)
+	}
+	packet := probe.packet
+	if len(packet) < quicMinDatagram || len(packet) > quicMaxDatagram {
+		t.Errorf("probe is %d bytes; want %d-%d", len(packet), quicMinDatagram, quicMaxDatagram)
+	}
+	if packet[0]&0xf0 != 0xc0 || binary.BigEndian.Uint32(packet[1:5]) != quicVersion1 {
+		t.Errorf("probe header %x is not a version 1 Initial", packet[:5])
+	}
+
+	// The server reads the probe with the client Initial keys.
+	keys, err := quicInitialKeys(probe.dcid, "client in")
+	if err != nil {
+		t.Fatal(err)
+	}
+	frames, err := openQUICInitial(keys, packet)
+	if err != nil {
+		t.Fatalf("server could not open the probe: %v", err)
+	}
+	if frames[0] != 0x06 {
+		t.Fatalf("first frame type = %#x; want CRYPTO", frames[0])
+	}
+	hello, length, ok := skipQUICVarints(frames[1:], 2) // offset and length
+	if !ok || uint64(len(hello)) < length {
+		t.Fatal("truncated CRYPTO frame")
+	}
+	if hello[0] != 0x01 {
+		t.Errorf("CRYPTO frame starts with handshake type %#x; want client_hello", hello[0])
+	}
+	if !bytes.Contains(hello, []byte("\x02h3")) || !bytes.Contains(hello, []byte("example.com")) {
+		t.Error("ClientHello should offer ALPN h3 and carry the server name")
+	}
+}
+
+// quicServerInitial builds the server's reply to probe: an Initial whose
+// frames are protected with the server Initial keys.
+func quicServerInitial(t *testing.T, probe *quicProbe, frames []byte) []byte {
+	t.Helper()
+	keys, err := quicInitialKeys(probe.dcid, "server in")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sealQUICInitial(keys, []byte("portscan"), []byte("srv-cid1"), frames)
+}
+
+func TestQUICParseResponse(t *testing.T) {
+	probe, err := newQUICProbe("192.0.2.1")
+	if err != nil {
+		t.Fatalf("newQUICProbe: %v", err)
+	}
+
+	ack := []byte{0x02, 0x00, 0x00, 0x00, 0x00}
+	serverHello := append(append([]byte(nil), ack...), 0x06, 0x00, 0x04, 0x02, 0x00, 0x00, 0x00)
+	closeNoALPN := []byte{0x1c, 0x41, 0x78, 0x06, 0x00}
+	closeOther := []byte{0x1c, 0x41, 0x28, 0x06, 0x00}
+
+	versionNegotiation := []byte{0x80, 0, 0, 0, 0, 8}
+	versionNegotiation = append(versionNegotiation, "portscan"...)
+	versionNegotiation = append(versionNegotiation, 0)
+	versionNegotiation = binary.BigEndian.AppendUint32(versionNegotiation, 0x6b3343cf)
+	versionNegotiation = binary.BigEndian.AppendUint32(versionNegotiation, 0x1a2a3a4a) // greased
+	versionNegotiation = binary.BigEndian.AppendUint32(versionNegotiation, 0xff00001d)
+
+	retry := []byte{0xf0, 0, 0, 0, 1, 8}
+	retry = append(retry, "portscan"...)
+	retry = append(retry, 8)
+	retry = append(retry, "srv-cid1token"...)
+
+	tests := []struct {
+		name  string
+		reply []byte
+		want  string
+	}{
+		{"server hello", quicServerInitial(t, probe, serverHello), "quic/http3, alpn h3 (95.0%)"},
+		{"alpn refused", quicServerInitial(t, probe, closeNoALPN), "quic, h3 refused (90.0%)"},
+		{"closed", quicServerInitial(t, probe, closeOther), "quic, closed 0x128 (85.0%)"},
+		{"version negotiation", versionNegotiation, "quic/http3, versions v2 draft-29 (90.0%)"},
+		{"retry", retry, "quic/http3, retry (90.0%)"},
+		{"undecryptable initial", quicServerInitial(t, &quicProbe{dcid: []byte("otherdci")}, serverHello), "quic/http3 (80.0%)"},
+		{"initial shorter than its packet number", append([]byte{0xc0, 0, 0, 0, 1, 0, 0, 0, 0}, make([]byte, 20)...), "quic/http3 (80.0%)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeQUIC(probe, 443, tt.reply); got != tt.want {
+				t.Errorf("describeQUIC() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := describeQUIC(probe, 443, []byte("hello")); strings.Contains(got, "quic") {
+		t.Errorf("non-QUIC reply described as %q", got)
+	}
+}
+
+func FuzzQUICParseResponse(f *testing.F) {
+	probe, err := newQUICProbe("192.0.2.1")
+	if err != nil {
+		f.Fatalf("newQUICProbe: %v", err)
+	}
+	keys, err := quicInitialKeys(probe.dcid, "server in")
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(sealQUICInitial(keys, []byte("portscan"), []byte("srv-cid1"), []byte{0x02, 0x00, 0x00, 0x00, 0x00}))
+	f.Add(sealQUICInitial(keys, []byte("portscan"), []byte("srv-cid1"), []byte{0x1c, 0x41, 0x78, 0x06, 0x00}))
+	f.Add(append([]byte{0xc0, 0, 0, 0, 1, 0, 0, 0, 0}, make([]byte, 20)...))
+	f.Add([]byte{0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1})
+	f.Add([]byte{0xf0, 0, 0, 0, 1, 0, 0})
+	f.Fuzz(func(t *testing.T, reply []byte) {
+		// Any reply may be malformed; none may panic the scanner. The
+		// frame walker is fed directly too, as few inputs decrypt.
+		probe.parseResponse(reply)
+		scanQUICFrames(reply)
+	})
+}
+
+func TestQUICProbeForPort(t *testing.T) {
+	scanner := NewUDPScanner(&Config{Workers: 1, Timeout: 100 * time.Millisecond})
+	if scanner.quicProbeFor("example.com", 443) == nil {
+		t.Error("port 443 should get a QUIC probe")
+	}
+	if scanner.quicProbeFor("example.com", 53) != nil {
+		t.Error("port 53 should not get a QUIC probe")
+	}
+	scanner.AddCustomProbe(443, []byte{0x01})
+	if scanner.quicProbeFor("example.com", 443) != nil {
+		t.Error("a custom probe should replace the QUIC probe")
+	}
+}
@@ -9,6 +9,10 @@ import (
 )
 
 func (s *UDPScanner) parseUDPResponse(port uint16, data []byte) string {
+	if service, ok := s.matchCustomProbe(port, data); ok {
+		return fmt.Sprintf("%s (95.0%%)", service)
+	}
+
 	var service string
 	var confidence float64
 
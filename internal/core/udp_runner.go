@@ -6,14 +6,15 @@ import "context"
 func (s *UDPScanner) ScanTargets(ctx context.Context, targets []ScanTarget) {
 	totalPorts := totalPortCount(targets)
 	if totalPorts == 0 {
-		if s.rateTicker != nil {
-			s.rateTicker.Stop()
-		}
+		s.stopRate()
 		close(s.results)
 		return
 	}
 
 	s.progressReporter.SetCompleted(0)
+	s.progressReporter.TrackTargets(targets)
+	s.hostLimit = newHostLimiter(targets, s.config.MaxHostParallelism)
+	s.hostnames = targetHostnames(targets)
 
 	jobs := make(chan scanJob, s.jobBufferSize(totalPorts))
 	progressDone := s.progressReporter.StartReporting(ctx, totalPorts)
@@ -6,7 +6,6 @@ import (
 	"math/rand"
 	"net"
 	"os"
-	"strconv"
 	"sync"
 	"syscall"
 	"time"
@@ -16,19 +15,23 @@ import (
 type UDPScanner struct {
 	*Scanner
 	serviceProbes map[uint16][]byte
-	customProbes  map[uint16][]byte
+	customProbes  map[uint16]UDPProbe
 	probeStats    map[uint16]ProbeStats
 	probeMu       sync.RWMutex
 }
 
 // NewUDPScanner creates a new UDP scanner instance.
 func NewUDPScanner(cfg *Config) *UDPScanner {
-	return &UDPScanner{
+	s := &UDPScanner{
 		Scanner:       NewScanner(cfg),
 		serviceProbes: initUDPProbes(),
-		customProbes:  make(map[uint16][]byte),
+		customProbes:  make(map[uint16]UDPProbe, len(cfg.UDPProbes)),
 		probeStats:    make(map[uint16]ProbeStats),
 	}
+	for port, probe := range cfg.UDPProbes {
+		s.customProbes[port] = probe
+	}
+	return s
 }
 
 // ScanRange implements the Scanner interface for UDP scanning.
@@ -50,41 +53,58 @@ func (s *UDPScanner) udpWorker(ctx context.Context, jobs <-chan scanJob) {
 				return
 			}
 
-			if s.rateTicker != nil {
-				select {
-				case <-ctx.Done():
+			if !s.pause.Wait(ctx) {
+				return
+			}
+
+			if s.rateTicker != nil || s.budgetTicker != nil {
+				if !s.waitForRate(ctx) {
 					return
-				case <-s.rateTicker.C:
-					if s.config.UDPJitterMaxMs > 0 {
-						jitter := time.Duration(rng.Intn(s.config.UDPJitterMaxMs)) * time.Millisecond
-						if jitter > 0 {
-							timer := time.NewTimer(jitter)
-							select {
-							case <-ctx.Done():
-								timer.Stop()
-								return
-							case <-timer.C:
-							}
-						}
+				}
+				if s.config.UDPJitterMaxMs > 0 {
+					jitter := time.Duration(rng.Intn(s.config.UDPJitterMaxMs)) * time.Millisecond
+					if !sleepContext(ctx, jitter) {
+						return
 					}
 				}
 			}
 
+			if !s.pause.Wait(ctx) {
+				return
+			}
+
+			if !s.hostLimit.acquire(ctx, job.host) {
+				return
+			}
+			if s.progressReporter.StartJob(job.host) {
+				s.emitHostEvent(ctx, TimelineHostStart, job.host)
+			}
 			s.scanUDPPort(ctx, job.host, job.port)
+			s.hostLimit.release(job.host)
 		}
 	}
 }
 
 func (s *UDPScanner) scanUDPPort(ctx context.Context, host string, port uint16) {
 	start := time.Now()
-	address := net.JoinHostPort(host, strconv.Itoa(int(port)))
+	address, err := dialAddress(ctx, s.config.Resolver, host, port)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.emitError(ctx, scanJob{host: host, port: port}, "udp", err)
+		}
+		return
+	}
 
-	dialer := &net.Dialer{Timeout: s.config.Timeout}
+	dialer := &net.Dialer{Timeout: s.readTimeoutFor(port, s.config.Timeout)}
 	conn, err := dialer.DialContext(ctx, "udp", address)
 	if err != nil {
 		if ctx.Err() != nil {
 			return
 		}
+		if isProbeError(err) {
+			s.emitError(ctx, scanJob{host: host, port: port}, "udp", err)
+			return
+		}
 
 		s.recordProbeAttempt(port, false)
 
@@ -100,10 +120,20 @@ func (s *UDPScanner) scanUDPPort(ctx context.Context, host string, port uint16)
 	}
 	defer func() { _ = conn.Close() }()
 
-	_ = conn.SetReadDeadline(time.Now().Add(s.config.UDPReadTimeout))
+	_ = conn.SetReadDeadline(time.Now().Add(s.readTimeoutFor(port, s.config.UDPReadTimeout)))
 
 	probe := s.getProbeForPort(port)
-	if _, err = conn.Write(probe); err != nil {
+	quic := s.quicProbeFor(host, port)
+	if quic != nil {
+		probe = quic.packet
+	}
+	snmp := s.snmpProbeFor(port)
+	if snmp != nil {
+		err = snmp.send(conn)
+	} else {
+		_, err = conn.Write(probe)
+	}
+	if err != nil {
 		if ctx.Err() != nil {
 			return
 		}
@@ -121,8 +151,21 @@ func (s *UDPScanner) scanUDPPort(ctx context.Context, host string, port uint16)
 		return
 	}
 
-	buffer := make([]byte, s.config.UDPBufferSize)
-	n, err := conn.Read(buffer)
+	bufferSize := s.config.UDPBufferSize
+	if quic != nil {
+		// The server's Initial must be read whole to be decrypted.
+		bufferSize = max(bufferSize, quicMaxDatagram)
+	}
+	buffer := make([]byte, bufferSize)
+	var (
+		n          int
+		snmpBanner string
+	)
+	if snmp != nil {
+		n, snmpBanner, err = snmp.read(conn, buffer)
+	} else {
+		n, err = conn.Read(buffer)
+	}
 	if ctx.Err() != nil {
 		return
 	}
@@ -161,8 +204,21 @@ func (s *UDPScanner) scanUDPPort(ctx context.Context, host string, port uint16)
 	} else {
 		s.recordProbeAttempt(port, true)
 		result.State = StateOpen
-		if n > 0 && s.config.BannerGrab {
-			result.Banner = s.parseUDPResponse(port, buffer[:n])
+		if snmpBanner != "" {
+			// Community probing was asked for explicitly, so its answer is
+			// reported even without --banners.
+			result.Banner = snmpBanner
+		} else if n > 0 && s.config.BannerGrab {
+			reply := buffer[:min(n, s.config.BannerMaxBytes)]
+			if quic != nil {
+				result.Banner = describeQUIC(quic, port, buffer[:n])
+			} else {
+				result.Banner = s.parseUDPResponse(port, reply)
+			}
+			result.Transcript = []ProbeExchange{
+				{Sent: true, Data: probe},
+				{Data: append([]byte(nil), reply...)},
+			}
 		}
 	}
 
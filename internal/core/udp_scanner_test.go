@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"net"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -135,6 +136,7 @@ func TestUDPScannerIntegration(t *testing.T) {
 	}
 
 	scanner := NewUDPScanner(cfg)
+	scanner.AddCustomProbe(port, []byte("PING"))
 
 	// Scan the test port
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -163,6 +165,9 @@ func TestUDPScannerIntegration(t *testing.T) {
 			if r.Protocol != "udp" {
 				t.Errorf("Expected protocol udp, got %s", r.Protocol)
 			}
+			if len(r.Transcript) != 2 || string(r.Transcript[0].Data) != "PING" || string(r.Transcript[1].Data) != "ECHO" {
+				t.Errorf("Expected the probe and its reply in the transcript, got %+v", r.Transcript)
+			}
 			foundResult = true
 		case <-timeout:
 			t.Error("Timeout waiting for scan result")
@@ -204,6 +209,36 @@ func TestCustomProbes(t *testing.T) {
 	}
 }
 
+func TestConfigUDPProbes(t *testing.T) {
+	scanner := NewUDPScanner(&Config{
+		Workers: 1,
+		Timeout: 100 * time.Millisecond,
+		UDPProbes: map[uint16]UDPProbe{
+			1900: {Service: "SSDP/UPnP", Payload: []byte("M-SEARCH * HTTP/1.1\r\n\r\n"), Match: regexp.MustCompile(`^HTTP/1\.1 200`)},
+			53:   {Service: "dns-alt", Payload: []byte{0x01}},
+		},
+	})
+
+	if got := string(scanner.getProbeForPort(1900)); !strings.HasPrefix(got, "M-SEARCH") {
+		t.Errorf("port 1900 probe = %q; want the configured payload", got)
+	}
+	if got := scanner.getProbeForPort(53); len(got) != 1 {
+		t.Errorf("configured probe should replace the built-in DNS probe, got %d bytes", len(got))
+	}
+
+	if got := scanner.parseUDPResponse(1900, []byte("HTTP/1.1 200 OK\r\n")); got != "SSDP/UPnP (95.0%)" {
+		t.Errorf("matching response = %q; want the probe's service", got)
+	}
+	// A reply the pattern rejects, or a probe without one, falls back to
+	// the built-in parsers.
+	if got := scanner.parseUDPResponse(1900, []byte("hello")); strings.Contains(got, "SSDP") {
+		t.Errorf("non-matching response = %q; should not name the probe's service", got)
+	}
+	if got := scanner.parseUDPResponse(53, []byte{0x00}); strings.Contains(got, "dns-alt") {
+		t.Errorf("probe without a pattern should not name its service, got %q", got)
+	}
+}
+
 func TestBuildDNSProbe(t *testing.T) {
 	probe := buildDNSProbe()
 	if len(probe) == 0 {
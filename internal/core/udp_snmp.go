@@ -0,0 +1,244 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// snmpPort is where community probing runs.
+const snmpPort = 161
+
+// OIDs read from the system group, BER-encoded.
+var (
+	oidSysDescr = []byte{0x2b, 0x06, 0x01, 0x02, 0x01, 0x01, 0x01, 0x00} // 1.3.6.1.2.1.1.1.0
+	oidSysName  = []byte{0x2b, 0x06, 0x01, 0x02, 0x01, 0x01, 0x05, 0x00} // 1.3.6.1.2.1.1.5.0
+)
+
+// BER tags used by SNMPv1 messages.
+const (
+	berInteger     = 0x02
+	berOctetString = 0x04
+	berNull        = 0x05
+	berOID         = 0x06
+	berSequence    = 0x30
+	snmpGetRequest = 0xa0
+	snmpGetReply   = 0xa2
+)
+
+// snmpProbe tries several community strings at once: one GetRequest per
+// community, told apart by request ID. Agents drop requests with a wrong
+// community, so the replies that arrive name the communities that work.
+type snmpProbe struct {
+	communities []string
+}
+
+// snmpReply is a decoded GetResponse.
+type snmpReply struct {
+	requestID int
+	errStatus int
+	sysDescr  string
+	sysName   string
+}
+
+// snmpProbeFor returns a community probe for port 161 when
+// Config.SNMPCommunities is set and no custom probe replaces it.
+func (s *UDPScanner) snmpProbeFor(port uint16) *snmpProbe {
+	if port != snmpPort || len(s.config.SNMPCommunities) == 0 {
+		return nil
+	}
+	s.probeMu.RLock()
+	_, custom := s.customProbes[port]
+	s.probeMu.RUnlock()
+	if custom {
+		return nil
+	}
+	return &snmpProbe{communities: s.config.SNMPCommunities}
+}
+
+// send writes one GetRequest for sysDescr and sysName per community.
+func (p *snmpProbe) send(conn net.Conn) error {
+	for i, community := range p.communities {
+		if _, err := conn.Write(buildSNMPGet(community, i+1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// read waits for replies into buf until one carries system values or the
+// read deadline passes. It returns the length of the last reply read and
+// a banner naming the community that answered, or the first read error
+// when nothing arrived.
+func (p *snmpProbe) read(conn net.Conn, buf []byte) (int, string, error) {
+	n, err := conn.Read(buf)
+	if err != nil {
+		return 0, "", err
+	}
+	for {
+		if banner := p.describe(buf[:n]); banner != "" {
+			return n, banner, nil
+		}
+		more, err := conn.Read(buf)
+		if err != nil {
+			// An agent answered, just not with system values.
+			return n, "", nil
+		}
+		n = more
+	}
+}
+
+// describe formats a reply to one of the probe's requests as a banner such
+// as "SNMP, community public, core-sw1: Cisco IOS Software (95.0%)".
+func (p *snmpProbe) describe(data []byte) string {
+	reply, ok := parseSNMPReply(data)
+	if !ok || reply.errStatus != 0 || reply.requestID < 1 || reply.requestID > len(p.communities) {
+		return ""
+	}
+	if reply.sysDescr == "" && reply.sysName == "" {
+		return ""
+	}
+	community := p.communities[reply.requestID-1]
+	system := strings.Join(strings.Fields(reply.sysDescr), " ")
+	if reply.sysName != "" {
+		system = strings.TrimSuffix(reply.sysName+": "+system, ": ")
+	}
+	return fmt.Sprintf("SNMP, community %s, %s (95.0%%)", community, system)
+}
+
+// buildSNMPGet encodes an SNMPv1 GetRequest for sysDescr.0 and sysName.0.
+func buildSNMPGet(community string, requestID int) []byte {
+	varbind := func(oid []byte) []byte {
+		return berTLV(berSequence, append(berTLV(berOID, oid), berNull, 0x00))
+	}
+	varbinds := berTLV(berSequence, append(varbind(oidSysDescr), varbind(oidSysName)...))
+
+	pdu := berInt(requestID)
+	pdu = append(pdu, berInt(0)...) // error status
+	pdu = append(pdu, berInt(0)...) // error index
+	pdu = append(pdu, varbinds...)
+
+	msg := berInt(0) // version 1
+	msg = append(msg, berTLV(berOctetString, []byte(community))...)
+	msg = append(msg, berTLV(snmpGetRequest, pdu)...)
+	return berTLV(berSequence, msg)
+}
+
+// parseSNMPReply decodes an SNMPv1 or v2c GetResponse, keeping the
+// sysDescr and sysName values.
+func parseSNMPReply(data []byte) (snmpReply, bool) {
+	var reply snmpReply
+	tag, msg, _, ok := readBER(data)
+	if !ok || tag != berSequence {
+		return reply, false
+	}
+	if tag, _, msg, ok = readBER(msg); !ok || tag != berInteger { // version
+		return reply, false
+	}
+	if tag, _, msg, ok = readBER(msg); !ok || tag != berOctetString { // community
+		return reply, false
+	}
+	tag, pdu, _, ok := readBER(msg)
+	if !ok || tag != snmpGetReply {
+		return reply, false
+	}
+
+	var fields [3]int
+	for i := range fields {
+		var value []byte
+		if tag, value, pdu, ok = readBER(pdu); !ok || tag != berInteger {
+			return reply, false
+		}
+		fields[i] = berIntValue(value)
+	}
+	reply.requestID, reply.errStatus = fields[0], fields[1]
+
+	tag, varbinds, _, ok := readBER(pdu)
+	if !ok || tag != berSequence {
+		return reply, false
+	}
+	for len(varbinds) > 0 {
+		var varbind, oid, value []byte
+		if tag, varbind, varbinds, ok = readBER(varbinds); !ok || tag != berSequence {
+			return reply, false
+		}
+		if tag, oid, varbind, ok = readBER(varbind); !ok || tag != berOID {
+			return reply, false
+		}
+		if tag, value, _, ok = readBER(varbind); !ok {
+			return reply, false
+		}
+		if tag != berOctetString {
+			continue
+		}
+		switch {
+		case bytes.Equal(oid, oidSysDescr):
+			reply.sysDescr = sanitizeBanner(value)
+		case bytes.Equal(oid, oidSysName):
+			reply.sysName = sanitizeBanner(value)
+		}
+	}
+	return reply, true
+}
+
+// berTLV encodes one BER element with a definite length.
+func berTLV(tag byte, content []byte) []byte {
+	out := []byte{tag}
+	switch n := len(content); {
+	case n < 0x80:
+		out = append(out, byte(n))
+	case n <= 0xff:
+		out = append(out, 0x81, byte(n))
+	default:
+		out = append(out, 0x82, byte(n>>8), byte(n))
+	}
+	return append(out, content...)
+}
+
+// berInt encodes a non-negative INTEGER.
+func berInt(v int) []byte {
+	content := []byte{byte(v)}
+	for v >>= 8; v > 0; v >>= 8 {
+		content = append([]byte{byte(v)}, content...)
+	}
+	if content[0]&0x80 != 0 {
+		content = append([]byte{0}, content...)
+	}
+	return berTLV(berInteger, content)
+}
+
+// berIntValue decodes INTEGER content of up to four bytes.
+func berIntValue(content []byte) int {
+	if len(content) == 0 || len(content) > 4 {
+		return -1
+	}
+	v := int(int8(content[0]))
+	for _, b := range content[1:] {
+		v = v<<8 | int(b)
+	}
+	return v
+}
+
+// readBER splits the first BER element off data.
+func readBER(data []byte) (tag byte, content, rest []byte, ok bool) {
+	if len(data) < 2 {
+		return 0, nil, nil, false
+	}
+	tag, length, off := data[0], int(data[1]), 2
+	if length&0x80 != 0 {
+		size := length & 0x7f
+		if size == 0 || size > 2 || len(data) < 2+size {
+			return 0, nil, nil, false
+		}
+		length = 0
+		for _, b := range data[2 : 2+size] {
+			length = length<<8 | int(b)
+		}
+		off += size
+	}
+	if off+length > len(data) {
+		return 0, nil, nil, false
+	}
+	return tag, data[off : off+length], data[off+length:], true
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// snmpTestReply builds a GetResponse to requestID carrying sysDescr and
+// sysName.
+func snmpTestReply(community string, requestID int, descr, name string) []byte {
+	varbind := func(oid []byte, value string) []byte {
+		return berTLV(berSequence, append(berTLV(berOID, oid), berTLV(berOctetString, []byte(value))...))
+	}
+	pdu := append(berInt(requestID), berInt(0)...)
+	pdu = append(pdu, berInt(0)...)
+	pdu = append(pdu, berTLV(berSequence, append(varbind(oidSysDescr, descr), varbind(oidSysName, name)...))...)
+	msg := append(berInt(0), berTLV(berOctetString, []byte(community))...)
+	msg = append(msg, berTLV(snmpGetReply, pdu)...)
+	return berTLV(berSequence, msg)
+}
+
+func TestBuildSNMPGet(t *testing.T) {
+	long := strings.Repeat("c", 200) // pushes the message past one-byte lengths
+	for _, community := range []string{"public", long} {
+		tag, msg, rest, ok := readBER(buildSNMPGet(community, 300))
+		if !ok || tag != berSequence || len(rest) != 0 {
+			t.Fatalf("request for %d-byte community is not one SEQUENCE", len(community))
+		}
+		_, _, msg, _ = readBER(msg)
+		tag, got, msg, _ := readBER(msg)
+		if tag != berOctetString || string(got) != community {
+			t.Errorf("community = %q", got)
+		}
+		tag, pdu, _, _ := readBER(msg)
+		if tag != snmpGetRequest {
+			t.Fatalf("PDU tag = %#x, want GetRequest", tag)
+		}
+		_, id, _, _ := readBER(pdu)
+		if berIntValue(id) != 300 {
+			t.Errorf("request ID = %d, want 300", berIntValue(id))
+		}
+	}
+}
+
+func TestParseSNMPReply(t *testing.T) {
+	reply, ok := parseSNMPReply(snmpTestReply("public", 2, "Cisco IOS Software,\r\nC2960", "core-sw1"))
+	if !ok || reply.requestID != 2 || reply.sysName != "core-sw1" || !strings.HasPrefix(reply.sysDescr, "Cisco IOS") {
+		t.Errorf("parseSNMPReply() = %+v, %v", reply, ok)
+	}
+
+	probe := &snmpProbe{communities: []string{"private", "public"}}
+	got := probe.describe(snmpTestReply("public", 2, "Cisco IOS Software,\r\nC2960", "core-sw1"))
+	if want := "SNMP, community public, core-sw1: Cisco IOS Software,C2960 (95.0%)"; got != want {
+		t.Errorf("describe() = %q, want %q", got, want)
+	}
+	if got := probe.describe(snmpTestReply("public", 3, "x", "y")); got != "" {
+		t.Errorf("reply to an unknown request described as %q", got)
+	}
+	if _, ok := parseSNMPReply([]byte{0x30, 0x05, 0x02}); ok {
+		t.Error("truncated reply should not parse")
+	}
+}
+
+func TestSNMPProbeFindsCommunity(t *testing.T) {
+	agent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer agent.Close()
+	go func() {
+		// Like a real agent, answer only the request with the right
+		// community.
+		buf := make([]byte, 512)
+		for {
+			n, from, err := agent.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if strings.Contains(string(buf[:n]), "secret") {
+				_, _ = agent.WriteTo(snmpTestReply("secret", 2, "Linux nas 6.1", "nas"), from)
+			}
+		}
+	}()
+
+	conn, err := net.Dial("udp", agent.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	probe := &snmpProbe{communities: []string{"public", "secret"}}
+	if err := probe.send(conn); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	_, banner, err := probe.read(conn, make([]byte, 512))
+	if err != nil || banner != "SNMP, community secret, nas: Linux nas 6.1 (95.0%)" {
+		t.Errorf("read() = %q, %v", banner, err)
+	}
+}
+
+func TestSNMPProbeFor(t *testing.T) {
+	scanner := NewUDPScanner(&Config{Workers: 1, SNMPCommunities: []string{"public"}})
+	if scanner.snmpProbeFor(161) == nil {
+		t.Error("port 161 should get a community probe")
+	}
+	if scanner.snmpProbeFor(162) != nil {
+		t.Error("only port 161 is probed for communities")
+	}
+	scanner.AddCustomProbe(161, []byte{0x01})
+	if scanner.snmpProbeFor(161) != nil {
+		t.Error("a custom probe should replace community probing")
+	}
+	if NewUDPScanner(&Config{Workers: 1}).snmpProbeFor(161) != nil {
+		t.Error("community probing is opt-in")
+	}
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultVerifyHold is how long a verification connection must stay up
+// without being reset for the port to count as verified.
+const DefaultVerifyHold = 100 * time.Millisecond
+
+// VerifyOptions configures the open-port verification pass.
+type VerifyOptions struct {
+	Timeout time.Duration // dial timeout; defaults to DefaultTimeoutMs
+	Hold    time.Duration // reset window after connecting; defaults to DefaultVerifyHold
+	Workers int           // concurrent verifications; defaults to 16
+	// Resolver gives the address a hostname result is reconnected to, so
+	// verification hits the server the scan found open; nil dials by name.
+	Resolver HostResolver
+}
+
+// VerifyOpen re-connects to every open TCP port in events before passing
+// the result on. Ports that accept and then reset, as SYN proxies and some
+// load balancers do, are reported as filtered; the rest are marked
+// Verified. Other events pass through unchanged. The returned channel is
+// closed once events is closed and every verification has finished.
+func VerifyOpen(ctx context.Context, events <-chan Event, opts VerifyOptions) <-chan Event {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeoutMs * time.Millisecond
+	}
+	if opts.Hold <= 0 {
+		opts.Hold = DefaultVerifyHold
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 16
+	}
+
+	out := make(chan Event, cap(events))
+	jobs := make(chan ResultEvent)
+	var wg sync.WaitGroup
+
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dialer := &net.Dialer{Timeout: opts.Timeout}
+			for result := range jobs {
+				if verifyConnection(ctx, dialer, opts.Resolver, result, opts.Hold) {
+					result.Verified = true
+				} else {
+					result.State = StateFiltered
+				}
+				select {
+				case out <- NewResultEvent(result):
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer func() {
+			close(jobs)
+			wg.Wait()
+			close(out)
+		}()
+		for event := range events {
+			if event.Kind == EventKindResult && needsVerification(*event.Result) {
+				select {
+				case jobs <- *event.Result:
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+func needsVerification(r ResultEvent) bool {
+	return r.State == StateOpen && (r.Protocol == "" || r.Protocol == "tcp")
+}
+
+// verifyConnection dials the result's port and waits up to hold for the
+// peer to reset or close the connection. Receiving data or reaching the
+// end of the window counts as a live service.
+func verifyConnection(ctx context.Context, dialer *net.Dialer, resolver HostResolver, r ResultEvent, hold time.Duration) bool {
+	address, err := dialAddress(ctx, resolver, r.Host, r.Port)
+	if err != nil {
+		return false
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = conn.Close() }()
+
+	_ = conn.SetReadDeadline(time.Now().Add(hold))
+	n, err := conn.Read(make([]byte, 1))
+	if n > 0 || err == nil {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	// EOF or a reset before any data: accepted, then dropped.
+	return false
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/testserver"
+)
+
+func TestVerifyOpen(t *testing.T) {
+	server, err := testserver.Start("", []testserver.Service{
+		{Protocol: "tcp", Banner: "SSH-2.0-mock\r\n"},
+		{Protocol: "tcp", Delay: time.Second}, // silent but alive
+		{Protocol: "tcp", Reset: true},
+	})
+	if err != nil {
+		t.Fatalf("failed to start mock server: %v", err)
+	}
+	defer server.Close()
+	ports := server.Ports("tcp")
+
+	events := make(chan Event, 8)
+	for _, port := range ports {
+		events <- NewResultEvent(ResultEvent{Host: server.Host, Port: port, State: StateOpen, Protocol: "tcp"})
+	}
+	events <- NewResultEvent(ResultEvent{Host: server.Host, Port: 1, State: StateClosed, Protocol: "tcp"})
+	events <- NewResultEvent(ResultEvent{Host: server.Host, Port: 53, State: StateOpen, Protocol: "udp"})
+	events <- NewProgressEvent(ProgressEvent{Total: 5, Completed: 5})
+	close(events)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	results := make(map[uint16]ResultEvent)
+	var progress int
+	for event := range VerifyOpen(ctx, events, VerifyOptions{Timeout: 500 * time.Millisecond, Hold: 50 * time.Millisecond}) {
+		switch event.Kind {
+		case EventKindResult:
+			results[event.Result.Port] = *event.Result
+		case EventKindProgress:
+			progress++
+		}
+	}
+
+	if len(results) != 5 || progress != 1 {
+		t.Fatalf("got %d results and %d progress events, want 5 and 1", len(results), progress)
+	}
+	for _, port := range ports[:2] {
+		if r := results[port]; r.State != StateOpen || !r.Verified {
+			t.Errorf("live port %d = %+v, want open and verified", port, r)
+		}
+	}
+	if r := results[ports[2]]; r.State != StateFiltered || r.Verified {
+		t.Errorf("reset port = %+v, want filtered and unverified", r)
+	}
+	if r := results[1]; r.State != StateClosed || r.Verified {
+		t.Errorf("closed port = %+v, want unchanged", r)
+	}
+	if r := results[53]; r.State != StateOpen || r.Verified {
+		t.Errorf("udp port = %+v, want passed through unverified", r)
+	}
+}
+
+func TestVerifyOpen_Cancelled(t *testing.T) {
+	events := make(chan Event)
+	ctx, cancel := context.WithCancel(context.Background())
+	out := VerifyOpen(ctx, events, VerifyOptions{})
+	cancel()
+	close(events)
+
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Error("no events expected after cancellation")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("output not closed after input closed")
+	}
+}
@@ -0,0 +1,78 @@
+//go:build linux
+
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+func platformChecks(opts Options) []Check {
+	return []Check{rawSocketCheck(), readConntrack(opts.Workers), readFirewall()}
+}
+
+// rawSocketCheck reports whether the process may open raw sockets. Connect
+// scans do not need them; SYN scanning and some low-level probes do.
+func rawSocketCheck() Check {
+	c := Check{Name: "raw sockets"}
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_RAW, syscall.IPPROTO_TCP)
+	if err == nil {
+		_ = syscall.Close(fd)
+		c.Status = StatusOK
+		c.Detail = "CAP_NET_RAW available"
+		return c
+	}
+	c.Status = StatusInfo
+	c.Detail = fmt.Sprintf("unavailable (%v); not needed for connect scans", err)
+	if errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.EACCES) {
+		c.Fix = "Grant the capability without running as root:\nsudo setcap cap_net_raw+ep \"$(command -v portscan)\""
+	}
+	return c
+}
+
+// readConntrack checks the netfilter connection tracking table, when the
+// module is loaded.
+func readConntrack(workers int) Check {
+	max, errMax := readProcInt("/proc/sys/net/netfilter/nf_conntrack_max")
+	count, errCount := readProcInt("/proc/sys/net/netfilter/nf_conntrack_count")
+	if errMax != nil || errCount != nil {
+		return Check{Name: "conntrack table", Status: StatusSkip, Detail: "nf_conntrack not loaded"}
+	}
+	return conntrackCheck(count, max, workers)
+}
+
+// readFirewall inspects outbound firewall rules with iptables, falling
+// back to nft. Listing rules usually needs root.
+func readFirewall() Check {
+	for _, tool := range [][]string{
+		{"iptables", "-S", "OUTPUT"},
+		{"nft", "list", "ruleset"},
+	} {
+		if _, err := exec.LookPath(tool[0]); err != nil {
+			continue
+		}
+		out, err := exec.Command(tool[0], tool[1:]...).Output()
+		if err != nil {
+			return Check{
+				Name:   "local firewall",
+				Status: StatusSkip,
+				Detail: fmt.Sprintf("could not list rules with %s (run as root to check)", tool[0]),
+			}
+		}
+		return firewallCheck(tool[0], string(out))
+	}
+	return Check{Name: "local firewall", Status: StatusSkip, Detail: "neither iptables nor nft found"}
+}
+
+func readProcInt(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
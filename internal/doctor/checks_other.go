@@ -0,0 +1,13 @@
+//go:build !linux
+
+package doctor
+
+// platformChecks covers Linux-specific settings; elsewhere only the file
+// descriptor limit is checked.
+func platformChecks(Options) []Check {
+	return []Check{
+		{Name: "raw sockets", Status: StatusSkip, Detail: "only checked on Linux"},
+		{Name: "conntrack table", Status: StatusSkip, Detail: "only checked on Linux"},
+		{Name: "local firewall", Status: StatusSkip, Detail: "only checked on Linux"},
+	}
+}
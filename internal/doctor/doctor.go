@@ -0,0 +1,176 @@
+// Package doctor checks the host for limits and settings that commonly
+// break or slow down large scans, and suggests how to fix each one.
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+// Status is the outcome of a single check.
+type Status int
+
+const (
+	StatusOK Status = iota
+	StatusInfo
+	StatusWarn
+	StatusFail
+	StatusSkip
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusOK:
+		return "ok"
+	case StatusInfo:
+		return "info"
+	case StatusWarn:
+		return "warn"
+	case StatusFail:
+		return "fail"
+	default:
+		return "skip"
+	}
+}
+
+// Check is the result of one preflight check.
+type Check struct {
+	Name   string
+	Status Status
+	Detail string // what was found
+	Fix    string // how to resolve a warning or failure; empty when nothing to do
+}
+
+// Options configures the checks.
+type Options struct {
+	// Workers is the scan worker count the file descriptor limit must fit.
+	Workers int
+	// BannerWorkers is the banner pool size, or 0 when banners are off.
+	BannerWorkers int
+}
+
+// Run performs every check supported on this platform.
+func Run(opts Options) []Check {
+	soft, hard, ok := core.FileLimit()
+	checks := []Check{fileLimitCheck(soft, hard, ok, opts.Workers+opts.BannerWorkers)}
+	return append(checks, platformChecks(opts)...)
+}
+
+// Failed reports how many checks failed.
+func Failed(checks []Check) int {
+	n := 0
+	for _, c := range checks {
+		if c.Status == StatusFail {
+			n++
+		}
+	}
+	return n
+}
+
+// Print writes checks as a report with each fix indented below its check.
+func Print(w io.Writer, checks []Check) {
+	for _, c := range checks {
+		fmt.Fprintf(w, "[%-4s] %-20s %s\n", c.Status, c.Name, c.Detail)
+		if c.Fix == "" {
+			continue
+		}
+		for _, line := range strings.Split(c.Fix, "\n") {
+			fmt.Fprintf(w, "       %-20s %s\n", "", line)
+		}
+	}
+}
+
+// fileLimitCheck reports whether the soft open file limit covers the
+// given number of concurrent sockets plus core.FDReserve.
+func fileLimitCheck(soft, hard uint64, ok bool, sockets int) Check {
+	c := Check{Name: "file descriptors"}
+	if !ok {
+		c.Status = StatusSkip
+		c.Detail = "open file limit not available on this platform"
+		return c
+	}
+	need := uint64(sockets + core.FDReserve)
+	c.Detail = fmt.Sprintf("soft limit %d, hard limit %d; %d sockets need %d", soft, hard, sockets, need)
+	switch {
+	case soft >= need:
+		c.Status = StatusOK
+	case hard >= need:
+		c.Status = StatusWarn
+		c.Fix = fmt.Sprintf("Run 'ulimit -n %d' in this shell, or scan with --raise-fd-limit.\nWorkers are capped to the limit otherwise.", need)
+	default:
+		c.Status = StatusFail
+		c.Fix = fmt.Sprintf("Raise the hard limit to at least %d (e.g. 'nofile' in /etc/security/limits.conf\nor LimitNOFILE= in a systemd unit), or lower --workers.", need)
+	}
+	return c
+}
+
+// conntrackCheck reports whether the connection tracking table can hold
+// the entries a scan adds; every probe occupies one until it times out.
+func conntrackCheck(count, max, workers int) Check {
+	c := Check{Name: "conntrack table"}
+	c.Detail = fmt.Sprintf("%d of %d entries in use", count, max)
+	const minEntries = 65536
+	switch {
+	case max > 0 && count*10 >= max*8:
+		c.Status = StatusWarn
+		c.Fix = fmt.Sprintf("The table is over 80%% full; new probes may be dropped and reported filtered.\nRaise it: sysctl -w net.netfilter.nf_conntrack_max=%d", max*2)
+	case max < minEntries || max < workers*100:
+		c.Status = StatusWarn
+		c.Fix = fmt.Sprintf("Fast scans fill small tables quickly. Raise it:\nsysctl -w net.netfilter.nf_conntrack_max=%d", minEntries*4)
+	default:
+		c.Status = StatusOK
+	}
+	return c
+}
+
+// firewallCheck looks for outbound rules that drop or reject traffic in
+// the output of 'iptables -S OUTPUT' or 'nft list ruleset'.
+func firewallCheck(tool, rules string) Check {
+	c := Check{Name: "local firewall"}
+	var drops []string
+	outputChain := false
+	for _, line := range strings.Split(rules, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "-P OUTPUT DROP":
+			drops = append(drops, line)
+		case strings.HasPrefix(line, "-A OUTPUT") &&
+			(strings.Contains(line, "-j DROP") || strings.Contains(line, "-j REJECT")):
+			drops = append(drops, line)
+		case strings.HasPrefix(line, "type filter hook output"):
+			outputChain = true
+			if strings.Contains(line, "policy drop") {
+				drops = append(drops, line)
+			}
+		case strings.HasPrefix(line, "chain "):
+			outputChain = false
+		case outputChain && hasVerdict(line, "drop", "reject"):
+			drops = append(drops, line)
+		}
+	}
+	if len(drops) == 0 {
+		c.Status = StatusOK
+		c.Detail = fmt.Sprintf("no outbound drop rules (%s)", tool)
+		return c
+	}
+	c.Status = StatusWarn
+	c.Detail = fmt.Sprintf("%d outbound drop/reject rule(s) (%s)", len(drops), tool)
+	c.Fix = "Outbound probes matching these rules never leave the host and show as filtered:\n" +
+		strings.Join(drops, "\n")
+	return c
+}
+
+// hasVerdict reports whether an nft rule applies one of verdicts.
+func hasVerdict(rule string, verdicts ...string) bool {
+	for _, field := range strings.Fields(rule) {
+		for _, v := range verdicts {
+			if field == v {
+				return true
+			}
+		}
+	}
+	return false
+}
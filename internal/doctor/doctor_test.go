@@ -0,0 +1,115 @@
+package doctor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+func TestFileLimitCheck(t *testing.T) {
+	need := uint64(100 + core.FDReserve)
+	tests := []struct {
+		name      string
+		soft      uint64
+		hard      uint64
+		ok        bool
+		want      Status
+		wantInFix string
+	}{
+		{"fits", 1024, 4096, true, StatusOK, ""},
+		{"soft too low", 128, 4096, true, StatusWarn, "--raise-fd-limit"},
+		{"hard too low", 128, 128, true, StatusFail, "limits.conf"},
+		{"unavailable", 0, 0, false, StatusSkip, ""},
+		{"exact", need, need, true, StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fileLimitCheck(tt.soft, tt.hard, tt.ok, 100)
+			if c.Status != tt.want {
+				t.Errorf("status = %v, want %v", c.Status, tt.want)
+			}
+			if !strings.Contains(c.Fix, tt.wantInFix) {
+				t.Errorf("fix %q should mention %q", c.Fix, tt.wantInFix)
+			}
+		})
+	}
+}
+
+func TestConntrackCheck(t *testing.T) {
+	if c := conntrackCheck(100, 262144, 200); c.Status != StatusOK {
+		t.Errorf("roomy table: status = %v, want ok", c.Status)
+	}
+	if c := conntrackCheck(0, 16384, 100); c.Status != StatusWarn || !strings.Contains(c.Fix, "nf_conntrack_max") {
+		t.Errorf("small table: got %v %q", c.Status, c.Fix)
+	}
+	if c := conntrackCheck(230000, 262144, 100); c.Status != StatusWarn || !strings.Contains(c.Fix, "80%") {
+		t.Errorf("nearly full table: got %v %q", c.Status, c.Fix)
+	}
+}
+
+func TestFirewallCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		tool  string
+		rules string
+		drops int
+	}{
+		{"iptables accept", "iptables", "-P OUTPUT ACCEPT\n-A OUTPUT -o lo -j ACCEPT\n", 0},
+		{"iptables policy drop", "iptables", "-P OUTPUT DROP\n-A OUTPUT -p tcp --dport 443 -j ACCEPT\n", 1},
+		{"iptables reject rule", "iptables", "-P OUTPUT ACCEPT\n-A OUTPUT -p tcp --dport 25 -j REJECT --reject-with icmp-port-unreachable\n", 1},
+		{"nft output drop", "nft", `table inet filter {
+	chain input {
+		type filter hook input priority filter; policy drop;
+		tcp dport 22 accept
+	}
+	chain output {
+		type filter hook output priority filter; policy accept;
+		tcp dport 23 reject with tcp reset
+		ip daddr 10.0.0.0/8 drop
+	}
+}`, 2},
+		{"nft input drop only", "nft", `table inet filter {
+	chain input {
+		type filter hook input priority filter; policy drop;
+		udp dport 53 drop
+	}
+}`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := firewallCheck(tt.tool, tt.rules)
+			if tt.drops == 0 {
+				if c.Status != StatusOK {
+					t.Errorf("status = %v (%s), want ok", c.Status, c.Fix)
+				}
+				return
+			}
+			if c.Status != StatusWarn {
+				t.Fatalf("status = %v, want warn", c.Status)
+			}
+			if got := strings.Count(c.Fix, "\n"); got != tt.drops {
+				t.Errorf("fix lists %d rules, want %d:\n%s", got, tt.drops, c.Fix)
+			}
+		})
+	}
+}
+
+func TestPrintAndFailed(t *testing.T) {
+	checks := []Check{
+		{Name: "file descriptors", Status: StatusFail, Detail: "soft limit 64", Fix: "line one\nline two"},
+		{Name: "raw sockets", Status: StatusOK, Detail: "available"},
+	}
+	var buf bytes.Buffer
+	Print(&buf, checks)
+	out := buf.String()
+	for _, want := range []string{"[fail] file descriptors", "[ok  ] raw sockets", "line one", "line two"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report should contain %q:\n%s", want, out)
+		}
+	}
+	if got := Failed(checks); got != 1 {
+		t.Errorf("Failed() = %d, want 1", got)
+	}
+}
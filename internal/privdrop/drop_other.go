@@ -0,0 +1,10 @@
+//go:build !unix
+
+package privdrop
+
+import "errors"
+
+// Drop is not supported on this platform.
+func Drop(Credentials) error {
+	return errors.New("dropping privileges is not supported on this platform")
+}
@@ -0,0 +1,40 @@
+//go:build unix
+
+package privdrop
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Drop switches every thread of the process to creds: supplementary
+// groups first, then the group, then the user. It is a no-op when the
+// process already runs as creds, and verifies afterwards that root cannot
+// be regained.
+func Drop(creds Credentials) error {
+	if os.Geteuid() == creds.UID && os.Getegid() == creds.GID {
+		return nil
+	}
+	if os.Geteuid() != 0 {
+		return ErrNotRoot
+	}
+
+	if err := syscall.Setgroups(creds.Groups); err != nil {
+		return fmt.Errorf("setgroups: %w", err)
+	}
+	if err := syscall.Setgid(creds.GID); err != nil {
+		return fmt.Errorf("setgid %d: %w", creds.GID, err)
+	}
+	if err := syscall.Setuid(creds.UID); err != nil {
+		return fmt.Errorf("setuid %d: %w", creds.UID, err)
+	}
+
+	if creds.UID != 0 {
+		if err := syscall.Setuid(0); err == nil {
+			return errors.New("privileges were not dropped: setuid(0) still succeeds")
+		}
+	}
+	return nil
+}
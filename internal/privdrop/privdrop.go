@@ -0,0 +1,94 @@
+// Package privdrop switches a process started as root to an unprivileged
+// user once privileged setup, such as raising resource limits or opening
+// raw sockets, is done.
+package privdrop
+
+import (
+	"errors"
+	"fmt"
+	"os/user"
+	"strconv"
+	"strings"
+)
+
+// ErrNotRoot is returned when asked to switch to another user without
+// running as root.
+var ErrNotRoot = errors.New("only root can switch users")
+
+// Credentials are the user and groups a process runs as after dropping
+// privileges.
+type Credentials struct {
+	Name   string // the user as given, for messages
+	UID    int
+	GID    int
+	Groups []int // supplementary groups
+}
+
+// Lookup resolves spec, "USER" or "USER:GROUP", to credentials. USER and
+// GROUP may be names or numeric IDs. Without GROUP the user's primary group
+// is used, and for a named user its supplementary groups are kept.
+func Lookup(spec string) (Credentials, error) {
+	name, group, hasGroup := strings.Cut(spec, ":")
+	if name == "" || (hasGroup && group == "") {
+		return Credentials{}, fmt.Errorf("invalid --run-as %q: want USER or USER:GROUP", spec)
+	}
+
+	creds := Credentials{Name: spec}
+	u, err := lookupUser(name)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if creds.UID, err = strconv.Atoi(u.Uid); err != nil {
+		return Credentials{}, fmt.Errorf("user %q has non-numeric uid %q", name, u.Uid)
+	}
+
+	gid := u.Gid
+	if hasGroup {
+		g, err := lookupGroup(group)
+		if err != nil {
+			return Credentials{}, err
+		}
+		gid = g.Gid
+	}
+	if creds.GID, err = strconv.Atoi(gid); err != nil {
+		return Credentials{}, fmt.Errorf("group of %q has non-numeric gid %q", spec, gid)
+	}
+
+	creds.Groups = []int{creds.GID}
+	if ids, err := u.GroupIds(); err == nil && !hasGroup {
+		for _, id := range ids {
+			if n, err := strconv.Atoi(id); err == nil && n != creds.GID {
+				creds.Groups = append(creds.Groups, n)
+			}
+		}
+	}
+	return creds, nil
+}
+
+// lookupUser finds a user by name, then by numeric uid.
+func lookupUser(name string) (*user.User, error) {
+	u, err := user.Lookup(name)
+	if err == nil {
+		return u, nil
+	}
+	if _, convErr := strconv.Atoi(name); convErr == nil {
+		if u, idErr := user.LookupId(name); idErr == nil {
+			return u, nil
+		}
+		// A bare uid without a passwd entry is still usable.
+		return &user.User{Uid: name, Gid: name, Username: name}, nil
+	}
+	return nil, fmt.Errorf("unknown user %q: %w", name, err)
+}
+
+// lookupGroup finds a group by name, then by numeric gid.
+func lookupGroup(name string) (*user.Group, error) {
+	g, err := user.LookupGroup(name)
+	if err == nil {
+		return g, nil
+	}
+	if _, convErr := strconv.Atoi(name); convErr == nil {
+		return &user.Group{Gid: name, Name: name}, nil
+	}
+	return nil, fmt.Errorf("unknown group %q: %w", name, err)
+}
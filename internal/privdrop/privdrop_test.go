@@ -0,0 +1,62 @@
+package privdrop
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	current, err := user.Current()
+	if err != nil {
+		t.Skipf("current user unavailable: %v", err)
+	}
+	uid, _ := strconv.Atoi(current.Uid)
+	gid, _ := strconv.Atoi(current.Gid)
+
+	for _, spec := range []string{current.Username, current.Uid} {
+		creds, err := Lookup(spec)
+		if err != nil {
+			t.Fatalf("Lookup(%q): %v", spec, err)
+		}
+		if creds.UID != uid || creds.GID != gid {
+			t.Errorf("Lookup(%q) = uid %d gid %d; want %d %d", spec, creds.UID, creds.GID, uid, gid)
+		}
+		if len(creds.Groups) == 0 || creds.Groups[0] != gid {
+			t.Errorf("Lookup(%q) groups = %v; want primary group %d first", spec, creds.Groups, gid)
+		}
+	}
+
+	creds, err := Lookup(current.Username + ":4242")
+	if err != nil {
+		t.Fatalf("Lookup with numeric group: %v", err)
+	}
+	if creds.GID != 4242 || len(creds.Groups) != 1 {
+		t.Errorf("explicit group: gid %d groups %v; want 4242 only", creds.GID, creds.Groups)
+	}
+}
+
+func TestLookupInvalid(t *testing.T) {
+	for _, spec := range []string{"", ":wheel", "root:", "no-such-user-portscan"} {
+		if _, err := Lookup(spec); err == nil {
+			t.Errorf("Lookup(%q) should fail", spec)
+		}
+	}
+}
+
+func TestDropToCurrentUserIsNoop(t *testing.T) {
+	creds := Credentials{UID: os.Geteuid(), GID: os.Getegid()}
+	if err := Drop(creds); err != nil {
+		t.Errorf("dropping to the current user should be a no-op: %v", err)
+	}
+}
+
+func TestDropRequiresRoot(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("running as root; dropping would affect the test process")
+	}
+	if err := Drop(Credentials{UID: os.Geteuid() + 1, GID: os.Getegid()}); err != ErrNotRoot {
+		t.Errorf("Drop as non-root = %v; want ErrNotRoot", err)
+	}
+}
@@ -0,0 +1,194 @@
+// Package testserver runs mock TCP and UDP targets for integration tests
+// and for users validating a scanner setup.
+package testserver
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Service describes one mock listener.
+type Service struct {
+	Protocol string        // "tcp" or "udp"
+	Port     uint16        // 0 picks a free port
+	Banner   string        // sent on connect (TCP) or in reply to a datagram (UDP)
+	Delay    time.Duration // wait before sending the banner
+	Reset    bool          // TCP: reset connections instead of answering; UDP: never reply
+	DropRate float64       // TCP: fraction of connections reset at random (0-1)
+}
+
+// Server owns a set of running mock listeners.
+type Server struct {
+	Host     string
+	services []Service
+	tcp      []net.Listener
+	udp      []net.PacketConn
+	done     chan struct{}
+	wg       sync.WaitGroup
+	once     sync.Once
+	rng      *rand.Rand
+	rngMu    sync.Mutex
+}
+
+// Start binds every service on host and begins serving. Ports chosen by the
+// kernel are written back into the returned server's services.
+func Start(host string, services []Service) (*Server, error) {
+	if len(services) == 0 {
+		return nil, errors.New("testserver: no services configured")
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+
+	s := &Server{
+		Host: host,
+		done: make(chan struct{}),
+		rng:  rand.New(rand.NewSource(time.Now().UnixNano())), // #nosec G404 - simulation only
+	}
+	for _, svc := range services {
+		if svc.DropRate < 0 || svc.DropRate > 1 {
+			s.Close()
+			return nil, fmt.Errorf("testserver: drop rate must be between 0 and 1, got %v", svc.DropRate)
+		}
+		addr := net.JoinHostPort(host, strconv.Itoa(int(svc.Port)))
+		switch svc.Protocol {
+		case "tcp", "":
+			ln, err := net.Listen("tcp", addr)
+			if err != nil {
+				s.Close()
+				return nil, fmt.Errorf("testserver: listen tcp %s: %w", addr, err)
+			}
+			svc.Protocol = "tcp"
+			svc.Port = uint16(ln.Addr().(*net.TCPAddr).Port)
+			s.tcp = append(s.tcp, ln)
+			s.wg.Add(1)
+			go s.serveTCP(ln, svc)
+		case "udp":
+			pc, err := net.ListenPacket("udp", addr)
+			if err != nil {
+				s.Close()
+				return nil, fmt.Errorf("testserver: listen udp %s: %w", addr, err)
+			}
+			svc.Port = uint16(pc.LocalAddr().(*net.UDPAddr).Port)
+			s.udp = append(s.udp, pc)
+			s.wg.Add(1)
+			go s.serveUDP(pc, svc)
+		default:
+			s.Close()
+			return nil, fmt.Errorf("testserver: unsupported protocol %q", svc.Protocol)
+		}
+		s.services = append(s.services, svc)
+	}
+	return s, nil
+}
+
+// Services returns the running services with their bound ports.
+func (s *Server) Services() []Service {
+	out := make([]Service, len(s.services))
+	copy(out, s.services)
+	return out
+}
+
+// Ports returns the bound ports for protocol.
+func (s *Server) Ports(protocol string) []uint16 {
+	var ports []uint16
+	for _, svc := range s.services {
+		if svc.Protocol == protocol {
+			ports = append(ports, svc.Port)
+		}
+	}
+	return ports
+}
+
+// Close stops all listeners and waits for their goroutines to exit.
+func (s *Server) Close() {
+	s.once.Do(func() {
+		close(s.done)
+		for _, ln := range s.tcp {
+			_ = ln.Close()
+		}
+		for _, pc := range s.udp {
+			_ = pc.Close()
+		}
+	})
+	s.wg.Wait()
+}
+
+func (s *Server) serveTCP(ln net.Listener, svc Service) {
+	defer s.wg.Done()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handleTCP(conn, svc)
+		}()
+	}
+}
+
+func (s *Server) handleTCP(conn net.Conn, svc Service) {
+	defer conn.Close()
+	if svc.Reset || s.drop(svc.DropRate) {
+		if tcp, ok := conn.(*net.TCPConn); ok {
+			_ = tcp.SetLinger(0) // send RST instead of FIN
+		}
+		return
+	}
+	if !s.wait(svc.Delay) {
+		return
+	}
+	if svc.Banner != "" {
+		_, _ = conn.Write([]byte(svc.Banner))
+	}
+}
+
+func (s *Server) serveUDP(pc net.PacketConn, svc Service) {
+	defer s.wg.Done()
+	buf := make([]byte, 2048)
+	for {
+		_, addr, err := pc.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if svc.Reset || svc.Banner == "" {
+			continue
+		}
+		if !s.wait(svc.Delay) {
+			return
+		}
+		_, _ = pc.WriteTo([]byte(svc.Banner), addr)
+	}
+}
+
+// drop reports whether a connection should be reset, with probability rate.
+func (s *Server) drop(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	s.rngMu.Lock()
+	defer s.rngMu.Unlock()
+	return s.rng.Float64() < rate
+}
+
+// wait sleeps for d and reports false if the server closed meanwhile.
+func (s *Server) wait(d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-s.done:
+		return false
+	}
+}
@@ -0,0 +1,153 @@
+package testserver
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func dial(t *testing.T, s *Server, proto string, port uint16) net.Conn {
+	t.Helper()
+	conn, err := net.DialTimeout(proto, net.JoinHostPort(s.Host, strconv.Itoa(int(port))), time.Second)
+	if err != nil {
+		t.Fatalf("dial %s/%d: %v", proto, port, err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+	return conn
+}
+
+func TestServer_TCPBannerAndDelay(t *testing.T) {
+	s, err := Start("", []Service{{Protocol: "tcp", Banner: "HELLO\r\n", Delay: 50 * time.Millisecond}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer s.Close()
+
+	conn := dial(t, s, "tcp", s.Ports("tcp")[0])
+	defer conn.Close()
+
+	start := time.Now()
+	buf := make([]byte, 32)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if got := string(buf[:n]); got != "HELLO\r\n" {
+		t.Errorf("banner = %q, want HELLO", got)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("banner arrived after %v, want the configured delay", elapsed)
+	}
+}
+
+func TestServer_TCPReset(t *testing.T) {
+	s, err := Start("", []Service{{Protocol: "tcp", Banner: "unused", Reset: true}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer s.Close()
+
+	// The reset can land during the handshake or on the first read.
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(int(s.Ports("tcp")[0])))
+	conn, err := net.DialTimeout("tcp", addr, time.Second)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	n, err := conn.Read(make([]byte, 16))
+	if n != 0 || err == nil {
+		t.Errorf("read = %d, %v; want connection closed without data", n, err)
+	}
+}
+
+func TestServer_TCPDropRate(t *testing.T) {
+	s, err := Start("", []Service{{Protocol: "tcp", Banner: "hi", DropRate: 1}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer s.Close()
+
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(int(s.Ports("tcp")[0])))
+	conn, err := net.DialTimeout("tcp", addr, time.Second)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	if n, _ := conn.Read(make([]byte, 16)); n != 0 {
+		t.Errorf("read %d bytes; a drop rate of 1 should reset every connection", n)
+	}
+}
+
+func TestServer_UDPReply(t *testing.T) {
+	s, err := Start("", []Service{{Protocol: "udp", Banner: "pong"}, {Protocol: "udp", Reset: true}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer s.Close()
+
+	ports := s.Ports("udp")
+	if len(ports) != 2 {
+		t.Fatalf("udp ports = %v, want 2", ports)
+	}
+
+	conn := dial(t, s, "udp", ports[0])
+	defer conn.Close()
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	buf := make([]byte, 16)
+	n, err := conn.Read(buf)
+	if err != nil || string(buf[:n]) != "pong" {
+		t.Errorf("reply = %q, %v; want pong", buf[:n], err)
+	}
+
+	silent := dial(t, s, "udp", ports[1])
+	defer silent.Close()
+	_ = silent.SetDeadline(time.Now().Add(100 * time.Millisecond))
+	_, _ = silent.Write([]byte("ping"))
+	if n, err := silent.Read(buf); err == nil {
+		t.Errorf("silent service replied %q", buf[:n])
+	}
+}
+
+func TestServer_CloseStopsListeners(t *testing.T) {
+	s, err := Start("", []Service{{Protocol: "tcp", Delay: time.Hour, Banner: "late"}})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	port := s.Ports("tcp")[0]
+	conn := dial(t, s, "tcp", port)
+	defer conn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		s.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close blocked on a delayed connection")
+	}
+
+	if _, err := net.DialTimeout("tcp", net.JoinHostPort(s.Host, strconv.Itoa(int(port))), 200*time.Millisecond); err == nil {
+		t.Error("listener still accepting after Close")
+	}
+}
+
+func TestStart_Errors(t *testing.T) {
+	if _, err := Start("", nil); err == nil {
+		t.Error("Start with no services should fail")
+	}
+	if _, err := Start("", []Service{{Protocol: "sctp"}}); err == nil {
+		t.Error("Start with an unknown protocol should fail")
+	}
+	if _, err := Start("", []Service{{Protocol: "tcp", DropRate: 1.5}}); err == nil {
+		t.Error("Start with a drop rate above 1 should fail")
+	}
+}
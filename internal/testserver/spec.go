@@ -0,0 +1,86 @@
+package testserver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseService parses a service spec of the form
+//
+//	PROTO/PORT[,banner=TEXT][,delay=DURATION][,reset][,drop=RATE]
+//
+// for example "tcp/2222,banner=SSH-2.0-mock\r\n,delay=50ms". Banners accept
+// Go escape sequences such as \r\n and \x00.
+func ParseService(spec string) (Service, error) {
+	parts := strings.Split(spec, ",")
+	proto, portStr, ok := strings.Cut(parts[0], "/")
+	if !ok {
+		return Service{}, fmt.Errorf("invalid service %q: want PROTO/PORT", spec)
+	}
+
+	svc := Service{Protocol: strings.ToLower(strings.TrimSpace(proto))}
+	if svc.Protocol != "tcp" && svc.Protocol != "udp" {
+		return Service{}, fmt.Errorf("invalid service %q: protocol must be tcp or udp", spec)
+	}
+	port, err := strconv.ParseUint(strings.TrimSpace(portStr), 10, 16)
+	if err != nil {
+		return Service{}, fmt.Errorf("invalid service %q: bad port %q", spec, portStr)
+	}
+	svc.Port = uint16(port)
+
+	for _, opt := range parts[1:] {
+		key, value, _ := strings.Cut(opt, "=")
+		switch strings.TrimSpace(key) {
+		case "banner":
+			svc.Banner = unescape(value)
+		case "delay":
+			d, err := time.ParseDuration(value)
+			if err != nil || d < 0 {
+				return Service{}, fmt.Errorf("invalid service %q: bad delay %q", spec, value)
+			}
+			svc.Delay = d
+		case "reset":
+			svc.Reset = true
+		case "drop":
+			rate, err := strconv.ParseFloat(value, 64)
+			if err != nil || rate < 0 || rate > 1 {
+				return Service{}, fmt.Errorf("invalid service %q: drop must be between 0 and 1, got %q", spec, value)
+			}
+			svc.DropRate = rate
+		default:
+			return Service{}, fmt.Errorf("invalid service %q: unknown option %q", spec, key)
+		}
+	}
+	return svc, nil
+}
+
+// unescape interprets Go escape sequences, returning s unchanged if it
+// does not form a valid quoted string.
+func unescape(s string) string {
+	if out, err := strconv.Unquote(`"` + strings.ReplaceAll(s, `"`, `\"`) + `"`); err == nil {
+		return out
+	}
+	return s
+}
+
+// String formats svc back into spec form.
+func (svc Service) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s/%d", svc.Protocol, svc.Port)
+	if svc.Banner != "" {
+		b.WriteString(",banner=")
+		b.WriteString(strings.Trim(strconv.Quote(svc.Banner), `"`))
+	}
+	if svc.Delay > 0 {
+		fmt.Fprintf(&b, ",delay=%s", svc.Delay)
+	}
+	if svc.Reset {
+		b.WriteString(",reset")
+	}
+	if svc.DropRate > 0 {
+		fmt.Fprintf(&b, ",drop=%g", svc.DropRate)
+	}
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package testserver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseService(t *testing.T) {
+	tests := []struct {
+		spec string
+		want Service
+	}{
+		{"tcp/8080", Service{Protocol: "tcp", Port: 8080}},
+		{"UDP/53,banner=pong", Service{Protocol: "udp", Port: 53, Banner: "pong"}},
+		{`tcp/22,banner=SSH-2.0-mock\r\n,delay=50ms`, Service{Protocol: "tcp", Port: 22, Banner: "SSH-2.0-mock\r\n", Delay: 50 * time.Millisecond}},
+		{"tcp/0,reset", Service{Protocol: "tcp", Reset: true}},
+		{"tcp/80,drop=0.25", Service{Protocol: "tcp", Port: 80, DropRate: 0.25}},
+	}
+	for _, tt := range tests {
+		got, err := ParseService(tt.spec)
+		if err != nil {
+			t.Errorf("ParseService(%q) error: %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseService(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+		if again, err := ParseService(got.String()); err != nil || again != got {
+			t.Errorf("round trip of %q = %+v, %v", got.String(), again, err)
+		}
+	}
+}
+
+func TestParseService_Invalid(t *testing.T) {
+	for _, spec := range []string{"8080", "sctp/1", "tcp/70000", "tcp/80,delay=soon", "tcp/80,color=red", "tcp/80,drop=2"} {
+		if _, err := ParseService(spec); err == nil {
+			t.Errorf("ParseService(%q) succeeded, want error", spec)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+)
+
+// QuitChoice identifies an entry in the quit-during-scan prompt.
+type QuitChoice int
+
+const (
+	QuitCancelKeep QuitChoice = iota
+	QuitCancelExport
+	QuitKeepScanning
+	QuitNow
+)
+
+// quitChoiceLabels are the catalog keys of the quit choices, in order.
+var quitChoiceLabels = []string{
+	"ui.quit.cancel_keep",
+	"ui.quit.cancel_export",
+	"ui.quit.keep_scanning",
+	"ui.quit.now",
+}
+
+// SetCancelFunc attaches the scan's cancel function so quitting mid-scan can
+// stop the scanner while keeping the TUI open.
+func (m *ScanUI) SetCancelFunc(cancel context.CancelFunc) {
+	m.cancelScan = cancel
+}
+
+// requestQuit quits immediately when the scan has finished or cannot be
+// cancelled, and otherwise asks how to handle the running scan.
+func (m *ScanUI) requestQuit() tea.Cmd {
+	if !m.scanning || m.cancelScan == nil {
+		return tea.Quit
+	}
+	m.openModal(ModalQuit)
+	return nil
+}
+
+// handleQuitModalKey navigates and applies the quit prompt.
+func (m *ScanUI) handleQuitModalKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		m.modalState.Cursor = max(0, m.modalState.Cursor-1)
+		return true, true, nil
+	case "down", "j":
+		m.modalState.Cursor = min(len(quitChoiceLabels)-1, m.modalState.Cursor+1)
+		return true, true, nil
+	case "q", "ctrl+c":
+		return true, true, tea.Quit
+	case "enter":
+		return true, true, m.applyQuitChoice(QuitChoice(m.modalState.Cursor))
+	default:
+		return true, true, nil
+	}
+}
+
+// applyQuitChoice cancels the scan as requested. The scanner closes its
+// result stream once cancelled, which marks the scan complete.
+func (m *ScanUI) applyQuitChoice(choice QuitChoice) tea.Cmd {
+	m.modalState.IsActive = false
+	m.modalState.Cursor = 0
+
+	switch choice {
+	case QuitCancelKeep:
+		m.stopScan()
+		return m.showToast(i18n.T("ui.quit.cancelled", m.results.Len()), false)
+	case QuitCancelExport:
+		m.stopScan()
+		return m.openExportModal()
+	case QuitNow:
+		return tea.Quit
+	default:
+		return nil
+	}
+}
+
+// stopScan cancels the scanner and releases a pause so workers can exit.
+func (m *ScanUI) stopScan() {
+	if m.isPaused {
+		m.togglePause()
+	}
+	if m.cancelScan != nil {
+		m.cancelScan()
+	}
+}
+
+// renderQuitModal renders the quit-during-scan prompt.
+func (m *ScanUI) renderQuitModal() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Warning).
+		Width(30).
+		Render(i18n.T("ui.quit.title"))
+	b.WriteString(title + "\n\n")
+
+	m.modalState.OptionsTop = strings.Count(b.String(), "\n")
+	for i, label := range quitChoiceLabels {
+		style := lipgloss.NewStyle()
+		if i == m.modalState.Cursor {
+			style = style.Background(m.theme.Primary).Foreground(m.theme.Background)
+		}
+		b.WriteString(style.Render(fmt.Sprintf("%d. %s", i+1, i18n.T(label))) + "\n")
+	}
+
+	count := lipgloss.NewStyle().
+		Foreground(m.theme.Secondary).
+		Render(i18n.T("ui.quit.collected", m.results.Len()))
+	b.WriteString("\n" + count + "\n")
+
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Render(i18n.T("ui.quit.keys"))
+	b.WriteString("\n" + instructions)
+
+	return b.String()
+}
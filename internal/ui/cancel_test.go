@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+)
+
+var quitKey = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}}
+
+func isQuit(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	_, ok := cmd().(tea.QuitMsg)
+	return ok
+}
+
+func TestScanUI_QuitDuringScanPrompts(t *testing.T) {
+	ui := newSearchTestUI(t)
+	cancelled := false
+	ui.SetCancelFunc(func() { cancelled = true })
+
+	_, _, cmd := ui.handleKeyMsg(quitKey)
+	if isQuit(cmd) {
+		t.Fatal("q during a cancellable scan should prompt instead of quitting")
+	}
+	if !ui.modalState.IsActive || ui.modalState.Type != ModalQuit {
+		t.Fatal("expected quit prompt modal")
+	}
+
+	// Esc keeps scanning
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEsc})
+	if ui.modalState.IsActive || cancelled {
+		t.Error("esc should dismiss the prompt and keep scanning")
+	}
+}
+
+func TestScanUI_QuitChoiceCancelKeep(t *testing.T) {
+	ui := newSearchTestUI(t)
+	cancelled := false
+	ui.SetCancelFunc(func() { cancelled = true })
+	ui.togglePause()
+
+	ui.handleKeyMsg(quitKey)
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if !cancelled {
+		t.Error("cancel and keep should cancel the scan")
+	}
+	if ui.isPaused {
+		t.Error("cancelling should release a paused scan")
+	}
+	if ui.modalState.IsActive || ui.toast == "" {
+		t.Error("cancel and keep should close the prompt and confirm with a toast")
+	}
+}
+
+func TestScanUI_QuitChoiceCancelExport(t *testing.T) {
+	ui := newSearchTestUI(t)
+	cancelled := false
+	ui.SetCancelFunc(func() { cancelled = true })
+
+	ui.handleKeyMsg(quitKey)
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyDown})
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if !cancelled {
+		t.Error("cancel and export should cancel the scan")
+	}
+	if !ui.modalState.IsActive || ui.modalState.Type != ModalExport {
+		t.Error("cancel and export should open the export modal")
+	}
+}
+
+func TestScanUI_QuitImmediatelyWhenIdle(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.SetCancelFunc(func() {})
+	ui.scanning = false
+
+	if _, _, cmd := ui.handleKeyMsg(quitKey); !isQuit(cmd) {
+		t.Error("q after the scan completes should quit")
+	}
+
+	ui.scanning = true
+	if _, _, cmd := ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyCtrlC}); !isQuit(cmd) {
+		t.Error("ctrl+c should always quit")
+	}
+}
+
+// TestModalLabels_Translated keeps the catalog keys behind the quit and
+// bulk menus in the message catalog.
+func TestModalLabels_Translated(t *testing.T) {
+	i18n.SetLanguage("es")
+	defer i18n.SetLanguage(i18n.Default)
+
+	for _, key := range append(append([]string(nil), quitChoiceLabels...), bulkActionLabels...) {
+		if i18n.T(key) == key {
+			t.Errorf("%s is not in the message catalog", key)
+		}
+	}
+	ui := newSearchTestUI(t)
+	ui.SetCancelFunc(func() {})
+	ui.handleKeyMsg(quitKey)
+	if view := ui.renderQuitModal(); !strings.Contains(view, "Seguir escaneando") {
+		t.Errorf("quit prompt not translated:\n%s", view)
+	}
+}
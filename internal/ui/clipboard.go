@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+)
+
+// clipboardWriter copies text to the system clipboard. It is a variable so
+// tests can capture clipboard writes instead of emitting escape sequences.
+var clipboardWriter = writeOSC52
+
+// writeOSC52 sets the terminal clipboard with an OSC52 escape sequence, which
+// also works over SSH. The sequence goes to stderr so it does not interleave
+// with the TUI's frame output on stdout.
+func writeOSC52(text string) error {
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		seq = seq.Screen()
+	}
+	_, err := seq.WriteTo(os.Stderr)
+	return err
+}
+
+// selectedResult returns the result under the table cursor, or false when
+// the cursor is on a service group's header.
+func (m *ScanUI) selectedResult() (core.ResultEvent, bool) {
+	return m.resultAt(m.table.Cursor())
+}
+
+// copySelected copies host:port of the selected row, or the full banner when
+// bannerOnly is set, and confirms with a toast.
+func (m *ScanUI) copySelected(bannerOnly bool) tea.Cmd {
+	result, ok := m.selectedResult()
+	if !ok {
+		return m.showToast(i18n.T("ui.copy.nothing"), true)
+	}
+
+	text := net.JoinHostPort(result.Host, strconv.Itoa(int(result.Port)))
+	label := text
+	if bannerOnly {
+		if result.Banner == "" {
+			return m.showToast(i18n.T("ui.copy.no_banner"), true)
+		}
+		text = result.Banner
+		label = i18n.T("ui.copy.banner_for", label)
+	}
+
+	if err := clipboardWriter(text); err != nil {
+		return m.showToast(i18n.T("ui.copy.failed", err), true)
+	}
+	return m.showToast(i18n.T("ui.copy.copied", label), false)
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+func captureClipboard(t *testing.T) *[]string {
+	t.Helper()
+	var copied []string
+	original := clipboardWriter
+	clipboardWriter = func(text string) error {
+		copied = append(copied, text)
+		return nil
+	}
+	t.Cleanup(func() { clipboardWriter = original })
+	return &copied
+}
+
+func TestScanUI_CopySelectedHostPort(t *testing.T) {
+	copied := captureClipboard(t)
+	ui := newSearchTestUI(t)
+	ui.table.SetCursor(1)
+
+	handled, skip, cmd := ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if !handled || !skip || cmd == nil {
+		t.Fatal("'y' should be handled and schedule the toast expiry")
+	}
+	if len(*copied) != 1 || (*copied)[0] != "10.0.0.2:80" {
+		t.Fatalf("expected host:port to be copied, got %v", *copied)
+	}
+	if !strings.Contains(ui.renderFooter(), "Copied 10.0.0.2:80") {
+		t.Errorf("footer should confirm the copy, got %q", ui.renderFooter())
+	}
+}
+
+func TestScanUI_CopySelectedIPv6HostPort(t *testing.T) {
+	copied := captureClipboard(t)
+	ui := NewScanUI(&config.Config{}, 1, make(chan core.Event), false)
+	ui.recordResults([]core.ResultEvent{{Host: "::1", Port: 22, State: core.StateOpen}})
+
+	ui.copySelected(false)
+	if len(*copied) != 1 || (*copied)[0] != "[::1]:22" {
+		t.Fatalf("expected a bracketed IPv6 host:port, got %v", *copied)
+	}
+}
+
+func TestScanUI_CopyBannerFromDetailsModal(t *testing.T) {
+	copied := captureClipboard(t)
+	ui := newSearchTestUI(t)
+	ui.table.SetCursor(0)
+	ui.openModal(ModalDetails)
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if len(*copied) != 1 || (*copied)[0] != "SSH-2.0-OpenSSH_8.9" {
+		t.Fatalf("expected full banner to be copied, got %v", *copied)
+	}
+
+	// Rows without a banner report an error instead of copying.
+	ui.table.SetCursor(2)
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if len(*copied) != 1 || !ui.toastIsError {
+		t.Error("copying an empty banner should show an error toast")
+	}
+}
+
+func TestScanUI_CopyReportsFailure(t *testing.T) {
+	original := clipboardWriter
+	clipboardWriter = func(string) error { return errors.New("no terminal") }
+	t.Cleanup(func() { clipboardWriter = original })
+
+	ui := newSearchTestUI(t)
+	ui.copySelected(false)
+	if !ui.toastIsError || !strings.Contains(ui.toast, "no terminal") {
+		t.Errorf("expected error toast, got %q", ui.toast)
+	}
+}
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+)
+
+// columnsSettingKey is the config key the table layout is saved under.
+const columnsSettingKey = "ui.columns"
+
+// SettingsSaver writes a setting, such as "ui.columns", to the config file
+// so a preference changed in the TUI outlives the session. It is supplied
+// by the command layer.
+type SettingsSaver func(key string, value interface{}) error
+
+// SetSettingsSaver lets the column chooser, the theme picker and the filter
+// presets save their choices.
+func (m *ScanUI) SetSettingsSaver(fn SettingsSaver) {
+	m.saveSetting = fn
+}
+
+// openColumnsModal opens the column chooser on the first column.
+func (m *ScanUI) openColumnsModal() tea.Cmd {
+	m.openModal(ModalColumns)
+	return nil
+}
+
+// handleColumnsModalKey moves through the columns, shows or hides the one
+// under the cursor and moves it left or right in the table. Every change
+// is applied and saved at once, so Enter and Esc only close the chooser.
+func (m *ScanUI) handleColumnsModalKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
+	last := len(m.layout.order) - 1
+	switch msg.String() {
+	case "up", "k":
+		m.modalState.Cursor = max(0, m.modalState.Cursor-1)
+	case "down", "j":
+		m.modalState.Cursor = min(last, m.modalState.Cursor+1)
+	case " ", "x":
+		return true, true, m.toggleColumn(m.modalState.Cursor)
+	case "shift+up", "K":
+		return true, true, m.moveColumn(m.modalState.Cursor, -1)
+	case "shift+down", "J":
+		return true, true, m.moveColumn(m.modalState.Cursor, 1)
+	case "enter":
+		m.closeModal()
+	}
+	return true, true, nil
+}
+
+// toggleColumn shows or hides the column at position i of the layout. The
+// last visible column cannot be hidden.
+func (m *ScanUI) toggleColumn(i int) tea.Cmd {
+	if i < 0 || i >= len(m.layout.order) {
+		return nil
+	}
+	id := m.layout.order[i]
+	if m.layout.shown(id, m.showGeo) && len(m.layout.names(m.showGeo)) == 1 {
+		return m.showToast(i18n.T("ui.column_chooser.last"), true)
+	}
+	if id == geoColumn {
+		m.showGeo = !m.showGeo
+	} else {
+		m.layout.hidden[id] = !m.layout.hidden[id]
+	}
+	return m.columnsChanged()
+}
+
+// moveColumn swaps the column at position i with its neighbour delta
+// positions away, keeping the cursor on the moved column.
+func (m *ScanUI) moveColumn(i, delta int) tea.Cmd {
+	j := i + delta
+	if i < 0 || j < 0 || i >= len(m.layout.order) || j >= len(m.layout.order) {
+		return nil
+	}
+	m.layout.order[i], m.layout.order[j] = m.layout.order[j], m.layout.order[i]
+	m.modalState.Cursor = j
+	return m.columnsChanged()
+}
+
+// columnsChanged redraws the table in the new layout and saves it.
+func (m *ScanUI) columnsChanged() tea.Cmd {
+	m.updateTable()
+	if m.saveSetting == nil {
+		return nil
+	}
+	if err := m.saveSetting(columnsSettingKey, m.layout.names(m.showGeo)); err != nil {
+		return m.showToast(i18n.T("ui.column_chooser.save_failed", err), true)
+	}
+	return nil
+}
+
+// renderColumnsModal renders the column chooser: every column in table
+// order with a mark for those shown.
+func (m *ScanUI) renderColumnsModal() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Primary).
+		Width(30).
+		Render(i18n.T("ui.column_chooser.title"))
+	b.WriteString(title + "\n\n")
+
+	m.modalState.OptionsTop = strings.Count(b.String(), "\n")
+	for i, id := range m.layout.order {
+		mark := "[ ]"
+		style := lipgloss.NewStyle().Foreground(m.theme.Muted)
+		if m.layout.shown(id, m.showGeo) {
+			mark = "[x]"
+			style = lipgloss.NewStyle()
+		}
+		if i == m.modalState.Cursor {
+			style = style.Background(m.theme.Primary).Foreground(m.theme.Background)
+		}
+		b.WriteString(style.Render(mark+" "+i18n.T(defaultColumnSpecs[id].titleKey)) + "\n")
+	}
+
+	if m.saveSetting == nil {
+		note := lipgloss.NewStyle().
+			Foreground(m.theme.Secondary).
+			Render(i18n.T("ui.column_chooser.not_saved"))
+		b.WriteString("\n" + note + "\n")
+	}
+
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Render(i18n.T("ui.column_chooser.keys"))
+	b.WriteString("\n" + instructions)
+
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+func TestNewTableLayout(t *testing.T) {
+	layout, showGeo := newTableLayout(nil)
+	if showGeo || !layout.hidden[rdnsColumn] || layout.order[geoColumn] != geoColumn {
+		t.Fatalf("default layout = %+v, showGeo %v", layout, showGeo)
+	}
+
+	layout, showGeo = newTableLayout([]string{"port", "host", "rdns", "geo", "bogus", "port"})
+	if !showGeo {
+		t.Error("naming geo should show the location column")
+	}
+	want := []string{"port", "host", "rdns", "geo"}
+	if got := layout.names(showGeo); !reflect.DeepEqual(got, want) {
+		t.Errorf("names = %v; want %v", got, want)
+	}
+	if len(layout.order) != len(defaultColumnSpecs) || !layout.hidden[bannerColumn] {
+		t.Errorf("unlisted columns should follow hidden: %+v", layout)
+	}
+}
+
+func TestColumnWidthsFollowLayout(t *testing.T) {
+	layout, showGeo := newTableLayout([]string{"state", "host"})
+	columns := calculateColumnWidths(80, layout, showGeo)
+
+	if columns[0].Title != "State" || columns[1].Title != "Host" {
+		t.Fatalf("first columns = %q, %q", columns[0].Title, columns[1].Title)
+	}
+	for _, col := range columns[2:] {
+		if col.Width != 0 {
+			t.Errorf("hidden column %q has width %d", col.Title, col.Width)
+		}
+	}
+	total := 0
+	for _, col := range columns[:2] {
+		total += col.Width + tableCellPadding
+	}
+	if total != 80 {
+		t.Errorf("visible columns fill %d of 80", total)
+	}
+}
+
+func TestColumnsModalTogglesMovesAndSaves(t *testing.T) {
+	ui := NewScanUI(&config.Config{}, 1, make(chan core.Event), false)
+	ui.handleWindowSize(tea.WindowSizeMsg{Width: 120, Height: 30})
+	ui.recordResults([]core.ResultEvent{{Host: "10.0.0.1", Hostname: "gw.example.net", Port: 22, State: core.StateOpen, Banner: "SSH-2.0-OpenSSH"}})
+
+	var saved []string
+	ui.SetSettingsSaver(func(key string, value interface{}) error {
+		if key != "ui.columns" {
+			t.Errorf("saved key %q", key)
+		}
+		saved = value.([]string)
+		return nil
+	})
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+	if !ui.modalState.IsActive || ui.modalState.Type != ModalColumns {
+		t.Fatal("C should open the column chooser")
+	}
+	press := func(keys ...string) {
+		for _, k := range keys {
+			msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			switch k {
+			case "down":
+				msg = tea.KeyMsg{Type: tea.KeyDown}
+			case "space":
+				msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+			}
+			ui.handleKeyMsg(msg)
+		}
+	}
+
+	// Hide the banner, show the reverse DNS name and move it before the port.
+	press("down", "down", "down", "down", "down", "space")
+	press("down", "down", "down", "space")
+	for range 7 {
+		press("K")
+	}
+	want := []string{"host", "rdns", "port", "protocol", "state", "service", "latency"}
+	if !reflect.DeepEqual(saved, want) {
+		t.Fatalf("saved columns = %v; want %v", saved, want)
+	}
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	view := ui.View()
+	if !strings.Contains(view, "gw.example.net") {
+		t.Error("the reverse DNS column should show the hostname")
+	}
+	if strings.Contains(view, "SSH-2.0-OpenSSH") {
+		t.Error("the hidden banner column is still drawn")
+	}
+}
+
+func TestColumnsKeepOneVisible(t *testing.T) {
+	ui := NewScanUI(&config.Config{UI: config.UIConfig{Columns: []string{"host"}}}, 1, make(chan core.Event), false)
+	ui.openColumnsModal()
+
+	cmd := ui.toggleColumn(0)
+	if cmd == nil || !ui.layout.shown(hostColumn, ui.showGeo) {
+		t.Error("hiding the only visible column should be refused with a toast")
+	}
+}
+
+func TestColumnsSaveFailureShowsToast(t *testing.T) {
+	ui := NewScanUI(&config.Config{}, 1, make(chan core.Event), false)
+	ui.SetSettingsSaver(func(string, interface{}) error { return errors.New("read-only") })
+	ui.openColumnsModal()
+
+	ui.toggleColumn(int(bannerColumn))
+	if !ui.toastIsError || !strings.Contains(ui.toast, "read-only") {
+		t.Errorf("toast = %q, error %v", ui.toast, ui.toastIsError)
+	}
+	if !ui.layout.hidden[bannerColumn] {
+		t.Error("the change should apply even when it cannot be saved")
+	}
+}
@@ -27,6 +27,11 @@ type Command struct {
 	Action func() tea.Cmd
 	// UIAction is a function that executes a command with direct access to the UI model
 	UIAction func(model interface{}) tea.Cmd
+	// ArgPrompt labels the argument a command asks for before it runs, such
+	// as a path or a rate; commands with one are run through UIArgAction
+	ArgPrompt string
+	// UIArgAction executes a command with the argument entered for it
+	UIArgAction func(model interface{}, arg string) tea.Cmd
 	// IsActive returns whether the command should be available in the current context
 	IsActive func(model interface{}) bool
 }
@@ -95,6 +100,17 @@ func (r *Registry) ExecuteCommand(id string, model interface{}) tea.Cmd {
 	return nil
 }
 
+// ExecuteCommandWithArg finds a command that takes an argument by ID and
+// executes it with arg if available
+func (r *Registry) ExecuteCommandWithArg(id string, model interface{}, arg string) tea.Cmd {
+	for _, cmd := range r.commands {
+		if cmd.ID == id && cmd.UIArgAction != nil && (cmd.IsActive == nil || cmd.IsActive(model)) {
+			return cmd.UIArgAction(model, arg)
+		}
+	}
+	return nil
+}
+
 // GetActiveCommands returns all commands that are currently active in the given model context
 func (r *Registry) GetActiveCommands(model interface{}) []Command {
 	var active []Command
@@ -139,3 +139,26 @@ func TestRegistry_GetActiveCommands(t *testing.T) {
 		t.Errorf("Expected active command ID 'active', got '%s'", active[0].ID)
 	}
 }
+
+func TestRegistry_ExecuteCommandWithArg(t *testing.T) {
+	reg := NewRegistry()
+	var got string
+	reg.AddCommand(Command{
+		ID:        "with-arg",
+		ArgPrompt: "Value",
+		UIArgAction: func(model interface{}, arg string) tea.Cmd {
+			got = arg
+			return nil
+		},
+	})
+	reg.AddCommand(Command{ID: "no-arg", UIAction: func(model interface{}) tea.Cmd {
+		t.Error("a command without UIArgAction should not run with an argument")
+		return nil
+	}})
+
+	reg.ExecuteCommandWithArg("with-arg", nil, "42")
+	if got != "42" {
+		t.Errorf("argument = %q; want 42", got)
+	}
+	reg.ExecuteCommandWithArg("no-arg", nil, "x")
+}
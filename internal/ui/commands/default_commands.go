@@ -108,6 +108,18 @@ func DefaultCommands() []Command {
 			},
 			IsActive: nil,
 		},
+		{
+			ID:          "action-filter",
+			Name:        "Filter Results",
+			Description: "Filter by host, port range, service, state and latency",
+			Alias:       "filters narrow",
+			Keys:        []string{"f"},
+			Category:    CommandTypeAction,
+			Action: func() tea.Cmd {
+				return nil // Will be handled through UIAction
+			},
+			IsActive: nil,
+		},
 		{
 			ID:          "action-filter",
 			Name:        "Open Filter Modal",
@@ -187,8 +199,129 @@ func DefaultCommands() []Command {
 				return true // The UI handles this internally
 			},
 		},
+		{
+			ID:          "action-host-summary",
+			Name:        "Host Summary",
+			Description: "Summarize every scanned port of the selected host",
+			Alias:       "host overview",
+			Keys:        []string{"h"},
+			Category:    CommandTypeAction,
+			Action: func() tea.Cmd {
+				return nil // Will be handled through UIAction
+			},
+			IsActive: nil,
+		},
+
+		// Commands that ask for an argument
+		{
+			ID:          "export-csv",
+			Name:        "Export as CSV to…",
+			Description: "Export the current view as CSV to a file",
+			Alias:       "csv",
+			Category:    CommandTypeAction,
+			ArgPrompt:   "Path",
+			Action: func() tea.Cmd {
+				return nil // Will be handled through UIArgAction
+			},
+			IsActive: nil,
+		},
+		{
+			ID:          "snapshot-save",
+			Name:        "Save Dashboard Snapshot to…",
+			Description: "Draw the dashboard statistics to an SVG or PNG image",
+			Alias:       "svg png image screenshot",
+			Category:    CommandTypeAction,
+			ArgPrompt:   "Path (.svg or .png)",
+			Action: func() tea.Cmd {
+				return nil // Will be handled through UIArgAction
+			},
+			IsActive: nil,
+		},
+		{
+			ID:          "theme-switch",
+			Name:        "Switch Theme to…",
+			Description: "Change the color theme",
+			Alias:       "theme",
+			Category:    CommandTypeView,
+			ArgPrompt:   "Theme",
+			Action: func() tea.Cmd {
+				return nil // Will be handled through UIArgAction
+			},
+			IsActive: nil,
+		},
+		{
+			ID:          "rate-set",
+			Name:        "Set Rate to…",
+			Description: "Set the scan rate in packets per second",
+			Alias:       "rate pps",
+			Category:    CommandTypeAction,
+			ArgPrompt:   "Packets per second",
+			Action: func() tea.Cmd {
+				return nil // Will be handled through UIArgAction
+			},
+			IsActive: nil,
+		},
+		{
+			ID:          "filter-host",
+			Name:        "Filter Host…",
+			Description: "Show only hosts containing the given text or matching a re: pattern",
+			Alias:       "host",
+			Category:    CommandTypeFilter,
+			ArgPrompt:   "Host contains (or re:pattern)",
+			Action: func() tea.Cmd {
+				return nil // Will be handled through UIArgAction
+			},
+			IsActive: nil,
+		},
+		{
+			ID:          "preset-save",
+			Name:        "Save View as Preset…",
+			Description: "Save the current filters and sort under a name",
+			Alias:       "preset save view",
+			Category:    CommandTypeFilter,
+			ArgPrompt:   "Preset name",
+			Action: func() tea.Cmd {
+				return nil // Will be handled through UIArgAction
+			},
+			IsActive: nil,
+		},
 
 		// View commands
+		{
+			ID:          "view-columns",
+			Name:        "Choose Columns",
+			Description: "Show, hide and reorder the results table columns",
+			Alias:       "columns",
+			Keys:        []string{"C"},
+			Category:    CommandTypeView,
+			Action: func() tea.Cmd {
+				return nil // Will be handled through UIAction
+			},
+			IsActive: nil,
+		},
+		{
+			ID:          "view-theme",
+			Name:        "Choose Theme",
+			Description: "Preview the color themes and keep one",
+			Alias:       "themes colors",
+			Keys:        []string{"T"},
+			Category:    CommandTypeView,
+			Action: func() tea.Cmd {
+				return nil // Will be handled through UIAction
+			},
+			IsActive: nil,
+		},
+		{
+			ID:          "view-group",
+			Name:        "Group by Service",
+			Description: "Collapse the table into one row per service, or back to every result",
+			Alias:       "group services collapse",
+			Category:    CommandTypeView,
+			Action: func() tea.Cmd {
+				return nil // Will be handled through UIAction
+			},
+			IsActive: nil,
+		},
 		{
 			ID:          "view-help",
 			Name:        "Toggle Help",
@@ -4,6 +4,10 @@ import (
 	"strings"
 )
 
+// maxFuzzyScore keeps scattered matches below substring matches, which
+// score 8 and above
+const maxFuzzyScore = 7.5
+
 // MatchResult represents the result of a fuzzy search match
 type MatchResult struct {
 	Command Command
@@ -113,7 +117,9 @@ func calculateFuzzyScore(cmd Command, query string) (float64, []int) {
 			finalScore += positionBonus
 		}
 
-		bestScore = finalScore
+		// Scattered matches never outrank a substring match, however
+		// long the query
+		bestScore = min(finalScore, maxFuzzyScore)
 		bestMatches = matches
 	}
 
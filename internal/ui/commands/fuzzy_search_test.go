@@ -133,3 +133,14 @@ func TestCalculateFuzzyScore_NoMatch(t *testing.T) {
 		t.Errorf("Expected 0 score for no match, got %f", score)
 	}
 }
+
+func TestCalculateFuzzyScore_SubstringBeatsLongFuzzyMatch(t *testing.T) {
+	prefix := Command{Name: "Set Rate to…", Description: "Set the scan rate"}
+	scattered := Command{Name: "Reset Filters", Description: "Reset all applied filters"}
+
+	want, _ := calculateFuzzyScore(prefix, "set rate")
+	got, _ := calculateFuzzyScore(scattered, "set rate")
+	if got <= 0 || got >= want {
+		t.Errorf("scattered match scored %f against the prefix match's %f", got, want)
+	}
+}
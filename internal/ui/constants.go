@@ -28,6 +28,16 @@ const (
 	ColumnWeightLatency  = 8
 )
 
+// The location and reverse DNS columns are optional and sized apart from
+// the weights above, which share the width left once they are placed.
+const (
+	ColumnWeightGeo   = 14
+	ColumnMinWidthGeo = 12
+
+	ColumnWeightRDNS   = 16
+	ColumnMinWidthRDNS = 14
+)
+
 // Table column minimum widths to keep data legible on narrow terminals.
 const (
 	ColumnMinWidthHost     = 16
@@ -54,10 +64,23 @@ const (
 	PageScrollLines = 10
 )
 
+// Mouse
+const (
+	// MouseWheelLines is the number of lines one wheel notch scrolls
+	MouseWheelLines = 3
+)
+
 // Event polling
 const (
 	// ResultPollTimeout is the timeout for polling result events
 	ResultPollTimeout = 100 * time.Millisecond
+
+	// ResultBatchWindow is how long results are collected before the
+	// table is rebuilt, keeping the UI responsive at high result rates
+	ResultBatchWindow = 50 * time.Millisecond
+
+	// ResultBatchMaxSize caps the results applied in a single update
+	ResultBatchMaxSize = 4096
 )
 
 // Dashboard and UI layout
@@ -66,7 +89,10 @@ const (
 	DashboardMinWidth = 120
 
 	// StatusBarLabelWidth is the width of status bar labels
-	StatusBarLabelWidth = 10
+	StatusBarLabelWidth = 12
+
+	// ErrorTickerSize is how many recent scan errors the dashboard lists
+	ErrorTickerSize = 5
 )
 
 // Dashboard panel ratios and spacing.
@@ -102,5 +128,28 @@ const (
 	HeightStatus     = 2
 	HeightIndicators = 1
 	HeightSpacing    = 1
+	HeightSummary    = 1
 	HeightFooter     = 2
 )
+
+// Transient notifications
+const (
+	// ToastDuration is how long a footer notification stays visible
+	ToastDuration = 3 * time.Second
+)
+
+// Interactive rate changes
+const (
+	// RateStep is how many probes per second the rate keys add or remove
+	RateStep = 500
+)
+
+// Single-port re-scan from the details modal
+const (
+	// SingleRescanTimeoutMultiplier scales the configured timeout so slow
+	// services misclassified as filtered get a fairer second chance
+	SingleRescanTimeoutMultiplier = 5
+
+	// SingleRescanMinTimeout is the lower bound for a single-port re-scan
+	SingleRescanMinTimeout = 2 * time.Second
+)
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+)
+
+// toggleDetailsTab switches the details modal between the summary and the
+// raw JSON and probe transcript, starting the new tab at its top.
+func (m *ScanUI) toggleDetailsTab() {
+	m.detailsRaw = !m.detailsRaw
+	m.modalState.ScrollPosition = 0
+}
+
+// renderDetailsTabs renders the details modal's tab bar with the open tab
+// highlighted.
+func (m *ScanUI) renderDetailsTabs() string {
+	active := lipgloss.NewStyle().Bold(true).Background(m.theme.Primary).Foreground(m.theme.Background)
+	inactive := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	details, raw := active, inactive
+	if m.detailsRaw {
+		details, raw = inactive, active
+	}
+	return details.Render(" Details ") + " " + raw.Render(" JSON/Raw ")
+}
+
+// writeDetailsRaw writes the raw tab: the result as a JSON export would
+// record it, then the bytes its banner came from.
+func (m *ScanUI) writeDetailsRaw(b *strings.Builder, r core.ResultEvent) {
+	section := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Secondary)
+
+	b.WriteString(section.Render("🧾 JSON") + "\n")
+	record, err := m.resultJSON(r)
+	if err != nil {
+		b.WriteString("  " + err.Error() + "\n\n")
+	} else {
+		for _, line := range strings.Split(string(record), "\n") {
+			b.WriteString("  " + line + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(section.Render("📡 Probe Transcript") + "\n")
+	for _, line := range transcriptLines(r, m.bannerHex) {
+		b.WriteString("  " + line + "\n")
+	}
+}
+
+// resultJSON returns r as a JSON export of the session would record it,
+// with the tags and note added in the UI.
+func (m *ScanUI) resultJSON(r core.ResultEvent) ([]byte, error) {
+	r.Tags = m.tagsFor(r)
+	r.Note = m.noteFor(r)
+	return exporter.ResultJSON(r)
+}
+
+// probeTranscript returns the raw exchange behind r's banner. A TCP banner
+// is read without sending anything, so it is its own transcript.
+func probeTranscript(r core.ResultEvent) []core.ProbeExchange {
+	if len(r.Transcript) > 0 {
+		return r.Transcript
+	}
+	if r.Banner != "" && r.Protocol != "udp" {
+		return []core.ProbeExchange{{Data: []byte(r.Banner)}}
+	}
+	return nil
+}
+
+// transcriptLines formats r's probe transcript, each datagram under a line
+// giving its direction and size, as escaped text or a hex dump.
+func transcriptLines(r core.ResultEvent, asHex bool) []string {
+	exchanges := probeTranscript(r)
+	if len(exchanges) == 0 {
+		return []string{"Nothing recorded (banners were not grabbed or the port did not answer)"}
+	}
+	var lines []string
+	for _, e := range exchanges {
+		heading := fmt.Sprintf("← Received %d bytes", len(e.Data))
+		if e.Sent {
+			heading = fmt.Sprintf("→ Sent %d bytes", len(e.Data))
+		}
+		lines = append(lines, heading)
+		if len(e.Data) == 0 {
+			continue
+		}
+		for _, line := range bannerDetailLines(string(e.Data), asHex) {
+			lines = append(lines, "  "+line)
+		}
+	}
+	return lines
+}
+
+// copyDetailsRaw copies what the raw tab shows for the selected result:
+// its JSON record followed by the probe transcript.
+func (m *ScanUI) copyDetailsRaw() tea.Cmd {
+	result, ok := m.selectedResult()
+	if !ok {
+		return m.showToast(i18n.T("ui.copy.nothing"), true)
+	}
+	record, err := m.resultJSON(result)
+	if err != nil {
+		return m.showToast(i18n.T("ui.copy.failed", err), true)
+	}
+	text := string(record) + "\n\n" + strings.Join(transcriptLines(result, m.bannerHex), "\n") + "\n"
+	if err := clipboardWriter(text); err != nil {
+		return m.showToast(i18n.T("ui.copy.failed", err), true)
+	}
+	label := i18n.T("ui.copy.raw_for", net.JoinHostPort(result.Host, strconv.Itoa(int(result.Port))))
+	return m.showToast(i18n.T("ui.copy.copied", label), false)
+}
@@ -0,0 +1,252 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/lucchesi-sec/portscan/pkg/theme"
+)
+
+// ExportPathCharLimit caps the length of the export destination path.
+const ExportPathCharLimit = 512
+
+// exportFinishedMsg reports the outcome of an in-TUI export.
+type exportFinishedMsg struct {
+	path  string
+	count int
+	err   error
+}
+
+// ExportState holds the export modal's format selection and destination.
+type ExportState struct {
+	FormatIndex int
+	PathInput   textinput.Model
+	PathEdited  bool // stop suggesting file names once the user types a path
+	// SelectedOnly exports the marked rows instead of the current view
+	SelectedOnly bool
+}
+
+// newExportState creates the export modal state with an empty path input.
+func newExportState(t theme.Theme) ExportState {
+	input := textinput.New()
+	input.Prompt = i18n.T("ui.export.prompt")
+	input.CharLimit = ExportPathCharLimit
+	input.PromptStyle = lipgloss.NewStyle().Foreground(t.Secondary).Bold(true)
+	return ExportState{PathInput: input}
+}
+
+// defaultExportPath suggests a timestamped file name for the given format.
+func defaultExportPath(format string, now time.Time) string {
+	return "portscan-results-" + now.Format("20060102-150405") + exporter.FileExtension(format)
+}
+
+// suggestedExportPath is defaultExportPath with the configured --compress
+// suffix, which writeExport then picks up from the name.
+func (m *ScanUI) suggestedExportPath() string {
+	return defaultExportPath(m.selectedExportFormat(), time.Now()) + exporter.CompressionExtension(m.config.Compress)
+}
+
+// selectedExportFormat returns the format under the modal cursor.
+func (m *ScanUI) selectedExportFormat() string {
+	return exporter.Formats[m.exportState.FormatIndex]
+}
+
+// openExportModal opens the export dialog with a suggested destination.
+func (m *ScanUI) openExportModal() tea.Cmd {
+	m.openModal(ModalExport)
+	m.exportState.PathEdited = false
+	m.exportState.SelectedOnly = false
+	m.exportState.PathInput.SetValue(m.suggestedExportPath())
+	m.exportState.PathInput.CursorEnd()
+	return m.exportState.PathInput.Focus()
+}
+
+// cycleExportFormat moves the format cursor, updating the suggested path
+// unless the user has typed their own.
+func (m *ScanUI) cycleExportFormat(delta int) {
+	count := len(exporter.Formats)
+	m.exportState.FormatIndex = ((m.exportState.FormatIndex+delta)%count + count) % count
+	if !m.exportState.PathEdited {
+		m.exportState.PathInput.SetValue(m.suggestedExportPath())
+		m.exportState.PathInput.CursorEnd()
+	}
+}
+
+// handleExportModalKey handles format selection and path editing.
+func (m *ScanUI) handleExportModalKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyUp, tea.KeyShiftTab:
+		m.cycleExportFormat(-1)
+		return true, true, nil
+	case tea.KeyDown, tea.KeyTab:
+		m.cycleExportFormat(1)
+		return true, true, nil
+	case tea.KeyCtrlC:
+		return true, true, tea.Quit
+	case tea.KeyEnter:
+		return true, true, m.startExport()
+	}
+
+	before := m.exportState.PathInput.Value()
+	var cmd tea.Cmd
+	m.exportState.PathInput, cmd = m.exportState.PathInput.Update(msg)
+	if m.exportState.PathInput.Value() != before {
+		m.exportState.PathEdited = true
+	}
+	return true, true, cmd
+}
+
+// startExport closes the modal and writes the current view in the background
+// so the scan keeps running while the file is written.
+func (m *ScanUI) startExport() tea.Cmd {
+	path := strings.TrimSpace(m.exportState.PathInput.Value())
+	if path == "" {
+		return m.showToast(i18n.T("ui.export.no_path"), true)
+	}
+
+	m.modalState.IsActive = false
+	m.exportState.PathInput.Blur()
+	return m.exportTo(path, m.selectedExportFormat())
+}
+
+// exportTo writes the rows the export modal selects, the current view or
+// the marked results, to path in the background.
+func (m *ScanUI) exportTo(path, format string) tea.Cmd {
+	if !m.exportState.SelectedOnly && m.spill != nil {
+		return m.startFullExport(path, format)
+	}
+	results := m.exportSource()
+	m.annotations().apply(results)
+
+	return func() tea.Msg {
+		return exportFinishedMsg{
+			path:  path,
+			count: len(results),
+			err:   writeExport(path, format, results),
+		}
+	}
+}
+
+// startFullExport exports the current view including results evicted to
+// the overflow file. Filters and sort are copied so the background read
+// sees the view as it was when the export started.
+func (m *ScanUI) startFullExport(path, format string) tea.Cmd {
+	spillPath, size, err := m.spill.Snapshot()
+	if err != nil {
+		return m.showToast(i18n.T("ui.export.failed", err), true)
+	}
+	buffered := m.results.Items()
+	filters := *m.filterState
+	sorting := *m.sortState
+	annotations := m.annotations()
+
+	return func() tea.Msg {
+		spilled, err := readSpill(spillPath, size)
+		if err != nil {
+			return exportFinishedMsg{path: path, err: err}
+		}
+		results := sorting.ApplySort(filters.ApplyFilters(append(spilled, buffered...)))
+		annotations.apply(results)
+		return exportFinishedMsg{
+			path:  path,
+			count: len(results),
+			err:   writeExport(path, format, results),
+		}
+	}
+}
+
+// exportSource returns a copy of the rows the export modal will write.
+func (m *ScanUI) exportSource() []core.ResultEvent {
+	if m.exportState.SelectedOnly {
+		return m.selectedResults()
+	}
+	results := make([]core.ResultEvent, len(m.displayResults))
+	copy(results, m.displayResults)
+	return results
+}
+
+// writeExport writes results to path in the given format, compressed when
+// the name ends in a compression suffix such as .gz.
+func writeExport(path, format string, results []core.ResultEvent) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	w, err := exporter.NewCompressWriter(file, exporter.CompressionForPath(path))
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	exp, err := exporter.New(format, w)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := exporter.WriteResults(exp, results); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// handleExportFinished reports the export outcome in the footer.
+func (m *ScanUI) handleExportFinished(msg exportFinishedMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.showToast(i18n.T("ui.export.failed", msg.err), true)
+	}
+	return m.showToast(i18n.T("ui.export.done", msg.count, msg.path), false)
+}
+
+// renderExportModal renders the export format and destination dialog.
+func (m *ScanUI) renderExportModal() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Primary).
+		Width(30).
+		Render(i18n.T("ui.export.title"))
+	b.WriteString(title + "\n\n")
+
+	m.modalState.OptionsTop = strings.Count(b.String(), "\n")
+	for i, format := range exporter.Formats {
+		style := lipgloss.NewStyle()
+		if i == m.exportState.FormatIndex {
+			style = style.Background(m.theme.Primary).Foreground(m.theme.Background)
+		}
+		b.WriteString(style.Render(fmt.Sprintf("%d. %s", i+1, format)) + "\n")
+	}
+
+	b.WriteString("\n" + m.exportState.PathInput.View() + "\n\n")
+
+	source := i18n.T("ui.export.view_count", len(m.displayResults))
+	if m.exportState.SelectedOnly {
+		source = i18n.T("ui.export.selected_count", m.selection.Count())
+	}
+	count := lipgloss.NewStyle().
+		Foreground(m.theme.Secondary).
+		Render(source)
+	b.WriteString(count + "\n")
+
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Render(i18n.T("ui.export.keys"))
+	b.WriteString("\n" + instructions)
+
+	return b.String()
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+)
+
+func TestScanUI_ExportKeyOpensModal(t *testing.T) {
+	ui := newSearchTestUI(t)
+
+	handled, skip, _ := ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	if !handled || !skip {
+		t.Fatal("'e' should be handled and skip table update")
+	}
+	if !ui.modalState.IsActive || ui.modalState.Type != ModalExport {
+		t.Fatal("'e' should open the export modal")
+	}
+	if path := ui.exportState.PathInput.Value(); !strings.HasSuffix(path, ".ndjson") {
+		t.Errorf("expected default ndjson path, got %q", path)
+	}
+}
+
+func TestScanUI_ExportModalCyclesFormats(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.openExportModal()
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyTab})
+	if got := ui.selectedExportFormat(); got != exporter.FormatCSV {
+		t.Errorf("tab should select csv, got %s", got)
+	}
+	if path := ui.exportState.PathInput.Value(); !strings.HasSuffix(path, ".csv") {
+		t.Errorf("suggested path should follow the format, got %q", path)
+	}
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyUp})
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyUp})
+	if got := ui.selectedExportFormat(); got != exporter.FormatMarkdown {
+		t.Errorf("up should wrap to markdown, got %s", got)
+	}
+}
+
+func TestScanUI_ExportModalCapturesTyping(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.openExportModal()
+	ui.exportState.PathInput.SetValue("")
+
+	typeRunes(ui, "q?.html")
+	if ui.showHelp {
+		t.Error("'?' in the path input must not toggle help")
+	}
+	if got := ui.exportState.PathInput.Value(); got != "q?.html" {
+		t.Errorf("expected typed path, got %q", got)
+	}
+
+	// A user-edited path is kept when the format changes.
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyDown})
+	if got := ui.exportState.PathInput.Value(); got != "q?.html" {
+		t.Errorf("edited path should be preserved, got %q", got)
+	}
+}
+
+func TestScanUI_ExportWritesFilteredView(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.filterState.SetSearchQuery("10.0.0")
+	ui.updateTable()
+
+	path := filepath.Join(t.TempDir(), "out.csv")
+	ui.openExportModal()
+	ui.exportState.FormatIndex = 1 // csv
+	ui.exportState.PathInput.SetValue(path)
+
+	_, _, cmd := ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should return an export command")
+	}
+	if ui.modalState.IsActive {
+		t.Error("enter should close the export modal")
+	}
+
+	msg, ok := cmd().(exportFinishedMsg)
+	if !ok {
+		t.Fatal("export command should report exportFinishedMsg")
+	}
+	if msg.err != nil {
+		t.Fatalf("export failed: %v", msg.err)
+	}
+	if msg.count != 3 {
+		t.Errorf("expected 3 filtered results exported, got %d", msg.count)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading export: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 4 {
+		t.Errorf("expected header plus 3 rows, got %d lines", len(lines))
+	}
+
+	ui.Update(msg)
+	if !strings.Contains(ui.renderFooter(), "Exported 3 results") {
+		t.Errorf("footer should confirm the export, got %q", ui.renderFooter())
+	}
+}
+
+func TestScanUI_ToastExpiry(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.showToast("first", false)
+	stale := toastExpiredMsg{id: ui.toastID}
+	ui.showToast("second", true)
+
+	ui.handleToastExpired(stale)
+	if ui.toast != "second" {
+		t.Errorf("stale expiry should not clear a newer toast, got %q", ui.toast)
+	}
+
+	ui.handleToastExpired(toastExpiredMsg{id: ui.toastID})
+	if ui.toast != "" {
+		t.Error("matching expiry should clear the toast")
+	}
+}
+
+func TestDefaultExportPath(t *testing.T) {
+	now := time.Date(2024, 5, 1, 13, 4, 5, 0, time.UTC)
+	if got := defaultExportPath(exporter.FormatHTML, now); got != "portscan-results-20240501-130405.html" {
+		t.Errorf("unexpected default path %q", got)
+	}
+}
+
+func TestScanUI_ExportCompressed(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.config.Compress = exporter.CompressionGzip
+
+	ui.openExportModal()
+	if path := ui.exportState.PathInput.Value(); !strings.HasSuffix(path, ".ndjson.gz") {
+		t.Errorf("suggested path should carry the compression suffix, got %q", path)
+	}
+
+	path := filepath.Join(t.TempDir(), "out.ndjson.gz")
+	if err := writeExport(path, exporter.FormatNDJSON, ui.exportSource()); err != nil {
+		t.Fatalf("writeExport: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading export: %v", err)
+	}
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		t.Errorf("export should be gzip-compressed, starts with %q", data[:min(len(data), 4)])
+	}
+}
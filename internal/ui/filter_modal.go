@@ -0,0 +1,281 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/lucchesi-sec/portscan/pkg/theme"
+)
+
+// filterField is a row of the filter modal, in display order.
+type filterField int
+
+const (
+	filterFieldHost filterField = iota
+	filterFieldPorts
+	filterFieldService
+	filterFieldState
+	filterFieldLatency
+	filterFieldBanner
+	filterFieldCount
+)
+
+// filterFieldLabels are the i18n keys of the filter modal's rows.
+var filterFieldLabels = []string{
+	"ui.filter_modal.host",
+	"ui.filter_modal.ports",
+	"ui.filter_modal.service",
+	"ui.filter_modal.state",
+	"ui.filter_modal.latency",
+	"ui.filter_modal.banner",
+}
+
+// stateFilterLabels name the state filter choices in StateFilterType order.
+var stateFilterLabels = []string{
+	"ui.filter_modal.state_all",
+	"ui.filter_modal.state_open",
+	"ui.filter_modal.state_closed",
+	"ui.filter_modal.state_filtered",
+}
+
+// filterForm is the filter modal being edited. Nothing reaches the table
+// until the form is applied.
+type filterForm struct {
+	host    textinput.Model
+	ports   textinput.Model
+	service textinput.Model
+	latency textinput.Model
+	banner  textinput.Model
+	state   StateFilterType
+}
+
+// newFilterForm builds the filter modal's inputs styled for t.
+func newFilterForm(t theme.Theme) filterForm {
+	newInput := func(placeholder string) textinput.Model {
+		input := textinput.New()
+		input.Prompt = ""
+		input.Placeholder = i18n.T(placeholder)
+		input.PromptStyle = lipgloss.NewStyle().Foreground(t.Secondary).Bold(true)
+		return input
+	}
+	return filterForm{
+		host:    newInput("ui.filter_modal.host_placeholder"),
+		ports:   newInput("ui.filter_modal.ports_placeholder"),
+		service: newInput("ui.filter_modal.service_placeholder"),
+		latency: newInput("ui.filter_modal.latency_placeholder"),
+		banner:  newInput("ui.filter_modal.banner_placeholder"),
+	}
+}
+
+// input returns the text input of field, or nil for the state choice.
+func (f *filterForm) input(field filterField) *textinput.Model {
+	switch field {
+	case filterFieldHost:
+		return &f.host
+	case filterFieldPorts:
+		return &f.ports
+	case filterFieldService:
+		return &f.service
+	case filterFieldLatency:
+		return &f.latency
+	case filterFieldBanner:
+		return &f.banner
+	}
+	return nil
+}
+
+// openFilterModal fills the form from the filters in effect and focuses
+// its first field.
+func (m *ScanUI) openFilterModal() tea.Cmd {
+	m.openModal(ModalFilter)
+	f := m.filterState
+	m.filterForm.host.SetValue(f.HostFilter)
+	m.filterForm.ports.SetValue(formatPortRange(f.PortRangeMin, f.PortRangeMax))
+	m.filterForm.service.SetValue(f.ServiceFilter)
+	m.filterForm.latency.SetValue("")
+	if f.LatencyMax > 0 {
+		m.filterForm.latency.SetValue(strconv.Itoa(f.LatencyMax))
+	}
+	m.filterForm.banner.SetValue(f.BannerSearch)
+	m.filterForm.state = f.StateFilter
+	return m.focusFilterField(filterFieldHost)
+}
+
+// focusFilterField moves the cursor to field, focusing its input.
+func (m *ScanUI) focusFilterField(field filterField) tea.Cmd {
+	if field < 0 || field >= filterFieldCount {
+		return nil
+	}
+	for i := filterField(0); i < filterFieldCount; i++ {
+		if input := m.filterForm.input(i); input != nil {
+			input.Blur()
+		}
+	}
+	m.modalState.Cursor = int(field)
+	if input := m.filterForm.input(field); input != nil {
+		input.CursorEnd()
+		return input.Focus()
+	}
+	return nil
+}
+
+// cycleStateFilter steps the state choice by delta, wrapping around.
+func (m *ScanUI) cycleStateFilter(delta int) {
+	n := len(stateFilterLabels)
+	m.filterForm.state = StateFilterType((int(m.filterForm.state) + delta + n) % n)
+}
+
+// handleFilterModalKey moves between the fields, edits the focused one and
+// applies the form on Enter.
+func (m *ScanUI) handleFilterModalKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
+	field := filterField(m.modalState.Cursor)
+	switch msg.String() {
+	case "ctrl+c":
+		return true, true, tea.Quit
+	case "enter":
+		return true, true, m.applyFilterForm()
+	case "tab", "down":
+		return true, true, m.focusFilterField((field + 1) % filterFieldCount)
+	case "shift+tab", "up":
+		return true, true, m.focusFilterField((field + filterFieldCount - 1) % filterFieldCount)
+	}
+
+	input := m.filterForm.input(field)
+	if input == nil {
+		switch msg.String() {
+		case "left", "h":
+			m.cycleStateFilter(-1)
+		case "right", "l", " ":
+			m.cycleStateFilter(1)
+		}
+		return true, true, nil
+	}
+	var cmd tea.Cmd
+	*input, cmd = input.Update(msg)
+	return true, true, cmd
+}
+
+// applyFilterForm replaces the table's filters with the form's, keeping the
+// '/' search. A port range, latency or "re:" pattern that does not parse
+// leaves the modal open on that field.
+func (m *ScanUI) applyFilterForm() tea.Cmd {
+	minPort, maxPort, err := parsePortRange(m.filterForm.ports.Value())
+	if err != nil {
+		cmd := m.showToast(i18n.T("ui.filter_modal.invalid_ports", m.filterForm.ports.Value()), true)
+		return tea.Batch(m.focusFilterField(filterFieldPorts), cmd)
+	}
+	latency, err := parseLatency(m.filterForm.latency.Value())
+	if err != nil {
+		cmd := m.showToast(i18n.T("ui.filter_modal.invalid_latency", m.filterForm.latency.Value()), true)
+		return tea.Batch(m.focusFilterField(filterFieldLatency), cmd)
+	}
+
+	for _, field := range []filterField{filterFieldHost, filterFieldBanner} {
+		if _, err := compileFilterPattern(strings.TrimSpace(m.filterForm.input(field).Value())); err != nil {
+			cmd := m.showToast(i18n.T("ui.filter_modal.invalid_pattern", err), true)
+			return tea.Batch(m.focusFilterField(field), cmd)
+		}
+	}
+
+	f := m.filterState
+	_ = f.SetHostFilter(strings.TrimSpace(m.filterForm.host.Value()))
+	f.SetPortRange(minPort, maxPort)
+	f.SetServiceFilter(strings.TrimSpace(m.filterForm.service.Value()))
+	f.SetStateFilter(m.filterForm.state)
+	f.SetLatencyFilter(latency)
+	_ = f.SetBannerSearch(strings.TrimSpace(m.filterForm.banner.Value()))
+
+	m.closeModal()
+	m.updateTable()
+	return nil
+}
+
+// parsePortRange reads "80" or "20-443"; empty means every port.
+func parsePortRange(s string) (uint16, uint16, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, 65535, nil
+	}
+	low, high, isRange := strings.Cut(s, "-")
+	minPort, err := strconv.ParseUint(strings.TrimSpace(low), 10, 16)
+	if err != nil {
+		return 0, 0, err
+	}
+	maxPort := minPort
+	if isRange {
+		if maxPort, err = strconv.ParseUint(strings.TrimSpace(high), 10, 16); err != nil {
+			return 0, 0, err
+		}
+	}
+	if minPort > maxPort {
+		return 0, 0, fmt.Errorf("port range %d-%d is reversed", minPort, maxPort)
+	}
+	return uint16(minPort), uint16(maxPort), nil
+}
+
+// formatPortRange writes a port range as parsePortRange reads it, "" for
+// every port.
+func formatPortRange(minPort, maxPort uint16) string {
+	switch {
+	case minPort == 0 && maxPort == 65535:
+		return ""
+	case minPort == maxPort:
+		return strconv.Itoa(int(minPort))
+	default:
+		return fmt.Sprintf("%d-%d", minPort, maxPort)
+	}
+}
+
+// parseLatency reads a latency limit such as "200" or "200ms"; empty means
+// no limit.
+func parseLatency(s string) (int, error) {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "ms")
+	if s == "" {
+		return 0, nil
+	}
+	ms, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || ms < 0 {
+		return 0, fmt.Errorf("invalid latency %q", s)
+	}
+	return ms, nil
+}
+
+// renderFilterModal renders one row per filter, with the focused row's
+// label highlighted.
+func (m *ScanUI) renderFilterModal() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Primary).
+		Width(40).
+		Render(i18n.T("ui.filter_modal.title"))
+	b.WriteString(title + "\n\n")
+
+	m.modalState.OptionsTop = strings.Count(b.String(), "\n")
+	for field := filterField(0); field < filterFieldCount; field++ {
+		label := lipgloss.NewStyle().Width(14)
+		if int(field) == m.modalState.Cursor {
+			label = label.Bold(true).Foreground(m.theme.Primary)
+		}
+		value := ""
+		if input := m.filterForm.input(field); input != nil {
+			value = input.View()
+		} else {
+			value = "‹ " + i18n.T(stateFilterLabels[m.filterForm.state]) + " ›"
+		}
+		b.WriteString(label.Render(i18n.T(filterFieldLabels[field])) + value + "\n")
+	}
+
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Render(i18n.T("ui.filter_modal.keys"))
+	b.WriteString("\n" + instructions)
+
+	return b.String()
+}
@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+func newFilterTestUI() *ScanUI {
+	ui := NewScanUI(&config.Config{}, 5, make(chan core.Event), false)
+	ui.recordResults([]core.ResultEvent{
+		{Host: "10.0.0.1", Port: 22, State: core.StateOpen, Duration: 5 * time.Millisecond},
+		{Host: "10.0.0.1", Port: 80, State: core.StateOpen, Duration: 400 * time.Millisecond},
+		{Host: "10.0.0.1", Port: 81, State: core.StateClosed, Duration: 5 * time.Millisecond},
+		{Host: "10.0.0.2", Port: 80, State: core.StateOpen, Duration: 5 * time.Millisecond},
+		{Host: "10.0.0.1", Port: 8080, State: core.StateOpen, Duration: 5 * time.Millisecond},
+	})
+	return ui
+}
+
+func TestFilterModalCombinesFields(t *testing.T) {
+	ui := newFilterTestUI()
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	if !ui.modalState.IsActive || ui.modalState.Type != ModalFilter {
+		t.Fatal("f should open the filter modal")
+	}
+	typeRunes(ui, "10.0.0.1")
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyTab})
+	typeRunes(ui, "20-100")
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyTab})
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyTab})
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRight})
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyTab})
+	typeRunes(ui, "100ms")
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if ui.modalState.IsActive {
+		t.Fatal("Enter should apply the filters and close the modal")
+	}
+	if len(ui.displayResults) != 1 || ui.displayResults[0].Port != 22 {
+		t.Errorf("rows = %+v; want only 10.0.0.1:22", ui.displayResults)
+	}
+	desc := ui.filterState.GetActiveFilterDescription()
+	for _, want := range []string{"Open", "Host: 10.0.0.1", "Ports 20-100", "Latency <100ms"} {
+		if !strings.Contains(desc, want) {
+			t.Errorf("indicator %q lacks %q", desc, want)
+		}
+	}
+
+	// Reopening shows the filters in effect, and clearing one keeps the rest.
+	ui.openFilterModal()
+	if got := ui.filterForm.ports.Value(); got != "20-100" {
+		t.Errorf("ports field = %q", got)
+	}
+	ui.filterForm.latency.SetValue("")
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(ui.displayResults) != 2 {
+		t.Errorf("without the latency limit %d rows are shown; want 2", len(ui.displayResults))
+	}
+}
+
+func TestFilterModalRejectsBadPortRange(t *testing.T) {
+	ui := newFilterTestUI()
+	ui.openFilterModal()
+	ui.filterForm.ports.SetValue("443-80")
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if !ui.modalState.IsActive || filterField(ui.modalState.Cursor) != filterFieldPorts || !ui.toastIsError {
+		t.Error("a reversed port range should be reported with the ports field focused")
+	}
+	if len(ui.displayResults) != 5 {
+		t.Error("nothing should be filtered until the form is valid")
+	}
+}
+
+func TestFilterModalBannerPattern(t *testing.T) {
+	ui := newFilterTestUI()
+	ui.recordResults([]core.ResultEvent{{Host: "10.0.0.3", Port: 22, State: core.StateOpen, Banner: "SSH-2.0-OpenSSH_7.4"}})
+	ui.openFilterModal()
+
+	ui.filterForm.banner.SetValue("re:OpenSSH_[67")
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if !ui.modalState.IsActive || filterField(ui.modalState.Cursor) != filterFieldBanner || !ui.toastIsError {
+		t.Fatal("an invalid pattern should be reported with the banner field focused")
+	}
+	if !strings.Contains(ui.toast, "missing closing ]") {
+		t.Errorf("toast = %q; want the regexp error", ui.toast)
+	}
+
+	ui.filterForm.banner.SetValue(`re:OpenSSH_[67]\.`)
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if ui.modalState.IsActive || len(ui.displayResults) != 1 || ui.displayResults[0].Host != "10.0.0.3" {
+		t.Errorf("banner pattern rows = %+v", ui.displayResults)
+	}
+}
+
+func TestParsePortRange(t *testing.T) {
+	tests := []struct {
+		in       string
+		min, max uint16
+		ok       bool
+	}{
+		{"", 0, 65535, true},
+		{"443", 443, 443, true},
+		{" 1 - 1024 ", 1, 1024, true},
+		{"1024-1", 0, 0, false},
+		{"http", 0, 0, false},
+		{"1-70000", 0, 0, false},
+	}
+	for _, tt := range tests {
+		lo, hi, err := parsePortRange(tt.in)
+		if (err == nil) != tt.ok || lo != tt.min || hi != tt.max {
+			t.Errorf("parsePortRange(%q) = %d, %d, %v", tt.in, lo, hi, err)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/lucchesi-sec/portscan/internal/core"
@@ -10,14 +11,25 @@ import (
 // FilterState represents the current filter configuration
 type FilterState struct {
 	StateFilter   StateFilterType
+	HostFilter    string // substring of the host address or name
 	PortRangeMin  uint16
 	PortRangeMax  uint16
 	ServiceFilter string
 	LatencyMax    int // milliseconds, 0 = no filter
 	BannerSearch  string
+	SearchQuery   string // Incremental '/' search across host, service, and banner
 	IsActive      bool
+
+	// Compiled "re:" patterns of HostFilter and BannerSearch, nil when
+	// they are plain substrings.
+	hostPattern   *regexp.Regexp
+	bannerPattern *regexp.Regexp
 }
 
+// regexPrefix marks a host or banner filter as a regular expression rather
+// than a substring, as in "re:OpenSSH_[67]\.".
+const regexPrefix = "re:"
+
 // StateFilterType represents which states to show
 type StateFilterType int
 
@@ -62,6 +74,14 @@ func (f *FilterState) matchesFilters(r core.ResultEvent) bool {
 		return false
 	}
 
+	// Host filter
+	if f.HostFilter != "" {
+		if !matchesText(f.hostPattern, f.HostFilter, r.Host) &&
+			!matchesText(f.hostPattern, f.HostFilter, r.Hostname) {
+			return false
+		}
+	}
+
 	// Port range filter
 	if r.Port < f.PortRangeMin || r.Port > f.PortRangeMax {
 		return false
@@ -69,7 +89,7 @@ func (f *FilterState) matchesFilters(r core.ResultEvent) bool {
 
 	// Service filter
 	if f.ServiceFilter != "" {
-		service := getServiceName(r.Port)
+		service := serviceName(r)
 		if !strings.Contains(strings.ToLower(service), strings.ToLower(f.ServiceFilter)) {
 			return false
 		}
@@ -84,14 +104,55 @@ func (f *FilterState) matchesFilters(r core.ResultEvent) bool {
 
 	// Banner search
 	if f.BannerSearch != "" {
-		if !strings.Contains(strings.ToLower(r.Banner), strings.ToLower(f.BannerSearch)) {
+		if !matchesText(f.bannerPattern, f.BannerSearch, r.Banner) {
 			return false
 		}
 	}
 
+	// Incremental search
+	if f.SearchQuery != "" && !matchesSearch(r, f.SearchQuery) {
+		return false
+	}
+
 	return true
 }
 
+// matchesSearch reports whether the query appears in the result's host,
+// service name, or banner (case-insensitive).
+func matchesSearch(r core.ResultEvent, query string) bool {
+	query = strings.ToLower(query)
+	if strings.Contains(strings.ToLower(r.Host), query) {
+		return true
+	}
+	if strings.Contains(strings.ToLower(serviceName(r)), query) {
+		return true
+	}
+	return strings.Contains(strings.ToLower(r.Banner), query)
+}
+
+// matchesText reports whether text matches pattern when the filter is a
+// regular expression, or contains the filter otherwise (case-insensitive).
+func matchesText(pattern *regexp.Regexp, filter, text string) bool {
+	if pattern != nil {
+		return pattern.MatchString(text)
+	}
+	return strings.Contains(strings.ToLower(text), strings.ToLower(filter))
+}
+
+// compileFilterPattern compiles a "re:" filter; a plain substring yields
+// nil.
+func compileFilterPattern(filter string) (*regexp.Regexp, error) {
+	expr, ok := strings.CutPrefix(filter, regexPrefix)
+	if !ok {
+		return nil, nil
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", expr, err)
+	}
+	return pattern, nil
+}
+
 // matchesStateFilter checks if result matches the state filter
 func (f *FilterState) matchesStateFilter(r core.ResultEvent) bool {
 	switch f.StateFilter {
@@ -108,47 +169,85 @@ func (f *FilterState) matchesStateFilter(r core.ResultEvent) bool {
 	}
 }
 
+// SetHostFilter shows only hosts whose address or name contains host, or
+// matches it when it starts with "re:"; an empty host removes the filter.
+// A pattern that does not compile is returned and the filter is unchanged.
+func (f *FilterState) SetHostFilter(host string) error {
+	pattern, err := compileFilterPattern(host)
+	if err != nil {
+		return err
+	}
+	f.HostFilter = host
+	f.hostPattern = pattern
+	f.IsActive = f.hasFilters()
+	return nil
+}
+
 // SetPortRange sets the port range filter
 func (f *FilterState) SetPortRange(min, max uint16) {
 	f.PortRangeMin = min
 	f.PortRangeMax = max
-	f.IsActive = true
+	f.IsActive = f.hasFilters()
 }
 
 // SetStateFilter sets the state filter
 func (f *FilterState) SetStateFilter(stateType StateFilterType) {
 	f.StateFilter = stateType
-	if stateType != StateFilterAll {
-		f.IsActive = true
-	}
+	f.IsActive = f.hasFilters()
 }
 
 // SetServiceFilter sets the service name filter
 func (f *FilterState) SetServiceFilter(service string) {
 	f.ServiceFilter = service
-	f.IsActive = service != ""
+	f.IsActive = f.hasFilters()
 }
 
 // SetLatencyFilter sets the maximum latency filter
 func (f *FilterState) SetLatencyFilter(maxMs int) {
 	f.LatencyMax = maxMs
-	f.IsActive = maxMs > 0
+	f.IsActive = f.hasFilters()
 }
 
-// SetBannerSearch sets the banner search filter
-func (f *FilterState) SetBannerSearch(search string) {
+// SetBannerSearch sets the banner search filter, a substring or a "re:"
+// pattern. A pattern that does not compile is returned and the filter is
+// unchanged.
+func (f *FilterState) SetBannerSearch(search string) error {
+	pattern, err := compileFilterPattern(search)
+	if err != nil {
+		return err
+	}
 	f.BannerSearch = search
-	f.IsActive = search != ""
+	f.bannerPattern = pattern
+	f.IsActive = f.hasFilters()
+	return nil
+}
+
+// SetSearchQuery sets the incremental search query
+func (f *FilterState) SetSearchQuery(query string) {
+	f.SearchQuery = query
+	f.IsActive = f.hasFilters()
+}
+
+// hasFilters reports whether any filter narrows the results, so clearing
+// one filter leaves the others in effect.
+func (f *FilterState) hasFilters() bool {
+	return f.StateFilter != StateFilterAll || f.HostFilter != "" ||
+		f.PortRangeMin > 0 || f.PortRangeMax < 65535 || f.ServiceFilter != "" ||
+		f.LatencyMax > 0 || f.BannerSearch != "" || f.SearchQuery != ""
 }
 
 // Reset clears all filters
 func (f *FilterState) Reset() {
 	f.StateFilter = StateFilterAll
+	f.HostFilter = ""
+	f.hostPattern = nil
 	f.PortRangeMin = 0
 	f.PortRangeMax = 65535
 	f.ServiceFilter = ""
 	f.LatencyMax = 0
 	f.BannerSearch = ""
+	f.bannerPattern = nil
+	f.SearchQuery = ""
 	f.IsActive = false
 }
 
@@ -171,6 +270,10 @@ func (f *FilterState) GetActiveFilterDescription() string {
 		}
 	}
 
+	if f.HostFilter != "" {
+		filters = append(filters, "Host: "+f.HostFilter)
+	}
+
 	if f.PortRangeMin > 0 || f.PortRangeMax < 65535 {
 		filters = append(filters, fmt.Sprintf("Ports %d-%d", f.PortRangeMin, f.PortRangeMax))
 	}
@@ -187,6 +290,10 @@ func (f *FilterState) GetActiveFilterDescription() string {
 		filters = append(filters, "Banner: "+f.BannerSearch)
 	}
 
+	if f.SearchQuery != "" {
+		filters = append(filters, "Search: "+f.SearchQuery)
+	}
+
 	if len(filters) > 0 {
 		return "Filters: " + strings.Join(filters, ", ")
 	}
@@ -164,6 +164,66 @@ func TestFilterState_ApplyFilters_BannerSearch(t *testing.T) {
 	}
 }
 
+func TestFilterState_ApplyFilters_HostFilter(t *testing.T) {
+	results := []core.ResultEvent{
+		{Host: "10.0.0.1", Port: 80, State: core.StateOpen},
+		{Host: "10.0.0.2", Hostname: "DB.example.net", Port: 5432, State: core.StateOpen},
+		{Host: "192.168.1.1", Port: 22, State: core.StateOpen},
+	}
+
+	state := NewFilterState()
+	state.SetHostFilter("example")
+
+	filtered := state.ApplyFilters(results)
+	if len(filtered) != 1 || filtered[0].Port != 5432 {
+		t.Errorf("expected the host named db.example.net, got %+v", filtered)
+	}
+	if desc := state.GetActiveFilterDescription(); desc != "Filters: Host: example" {
+		t.Errorf("description = %q", desc)
+	}
+
+	state.Reset()
+	if state.HostFilter != "" {
+		t.Error("Reset should clear the host filter")
+	}
+}
+
+func TestFilterState_RegexFilters(t *testing.T) {
+	results := []core.ResultEvent{
+		{Host: "10.0.0.1", Port: 22, State: core.StateOpen, Banner: "SSH-2.0-OpenSSH_7.4"},
+		{Host: "10.0.0.2", Hostname: "web1.example.net", Port: 22, State: core.StateOpen, Banner: "SSH-2.0-OpenSSH_8.9p1"},
+		{Host: "10.0.0.3", Hostname: "web2.example.net", Port: 22, State: core.StateOpen, Banner: "SSH-2.0-OpenSSH_6.6.1"},
+	}
+
+	state := NewFilterState()
+	if err := state.SetBannerSearch(`re:OpenSSH_[67]\.`); err != nil {
+		t.Fatal(err)
+	}
+	if filtered := state.ApplyFilters(results); len(filtered) != 2 {
+		t.Errorf("banner pattern matched %d results; want 2", len(filtered))
+	}
+
+	if err := state.SetHostFilter(`re:^web\d\.`); err != nil {
+		t.Fatal(err)
+	}
+	filtered := state.ApplyFilters(results)
+	if len(filtered) != 1 || filtered[0].Host != "10.0.0.3" {
+		t.Errorf("host and banner patterns matched %+v", filtered)
+	}
+
+	if err := state.SetHostFilter("re:web[1"); err == nil {
+		t.Error("an invalid pattern should be reported")
+	}
+	if state.HostFilter != `re:^web\d\.` {
+		t.Errorf("an invalid pattern replaced the host filter with %q", state.HostFilter)
+	}
+
+	state.Reset()
+	if filtered := state.ApplyFilters(results); len(filtered) != 3 {
+		t.Errorf("Reset should drop the patterns, %d results shown", len(filtered))
+	}
+}
+
 func TestFilterState_ApplyFilters_LatencyFilter(t *testing.T) {
 	results := []core.ResultEvent{
 		{Host: "host1", Port: 80, State: core.StateOpen, Duration: 50 * time.Millisecond},
@@ -326,3 +386,27 @@ func TestFilterState_GetActiveFilterDescription(t *testing.T) {
 		t.Error("expected non-empty description with banner search filter")
 	}
 }
+
+func TestFilterState_ClearingOneFilterKeepsOthers(t *testing.T) {
+	results := []core.ResultEvent{
+		{Host: "host1", Port: 22, State: core.StateOpen},
+		{Host: "host1", Port: 8080, State: core.StateOpen},
+	}
+
+	state := NewFilterState()
+	state.SetPortRange(1, 1024)
+	state.SetServiceFilter("ssh")
+	state.SetServiceFilter("")
+
+	if !state.IsActive {
+		t.Fatal("expected the port range to stay active after clearing the service filter")
+	}
+	if filtered := state.ApplyFilters(results); len(filtered) != 1 {
+		t.Errorf("expected 1 result within ports 1-1024, got %d", len(filtered))
+	}
+
+	state.SetPortRange(0, 65535)
+	if state.IsActive {
+		t.Error("expected filter to be inactive once every filter is cleared")
+	}
+}
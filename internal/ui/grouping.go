@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+)
+
+// groupRow is a table row while the table is grouped by service: a group's
+// header, or one of its results when the group is expanded.
+type groupRow struct {
+	service string
+	result  int // index into displayResults, -1 for the header
+}
+
+// serviceGroup counts what a service group holds.
+type serviceGroup struct {
+	hosts int
+	ports int
+}
+
+// serviceGrouping collapses the filtered, sorted results into one row per
+// service. Groups are listed by host count and expanded ones list their
+// results in the table's sort order.
+type serviceGrouping struct {
+	enabled  bool
+	expanded map[string]bool
+	groups   map[string]serviceGroup
+	rows     []groupRow
+}
+
+// toggleGrouping switches the table between result rows and service
+// groups.
+func (m *ScanUI) toggleGrouping() tea.Cmd {
+	m.grouping.enabled = !m.grouping.enabled
+	if m.grouping.expanded == nil {
+		m.grouping.expanded = make(map[string]bool)
+	}
+	m.table.SetCursor(0)
+	m.updateTable()
+	if m.grouping.enabled {
+		return m.showToast(i18n.T("ui.grouping.on"), false)
+	}
+	return m.showToast(i18n.T("ui.grouping.off"), false)
+}
+
+// groupResults rebuilds the group rows from displayResults.
+func (m *ScanUI) groupResults() {
+	g := &m.grouping
+	g.rows = g.rows[:0]
+	if !g.enabled {
+		return
+	}
+
+	members := make(map[string][]int)
+	g.groups = make(map[string]serviceGroup)
+	for i, r := range m.displayResults {
+		service := serviceName(r)
+		members[service] = append(members[service], i)
+		group := g.groups[service]
+		group.ports++
+		g.groups[service] = group
+	}
+	hosts := serviceHostCounts(m.displayResults)
+	for _, stat := range topCounts(hosts, len(hosts)) {
+		group := g.groups[stat.Name]
+		group.hosts = stat.Count
+		g.groups[stat.Name] = group
+
+		g.rows = append(g.rows, groupRow{service: stat.Name, result: -1})
+		if g.expanded[stat.Name] {
+			for _, i := range members[stat.Name] {
+				g.rows = append(g.rows, groupRow{service: stat.Name, result: i})
+			}
+		}
+	}
+}
+
+// rowCount returns the number of table rows.
+func (m *ScanUI) rowCount() int {
+	if m.grouping.enabled {
+		return len(m.grouping.rows)
+	}
+	return len(m.displayResults)
+}
+
+// resultAt returns the result drawn on table row i, or false for a group
+// header or a row out of range.
+func (m *ScanUI) resultAt(i int) (core.ResultEvent, bool) {
+	if m.grouping.enabled {
+		if i < 0 || i >= len(m.grouping.rows) || m.grouping.rows[i].result < 0 {
+			return core.ResultEvent{}, false
+		}
+		return m.displayResults[m.grouping.rows[i].result], true
+	}
+	if i < 0 || i >= len(m.displayResults) {
+		return core.ResultEvent{}, false
+	}
+	return m.displayResults[i], true
+}
+
+// openSelected opens the details of the result under the cursor, or
+// expands or collapses the group whose header is under it.
+func (m *ScanUI) openSelected() {
+	cursor := m.table.Cursor()
+	if m.grouping.enabled && cursor >= 0 && cursor < len(m.grouping.rows) && m.grouping.rows[cursor].result < 0 {
+		service := m.grouping.rows[cursor].service
+		m.grouping.expanded[service] = !m.grouping.expanded[service]
+		m.updateTable()
+		return
+	}
+	if _, ok := m.resultAt(cursor); ok {
+		m.openModal(ModalDetails)
+	}
+}
+
+// groupHeaderRow renders the header of a service group: the service with
+// its host count where hosts are listed and its port count in the banner
+// column.
+func (m *ScanUI) groupHeaderRow(service string, columns []table.Column) table.Row {
+	widths := m.columnWidthsByID(columns)
+	group := m.grouping.groups[service]
+	marker := m.symbols().Text("▸ ")
+	if m.grouping.expanded[service] {
+		marker = m.symbols().Text("▾ ")
+	}
+	style := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Primary)
+
+	cells := make([]string, len(defaultColumnSpecs))
+	cells[hostColumn] = style.Render(truncateToWidth(marker+i18n.T("ui.grouping.header", service, group.hosts), widths[hostColumn]))
+	cells[bannerColumn] = style.Render(truncateToWidth(i18n.T("ui.grouping.ports", group.ports), widths[bannerColumn]))
+
+	row := make(table.Row, len(m.layout.order))
+	for i, id := range m.layout.order {
+		row[i] = cells[id]
+	}
+	return row
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+func newGroupingTestUI() *ScanUI {
+	ui := NewScanUI(&config.Config{}, 6, make(chan core.Event), false)
+	ui.handleWindowSize(tea.WindowSizeMsg{Width: 120, Height: 30})
+	ui.recordResults([]core.ResultEvent{
+		{Host: "10.0.0.1", Port: 22, State: core.StateOpen},
+		{Host: "10.0.0.1", Port: 80, State: core.StateOpen},
+		{Host: "10.0.0.2", Port: 80, State: core.StateOpen},
+		{Host: "10.0.0.2", Port: 443, State: core.StateOpen},
+		{Host: "10.0.0.3", Port: 80, State: core.StateClosed},
+	})
+	return ui
+}
+
+func TestGroupingCollapsesAndExpands(t *testing.T) {
+	ui := newGroupingTestUI()
+
+	ui.openPalette()
+	typeRunes(ui, "group by service")
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if !ui.grouping.enabled || ui.rowCount() != 3 {
+		t.Fatalf("grouped rows = %+v", ui.grouping.rows)
+	}
+	if first := ui.grouping.rows[0].service; first != "http" {
+		t.Errorf("first group = %q; want the service on most hosts", first)
+	}
+	if group := ui.grouping.groups["http"]; group.hosts != 3 || group.ports != 3 {
+		t.Errorf("http group = %+v; want 3 hosts, 3 ports", group)
+	}
+	if !strings.Contains(ui.View(), "http: 3 hosts") {
+		t.Error("the group header should show the service and its host count")
+	}
+	if _, ok := ui.selectedResult(); ok {
+		t.Error("a group header is not a result")
+	}
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if ui.modalState.IsActive || ui.rowCount() != 6 {
+		t.Fatalf("Enter on a header should expand it, rows %d", ui.rowCount())
+	}
+	ui.table.MoveDown(1)
+	if r, ok := ui.selectedResult(); !ok || r.Port != 80 {
+		t.Errorf("first member = %+v", r)
+	}
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if !ui.modalState.IsActive || ui.modalState.Type != ModalDetails {
+		t.Error("Enter on a member row should open its details")
+	}
+}
+
+func TestGroupingFollowsFilters(t *testing.T) {
+	ui := newGroupingTestUI()
+	ui.toggleGrouping()
+
+	ui.toggleOpenOnly()
+	if group := ui.grouping.groups["http"]; group.hosts != 2 || group.ports != 2 {
+		t.Errorf("open http group = %+v; want 2 hosts, 2 ports", group)
+	}
+
+	ui.toggleGrouping()
+	if ui.grouping.enabled || ui.rowCount() != len(ui.displayResults) {
+		t.Error("toggling again should list every result")
+	}
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// hostBarWidth is the width of each host's completion bar.
+const hostBarWidth = 20
+
+// renderHostProgressPanel lists the hosts the scanner is part way through,
+// least complete first, with a completion bar and open count each, as many
+// as fit in height lines.
+func (m *ScanUI) renderHostProgressPanel(width, height int) string {
+	var b strings.Builder
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Primary).
+		Render("⏳ Hosts in Progress")
+	b.WriteString(title + "\n")
+	muted := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	b.WriteString(muted.Render("  "+m.progressTrack.GetHostProgress()+" hosts done") + "\n\n")
+
+	if len(m.activeHosts) == 0 {
+		b.WriteString("  No hosts in progress\n")
+		return b.String()
+	}
+
+	hostStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Secondary)
+	barStyle := lipgloss.NewStyle().Foreground(m.theme.GetStateColors().Open)
+	// Each host takes two lines below the three-line heading.
+	fit := max(1, (height-3)/2)
+	symbols := m.symbols()
+	for i, h := range m.activeHosts {
+		if i == fit {
+			break
+		}
+		filled := 0
+		if h.Total > 0 {
+			filled = h.Completed * hostBarWidth / h.Total
+		}
+		bar := barStyle.Render(strings.Repeat(string(symbols.BarFull), filled)) + muted.Render(strings.Repeat(string(symbols.BarEmpty), hostBarWidth-filled))
+		b.WriteString(hostStyle.Render(truncateToWidth(h.Host, width)) + "\n")
+		b.WriteString(fmt.Sprintf("  %s %d/%d • %d open\n", bar, h.Completed, h.Total, h.Open))
+	}
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+func TestScanUI_HostProgressPanel(t *testing.T) {
+	ui := newSearchTestUI(t)
+	if panel := ui.renderHostProgressPanel(60, 20); !strings.Contains(panel, "No hosts in progress") {
+		t.Errorf("empty panel = %q", panel)
+	}
+
+	ui.handleScanProgress(scanProgressMsg{progress: core.ProgressEvent{
+		Total: 300, Completed: 150, TotalHosts: 3, CompletedHosts: 1,
+		Hosts: []core.HostProgress{
+			{Host: "10.0.0.2", Total: 100, Completed: 10, Open: 0},
+			{Host: "10.0.0.3", Total: 100, Completed: 40, Open: 2},
+		},
+	}})
+
+	panel := ui.renderHostProgressPanel(60, 20)
+	for _, want := range []string{"1/3 hosts done", "10.0.0.2", "10/100 • 0 open", "40/100 • 2 open"} {
+		if !strings.Contains(panel, want) {
+			t.Errorf("panel missing %q:\n%s", want, panel)
+		}
+	}
+	if strings.Index(panel, "10.0.0.2") > strings.Index(panel, "10.0.0.3") {
+		t.Error("hosts should keep the scanner's least-complete-first order")
+	}
+	if short := ui.renderHostProgressPanel(60, 5); strings.Contains(short, "10.0.0.3") {
+		t.Errorf("a five-line panel should fit one host:\n%s", short)
+	}
+}
@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+)
+
+// hostSummary aggregates every buffered result for one host, whatever the
+// table's filters hide.
+type hostSummary struct {
+	Host     string
+	Hostname string
+	Ports    []core.ResultEvent // in port order, TCP before UDP
+	Open     int
+	Closed   int
+	Filtered int
+
+	TopServices []ServiceStat // services of the open ports, most common first
+
+	MinLatency time.Duration
+	AvgLatency time.Duration
+	MaxLatency time.Duration
+
+	OSGuess string
+	Tags    []string
+}
+
+// summarizeHost aggregates the buffered results for host.
+func (m *ScanUI) summarizeHost(host string) hostSummary {
+	summary := hostSummary{Host: host}
+	open := make(map[string]bool)
+	serviceCounts := make(map[string]int)
+	var banners []string
+	var total time.Duration
+	timed := 0
+
+	for _, r := range m.results.Items() {
+		if r.Host != host {
+			continue
+		}
+		summary.Ports = append(summary.Ports, r)
+		if summary.Hostname == "" {
+			summary.Hostname = r.Hostname
+		}
+		switch r.State {
+		case core.StateOpen:
+			summary.Open++
+			open[fmt.Sprintf("%d/%s", r.Port, resultProtocol(r))] = true
+			if service := serviceName(r); service != "unknown" {
+				serviceCounts[service]++
+			}
+			if banner := strings.TrimSpace(r.Banner); banner != "" {
+				banners = append(banners, banner)
+			}
+		case core.StateClosed:
+			summary.Closed++
+		case core.StateFiltered:
+			summary.Filtered++
+		}
+		if r.Duration > 0 {
+			if timed == 0 || r.Duration < summary.MinLatency {
+				summary.MinLatency = r.Duration
+			}
+			if r.Duration > summary.MaxLatency {
+				summary.MaxLatency = r.Duration
+			}
+			total += r.Duration
+			timed++
+		}
+		summary.Tags = mergeTags(summary.Tags, m.tagsFor(r))
+	}
+
+	sort.SliceStable(summary.Ports, func(i, j int) bool {
+		a, b := summary.Ports[i], summary.Ports[j]
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		return resultProtocol(a) < resultProtocol(b)
+	})
+	if timed > 0 {
+		summary.AvgLatency = total / time.Duration(timed)
+	}
+	summary.TopServices = topCounts(serviceCounts, 5)
+	summary.OSGuess = exporter.GuessOS(open, banners)
+	return summary
+}
+
+// openHostSummary opens the host summary for the host of the selected row.
+func (m *ScanUI) openHostSummary() tea.Cmd {
+	result, ok := m.selectedResult()
+	if !ok {
+		return nil
+	}
+	m.openModal(ModalHost)
+	m.summaryHost = result.Host
+	return nil
+}
+
+// handleHostSummaryKey scrolls the host summary.
+func (m *ScanUI) handleHostSummaryKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		m.scrollDetails(-1)
+	case "down", "j":
+		m.scrollDetails(1)
+	case "enter":
+		m.closeModal()
+	}
+	return true, true, nil
+}
+
+// renderHostSummaryModal renders the summary of the host the modal was
+// opened on: its names, state counts, services, latency and every port.
+func (m *ScanUI) renderHostSummaryModal() string {
+	s := m.summarizeHost(m.summaryHost)
+	var b strings.Builder
+
+	name := s.Host
+	if s.Hostname != "" {
+		name += " (" + s.Hostname + ")"
+	}
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Primary).
+		Render(i18n.T("ui.host_summary.title", name))
+	b.WriteString(title + "\n\n")
+
+	section := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Secondary)
+	muted := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	b.WriteString(section.Render(i18n.T("ui.host_summary.overview")) + "\n")
+	b.WriteString("  " + i18n.T("ui.host_summary.counts", len(s.Ports), s.Open, s.Closed, s.Filtered) + "\n")
+	if s.Hostname != "" {
+		b.WriteString("  " + i18n.T("ui.host_summary.rdns", s.Hostname) + "\n")
+	}
+	if s.OSGuess != "" {
+		b.WriteString("  " + i18n.T("ui.host_summary.os", s.OSGuess) + "\n")
+	}
+	if len(s.Tags) > 0 {
+		b.WriteString("  " + i18n.T("ui.host_summary.tags", strings.Join(s.Tags, ", ")) + "\n")
+	}
+	if len(s.Ports) > 0 && s.MaxLatency > 0 {
+		b.WriteString("  " + i18n.T("ui.host_summary.latency",
+			s.MinLatency.Milliseconds(), s.AvgLatency.Milliseconds(), s.MaxLatency.Milliseconds()) + "\n")
+	}
+	if m.results.Evicted() > 0 {
+		b.WriteString(muted.Render("  "+i18n.T("ui.host_summary.evicted")) + "\n")
+	}
+	b.WriteString("\n")
+
+	if len(s.TopServices) > 0 {
+		b.WriteString(section.Render(i18n.T("ui.host_summary.services")) + "\n")
+		for _, svc := range s.TopServices {
+			b.WriteString(fmt.Sprintf("  %-16s %d\n", svc.Name, svc.Count))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(section.Render(i18n.T("ui.host_summary.ports")) + "\n")
+	for _, r := range s.Ports {
+		port := fmt.Sprintf("%d/%s", r.Port, resultProtocol(r))
+		line := fmt.Sprintf("  %-11s %-9s %-16s %dms", port, r.State, serviceName(r), r.Duration.Milliseconds())
+		if r.State != core.StateOpen {
+			line = muted.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Render(i18n.T("ui.host_summary.keys"))
+	b.WriteString("\n" + instructions)
+
+	return m.scrollModalContent(b.String(), modalScrollLines)
+}
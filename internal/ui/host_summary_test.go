@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+func TestSummarizeHostUsesAllBufferedResults(t *testing.T) {
+	ui := NewScanUI(&config.Config{}, 5, make(chan core.Event), false)
+	ui.recordResults([]core.ResultEvent{
+		{Host: "10.0.0.5", Hostname: "web.example.net", Port: 443, State: core.StateOpen, Duration: 30 * time.Millisecond},
+		{Host: "10.0.0.5", Port: 22, State: core.StateOpen, Banner: "SSH-2.0-OpenSSH_9.6p1 Ubuntu-3ubuntu13", Duration: 10 * time.Millisecond},
+		{Host: "10.0.0.5", Port: 25, State: core.StateFiltered, Duration: 50 * time.Millisecond},
+		{Host: "10.0.0.5", Port: 53, Protocol: "udp", State: core.StateClosed},
+		{Host: "10.0.0.9", Port: 80, State: core.StateOpen},
+	})
+	// Filters hide rows from the table, not from the summary.
+	ui.toggleOpenOnly()
+
+	s := ui.summarizeHost("10.0.0.5")
+	if len(s.Ports) != 4 || s.Open != 2 || s.Closed != 1 || s.Filtered != 1 {
+		t.Fatalf("summary = %+v", s)
+	}
+	if s.Ports[0].Port != 22 || s.Ports[3].Port != 443 {
+		t.Errorf("ports should be in port order: %v, %v", s.Ports[0].Port, s.Ports[3].Port)
+	}
+	if s.Hostname != "web.example.net" || s.OSGuess != "Linux (Ubuntu)" {
+		t.Errorf("hostname %q, OS guess %q", s.Hostname, s.OSGuess)
+	}
+	if s.MinLatency != 10*time.Millisecond || s.AvgLatency != 30*time.Millisecond || s.MaxLatency != 50*time.Millisecond {
+		t.Errorf("latency min %v avg %v max %v", s.MinLatency, s.AvgLatency, s.MaxLatency)
+	}
+	if len(s.TopServices) != 2 {
+		t.Errorf("top services = %+v", s.TopServices)
+	}
+}
+
+func TestHostSummaryModalOpensOnSelectedHost(t *testing.T) {
+	ui := NewScanUI(&config.Config{}, 2, make(chan core.Event), false)
+	ui.recordResults([]core.ResultEvent{
+		{Host: "10.0.0.5", Port: 22, State: core.StateOpen},
+		{Host: "10.0.0.5", Port: 8443, State: core.StateClosed},
+	})
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+	if !ui.modalState.IsActive || ui.modalState.Type != ModalHost || ui.summaryHost != "10.0.0.5" {
+		t.Fatalf("h should open the summary of 10.0.0.5; modal %v, host %q", ui.modalState.Type, ui.summaryHost)
+	}
+	view := ui.renderHostSummaryModal()
+	for range 5 {
+		ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	view += ui.renderHostSummaryModal()
+	for _, want := range []string{"10.0.0.5", "2 ports scanned: 1 open, 1 closed, 0 filtered", "8443/tcp"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("summary lacks %q:\n%s", want, view)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+// DashboardTab selects the panel shown beside the results table.
+type DashboardTab int
+
+const (
+	// DashboardTabStats shows live scan statistics.
+	DashboardTabStats DashboardTab = iota
+	// DashboardTabInventory shows the per-host asset inventory.
+	DashboardTabInventory
+	// DashboardTabHosts shows the hosts still being scanned.
+	DashboardTabHosts
+)
+
+// dashboardTabNames labels the tabs in display order.
+var dashboardTabNames = []string{"📊 Stats", "🖥  Inventory", "⏳ Hosts"}
+
+// nextDashboardTab switches the dashboard panel to the next tab.
+func (m *ScanUI) nextDashboardTab() {
+	m.dashboardTab = (m.dashboardTab + 1) % DashboardTab(len(dashboardTabNames))
+}
+
+// recordInventory adds a result to the live per-host inventory.
+func (m *ScanUI) recordInventory(r core.ResultEvent) {
+	m.inventory.Add(r, time.Now())
+}
+
+// renderDashboardTabs renders the tab bar above the dashboard panel.
+func (m *ScanUI) renderDashboardTabs() string {
+	active := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Primary).Underline(true)
+	inactive := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	tabs := make([]string, len(dashboardTabNames))
+	for i, name := range dashboardTabNames {
+		if DashboardTab(i) == m.dashboardTab {
+			tabs[i] = active.Render(name)
+		} else {
+			tabs[i] = inactive.Render(name)
+		}
+	}
+	return strings.Join(tabs, "  ") + inactive.Render("  (Tab)")
+}
+
+// renderInventoryPanel lists responsive hosts with their open ports and OS
+// guess, as many as fit in height lines.
+func (m *ScanUI) renderInventoryPanel(width, height int) string {
+	hosts := m.inventory.Hosts()
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Primary).
+		Render(fmt.Sprintf("🖥  Asset Inventory (%d hosts)", len(hosts)))
+	b.WriteString(title + "\n\n")
+	if len(hosts) == 0 {
+		b.WriteString("  No responsive hosts yet\n")
+		return b.String()
+	}
+
+	hostStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Secondary)
+	muted := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	// Each host takes two lines; keep one for the overflow note.
+	fit := max(1, (height-3)/2)
+	for i, h := range hosts {
+		if i == fit && len(hosts) > fit {
+			b.WriteString(muted.Render(fmt.Sprintf("  … %d more hosts (--output inventory exports all)", len(hosts)-fit)) + "\n")
+			break
+		}
+		name := h.Host
+		if h.Hostname != "" {
+			name += " (" + h.Hostname + ")"
+		}
+		summary := fmt.Sprintf("%d open", len(h.OpenPorts))
+		if h.OSGuess != "" {
+			summary += " • " + h.OSGuess
+		}
+		if device := deviceLabel(h.DeviceName, h.DeviceType); device != "" {
+			summary += " • " + device
+		}
+		b.WriteString(hostStyle.Render(truncateToWidth(name, width)) + " " + muted.Render(summary) + "\n")
+		ports := "no open ports"
+		if len(h.OpenPorts) > 0 {
+			ports = strings.Join(h.OpenPorts, " ")
+		}
+		b.WriteString("  " + truncateToWidth(ports, width-2) + "\n")
+	}
+	return b.String()
+}
+
+// deviceLabel joins the name and type local discovery found for a host,
+// e.g. "living-room (MediaRenderer)".
+func deviceLabel(name, kind string) string {
+	switch {
+	case name != "" && kind != "":
+		return name + " (" + kind + ")"
+	case name != "":
+		return name
+	default:
+		return kind
+	}
+}
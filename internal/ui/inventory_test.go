@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+func TestScanUI_DashboardInventoryTab(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.recordResults([]core.ResultEvent{
+		{Host: "10.0.0.1", Port: 22, State: core.StateOpen, Banner: "SSH-2.0-OpenSSH_8.9p1 Debian-3"},
+		{Host: "10.0.0.1", Port: 443, State: core.StateOpen, DeviceName: "gw", DeviceType: "Router"},
+		{Host: "10.0.0.4", Port: 80, State: core.StateFiltered},
+	})
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+
+	ui.handleKeyMsg(tab)
+	if ui.dashboardTab != DashboardTabStats {
+		t.Error("Tab should do nothing while the dashboard is hidden")
+	}
+
+	ui.showDashboard = true
+	ui.handleKeyMsg(tab)
+	if ui.dashboardTab != DashboardTabInventory {
+		t.Fatal("Tab should switch to the inventory tab")
+	}
+	view := ui.renderDashboardView()
+	for _, want := range []string{"Asset Inventory (1 hosts)", "10.0.0.1", "Linux (Debian)", "gw (Router)", "22/tcp 443/tcp"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("inventory tab missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(ui.renderInventoryPanel(60, 20), "10.0.0.4") {
+		t.Error("filtered-only hosts should not be listed")
+	}
+
+	ui.handleKeyMsg(tab)
+	if ui.dashboardTab != DashboardTabHosts {
+		t.Error("Tab should switch to the hosts tab")
+	}
+	ui.handleKeyMsg(tab)
+	if ui.dashboardTab != DashboardTabStats {
+		t.Error("Tab should cycle back to the stats tab")
+	}
+}
+
+func TestScanUI_InventoryPanelOverflow(t *testing.T) {
+	ui := newSearchTestUI(t)
+	for _, host := range []string{"10.0.1.1", "10.0.1.2", "10.0.1.3", "10.0.1.4"} {
+		ui.recordInventory(core.ResultEvent{Host: host, Port: 80, State: core.StateOpen})
+	}
+
+	panel := ui.renderInventoryPanel(40, 7)
+	if strings.Contains(panel, "10.0.1.3") || !strings.Contains(panel, "2 more hosts") {
+		t.Errorf("panel should list two hosts and note the rest:\n%s", panel)
+	}
+}
@@ -2,28 +2,128 @@ package ui
 
 import (
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
 )
 
 type columnSpec struct {
-	title  string
-	weight int
-	min    int
+	name     string // name of the column in ui.columns
+	titleKey string // i18n key of the column title
+	weight   int
+	min      int
 }
 
+// columnID identifies a table column and indexes defaultColumnSpecs.
+type columnID int
+
+const (
+	hostColumn columnID = iota
+	portColumn
+	protocolColumn
+	stateColumn
+	serviceColumn
+	bannerColumn
+	latencyColumn
+	// geoColumn is the location column, which has zero width until
+	// toggled on.
+	geoColumn
+	rdnsColumn
+)
+
 var defaultColumnSpecs = []columnSpec{
-	{"Host", ColumnWeightHost, ColumnMinWidthHost},
-	{"Port", ColumnWeightPort, ColumnMinWidthPort},
-	{"Protocol", ColumnWeightProtocol, ColumnMinWidthProtocol},
-	{"State", ColumnWeightState, ColumnMinWidthState},
-	{"Service", ColumnWeightService, ColumnMinWidthService},
-	{"Banner", ColumnWeightBanner, ColumnMinWidthBanner},
-	{"Latency", ColumnWeightLatency, ColumnMinWidthLatency},
+	{"host", "ui.columns.host", ColumnWeightHost, ColumnMinWidthHost},
+	{"port", "ui.columns.port", ColumnWeightPort, ColumnMinWidthPort},
+	{"protocol", "ui.columns.protocol", ColumnWeightProtocol, ColumnMinWidthProtocol},
+	{"state", "ui.columns.state", ColumnWeightState, ColumnMinWidthState},
+	{"service", "ui.columns.service", ColumnWeightService, ColumnMinWidthService},
+	{"banner", "ui.columns.banner", ColumnWeightBanner, ColumnMinWidthBanner},
+	{"latency", "ui.columns.latency", ColumnWeightLatency, ColumnMinWidthLatency},
+	{"geo", "ui.columns.geo", ColumnWeightGeo, ColumnMinWidthGeo},
+	{"rdns", "ui.columns.rdns", ColumnWeightRDNS, ColumnMinWidthRDNS},
 }
 
-var columnPriorityOrder = []int{0, 5, 4, 6, 1, 2, 3}
+var columnPriorityOrder = []columnID{
+	hostColumn, bannerColumn, serviceColumn, geoColumn, rdnsColumn,
+	latencyColumn, portColumn, protocolColumn, stateColumn,
+}
 
 const tableHorizontalFrame = 4
 
+// tableCellPadding is the horizontal padding the table styles add around
+// every cell, which the column widths leave room for.
+const tableCellPadding = 2
+
+// tableLayout is the order of the table's columns and which of them are
+// hidden. Hidden columns stay in the table with zero width. The location
+// column is shown or hidden by showGeo instead.
+type tableLayout struct {
+	order  []columnID
+	hidden map[columnID]bool
+}
+
+// defaultTableLayout shows every column but the reverse DNS name, in
+// their natural order.
+func defaultTableLayout() tableLayout {
+	order := make([]columnID, len(defaultColumnSpecs))
+	for i := range order {
+		order[i] = columnID(i)
+	}
+	return tableLayout{order: order, hidden: map[columnID]bool{rdnsColumn: true}}
+}
+
+// newTableLayout builds the layout ui.columns describes: the named columns
+// in order, followed by the others hidden. It also reports whether the
+// location column is named. Unknown names are skipped; an empty list
+// gives the default layout.
+func newTableLayout(names []string) (tableLayout, bool) {
+	if len(names) == 0 {
+		return defaultTableLayout(), false
+	}
+	layout := tableLayout{hidden: make(map[columnID]bool)}
+	listed := make(map[columnID]bool)
+	for _, name := range names {
+		if id, ok := columnByName(name); ok && !listed[id] {
+			listed[id] = true
+			layout.order = append(layout.order, id)
+		}
+	}
+	for i := range defaultColumnSpecs {
+		if id := columnID(i); !listed[id] {
+			layout.order = append(layout.order, id)
+			layout.hidden[id] = true
+		}
+	}
+	return layout, listed[geoColumn]
+}
+
+// columnByName returns the column ui.columns calls name.
+func columnByName(name string) (columnID, bool) {
+	for i, spec := range defaultColumnSpecs {
+		if spec.name == name {
+			return columnID(i), true
+		}
+	}
+	return 0, false
+}
+
+// shown reports whether a column is drawn.
+func (l tableLayout) shown(id columnID, showGeo bool) bool {
+	if id == geoColumn {
+		return showGeo
+	}
+	return !l.hidden[id]
+}
+
+// names returns the drawn columns in order, as ui.columns lists them.
+func (l tableLayout) names(showGeo bool) []string {
+	var names []string
+	for _, id := range l.order {
+		if l.shown(id, showGeo) {
+			names = append(names, defaultColumnSpecs[id].name)
+		}
+	}
+	return names
+}
+
 func (m *ScanUI) applyTableGeometry() {
 	if m == nil {
 		return
@@ -33,7 +133,7 @@ func (m *ScanUI) applyTableGeometry() {
 	}
 
 	contentWidth := m.tableViewportWidth()
-	columns := calculateColumnWidths(contentWidth)
+	columns := calculateColumnWidths(contentWidth, m.layout, m.showGeo)
 	m.table.SetColumns(columns)
 	m.table.SetWidth(contentWidth)
 
@@ -42,26 +142,31 @@ func (m *ScanUI) applyTableGeometry() {
 	m.table.SetHeight(availableRows)
 }
 
-// calculateColumnWidths returns table columns sized according to the configured
-// weights while respecting minimum widths. The total width will never be less
-// than the sum of minimum widths, ensuring the table stays legible on narrow
-// terminals.
-func calculateColumnWidths(totalWidth int) []table.Column {
+// calculateColumnWidths returns table columns, in the layout's order,
+// sized according to the configured weights while respecting minimum
+// widths. The total width will never be less than the sum of minimum
+// widths, ensuring the table stays legible on narrow terminals. Columns
+// the layout hides are given zero width, and so not drawn; the location
+// column is hidden unless showGeo is set.
+func calculateColumnWidths(totalWidth int, layout tableLayout, showGeo bool) []table.Column {
 	if totalWidth <= 0 {
-		totalWidth = sumMinWidths()
+		totalWidth = sumMinWidths(layout, showGeo)
 	}
 
-	minWidth := sumMinWidths()
+	minWidth := sumMinWidths(layout, showGeo)
 	if totalWidth < minWidth {
 		totalWidth = minWidth
 	}
 
 	remaining := totalWidth - minWidth
-	weightSum := sumWeights()
+	weightSum := sumWeights(layout, showGeo)
 	columnWidths := make([]int, len(defaultColumnSpecs))
 	extraAssigned := 0
 
 	for i, spec := range defaultColumnSpecs {
+		if !layout.shown(columnID(i), showGeo) {
+			continue
+		}
 		columnWidths[i] = spec.min
 		if remaining <= 0 || weightSum == 0 {
 			continue
@@ -73,33 +178,44 @@ func calculateColumnWidths(totalWidth int) []table.Column {
 	}
 
 	leftover := remaining - extraAssigned
-	for _, idx := range columnPriorityOrder {
+	for _, id := range columnPriorityOrder {
 		if leftover <= 0 {
 			break
 		}
-		columnWidths[idx]++
+		if !layout.shown(id, showGeo) {
+			continue
+		}
+		columnWidths[id]++
 		leftover--
 	}
 
-	columns := make([]table.Column, len(defaultColumnSpecs))
-	for i, spec := range defaultColumnSpecs {
-		columns[i] = table.Column{Title: spec.title, Width: columnWidths[i]}
+	columns := make([]table.Column, len(layout.order))
+	for i, id := range layout.order {
+		columns[i] = table.Column{Title: i18n.T(defaultColumnSpecs[id].titleKey), Width: columnWidths[id]}
 	}
 
 	return columns
 }
 
-func sumMinWidths() int {
+// sumMinWidths returns the narrowest the table can be: every visible
+// column at its minimum width, with its padding.
+func sumMinWidths(layout tableLayout, showGeo bool) int {
 	total := 0
-	for _, spec := range defaultColumnSpecs {
-		total += spec.min
+	for i, spec := range defaultColumnSpecs {
+		if !layout.shown(columnID(i), showGeo) {
+			continue
+		}
+		total += spec.min + tableCellPadding
 	}
 	return total
 }
 
-func sumWeights() int {
+func sumWeights(layout tableLayout, showGeo bool) int {
 	total := 0
-	for _, spec := range defaultColumnSpecs {
+	for i, spec := range defaultColumnSpecs {
+		if !layout.shown(columnID(i), showGeo) {
+			continue
+		}
 		total += spec.weight
 	}
 	return total
@@ -109,7 +225,7 @@ func sumWeights() int {
 // viewport. The indicators flag should reflect whether sort or filter badges
 // will be shown.
 func tableOverheadLines(scanning bool, showIndicators bool) int {
-	overhead := HeightBreadcrumb + HeightHeader + HeightStatus + HeightSpacing + HeightFooter
+	overhead := HeightBreadcrumb + HeightHeader + HeightStatus + HeightSpacing + HeightSummary + HeightFooter
 	if scanning {
 		overhead += HeightProgress
 	}
@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/muesli/reflow/truncate"
+)
+
+// cursorMarker tags the selected row when locating it on screen. It is a
+// private-use rune, so it never appears in scan results.
+const cursorMarker = "\uE000"
+
+// handleMouseMsg routes mouse events: the wheel scrolls the table or the
+// details modal, and a left click selects a row, picks a modal option or
+// presses a modal's close button.
+func (m *ScanUI) handleMouseMsg(msg tea.MouseMsg) tea.Cmd {
+	if msg.Action != tea.MouseActionPress || m.searchActive || m.showHelp {
+		return nil
+	}
+	if m.modalState.IsActive {
+		return m.handleModalMouse(msg)
+	}
+	if m.viewState != UIViewMain {
+		return nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.table.MoveUp(MouseWheelLines)
+	case tea.MouseButtonWheelDown:
+		m.table.MoveDown(MouseWheelLines)
+	case tea.MouseButtonLeft:
+		row, ok := m.tableRowAt(msg.X, msg.Y)
+		if !ok {
+			return nil
+		}
+		// A click on the row already selected opens it, like Enter.
+		if cursor := m.table.Cursor(); row == cursor {
+			m.openSelected()
+		} else if row > cursor {
+			m.table.MoveDown(row - cursor)
+		} else {
+			m.table.MoveUp(cursor - row)
+		}
+	}
+	return nil
+}
+
+// handleModalMouse scrolls the details and host summary modals and applies clicks on the open
+// modal's options and close button.
+func (m *ScanUI) handleModalMouse(msg tea.MouseMsg) tea.Cmd {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		if m.modalState.Type == ModalDetails || m.modalState.Type == ModalHost {
+			m.scrollDetails(-MouseWheelLines)
+		}
+	case tea.MouseButtonWheelDown:
+		if m.modalState.Type == ModalDetails || m.modalState.Type == ModalHost {
+			m.scrollDetails(MouseWheelLines)
+		}
+	case tea.MouseButtonLeft:
+		if m.onModalCloseButton(msg.X, msg.Y) {
+			m.closeModal()
+			return nil
+		}
+		if option, ok := m.modalOptionAt(msg.X, msg.Y); ok {
+			return m.chooseModalOption(option)
+		}
+	}
+	return nil
+}
+
+// chooseModalOption applies a clicked menu entry as if it had been
+// highlighted and confirmed. Export formats are only selected, since the
+// destination still has to be confirmed, and a clicked column is shown or
+// hidden. Themes are listed from the first one in view. A click on a filter
+// focuses its field, and a second one on the state steps through its choices.
+func (m *ScanUI) chooseModalOption(option int) tea.Cmd {
+	switch m.modalState.Type {
+	case ModalExport:
+		m.cycleExportFormat(option - m.exportState.FormatIndex)
+		return nil
+	case ModalColumns:
+		m.modalState.Cursor = option
+		return m.toggleColumn(option)
+	case ModalTheme:
+		return m.chooseTheme(m.themePicker.first + option)
+	case ModalFilter:
+		if filterField(option) == filterFieldState && m.modalState.Cursor == option {
+			m.cycleStateFilter(1)
+		}
+		return m.focusFilterField(filterField(option))
+	}
+	m.modalState.Cursor = option
+	_, _, cmd := m.handleModalKey(tea.KeyMsg{Type: tea.KeyEnter})
+	return cmd
+}
+
+// modalOptionCount returns how many clickable options the open modal lists.
+func (m *ScanUI) modalOptionCount() int {
+	switch m.modalState.Type {
+	case ModalSort:
+		return len(sortOptions)
+	case ModalBulk:
+		return len(bulkActionLabels)
+	case ModalQuit:
+		return len(quitChoiceLabels)
+	case ModalExport:
+		return len(exporter.Formats)
+	case ModalColumns:
+		return len(m.layout.order)
+	case ModalPalette:
+		return m.paletteRows()
+	case ModalTheme:
+		return min(len(m.themePicker.names), ThemePickerRows)
+	case ModalFilter:
+		return int(filterFieldCount)
+	default:
+		return 0
+	}
+}
+
+// modalContentOrigin returns the screen position of the modal's first
+// content column and line, inside its border and padding.
+func (m *ScanUI) modalContentOrigin() (x, y int) {
+	style := m.theme.ModalStyle(ModalBorderPadding)
+	x = m.modalState.Position.X + style.GetBorderLeftSize() + style.GetPaddingLeft()
+	y = m.modalState.Position.Y + style.GetBorderTopSize() + style.GetPaddingTop()
+	return x, y
+}
+
+// insideModal reports whether a screen position falls on the modal.
+func (m *ScanUI) insideModal(x, y int) bool {
+	pos := m.modalState.Position
+	return x >= pos.X && x < pos.X+pos.Width && y >= pos.Y && y < pos.Y+pos.Height
+}
+
+// modalOptionAt returns the option drawn on screen line y of a menu modal.
+func (m *ScanUI) modalOptionAt(x, y int) (int, bool) {
+	if !m.insideModal(x, y) {
+		return 0, false
+	}
+	_, top := m.modalContentOrigin()
+	option := y - top - m.modalState.OptionsTop
+	if option < 0 || option >= m.modalOptionCount() {
+		return 0, false
+	}
+	return option, true
+}
+
+// onModalCloseButton reports whether a screen position falls on the close
+// button drawn at the top right of every modal's content.
+func (m *ScanUI) onModalCloseButton(x, y int) bool {
+	left, top := m.modalContentOrigin()
+	right := left + m.modalState.Position.Width - 2*ModalBorderPadding
+	return y == top && x < right && x >= right-lipgloss.Width(i18n.T("ui.modal.close"))
+}
+
+// withModalCloseButton draws the close button at the right end of the
+// first line of a modal's content, shortening that line if they collide.
+func (m *ScanUI) withModalCloseButton(content string, width int) string {
+	button := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Render(i18n.T("ui.modal.close"))
+	room := max(0, width-lipgloss.Width(button)-1)
+
+	first, rest, multiline := strings.Cut(content, "\n")
+	first = truncate.String(first, uint(room))
+	gap := max(1, width-lipgloss.Width(first)-lipgloss.Width(button))
+	first += strings.Repeat(" ", gap) + button
+	if !multiline {
+		return first
+	}
+	return first + "\n" + rest
+}
+
+// tableRowAt returns the result row drawn at a screen position, if any.
+func (m *ScanUI) tableRowAt(x, y int) (int, bool) {
+	top := strings.Count(m.renderAboveTable(), "\n")
+	if m.showDashboard && m.width >= DashboardMinWidth {
+		top = strings.Count(m.renderTopLines(), "\n")
+		if x >= m.dashboardLeftWidth() {
+			return 0, false
+		}
+	}
+
+	lines, cursorLine := m.markedTableView()
+	line := y - top
+	headerLines := len(lines) - m.table.Height()
+	if line < headerLines || line >= len(lines) || cursorLine < 0 {
+		return 0, false
+	}
+	row := m.table.Cursor() + line - cursorLine
+	if row < 0 || row >= m.rowCount() {
+		return 0, false
+	}
+	return row, true
+}
+
+// markedTableView renders the table with the selected row tagged and
+// returns its lines and the line the cursor is drawn on, or -1. The table
+// keeps its scroll offset to itself, so the cursor has to be found on
+// screen to map a click to a row.
+func (m *ScanUI) markedTableView() ([]string, int) {
+	probe := m.table
+	styles := tableStyles(m.theme)
+	styles.Selected = styles.Selected.Transform(func(s string) string {
+		return cursorMarker + s
+	})
+	probe.SetStyles(styles)
+
+	lines := strings.Split(probe.View(), "\n")
+	for i, line := range lines {
+		if strings.Contains(line, cursorMarker) {
+			return lines, i
+		}
+	}
+	return lines, -1
+}
@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+// newMouseTestUI returns a sized UI holding results for hosts 10.0.0.1
+// through 10.0.0.n.
+func newMouseTestUI(t *testing.T, n int) *ScanUI {
+	t.Helper()
+	ui := NewScanUI(&config.Config{}, n, make(chan core.Event), false)
+	ui.handleWindowSize(tea.WindowSizeMsg{Width: 100, Height: 30})
+	results := make([]core.ResultEvent, n)
+	for i := range results {
+		results[i] = core.ResultEvent{Host: fmt.Sprintf("10.0.0.%d", i+1), Port: 80, State: core.StateOpen}
+	}
+	ui.recordResults(results)
+	return ui
+}
+
+// locate returns the screen position of text in the rendered view.
+func locate(t *testing.T, ui *ScanUI, text string) (x, y int) {
+	t.Helper()
+	for y, line := range strings.Split(ui.View(), "\n") {
+		if i := strings.Index(line, text); i >= 0 {
+			return lipgloss.Width(line[:i]), y
+		}
+	}
+	t.Fatalf("%q not found in view:\n%s", text, ui.View())
+	return 0, 0
+}
+
+func click(ui *ScanUI, x, y int) {
+	ui.Update(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+}
+
+func wheel(ui *ScanUI, button tea.MouseButton) {
+	ui.Update(tea.MouseMsg{Action: tea.MouseActionPress, Button: button})
+}
+
+func TestMouseClickSelectsRowAndOpensDetails(t *testing.T) {
+	ui := newMouseTestUI(t, 40)
+
+	x, y := locate(t, ui, "10.0.0.4 ")
+	click(ui, x, y)
+	if got := ui.table.Cursor(); got != 3 {
+		t.Fatalf("cursor after clicking the fourth row = %d; want 3", got)
+	}
+	if ui.modalState.IsActive {
+		t.Fatal("the first click should only select the row")
+	}
+
+	// Scroll so the table's viewport no longer starts at the first row.
+	for range 10 {
+		wheel(ui, tea.MouseButtonWheelDown)
+	}
+	if got := ui.table.Cursor(); got != 3+10*MouseWheelLines {
+		t.Fatalf("cursor after scrolling = %d; want %d", got, 3+10*MouseWheelLines)
+	}
+	x, y = locate(t, ui, "10.0.0.30 ")
+	click(ui, x, y)
+	if got := ui.displayResults[ui.table.Cursor()].Host; got != "10.0.0.30" {
+		t.Fatalf("clicked 10.0.0.30 but selected %s", got)
+	}
+
+	click(ui, x, y)
+	if !ui.modalState.IsActive || ui.modalState.Type != ModalDetails {
+		t.Fatal("clicking the selected row should open its details")
+	}
+}
+
+func TestMouseClickOutsideTableIsIgnored(t *testing.T) {
+	ui := newMouseTestUI(t, 5)
+	ui.table.MoveDown(2)
+
+	click(ui, 0, 0)
+	if got := ui.table.Cursor(); got != 2 {
+		t.Errorf("clicking the breadcrumb moved the cursor to %d", got)
+	}
+}
+
+func TestMouseSortModalOptions(t *testing.T) {
+	ui := newMouseTestUI(t, 5)
+	ui.openModal(ModalSort)
+
+	x, y := locate(t, ui, "3. Host")
+	click(ui, x, y)
+	if ui.modalState.IsActive {
+		t.Error("clicking a sort option should close the modal")
+	}
+	if ui.sortState.Mode != SortByHost {
+		t.Errorf("sort mode = %v; want SortByHost", ui.sortState.Mode)
+	}
+}
+
+func TestMouseCloseButton(t *testing.T) {
+	for _, modal := range []ModalType{ModalSort, ModalDetails, ModalExport} {
+		ui := newMouseTestUI(t, 5)
+		ui.openModal(modal)
+
+		x, y := locate(t, ui, "[ Close ]")
+		click(ui, x+2, y)
+		if ui.modalState.IsActive {
+			t.Errorf("modal %d should close when its close button is clicked", modal)
+		}
+	}
+}
+
+func TestMouseWheelScrollsDetails(t *testing.T) {
+	ui := newMouseTestUI(t, 1)
+	ui.displayResults[0].Banner = strings.Repeat("line\n", 40)
+	ui.openModal(ModalDetails)
+	ui.View()
+
+	wheel(ui, tea.MouseButtonWheelDown)
+	if got := ui.modalState.ScrollPosition; got != MouseWheelLines {
+		t.Fatalf("scroll position = %d; want %d", got, MouseWheelLines)
+	}
+	wheel(ui, tea.MouseButtonWheelUp)
+	wheel(ui, tea.MouseButtonWheelUp)
+	if got := ui.modalState.ScrollPosition; got != 0 {
+		t.Errorf("scroll position = %d; want 0", got)
+	}
+}
+
+func TestMouseExportModalSelectsFormat(t *testing.T) {
+	ui := newMouseTestUI(t, 1)
+	ui.openExportModal()
+
+	x, y := locate(t, ui, "2. ")
+	click(ui, x, y)
+	if !ui.modalState.IsActive {
+		t.Fatal("picking a format should leave the export modal open")
+	}
+	if ui.exportState.FormatIndex != 1 {
+		t.Errorf("format index = %d; want 1", ui.exportState.FormatIndex)
+	}
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/lucchesi-sec/portscan/pkg/theme"
+)
+
+// NoteCharLimit caps the length of a result note.
+const NoteCharLimit = 256
+
+// newNoteInput creates the text input used to annotate a single result.
+func newNoteInput(t theme.Theme) textinput.Model {
+	input := textinput.New()
+	input.Prompt = i18n.T("ui.note.prompt")
+	input.Placeholder = i18n.T("ui.note.placeholder")
+	input.CharLimit = NoteCharLimit
+	input.PromptStyle = lipgloss.NewStyle().Foreground(t.Secondary).Bold(true)
+	return input
+}
+
+// openNoteModal prompts for a note on the result under the cursor,
+// starting from its current note.
+func (m *ScanUI) openNoteModal() tea.Cmd {
+	result, ok := m.selectedResult()
+	if !ok {
+		return nil
+	}
+	m.noteKey = selectionKey(result)
+	m.openModal(ModalNote)
+	m.noteInput.SetValue(m.notes[m.noteKey])
+	m.noteInput.CursorEnd()
+	return m.noteInput.Focus()
+}
+
+// handleNoteModalKey edits the note and saves it on Enter; an empty note
+// removes it.
+func (m *ScanUI) handleNoteModalKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return true, true, tea.Quit
+	case tea.KeyEnter:
+		note := strings.TrimSpace(m.noteInput.Value())
+		m.modalState.IsActive = false
+		m.noteInput.Blur()
+		if note == "" {
+			if _, ok := m.notes[m.noteKey]; !ok {
+				return true, true, nil
+			}
+			delete(m.notes, m.noteKey)
+			return true, true, m.showToast(i18n.T("ui.note.removed"), false)
+		}
+		m.notes[m.noteKey] = note
+		return true, true, m.showToast(i18n.T("ui.note.saved"), false)
+	}
+
+	var cmd tea.Cmd
+	m.noteInput, cmd = m.noteInput.Update(msg)
+	return true, true, cmd
+}
+
+// noteFor returns the note on a result, preferring one added in the UI.
+func (m *ScanUI) noteFor(r core.ResultEvent) string {
+	if note, ok := m.notes[selectionKey(r)]; ok {
+		return note
+	}
+	return r.Note
+}
+
+// resultAnnotations is a snapshot of the tags and notes added in the UI,
+// safe to apply from a background export.
+type resultAnnotations struct {
+	tags  map[string][]string
+	notes map[string]string
+}
+
+// annotations copies the current UI tags and notes.
+func (m *ScanUI) annotations() resultAnnotations {
+	a := resultAnnotations{
+		tags:  make(map[string][]string, len(m.tags)),
+		notes: make(map[string]string, len(m.notes)),
+	}
+	for key, tags := range m.tags {
+		a.tags[key] = append([]string(nil), tags...)
+	}
+	for key, note := range m.notes {
+		a.notes[key] = note
+	}
+	return a
+}
+
+// apply adds the UI tags and notes to results in place, so exports carry
+// them alongside any tags set for the whole scan.
+func (a resultAnnotations) apply(results []core.ResultEvent) {
+	for i := range results {
+		key := selectionKey(results[i])
+		results[i].Tags = mergeTags(results[i].Tags, a.tags[key])
+		if note, ok := a.notes[key]; ok {
+			results[i].Note = note
+		}
+	}
+}
+
+// mergeTags returns base followed by the extra tags it lacks, without
+// modifying base.
+func mergeTags(base, extra []string) []string {
+	if len(extra) == 0 {
+		return base
+	}
+	merged := append([]string(nil), base...)
+	for _, tag := range extra {
+		if !containsString(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
+// renderNoteModal renders the note prompt for the result under the cursor.
+func (m *ScanUI) renderNoteModal() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Primary).
+		Width(40).
+		Render(i18n.T("ui.note.title", m.noteKey))
+	b.WriteString(title + "\n\n")
+	b.WriteString(m.noteInput.View() + "\n")
+
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Render(i18n.T("ui.note.keys"))
+	b.WriteString("\n" + instructions)
+
+	return b.String()
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+var noteKeyMsg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}}
+
+func TestScanUI_NoteOnCursorRow(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.table.SetCursor(1)
+
+	ui.handleKeyMsg(noteKeyMsg)
+	if !ui.modalState.IsActive || ui.modalState.Type != ModalNote {
+		t.Fatal("'t' should open the note prompt")
+	}
+	// The prompt captures keys that are bindings in the main view.
+	typeRunes(ui, "expected web? yes")
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if ui.modalState.IsActive {
+		t.Error("Enter should close the note prompt")
+	}
+	if got := ui.noteFor(ui.displayResults[1]); got != "expected web? yes" {
+		t.Errorf("note = %q", got)
+	}
+	if got := ui.noteFor(ui.displayResults[0]); got != "" {
+		t.Errorf("other rows should have no note, got %q", got)
+	}
+
+	// Reopening starts from the saved note; clearing it removes the note.
+	ui.handleKeyMsg(noteKeyMsg)
+	if ui.noteInput.Value() != "expected web? yes" {
+		t.Errorf("prompt = %q, want the saved note", ui.noteInput.Value())
+	}
+	ui.noteInput.SetValue("  ")
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := ui.noteFor(ui.displayResults[1]); got != "" {
+		t.Errorf("cleared note = %q", got)
+	}
+}
+
+func TestScanUI_NoteFromDetails(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.table.SetCursor(0)
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if ui.modalState.Type != ModalDetails {
+		t.Fatal("Enter should open the details modal")
+	}
+
+	ui.handleKeyMsg(noteKeyMsg)
+	if ui.modalState.Type != ModalNote {
+		t.Fatal("'t' in details should open the note prompt")
+	}
+	typeRunes(ui, "bastion")
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+
+	ui.openModal(ModalDetails)
+	if view := ui.renderDetailsModal(); !strings.Contains(view, "Note: bastion") {
+		t.Errorf("details should show the note:\n%s", view)
+	}
+}
+
+func TestScanUI_ExportIncludesTagsAndNotes(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.selection.Mark(ui.displayResults[0])
+	ui.tagSelected("triage")
+	ui.notes[selectionKey(ui.displayResults[0])] = "owned by ops"
+	ui.results.Append(core.ResultEvent{Host: "10.0.0.9", Port: 25, State: core.StateOpen, Tags: []string{"env=prod"}})
+	ui.updateTable()
+
+	path := filepath.Join(t.TempDir(), "annotated.json")
+	ui.openExportModal()
+	ui.exportState.PathInput.SetValue(path)
+	msg, ok := ui.startExport()().(exportFinishedMsg)
+	if !ok || msg.err != nil {
+		t.Fatalf("export = %+v", msg)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{`"note":"owned by ops"`, `"tags":["triage"]`, `"tags":["env=prod"]`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("export missing %s:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, `"note"`) != 1 {
+		t.Errorf("only the annotated result should carry a note:\n%s", out)
+	}
+	if tags := ui.results.Items()[0].Tags; len(tags) != 0 {
+		t.Errorf("export must not modify buffered results, got tags %v", tags)
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	base := []string{"env=prod"}
+	got := mergeTags(base, []string{"triage", "env=prod"})
+	if len(got) != 2 || got[0] != "env=prod" || got[1] != "triage" {
+		t.Errorf("mergeTags() = %q", got)
+	}
+	if len(base) != 1 {
+		t.Error("mergeTags must not modify base")
+	}
+	if got := mergeTags(base, nil); len(got) != 1 {
+		t.Errorf("mergeTags(base, nil) = %q", got)
+	}
+}
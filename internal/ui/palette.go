@@ -0,0 +1,362 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/internal/ui/commands"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/lucchesi-sec/portscan/pkg/theme"
+)
+
+// PaletteMaxResults caps how many matching commands the palette lists.
+const PaletteMaxResults = 10
+
+// paletteActions runs each palette command against the UI, keyed by
+// command ID. Commands without an entry are left out of the palette.
+var paletteActions = map[string]func(m *ScanUI) tea.Cmd{
+	"nav-up":        func(m *ScanUI) tea.Cmd { m.table.MoveUp(1); return nil },
+	"nav-down":      func(m *ScanUI) tea.Cmd { m.table.MoveDown(1); return nil },
+	"nav-top":       func(m *ScanUI) tea.Cmd { m.table.GotoTop(); return nil },
+	"nav-bottom":    func(m *ScanUI) tea.Cmd { m.table.GotoBottom(); return nil },
+	"nav-page-up":   func(m *ScanUI) tea.Cmd { m.table.MoveUp(PageScrollLines); return nil },
+	"nav-page-down": func(m *ScanUI) tea.Cmd { m.table.MoveDown(PageScrollLines); return nil },
+	"action-pause":  func(m *ScanUI) tea.Cmd { m.togglePause(); return nil },
+	"action-sort":   func(m *ScanUI) tea.Cmd { m.openModal(ModalSort); return nil },
+	"action-filter": func(m *ScanUI) tea.Cmd { return m.openFilterModal() },
+	"action-reset-filters": func(m *ScanUI) tea.Cmd {
+		m.resetFilters()
+		return nil
+	},
+	"action-toggle-open-only": func(m *ScanUI) tea.Cmd {
+		m.toggleOpenOnly()
+		return nil
+	},
+	"action-search":       func(m *ScanUI) tea.Cmd { return m.startSearch() },
+	"action-host-summary": func(m *ScanUI) tea.Cmd { return m.openHostSummary() },
+	"action-toggle-dashboard": func(m *ScanUI) tea.Cmd {
+		m.toggleDashboard()
+		return nil
+	},
+	"action-view-details": func(m *ScanUI) tea.Cmd {
+		m.openSelected()
+		return nil
+	},
+	"view-columns": func(m *ScanUI) tea.Cmd { return m.openColumnsModal() },
+	"view-theme":   func(m *ScanUI) tea.Cmd { return m.openThemeModal() },
+	"view-group":   func(m *ScanUI) tea.Cmd { return m.toggleGrouping() },
+	"view-help":    func(m *ScanUI) tea.Cmd { m.toggleHelp(); return nil },
+	"view-clear":   func(m *ScanUI) tea.Cmd { return tea.ClearScreen },
+	"view-quit":    func(m *ScanUI) tea.Cmd { return m.requestQuit() },
+}
+
+// paletteArg runs a palette command that asks for an argument.
+type paletteArg struct {
+	initial func(m *ScanUI) string   // pre-fills the argument; optional
+	options func(m *ScanUI) []string // arguments to pick from; optional
+	run     func(m *ScanUI, arg string) tea.Cmd
+}
+
+// paletteArgActions runs the palette commands that ask for an argument,
+// keyed by command ID.
+var paletteArgActions = map[string]paletteArg{
+	"export-csv": {
+		initial: func(m *ScanUI) string {
+			return defaultExportPath(exporter.FormatCSV, time.Now()) + exporter.CompressionExtension(m.config.Compress)
+		},
+		run: func(m *ScanUI, arg string) tea.Cmd {
+			path := strings.TrimSpace(arg)
+			if path == "" {
+				return m.showToast(i18n.T("ui.export.no_path"), true)
+			}
+			m.exportState.SelectedOnly = false
+			return m.exportTo(path, exporter.FormatCSV)
+		},
+	},
+	"theme-switch": {
+		options: func(m *ScanUI) []string { return theme.Names() },
+		run:     func(m *ScanUI, arg string) tea.Cmd { return m.switchTheme(arg) },
+	},
+	"rate-set": {
+		initial: func(m *ScanUI) string {
+			if m.targetRate == 0 {
+				return ""
+			}
+			return strconv.Itoa(m.targetRate)
+		},
+		run: func(m *ScanUI, arg string) tea.Cmd { return m.setRateArg(arg) },
+	},
+	"snapshot-save": {
+		initial: func(m *ScanUI) string { return defaultSnapshotPath(time.Now()) },
+		run:     func(m *ScanUI, arg string) tea.Cmd { return m.saveSnapshot(arg) },
+	},
+	"preset-save": {
+		options: func(m *ScanUI) []string { return m.presetNames() },
+		run:     func(m *ScanUI, arg string) tea.Cmd { return m.savePreset(arg) },
+	},
+	"filter-host": {
+		initial: func(m *ScanUI) string { return m.filterState.HostFilter },
+		run: func(m *ScanUI, arg string) tea.Cmd {
+			if err := m.filterState.SetHostFilter(strings.TrimSpace(arg)); err != nil {
+				return m.showToast(i18n.T("ui.filter_modal.invalid_pattern", err), true)
+			}
+			m.updateTable()
+			return nil
+		},
+	},
+}
+
+// paletteState holds the command palette's query and the commands that
+// match it. Once a command that asks for an argument is chosen, the input
+// holds the argument instead and options the suggestions matching it.
+type paletteState struct {
+	input    textinput.Model
+	registry *commands.Registry
+	matches  []commands.MatchResult
+	pending  *commands.Command
+	options  []string
+}
+
+// newPaletteState registers the default commands the UI can run.
+func newPaletteState(t theme.Theme) paletteState {
+	input := textinput.New()
+	input.Prompt = i18n.T("ui.palette.prompt")
+	input.Placeholder = i18n.T("ui.palette.placeholder")
+	input.PromptStyle = lipgloss.NewStyle().Foreground(t.Secondary).Bold(true)
+
+	registry := commands.NewRegistry()
+	for _, cmd := range commands.DefaultCommands() {
+		if cmd.ArgPrompt != "" {
+			arg, ok := paletteArgActions[cmd.ID]
+			if !ok {
+				continue
+			}
+			cmd.UIArgAction = func(model interface{}, value string) tea.Cmd {
+				return arg.run(model.(*ScanUI), value)
+			}
+			registry.AddCommand(cmd)
+			continue
+		}
+		action, ok := paletteActions[cmd.ID]
+		if !ok {
+			continue
+		}
+		cmd.UIAction = func(model interface{}) tea.Cmd {
+			return action(model.(*ScanUI))
+		}
+		registry.AddCommand(cmd)
+	}
+	return paletteState{input: input, registry: registry}
+}
+
+// openPalette opens the command palette with an empty query.
+func (m *ScanUI) openPalette() tea.Cmd {
+	m.openModal(ModalPalette)
+	m.palette.pending = nil
+	m.palette.input.Prompt = i18n.T("ui.palette.prompt")
+	m.palette.input.Placeholder = i18n.T("ui.palette.placeholder")
+	m.palette.input.Reset()
+	m.refreshPaletteMatches()
+	return m.palette.input.Focus()
+}
+
+// askPaletteArg switches the palette to prompting for the argument of cmd.
+func (m *ScanUI) askPaletteArg(cmd commands.Command) {
+	m.palette.pending = &cmd
+	m.palette.input.Prompt = cmd.ArgPrompt + ": "
+	m.palette.input.Placeholder = ""
+	m.palette.input.Reset()
+	if arg := paletteArgActions[cmd.ID]; arg.initial != nil {
+		m.palette.input.SetValue(arg.initial(m))
+		m.palette.input.CursorEnd()
+	}
+	m.modalState.Cursor = 0
+	m.refreshPaletteOptions()
+}
+
+// refreshPaletteOptions lists the suggested arguments containing the text
+// typed so far.
+func (m *ScanUI) refreshPaletteOptions() {
+	m.palette.options = nil
+	arg := paletteArgActions[m.palette.pending.ID]
+	if arg.options == nil {
+		return
+	}
+	typed := strings.ToLower(strings.TrimSpace(m.palette.input.Value()))
+	for _, option := range arg.options(m) {
+		if strings.Contains(strings.ToLower(option), typed) {
+			m.palette.options = append(m.palette.options, option)
+		}
+	}
+	if len(m.palette.options) > PaletteMaxResults {
+		m.palette.options = m.palette.options[:PaletteMaxResults]
+	}
+	m.modalState.Cursor = min(m.modalState.Cursor, max(0, len(m.palette.options)-1))
+}
+
+// paletteRows returns how many entries the palette lists: matching
+// commands, or suggested arguments once a command asks for one.
+func (m *ScanUI) paletteRows() int {
+	if m.palette.pending != nil {
+		return len(m.palette.options)
+	}
+	return len(m.palette.matches)
+}
+
+// refreshPaletteMatches searches the active commands, and one applying
+// each saved preset, for the query.
+func (m *ScanUI) refreshPaletteMatches() {
+	active := append(m.palette.registry.GetActiveCommands(m), m.presetCommands()...)
+	matches := commands.FuzzySearch(active, strings.TrimSpace(m.palette.input.Value()))
+	if len(matches) > PaletteMaxResults {
+		matches = matches[:PaletteMaxResults]
+	}
+	m.palette.matches = matches
+	m.modalState.Cursor = min(m.modalState.Cursor, max(0, len(matches)-1))
+}
+
+// handlePaletteKey edits the query or argument, moves through the listed
+// entries and runs the highlighted command on Enter.
+func (m *ScanUI) handlePaletteKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return true, true, tea.Quit
+	case tea.KeyUp, tea.KeyCtrlP:
+		m.modalState.Cursor = max(0, m.modalState.Cursor-1)
+		return true, true, nil
+	case tea.KeyDown, tea.KeyCtrlN:
+		m.modalState.Cursor = max(0, min(m.paletteRows()-1, m.modalState.Cursor+1))
+		return true, true, nil
+	case tea.KeyEnter:
+		if m.palette.pending != nil {
+			return true, true, m.runPaletteArg()
+		}
+		return true, true, m.runPaletteCommand(m.modalState.Cursor)
+	}
+
+	var cmd tea.Cmd
+	m.palette.input, cmd = m.palette.input.Update(msg)
+	m.modalState.Cursor = 0
+	if m.palette.pending != nil {
+		m.refreshPaletteOptions()
+	} else {
+		m.refreshPaletteMatches()
+	}
+	return true, true, cmd
+}
+
+// runPaletteCommand closes the palette and runs the i-th match, which may
+// open another modal in its place. A command that asks for an argument
+// keeps the palette open to prompt for it.
+func (m *ScanUI) runPaletteCommand(i int) tea.Cmd {
+	if i < 0 || i >= len(m.palette.matches) {
+		return nil
+	}
+	command := m.palette.matches[i].Command
+	if command.ArgPrompt != "" {
+		m.askPaletteArg(command)
+		return nil
+	}
+	m.palette.input.Blur()
+	m.closeModal()
+	if command.UIAction != nil {
+		// Matches carry their action, preset commands included, which
+		// the registry does not hold.
+		return command.UIAction(m)
+	}
+	return m.palette.registry.ExecuteCommand(command.ID, m)
+}
+
+// runPaletteArg closes the palette and runs the pending command with the
+// highlighted suggestion, or with the typed argument when none is listed.
+func (m *ScanUI) runPaletteArg() tea.Cmd {
+	arg := m.palette.input.Value()
+	if cursor := m.modalState.Cursor; cursor < len(m.palette.options) {
+		arg = m.palette.options[cursor]
+	}
+	id := m.palette.pending.ID
+	m.palette.pending = nil
+	m.palette.input.Blur()
+	m.closeModal()
+	return m.palette.registry.ExecuteCommandWithArg(id, m, arg)
+}
+
+// renderPaletteModal renders the query and the matching commands with
+// their shortcuts.
+func (m *ScanUI) renderPaletteModal() string {
+	if m.palette.pending != nil {
+		return m.renderPaletteArg()
+	}
+
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Primary).
+		Width(30).
+		Render(i18n.T("ui.palette.title"))
+	b.WriteString(title + "\n\n")
+	b.WriteString(m.palette.input.View() + "\n\n")
+
+	m.modalState.OptionsTop = strings.Count(b.String(), "\n")
+	if len(m.palette.matches) == 0 {
+		b.WriteString(lipgloss.NewStyle().
+			Foreground(m.theme.Muted).
+			Render(i18n.T("ui.palette.no_match")) + "\n")
+	}
+	keyStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	for i, match := range m.palette.matches {
+		style := lipgloss.NewStyle()
+		if i == m.modalState.Cursor {
+			style = style.Background(m.theme.Primary).Foreground(m.theme.Background)
+		}
+		line := style.Render(fmt.Sprintf("%-24s", match.Command.Name))
+		if len(match.Command.Keys) > 0 {
+			line += "  " + keyStyle.Render(strings.Join(match.Command.Keys, "/"))
+		}
+		b.WriteString(line + "\n")
+	}
+
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Render(i18n.T("ui.palette.keys"))
+	b.WriteString("\n" + instructions)
+
+	return b.String()
+}
+
+// renderPaletteArg renders the argument prompt of the chosen command and
+// the suggestions matching it.
+func (m *ScanUI) renderPaletteArg() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Primary).
+		Width(30).
+		Render(m.palette.pending.Name)
+	b.WriteString(title + "\n\n")
+	b.WriteString(m.palette.input.View() + "\n\n")
+
+	m.modalState.OptionsTop = strings.Count(b.String(), "\n")
+	for i, option := range m.palette.options {
+		style := lipgloss.NewStyle()
+		if i == m.modalState.Cursor {
+			style = style.Background(m.theme.Primary).Foreground(m.theme.Background)
+		}
+		b.WriteString(style.Render(option) + "\n")
+	}
+
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Render(i18n.T("ui.palette.arg_keys"))
+	b.WriteString("\n" + instructions)
+
+	return b.String()
+}
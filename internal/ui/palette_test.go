@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+func TestPaletteRunsMatchingCommand(t *testing.T) {
+	ui := NewScanUI(&config.Config{}, 1, make(chan core.Event), false)
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyCtrlK})
+	if !ui.modalState.IsActive || ui.modalState.Type != ModalPalette {
+		t.Fatal("Ctrl+K should open the command palette")
+	}
+	if len(ui.palette.matches) == 0 {
+		t.Fatal("an empty query should list the commands")
+	}
+
+	// Keys the main view binds are typed into the query.
+	typeRunes(ui, "columns")
+	if got := ui.palette.matches[0].Command.ID; got != "view-columns" {
+		t.Fatalf("best match for \"columns\" = %s", got)
+	}
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if !ui.modalState.IsActive || ui.modalState.Type != ModalColumns {
+		t.Error("running the columns command should open the column chooser")
+	}
+}
+
+func TestPaletteListsOnlyRunnableCommands(t *testing.T) {
+	ui := NewScanUI(&config.Config{}, 1, make(chan core.Event), false)
+	for _, cmd := range ui.palette.registry.GetCommands() {
+		_, plain := paletteActions[cmd.ID]
+		_, withArg := paletteArgActions[cmd.ID]
+		if !plain && !withArg {
+			t.Errorf("palette lists %s, which the UI cannot run", cmd.ID)
+		}
+	}
+
+	ui.openPalette()
+	typeRunes(ui, "zzzz")
+	if len(ui.palette.matches) != 0 {
+		t.Errorf("matches for nonsense = %d", len(ui.palette.matches))
+	}
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if !ui.modalState.IsActive {
+		t.Error("Enter without a match should leave the palette open")
+	}
+}
+
+// runPaletteArgCommand picks the best match for query in the palette,
+// replaces the argument it prompts for with arg and presses Enter.
+func runPaletteArgCommand(t *testing.T, ui *ScanUI, query, arg string) {
+	t.Helper()
+	ui.openPalette()
+	typeRunes(ui, query)
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if ui.palette.pending == nil {
+		t.Fatalf("%q should prompt for an argument", query)
+	}
+	ui.palette.input.SetValue("")
+	typeRunes(ui, arg)
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if ui.modalState.IsActive {
+		t.Fatalf("running %q should close the palette", query)
+	}
+}
+
+func TestPaletteFilterHost(t *testing.T) {
+	ui := NewScanUI(&config.Config{}, 3, make(chan core.Event), false)
+	ui.recordResults([]core.ResultEvent{
+		{Host: "10.0.0.1", Port: 22, State: core.StateOpen},
+		{Host: "10.0.0.2", Hostname: "db.example.net", Port: 5432, State: core.StateOpen},
+		{Host: "192.168.1.1", Port: 80, State: core.StateOpen},
+	})
+
+	runPaletteArgCommand(t, ui, "filter host", "10.0.0")
+	if len(ui.displayResults) != 2 {
+		t.Errorf("host filter 10.0.0 shows %d rows; want 2", len(ui.displayResults))
+	}
+	runPaletteArgCommand(t, ui, "filter host", "example")
+	if len(ui.displayResults) != 1 || ui.displayResults[0].Port != 5432 {
+		t.Errorf("host filter should match host names: %+v", ui.displayResults)
+	}
+}
+
+func TestPaletteSwitchTheme(t *testing.T) {
+	ui := NewScanUI(&config.Config{}, 1, make(chan core.Event), false)
+
+	ui.openPalette()
+	typeRunes(ui, "switch theme")
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(ui.palette.options) == 0 {
+		t.Fatal("the theme prompt should suggest theme names")
+	}
+	typeRunes(ui, "drac")
+	if len(ui.palette.options) != 1 || ui.palette.options[0] != "dracula" {
+		t.Fatalf("suggestions for drac = %v", ui.palette.options)
+	}
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if ui.theme.Name != "dracula" {
+		t.Errorf("theme = %q; want dracula", ui.theme.Name)
+	}
+
+	runPaletteArgCommand(t, ui, "switch theme", "no-such-theme")
+	if ui.theme.Name != "dracula" || !ui.toastIsError {
+		t.Error("an unknown theme should be reported and leave the theme as it was")
+	}
+}
+
+func TestPaletteSetRate(t *testing.T) {
+	ui := NewScanUI(&config.Config{}, 1, make(chan core.Event), false)
+	limiter := &fakeRateControl{rate: 1000}
+	ui.SetRateControl(limiter)
+
+	runPaletteArgCommand(t, ui, "set rate", "8000pps")
+	if limiter.rate != 8000 || ui.targetRate != 8000 {
+		t.Errorf("rate = %d, target %d; want 8000", limiter.rate, ui.targetRate)
+	}
+
+	runPaletteArgCommand(t, ui, "set rate", "fast")
+	if limiter.rate != 8000 || !ui.toastIsError {
+		t.Error("a rate that is not a number should be refused")
+	}
+}
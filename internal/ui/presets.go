@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/internal/ui/commands"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+)
+
+// presetsSettingKey is the config key presets are saved under, one key
+// per preset name.
+const presetsSettingKey = "ui.presets"
+
+// presetCommandPrefix starts the IDs of the palette's per-preset commands.
+const presetCommandPrefix = "preset-apply:"
+
+// sortModeNames name the sort modes in presets, in SortMode order.
+var sortModeNames = []string{"port", "port-desc", "host", "state", "service", "latency", "latency-desc", "discovery"}
+
+// stateFilterNames name the state filters in presets, in StateFilterType
+// order; showing every state is the empty name.
+var stateFilterNames = []string{"", "open", "closed", "filtered"}
+
+// presetName normalizes a name typed for a preset the way the config file
+// reads keys: lower case, with dashes for spaces. Dots would nest the key,
+// so a name with one is refused.
+func presetName(s string) (string, bool) {
+	name := strings.Join(strings.Fields(strings.ToLower(s)), "-")
+	return name, name != "" && !strings.Contains(name, ".")
+}
+
+// presetNames returns the saved preset names in order.
+func (m *ScanUI) presetNames() []string {
+	names := make([]string, 0, len(m.config.UI.Presets))
+	for name := range m.config.UI.Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// currentPreset captures the table's filters and sort order. The '/'
+// search is left out, being a lookup rather than part of a view.
+func (m *ScanUI) currentPreset() config.FilterPreset {
+	f := m.filterState
+	return config.FilterPreset{
+		Host:       f.HostFilter,
+		Ports:      formatPortRange(f.PortRangeMin, f.PortRangeMax),
+		Service:    f.ServiceFilter,
+		State:      stateFilterNames[f.StateFilter],
+		MaxLatency: f.LatencyMax,
+		Banner:     f.BannerSearch,
+		Sort:       sortModeNames[m.sortState.Mode],
+	}
+}
+
+// savePreset saves the table's filters and sort order under name, in the
+// config file when one can be written.
+func (m *ScanUI) savePreset(arg string) tea.Cmd {
+	name, ok := presetName(arg)
+	if !ok {
+		return m.showToast(i18n.T("ui.preset.invalid_name", arg), true)
+	}
+	preset := m.currentPreset()
+	if m.config.UI.Presets == nil {
+		m.config.UI.Presets = make(map[string]config.FilterPreset)
+	}
+	m.config.UI.Presets[name] = preset
+	if m.saveSetting != nil {
+		if err := m.saveSetting(presetsSettingKey+"."+name, preset); err != nil {
+			return m.showToast(i18n.T("ui.preset.save_failed", err), true)
+		}
+	}
+	return m.showToast(i18n.T("ui.preset.saved", name), false)
+}
+
+// applyPreset replaces the table's filters and sort order with the named
+// preset's, keeping the '/' search.
+func (m *ScanUI) applyPreset(name string) tea.Cmd {
+	preset, ok := m.config.UI.Presets[name]
+	if !ok {
+		return m.showToast(i18n.T("ui.preset.unknown", name), true)
+	}
+	minPort, maxPort, err := parsePortRange(preset.Ports)
+	if err != nil {
+		return m.showToast(i18n.T("ui.filter_modal.invalid_ports", preset.Ports), true)
+	}
+
+	for _, pattern := range []string{preset.Host, preset.Banner} {
+		if _, err := compileFilterPattern(pattern); err != nil {
+			return m.showToast(i18n.T("ui.filter_modal.invalid_pattern", err), true)
+		}
+	}
+
+	f := m.filterState
+	query := f.SearchQuery
+	f.Reset()
+	_ = f.SetHostFilter(preset.Host)
+	f.SetPortRange(minPort, maxPort)
+	f.SetServiceFilter(preset.Service)
+	f.SetLatencyFilter(preset.MaxLatency)
+	_ = f.SetBannerSearch(preset.Banner)
+	f.SetSearchQuery(query)
+	for i, state := range stateFilterNames {
+		if state == preset.State {
+			f.SetStateFilter(StateFilterType(i))
+		}
+	}
+	for i, mode := range sortModeNames {
+		if mode == preset.Sort {
+			m.sortState.SetMode(SortMode(i))
+		}
+	}
+
+	m.updateTable()
+	return m.showToast(i18n.T("ui.preset.applied", name), false)
+}
+
+// presetCommands returns a palette command applying each saved preset.
+// They are built whenever the palette searches, so a preset saved in this
+// session is offered at once.
+func (m *ScanUI) presetCommands() []commands.Command {
+	var cmds []commands.Command
+	for _, name := range m.presetNames() {
+		cmds = append(cmds, commands.Command{
+			ID:          presetCommandPrefix + name,
+			Name:        i18n.T("ui.preset.apply", name),
+			Description: i18n.T("ui.preset.apply_description"),
+			Alias:       "preset view " + name,
+			Category:    commands.CommandTypeFilter,
+			UIAction: func(model interface{}) tea.Cmd {
+				return model.(*ScanUI).applyPreset(name)
+			},
+		})
+	}
+	return cmds
+}
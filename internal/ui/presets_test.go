@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+func TestPresetSaveAndApply(t *testing.T) {
+	ui := newFilterTestUI()
+	saved := map[string]interface{}{}
+	ui.SetSettingsSaver(func(key string, value interface{}) error {
+		saved[key] = value
+		return nil
+	})
+
+	ui.filterState.SetPortRange(80, 443)
+	ui.filterState.SetStateFilter(StateFilterOpen)
+	ui.sortState.SetMode(SortByHost)
+	runPaletteArgCommand(t, ui, "save view as preset", "External Web")
+
+	want := config.FilterPreset{Ports: "80-443", State: "open", Sort: "host"}
+	if got := saved["ui.presets.external-web"]; got != want {
+		t.Fatalf("saved %v; want %+v", saved, want)
+	}
+
+	ui.resetFilters()
+	ui.sortState.SetMode(SortByPort)
+	ui.updateTable()
+
+	ui.openPalette()
+	typeRunes(ui, "external-web")
+	if len(ui.palette.matches) == 0 || ui.palette.matches[0].Command.Name != "Apply preset: external-web" {
+		t.Fatalf("palette matches = %+v", ui.palette.matches)
+	}
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if ui.sortState.Mode != SortByHost || ui.filterState.StateFilter != StateFilterOpen {
+		t.Errorf("sort %v, state filter %v after applying the preset", ui.sortState.Mode, ui.filterState.StateFilter)
+	}
+	if len(ui.displayResults) != 2 {
+		t.Errorf("preset shows %d rows; want the 2 open ports 80", len(ui.displayResults))
+	}
+}
+
+func TestPresetFromConfig(t *testing.T) {
+	cfg := &config.Config{UI: config.UIConfig{Presets: map[string]config.FilterPreset{
+		"slow": {State: "open", MaxLatency: 100, Sort: "latency-desc"},
+	}}}
+	ui := NewScanUI(cfg, 2, make(chan core.Event), false)
+	ui.recordResults([]core.ResultEvent{
+		{Host: "10.0.0.1", Port: 22, State: core.StateOpen},
+		{Host: "10.0.0.1", Port: 23, State: core.StateClosed},
+	})
+
+	ui.applyPreset("slow")
+	if ui.filterState.LatencyMax != 100 || ui.sortState.Mode != SortByLatencyDesc || len(ui.displayResults) != 1 {
+		t.Errorf("filters %+v, sort %v, rows %d", ui.filterState, ui.sortState.Mode, len(ui.displayResults))
+	}
+
+	ui.applyPreset("missing")
+	if !ui.toastIsError {
+		t.Error("an unknown preset should be reported")
+	}
+}
+
+func TestPresetName(t *testing.T) {
+	for in, want := range map[string]string{"External Web": "external-web", " dmz ": "dmz"} {
+		if got, ok := presetName(in); !ok || got != want {
+			t.Errorf("presetName(%q) = %q, %v", in, got, ok)
+		}
+	}
+	for _, bad := range []string{"", "   ", "a.b"} {
+		if _, ok := presetName(bad); ok {
+			t.Errorf("presetName(%q) should be refused", bad)
+		}
+	}
+}
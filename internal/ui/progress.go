@@ -26,9 +26,15 @@ type ProgressTracker struct {
 	LastUpdate     time.Time
 	CurrentRate    float64
 	AverageRate    float64
+	RecentRate     float64 // scanner's rate over its recent window; preferred for the ETA
 	IsPaused       bool
 	PausedDuration time.Duration
 	pauseStart     time.Time
+
+	// The scanner's own active-time clock, when it reports one. It keeps
+	// counting across pauses the TUI did not make, such as scan windows.
+	scanElapsed   time.Duration
+	scanElapsedAt time.Time
 	
 	// Enhanced metrics for breadcrumb
 	TotalHosts       int
@@ -73,6 +79,20 @@ func (p *ProgressTracker) UpdateHosts(totalHosts, scannedHosts int) {
 	p.ScannedHosts = scannedHosts
 }
 
+// SyncScanner adopts the scanner's active scanning time, recent rate and
+// pause state from its latest progress report, so the elapsed time and ETA
+// match the scanner however it was paused.
+func (p *ProgressTracker) SyncScanner(elapsed time.Duration, recentRate float64, paused bool) {
+	p.scanElapsed = elapsed
+	p.scanElapsedAt = time.Now()
+	p.RecentRate = recentRate
+	if paused {
+		p.Pause()
+	} else {
+		p.Resume()
+	}
+}
+
 // calculatePerformanceTrend calculates the current performance trend
 func (p *ProgressTracker) calculatePerformanceTrend() {
 	// Calculate relative change
@@ -101,9 +121,14 @@ func (p *ProgressTracker) GetProgress() float64 {
 	return float64(p.ScannedPorts) / float64(p.TotalPorts) * 100
 }
 
-// GetETA calculates the estimated time of arrival
+// GetETA calculates the estimated time of arrival from the recent rate,
+// or the average rate until the scanner reports one.
 func (p *ProgressTracker) GetETA() time.Duration {
-	if p.IsPaused || p.AverageRate <= 0 {
+	rate := p.RecentRate
+	if rate <= 0 {
+		rate = p.AverageRate
+	}
+	if p.IsPaused || rate <= 0 {
 		return 0
 	}
 
@@ -112,12 +137,20 @@ func (p *ProgressTracker) GetETA() time.Duration {
 		return 0
 	}
 
-	secondsRemaining := float64(remaining) / p.AverageRate
+	secondsRemaining := float64(remaining) / rate
 	return time.Duration(secondsRemaining * float64(time.Second))
 }
 
-// GetActiveTime returns the time spent actively scanning (excluding pauses)
+// GetActiveTime returns the time spent actively scanning (excluding pauses).
+// Once the scanner reports its clock, that clock is advanced by the time
+// since its last report unless paused.
 func (p *ProgressTracker) GetActiveTime() time.Duration {
+	if !p.scanElapsedAt.IsZero() {
+		if p.IsPaused {
+			return p.scanElapsed
+		}
+		return p.scanElapsed + time.Since(p.scanElapsedAt)
+	}
 	total := time.Since(p.StartTime)
 	if p.IsPaused {
 		// Currently paused, add current pause duration
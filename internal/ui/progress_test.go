@@ -148,6 +148,34 @@ func TestProgressTracker_GetETA(t *testing.T) {
 	}
 }
 
+func TestProgressTracker_SyncScanner(t *testing.T) {
+	tracker := NewProgressTracker(1000)
+	// The TUI has run for a minute, but the scanner was paused by a scan
+	// window for most of it.
+	tracker.StartTime = time.Now().Add(-time.Minute)
+	tracker.SyncScanner(10*time.Second, 100, false)
+	tracker.Update(500, 0, 500, 0, 80)
+
+	if active := tracker.GetActiveTime(); active < 10*time.Second || active > 11*time.Second {
+		t.Errorf("active time = %v, want the scanner's 10s", active)
+	}
+	if eta := tracker.GetETA(); eta != 5*time.Second {
+		t.Errorf("ETA = %v, want 5s from the recent rate", eta)
+	}
+
+	tracker.SyncScanner(10*time.Second, 100, true)
+	if !tracker.IsPaused || tracker.GetETA() != 0 {
+		t.Error("a scanner-reported pause should pause the tracker")
+	}
+	if active := tracker.GetActiveTime(); active != 10*time.Second {
+		t.Errorf("paused active time = %v, want it frozen at 10s", active)
+	}
+	tracker.SyncScanner(10*time.Second, 100, false)
+	if tracker.IsPaused {
+		t.Error("a scanner-reported resume should resume the tracker")
+	}
+}
+
 func TestProgressTracker_GetETA_Complete(t *testing.T) {
 	tracker := NewProgressTracker(1000)
 	tracker.Update(1000, 20, 960, 20, 500.0)
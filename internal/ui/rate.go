@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+)
+
+// SetRateControl attaches the running scanner's rate limiter so the rate
+// keys can speed the scan up or back it off.
+func (m *ScanUI) SetRateControl(control core.RateAdjustable) {
+	m.rateControl = control
+	m.targetRate = control.Rate()
+}
+
+// adjustRate changes the scanner's target rate by delta probes per second
+// and reports the new rate in a toast.
+func (m *ScanUI) adjustRate(delta int) tea.Cmd {
+	if !m.scanning {
+		return nil
+	}
+	return m.setRate(m.targetRate + delta)
+}
+
+// setRateArg sets the target rate typed into the command palette, such as
+// "8000" or "8000pps".
+func (m *ScanUI) setRateArg(arg string) tea.Cmd {
+	arg = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(arg)), "pps")
+	pps, err := strconv.Atoi(strings.TrimSpace(arg))
+	if err != nil || pps <= 0 {
+		return m.showToast(i18n.T("ui.rate_change.invalid"), true)
+	}
+	if !m.scanning {
+		return m.showToast(i18n.T("ui.rate_change.unavailable"), true)
+	}
+	return m.setRate(pps)
+}
+
+// setRate sets the scanner's target rate and reports the rate the limiter
+// settled on, which may be clamped, in a toast.
+func (m *ScanUI) setRate(pps int) tea.Cmd {
+	if m.rateControl == nil || m.targetRate == 0 {
+		return m.showToast(i18n.T("ui.rate_change.unavailable"), true)
+	}
+	rate := m.rateControl.SetRate(pps)
+	if rate == 0 {
+		return m.showToast(i18n.T("ui.rate_change.unavailable"), true)
+	}
+	m.targetRate = rate
+	return m.showToast(i18n.T("ui.rate_change.set", rate), false)
+}
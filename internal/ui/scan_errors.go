@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// scanErrorLog counts scan errors and keeps the most recent ones for the
+// dashboard ticker. Errors do not end the scan; only closing the event
+// channel does.
+type scanErrorLog struct {
+	total  int
+	recent []error
+}
+
+func (l *scanErrorLog) add(err error) {
+	l.total++
+	l.recent = append(l.recent, err)
+	if len(l.recent) > ErrorTickerSize {
+		l.recent = l.recent[len(l.recent)-ErrorTickerSize:]
+	}
+}
+
+// Count returns the number of errors reported during the scan.
+func (l *scanErrorLog) Count() int {
+	return l.total
+}
+
+// renderErrorTicker lists the latest scan errors, newest first. It returns
+// an empty string until the scanner reports an error.
+func (m *ScanUI) renderErrorTicker(width int) string {
+	if m.scanErrors.Count() == 0 {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Danger)
+	lineStyle := lipgloss.NewStyle().Foreground(m.theme.Warning)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Errors (%d):", m.scanErrors.Count())) + "\n")
+	for i := len(m.scanErrors.recent) - 1; i >= 0; i-- {
+		line := truncateToWidth(m.scanErrors.recent[i].Error(), max(width-2, 1))
+		b.WriteString("  " + lineStyle.Render(line) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+func TestListenForResultsKeepsScanningAfterError(t *testing.T) {
+	events := make(chan core.Event, 3)
+	ui := NewScanUI(&config.Config{}, 2, events, false)
+
+	events <- core.NewErrorEvent(&core.ScanError{Host: "x.invalid", Port: 80, Protocol: "tcp", Err: errors.New("no such host")})
+	events <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen})
+
+	batch, ok := ui.listenForResults()().(scanBatchMsg)
+	if !ok {
+		t.Fatal("expected a scanBatchMsg")
+	}
+	if batch.complete || len(batch.errors) != 1 || len(batch.results) != 1 {
+		t.Fatalf("unexpected batch %+v", batch)
+	}
+
+	ui.Update(batch)
+	if !ui.scanning {
+		t.Error("an error event must not end the scan")
+	}
+	if ui.scanErrors.Count() != 1 {
+		t.Errorf("error count = %d, want 1", ui.scanErrors.Count())
+	}
+
+	close(events)
+	batch, _ = ui.listenForResults()().(scanBatchMsg)
+	ui.Update(batch)
+	if ui.scanning {
+		t.Error("closing the event channel should end the scan")
+	}
+}
+
+func TestScanErrorLogKeepsRecent(t *testing.T) {
+	var log scanErrorLog
+	for i := 0; i < ErrorTickerSize+3; i++ {
+		log.add(fmt.Errorf("error %d", i))
+	}
+	if log.Count() != ErrorTickerSize+3 {
+		t.Errorf("count = %d, want %d", log.Count(), ErrorTickerSize+3)
+	}
+	if len(log.recent) != ErrorTickerSize || log.recent[0].Error() != "error 3" {
+		t.Errorf("recent = %v, want the last %d errors", log.recent, ErrorTickerSize)
+	}
+}
+
+func TestRenderErrorTicker(t *testing.T) {
+	ui := NewScanUI(&config.Config{}, 10, make(chan core.Event), false)
+	if got := ui.renderErrorTicker(40); got != "" {
+		t.Errorf("ticker without errors = %q, want empty", got)
+	}
+
+	ui.scanErrors.add(&core.ScanError{Host: "a.invalid", Port: 22, Protocol: "tcp", Time: time.Now(), Err: errors.New("no such host")})
+	ui.scanErrors.add(errors.New("too many open files"))
+
+	ticker := ui.renderErrorTicker(60)
+	if !strings.Contains(ticker, "Errors (2):") {
+		t.Errorf("ticker should show the error count, got %q", ticker)
+	}
+	newest := strings.Index(ticker, "too many open files")
+	oldest := strings.Index(ticker, "tcp a.invalid:22: no such host")
+	if newest < 0 || oldest < 0 || newest > oldest {
+		t.Errorf("ticker should list newest errors first, got %q", ticker)
+	}
+
+	if status := ui.renderStatus(); !strings.Contains(status, "Errors: 2") {
+		t.Errorf("status should include the error count, got %q", status)
+	}
+}
+
+func TestErrorRateFromProgress(t *testing.T) {
+	ui := NewScanUI(&config.Config{}, 400, make(chan core.Event), false)
+
+	ui.handleScanProgress(scanProgressMsg{progress: core.ProgressEvent{Total: 400, Completed: 100, Rate: 100}})
+	ui.handleScanProgress(scanProgressMsg{progress: core.ProgressEvent{Total: 400, Completed: 200, Rate: 100, Errors: 25}})
+	ui.handleScanProgress(scanProgressMsg{progress: core.ProgressEvent{Total: 400, Completed: 300, Rate: 100, Errors: 25}})
+
+	var rates []float64
+	for _, point := range ui.sparklineData.ErrorRate {
+		rates = append(rates, point.Value)
+	}
+	if want := []float64{0, 25, 0}; !slices.Equal(rates, want) {
+		t.Errorf("error rates = %v; want %v percent of each interval's probes", rates, want)
+	}
+
+	ui.statsData = ui.computeStats()
+	if !strings.Contains(ui.renderSparklines(), "Error Rate (60s):") {
+		t.Error("the dashboard should chart the error rate")
+	}
+}
@@ -1,15 +1,20 @@
 package ui
 
 import (
+	"context"
+	"io"
+
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/lucchesi-sec/portscan/internal/core"
 	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
 	"github.com/lucchesi-sec/portscan/pkg/theme"
 )
 
@@ -29,6 +34,16 @@ type ModalType int
 const (
 	ModalSort ModalType = iota
 	ModalDetails
+	ModalExport
+	ModalBulk
+	ModalTag
+	ModalNote
+	ModalQuit
+	ModalColumns
+	ModalPalette
+	ModalTheme
+	ModalHost
+	ModalFilter
 )
 
 // Position represents screen coordinates and dimensions
@@ -47,6 +62,7 @@ type ModalState struct {
 	Cursor          int
 	ScrollPosition  int // For details modal scrolling
 	MaxScrollHeight int // Track content height for scrolling
+	OptionsTop      int // Content line of the first option of a menu modal
 }
 
 // Message types for communication with the UI
@@ -60,6 +76,34 @@ type scanProgressMsg struct {
 
 type scanCompleteMsg struct{}
 
+// scanBatchMsg carries the scan events collected during one batch window.
+type scanBatchMsg struct {
+	results  []core.ResultEvent
+	errors   []error
+	progress *core.ProgressEvent // latest progress in the window, if any
+	complete bool                // the event stream was closed by the scanner
+}
+
+// add records an event and reports whether the stream is still open.
+func (b *scanBatchMsg) add(event core.Event, ok bool) bool {
+	if !ok {
+		b.complete = true
+		return false
+	}
+	switch event.Kind {
+	case core.EventKindResult:
+		b.results = append(b.results, *event.Result)
+	case core.EventKindProgress:
+		progress := *event.Progress
+		b.progress = &progress
+	case core.EventKindError:
+		if event.Error != nil {
+			b.errors = append(b.errors, event.Error)
+		}
+	}
+	return true
+}
+
 // Note: DefaultResultBufferSize is now defined in constants.go
 
 // ResultBuffer maintains a fixed-size circular buffer of recent scan results.
@@ -68,6 +112,7 @@ type ResultBuffer struct {
 	start    int
 	length   int
 	capacity int
+	evicted  int
 }
 
 // NewResultBuffer creates a new ring buffer with the provided capacity.
@@ -83,21 +128,25 @@ func NewResultBuffer(capacity int) *ResultBuffer {
 }
 
 // Append inserts a result into the buffer, evicting the oldest when full.
-func (b *ResultBuffer) Append(result core.ResultEvent) {
+// It returns the evicted result and true when an eviction happened.
+func (b *ResultBuffer) Append(result core.ResultEvent) (core.ResultEvent, bool) {
 	if b.capacity == 0 {
-		return
+		return core.ResultEvent{}, false
 	}
 
 	if b.length < b.capacity {
 		idx := (b.start + b.length) % b.capacity
 		b.data[idx] = result
 		b.length++
-		return
+		return core.ResultEvent{}, false
 	}
 
 	// Buffer full: overwrite the oldest and move start forward.
+	evicted := b.data[b.start]
 	b.data[b.start] = result
 	b.start = (b.start + 1) % b.capacity
+	b.evicted++
+	return evicted, true
 }
 
 // Items returns the buffered results in discovery order (oldest to newest).
@@ -114,25 +163,47 @@ func (b *ResultBuffer) Items() []core.ResultEvent {
 	return items
 }
 
+// Replace overwrites the stored result for the same host, port, and protocol,
+// returning the previous value. It reports false when no such result exists.
+func (b *ResultBuffer) Replace(result core.ResultEvent) (core.ResultEvent, bool) {
+	key := selectionKey(result)
+	for i := 0; i < b.length; i++ {
+		idx := (b.start + i) % b.capacity
+		if selectionKey(b.data[idx]) == key {
+			previous := b.data[idx]
+			b.data[idx] = result
+			return previous, true
+		}
+	}
+	return core.ResultEvent{}, false
+}
+
 // Len reports the number of items currently stored.
 func (b *ResultBuffer) Len() int {
 	return b.length
 }
 
+// Evicted reports how many results have been dropped to make room.
+func (b *ResultBuffer) Evicted() int {
+	return b.evicted
+}
+
 // ResultStats tracks aggregate counts for scan results regardless of buffer eviction.
 type ResultStats struct {
 	total    int
 	open     int
 	closed   int
 	filtered int
+	hosts    map[string]struct{}
 }
 
 func NewResultStats() *ResultStats {
-	return &ResultStats{}
+	return &ResultStats{hosts: make(map[string]struct{})}
 }
 
 func (s *ResultStats) Add(result core.ResultEvent) {
 	s.total++
+	s.hosts[result.Host] = struct{}{}
 
 	switch result.State {
 	case core.StateOpen:
@@ -144,10 +215,30 @@ func (s *ResultStats) Add(result core.ResultEvent) {
 	}
 }
 
+// Replace moves a result between state counters when a port is re-scanned.
+func (s *ResultStats) Replace(previous, current core.ResultEvent) {
+	switch previous.State {
+	case core.StateOpen:
+		s.open--
+	case core.StateClosed:
+		s.closed--
+	case core.StateFiltered:
+		s.filtered--
+	}
+	s.total--
+	s.Add(current)
+}
+
 func (s *ResultStats) Totals() (total, open, closed, filtered int) {
 	return s.total, s.open, s.closed, s.filtered
 }
 
+// Hosts returns how many distinct hosts have reported results, including
+// results the buffer has since evicted.
+func (s *ResultStats) Hosts() int {
+	return len(s.hosts)
+}
+
 // ScanUI renders scan activity in the terminal.
 type ScanUI struct {
 	// Core
@@ -156,6 +247,16 @@ type ScanUI struct {
 	results    *ResultBuffer
 	resultChan <-chan core.Event
 	bufferSize int
+	control    core.Pausable      // optional; lets pause suspend the scanner itself
+	cancelScan context.CancelFunc // optional; lets quit stop the scan but keep the TUI
+	spill      *resultSpill       // created on first eviction when ui.spill_overflow is set
+	spillErr   error              // set when the spill file could not be created
+
+	// Table rows: tableRows backs the table and holds styled rows only
+	// around renderedCursor; see table_rows.go.
+	tableRows      []table.Row
+	rowCache       rowCache
+	renderedCursor int
 
 	// View state
 	viewState  UIViewState
@@ -173,25 +274,91 @@ type ScanUI struct {
 	// Progress tracking
 	progressTrack *ProgressTracker
 
+	// Rate control: optional; lets the rate keys change the scanner's rate
+	rateControl core.RateAdjustable
+	targetRate  int // probes per second last set through rateControl
+
 	// State
 	scanning     bool
 	isPaused     bool
 	showHelp     bool
 	totalPorts   int
 	showOnlyOpen bool
+	bannerHex    bool // details modal shows banners as a hex dump
+	detailsRaw   bool // details modal shows the JSON record and probe transcript
+	showGeo      bool // table shows the location column
+	layout       tableLayout
+
+	// saveSetting writes preferences changed in the UI to the config file
+	saveSetting SettingsSaver
+
+	// sessionOut receives the asciinema recording of the session, kept by
+	// session while Run draws it
+	sessionOut io.Writer
+	session    *SessionRecorder
+
+	// hostProgressKnown is set once the scanner reports host totals, after
+	// which host counters come only from progress events.
+	hostProgressKnown bool
 
 	// Stats
 	stats             *ResultStats
+	scanErrors        scanErrorLog
 	currentRate       float64
 	previousOpenCount int
+	// Probes and failed probes at the last progress event, from which
+	// the error rate is worked out
+	previousCompleted  int
+	previousErrorCount int
 
 	// Sorting and Filtering
 	sortState      *SortState
 	filterState    *FilterState
 	displayResults []core.ResultEvent // Filtered/sorted view of results
 
+	// Search
+	searchInput  textinput.Model
+	searchActive bool
+
+	// Command palette
+	palette paletteState
+
+	// Theme picker
+	themePicker themePicker
+
+	// Service groups the table shows in place of results when enabled
+	grouping serviceGrouping
+
+	// Host the host summary modal describes
+	summaryHost string
+
+	// Filter modal fields, applied to filterState on Enter
+	filterForm filterForm
+
+	// Export
+	exportState ExportState
+
+	// Multi-select and bulk actions
+	selection *SelectionState
+	tagInput  textinput.Model
+	tags      map[string][]string
+	rescan    RescanFunc
+
+	// Notes on individual results, keyed like tags
+	noteInput textinput.Model
+	noteKey   string
+	notes     map[string]string
+
+	// Transient footer notification
+	toast        string
+	toastIsError bool
+	toastID      int
+
 	// Dashboard
 	showDashboard bool
+	dashboardTab  DashboardTab
+	inventory     *exporter.Inventory
+	activeHosts   []core.HostProgress // from the latest progress event
 	statsData     *StatsData
 	sparklineData *SparklineData
 }
@@ -206,12 +373,30 @@ type KeyBindings struct {
 	End             key.Binding
 	Help            key.Binding
 	Pause           key.Binding
+	RateUp          key.Binding
+	RateDown        key.Binding
 	Clear           key.Binding
 	Quit            key.Binding
 	Sort            key.Binding
 	Reset           key.Binding
 	OpenOnly        key.Binding
+	ToggleGeo       key.Binding
+	Columns         key.Binding
+	Palette         key.Binding
+	Theme           key.Binding
+	HostSummary     key.Binding
+	Filter          key.Binding
 	ToggleDashboard key.Binding
+	DashboardTab    key.Binding
+	Search          key.Binding
+	NextMatch       key.Binding
+	PrevMatch       key.Binding
+	Export          key.Binding
+	Copy            key.Binding
+	Mark            key.Binding
+	MarkRange       key.Binding
+	BulkActions     key.Binding
+	Note            key.Binding
 	Enter           key.Binding
 	Escape          key.Binding
 }
@@ -249,6 +434,14 @@ var defaultKeys = KeyBindings{
 		key.WithKeys("p", " "),
 		key.WithHelp("p/Space", "pause/resume"),
 	),
+	RateUp: key.NewBinding(
+		key.WithKeys("+", "="),
+		key.WithHelp("+", "raise rate 500 pps"),
+	),
+	RateDown: key.NewBinding(
+		key.WithKeys("-"),
+		key.WithHelp("-", "lower rate 500 pps"),
+	),
 	Clear: key.NewBinding(
 		key.WithKeys("ctrl+l"),
 		key.WithHelp("Ctrl+L", "clear screen"),
@@ -269,10 +462,74 @@ var defaultKeys = KeyBindings{
 		key.WithKeys("o"),
 		key.WithHelp("o", "toggle open only"),
 	),
+	ToggleGeo: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "toggle location column"),
+	),
+	Columns: key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "choose columns"),
+	),
+	Palette: key.NewBinding(
+		key.WithKeys("ctrl+k"),
+		key.WithHelp("Ctrl+K", "command palette"),
+	),
+	Theme: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "choose theme"),
+	),
+	Filter: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "filter results"),
+	),
+	HostSummary: key.NewBinding(
+		key.WithKeys("h"),
+		key.WithHelp("h", "host summary"),
+	),
 	ToggleDashboard: key.NewBinding(
 		key.WithKeys("D"),
 		key.WithHelp("D", "toggle dashboard"),
 	),
+	DashboardTab: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("Tab", "switch dashboard tab"),
+	),
+	Search: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "search"),
+	),
+	NextMatch: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "next match"),
+	),
+	PrevMatch: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "previous match"),
+	),
+	Export: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "export results"),
+	),
+	Copy: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy host:port"),
+	),
+	Mark: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "mark row"),
+	),
+	MarkRange: key.NewBinding(
+		key.WithKeys("V"),
+		key.WithHelp("V", "mark range"),
+	),
+	BulkActions: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "bulk actions"),
+	),
+	Note: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "add note"),
+	),
 	Enter: key.NewBinding(
 		key.WithKeys("enter"),
 		key.WithHelp("Enter", "confirm selection"),
@@ -285,22 +542,30 @@ var defaultKeys = KeyBindings{
 
 // ShortHelp returns key bindings for the help bar
 func (k KeyBindings) ShortHelp() []key.Binding {
-	return []key.Binding{k.Help, k.Sort, k.Pause, k.Quit}
+	return []key.Binding{k.Help, k.Search, k.Sort, k.Pause, k.Quit}
 }
 
 // FullHelp returns all key bindings for the help view
 func (k KeyBindings) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.PageUp, k.PageDown},
-		{k.Home, k.End, k.Clear},
-		{k.Sort, k.Reset, k.OpenOnly},
-		{k.Pause, k.Help, k.Quit},
+		{k.Home, k.End, k.Clear, k.Palette, k.Theme},
+		{k.Sort, k.Filter, k.Reset, k.OpenOnly, k.ToggleGeo, k.Columns, k.Export, k.Copy},
+		{k.Search, k.NextMatch, k.PrevMatch},
+		{k.Mark, k.MarkRange, k.BulkActions, k.Note, k.HostSummary},
+		{k.Pause, k.RateUp, k.RateDown, k.Help, k.Quit},
 	}
 }
 
 // NewScanUI creates a new scan UI model.
 func NewScanUI(cfg *config.Config, totalPorts int, results <-chan core.Event, onlyOpen bool) *ScanUI {
 	t := theme.GetTheme(cfg.UI.Theme)
+	if cfg.UI.Accessible {
+		t = theme.Accessible(t)
+	}
+	if cfg.UI.ASCII {
+		t = theme.ASCII(t)
+	}
 
 	bufferSize := cfg.UI.ResultBufferSize
 	if bufferSize <= 0 {
@@ -310,8 +575,9 @@ func NewScanUI(cfg *config.Config, totalPorts int, results <-chan core.Event, on
 	resultBuffer := NewResultBuffer(bufferSize)
 	stats := NewResultStats()
 
-	initialWidth := sumMinWidths()
-	columns := calculateColumnWidths(initialWidth)
+	layout, showGeo := newTableLayout(cfg.UI.Columns)
+	initialWidth := sumMinWidths(layout, showGeo)
+	columns := calculateColumnWidths(initialWidth, layout, showGeo)
 
 	tbl := table.New(
 		table.WithColumns(columns),
@@ -320,24 +586,30 @@ func NewScanUI(cfg *config.Config, totalPorts int, results <-chan core.Event, on
 	)
 	tbl.SetWidth(initialWidth)
 
-	styles := table.DefaultStyles()
-	styles.Header = t.TableHeaderStyle()
-	styles.Cell = t.TableCellStyle()
-	styles.Selected = t.TableSelectedStyle()
-	tbl.SetStyles(styles)
+	tbl.SetStyles(tableStyles(t))
 
-	prog := progress.New(progress.WithDefaultGradient())
+	symbols := t.GetSymbols()
+	prog := progress.New(progress.WithDefaultGradient(), progress.WithFillCharacters(symbols.BarFull, symbols.BarEmpty))
 
 	spin := spinner.New()
-	spin.Spinner = spinner.Dot
+	spin.Spinner = spinner.Spinner{Frames: symbols.Spinner, FPS: spinner.Dot.FPS}
 	spin.Style = lipgloss.NewStyle().Foreground(t.Primary)
 
 	helpModel := help.New()
 	helpModel.ShowAll = false
+	helpModel.ShortSeparator = symbols.Text(helpModel.ShortSeparator)
+	helpModel.Ellipsis = symbols.Text(helpModel.Ellipsis)
+
+	keys := defaultKeys
+	for _, binding := range []*key.Binding{&keys.Up, &keys.Down} {
+		h := binding.Help()
+		binding.SetHelp(symbols.Text(h.Key), h.Desc)
+	}
 
 	sortState := NewSortState()
 	filterState := NewFilterState()
 	sparklineData := NewSparklineData()
+	sparklineData.Levels = symbols.Sparkline
 	if onlyOpen {
 		filterState.SetStateFilter(StateFilterOpen)
 	}
@@ -352,20 +624,47 @@ func NewScanUI(cfg *config.Config, totalPorts int, results <-chan core.Event, on
 		progressBar:    prog,
 		spinner:        spin,
 		help:           helpModel,
-		keys:           defaultKeys,
+		keys:           keys,
 		progressTrack:  NewProgressTracker(totalPorts),
 		scanning:       true,
 		totalPorts:     totalPorts,
 		viewState:      UIViewMain,
 		showOnlyOpen:   onlyOpen,
+		showGeo:        showGeo,
+		layout:         layout,
 		sortState:      sortState,
 		filterState:    filterState,
+		filterForm:     newFilterForm(t),
 		stats:          stats,
 		displayResults: []core.ResultEvent{},
+		searchInput:    newSearchInput(t),
+		palette:        newPaletteState(t),
+		exportState:    newExportState(t),
+		selection:      NewSelectionState(),
+		tagInput:       newTagInput(t),
+		tags:           make(map[string][]string),
+		noteInput:      newNoteInput(t),
+		notes:          make(map[string]string),
+		inventory:      exporter.NewInventory(),
 		sparklineData:  sparklineData,
 	}
 }
 
+// tableStyles returns the results table styles for a theme.
+func tableStyles(t theme.Theme) table.Styles {
+	styles := table.DefaultStyles()
+	styles.Header = t.TableHeaderStyle()
+	styles.Cell = t.TableCellStyle()
+	styles.Selected = t.TableSelectedStyle()
+	return styles
+}
+
+// SetScanControl attaches the running scanner so pausing the UI suspends
+// scanner workers rather than only the progress display.
+func (m *ScanUI) SetScanControl(control core.Pausable) {
+	m.control = control
+}
+
 // Init initializes the UI
 func (m *ScanUI) Init() tea.Cmd {
 	return tea.Batch(
@@ -381,30 +680,51 @@ func (m *ScanUI) indicatorsVisible() bool {
 	if m.filterState != nil && m.filterState.GetActiveFilterDescription() != "" {
 		return true
 	}
+	if m.selection != nil && m.selection.Count() > 0 {
+		return true
+	}
+	if m.results != nil && m.results.Evicted() > 0 {
+		return true
+	}
 	return false
 }
 
 func (m *ScanUI) tableViewportWidth() int {
+	minWidth := sumMinWidths(m.layout, m.showGeo)
 	if m.width <= 0 {
-		return sumMinWidths()
+		return minWidth
 	}
 	baseWidth := m.width
 	if m.showDashboard && m.width >= DashboardMinWidth {
 		baseWidth = int(float64(m.width) * DashboardLeftWidthPercent)
-		if baseWidth < sumMinWidths()+tableHorizontalFrame {
-			baseWidth = sumMinWidths() + tableHorizontalFrame
+		if baseWidth < minWidth+tableHorizontalFrame {
+			baseWidth = minWidth + tableHorizontalFrame
 		}
 	}
 	available := baseWidth - tableHorizontalFrame
-	if available < sumMinWidths() {
-		return sumMinWidths()
+	if available < minWidth {
+		return minWidth
 	}
 	return available
 }
 
 // Run starts the TUI program.
 func (m *ScanUI) Run() error {
-	program := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	defer m.closeSpill()
+	opts := []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
+	if m.sessionOut != nil {
+		recording, err := m.startSessionRecording()
+		if err != nil {
+			return err
+		}
+		opts = append(opts, recording...)
+	}
+	program := tea.NewProgram(m, opts...)
 	_, err := program.Run()
+	if m.session != nil {
+		if closeErr := m.session.Close(); err == nil {
+			err = closeErr
+		}
+	}
 	return err
 }
@@ -202,6 +202,29 @@ func TestScanUI_Update_ScanProgress(t *testing.T) {
 	_ = updatedUI // Just ensure update succeeded without panic
 }
 
+func TestScanUI_HostProgressFromScanner(t *testing.T) {
+	ui := NewScanUI(&config.Config{}, 0, make(chan core.Event), false)
+
+	ui.handleScanProgress(scanProgressMsg{progress: core.ProgressEvent{
+		Total: 2048, Completed: 1024, Rate: 500, TotalHosts: 8, CompletedHosts: 3,
+	}})
+	if got := ui.progressTrack.GetHostProgress(); got != "3/8" {
+		t.Errorf("host progress = %q, want 3/8", got)
+	}
+	if got := ui.progressTrack.GetProgress(); got != 50 {
+		t.Errorf("progress = %.1f%%, want 50%%", got)
+	}
+
+	// A result arriving between progress events must not reset either counter.
+	ui.handleScanResult(scanResultMsg{result: core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen}})
+	if got := ui.progressTrack.GetHostProgress(); got != "3/8" {
+		t.Errorf("host progress after result = %q, want 3/8", got)
+	}
+	if got := ui.progressTrack.GetProgress(); got != 50 {
+		t.Errorf("progress after result = %.1f%%, want 50%%", got)
+	}
+}
+
 func TestScanUI_Update_ScanComplete(t *testing.T) {
 	cfg := &config.Config{}
 	events := make(chan core.Event, 10)
@@ -254,6 +277,10 @@ func TestResultBuffer_Append_Capacity(t *testing.T) {
 	if items[capacity-1].Port != 89 {
 		t.Errorf("last item port = %d; want 89", items[capacity-1].Port)
 	}
+
+	if buffer.Evicted() != 5 {
+		t.Errorf("evicted = %d; want 5", buffer.Evicted())
+	}
 }
 
 func TestResultBuffer_ZeroCapacity(t *testing.T) {
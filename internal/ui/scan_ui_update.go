@@ -1,8 +1,6 @@
 package ui
 
 import (
-	"fmt"
-	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -25,6 +23,12 @@ func (m *ScanUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.handleWindowSize(typed)
 
+	case tea.MouseMsg:
+		if cmd := m.handleMouseMsg(typed); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		skipTableUpdate = true
+
 	case tea.KeyMsg:
 		handled, skip, cmd := m.handleKeyMsg(typed)
 		if cmd != nil {
@@ -47,6 +51,32 @@ func (m *ScanUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.scanning = false
 		skipTableUpdate = true
 
+	case scanBatchMsg:
+		m.handleScanBatch(typed)
+		skipTableUpdate = true
+
+	case exportFinishedMsg:
+		if cmd := m.handleExportFinished(typed); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		skipTableUpdate = true
+
+	case snapshotSavedMsg:
+		if cmd := m.handleSnapshotSaved(typed); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		skipTableUpdate = true
+
+	case rescanFinishedMsg:
+		if cmd := m.handleRescanFinished(typed); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		skipTableUpdate = true
+
+	case toastExpiredMsg:
+		m.handleToastExpired(typed)
+		skipTableUpdate = true
+
 	case spinner.TickMsg:
 		if cmd := m.handleSpinnerTick(typed); cmd != nil {
 			cmds = append(cmds, cmd)
@@ -64,6 +94,8 @@ func (m *ScanUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	m.refreshRowWindow()
+
 	if m.scanning {
 		cmds = append(cmds, m.listenForResults())
 	}
@@ -74,25 +106,42 @@ func (m *ScanUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *ScanUI) handleWindowSize(msg tea.WindowSizeMsg) {
 	m.width = msg.Width
 	m.height = msg.Height
+	if m.session != nil {
+		m.session.Resize(msg.Width, msg.Height)
+	}
 	m.applyTableGeometry()
 }
 
 func (m *ScanUI) handleKeyMsg(msg tea.KeyMsg) (handled bool, skipTable bool, cmd tea.Cmd) {
+	// The search input captures all keys while it is focused
+	if m.searchActive {
+		return m.handleSearchKey(msg)
+	}
+
 	// Handle modal escape key globally if modal is active
 	if m.modalState.IsActive && key.Matches(msg, m.keys.Escape) {
-		m.modalState.IsActive = false
-		m.modalState.Cursor = 0
+		m.closeModal()
 		return true, true, nil
 	}
 
-	if key.Matches(msg, m.keys.Help) {
-		m.showHelp = !m.showHelp
-		m.help.ShowAll = m.showHelp
-		if m.showHelp {
-			m.viewState = UIViewHelp
-		} else {
-			m.viewState = UIViewMain
+	// Text inputs in modals capture all other keys, including '?'
+	if m.modalState.IsActive {
+		switch m.modalState.Type {
+		case ModalExport:
+			return m.handleExportModalKey(msg)
+		case ModalTag:
+			return m.handleTagModalKey(msg)
+		case ModalNote:
+			return m.handleNoteModalKey(msg)
+		case ModalPalette:
+			return m.handlePaletteKey(msg)
+		case ModalFilter:
+			return m.handleFilterModalKey(msg)
 		}
+	}
+
+	if key.Matches(msg, m.keys.Help) {
+		m.toggleHelp()
 		return true, true, nil
 	}
 
@@ -123,6 +172,24 @@ func (m *ScanUI) handleModalKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
 		return m.handleSortModalKey(msg)
 	case ModalDetails:
 		return m.handleDetailsModalKey(msg)
+	case ModalExport:
+		return m.handleExportModalKey(msg)
+	case ModalBulk:
+		return m.handleBulkModalKey(msg)
+	case ModalTag:
+		return m.handleTagModalKey(msg)
+	case ModalNote:
+		return m.handleNoteModalKey(msg)
+	case ModalQuit:
+		return m.handleQuitModalKey(msg)
+	case ModalColumns:
+		return m.handleColumnsModalKey(msg)
+	case ModalPalette:
+		return m.handlePaletteKey(msg)
+	case ModalTheme:
+		return m.handleThemeModalKey(msg)
+	case ModalHost:
+		return m.handleHostSummaryKey(msg)
 	default:
 		return true, true, nil
 	}
@@ -134,7 +201,7 @@ func (m *ScanUI) handleSortModalKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
 		m.modalState.Cursor = max(0, m.modalState.Cursor-1)
 		return true, true, nil
 	case "down", "j":
-		m.modalState.Cursor = min(7, m.modalState.Cursor+1)
+		m.modalState.Cursor = min(len(sortOptions)-1, m.modalState.Cursor+1)
 		return true, true, nil
 	case "enter":
 		switch m.modalState.Cursor {
@@ -167,23 +234,49 @@ func (m *ScanUI) handleSortModalKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
 func (m *ScanUI) handleDetailsModalKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
 	switch msg.String() {
 	case "up", "k":
-		// Scroll up if content is scrollable
-		if m.modalState.ScrollPosition > 0 {
-			m.modalState.ScrollPosition--
-		}
+		m.scrollDetails(-1)
 		return true, true, nil
 	case "down", "j":
-		// Scroll down if there's more content
-		maxScroll := max(0, m.modalState.MaxScrollHeight-maxModalContentHeight)
-		if m.modalState.ScrollPosition < maxScroll {
-			m.modalState.ScrollPosition++
+		m.scrollDetails(1)
+		return true, true, nil
+	case "tab":
+		m.toggleDetailsTab()
+		return true, true, nil
+	case "y":
+		if m.detailsRaw {
+			return true, true, m.copyDetailsRaw()
 		}
+		return true, true, m.copySelected(true)
+	case "r":
+		return true, true, m.rescanSelectedRow()
+	case "t":
+		return true, true, m.openNoteModal()
+	case "x":
+		m.bannerHex = !m.bannerHex
 		return true, true, nil
 	default:
 		return true, true, nil
 	}
 }
 
+// scrollDetails scrolls the details modal by delta lines, stopping at the
+// top and at the last screenful of content.
+func (m *ScanUI) scrollDetails(delta int) {
+	maxScroll := max(0, m.modalState.MaxScrollHeight-modalScrollLines)
+	m.modalState.ScrollPosition = min(maxScroll, max(0, m.modalState.ScrollPosition+delta))
+}
+
+// toggleHelp shows or hides the full keyboard help.
+func (m *ScanUI) toggleHelp() {
+	m.showHelp = !m.showHelp
+	m.help.ShowAll = m.showHelp
+	if m.showHelp {
+		m.viewState = UIViewHelp
+	} else {
+		m.viewState = UIViewMain
+	}
+}
+
 func (m *ScanUI) handleHelpKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
 	if key.Matches(msg, m.keys.Quit) || key.Matches(msg, m.keys.Help) {
 		m.showHelp = false
@@ -192,6 +285,26 @@ func (m *ScanUI) handleHelpKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
 	return true, true, nil
 }
 
+// togglePause suspends or resumes the scan. With a scan control attached the
+// scanner workers stop probing; otherwise only progress tracking is paused.
+func (m *ScanUI) togglePause() {
+	if !m.scanning {
+		return
+	}
+	m.isPaused = !m.isPaused
+	if m.isPaused {
+		m.progressTrack.Pause()
+		if m.control != nil {
+			m.control.Pause()
+		}
+	} else {
+		m.progressTrack.Resume()
+		if m.control != nil {
+			m.control.Resume()
+		}
+	}
+}
+
 // Helper functions
 func (m *ScanUI) openModal(modalType ModalType) {
 	m.modalState.IsActive = true
@@ -201,6 +314,15 @@ func (m *ScanUI) openModal(modalType ModalType) {
 	m.modalState.MaxScrollHeight = 0
 }
 
+// closeModal dismisses the open modal without applying it.
+func (m *ScanUI) closeModal() {
+	if m.modalState.IsActive && m.modalState.Type == ModalTheme {
+		m.restoreTheme()
+	}
+	m.modalState.IsActive = false
+	m.modalState.Cursor = 0
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -217,48 +339,72 @@ func max(a, b int) int {
 
 func (m *ScanUI) handleMainKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
 	switch {
-	case key.Matches(msg, m.keys.Quit):
+	case msg.Type == tea.KeyCtrlC:
 		return true, true, tea.Quit
+	case key.Matches(msg, m.keys.Quit):
+		return true, true, m.requestQuit()
 	case key.Matches(msg, m.keys.Pause):
-		if m.scanning {
-			m.isPaused = !m.isPaused
-			if m.isPaused {
-				m.progressTrack.Pause()
-			} else {
-				m.progressTrack.Resume()
-			}
-		}
+		m.togglePause()
 		return true, true, nil
 	case key.Matches(msg, m.keys.Clear):
 		return true, true, tea.ClearScreen
 	case key.Matches(msg, m.keys.Sort):
 		m.openModal(ModalSort)
 		return true, true, nil
+	case key.Matches(msg, m.keys.Search):
+		return true, true, m.startSearch()
+	case key.Matches(msg, m.keys.NextMatch):
+		m.jumpToMatch(true)
+		return true, true, nil
+	case key.Matches(msg, m.keys.PrevMatch):
+		m.jumpToMatch(false)
+		return true, true, nil
+	case key.Matches(msg, m.keys.Export):
+		return true, true, m.openExportModal()
+	case key.Matches(msg, m.keys.Copy):
+		return true, true, m.copySelected(false)
+	case key.Matches(msg, m.keys.Mark):
+		m.toggleMark()
+		return true, true, nil
+	case key.Matches(msg, m.keys.MarkRange):
+		m.markRange()
+		return true, true, nil
+	case key.Matches(msg, m.keys.BulkActions):
+		return true, true, m.openBulkModal()
+	case key.Matches(msg, m.keys.Note):
+		return true, true, m.openNoteModal()
 	case key.Matches(msg, m.keys.Enter):
-		if len(m.displayResults) > 0 {
-			m.openModal(ModalDetails)
-		}
+		m.openSelected()
 		return true, true, nil
 	case key.Matches(msg, m.keys.Reset):
-		m.filterState.Reset()
-		m.sortState = NewSortState()
-		m.updateTable()
+		m.resetFilters()
 		return true, true, nil
 	case key.Matches(msg, m.keys.OpenOnly):
-		if m.filterState.StateFilter == StateFilterOpen {
-			m.filterState.SetStateFilter(StateFilterAll)
-		} else {
-			m.filterState.SetStateFilter(StateFilterOpen)
-		}
+		m.toggleOpenOnly()
+		return true, true, nil
+	case key.Matches(msg, m.keys.RateUp):
+		return true, true, m.adjustRate(RateStep)
+	case key.Matches(msg, m.keys.RateDown):
+		return true, true, m.adjustRate(-RateStep)
+	case key.Matches(msg, m.keys.ToggleGeo):
+		m.showGeo = !m.showGeo
 		m.updateTable()
 		return true, true, nil
+	case key.Matches(msg, m.keys.Columns):
+		return true, true, m.openColumnsModal()
+	case key.Matches(msg, m.keys.Palette):
+		return true, true, m.openPalette()
+	case key.Matches(msg, m.keys.Theme):
+		return true, true, m.openThemeModal()
+	case key.Matches(msg, m.keys.HostSummary):
+		return true, true, m.openHostSummary()
+	case key.Matches(msg, m.keys.Filter):
+		return true, true, m.openFilterModal()
 	case key.Matches(msg, m.keys.ToggleDashboard):
-		m.showDashboard = !m.showDashboard
-		m.applyTableGeometry()
-		// Recompute stats when showing dashboard
-		if m.showDashboard {
-			m.statsData = m.computeStats()
-		}
+		m.toggleDashboard()
+		return true, true, nil
+	case key.Matches(msg, m.keys.DashboardTab) && m.showDashboard:
+		m.nextDashboardTab()
 		return true, true, nil
 	case key.Matches(msg, m.keys.Up):
 		m.table.MoveUp(1)
@@ -283,40 +429,84 @@ func (m *ScanUI) handleMainKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
 	}
 }
 
-func (m *ScanUI) handleScanResult(msg scanResultMsg) {
-	m.results.Append(msg.result)
-	m.stats.Add(msg.result)
+// resetFilters clears every filter and restores discovery order.
+func (m *ScanUI) resetFilters() {
+	m.filterState.Reset()
+	m.sortState = NewSortState()
 	m.updateTable()
-	total, open, closed, filtered := m.stats.Totals()
-
-	// Calculate host metrics
-	hostsScanned := m.calculateHostsScanned()
+}
 
-	// Update progress with host tracking
-	m.progressTrack.Update(total, open, closed, filtered, m.currentRate)
-	m.progressTrack.UpdateHosts(m.calculateTotalHosts(), hostsScanned)
+// toggleOpenOnly switches between showing open ports and every state.
+func (m *ScanUI) toggleOpenOnly() {
+	if m.filterState.StateFilter == StateFilterOpen {
+		m.filterState.SetStateFilter(StateFilterAll)
+	} else {
+		m.filterState.SetStateFilter(StateFilterOpen)
+	}
+	m.updateTable()
+}
 
-	// Update dashboard stats if visible
+// toggleDashboard shows or hides the dashboard beside the table.
+func (m *ScanUI) toggleDashboard() {
+	m.showDashboard = !m.showDashboard
+	m.applyTableGeometry()
+	// Recompute stats when showing dashboard
 	if m.showDashboard {
 		m.statsData = m.computeStats()
 	}
 }
 
-// calculateHostsScanned determines how many unique hosts have been scanned
-func (m *ScanUI) calculateHostsScanned() int {
-	hosts := make(map[string]bool)
-	results := m.results.Items()
+func (m *ScanUI) handleScanResult(msg scanResultMsg) {
+	m.recordResults([]core.ResultEvent{msg.result})
+}
 
-	for _, result := range results {
-		hosts[result.Host] = true
+// handleScanBatch applies a batch of scan events with a single table rebuild.
+func (m *ScanUI) handleScanBatch(msg scanBatchMsg) {
+	if len(msg.results) > 0 {
+		m.recordResults(msg.results)
+	}
+	for _, err := range msg.errors {
+		m.scanErrors.add(err)
+	}
+	if msg.progress != nil {
+		m.handleScanProgress(scanProgressMsg{progress: *msg.progress})
 	}
+	if msg.complete {
+		m.scanning = false
+	}
+}
 
-	return len(hosts)
+// recordResults buffers results and refreshes the table and progress once.
+func (m *ScanUI) recordResults(results []core.ResultEvent) {
+	for _, r := range results {
+		m.appendResult(r)
+		m.stats.Add(r)
+		m.recordInventory(r)
+	}
+	m.updateTable()
+	total, open, closed, filtered := m.stats.Totals()
+
+	// Progress events count every probe across hosts; never let the result
+	// count move the percentage backwards between them.
+	scanned := total
+	if m.progressTrack.ScannedPorts > scanned {
+		scanned = m.progressTrack.ScannedPorts
+	}
+	m.progressTrack.Update(scanned, open, closed, filtered, m.currentRate)
+	if !m.hostProgressKnown {
+		hosts := m.calculateHostsScanned()
+		m.progressTrack.UpdateHosts(hosts, hosts)
+	}
+
+	// Update dashboard stats if visible
+	if m.showDashboard {
+		m.statsData = m.computeStats()
+	}
 }
 
-// calculateTotalHosts returns the total number of unique hosts being scanned
-// This is an estimation based on the configuration and known targets
-func (m *ScanUI) calculateTotalHosts() int {
+// calculateHostsScanned determines how many unique hosts have results. It
+// is only a fallback until the scanner reports host progress.
+func (m *ScanUI) calculateHostsScanned() int {
 	hosts := make(map[string]bool)
 	results := m.results.Items()
 
@@ -339,10 +529,14 @@ func (m *ScanUI) handleScanProgress(msg scanProgressMsg) {
 		scanned = total
 	}
 
-	// Calculate host metrics
-	hostsScanned := m.calculateHostsScanned()
-	totalHosts := m.calculateTotalHosts()
-
+	if msg.progress.Elapsed > 0 {
+		// Without a scan control only the TUI knows the scan is paused.
+		paused := msg.progress.Paused
+		if m.control == nil {
+			paused = m.isPaused
+		}
+		m.progressTrack.SyncScanner(msg.progress.Elapsed, msg.progress.RecentRate, paused)
+	}
 	m.progressTrack.Update(
 		scanned,
 		open,
@@ -350,7 +544,14 @@ func (m *ScanUI) handleScanProgress(msg scanProgressMsg) {
 		filtered,
 		m.currentRate,
 	)
-	m.progressTrack.UpdateHosts(totalHosts, hostsScanned)
+	m.activeHosts = msg.progress.Hosts
+	if msg.progress.TotalHosts > 0 {
+		m.hostProgressKnown = true
+		m.progressTrack.UpdateHosts(msg.progress.TotalHosts, msg.progress.CompletedHosts)
+	} else if !m.hostProgressKnown {
+		hosts := m.calculateHostsScanned()
+		m.progressTrack.UpdateHosts(hosts, hosts)
+	}
 
 	// Update sparkline data
 	if m.sparklineData != nil {
@@ -371,8 +572,16 @@ func (m *ScanUI) handleScanProgress(msg scanProgressMsg) {
 		m.sparklineData.AddDiscoveryRate(discoveryRate)
 		m.previousOpenCount = open
 
-		// For now, error rate is 0 (we don't track errors explicitly yet)
-		m.sparklineData.AddErrorRate(0.0)
+		// Error rate: the share of probes since the last update that failed
+		probes := msg.progress.Completed - m.previousCompleted
+		failed := msg.progress.Errors - m.previousErrorCount
+		errorRate := 0.0
+		if probes > 0 && failed > 0 {
+			errorRate = float64(failed) / float64(probes) * 100
+		}
+		m.sparklineData.AddErrorRate(errorRate)
+		m.previousCompleted = msg.progress.Completed
+		m.previousErrorCount = msg.progress.Errors
 	}
 }
 
@@ -416,60 +625,33 @@ func (m *ScanUI) isNavigationKey(msg tea.Msg) bool {
 		key.Matches(keyMsg, m.keys.End)
 }
 
+// updateTable re-filters and re-sorts the buffered results. Only rows near
+// the cursor are styled; the rest stay placeholders until scrolled to.
 func (m *ScanUI) updateTable() {
 	baseResults := m.results.Items()
 	filtered := m.filterState.ApplyFilters(baseResults)
 	m.displayResults = m.sortState.ApplySort(filtered)
+	m.groupResults()
 	m.applyTableGeometry()
 
-	stateColors := m.theme.GetStateColors()
-	columns := m.table.Columns()
-	if len(columns) != len(defaultColumnSpecs) {
-		columns = calculateColumnWidths(m.tableViewportWidth())
-	}
+	m.rowCache.reset(m.renderSignature(m.rowColumns()), m.bufferSize)
 
-	widthFor := func(idx int) int {
-		if idx < len(columns) {
-			return columns[idx].Width
-		}
-		return 0
+	rows := make([]table.Row, m.rowCount())
+	for i := range rows {
+		rows[i] = placeholderRow
 	}
+	m.tableRows = rows
 
-	var rows []table.Row
-	for _, r := range m.displayResults {
-		rowStyle := m.theme.GetRowStyle(string(r.State))
-
-		service := getServiceName(r.Port)
-		banner := r.Banner
-		stateDisplay := m.getRowStateDisplay(r, stateColors)
-
-		protocol := r.Protocol
-		if protocol == "" {
-			protocol = "tcp"
+	if len(rows) > 0 {
+		cursor := m.table.Cursor()
+		if cursor >= len(rows) {
+			cursor = len(rows) - 1
 		}
-		protocol = strings.ToUpper(protocol)
-
-		hostCell := rowStyle.Render(truncateToWidth(r.Host, widthFor(0)))
-		portCell := rowStyle.Render(truncateToWidth(fmt.Sprintf("%d", r.Port), widthFor(1)))
-		protocolCell := rowStyle.Render(truncateToWidth(protocol, widthFor(2)))
-		stateCell := truncateStyled(stateDisplay, widthFor(3))
-		serviceCell := rowStyle.Render(truncateToWidth(service, widthFor(4)))
-		bannerCell := rowStyle.Render(truncateToWidth(banner, widthFor(5)))
-		latencyCell := rowStyle.Render(truncateToWidth(fmt.Sprintf("%dms", r.Duration.Milliseconds()), widthFor(6)))
-
-		row := table.Row{
-			hostCell,
-			portCell,
-			protocolCell,
-			stateCell,
-			serviceCell,
-			bannerCell,
-			latencyCell,
+		if cursor < 0 {
+			cursor = 0
 		}
-
-		rows = append(rows, row)
+		m.fillRowWindow(cursor)
 	}
-
 	m.table.SetRows(rows)
 }
 
@@ -503,27 +685,39 @@ func (m *ScanUI) getRowStateDisplay(result core.ResultEvent, colors theme.StateC
 	case core.StateFiltered:
 		stateStyle = stateStyle.Foreground(colors.Filtered)
 	}
-	return stateStyle.Render(string(result.State))
+	return stateStyle.Render(m.theme.StateLabel(string(result.State)))
 }
 
+// listenForResults waits for the next scan event, then keeps collecting
+// events for up to ResultBatchWindow so the table is rebuilt once per batch
+// rather than once per result.
 func (m *ScanUI) listenForResults() tea.Cmd {
 	return func() tea.Msg {
+		var batch scanBatchMsg
+
+		poll := time.NewTimer(ResultPollTimeout)
+		defer poll.Stop()
 		select {
 		case event, ok := <-m.resultChan:
-			if !ok {
-				return scanCompleteMsg{}
+			if !batch.add(event, ok) {
+				return batch
 			}
+		case <-poll.C:
+			return nil
+		}
 
-			switch event.Kind {
-			case core.EventKindResult:
-				return scanResultMsg{result: *event.Result}
-			case core.EventKindProgress:
-				return scanProgressMsg{progress: *event.Progress}
-			case core.EventKindError:
-				return scanCompleteMsg{}
+		window := time.NewTimer(ResultBatchWindow)
+		defer window.Stop()
+		for len(batch.results) < ResultBatchMaxSize {
+			select {
+			case event, ok := <-m.resultChan:
+				if !batch.add(event, ok) {
+					return batch
+				}
+			case <-window.C:
+				return batch
 			}
-		case <-time.After(ResultPollTimeout):
 		}
-		return nil
+		return batch
 	}
 }
@@ -117,6 +117,98 @@ func TestScanUI_HandleKeyMsg_Pause(t *testing.T) {
 	if ui.isPaused {
 		t.Error("scan should be resumed")
 	}
+
+	// Space pauses too.
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	if !ui.isPaused {
+		t.Error("space should pause the scan")
+	}
+}
+
+// fakeScanControl records pause and resume calls from the UI.
+type fakeScanControl struct {
+	paused          bool
+	pauses, resumes int
+}
+
+func (f *fakeScanControl) Pause()       { f.paused = true; f.pauses++ }
+func (f *fakeScanControl) Resume()      { f.paused = false; f.resumes++ }
+func (f *fakeScanControl) Paused() bool { return f.paused }
+
+// TestScanUI_PausePropagatesToScanner tests that pausing suspends the scanner
+func TestScanUI_PausePropagatesToScanner(t *testing.T) {
+	results := make(chan core.Event, 10)
+	close(results)
+
+	ui := NewScanUI(&config.Config{}, 100, results, false)
+	control := &fakeScanControl{}
+	ui.SetScanControl(control)
+
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}}
+	ui.handleKeyMsg(msg)
+	if !control.paused || control.pauses != 1 {
+		t.Errorf("pause should suspend the scanner, got %+v", control)
+	}
+
+	ui.handleKeyMsg(msg)
+	if control.paused || control.resumes != 1 {
+		t.Errorf("second press should resume the scanner, got %+v", control)
+	}
+
+	ui.scanning = false
+	ui.handleKeyMsg(msg)
+	if control.pauses != 1 {
+		t.Error("pause should be ignored once the scan has finished")
+	}
+}
+
+// fakeRateControl records the rates the UI sets, clamped like a scanner.
+type fakeRateControl struct {
+	rate int
+}
+
+func (f *fakeRateControl) SetRate(pps int) int { f.rate = max(pps, 1); return f.rate }
+func (f *fakeRateControl) Rate() int           { return f.rate }
+
+// TestScanUI_RateKeysAdjustScanner tests that + and - change the scanner's rate
+func TestScanUI_RateKeysAdjustScanner(t *testing.T) {
+	results := make(chan core.Event, 10)
+	close(results)
+
+	ui := NewScanUI(&config.Config{}, 100, results, false)
+	control := &fakeRateControl{rate: 7500}
+	ui.SetRateControl(control)
+	ui.viewState = UIViewMain
+	ui.scanning = true
+
+	up := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}}
+	down := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'-'}}
+	ui.handleKeyMsg(up)
+	if control.rate != 8000 || ui.targetRate != 8000 {
+		t.Errorf("+ should raise the rate to 8000, got %d (target %d)", control.rate, ui.targetRate)
+	}
+	ui.handleKeyMsg(down)
+	ui.handleKeyMsg(down)
+	if control.rate != 7000 {
+		t.Errorf("- should lower the rate by %d, got %d", RateStep, control.rate)
+	}
+	if crumb := ui.renderBreadcrumb(); !strings.Contains(crumb, "target 7000 pps") {
+		t.Errorf("breadcrumb should show the target rate: %s", crumb)
+	}
+
+	ui.scanning = false
+	ui.handleKeyMsg(up)
+	if control.rate != 7000 {
+		t.Error("rate keys should be ignored once the scan has finished")
+	}
+
+	unlimited := NewScanUI(&config.Config{}, 100, results, false)
+	unlimited.viewState = UIViewMain
+	unlimited.scanning = true
+	unlimited.handleKeyMsg(up)
+	if !unlimited.toastIsError {
+		t.Error("without a rate control the rate keys should report an error")
+	}
 }
 
 // TestScanUI_HandleKeyMsg_SortMenu tests sort menu toggle
@@ -144,6 +236,38 @@ func TestScanUI_HandleKeyMsg_SortMenu(t *testing.T) {
 	}
 }
 
+// TestScanUI_HandleKeyMsg_ToggleGeo tests the location column toggle
+func TestScanUI_HandleKeyMsg_ToggleGeo(t *testing.T) {
+	results := make(chan core.Event, 10)
+	close(results)
+
+	cfg := &config.Config{}
+	ui := NewScanUI(cfg, 100, results, false)
+	ui.viewState = UIViewMain
+	ui.width, ui.height = 160, 40
+	ui.results.Append(core.ResultEvent{Host: "8.8.8.8", Port: 53, State: core.StateOpen, Country: "US", ASN: 15169, ASOrg: "GOOGLE"})
+	ui.updateTable()
+
+	if w := ui.table.Columns()[geoColumn].Width; w != 0 {
+		t.Fatalf("location column width = %d before toggling, want 0", w)
+	}
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}}
+	if handled, _, _ := ui.handleKeyMsg(msg); !handled {
+		t.Fatal("L should be handled")
+	}
+	if !ui.showGeo || ui.table.Columns()[geoColumn].Width == 0 {
+		t.Fatal("L should show the location column")
+	}
+	if view := ui.table.View(); !strings.Contains(view, "US AS15169 GOOGLE") {
+		t.Errorf("table should show the location:\n%s", view)
+	}
+
+	ui.handleKeyMsg(msg)
+	if ui.showGeo || ui.table.Columns()[geoColumn].Width != 0 {
+		t.Error("a second L should hide the location column")
+	}
+}
+
 // TestScanUI_HandleKeyMsg_Reset tests filter reset
 func TestScanUI_HandleKeyMsg_Reset(t *testing.T) {
 	results := make(chan core.Event, 10)
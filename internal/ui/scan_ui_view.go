@@ -1,12 +1,15 @@
 package ui
 
 import (
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
 	"github.com/mattn/go-runewidth"
 	"github.com/muesli/reflow/ansi"
 	"github.com/muesli/reflow/truncate"
@@ -15,7 +18,7 @@ import (
 // View renders the UI
 func (m *ScanUI) View() string {
 	if m.width == 0 || m.height == 0 {
-		return "Initializing..."
+		return i18n.T("ui.initializing")
 	}
 
 	if m.showHelp {
@@ -37,16 +40,21 @@ func (m *ScanUI) renderMain() string {
 
 	var b strings.Builder
 
-	breadcrumb := m.renderBreadcrumb()
-	b.WriteString(breadcrumb + "\n")
+	b.WriteString(m.renderAboveTable())
+	b.WriteString(m.table.View() + "\n")
+	b.WriteString(m.renderSummaryBar() + "\n")
 
-	header := m.renderHeader()
-	b.WriteString(header + "\n")
+	footer := m.renderFooter()
+	b.WriteString(footer)
 
-	if m.scanning {
-		progressView := m.renderProgress()
-		b.WriteString(progressView + "\n")
-	}
+	return b.String()
+}
+
+// renderAboveTable renders the lines the main view draws above the table.
+func (m *ScanUI) renderAboveTable() string {
+	var b strings.Builder
+
+	b.WriteString(m.renderTopLines())
 
 	status := m.renderStatus()
 	b.WriteString(status + "\n")
@@ -57,11 +65,24 @@ func (m *ScanUI) renderMain() string {
 	}
 
 	b.WriteString("\n")
-	b.WriteString(m.table.View() + "\n")
+	return b.String()
+}
 
-	footer := m.renderFooter()
-	b.WriteString(footer)
+// renderTopLines renders the breadcrumb, header and progress bar that head
+// both the main and the dashboard view.
+func (m *ScanUI) renderTopLines() string {
+	var b strings.Builder
+
+	breadcrumb := m.renderBreadcrumb()
+	b.WriteString(breadcrumb + "\n")
+
+	header := m.renderHeader()
+	b.WriteString(header + "\n")
 
+	if m.scanning {
+		progressView := m.renderProgress()
+		b.WriteString(progressView + "\n")
+	}
 	return b.String()
 }
 
@@ -70,21 +91,24 @@ func (m *ScanUI) renderBreadcrumb() string {
 		Foreground(m.theme.Secondary).
 		Bold(true)
 
-	location := "Port Scanner"
+	location := i18n.T("ui.breadcrumb.title")
 	if m.isPaused {
-		location += " › Paused"
+		location += i18n.T("ui.breadcrumb.paused")
 	} else if m.scanning {
-		location += " › Scanning"
+		location += i18n.T("ui.breadcrumb.scanning")
 	} else {
-		location += " › Complete"
+		location += i18n.T("ui.breadcrumb.complete")
 	}
 
 	// Enhanced scan metrics
 	if m.scanning {
 		// Add scan duration and performance indicator with color coding
 		duration := m.progressTrack.GetElapsedDuration()
-		indicator := m.progressTrack.GetPerformanceIndicator()
-		rate := m.progressTrack.GetFormattedRate() + " ports/sec"
+		indicator := m.symbols().Text(m.progressTrack.GetPerformanceIndicator())
+		rate := m.progressTrack.GetFormattedRate() + i18n.T("ui.rate")
+		if m.targetRate > 0 {
+			rate += i18n.T("ui.breadcrumb.target", m.targetRate)
+		}
 
 		// Color code performance indicator
 		indicatorStyle := m.getPerformanceIndicatorStyle()
@@ -96,7 +120,7 @@ func (m *ScanUI) renderBreadcrumb() string {
 		eta := m.progressTrack.GetDetailedETA()
 
 		// Build metrics string with color-coded status
-		metrics := fmt.Sprintf(" • Running: %s • %s • Hosts: %s • Progress: %s complete • ETA: %s • %s",
+		metrics := i18n.T("ui.breadcrumb.running",
 			coloredIndicator,
 			duration,
 			hosts,
@@ -110,7 +134,7 @@ func (m *ScanUI) renderBreadcrumb() string {
 		total, open, closed, filtered := m.stats.Totals()
 		duration := m.progressTrack.GetElapsedDuration()
 
-		metrics := fmt.Sprintf(" • %d total • %d open • %d closed • %d filtered • Duration: %s • Complete ✓",
+		metrics := i18n.T("ui.breadcrumb.summary",
 			total, open, closed, filtered, duration)
 
 		return style.Render(location + metrics)
@@ -124,11 +148,9 @@ func (m *ScanUI) getPerformanceIndicatorStyle() lipgloss.Style {
 
 	switch trend {
 	case TrendImproving:
-		// Green for improving
-		return style.Foreground(lipgloss.Color("#00FF00"))
+		return style.Foreground(m.theme.Success)
 	case TrendDegrading:
-		// Red for degrading
-		return style.Foreground(lipgloss.Color("#FF4444"))
+		return style.Foreground(m.theme.Danger)
 	default:
 		// Blue/gray for stable
 		return style.Foreground(m.theme.Secondary)
@@ -145,20 +167,20 @@ func (m *ScanUI) getStatusMessage() string {
 
 	switch trend {
 	case TrendImproving:
-		message = "Performance improving"
-		color = lipgloss.Color("#00FF00") // Green
+		message = i18n.T("ui.performance.improving")
+		color = m.theme.Success
 	case TrendDegrading:
-		message = "Performance degrading"
-		color = lipgloss.Color("#FF4444") // Red
+		message = i18n.T("ui.performance.degrading")
+		color = m.theme.Danger
 	default:
 		if rate >= 5000 {
-			message = "High performance"
+			message = i18n.T("ui.performance.high")
 			color = m.theme.Primary
 		} else if rate >= 2000 {
-			message = "Normal performance"
+			message = i18n.T("ui.performance.normal")
 			color = m.theme.Info
 		} else {
-			message = "Low performance"
+			message = i18n.T("ui.performance.low")
 			color = m.theme.Warning
 		}
 	}
@@ -176,12 +198,12 @@ func (m *ScanUI) renderHeader() string {
 	if m.scanning && !m.isPaused {
 		icon = m.spinner.View() + " "
 	} else if m.isPaused {
-		icon = "⏸ "
+		icon = m.symbols().Text("⏸ ")
 	} else {
-		icon = "✓ "
+		icon = m.symbols().Text("✓ ")
 	}
 
-	return titleStyle.Render(icon + "Port Scanner Results")
+	return titleStyle.Render(icon + i18n.T("ui.header"))
 }
 
 func (m *ScanUI) renderProgress() string {
@@ -190,13 +212,13 @@ func (m *ScanUI) renderProgress() string {
 
 	// Add rate display
 	rateStyle := lipgloss.NewStyle().Foreground(m.theme.Secondary)
-	rateText := rateStyle.Render(fmt.Sprintf("  %0.1f pps • ETA: %s", m.currentRate, formatDuration(m.progressTrack.GetETA())))
+	rateText := rateStyle.Render(m.symbols().Text(fmt.Sprintf("  %0.1f pps • ETA: %s", m.currentRate, formatDuration(m.progressTrack.GetETA()))))
 
 	return progressBar + rateText
 }
 
 func (m *ScanUI) renderStatus() string {
-	status := m.progressTrack.GetStatusLine()
+	status := m.symbols().Text(m.progressTrack.GetStatusLine())
 	details := m.progressTrack.GetDetailedStats()
 
 	// Color-code based on status
@@ -212,19 +234,54 @@ func (m *ScanUI) renderStatus() string {
 	detailStyle := lipgloss.NewStyle().Foreground(m.theme.Secondary)
 
 	// Add elapsed time to details
-	elapsed := fmt.Sprintf(" • Elapsed: %s", formatDuration(m.progressTrack.GetActiveTime()))
+	elapsed := i18n.T("ui.status.elapsed", formatDuration(m.progressTrack.GetActiveTime()))
 	enhancedDetails := details + elapsed
+	if n := m.scanErrors.Count(); n > 0 {
+		enhancedDetails += i18n.T("ui.status.errors", n)
+	}
 
 	return statusStyle.Render(status) + "\n" + detailStyle.Render(enhancedDetails)
 }
 
 func (m *ScanUI) renderFooter() string {
+	if m.searchActive {
+		return m.renderSearchBar()
+	}
+	if m.toast != "" {
+		return m.renderToast()
+	}
+
 	footerStyle := lipgloss.NewStyle().
 		Foreground(m.theme.Secondary)
 
 	return footerStyle.Render(m.help.View(m.keys))
 }
 
+// renderSummaryBar renders the line kept below the table with the state
+// counts, the number of hosts seen and the filter in effect, so they stay
+// in view however far the table is scrolled.
+func (m *ScanUI) renderSummaryBar() string {
+	_, open, closed, filtered := m.stats.Totals()
+	colors := m.theme.GetStateColors()
+	muted := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	sep := muted.Render(m.symbols().Text(" • "))
+
+	filter := m.filterState.GetActiveFilterDescription()
+	if filter == "" {
+		filter = i18n.T("ui.summary.no_filter")
+	}
+
+	parts := []string{
+		lipgloss.NewStyle().Foreground(colors.Open).Render(i18n.T("ui.summary.open", open)),
+		lipgloss.NewStyle().Foreground(colors.Closed).Render(i18n.T("ui.summary.closed", closed)),
+		lipgloss.NewStyle().Foreground(colors.Filtered).Render(i18n.T("ui.summary.filtered", filtered)),
+		muted.Render(i18n.T("ui.summary.hosts", m.stats.Hosts())),
+		muted.Render(filter),
+		muted.Render(i18n.T("ui.summary.shown", len(m.displayResults))),
+	}
+	return truncate.String(strings.Join(parts, sep), uint(max(0, m.width)))
+}
+
 func (m *ScanUI) renderSortFilterIndicators() string {
 	style := lipgloss.NewStyle().
 		Foreground(m.theme.Secondary).
@@ -233,7 +290,7 @@ func (m *ScanUI) renderSortFilterIndicators() string {
 	var indicators []string
 
 	if m.sortState.IsActive {
-		indicators = append(indicators, m.sortState.GetSortDescription())
+		indicators = append(indicators, m.symbols().Text(m.sortState.GetSortDescription()))
 	}
 
 	filterDesc := m.filterState.GetActiveFilterDescription()
@@ -241,8 +298,16 @@ func (m *ScanUI) renderSortFilterIndicators() string {
 		indicators = append(indicators, filterDesc)
 	}
 
+	if count := m.selection.Count(); count > 0 {
+		indicators = append(indicators, i18n.T("ui.selected", count))
+	}
+
+	if overflow := m.overflowDescription(); overflow != "" {
+		indicators = append(indicators, overflow)
+	}
+
 	if len(indicators) > 0 {
-		return style.Render("▶ " + strings.Join(indicators, " | "))
+		return style.Render(m.symbols().Text("▶ ") + strings.Join(indicators, " | "))
 	}
 	return ""
 }
@@ -250,31 +315,10 @@ func (m *ScanUI) renderSortFilterIndicators() string {
 func (m *ScanUI) renderHelp() string {
 	helpStyle := lipgloss.NewStyle().
 		Padding(2).
-		Border(lipgloss.RoundedBorder()).
+		Border(m.symbols().Border).
 		BorderForeground(m.theme.Primary)
 
-	content := `
-📖 KEYBOARD SHORTCUTS
-
-Navigation:
-  ↑/k        Move up
-  ↓/j        Move down
-  PgUp/PgDn  Page up/down
-  g/G        Jump to top/bottom
-
-Filtering & Sorting:
-  s          Sort options (modal)
-  r          Reset filters
-  o          Toggle open-only
-
-View Controls:
-  D          Toggle dashboard view
-  Enter      View details
-  p / Space  Pause/resume
-  ?          Toggle help
-  Ctrl+L     Clear screen
-  q / Esc    Quit/Close modal
-`
+	content := i18n.T("ui.help")
 
 	return helpStyle.Render(content)
 }
@@ -299,9 +343,30 @@ func (m *ScanUI) renderModalOverlay() string {
 		modalContent = m.renderSortModal()
 	case ModalDetails:
 		modalContent = m.renderDetailsModal()
+	case ModalExport:
+		modalContent = m.renderExportModal()
+	case ModalBulk:
+		modalContent = m.renderBulkModal()
+	case ModalTag:
+		modalContent = m.renderTagModal()
+	case ModalNote:
+		modalContent = m.renderNoteModal()
+	case ModalQuit:
+		modalContent = m.renderQuitModal()
+	case ModalColumns:
+		modalContent = m.renderColumnsModal()
+	case ModalPalette:
+		modalContent = m.renderPaletteModal()
+	case ModalTheme:
+		modalContent = m.renderThemeModal()
+	case ModalHost:
+		modalContent = m.renderHostSummaryModal()
+	case ModalFilter:
+		modalContent = m.renderFilterModal()
 	default:
 		modalContent = ""
 	}
+	modalContent = m.withModalCloseButton(m.symbols().Text(modalContent), modalWidth-2*ModalBorderPadding)
 
 	modalStyle := m.theme.ModalStyle(ModalBorderPadding).
 		Width(modalWidth).
@@ -318,6 +383,7 @@ func (m *ScanUI) renderModalOverlay() string {
 		actualModalHeight = availableHeight
 	}
 
+	renderedWidth := lipgloss.Width(styledModal)
 	modalX := max((availableWidth-modalWidth)/2, 0)
 	modalY := max((availableHeight-actualModalHeight)/2, 0)
 
@@ -341,9 +407,10 @@ func (m *ScanUI) renderModalOverlay() string {
 			lines = append(lines, padLine("", availableWidth))
 		}
 
+		// Border lines are wider than modalWidth, which excludes the border.
 		left := truncate.String(lines[row], uint(modalX))
-		body := lipgloss.NewStyle().Width(modalWidth).Render(modalLine)
-		right := sliceFromColumn(lines[row], modalX+modalWidth)
+		body := padLine(modalLine, renderedWidth)
+		right := sliceFromColumn(lines[row], modalX+renderedWidth)
 
 		lines[row] = left + body + right
 	}
@@ -398,6 +465,18 @@ func sliceFromColumn(line string, x int) string {
 	return ""
 }
 
+// sortOptions are the entries of the sort modal, in cursor order.
+var sortOptions = []string{
+	"1. Port (ascending)",
+	"2. Port (descending)",
+	"3. Host (A → Z)",
+	"4. State (Open → Closed → Filtered)",
+	"5. Service (alphabetical)",
+	"6. Latency (fastest first)",
+	"7. Latency (slowest first)",
+	"8. Discovery order (original)",
+}
+
 // renderSortModal renders the sort options modal
 func (m *ScanUI) renderSortModal() string {
 	var b strings.Builder
@@ -411,18 +490,8 @@ func (m *ScanUI) renderSortModal() string {
 	b.WriteString(title + "\n\n")
 
 	// Options
-	options := []string{
-		"1. Port (ascending)",
-		"2. Port (descending)",
-		"3. Host (A → Z)",
-		"4. State (Open → Closed → Filtered)",
-		"5. Service (alphabetical)",
-		"6. Latency (fastest first)",
-		"7. Latency (slowest first)",
-		"8. Discovery order (original)",
-	}
-
-	for i, option := range options {
+	m.modalState.OptionsTop = strings.Count(b.String(), "\n")
+	for i, option := range sortOptions {
 		style := lipgloss.NewStyle()
 		if i == m.modalState.Cursor {
 			style = style.Background(m.theme.Primary).Foreground(m.theme.Background)
@@ -448,15 +517,11 @@ func (m *ScanUI) renderSortModal() string {
 
 // renderDetailsModal renders the details view for a selected result
 func (m *ScanUI) renderDetailsModal() string {
-	if len(m.displayResults) == 0 {
+	selectedResult, ok := m.selectedResult()
+	if !ok {
 		return "No results to display"
 	}
 
-	selectedResult := m.displayResults[m.table.Cursor()]
-
-	// Calculate available content area for scrolling
-	availableHeight := maxModalContentHeight - 10 // Account for title/borders
-
 	// Build full content
 	var fullContent strings.Builder
 
@@ -465,32 +530,145 @@ func (m *ScanUI) renderDetailsModal() string {
 		Bold(true).
 		Foreground(m.theme.Primary).
 		Render(fmt.Sprintf("📋 %s:%d (%s)", selectedResult.Host, selectedResult.Port, selectedResult.State))
-	fullContent.WriteString(title + "\n\n")
+	fullContent.WriteString(title + "  " + m.renderDetailsTabs() + "\n\n")
+
+	keys := "↑/↓: Scroll • Tab: JSON/Raw • x: Hex/ASCII • y: Copy banner • r: Re-scan • t: Note • ESC: Return to main view"
+	if m.detailsRaw {
+		m.writeDetailsRaw(&fullContent, selectedResult)
+		keys = "↑/↓: Scroll • Tab: Details • x: Hex/ASCII • y: Copy JSON and transcript • ESC: Return to main view"
+	} else {
+		m.writeDetailsSummary(&fullContent, selectedResult)
+	}
+
+	// Instructions
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Render(keys)
+	fullContent.WriteString("\n" + instructions)
+
+	return m.scrollModalContent(fullContent.String(), modalScrollLines)
+}
+
+// scrollModalContent shows the lines of a scrollable modal's content that
+// fit in availableHeight from the scroll position, with a line saying which
+// are shown.
+func (m *ScanUI) scrollModalContent(content string, availableHeight int) string {
+	// Track content height for scrolling
+	contentLines := strings.Split(content, "\n")
+	m.modalState.MaxScrollHeight = len(contentLines)
+
+	// Apply scrolling
+	if availableHeight > 0 && len(contentLines) > availableHeight {
+		// Scrolling needed - show only visible portion
+		start := m.modalState.ScrollPosition
+		end := min(start+availableHeight, len(contentLines))
+		visibleLines := contentLines[start:end]
+
+		scrollIndicator := ""
+		if start > 0 {
+			scrollIndicator += "▲"
+		} else {
+			scrollIndicator += " "
+		}
+		if end < len(contentLines) {
+			scrollIndicator += "▼"
+		} else {
+			scrollIndicator += " "
+		}
+
+		scrollStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+		scrollBar := scrollStyle.Render(fmt.Sprintf("Lines %d-%d of %d %s",
+			start+1, end, len(contentLines), scrollIndicator))
+
+		return strings.Join(visibleLines, "\n") + "\n\n" + scrollBar
+	}
+
+	// No scrolling needed
+	return content
+}
 
+// writeDetailsSummary writes the details tab: what is known about the
+// selected result, section by section.
+func (m *ScanUI) writeDetailsSummary(fullContent *strings.Builder, selectedResult core.ResultEvent) {
 	// Host information
 	section := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(m.theme.Secondary).
 		Render("🌐 Host Information")
 	fullContent.WriteString(section + "\n")
-	service := getServiceName(selectedResult.Port)
+	service := serviceName(selectedResult)
 	hostInfo := fmt.Sprintf("  Host: %s\n  Port: %d/%s\n  State: %s\n  Service: %s",
 		selectedResult.Host, selectedResult.Port, selectedResult.Protocol,
 		selectedResult.State, service)
+	if selectedResult.Hostname != "" {
+		hostInfo += "\n  Hostname: " + selectedResult.Hostname
+	}
+	if selectedResult.Verified {
+		hostInfo += "\n  Verified: yes (re-connected after scan)"
+	}
+	if selectedResult.Edge != "" {
+		hostInfo += "\n  Edge: " + selectedResult.Edge + " (CDN/WAF front end, not the origin)"
+	}
+	if device := deviceLabel(selectedResult.DeviceName, selectedResult.DeviceType); device != "" {
+		hostInfo += "\n  Device: " + device
+	}
+	if owner := ownerLabel(selectedResult.Owner); owner != "" {
+		hostInfo += "\n  Owner: " + owner
+	}
+	if location := geoLabel(selectedResult); location != "" {
+		hostInfo += "\n  Location: " + location
+	}
+	for _, e := range selectedResult.Exposures {
+		hostInfo += "\n  Exposure: [" + e.Severity + "] " + e.Message
+	}
+	if tags := m.tagsFor(selectedResult); len(tags) > 0 {
+		hostInfo += "\n  Tags: " + strings.Join(tags, ", ")
+	}
+	if note := m.noteFor(selectedResult); note != "" {
+		hostInfo += "\n  Note: " + note
+	}
 	fullContent.WriteString(hostInfo + "\n\n")
 
+	// TLS handshake from --tls-inspect
+	if selectedResult.TLS != nil {
+		section = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(m.theme.Secondary).
+			Render("🔒 TLS")
+		fullContent.WriteString(section + "\n")
+		for _, line := range tlsDetailLines(selectedResult.TLS) {
+			fullContent.WriteString("  " + line + "\n")
+		}
+		fullContent.WriteString("\n")
+	}
+
+	// Security header audit from --http-audit
+	if selectedResult.HTTPAudit != nil {
+		section = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(m.theme.Secondary).
+			Render("🛡️  HTTP Security Headers")
+		fullContent.WriteString(section + "\n")
+		for _, line := range httpAuditDetailLines(selectedResult.HTTPAudit) {
+			fullContent.WriteString("  " + line + "\n")
+		}
+		fullContent.WriteString("\n")
+	}
+
 	// Banner information (scrollable)
 	if selectedResult.Banner != "" {
+		heading := "🏷️  Service Banner (ASCII)"
+		if m.bannerHex {
+			heading = "🏷️  Service Banner (hex)"
+		}
 		section = lipgloss.NewStyle().
 			Bold(true).
 			Foreground(m.theme.Secondary).
-			Render("🏷️  Service Banner")
+			Render(heading)
 		fullContent.WriteString(section + "\n")
 
-		// Keep original formatting for banner (don't truncate)
-		bannerLines := strings.Split(selectedResult.Banner, "\n")
-		for _, line := range bannerLines {
-			fullContent.WriteString("  " + strings.TrimSpace(line) + "\n")
+		for _, line := range bannerDetailLines(selectedResult.Banner, m.bannerHex) {
+			fullContent.WriteString("  " + line + "\n")
 		}
 		fullContent.WriteString("\n")
 	}
@@ -514,63 +692,15 @@ func (m *ScanUI) renderDetailsModal() string {
 		Render("🔍 Network Analysis")
 	fullContent.WriteString(section + "\n")
 
-	// Check if it's a common service port
-	correctService := getServiceName(selectedResult.Port)
-	serviceAnalysis := fmt.Sprintf("  Expected Service: %s", correctService)
-	// Note: We can't check for service mismatch since ResultEvent doesn't contain detected service
-	serviceAnalysis += " (expected)"
-
-	// Categorize port state
-	stateAnalysis := ""
-	switch selectedResult.State {
-	case core.StateOpen:
-		stateAnalysis = "  🔓 Port is Open (listening for connections)"
-	case core.StateClosed:
-		stateAnalysis = "  🔒 Port is Closed (not accepting connections)"
-	case core.StateFiltered:
-		stateAnalysis = "  🚫 Port is Filtered (blocked by firewall)"
+	fullContent.WriteString(expectedServiceAnalysis(selectedResult) + "\n")
+	if detected := detectedServiceAnalysis(selectedResult); detected != "" {
+		fullContent.WriteString(detected + "\n")
 	}
-
-	fullContent.WriteString(serviceAnalysis + "\n" + stateAnalysis + "\n")
-
-	// Instructions
-	instructions := lipgloss.NewStyle().
-		Foreground(m.theme.Muted).
-		Render("↑/↓: Scroll • ESC: Return to main view")
-	fullContent.WriteString("\n" + instructions)
-
-	// Track content height for scrolling
-	contentLines := strings.Split(fullContent.String(), "\n")
-	m.modalState.MaxScrollHeight = len(contentLines)
-
-	// Apply scrolling
-	if availableHeight > 0 && len(contentLines) > availableHeight {
-		// Scrolling needed - show only visible portion
-		start := m.modalState.ScrollPosition
-		end := min(start+availableHeight, len(contentLines))
-		visibleLines := contentLines[start:end]
-
-		scrollIndicator := ""
-		if start > 0 {
-			scrollIndicator += "▲"
-		} else {
-			scrollIndicator += " "
-		}
-		if end < len(contentLines) {
-			scrollIndicator += "▼"
-		} else {
-			scrollIndicator += " "
-		}
-
-		scrollStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
-		scrollBar := scrollStyle.Render(fmt.Sprintf("Lines %d-%d of %d %s",
-			start+1, end, len(contentLines), scrollIndicator))
-
-		return strings.Join(visibleLines, "\n") + "\n\n" + scrollBar
+	if mismatch := mismatchAnalysis(selectedResult); mismatch != "" {
+		warning := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Warning)
+		fullContent.WriteString(warning.Render(mismatch) + "\n")
 	}
-
-	// No scrolling needed
-	return fullContent.String()
+	fullContent.WriteString(stateAnalysis(selectedResult) + "\n")
 }
 
 // Helper function for modal content height calculation
@@ -578,9 +708,67 @@ func getMaxModalContentHeight() int {
 	return 20 // Default maximum content lines for modal
 }
 
+// bannerDetailLines formats a banner for the details modal: a hex dump, or
+// its lines with control and binary bytes escaped.
+func bannerDetailLines(banner string, asHex bool) []string {
+	if asHex {
+		return strings.Split(strings.TrimRight(hex.Dump([]byte(banner)), "\n"), "\n")
+	}
+	lines := strings.Split(strings.TrimRight(banner, "\r\n"), "\n")
+	for i, line := range lines {
+		lines[i] = exporter.EscapeBanner(strings.TrimRight(line, "\r"))
+	}
+	return lines
+}
+
+// tlsDetailLines formats an inspected TLS handshake for the details modal.
+func tlsDetailLines(info *core.TLSInfo) []string {
+	version := info.Version
+	if info.StartTLS != "" {
+		version += " (STARTTLS over " + strings.ToUpper(info.StartTLS) + ")"
+	}
+	lines := []string{"Version: " + version, "Cipher: " + info.Cipher}
+	if info.Subject != "" {
+		lines = append(lines, "Subject: "+info.Subject)
+	}
+	if info.Issuer != "" {
+		lines = append(lines, "Issuer: "+info.Issuer)
+	}
+	if len(info.SANs) > 0 {
+		lines = append(lines, "Names: "+strings.Join(info.SANs, ", "))
+	}
+	if !info.NotAfter.IsZero() {
+		lines = append(lines, "Expires: "+info.NotAfter.UTC().Format("2006-01-02"))
+	}
+	if info.JA3S != "" {
+		lines = append(lines, "JA3S: "+info.JA3S)
+	}
+	return lines
+}
+
+// httpAuditDetailLines formats a security header audit for the details
+// modal.
+func httpAuditDetailLines(audit *core.HTTPAudit) []string {
+	lines := []string{fmt.Sprintf("Grade: %s (HTTP %d)", audit.Grade, audit.Status)}
+	if len(audit.Present) > 0 {
+		lines = append(lines, "Present: "+strings.Join(audit.Present, ", "))
+	}
+	if len(audit.Missing) > 0 {
+		lines = append(lines, "Missing: "+strings.Join(audit.Missing, ", "))
+	}
+	for _, location := range audit.Redirects {
+		lines = append(lines, "Redirect: "+location)
+	}
+	return lines
+}
+
 // Add the constant at the top
 const (
 	maxModalContentHeight = 20
+
+	// modalScrollLines is how many content lines a scrollable modal shows
+	// at once, leaving room for its title and borders.
+	modalScrollLines = maxModalContentHeight - 10
 )
 
 // renderDashboardView renders the split view dashboard
@@ -588,44 +776,46 @@ func (m *ScanUI) renderDashboardView() string {
 	var b strings.Builder
 
 	// Header
-	breadcrumb := m.renderBreadcrumb()
-	b.WriteString(breadcrumb + "\n")
-
-	header := m.renderHeader()
-	b.WriteString(header + "\n")
-
-	if m.scanning {
-		progressView := m.renderProgress()
-		b.WriteString(progressView + "\n")
-	}
+	b.WriteString(m.renderTopLines())
 
 	// Calculate split dimensions
-	leftWidth := int(float64(m.width) * 0.65)
+	leftWidth := m.dashboardLeftWidth()
 	rightWidth := m.width - leftWidth - 3 // -3 for spacing
 
 	// Left side: Results table
 	tableView := m.table.View()
 
-	// Right side: Stats panel
-	statsPanel := m.renderStatsPanel(rightWidth)
+	// Right side: the selected dashboard tab
+	panelHeight := m.height - 8
+	panel := m.renderDashboardTabs() + "\n\n"
+	// Border and padding take four columns and four rows, the tab bar two.
+	switch m.dashboardTab {
+	case DashboardTabInventory:
+		panel += m.renderInventoryPanel(rightWidth-4, panelHeight-6)
+	case DashboardTabHosts:
+		panel += m.renderHostProgressPanel(rightWidth-4, panelHeight-6)
+	default:
+		panel += m.renderStatsPanel(rightWidth)
+	}
 
 	// Join horizontally
 	leftStyle := lipgloss.NewStyle().
 		Width(leftWidth).
-		Height(m.height - 8)
+		Height(panelHeight)
 
 	rightStyle := lipgloss.NewStyle().
 		Width(rightWidth).
-		Height(m.height - 8).
-		Border(lipgloss.RoundedBorder()).
+		Height(panelHeight).
+		Border(m.symbols().Border).
 		BorderForeground(m.theme.Primary).
 		Padding(1)
 
 	leftContent := leftStyle.Render(tableView)
-	rightContent := rightStyle.Render(statsPanel)
+	rightContent := rightStyle.Render(m.symbols().Text(panel))
 
 	dashboard := lipgloss.JoinHorizontal(lipgloss.Top, leftContent, " ", rightContent)
 	b.WriteString(dashboard + "\n")
+	b.WriteString(m.renderSummaryBar() + "\n")
 
 	// Footer
 	footer := m.renderFooter()
@@ -634,6 +824,11 @@ func (m *ScanUI) renderDashboardView() string {
 	return b.String()
 }
 
+// dashboardLeftWidth returns the width of the dashboard's table side.
+func (m *ScanUI) dashboardLeftWidth() int {
+	return int(float64(m.width) * 0.65)
+}
+
 // renderStatsPanel renders the statistics panel content
 func (m *ScanUI) renderStatsPanel(width int) string {
 	if m.statsData == nil {
@@ -653,6 +848,16 @@ func (m *ScanUI) renderStatsPanel(width int) string {
 	// Port State Distribution
 	b.WriteString(m.renderMiniBarChart() + "\n\n")
 
+	// TCP vs UDP breakdown when both are scanned
+	if m.config.Protocol == "both" {
+		b.WriteString(m.renderProtocolBreakdown(width-4) + "\n")
+	}
+
+	// Error ticker (border and padding take four columns)
+	if ticker := m.renderErrorTicker(width - 4); ticker != "" {
+		b.WriteString(ticker + "\n")
+	}
+
 	// Top Services
 	sectionStyle := lipgloss.NewStyle().
 		Bold(true).
@@ -668,6 +873,22 @@ func (m *ScanUI) renderStatsPanel(width int) string {
 	}
 	b.WriteString("\n")
 
+	// GeoIP breakdowns
+	if len(stats.TopASNs) > 0 {
+		b.WriteString(sectionStyle.Render("Open Ports by ASN:") + "\n")
+		for i, as := range stats.TopASNs {
+			b.WriteString(fmt.Sprintf("  %d. %-20s %3d ports\n", i+1, truncateToWidth(as.Name, 20), as.Count))
+		}
+		b.WriteString("\n")
+	}
+	if len(stats.TopCountries) > 0 {
+		b.WriteString(sectionStyle.Render("Open Ports by Country:") + "\n")
+		for i, country := range stats.TopCountries {
+			b.WriteString(fmt.Sprintf("  %d. %-12s %3d ports\n", i+1, country.Name, country.Count))
+		}
+		b.WriteString("\n")
+	}
+
 	// Performance Metrics
 	b.WriteString(sectionStyle.Render("Performance:") + "\n")
 	b.WriteString(fmt.Sprintf("  Current:  %7.0f pps\n", stats.CurrentRate))
@@ -715,34 +936,61 @@ func (m *ScanUI) renderMiniBarChart() string {
 	filteredBar := int((float64(stats.FilteredCount) / total) * float64(maxBarWidth))
 
 	// Color styles
-	openStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00"))
-	closedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000"))
-	filteredStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500"))
+	stateColors := m.theme.GetStateColors()
+	openStyle := lipgloss.NewStyle().Foreground(stateColors.Open)
+	closedStyle := lipgloss.NewStyle().Foreground(stateColors.Closed)
+	filteredStyle := lipgloss.NewStyle().Foreground(stateColors.Filtered)
 	labelStyle := lipgloss.NewStyle().Width(StatusBarLabelWidth)
+	block := string(m.symbols().BarFull)
 
 	var b strings.Builder
 
 	// Open ports
 	openPct := getPercentage(stats.OpenCount, stats.TotalResults)
-	b.WriteString(labelStyle.Render("Open:") + " ")
-	b.WriteString(openStyle.Render(strings.Repeat("█", openBar)))
+	b.WriteString(labelStyle.Render(m.stateChartLabel("open", "Open")) + " ")
+	b.WriteString(openStyle.Render(strings.Repeat(block, openBar)))
 	b.WriteString(fmt.Sprintf(" %d (%.1f%%)\n", stats.OpenCount, openPct))
 
 	// Closed ports
 	closedPct := getPercentage(stats.ClosedCount, stats.TotalResults)
-	b.WriteString(labelStyle.Render("Closed:") + " ")
-	b.WriteString(closedStyle.Render(strings.Repeat("█", closedBar)))
+	b.WriteString(labelStyle.Render(m.stateChartLabel("closed", "Closed")) + " ")
+	b.WriteString(closedStyle.Render(strings.Repeat(block, closedBar)))
 	b.WriteString(fmt.Sprintf(" %d (%.1f%%)\n", stats.ClosedCount, closedPct))
 
 	// Filtered ports
 	filteredPct := getPercentage(stats.FilteredCount, stats.TotalResults)
-	b.WriteString(labelStyle.Render("Filtered:") + " ")
-	b.WriteString(filteredStyle.Render(strings.Repeat("█", filteredBar)))
+	b.WriteString(labelStyle.Render(m.stateChartLabel("filtered", "Filtered")) + " ")
+	b.WriteString(filteredStyle.Render(strings.Repeat(block, filteredBar)))
 	b.WriteString(fmt.Sprintf(" %d (%.1f%%)", stats.FilteredCount, filteredPct))
 
 	return b.String()
 }
 
+// stateChartLabel returns the bar chart label for a port state, prefixed
+// with its glyph when the theme uses glyphs.
+func (m *ScanUI) stateChartLabel(state, label string) string {
+	if glyph := m.symbols().State(state); m.theme.Glyphs && glyph != "" {
+		return glyph + " " + label + ":"
+	}
+	return label + ":"
+}
+
+// renderProtocolBreakdown counts the TCP and UDP results by state.
+func (m *ScanUI) renderProtocolBreakdown(width int) string {
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Secondary)
+
+	var b strings.Builder
+	b.WriteString(sectionStyle.Render("TCP vs UDP:") + "\n")
+	for _, p := range m.statsData.Protocols {
+		line := fmt.Sprintf("  %s: %d ports • %d open • %d closed • %d filtered",
+			strings.ToUpper(p.Name), p.Total(), p.Open, p.Closed, p.Filtered)
+		b.WriteString(truncateToWidth(line, width) + "\n")
+	}
+	return b.String()
+}
+
 // renderSparklines renders the sparkline charts for the dashboard
 func (m *ScanUI) renderSparklines() string {
 	if m.sparklineData == nil {
@@ -775,5 +1023,49 @@ func (m *ScanUI) renderSparklines() string {
 			summary.CurrentDiscoveryRate, summary.AverageDiscoveryRate))
 	}
 
+	// Error Rate Sparkline, as a share of probes
+	if len(m.sparklineData.ErrorRate) > 0 {
+		b.WriteString("\n" + sectionStyle.Render("Error Rate (60s):") + "\n")
+		errorSparkline := m.sparklineData.RenderSparkline(m.sparklineData.ErrorRate, 20)
+		errorStyle := lipgloss.NewStyle().Foreground(m.theme.Danger)
+		b.WriteString("  " + errorStyle.Render(errorSparkline) + "\n")
+		b.WriteString(fmt.Sprintf("  Cur: %0.1f%% • Avg: %0.1f%% of probes\n",
+			summary.CurrentErrorRate, summary.AverageErrorRate))
+	}
+
 	return b.String()
 }
+
+// geoLabel describes where GeoIP places a host, e.g. "US AS15169 GOOGLE".
+func geoLabel(r core.ResultEvent) string {
+	var parts []string
+	if r.Country != "" {
+		parts = append(parts, r.Country)
+	}
+	if r.ASN != 0 {
+		parts = append(parts, fmt.Sprintf("AS%d", r.ASN))
+	}
+	if r.ASOrg != "" {
+		parts = append(parts, r.ASOrg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// ownerLabel describes who holds a host's network, e.g. "Google LLC,
+// abuse network-abuse@google.com (8.8.8.0 - 8.8.8.255)".
+func ownerLabel(owner *core.Ownership) string {
+	if owner == nil {
+		return ""
+	}
+	label := owner.Org
+	if owner.Abuse != "" {
+		if label != "" {
+			label += ", "
+		}
+		label += "abuse " + owner.Abuse
+	}
+	if owner.Range != "" {
+		label += " (" + owner.Range + ")"
+	}
+	return label
+}
@@ -1,11 +1,17 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lucchesi-sec/portscan/internal/core"
 	"github.com/lucchesi-sec/portscan/pkg/config"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/lucchesi-sec/portscan/pkg/theme"
 )
 
 // TestScanUI_View_ShowHelp tests help view rendering
@@ -440,6 +446,37 @@ func TestScanUI_RenderFooter(t *testing.T) {
 }
 
 // TestScanUI_RenderSortFilterIndicators tests indicator rendering
+func TestScanUI_SummaryBarStaysVisible(t *testing.T) {
+	ui := NewScanUI(&config.Config{}, 200, make(chan core.Event), false)
+	ui.handleWindowSize(tea.WindowSizeMsg{Width: 120, Height: 30})
+	results := make([]core.ResultEvent, 200)
+	for i := range results {
+		state := core.StateClosed
+		if i%10 == 0 {
+			state = core.StateOpen
+		}
+		results[i] = core.ResultEvent{Host: fmt.Sprintf("10.0.%d.1", i%4), Port: uint16(i + 1), State: state}
+	}
+	ui.recordResults(results)
+	ui.toggleOpenOnly()
+	ui.table.GotoBottom()
+
+	view := ui.View()
+	if lines := strings.Count(view, "\n") + 1; lines > ui.height {
+		t.Errorf("view is %d lines, taller than %d", lines, ui.height)
+	}
+	for _, want := range []string{"20 open", "180 closed", "0 filtered", "4 hosts", "Filters: Open", "20 shown"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("summary lacks %q", want)
+		}
+	}
+
+	ui.resetFilters()
+	if !strings.Contains(ui.renderSummaryBar(), "No filters") {
+		t.Errorf("summary without filters = %q", ui.renderSummaryBar())
+	}
+}
+
 func TestScanUI_RenderSortFilterIndicators(t *testing.T) {
 	results := make(chan core.Event, 10)
 	close(results)
@@ -557,3 +594,179 @@ func TestScanUI_ViewStateTransitions(t *testing.T) {
 		})
 	}
 }
+
+func TestScanUI_AccessibleModeAddsStateGlyphs(t *testing.T) {
+	cfg := &config.Config{UI: config.UIConfig{Accessible: true}}
+	ui := NewScanUI(cfg, 10, make(chan core.Event), false)
+	ui.results.Append(core.ResultEvent{Host: "localhost", Port: 22, State: core.StateOpen})
+	ui.results.Append(core.ResultEvent{Host: "localhost", Port: 23, State: core.StateClosed})
+	ui.stats.Add(core.ResultEvent{Host: "localhost", Port: 22, State: core.StateOpen})
+	ui.stats.Add(core.ResultEvent{Host: "localhost", Port: 23, State: core.StateClosed})
+	ui.updateTable()
+
+	var states []string
+	for _, row := range ui.table.Rows() {
+		states = append(states, row[3])
+	}
+	joined := strings.Join(states, " ")
+	if !strings.Contains(joined, theme.GlyphOpen) || !strings.Contains(joined, theme.GlyphClosed) {
+		t.Errorf("state cells should include glyphs, got %q", joined)
+	}
+
+	ui.statsData = ui.computeStats()
+	if chart := ui.renderMiniBarChart(); !strings.Contains(chart, theme.GlyphFiltered+" Filtered:") {
+		t.Errorf("bar chart labels should include glyphs, got %q", chart)
+	}
+}
+
+func TestBannerDetailLines(t *testing.T) {
+	ascii := bannerDetailLines("220 mail ready\r\nESMTP\x00\r\n", false)
+	if len(ascii) != 2 || ascii[0] != "220 mail ready" || ascii[1] != `ESMTP\x00` {
+		t.Errorf("ascii lines = %q", ascii)
+	}
+
+	dump := bannerDetailLines("\x16\x03\x01", true)
+	if len(dump) != 1 || !strings.HasPrefix(dump[0], "00000000  16 03 01") {
+		t.Errorf("hex lines = %q", dump)
+	}
+}
+
+func TestDetailsModalHexToggle(t *testing.T) {
+	ui := NewScanUI(&config.Config{}, 1, make(chan core.Event), false)
+	ui.recordResults([]core.ResultEvent{{Host: "10.0.0.1", Port: 443, State: core.StateOpen, Banner: "\x16\x03\x01"}})
+	ui.modalState.IsActive = true
+	ui.modalState.Type = ModalDetails
+
+	if view := ui.renderDetailsModal(); !strings.Contains(view, `\x16\x03\x01`) || !strings.Contains(view, "(ASCII)") {
+		t.Errorf("ascii view should escape binary bytes:\n%s", view)
+	}
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if !ui.bannerHex {
+		t.Fatal("x should toggle the hex banner view")
+	}
+	if view := ui.renderDetailsModal(); !strings.Contains(view, "16 03 01") || !strings.Contains(view, "(hex)") {
+		t.Errorf("hex view should show a hex dump:\n%s", view)
+	}
+}
+
+func TestDetailsModalRawTab(t *testing.T) {
+	copied := captureClipboard(t)
+	ui := NewScanUI(&config.Config{}, 1, make(chan core.Event), false)
+	ui.recordResults([]core.ResultEvent{{
+		Host: "10.0.0.1", Port: 53, Protocol: "udp", State: core.StateOpen, Banner: "DNS",
+		Transcript: []core.ProbeExchange{{Sent: true, Data: []byte("\x00\x01")}, {Data: []byte("\x00\x81")}},
+	}})
+	ui.openModal(ModalDetails)
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyTab})
+	if !ui.detailsRaw {
+		t.Fatal("Tab should switch to the JSON/Raw tab")
+	}
+	if view := ui.renderDetailsModal(); !strings.Contains(view, `"host": "10.0.0.1"`) {
+		t.Errorf("raw tab should show the JSON record:\n%s", view)
+	}
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if len(*copied) != 1 {
+		t.Fatalf("y copied %d times", len(*copied))
+	}
+	text := (*copied)[0]
+	for _, want := range []string{`"port": 53`, "→ Sent 2 bytes", `\x00\x01`, "← Received 2 bytes", `\x00\x81`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("copied text lacks %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestDetailsRawCopyLabelsIPv6Host(t *testing.T) {
+	captureClipboard(t)
+	ui := NewScanUI(&config.Config{}, 1, make(chan core.Event), false)
+	ui.recordResults([]core.ResultEvent{{Host: "2001:db8::1", Port: 53, State: core.StateOpen}})
+	ui.openModal(ModalDetails)
+	ui.toggleDetailsTab()
+
+	ui.copyDetailsRaw()
+	if !strings.Contains(ui.toast, "[2001:db8::1]:53") {
+		t.Errorf("toast should name the bracketed host:port, got %q", ui.toast)
+	}
+}
+
+func TestTranscriptLinesForTCPBanner(t *testing.T) {
+	lines := transcriptLines(core.ResultEvent{Protocol: "tcp", Banner: "SSH-2.0-OpenSSH\r\n"}, false)
+	if len(lines) != 2 || lines[0] != "← Received 17 bytes" || lines[1] != "  SSH-2.0-OpenSSH" {
+		t.Errorf("lines = %q", lines)
+	}
+	if lines := transcriptLines(core.ResultEvent{Protocol: "udp"}, false); len(lines) != 1 || strings.HasPrefix(lines[0], "←") {
+		t.Errorf("a result without a transcript = %q", lines)
+	}
+}
+
+func TestTLSDetailLines(t *testing.T) {
+	lines := tlsDetailLines(&core.TLSInfo{
+		Version: "TLS 1.2", Cipher: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", StartTLS: "imap",
+		Subject: "mail.example.com", Issuer: "R11",
+		NotAfter: time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC),
+		JA3S:     "f4febc55ea12b31ae17cfb7e614afda8",
+	})
+	want := []string{
+		"Version: TLS 1.2 (STARTTLS over IMAP)",
+		"Cipher: TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+		"Subject: mail.example.com",
+		"Issuer: R11",
+		"Expires: 2027-03-01",
+		"JA3S: f4febc55ea12b31ae17cfb7e614afda8",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("tlsDetailLines() = %q, want %q", lines, want)
+	}
+}
+
+func TestHTTPAuditDetailLines(t *testing.T) {
+	lines := httpAuditDetailLines(&core.HTTPAudit{
+		Grade: "C", Status: 200,
+		Present:   []string{"Strict-Transport-Security", "X-Frame-Options", "Referrer-Policy"},
+		Missing:   []string{"Content-Security-Policy", "X-Content-Type-Options"},
+		Redirects: []string{"https://example.com/"},
+	})
+	want := []string{
+		"Grade: C (HTTP 200)",
+		"Present: Strict-Transport-Security, X-Frame-Options, Referrer-Policy",
+		"Missing: Content-Security-Policy, X-Content-Type-Options",
+		"Redirect: https://example.com/",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("httpAuditDetailLines() = %q, want %q", lines, want)
+	}
+}
+
+func TestScanUI_ASCIIModeDrawsOnlyASCII(t *testing.T) {
+	i18n.SetTransform(theme.ASCIIGlyphs.Text)
+	defer i18n.SetTransform(nil)
+
+	cfg := &config.Config{UI: config.UIConfig{ASCII: true, Accessible: true}}
+	ui := NewScanUI(cfg, 100, make(chan core.Event), false)
+	ui.handleWindowSize(tea.WindowSizeMsg{Width: 160, Height: 40})
+	ui.recordResults([]core.ResultEvent{
+		{Host: "10.0.0.1", Port: 22, State: core.StateOpen, Banner: "SSH-2.0-OpenSSH_9.6"},
+		{Host: "10.0.0.1", Port: 23, State: core.StateClosed},
+		{Host: "10.0.0.2", Port: 80, State: core.StateFiltered},
+	})
+
+	views := map[string]func(){
+		"main":      func() {},
+		"dashboard": func() { ui.showDashboard = true },
+		"help":      func() { ui.showDashboard, ui.showHelp = false, true },
+		"sort":      func() { ui.showHelp = false; ui.openModal(ModalSort) },
+		"details":   func() { ui.openModal(ModalDetails) },
+	}
+	for _, name := range []string{"main", "dashboard", "help", "sort", "details"} {
+		views[name]()
+		view := ui.View()
+		if i := strings.IndexFunc(view, func(r rune) bool { return r >= utf8.RuneSelf }); i >= 0 {
+			line := view[strings.LastIndex(view[:i], "\n")+1:]
+			line, _, _ = strings.Cut(line, "\n")
+			t.Errorf("%s view draws a non-ASCII glyph: %q", name, line)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/lucchesi-sec/portscan/pkg/theme"
+)
+
+// SearchCharLimit caps the length of the '/' search query.
+const SearchCharLimit = 128
+
+// newSearchInput creates the footer text input used by incremental search.
+func newSearchInput(t theme.Theme) textinput.Model {
+	input := textinput.New()
+	input.Prompt = "/"
+	input.Placeholder = i18n.T("ui.search.placeholder")
+	input.CharLimit = SearchCharLimit
+	input.PromptStyle = lipgloss.NewStyle().Foreground(t.Primary).Bold(true)
+	return input
+}
+
+// startSearch focuses the search input, seeded with the current query.
+func (m *ScanUI) startSearch() tea.Cmd {
+	m.searchActive = true
+	m.searchInput.SetValue(m.filterState.SearchQuery)
+	m.searchInput.CursorEnd()
+	return m.searchInput.Focus()
+}
+
+// stopSearch leaves search input mode. When clear is true the query is
+// dropped and the table returns to its unfiltered view.
+func (m *ScanUI) stopSearch(clear bool) {
+	m.searchActive = false
+	m.searchInput.Blur()
+	if clear {
+		m.searchInput.SetValue("")
+		m.filterState.SetSearchQuery("")
+		m.updateTable()
+	}
+}
+
+// handleSearchKey routes key presses to the search input while it is focused.
+// The result set is re-filtered live as the query changes.
+func (m *ScanUI) handleSearchKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.stopSearch(false)
+		return true, true, nil
+	case tea.KeyEsc:
+		m.stopSearch(true)
+		return true, true, nil
+	case tea.KeyCtrlC:
+		return true, true, tea.Quit
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+
+	if query := m.searchInput.Value(); query != m.filterState.SearchQuery {
+		m.filterState.SetSearchQuery(query)
+		m.updateTable()
+		m.table.GotoTop()
+	}
+
+	return true, true, cmd
+}
+
+// jumpToMatch moves the table cursor to the next (or previous) row matching
+// the active search query, wrapping around at either end.
+func (m *ScanUI) jumpToMatch(forward bool) {
+	query := m.filterState.SearchQuery
+	count := m.rowCount()
+	if query == "" || count == 0 {
+		return
+	}
+
+	cursor := m.table.Cursor()
+	for step := 1; step <= count; step++ {
+		idx := cursor - step
+		if forward {
+			idx = cursor + step
+		}
+		idx = ((idx % count) + count) % count
+
+		if r, ok := m.resultAt(idx); ok && matchesSearch(r, query) {
+			m.table.SetCursor(idx)
+			return
+		}
+	}
+}
+
+// renderSearchBar renders the footer input while search mode is active.
+func (m *ScanUI) renderSearchBar() string {
+	hint := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Render(m.symbols().Text("  Enter: apply • Esc: clear"))
+	return m.searchInput.View() + hint
+}
+
+// renderCell truncates content to the column width and renders it with the
+// row style, highlighting any occurrences of the active search query.
+func (m *ScanUI) renderCell(content string, width int, base lipgloss.Style) string {
+	content = truncateToWidth(content, width)
+	query := m.filterState.SearchQuery
+	if query == "" {
+		return base.Render(content)
+	}
+	return highlightMatches(content, query, base, m.theme.SearchMatchStyle())
+}
+
+// highlightMatches renders every case-insensitive occurrence of query within
+// text using the highlight style and the remainder using the base style.
+func highlightMatches(text, query string, base, highlight lipgloss.Style) string {
+	lowerText := strings.ToLower(text)
+	lowerQuery := strings.ToLower(query)
+
+	// Byte offsets are only safe to reuse when lowercasing preserved length.
+	if query == "" || len(lowerText) != len(text) || !strings.Contains(lowerText, lowerQuery) {
+		return base.Render(text)
+	}
+
+	var b strings.Builder
+	pos := 0
+	for {
+		idx := strings.Index(lowerText[pos:], lowerQuery)
+		if idx < 0 {
+			break
+		}
+		start := pos + idx
+		end := start + len(lowerQuery)
+		if start > pos {
+			b.WriteString(base.Render(text[pos:start]))
+		}
+		b.WriteString(highlight.Render(text[start:end]))
+		pos = end
+	}
+	if pos < len(text) {
+		b.WriteString(base.Render(text[pos:]))
+	}
+
+	return b.String()
+}
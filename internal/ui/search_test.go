@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+func newSearchTestUI(t *testing.T) *ScanUI {
+	t.Helper()
+
+	results := make(chan core.Event)
+	close(results)
+
+	ui := NewScanUI(&config.Config{}, 10, results, false)
+	ui.width = 160
+	ui.height = 40
+
+	for _, r := range []core.ResultEvent{
+		{Host: "10.0.0.1", Port: 22, State: core.StateOpen, Banner: "SSH-2.0-OpenSSH_8.9"},
+		{Host: "10.0.0.2", Port: 80, State: core.StateOpen, Banner: "nginx"},
+		{Host: "web.example.com", Port: 443, State: core.StateOpen},
+		{Host: "10.0.0.3", Port: 3306, State: core.StateClosed},
+	} {
+		ui.results.Append(r)
+		ui.stats.Add(r)
+	}
+	ui.updateTable()
+	return ui
+}
+
+func typeRunes(ui *ScanUI, text string) {
+	for _, r := range text {
+		ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestMatchesSearch(t *testing.T) {
+	result := core.ResultEvent{Host: "db.internal", Port: 22, Banner: "SSH-2.0-OpenSSH"}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"db.int", true},    // host
+		{"ssh", true},       // service and banner
+		{"OPENSSH", true},   // case-insensitive banner
+		{"postgres", false}, // no match
+		{"10.0.0.1", false}, // different host
+	}
+
+	for _, tt := range tests {
+		if got := matchesSearch(result, tt.query); got != tt.want {
+			t.Errorf("matchesSearch(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFilterState_SearchQuery(t *testing.T) {
+	state := NewFilterState()
+	state.SetSearchQuery("nginx")
+
+	results := []core.ResultEvent{
+		{Host: "a", Port: 80, State: core.StateOpen, Banner: "nginx/1.25"},
+		{Host: "b", Port: 8080, State: core.StateOpen, Banner: "Apache"},
+	}
+
+	filtered := state.ApplyFilters(results)
+	if len(filtered) != 1 || filtered[0].Host != "a" {
+		t.Fatalf("expected only host a to match, got %+v", filtered)
+	}
+
+	if desc := state.GetActiveFilterDescription(); !strings.Contains(desc, "Search: nginx") {
+		t.Errorf("expected description to mention search, got %q", desc)
+	}
+
+	state.Reset()
+	if state.SearchQuery != "" {
+		t.Error("reset should clear the search query")
+	}
+}
+
+func TestScanUI_SearchModeFiltersLive(t *testing.T) {
+	ui := newSearchTestUI(t)
+
+	handled, skip, _ := ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	if !handled || !skip {
+		t.Fatal("'/' should be handled and skip table update")
+	}
+	if !ui.searchActive {
+		t.Fatal("'/' should activate search mode")
+	}
+
+	typeRunes(ui, "10.0.0")
+	if len(ui.displayResults) != 3 {
+		t.Fatalf("expected 3 results matching host prefix, got %d", len(ui.displayResults))
+	}
+
+	// Keys such as 'q' must be captured by the input instead of quitting.
+	_, _, cmd := ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if cmd != nil {
+		if _, isQuit := cmd().(tea.QuitMsg); isQuit {
+			t.Fatal("typing 'q' in search mode must not quit")
+		}
+	}
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyBackspace})
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if ui.searchActive {
+		t.Error("enter should leave search input mode")
+	}
+	if ui.filterState.SearchQuery != "10.0.0" {
+		t.Errorf("enter should keep the query, got %q", ui.filterState.SearchQuery)
+	}
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEsc})
+	if ui.filterState.SearchQuery != "" {
+		t.Errorf("esc should clear the query, got %q", ui.filterState.SearchQuery)
+	}
+	if len(ui.displayResults) != 4 {
+		t.Errorf("clearing the search should restore all rows, got %d", len(ui.displayResults))
+	}
+}
+
+func TestScanUI_JumpToMatchWraps(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.filterState.SetSearchQuery("10.0.0")
+	ui.updateTable()
+	ui.table.SetCursor(0)
+
+	count := len(ui.displayResults)
+	for i := 1; i <= count; i++ {
+		ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+		if want := i % count; ui.table.Cursor() != want {
+			t.Fatalf("after %d 'n' presses expected cursor %d, got %d", i, want, ui.table.Cursor())
+		}
+	}
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'N'}})
+	if ui.table.Cursor() != count-1 {
+		t.Errorf("'N' from the first row should wrap to the last, got %d", ui.table.Cursor())
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	base := lipgloss.NewStyle()
+	highlight := lipgloss.NewStyle().Bold(true)
+
+	out := highlightMatches("OpenSSH openssh", "openssh", base, highlight)
+	if got := lipgloss.Width(out); got != len("OpenSSH openssh") {
+		t.Errorf("highlighting must not change visible width, got %d", got)
+	}
+
+	if plain := highlightMatches("nginx", "apache", base, highlight); plain != base.Render("nginx") {
+		t.Errorf("non-matching text should render with base style only, got %q", plain)
+	}
+}
+
+func TestScanUI_RenderFooterShowsSearchInput(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.startSearch()
+	typeRunes(ui, "ssh")
+
+	footer := ui.renderFooter()
+	if !strings.Contains(footer, "ssh") {
+		t.Errorf("footer should show the search query while typing, got %q", footer)
+	}
+}
@@ -0,0 +1,428 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/lucchesi-sec/portscan/pkg/theme"
+)
+
+// SelectionMarker prefixes the host cell of marked rows.
+const SelectionMarker = "● "
+
+// TagCharLimit caps the length of a bulk tag.
+const TagCharLimit = 64
+
+// BulkAction identifies an entry in the bulk actions modal.
+type BulkAction int
+
+const (
+	BulkExport BulkAction = iota
+	BulkCopy
+	BulkTag
+	BulkRescan
+	BulkClear
+)
+
+// bulkActionLabels are the catalog keys of the bulk actions, in order.
+var bulkActionLabels = []string{
+	"ui.bulk.export",
+	"ui.bulk.copy",
+	"ui.bulk.tag",
+	"ui.bulk.rescan",
+	"ui.bulk.clear",
+}
+
+// RescanFunc re-probes targets for one protocol with banner grabbing enabled
+// and returns the fresh results. A zero timeout keeps the configured timeout.
+// It is supplied by the command layer.
+type RescanFunc func(ctx context.Context, protocol string, targets []core.ScanTarget, timeout time.Duration) ([]core.ResultEvent, error)
+
+// rescanFinishedMsg carries the results of a bulk re-scan.
+type rescanFinishedMsg struct {
+	results []core.ResultEvent
+	err     error
+}
+
+// selectionKey identifies a result by host, protocol, and port so marks and
+// tags survive re-sorting, filtering, and re-scans.
+func selectionKey(r core.ResultEvent) string {
+	protocol := r.Protocol
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	return fmt.Sprintf("%s/%s:%d", protocol, r.Host, r.Port)
+}
+
+// SelectionState tracks the rows marked for bulk actions.
+type SelectionState struct {
+	marked map[string]struct{}
+	anchor int // display index of the last toggled row, for range marking
+}
+
+// NewSelectionState creates an empty selection.
+func NewSelectionState() *SelectionState {
+	return &SelectionState{marked: make(map[string]struct{}), anchor: -1}
+}
+
+// Toggle flips the mark on a result and reports whether it is now marked.
+func (s *SelectionState) Toggle(r core.ResultEvent) bool {
+	key := selectionKey(r)
+	if _, ok := s.marked[key]; ok {
+		delete(s.marked, key)
+		return false
+	}
+	s.marked[key] = struct{}{}
+	return true
+}
+
+// Mark adds a result to the selection.
+func (s *SelectionState) Mark(r core.ResultEvent) {
+	s.marked[selectionKey(r)] = struct{}{}
+}
+
+// IsMarked reports whether a result is selected.
+func (s *SelectionState) IsMarked(r core.ResultEvent) bool {
+	_, ok := s.marked[selectionKey(r)]
+	return ok
+}
+
+// Count returns the number of marked results.
+func (s *SelectionState) Count() int {
+	return len(s.marked)
+}
+
+// Clear removes all marks.
+func (s *SelectionState) Clear() {
+	s.marked = make(map[string]struct{})
+	s.anchor = -1
+}
+
+// Selected returns the marked results in the order they appear in results.
+func (s *SelectionState) Selected(results []core.ResultEvent) []core.ResultEvent {
+	if len(s.marked) == 0 {
+		return nil
+	}
+	selected := make([]core.ResultEvent, 0, len(s.marked))
+	for _, r := range results {
+		if s.IsMarked(r) {
+			selected = append(selected, r)
+		}
+	}
+	return selected
+}
+
+// newTagInput creates the text input used to tag selected results.
+func newTagInput(t theme.Theme) textinput.Model {
+	input := textinput.New()
+	input.Prompt = i18n.T("ui.tag.prompt")
+	input.Placeholder = i18n.T("ui.tag.placeholder")
+	input.CharLimit = TagCharLimit
+	input.PromptStyle = lipgloss.NewStyle().Foreground(t.Secondary).Bold(true)
+	return input
+}
+
+// SetRescanFunc enables the "re-scan selected" bulk action.
+func (m *ScanUI) SetRescanFunc(fn RescanFunc) {
+	m.rescan = fn
+}
+
+// selectedResults returns the marked results across the whole buffer, not
+// just the rows visible under the current filter.
+func (m *ScanUI) selectedResults() []core.ResultEvent {
+	return m.selection.Selected(m.results.Items())
+}
+
+// toggleMark marks or unmarks the row under the cursor and advances to the
+// next row so consecutive presses mark a run of rows.
+func (m *ScanUI) toggleMark() {
+	result, ok := m.selectedResult()
+	if !ok {
+		return
+	}
+	m.selection.Toggle(result)
+	m.selection.anchor = m.table.Cursor()
+	m.updateTable()
+	m.table.MoveDown(1)
+}
+
+// markRange marks every row between the last toggled row and the cursor.
+func (m *ScanUI) markRange() {
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= m.rowCount() {
+		return
+	}
+
+	anchor := m.selection.anchor
+	if anchor < 0 || anchor >= m.rowCount() {
+		anchor = cursor
+	}
+	start, end := min(anchor, cursor), max(anchor, cursor)
+	for i := start; i <= end; i++ {
+		if r, ok := m.resultAt(i); ok {
+			m.selection.Mark(r)
+		}
+	}
+	m.selection.anchor = cursor
+	m.updateTable()
+}
+
+// openBulkModal opens the bulk actions menu when rows are marked.
+func (m *ScanUI) openBulkModal() tea.Cmd {
+	if m.selection.Count() == 0 {
+		return m.showToast(i18n.T("ui.bulk.none_marked"), true)
+	}
+	m.openModal(ModalBulk)
+	return nil
+}
+
+// handleBulkModalKey navigates and runs bulk actions.
+func (m *ScanUI) handleBulkModalKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		m.modalState.Cursor = max(0, m.modalState.Cursor-1)
+		return true, true, nil
+	case "down", "j":
+		m.modalState.Cursor = min(len(bulkActionLabels)-1, m.modalState.Cursor+1)
+		return true, true, nil
+	case "enter":
+		return true, true, m.runBulkAction(BulkAction(m.modalState.Cursor))
+	default:
+		return true, true, nil
+	}
+}
+
+// runBulkAction executes a bulk action against the marked results.
+func (m *ScanUI) runBulkAction(action BulkAction) tea.Cmd {
+	selected := m.selectedResults()
+	m.modalState.IsActive = false
+	m.modalState.Cursor = 0
+
+	switch action {
+	case BulkExport:
+		cmd := m.openExportModal()
+		m.exportState.SelectedOnly = true
+		return cmd
+	case BulkCopy:
+		lines := make([]string, len(selected))
+		for i, r := range selected {
+			lines[i] = net.JoinHostPort(r.Host, strconv.Itoa(int(r.Port)))
+		}
+		if err := clipboardWriter(strings.Join(lines, "\n")); err != nil {
+			return m.showToast(i18n.T("ui.copy.failed", err), true)
+		}
+		return m.showToast(i18n.T("ui.bulk.copied", len(selected)), false)
+	case BulkTag:
+		m.openModal(ModalTag)
+		m.tagInput.SetValue("")
+		return m.tagInput.Focus()
+	case BulkRescan:
+		return m.startRescan(selected, 0)
+	case BulkClear:
+		m.selection.Clear()
+		m.updateTable()
+		return nil
+	default:
+		return nil
+	}
+}
+
+// handleTagModalKey edits the tag and applies it on Enter.
+func (m *ScanUI) handleTagModalKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return true, true, tea.Quit
+	case tea.KeyEnter:
+		tag := strings.TrimSpace(m.tagInput.Value())
+		m.modalState.IsActive = false
+		m.tagInput.Blur()
+		if tag == "" {
+			return true, true, nil
+		}
+		count := m.tagSelected(tag)
+		return true, true, m.showToast(i18n.T("ui.tag.tagged", count, tag), false)
+	}
+
+	var cmd tea.Cmd
+	m.tagInput, cmd = m.tagInput.Update(msg)
+	return true, true, cmd
+}
+
+// tagSelected attaches tag to every marked result and returns how many were tagged.
+func (m *ScanUI) tagSelected(tag string) int {
+	selected := m.selectedResults()
+	for _, r := range selected {
+		key := selectionKey(r)
+		if !containsString(m.tags[key], tag) {
+			m.tags[key] = append(m.tags[key], tag)
+		}
+	}
+	return len(selected)
+}
+
+// tagsFor returns the tags attached to a result, those set for the scan
+// first.
+func (m *ScanUI) tagsFor(r core.ResultEvent) []string {
+	return mergeTags(r.Tags, m.tags[selectionKey(r)])
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+// startRescan re-probes results in the background, grouped by protocol.
+func (m *ScanUI) startRescan(selected []core.ResultEvent, timeout time.Duration) tea.Cmd {
+	if m.rescan == nil {
+		return m.showToast(i18n.T("ui.rescan.unavailable"), true)
+	}
+
+	byProtocol := groupScanTargets(selected)
+	rescan := m.rescan
+	toast := m.showToast(i18n.T("ui.rescan.started", len(selected)), false)
+	return tea.Batch(
+		func() tea.Msg {
+			var results []core.ResultEvent
+			for _, protocol := range []string{"tcp", "udp"} {
+				targets := byProtocol[protocol]
+				if len(targets) == 0 {
+					continue
+				}
+				found, err := rescan(context.Background(), protocol, targets, timeout)
+				results = append(results, found...)
+				if err != nil {
+					return rescanFinishedMsg{results: results, err: err}
+				}
+			}
+			return rescanFinishedMsg{results: results}
+		},
+		toast,
+	)
+}
+
+// singleRescanTimeout returns the extended timeout used when re-probing one
+// port from the details modal.
+func (m *ScanUI) singleRescanTimeout() time.Duration {
+	timeout := SingleRescanMinTimeout
+	if m.config != nil {
+		if scaled := m.config.GetTimeout() * SingleRescanTimeoutMultiplier; scaled > timeout {
+			timeout = scaled
+		}
+	}
+	return timeout
+}
+
+// rescanSelectedRow re-probes the result shown in the details modal with a
+// longer timeout, updating it in place when the scan finishes.
+func (m *ScanUI) rescanSelectedRow() tea.Cmd {
+	result, ok := m.selectedResult()
+	if !ok {
+		return nil
+	}
+	return m.startRescan([]core.ResultEvent{result}, m.singleRescanTimeout())
+}
+
+// groupScanTargets builds per-protocol scan targets, one per host, keeping
+// the order in which hosts and ports were selected.
+func groupScanTargets(results []core.ResultEvent) map[string][]core.ScanTarget {
+	grouped := make(map[string][]core.ScanTarget)
+	index := make(map[string]int)
+	for _, r := range results {
+		protocol := r.Protocol
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		key := protocol + "/" + r.Host
+		i, ok := index[key]
+		if !ok {
+			i = len(grouped[protocol])
+			index[key] = i
+			grouped[protocol] = append(grouped[protocol], core.ScanTarget{Host: r.Host})
+		}
+		grouped[protocol][i].Ports = append(grouped[protocol][i].Ports, r.Port)
+	}
+	return grouped
+}
+
+// handleRescanFinished merges re-scanned results into the buffer in place.
+func (m *ScanUI) handleRescanFinished(msg rescanFinishedMsg) tea.Cmd {
+	for _, r := range msg.results {
+		m.recordInventory(r)
+		if previous, ok := m.results.Replace(r); ok {
+			m.stats.Replace(previous, r)
+		} else {
+			m.appendResult(r)
+			m.stats.Add(r)
+		}
+	}
+	m.updateTable()
+
+	if msg.err != nil {
+		return m.showToast(i18n.T("ui.rescan.failed", msg.err), true)
+	}
+	return m.showToast(i18n.T("ui.rescan.done", len(msg.results)), false)
+}
+
+// renderBulkModal renders the bulk actions menu.
+func (m *ScanUI) renderBulkModal() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Primary).
+		Width(30).
+		Render(i18n.T("ui.bulk.title", m.selection.Count()))
+	b.WriteString(title + "\n\n")
+
+	m.modalState.OptionsTop = strings.Count(b.String(), "\n")
+	for i, label := range bulkActionLabels {
+		style := lipgloss.NewStyle()
+		if BulkAction(i) == BulkRescan && m.rescan == nil {
+			style = style.Foreground(m.theme.Muted)
+		}
+		if i == m.modalState.Cursor {
+			style = style.Background(m.theme.Primary).Foreground(m.theme.Background)
+		}
+		b.WriteString(style.Render(fmt.Sprintf("%d. %s", i+1, i18n.T(label))) + "\n")
+	}
+
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Render(i18n.T("ui.bulk.keys"))
+	b.WriteString("\n" + instructions)
+
+	return b.String()
+}
+
+// renderTagModal renders the tag prompt for the marked results.
+func (m *ScanUI) renderTagModal() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Primary).
+		Width(30).
+		Render(i18n.T("ui.tag.title", m.selection.Count()))
+	b.WriteString(title + "\n\n")
+	b.WriteString(m.tagInput.View() + "\n")
+
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Render(i18n.T("ui.tag.keys"))
+	b.WriteString("\n" + instructions)
+
+	return b.String()
+}
@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+var (
+	markKey  = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}}
+	rangeKey = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'V'}}
+	bulkKey  = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}}
+)
+
+func TestSelectionState_ToggleAndSelected(t *testing.T) {
+	s := NewSelectionState()
+	a := core.ResultEvent{Host: "a", Port: 22}
+	b := core.ResultEvent{Host: "b", Port: 22}
+	udp := core.ResultEvent{Host: "a", Port: 22, Protocol: "udp"}
+
+	if !s.Toggle(a) || !s.Toggle(b) {
+		t.Fatal("first toggle should mark")
+	}
+	if s.IsMarked(udp) {
+		t.Error("marks must be protocol specific")
+	}
+	if s.Toggle(a) {
+		t.Error("second toggle should unmark")
+	}
+
+	selected := s.Selected([]core.ResultEvent{a, b, udp})
+	if len(selected) != 1 || selected[0].Host != "b" {
+		t.Errorf("expected only b selected, got %+v", selected)
+	}
+
+	s.Clear()
+	if s.Count() != 0 {
+		t.Error("clear should remove all marks")
+	}
+}
+
+func TestScanUI_MarkKeyMarksAndAdvances(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.table.SetCursor(0)
+
+	_, _, cmd := ui.handleKeyMsg(markKey)
+	if cmd != nil {
+		t.Error("m must mark rows, not pause the scan")
+	}
+	if ui.isPaused {
+		t.Error("m must not pause the scan")
+	}
+	if !ui.selection.IsMarked(ui.displayResults[0]) {
+		t.Error("m should mark the row under the cursor")
+	}
+	if ui.table.Cursor() != 1 {
+		t.Errorf("cursor should advance after marking, got %d", ui.table.Cursor())
+	}
+	if !strings.Contains(ui.table.Rows()[0][0], SelectionMarker) {
+		t.Error("marked rows should show the selection marker")
+	}
+}
+
+func TestScanUI_MarkRange(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.table.SetCursor(0)
+	ui.handleKeyMsg(markKey) // marks row 0, cursor on row 1
+
+	ui.table.SetCursor(3)
+	ui.handleKeyMsg(rangeKey)
+
+	if got := ui.selection.Count(); got != 4 {
+		t.Errorf("expected rows 0 through 3 marked, got %d", got)
+	}
+}
+
+func TestScanUI_BulkRequiresSelection(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.handleKeyMsg(bulkKey)
+	if ui.modalState.IsActive {
+		t.Error("bulk modal should not open without marked rows")
+	}
+	if !ui.toastIsError {
+		t.Error("expected an error toast explaining how to mark rows")
+	}
+}
+
+func TestScanUI_BulkCopyAndTag(t *testing.T) {
+	copied := captureClipboard(t)
+	ui := newSearchTestUI(t)
+	ui.table.SetCursor(0)
+	ui.handleKeyMsg(markKey)
+	ui.handleKeyMsg(markKey)
+
+	ui.handleKeyMsg(bulkKey)
+	if !ui.modalState.IsActive || ui.modalState.Type != ModalBulk {
+		t.Fatal("'b' should open the bulk actions modal")
+	}
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyDown})
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if len(*copied) != 1 || (*copied)[0] != "10.0.0.1:22\n10.0.0.2:80" {
+		t.Fatalf("unexpected bulk copy %q", *copied)
+	}
+
+	ui.runBulkAction(BulkTag)
+	if ui.modalState.Type != ModalTag {
+		t.Fatal("tag action should open the tag prompt")
+	}
+	typeRunes(ui, "triage?")
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if tags := ui.tagsFor(ui.displayResults[1]); len(tags) != 1 || tags[0] != "triage?" {
+		t.Errorf("expected tag on marked row, got %v", tags)
+	}
+	if tags := ui.tagsFor(ui.displayResults[2]); len(tags) != 0 {
+		t.Errorf("unmarked rows should not be tagged, got %v", tags)
+	}
+}
+
+func TestScanUI_BulkCopyIPv6(t *testing.T) {
+	copied := captureClipboard(t)
+	ui := NewScanUI(&config.Config{}, 2, make(chan core.Event), false)
+	ui.recordResults([]core.ResultEvent{
+		{Host: "fe80::1", Port: 22, State: core.StateOpen},
+		{Host: "10.0.0.1", Port: 80, State: core.StateOpen},
+	})
+	for _, r := range ui.displayResults {
+		ui.selection.Mark(r)
+	}
+
+	ui.runBulkAction(BulkCopy)
+	if len(*copied) != 1 || (*copied)[0] != "[fe80::1]:22\n10.0.0.1:80" {
+		t.Fatalf("unexpected bulk copy %q", *copied)
+	}
+}
+
+func TestScanUI_BulkExportUsesSelection(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.selection.Mark(ui.displayResults[3])
+	ui.runBulkAction(BulkExport)
+
+	if ui.modalState.Type != ModalExport || !ui.exportState.SelectedOnly {
+		t.Fatal("bulk export should open the export modal for the selection")
+	}
+	if got := ui.exportSource(); len(got) != 1 || got[0].Port != 3306 {
+		t.Errorf("expected only the marked row, got %+v", got)
+	}
+}
+
+func TestScanUI_BulkRescanMergesResults(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.selection.Mark(ui.displayResults[3]) // 10.0.0.3:3306 closed
+
+	var gotTargets []core.ScanTarget
+	ui.SetRescanFunc(func(_ context.Context, protocol string, targets []core.ScanTarget, _ time.Duration) ([]core.ResultEvent, error) {
+		gotTargets = targets
+		return []core.ResultEvent{{Host: "10.0.0.3", Port: 3306, State: core.StateOpen, Banner: "mysql", Protocol: protocol}}, nil
+	})
+
+	cmd := ui.startRescan(ui.selectedResults(), 0)
+	var finished rescanFinishedMsg
+	for _, msg := range cmd().(tea.BatchMsg) {
+		if done, ok := msg().(rescanFinishedMsg); ok {
+			finished = done
+			break
+		}
+	}
+	ui.handleRescanFinished(finished)
+
+	if len(gotTargets) != 1 || gotTargets[0].Host != "10.0.0.3" || gotTargets[0].Ports[0] != 3306 {
+		t.Errorf("unexpected rescan targets %+v", gotTargets)
+	}
+	if ui.results.Len() != 4 {
+		t.Errorf("rescan should update in place, buffer has %d results", ui.results.Len())
+	}
+	total, open, closed, _ := ui.stats.Totals()
+	if total != 4 || open != 4 || closed != 0 {
+		t.Errorf("stats should move the port to open, got total=%d open=%d closed=%d", total, open, closed)
+	}
+}
+
+func TestScanUI_RescanUnavailable(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.startRescan(nil, 0)
+	if !ui.toastIsError {
+		t.Error("rescan without a hook should report an error")
+	}
+
+	ui.SetRescanFunc(func(context.Context, string, []core.ScanTarget, time.Duration) ([]core.ResultEvent, error) {
+		return nil, errors.New("boom")
+	})
+	ui.handleRescanFinished(rescanFinishedMsg{err: errors.New("boom")})
+	if !strings.Contains(ui.toast, "boom") {
+		t.Errorf("expected failure toast, got %q", ui.toast)
+	}
+}
+
+func TestGroupScanTargets(t *testing.T) {
+	grouped := groupScanTargets([]core.ResultEvent{
+		{Host: "a", Port: 22},
+		{Host: "b", Port: 80, Protocol: "tcp"},
+		{Host: "a", Port: 443},
+		{Host: "a", Port: 53, Protocol: "udp"},
+	})
+
+	if tcp := grouped["tcp"]; len(tcp) != 2 || len(tcp[0].Ports) != 2 || tcp[0].Ports[1] != 443 {
+		t.Errorf("unexpected tcp grouping %+v", tcp)
+	}
+	if udp := grouped["udp"]; len(udp) != 1 || udp[0].Ports[0] != 53 {
+		t.Errorf("unexpected udp grouping %+v", udp)
+	}
+}
+
+func TestScanUI_DetailsRescanUsesLongerTimeout(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.config.TimeoutMs = 1000
+	ui.table.SetCursor(1)
+	ui.openModal(ModalDetails)
+
+	var gotTimeout time.Duration
+	var gotTargets []core.ScanTarget
+	ui.SetRescanFunc(func(_ context.Context, _ string, targets []core.ScanTarget, timeout time.Duration) ([]core.ResultEvent, error) {
+		gotTargets, gotTimeout = targets, timeout
+		return []core.ResultEvent{{Host: "10.0.0.2", Port: 80, State: core.StateOpen, Banner: "nginx/1.25"}}, nil
+	})
+
+	_, _, cmd := ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	if cmd == nil {
+		t.Fatal("'r' in the details modal should start a re-scan")
+	}
+	msg := cmd().(tea.BatchMsg)[0]().(rescanFinishedMsg)
+	ui.Update(msg)
+
+	if gotTimeout != 5*time.Second {
+		t.Errorf("expected 5x configured timeout, got %v", gotTimeout)
+	}
+	if len(gotTargets) != 1 || len(gotTargets[0].Ports) != 1 || gotTargets[0].Ports[0] != 80 {
+		t.Errorf("expected only the selected port, got %+v", gotTargets)
+	}
+	if got := ui.results.Items()[1].Banner; got != "nginx/1.25" {
+		t.Errorf("result should be updated in place, banner = %q", got)
+	}
+	if !ui.modalState.IsActive {
+		t.Error("details modal should stay open during re-scan")
+	}
+}
+
+func TestScanUI_SingleRescanTimeoutFloor(t *testing.T) {
+	ui := newSearchTestUI(t)
+	ui.config.TimeoutMs = 100
+	if got := ui.singleRescanTimeout(); got != SingleRescanMinTimeout {
+		t.Errorf("expected minimum timeout, got %v", got)
+	}
+}
@@ -1,28 +1,118 @@
 package ui
 
-var serviceNames = map[uint16]string{
-	21:    "FTP",
-	22:    "SSH",
-	23:    "Telnet",
-	25:    "SMTP",
-	53:    "DNS",
-	80:    "HTTP",
-	110:   "POP3",
-	143:   "IMAP",
-	443:   "HTTPS",
-	445:   "SMB",
-	3306:  "MySQL",
-	3389:  "RDP",
-	5432:  "PostgreSQL",
-	6379:  "Redis",
-	8080:  "HTTP-Alt",
-	8443:  "HTTPS-Alt",
-	27017: "MongoDB",
-}
-
-func getServiceName(port uint16) string {
-	if service, ok := serviceNames[port]; ok {
-		return service
-	}
-	return "Unknown"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/services"
+)
+
+// isUDP reports whether a result came from the UDP scanner; results
+// without a protocol are TCP.
+func isUDP(r core.ResultEvent) bool {
+	return strings.EqualFold(r.Protocol, "udp")
+}
+
+// resultProtocol returns the result's protocol in lower case, "tcp" when
+// unset.
+func resultProtocol(r core.ResultEvent) string {
+	if isUDP(r) {
+		return "udp"
+	}
+	return "tcp"
+}
+
+// lookupService returns the service assigned to the result's port under
+// its own protocol, or "" if there is none.
+func lookupService(r core.ResultEvent) string {
+	if isUDP(r) {
+		return services.LookupUDP(r.Port)
+	}
+	return services.LookupTCP(r.Port)
+}
+
+// serviceName returns the service name for a result from the services
+// database, using the result's protocol; "unknown" when unassigned.
+func serviceName(r core.ResultEvent) string {
+	if name := lookupService(r); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+// expectedServiceAnalysis describes the service registered for the
+// result's port and protocol for the details modal. When nothing is
+// registered under the result's protocol but the other one has a name,
+// that is mentioned so a UDP row on a TCP service port is not mistaken
+// for the TCP service.
+func expectedServiceAnalysis(r core.ResultEvent) string {
+	proto := resultProtocol(r)
+	if name := lookupService(r); name != "" {
+		return fmt.Sprintf("  Expected Service: %s (registered for %d/%s)", name, r.Port, proto)
+	}
+	other, otherName := "udp", services.LookupUDP(r.Port)
+	if isUDP(r) {
+		other, otherName = "tcp", services.LookupTCP(r.Port)
+	}
+	if otherName != "" {
+		return fmt.Sprintf("  Expected Service: none for %d/%s (%s is registered for %s only)",
+			r.Port, proto, otherName, strings.ToUpper(other))
+	}
+	return fmt.Sprintf("  Expected Service: none registered for %d/%s", r.Port, proto)
+}
+
+// detectedServiceAnalysis names the service identified from a TCP banner,
+// or returns "" when no signature recognized it.
+func detectedServiceAnalysis(r core.ResultEvent) string {
+	if isUDP(r) {
+		return ""
+	}
+	if detected := services.Identify(r.Banner); detected != "" {
+		return fmt.Sprintf("  Detected Service: %s (from banner)", detected)
+	}
+	return ""
+}
+
+// stateAnalysis explains what the result's state means for its protocol.
+// A UDP port cannot refuse a connection, so closed means an ICMP port
+// unreachable came back and filtered means no reply at all.
+func stateAnalysis(r core.ResultEvent) string {
+	if isUDP(r) {
+		switch r.State {
+		case core.StateOpen:
+			return "  🔓 Port is Open (replied to the UDP probe)"
+		case core.StateClosed:
+			return "  🔒 Port is Closed (ICMP port unreachable)"
+		case core.StateFiltered:
+			return "  🚫 Port is Open|Filtered (no reply; blocked, or a service that ignored the probe)"
+		}
+		return ""
+	}
+	switch r.State {
+	case core.StateOpen:
+		return "  🔓 Port is Open (listening for connections)"
+	case core.StateClosed:
+		return "  🔒 Port is Closed (not accepting connections)"
+	case core.StateFiltered:
+		return "  🚫 Port is Filtered (blocked by firewall)"
+	}
+	return ""
+}
+
+// serviceMismatch reports the service identified from the result's banner
+// when it differs from the one registered for the port.
+func serviceMismatch(r core.ResultEvent) (detected, expected string, ok bool) {
+	return services.Mismatch(r.Port, r.Protocol, r.Banner)
+}
+
+// mismatchAnalysis describes a service mismatch for the details modal, or
+// returns "" when the banner agrees with the port or was not recognized.
+func mismatchAnalysis(r core.ResultEvent) string {
+	detected, expected, ok := serviceMismatch(r)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("  ⚠ Service mismatch: %s answered on %d/%s, where %s is registered",
+		detected, r.Port, resultProtocol(r), expected)
 }
@@ -1,61 +1,95 @@
 package ui
 
-import "testing"
+import (
+	"strings"
+	"testing"
 
-func TestGetServiceName(t *testing.T) {
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+func TestServiceName(t *testing.T) {
 	tests := []struct {
 		name     string
 		port     uint16
+		protocol string
 		expected string
 	}{
-		{"FTP", 21, "FTP"},
-		{"SSH", 22, "SSH"},
-		{"Telnet", 23, "Telnet"},
-		{"SMTP", 25, "SMTP"},
-		{"DNS", 53, "DNS"},
-		{"HTTP", 80, "HTTP"},
-		{"POP3", 110, "POP3"},
-		{"IMAP", 143, "IMAP"},
-		{"HTTPS", 443, "HTTPS"},
-		{"SMB", 445, "SMB"},
-		{"MySQL", 3306, "MySQL"},
-		{"RDP", 3389, "RDP"},
-		{"PostgreSQL", 5432, "PostgreSQL"},
-		{"Redis", 6379, "Redis"},
-		{"HTTP-Alt", 8080, "HTTP-Alt"},
-		{"HTTPS-Alt", 8443, "HTTPS-Alt"},
-		{"MongoDB", 27017, "MongoDB"},
-		{"unknown port", 12345, "Unknown"},
-		{"another unknown", 9999, "Unknown"},
+		{"SSH", 22, "tcp", "ssh"},
+		{"HTTPS", 443, "tcp", "https"},
+		{"SMB", 445, "tcp", "smb"},
+		{"PostgreSQL", 5432, "tcp", "postgresql"},
+		{"MongoDB", 27017, "tcp", "mongodb"},
+		{"no protocol means TCP", 3389, "", "rdp"},
+		// Ports beyond the old 17-entry UI map are named too.
+		{"SNMP over UDP", 161, "udp", "snmp"},
+		{"NTP over UDP", 123, "udp", "ntp"},
+		{"WireGuard over UDP", 51820, "udp", "wireguard"},
+		{"DNS over UDP", 53, "udp", "dns"},
+		// TCP-only services do not label UDP rows, and the reverse.
+		{"Redis port over UDP", 6379, "udp", "unknown"},
+		{"VXLAN port over TCP", 4789, "tcp", "unknown"},
+		{"protocol is case-insensitive", 161, "UDP", "snmp"},
+		{"unknown port", 60000, "tcp", "unknown"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getServiceName(tt.port)
-			if result != tt.expected {
-				t.Errorf("getServiceName(%d) = %s; want %s", tt.port, result, tt.expected)
+			r := core.ResultEvent{Port: tt.port, Protocol: tt.protocol}
+			if got := serviceName(r); got != tt.expected {
+				t.Errorf("serviceName(%d/%s) = %s; want %s", tt.port, tt.protocol, got, tt.expected)
 			}
 		})
 	}
 }
 
-func TestGetServiceName_EdgeCases(t *testing.T) {
+func TestExpectedServiceAnalysis(t *testing.T) {
 	tests := []struct {
 		name     string
-		port     uint16
-		expected string
+		result   core.ResultEvent
+		contains string
 	}{
-		{"port 0", 0, "Unknown"},
-		{"port 1", 1, "Unknown"},
-		{"max port", 65535, "Unknown"},
+		{"UDP registration", core.ResultEvent{Port: 161, Protocol: "udp"}, "snmp (registered for 161/udp)"},
+		{"TCP registration", core.ResultEvent{Port: 22, Protocol: "tcp"}, "ssh (registered for 22/tcp)"},
+		{"TCP-only service on UDP", core.ResultEvent{Port: 6379, Protocol: "udp"}, "none for 6379/udp (redis is registered for TCP only)"},
+		{"UDP-only service on TCP", core.ResultEvent{Port: 4789, Protocol: "tcp"}, "none for 4789/tcp (vxlan is registered for UDP only)"},
+		{"unassigned", core.ResultEvent{Port: 60000, Protocol: "udp"}, "none registered for 60000/udp"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getServiceName(tt.port)
-			if result != tt.expected {
-				t.Errorf("getServiceName(%d) = %s; want %s", tt.port, result, tt.expected)
+			if got := expectedServiceAnalysis(tt.result); !strings.Contains(got, tt.contains) {
+				t.Errorf("expectedServiceAnalysis() = %q; want it to contain %q", got, tt.contains)
 			}
 		})
 	}
 }
+
+func TestStateAnalysis_UDPWording(t *testing.T) {
+	closed := stateAnalysis(core.ResultEvent{Port: 53, Protocol: "udp", State: core.StateClosed})
+	if !strings.Contains(closed, "ICMP port unreachable") {
+		t.Errorf("UDP closed analysis = %q; want ICMP wording", closed)
+	}
+	filtered := stateAnalysis(core.ResultEvent{Port: 53, Protocol: "udp", State: core.StateFiltered})
+	if !strings.Contains(filtered, "Open|Filtered") {
+		t.Errorf("UDP filtered analysis = %q; want Open|Filtered", filtered)
+	}
+	tcp := stateAnalysis(core.ResultEvent{Port: 22, Protocol: "tcp", State: core.StateClosed})
+	if !strings.Contains(tcp, "not accepting connections") {
+		t.Errorf("TCP closed analysis = %q; want connection wording", tcp)
+	}
+}
+
+func TestMismatchAnalysis(t *testing.T) {
+	r := core.ResultEvent{Port: 8080, Protocol: "tcp", Banner: "SSH-2.0-OpenSSH_9.6"}
+	if got := mismatchAnalysis(r); !strings.Contains(got, "ssh answered on 8080/tcp, where http-alt is registered") {
+		t.Errorf("mismatchAnalysis() = %q", got)
+	}
+	if got := detectedServiceAnalysis(r); !strings.Contains(got, "Detected Service: ssh") {
+		t.Errorf("detectedServiceAnalysis() = %q", got)
+	}
+
+	r.Port = 22
+	if got := mismatchAnalysis(r); got != "" {
+		t.Errorf("mismatchAnalysis() for SSH on 22 = %q; want none", got)
+	}
+}
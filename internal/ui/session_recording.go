@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+)
+
+// Terminal size recorded when the real one cannot be read.
+const (
+	defaultSessionWidth  = 80
+	defaultSessionHeight = 24
+)
+
+// castHeader is the first line of an asciinema v2 recording.
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// SessionRecorder writes a TUI session as an asciinema v2 recording: a JSON
+// header followed by one [seconds, code, data] line per event. What the UI
+// draws is recorded as "o" events, the keys read from the terminal as "i"
+// events and window resizes as "r" events.
+type SessionRecorder struct {
+	mu    sync.Mutex
+	w     *bufio.Writer
+	start time.Time
+	// partial holds the end of a write or read that split a UTF-8
+	// sequence, by event code, until the rest of it arrives.
+	partial map[string][]byte
+	err     error
+}
+
+// NewSessionRecorder writes the recording header for a width by height
+// terminal to w and returns the recorder for the session's events.
+func NewSessionRecorder(w io.Writer, width, height int) (*SessionRecorder, error) {
+	r := &SessionRecorder{w: bufio.NewWriter(w), start: time.Now(), partial: make(map[string][]byte)}
+	header := castHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: r.start.Unix(),
+		Env:       map[string]string{"TERM": os.Getenv("TERM"), "SHELL": os.Getenv("SHELL")},
+	}
+	line, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.w.Write(append(line, '\n')); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Output records data drawn to the terminal.
+func (r *SessionRecorder) Output(data []byte) { r.record("o", data) }
+
+// Input records data read from the terminal.
+func (r *SessionRecorder) Input(data []byte) { r.record("i", data) }
+
+// Resize records the terminal changing size.
+func (r *SessionRecorder) Resize(width, height int) {
+	r.record("r", []byte(fmt.Sprintf("%dx%d", width, height)))
+}
+
+// Close flushes the recording and returns the first error writing it.
+func (r *SessionRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = r.w.Flush()
+	}
+	return r.err
+}
+
+func (r *SessionRecorder) record(code string, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	data = append(r.partial[code], data...)
+	data, r.partial[code] = splitPartialRune(data)
+	if len(data) == 0 {
+		return
+	}
+	elapsed := float64(time.Since(r.start).Microseconds()) / 1e6
+	line, err := json.Marshal([]interface{}{elapsed, code, string(data)})
+	if err == nil {
+		_, err = r.w.Write(append(line, '\n'))
+	}
+	r.err = err
+}
+
+// splitPartialRune splits off the bytes at the end of b that start a UTF-8
+// sequence without finishing it.
+func splitPartialRune(b []byte) ([]byte, []byte) {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return b[:i], append([]byte(nil), b[i:]...)
+			}
+			break
+		}
+	}
+	return b, nil
+}
+
+// recordedFile is a terminal whose reads and writes are also recorded. It
+// keeps the file's descriptor so the program still sees a terminal.
+type recordedFile struct {
+	*os.File
+	rec *SessionRecorder
+}
+
+func (f recordedFile) Write(p []byte) (int, error) {
+	n, err := f.File.Write(p)
+	f.rec.Output(p[:n])
+	return n, err
+}
+
+func (f recordedFile) Read(p []byte) (int, error) {
+	n, err := f.File.Read(p)
+	f.rec.Input(p[:n])
+	return n, err
+}
+
+// RecordSession records the session Run draws, with the keys typed into
+// it, to w in asciinema v2 format.
+func (m *ScanUI) RecordSession(w io.Writer) {
+	m.sessionOut = w
+}
+
+// startSessionRecording writes the recording header with the terminal's
+// size and returns the program options routing its output, and its input
+// when that is a terminal, through the recorder.
+func (m *ScanUI) startSessionRecording() ([]tea.ProgramOption, error) {
+	width, height, err := term.GetSize(os.Stdout.Fd())
+	if err != nil {
+		width, height = defaultSessionWidth, defaultSessionHeight
+	}
+	rec, err := NewSessionRecorder(m.sessionOut, width, height)
+	if err != nil {
+		return nil, err
+	}
+	m.session = rec
+	opts := []tea.ProgramOption{tea.WithOutput(recordedFile{File: os.Stdout, rec: rec})}
+	if term.IsTerminal(os.Stdin.Fd()) {
+		opts = append(opts, tea.WithInput(recordedFile{File: os.Stdin, rec: rec}))
+	}
+	return opts, nil
+}
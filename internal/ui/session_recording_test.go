@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSessionRecorder(t *testing.T) {
+	var buf bytes.Buffer
+	rec, err := NewSessionRecorder(&buf, 120, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Output([]byte("\x1b[?1049hscan \xe2\x96"))
+	rec.Output([]byte("\x88 done"))
+	rec.Input([]byte("q"))
+	rec.Resize(100, 30)
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("got %d lines, want a header and 4 events:\n%s", len(lines), buf.String())
+	}
+	var header castHeader
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatal(err)
+	}
+	if header.Version != 2 || header.Width != 120 || header.Height != 40 || header.Timestamp == 0 {
+		t.Errorf("header = %+v", header)
+	}
+
+	want := [][2]string{
+		{"o", "\x1b[?1049hscan "},
+		{"o", "█ done"}, // the block split across writes is kept whole
+		{"i", "q"},
+		{"r", "100x30"},
+	}
+	for i, line := range lines[1:] {
+		var event []interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatal(err)
+		}
+		if len(event) != 3 {
+			t.Fatalf("event %d = %v, want [time, code, data]", i, event)
+		}
+		if _, ok := event[0].(float64); !ok {
+			t.Errorf("event %d time = %v, want seconds", i, event[0])
+		}
+		if event[1] != want[i][0] || event[2] != want[i][1] {
+			t.Errorf("event %d = %q %q, want %q %q", i, event[1], event[2], want[i][0], want[i][1])
+		}
+	}
+}
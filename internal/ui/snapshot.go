@@ -0,0 +1,219 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/lucchesi-sec/portscan/pkg/theme"
+)
+
+// Snapshot layout, in pixels.
+const (
+	snapshotWidth       = 960
+	snapshotPadding     = 32
+	snapshotLineHeight  = 28
+	snapshotBarMaxWidth = 480
+	snapshotSparkHeight = 56
+)
+
+// snapshotSavedMsg reports the outcome of a dashboard snapshot.
+type snapshotSavedMsg struct {
+	path string
+	err  error
+}
+
+// dashboardSnapshot is the dashboard as it stood when the snapshot was
+// taken, copied so it can be drawn off the UI goroutine.
+type dashboardSnapshot struct {
+	taken     time.Time
+	theme     theme.Theme
+	stats     StatsData
+	summary   MetricSummary
+	scanRate  []float64
+	discovery []float64
+	errorRate []float64
+	points    int // sparkline length, one point per progress event
+}
+
+// defaultSnapshotPath suggests a timestamped SVG file name.
+func defaultSnapshotPath(now time.Time) string {
+	return "portscan-dashboard-" + now.Format("20060102-150405") + ".svg"
+}
+
+// saveSnapshot draws the dashboard statistics to path in the background,
+// as SVG or PNG by the file's extension.
+func (m *ScanUI) saveSnapshot(path string) tea.Cmd {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return m.showToast(i18n.T("ui.export.no_path"), true)
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".svg" && ext != ".png" {
+		return m.showToast(i18n.T("ui.snapshot.bad_format", ext), true)
+	}
+
+	snap := dashboardSnapshot{
+		taken:   time.Now(),
+		theme:   m.theme,
+		stats:   *m.computeStats(),
+		summary: m.sparklineData.GetMetricSummary(),
+		points:  m.sparklineData.MaxPoints,
+	}
+	for _, s := range []struct {
+		src []TimeSeriesData
+		dst *[]float64
+	}{
+		{m.sparklineData.ScanRate, &snap.scanRate},
+		{m.sparklineData.DiscoveryRate, &snap.discovery},
+		{m.sparklineData.ErrorRate, &snap.errorRate},
+	} {
+		for _, point := range s.src {
+			*s.dst = append(*s.dst, point.Value)
+		}
+	}
+
+	return func() tea.Msg {
+		return snapshotSavedMsg{path: path, err: writeSnapshot(path, snap)}
+	}
+}
+
+// handleSnapshotSaved reports the snapshot outcome in the footer.
+func (m *ScanUI) handleSnapshotSaved(msg snapshotSavedMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.showToast(i18n.T("ui.snapshot.failed", msg.err), true)
+	}
+	return m.showToast(i18n.T("ui.snapshot.done", msg.path), false)
+}
+
+// writeSnapshot writes snap to path, as PNG when the name ends in .png and
+// SVG otherwise.
+func writeSnapshot(path string, snap dashboardSnapshot) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	canvas := snap.layout()
+	if strings.EqualFold(filepath.Ext(path), ".png") {
+		err = canvas.writePNG(file)
+	} else {
+		err = canvas.writeSVG(file)
+	}
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// layout draws the snapshot: the port state bar chart, the scan, discovery
+// and error rate sparklines, the top services and, when both protocols
+// were scanned, the TCP and UDP counts.
+func (s dashboardSnapshot) layout() *snapshotCanvas {
+	t := s.theme
+	fg, muted := rgbColor(t.Foreground), rgbColor(t.Muted)
+	heading := rgbColor(t.Secondary)
+	c := &snapshotCanvas{width: snapshotWidth, background: rgbColor(t.Background)}
+	x, y := snapshotPadding, snapshotPadding
+	barX := x + snapshotTextWidth("Filtered    ")
+
+	section := func(title string) {
+		y += snapshotLineHeight / 2
+		c.text(x, y, title, heading, true)
+		y += snapshotLineHeight
+	}
+	bar := func(label string, count, total int, fill color.RGBA) {
+		c.text(x, y, label, fg, false)
+		width := 0
+		if total > 0 {
+			width = count * snapshotBarMaxWidth / total
+		}
+		c.rect(barX, y+4, max(width, 1), snapshotLineHeight-12, fill)
+		c.text(barX+width+snapshotCharWidth, y, fmt.Sprintf("%d (%.1f%%)", count, getPercentage(count, total)), muted, false)
+		y += snapshotLineHeight
+	}
+
+	c.text(x, y, "Portscan dashboard", rgbColor(t.Primary), true)
+	y += snapshotLineHeight
+	c.text(x, y, fmt.Sprintf("%s | %d results | %d hosts, %d with open ports",
+		s.taken.Format("2006-01-02 15:04:05 MST"), s.stats.TotalResults, s.stats.UniqueHosts, s.stats.HostsWithOpen), muted, false)
+	y += snapshotLineHeight
+
+	colors := t.GetStateColors()
+	section("Port states")
+	bar("Open", s.stats.OpenCount, s.stats.TotalResults, rgbColor(colors.Open))
+	bar("Closed", s.stats.ClosedCount, s.stats.TotalResults, rgbColor(colors.Closed))
+	bar("Filtered", s.stats.FilteredCount, s.stats.TotalResults, rgbColor(colors.Filtered))
+
+	if len(s.stats.Protocols) > 1 {
+		section("TCP vs UDP")
+		for _, p := range s.stats.Protocols {
+			c.text(x, y, fmt.Sprintf("%s: %d ports | %d open | %d closed | %d filtered",
+				strings.ToUpper(p.Name), p.Total(), p.Open, p.Closed, p.Filtered), fg, false)
+			y += snapshotLineHeight
+		}
+	}
+
+	section("Top services")
+	if len(s.stats.TopServices) == 0 {
+		c.text(x, y, "No services detected", muted, false)
+		y += snapshotLineHeight
+	}
+	services := 0
+	for _, svc := range s.stats.TopServices {
+		services += svc.Count
+	}
+	for _, svc := range s.stats.TopServices {
+		bar(svc.Name, svc.Count, services, rgbColor(t.Primary))
+	}
+
+	sparkline := func(title string, values []float64, fill color.RGBA, summary string) {
+		section(title)
+		y = s.drawSparkline(c, barX, y, values, fill)
+		c.text(barX, y, summary, muted, false)
+		y += snapshotLineHeight
+	}
+	sparkline("Scan rate (60s)", s.scanRate, rgbColor(t.Primary), fmt.Sprintf("Cur %.1f | Avg %.1f | Peak %.1f pps",
+		s.summary.CurrentScanRate, s.summary.AverageScanRate, s.summary.PeakScanRate))
+	sparkline("Discovery rate (60s)", s.discovery, rgbColor(t.Success), fmt.Sprintf("Cur %.1f | Avg %.1f pps",
+		s.summary.CurrentDiscoveryRate, s.summary.AverageDiscoveryRate))
+	sparkline("Error rate (60s)", s.errorRate, rgbColor(t.Danger), fmt.Sprintf("Cur %.1f%% | Avg %.1f%% of probes",
+		s.summary.CurrentErrorRate, s.summary.AverageErrorRate))
+
+	if s.stats.AvgResponseTime > 0 {
+		section("Response times")
+		c.text(x, y, fmt.Sprintf("Min %dms | Avg %dms | P95 %dms | Max %dms",
+			s.stats.MinResponseTime.Milliseconds(), s.stats.AvgResponseTime.Milliseconds(),
+			s.stats.P95ResponseTime.Milliseconds(), s.stats.MaxResponseTime.Milliseconds()), fg, false)
+		y += snapshotLineHeight
+	}
+
+	c.height = y + snapshotPadding
+	return c
+}
+
+// drawSparkline draws values as columns scaled to the largest, and returns
+// the y below them.
+func (s dashboardSnapshot) drawSparkline(c *snapshotCanvas, x, y int, values []float64, fill color.RGBA) int {
+	c.rect(x, y+snapshotSparkHeight, snapshotBarMaxWidth, 1, rgbColor(s.theme.Muted))
+	peak := 0.0
+	for _, v := range values {
+		if v > peak {
+			peak = v
+		}
+	}
+	step := snapshotBarMaxWidth / max(s.points, 1)
+	for i, v := range values {
+		if v <= 0 || peak <= 0 {
+			continue
+		}
+		h := max(int(v/peak*snapshotSparkHeight), 1)
+		c.rect(x+i*step, y+snapshotSparkHeight-h, step-1, h, fill)
+	}
+	return y + snapshotSparkHeight + 8
+}
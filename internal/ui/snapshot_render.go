@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Snapshot text metrics. Text is 20px monospace, whose advance is about
+// 0.6em; the baseline sits 15px below the top of a line.
+const (
+	snapshotFontSize  = 20
+	snapshotCharWidth = 12
+	snapshotBaseline  = 15
+)
+
+// snapshotOp is one thing drawn on a snapshot: a line of text when text
+// is set, otherwise a filled rectangle.
+type snapshotOp struct {
+	text  string
+	bold  bool
+	x, y  int // top left corner
+	w, h  int
+	color color.RGBA
+}
+
+// snapshotCanvas is a drawing both snapshot formats render the same way,
+// so the SVG and the PNG of a dashboard match.
+type snapshotCanvas struct {
+	width, height int
+	background    color.RGBA
+	ops           []snapshotOp
+}
+
+func (c *snapshotCanvas) rect(x, y, w, h int, fill color.RGBA) {
+	c.ops = append(c.ops, snapshotOp{x: x, y: y, w: w, h: h, color: fill})
+}
+
+func (c *snapshotCanvas) text(x, y int, s string, fill color.RGBA, bold bool) {
+	c.ops = append(c.ops, snapshotOp{text: s, bold: bold, x: x, y: y, color: fill})
+}
+
+// writeSVG writes the canvas as an SVG document.
+func (c *snapshotCanvas) writeSVG(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="monospace" font-size="%d">`+"\n",
+		c.width, c.height, c.width, c.height, snapshotFontSize)
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="%s"/>`+"\n", c.width, c.height, hexColor(c.background))
+	for _, op := range c.ops {
+		if op.text == "" {
+			fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", op.x, op.y, op.w, op.h, hexColor(op.color))
+			continue
+		}
+		weight := ""
+		if op.bold {
+			weight = ` font-weight="bold"`
+		}
+		fmt.Fprintf(bw, `<text x="%d" y="%d" fill="%s"%s>`, op.x, op.y+snapshotBaseline, hexColor(op.color), weight)
+		if err := xml.EscapeText(bw, []byte(op.text)); err != nil {
+			return err
+		}
+		bw.WriteString("</text>\n")
+	}
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+// writePNG rasterizes the canvas, drawing text in Go Mono so it matches
+// the SVG's monospace grid.
+func (c *snapshotCanvas) writePNG(w io.Writer) error {
+	regular, bold, err := snapshotFaces()
+	if err != nil {
+		return err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, c.width, c.height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c.background), image.Point{}, draw.Src)
+	for _, op := range c.ops {
+		if op.text == "" {
+			draw.Draw(img, image.Rect(op.x, op.y, op.x+op.w, op.y+op.h), image.NewUniform(op.color), image.Point{}, draw.Src)
+			continue
+		}
+		d := font.Drawer{Dst: img, Src: image.NewUniform(op.color), Face: regular}
+		if op.bold {
+			d.Face = bold
+		}
+		// Place each rune on the grid rather than by the face's advance,
+		// so columns line up with the SVG whatever the hinting.
+		x := op.x
+		for _, r := range op.text {
+			d.Dot = fixed.P(x, op.y+snapshotBaseline)
+			d.DrawString(string(r))
+			x += snapshotCharWidth
+		}
+	}
+	return png.Encode(w, img)
+}
+
+var (
+	snapshotFaceOnce              sync.Once
+	snapshotRegular, snapshotBold font.Face
+	snapshotFaceErr               error
+)
+
+// snapshotFaces returns the regular and bold Go Mono faces at the
+// snapshot's font size, parsing them on first use.
+func snapshotFaces() (regular, bold font.Face, err error) {
+	snapshotFaceOnce.Do(func() {
+		opts := &opentype.FaceOptions{Size: snapshotFontSize, DPI: 72, Hinting: font.HintingFull}
+		if snapshotRegular, snapshotFaceErr = parseFace(gomono.TTF, opts); snapshotFaceErr != nil {
+			return
+		}
+		snapshotBold, snapshotFaceErr = parseFace(gomonobold.TTF, opts)
+	})
+	return snapshotRegular, snapshotBold, snapshotFaceErr
+}
+
+func parseFace(ttf []byte, opts *opentype.FaceOptions) (font.Face, error) {
+	f, err := opentype.Parse(ttf)
+	if err != nil {
+		return nil, err
+	}
+	return opentype.NewFace(f, opts)
+}
+
+// rgbColor converts a theme color, an ANSI index or a hex string, to RGB.
+func rgbColor(c lipgloss.Color) color.RGBA {
+	r, g, b := termenv.ConvertToRGB(termenv.TrueColor.Color(string(c))).RGB255()
+	return color.RGBA{R: r, G: g, B: b, A: 0xff}
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// snapshotTextWidth returns the width of s in snapshot pixels.
+func snapshotTextWidth(s string) int {
+	return len([]rune(s)) * snapshotCharWidth
+}
@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+func newSnapshotTestUI() *ScanUI {
+	ui := NewScanUI(&config.Config{}, 10, make(chan core.Event), false)
+	ui.recordResults([]core.ResultEvent{
+		{Host: "10.0.0.1", Port: 22, State: core.StateOpen, Duration: 3 * time.Millisecond},
+		{Host: "10.0.0.1", Port: 80, State: core.StateOpen, Duration: 5 * time.Millisecond},
+		{Host: "10.0.0.2", Port: 80, State: core.StateOpen, Duration: 9 * time.Millisecond},
+		{Host: "10.0.0.2", Port: 23, State: core.StateClosed},
+	})
+	for i, completed := range []int{2, 3, 4} {
+		ui.handleScanProgress(scanProgressMsg{progress: core.ProgressEvent{Total: 4, Completed: completed, Rate: float64(100 * (i + 1)), Errors: i}})
+	}
+	return ui
+}
+
+// takeSnapshot saves a snapshot to name in a temporary directory and
+// returns its path once the UI has reported it.
+func takeSnapshot(t *testing.T, ui *ScanUI, name string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	msg := ui.saveSnapshot(path)().(snapshotSavedMsg)
+	if msg.err != nil {
+		t.Fatal(msg.err)
+	}
+	ui.Update(msg)
+	if ui.toastIsError || !strings.Contains(ui.toast, path) {
+		t.Errorf("toast = %q", ui.toast)
+	}
+	return path
+}
+
+func TestSnapshotSVG(t *testing.T) {
+	ui := newSnapshotTestUI()
+	data, err := os.ReadFile(takeSnapshot(t, ui, "dash.svg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(string(data)))
+	for {
+		if _, err := decoder.Token(); err != nil {
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("snapshot is not well-formed XML: %v", err)
+			}
+			break
+		}
+	}
+	svg := string(data)
+	for _, want := range []string{"Port states", "3 (75.0%)", "Top services", "http", "Scan rate (60s)", "Error rate (60s)", "Cur 300.0"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("snapshot lacks %q", want)
+		}
+	}
+	if strings.Contains(svg, "TCP vs UDP") {
+		t.Error("the protocol breakdown needs results from both protocols")
+	}
+}
+
+func TestSnapshotPNG(t *testing.T) {
+	ui := newSnapshotTestUI()
+	file, err := os.Open(takeSnapshot(t, ui, "dash.PNG"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("snapshot is not a PNG: %v", err)
+	}
+	canvas := ui.snapshotLayoutForTest()
+	if got := img.Bounds().Dx(); got != snapshotWidth || img.Bounds().Dy() != canvas.height {
+		t.Errorf("image is %v; want %dx%d", img.Bounds(), snapshotWidth, canvas.height)
+	}
+
+	// The first bar drawn is the open ports', in the theme's open color.
+	for _, op := range canvas.ops {
+		if op.text == "" {
+			r, g, b, _ := img.At(op.x, op.y).RGBA()
+			if uint8(r>>8) != op.color.R || uint8(g>>8) != op.color.G || uint8(b>>8) != op.color.B {
+				t.Errorf("open bar pixel = %d,%d,%d; want %v", r>>8, g>>8, b>>8, op.color)
+			}
+			break
+		}
+	}
+}
+
+// TestSnapshotPNGDrawsLowercase checks that the PNG's font has lowercase
+// letters of its own, rather than drawing every letter as a capital.
+func TestSnapshotPNGDrawsLowercase(t *testing.T) {
+	render := func(text string) *image.RGBA {
+		canvas := &snapshotCanvas{width: 40, height: 24, background: color.RGBA{A: 0xff}}
+		canvas.text(4, 0, text, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, false)
+		var buf bytes.Buffer
+		if err := canvas.writePNG(&buf); err != nil {
+			t.Fatalf("writePNG: %v", err)
+		}
+		img, err := png.Decode(&buf)
+		if err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		rgba := image.NewRGBA(img.Bounds())
+		draw.Draw(rgba, rgba.Bounds(), img, image.Point{}, draw.Src)
+		return rgba
+	}
+	upper, lower := render("Open"), render("OPEN")
+	if bytes.Equal(upper.Pix, lower.Pix) {
+		t.Error("Open and OPEN render the same; lowercase letters are missing")
+	}
+	if blank := render(""); bytes.Equal(upper.Pix, blank.Pix) {
+		t.Error("text was not drawn")
+	}
+}
+
+func TestSnapshotRejectsOtherFormats(t *testing.T) {
+	ui := newSnapshotTestUI()
+	if cmd := ui.saveSnapshot(filepath.Join(t.TempDir(), "dash.jpg")); cmd == nil || !ui.toastIsError {
+		t.Error("only .svg and .png snapshots should be written")
+	}
+}
+
+// snapshotLayoutForTest lays out the dashboard as saveSnapshot would.
+func (m *ScanUI) snapshotLayoutForTest() *snapshotCanvas {
+	return dashboardSnapshot{theme: m.theme, stats: *m.computeStats(), points: m.sparklineData.MaxPoints}.layout()
+}
@@ -68,8 +68,8 @@ func (s *SortState) ApplySort(results []core.ResultEvent) []core.ResultEvent {
 
 	case SortByService:
 		sort.Slice(sorted, func(i, j int) bool {
-			serviceI := getServiceName(sorted[i].Port)
-			serviceJ := getServiceName(sorted[j].Port)
+			serviceI := serviceName(sorted[i])
+			serviceJ := serviceName(sorted[j])
 			// Sort by service name, then by port if services are equal
 			if serviceI == serviceJ {
 				return sorted[i].Port < sorted[j].Port
@@ -4,6 +4,8 @@ import (
 	"math"
 	"strings"
 	"time"
+
+	"github.com/lucchesi-sec/portscan/pkg/theme"
 )
 
 // TimeSeriesData represents a time-series data point
@@ -18,6 +20,9 @@ type SparklineData struct {
 	DiscoveryRate []TimeSeriesData
 	ErrorRate   []TimeSeriesData
 	MaxPoints   int
+	// Levels are the sparkline characters, lowest first; nil uses the
+	// Unicode blocks
+	Levels []rune
 }
 
 // NewSparklineData creates a new sparkline data collector
@@ -73,11 +78,17 @@ func (s *SparklineData) RenderSparkline(series []TimeSeriesData, width int) stri
 		values[i] = data.Value
 	}
 
-	return renderSparklineValues(values, width)
+	return renderSparklineLevels(values, width, s.Levels)
 }
 
 // renderSparklineValues renders a sparkline from float values
 func renderSparklineValues(values []float64, width int) string {
+	return renderSparklineLevels(values, width, nil)
+}
+
+// renderSparklineLevels renders a sparkline from float values with the
+// given characters, lowest first
+func renderSparklineLevels(values []float64, width int, blocks []rune) string {
 	if len(values) == 0 || width <= 0 {
 		return ""
 	}
@@ -99,8 +110,9 @@ func renderSparklineValues(values []float64, width int) string {
 		max = min + 1
 	}
 
-	// Sparkline characters from lowest to highest
-	blocks := []rune{' ', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+	if len(blocks) == 0 {
+		blocks = theme.UnicodeGlyphs.Sparkline
+	}
 
 	// Determine the sampling interval
 	dataLen := len(values)
@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+// resultSpill appends results evicted from the ring buffer to a temporary
+// NDJSON file so exports can include the full scan.
+type resultSpill struct {
+	file   *os.File
+	writer *bufio.Writer
+	count  int
+	size   int64
+	err    error
+}
+
+// newResultSpill creates the spill file in dir, or the system temp
+// directory when dir is empty.
+func newResultSpill(dir string) (*resultSpill, error) {
+	file, err := os.CreateTemp(dir, "portscan-overflow-*.ndjson")
+	if err != nil {
+		return nil, err
+	}
+	return &resultSpill{file: file, writer: bufio.NewWriter(file)}, nil
+}
+
+// Write appends one result. After the first failure further writes are
+// ignored and the error is kept for Snapshot.
+func (s *resultSpill) Write(result core.ResultEvent) {
+	if s.err != nil {
+		return
+	}
+	line, err := json.Marshal(result)
+	if err != nil {
+		s.err = err
+		return
+	}
+	n, err := s.writer.Write(append(line, '\n'))
+	s.size += int64(n)
+	if err != nil {
+		s.err = err
+		return
+	}
+	s.count++
+}
+
+// Count reports how many results have been spilled.
+func (s *resultSpill) Count() int {
+	return s.count
+}
+
+// Snapshot flushes buffered writes and returns the file path and the size
+// written so far. Readers should stop at that size because the scan keeps
+// appending while an export runs.
+func (s *resultSpill) Snapshot() (string, int64, error) {
+	if s.err == nil {
+		s.err = s.writer.Flush()
+	}
+	return s.file.Name(), s.size, s.err
+}
+
+// Close removes the spill file.
+func (s *resultSpill) Close() error {
+	closeErr := s.file.Close()
+	if err := os.Remove(s.file.Name()); err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// readSpill loads the first size bytes of a spill file.
+func readSpill(path string, size int64) ([]core.ResultEvent, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var results []core.ResultEvent
+	decoder := json.NewDecoder(io.LimitReader(file, size))
+	for decoder.More() {
+		var r core.ResultEvent
+		if err := decoder.Decode(&r); err != nil {
+			return results, fmt.Errorf("read overflow file: %w", err)
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// appendResult adds a result to the ring buffer, spilling the evicted
+// result to disk when overflow spilling is enabled.
+func (m *ScanUI) appendResult(result core.ResultEvent) {
+	evicted, ok := m.results.Append(result)
+	if !ok || !m.config.UI.SpillOverflow || m.spillErr != nil {
+		return
+	}
+	if m.spill == nil {
+		spill, err := newResultSpill("")
+		if err != nil {
+			m.spillErr = err
+			return
+		}
+		m.spill = spill
+	}
+	m.spill.Write(evicted)
+}
+
+// closeSpill removes the spill file, if one was created.
+func (m *ScanUI) closeSpill() {
+	if m.spill != nil {
+		_ = m.spill.Close()
+		m.spill = nil
+	}
+}
+
+// overflowDescription describes evicted results for the indicator row, or
+// returns an empty string when nothing has been evicted.
+func (m *ScanUI) overflowDescription() string {
+	evicted := m.results.Evicted()
+	if evicted == 0 {
+		return ""
+	}
+	shown := m.results.Len()
+	desc := fmt.Sprintf("Showing last %d of %d results", shown, shown+evicted)
+	switch {
+	case m.spillErr != nil || (m.spill != nil && m.spill.err != nil):
+		desc += " (overflow file unavailable)"
+	case m.spill != nil:
+		desc += " (export includes all)"
+	}
+	return desc
+}
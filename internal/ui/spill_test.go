@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+func newOverflowUI(t *testing.T, spill bool) *ScanUI {
+	t.Helper()
+	t.Setenv("TMPDIR", t.TempDir())
+	cfg := &config.Config{UI: config.UIConfig{ResultBufferSize: 2, SpillOverflow: spill}}
+	ui := NewScanUI(cfg, 10, make(chan core.Event), false)
+	t.Cleanup(ui.closeSpill)
+	for port := uint16(1); port <= 5; port++ {
+		ui.handleScanResult(scanResultMsg{result: core.ResultEvent{Host: "10.0.0.1", Port: port, State: core.StateOpen}})
+	}
+	return ui
+}
+
+func TestOverflowIndicator(t *testing.T) {
+	ui := newOverflowUI(t, false)
+
+	if !ui.indicatorsVisible() {
+		t.Error("indicators should be visible once results are evicted")
+	}
+	got := ui.renderSortFilterIndicators()
+	if !strings.Contains(got, "Showing last 2 of 5 results") {
+		t.Errorf("indicator = %q, want overflow count", got)
+	}
+	if ui.spill != nil {
+		t.Error("no spill file should be created unless enabled")
+	}
+}
+
+func TestSpillKeepsEvictedResultsForExport(t *testing.T) {
+	ui := newOverflowUI(t, true)
+
+	if ui.spill == nil || ui.spill.Count() != 3 {
+		t.Fatalf("expected 3 spilled results, got %+v", ui.spill)
+	}
+	if !strings.Contains(ui.overflowDescription(), "export includes all") {
+		t.Errorf("indicator = %q, want spill note", ui.overflowDescription())
+	}
+
+	path := filepath.Join(t.TempDir(), "all.csv")
+	ui.openExportModal()
+	ui.cycleExportFormat(1) // csv
+	ui.exportState.PathInput.SetValue(path)
+
+	cmd := ui.startExport()
+	msg, ok := cmd().(exportFinishedMsg)
+	if !ok {
+		t.Fatalf("expected exportFinishedMsg, got %T", cmd())
+	}
+	if msg.err != nil || msg.count != 5 {
+		t.Fatalf("export = %+v, want 5 results without error", msg)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 6 {
+		t.Errorf("expected header plus 5 rows, got %d lines", lines)
+	}
+}
+
+func TestCloseSpillRemovesFile(t *testing.T) {
+	ui := newOverflowUI(t, true)
+	spillPath, _, err := ui.spill.Snapshot()
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+
+	ui.closeSpill()
+	if _, err := os.Stat(spillPath); !os.IsNotExist(err) {
+		t.Errorf("spill file should be removed, stat err = %v", err)
+	}
+}
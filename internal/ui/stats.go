@@ -1,7 +1,9 @@
 package ui
 
 import (
+	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/lucchesi-sec/portscan/internal/core"
@@ -18,6 +20,14 @@ type StatsData struct {
 	ServiceCounts map[string]int
 	TopServices   []ServiceStat
 
+	// Open ports by GeoIP autonomous system and country, top five each;
+	// empty unless results were located with --geoip-db
+	TopASNs      []ServiceStat
+	TopCountries []ServiceStat
+
+	// Results by protocol, TCP first; only protocols with results
+	Protocols []ProtocolStat
+
 	// Response time statistics
 	MinResponseTime time.Duration
 	MaxResponseTime time.Duration
@@ -33,12 +43,26 @@ type StatsData struct {
 	HostsWithOpen int
 }
 
-// ServiceStat represents a service with its count
+// ServiceStat represents a service, or another grouping of ports, with its
+// count
 type ServiceStat struct {
 	Name  string
 	Count int
 }
 
+// ProtocolStat counts a protocol's results by state
+type ProtocolStat struct {
+	Name     string
+	Open     int
+	Closed   int
+	Filtered int
+}
+
+// Total returns the protocol's result count
+func (p ProtocolStat) Total() int {
+	return p.Open + p.Closed + p.Filtered
+}
+
 // computeStats calculates statistics from current results
 func (m *ScanUI) computeStats() *StatsData {
 	stats := &StatsData{
@@ -59,23 +83,36 @@ func (m *ScanUI) computeStats() *StatsData {
 
 	hostsMap := make(map[string]bool)
 	hostsWithOpen := make(map[string]bool)
+	asnCounts := make(map[string]int)
+	countryCounts := make(map[string]int)
+	protocols := map[string]*ProtocolStat{"tcp": {Name: "tcp"}, "udp": {Name: "udp"}}
 
 	// Collect statistics
 	for _, result := range results {
 		// Count states
+		protocol := protocols[resultProtocol(result)]
 		switch result.State {
 		case core.StateOpen:
 			stats.OpenCount++
+			protocol.Open++
 			hostsWithOpen[result.Host] = true
+			if result.ASN != 0 {
+				asnCounts[strings.TrimSpace(fmt.Sprintf("AS%d %s", result.ASN, result.ASOrg))]++
+			}
+			if result.Country != "" {
+				countryCounts[result.Country]++
+			}
 		case core.StateClosed:
 			stats.ClosedCount++
+			protocol.Closed++
 		case core.StateFiltered:
 			stats.FilteredCount++
+			protocol.Filtered++
 		}
 
 		// Count services
-		service := getServiceName(result.Port)
-		if service != "" && service != "Unknown" {
+		service := serviceName(result)
+		if service != "unknown" {
 			stats.ServiceCounts[service]++
 		}
 
@@ -112,30 +149,15 @@ func (m *ScanUI) computeStats() *StatsData {
 		}
 	}
 
-	// Top services
-	type servicePair struct {
-		name  string
-		count int
-	}
-	var servicePairs []servicePair
-	for name, count := range stats.ServiceCounts {
-		servicePairs = append(servicePairs, servicePair{name, count})
+	for _, name := range []string{"tcp", "udp"} {
+		if protocols[name].Total() > 0 {
+			stats.Protocols = append(stats.Protocols, *protocols[name])
+		}
 	}
-	sort.Slice(servicePairs, func(i, j int) bool {
-		return servicePairs[i].count > servicePairs[j].count
-	})
 
-	// Take top 5
-	maxServices := 5
-	if len(servicePairs) < maxServices {
-		maxServices = len(servicePairs)
-	}
-	for i := 0; i < maxServices; i++ {
-		stats.TopServices = append(stats.TopServices, ServiceStat{
-			Name:  servicePairs[i].name,
-			Count: servicePairs[i].count,
-		})
-	}
+	stats.TopServices = topCounts(stats.ServiceCounts, 5)
+	stats.TopASNs = topCounts(asnCounts, 5)
+	stats.TopCountries = topCounts(countryCounts, 5)
 
 	// Network stats
 	stats.UniqueHosts = len(hostsMap)
@@ -173,6 +195,43 @@ func (m *ScanUI) GetHostStats() (current int, total int, withOpen int) {
 	return len(uniqueHosts), len(uniqueHosts), len(hostsWithOpen) // current=total for now since we only know what we've scanned
 }
 
+// serviceHostCounts counts the distinct hosts offering each service among
+// results, unknown services included.
+func serviceHostCounts(results []core.ResultEvent) map[string]int {
+	hosts := make(map[string]map[string]bool)
+	for _, r := range results {
+		service := serviceName(r)
+		if hosts[service] == nil {
+			hosts[service] = make(map[string]bool)
+		}
+		hosts[service][r.Host] = true
+	}
+	counts := make(map[string]int, len(hosts))
+	for service, set := range hosts {
+		counts[service] = len(set)
+	}
+	return counts
+}
+
+// topCounts returns the limit largest counts, largest first and ties in
+// name order.
+func topCounts(counts map[string]int, limit int) []ServiceStat {
+	var top []ServiceStat
+	for name, count := range counts {
+		top = append(top, ServiceStat{Name: name, Count: count})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].Name < top[j].Name
+	})
+	if len(top) > limit {
+		top = top[:limit]
+	}
+	return top
+}
+
 // getPercentage calculates percentage
 func getPercentage(part, total int) float64 {
 	if total == 0 {
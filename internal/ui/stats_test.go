@@ -1,10 +1,13 @@
 package ui
 
 import (
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
 )
 
 func TestGetPercentage(t *testing.T) {
@@ -98,26 +101,31 @@ func TestComputeStats_ServiceCounts(t *testing.T) {
 	}
 
 	// Add test results with known services
-	m.results.Append(core.ResultEvent{Host: "host1", Port: 80, State: core.StateOpen})   // HTTP
-	m.results.Append(core.ResultEvent{Host: "host1", Port: 80, State: core.StateOpen})   // HTTP
-	m.results.Append(core.ResultEvent{Host: "host1", Port: 443, State: core.StateOpen})  // HTTPS
-	m.results.Append(core.ResultEvent{Host: "host2", Port: 22, State: core.StateOpen})   // SSH
-	m.results.Append(core.ResultEvent{Host: "host2", Port: 9999, State: core.StateOpen}) // Unknown
+	m.results.Append(core.ResultEvent{Host: "host1", Port: 80, State: core.StateOpen})                   // http
+	m.results.Append(core.ResultEvent{Host: "host1", Port: 80, State: core.StateOpen})                   // http
+	m.results.Append(core.ResultEvent{Host: "host1", Port: 443, State: core.StateOpen})                  // https
+	m.results.Append(core.ResultEvent{Host: "host2", Port: 22, State: core.StateOpen})                   // ssh
+	m.results.Append(core.ResultEvent{Host: "host2", Port: 161, Protocol: "udp", State: core.StateOpen}) // snmp
+	m.results.Append(core.ResultEvent{Host: "host2", Port: 60000, State: core.StateOpen})                // unknown
 
 	stats := m.computeStats()
 
-	if stats.ServiceCounts["HTTP"] != 2 {
-		t.Errorf("expected HTTP count = 2, got %d", stats.ServiceCounts["HTTP"])
+	if stats.ServiceCounts["http"] != 2 {
+		t.Errorf("expected http count = 2, got %d", stats.ServiceCounts["http"])
 	}
-	if stats.ServiceCounts["HTTPS"] != 1 {
-		t.Errorf("expected HTTPS count = 1, got %d", stats.ServiceCounts["HTTPS"])
+	if stats.ServiceCounts["https"] != 1 {
+		t.Errorf("expected https count = 1, got %d", stats.ServiceCounts["https"])
 	}
-	if stats.ServiceCounts["SSH"] != 1 {
-		t.Errorf("expected SSH count = 1, got %d", stats.ServiceCounts["SSH"])
+	if stats.ServiceCounts["ssh"] != 1 {
+		t.Errorf("expected ssh count = 1, got %d", stats.ServiceCounts["ssh"])
+	}
+	// Ports outside the TUI's former 17-entry map are named from pkg/services
+	if stats.ServiceCounts["snmp"] != 1 {
+		t.Errorf("expected snmp count = 1, got %d", stats.ServiceCounts["snmp"])
 	}
 	// Note: Unknown services should be filtered out and not counted
-	if stats.ServiceCounts["Unknown"] != 0 {
-		t.Errorf("expected Unknown count = 0 (filtered out), got %d", stats.ServiceCounts["Unknown"])
+	if stats.ServiceCounts["unknown"] != 0 {
+		t.Errorf("expected unknown count = 0 (filtered out), got %d", stats.ServiceCounts["unknown"])
 	}
 }
 
@@ -132,13 +140,13 @@ func TestComputeStats_TopServices(t *testing.T) {
 
 	// Add test results - HTTP appears most
 	for i := 0; i < 5; i++ {
-		m.results.Append(core.ResultEvent{Host: "host1", Port: 80, State: core.StateOpen}) // HTTP
+		m.results.Append(core.ResultEvent{Host: "host1", Port: 80, State: core.StateOpen}) // http
 	}
 	for i := 0; i < 3; i++ {
-		m.results.Append(core.ResultEvent{Host: "host1", Port: 443, State: core.StateOpen}) // HTTPS
+		m.results.Append(core.ResultEvent{Host: "host1", Port: 443, State: core.StateOpen}) // https
 	}
 	for i := 0; i < 2; i++ {
-		m.results.Append(core.ResultEvent{Host: "host1", Port: 22, State: core.StateOpen}) // SSH
+		m.results.Append(core.ResultEvent{Host: "host1", Port: 22, State: core.StateOpen}) // ssh
 	}
 
 	stats := m.computeStats()
@@ -146,17 +154,40 @@ func TestComputeStats_TopServices(t *testing.T) {
 	if len(stats.TopServices) != 3 {
 		t.Errorf("expected 3 top services, got %d", len(stats.TopServices))
 	}
-	if stats.TopServices[0].Name != "HTTP" {
-		t.Errorf("expected top service to be HTTP, got %s", stats.TopServices[0].Name)
+	if stats.TopServices[0].Name != "http" {
+		t.Errorf("expected top service to be http, got %s", stats.TopServices[0].Name)
 	}
 	if stats.TopServices[0].Count != 5 {
-		t.Errorf("expected HTTP count = 5, got %d", stats.TopServices[0].Count)
+		t.Errorf("expected http count = 5, got %d", stats.TopServices[0].Count)
+	}
+	if stats.TopServices[1].Name != "https" {
+		t.Errorf("expected second service to be https, got %s", stats.TopServices[1].Name)
+	}
+	if stats.TopServices[2].Name != "ssh" {
+		t.Errorf("expected third service to be ssh, got %s", stats.TopServices[2].Name)
 	}
-	if stats.TopServices[1].Name != "HTTPS" {
-		t.Errorf("expected second service to be HTTPS, got %s", stats.TopServices[1].Name)
+}
+
+func TestComputeStats_GeoBreakdown(t *testing.T) {
+	m := &ScanUI{
+		results:       NewResultBuffer(20),
+		progressTrack: &ProgressTracker{},
+	}
+	m.results.Append(core.ResultEvent{Host: "8.8.8.8", Port: 53, State: core.StateOpen, Country: "US", ASN: 15169, ASOrg: "GOOGLE"})
+	m.results.Append(core.ResultEvent{Host: "8.8.8.8", Port: 443, State: core.StateOpen, Country: "US", ASN: 15169, ASOrg: "GOOGLE"})
+	m.results.Append(core.ResultEvent{Host: "1.1.1.1", Port: 53, State: core.StateOpen, Country: "AU", ASN: 13335, ASOrg: "CLOUDFLARENET"})
+	m.results.Append(core.ResultEvent{Host: "1.1.1.1", Port: 22, State: core.StateClosed, Country: "AU", ASN: 13335, ASOrg: "CLOUDFLARENET"})
+	m.results.Append(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen})
+
+	stats := m.computeStats()
+
+	wantASNs := []ServiceStat{{"AS15169 GOOGLE", 2}, {"AS13335 CLOUDFLARENET", 1}}
+	if !slices.Equal(stats.TopASNs, wantASNs) {
+		t.Errorf("TopASNs = %v, want %v", stats.TopASNs, wantASNs)
 	}
-	if stats.TopServices[2].Name != "SSH" {
-		t.Errorf("expected third service to be SSH, got %s", stats.TopServices[2].Name)
+	wantCountries := []ServiceStat{{"US", 2}, {"AU", 1}}
+	if !slices.Equal(stats.TopCountries, wantCountries) {
+		t.Errorf("TopCountries = %v, want %v", stats.TopCountries, wantCountries)
 	}
 }
 
@@ -385,3 +416,28 @@ func TestComputeStats_MixedDurationsWithZeros(t *testing.T) {
 		t.Errorf("expected AvgResponseTime = 15ms, got %v", stats.AvgResponseTime)
 	}
 }
+
+func TestComputeStats_ProtocolBreakdown(t *testing.T) {
+	m := NewScanUI(&config.Config{Protocol: "both"}, 10, make(chan core.Event), false)
+	m.recordResults([]core.ResultEvent{
+		{Host: "10.0.0.1", Port: 22, State: core.StateOpen, Protocol: "tcp"},
+		{Host: "10.0.0.1", Port: 23, State: core.StateClosed},
+		{Host: "10.0.0.1", Port: 53, State: core.StateOpen, Protocol: "udp"},
+		{Host: "10.0.0.1", Port: 161, State: core.StateFiltered, Protocol: "udp"},
+	})
+
+	m.statsData = m.computeStats()
+	want := []ProtocolStat{{Name: "tcp", Open: 1, Closed: 1}, {Name: "udp", Open: 1, Filtered: 1}}
+	if !slices.Equal(m.statsData.Protocols, want) {
+		t.Errorf("Protocols = %+v, want %+v", m.statsData.Protocols, want)
+	}
+	panel := m.renderStatsPanel(80)
+	if !strings.Contains(panel, "TCP: 2 ports • 1 open") || !strings.Contains(panel, "UDP: 2 ports • 1 open") {
+		t.Errorf("stats panel lacks the protocol breakdown:\n%s", panel)
+	}
+
+	m.config.Protocol = "tcp"
+	if strings.Contains(m.renderStatsPanel(80), "TCP vs UDP") {
+		t.Error("the breakdown is only shown when both protocols are scanned")
+	}
+}
@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/exporter"
+)
+
+// placeholderRow stands in for rows outside the visible window. The table
+// only renders rows near the cursor, so these are never drawn.
+var placeholderRow = make(table.Row, len(defaultColumnSpecs))
+
+// cachedRow is a styled table row and the inputs it was rendered from.
+type cachedRow struct {
+	result core.ResultEvent
+	marked bool
+	row    table.Row
+}
+
+// rowCache keeps styled rows keyed by result so unchanged rows are not
+// restyled on every update. It is reset whenever column widths or the
+// search highlight change.
+type rowCache struct {
+	signature string
+	rows      map[string]cachedRow
+}
+
+// reset clears the cache when signature differs from the last render.
+// The cache is also cleared when it grows well past maxEntries, which
+// happens once evicted results leave stale entries behind.
+func (c *rowCache) reset(signature string, maxEntries int) {
+	if c.rows != nil && c.signature == signature && len(c.rows) <= 2*maxEntries {
+		return
+	}
+	c.signature = signature
+	c.rows = make(map[string]cachedRow, maxEntries)
+}
+
+// rowColumns returns the current table columns, falling back to computed
+// widths before the first layout pass.
+func (m *ScanUI) rowColumns() []table.Column {
+	columns := m.table.Columns()
+	if len(columns) != len(defaultColumnSpecs) {
+		columns = calculateColumnWidths(m.tableViewportWidth(), m.layout, m.showGeo)
+	}
+	return columns
+}
+
+// renderSignature captures the inputs shared by every row.
+func (m *ScanUI) renderSignature(columns []table.Column) string {
+	var b strings.Builder
+	for i, col := range columns {
+		fmt.Fprintf(&b, "%d:%d,", m.layout.order[i], col.Width)
+	}
+	b.WriteString(m.theme.Name + ",")
+	b.WriteString(m.filterState.SearchQuery)
+	return b.String()
+}
+
+// styledRow returns the rendered row for r, reusing the cached row when
+// neither the result nor its selection mark has changed.
+func (m *ScanUI) styledRow(r core.ResultEvent, columns []table.Column) table.Row {
+	key := selectionKey(r)
+	marked := m.selection.IsMarked(r)
+	if cached, ok := m.rowCache.rows[key]; ok && sameResult(cached.result, r) && cached.marked == marked {
+		return cached.row
+	}
+	row := m.buildRow(r, marked, columns)
+	m.rowCache.rows[key] = cachedRow{result: r, marked: marked, row: row}
+	return row
+}
+
+// sameResult reports whether two results are identical, tags included.
+func sameResult(a, b core.ResultEvent) bool {
+	return a.Host == b.Host && a.Port == b.Port && a.State == b.State &&
+		a.Banner == b.Banner && a.Duration == b.Duration && a.Protocol == b.Protocol &&
+		a.Verified == b.Verified && a.Hostname == b.Hostname && a.Note == b.Note && a.Edge == b.Edge &&
+		a.DeviceName == b.DeviceName && a.DeviceType == b.DeviceType && a.Owner == b.Owner &&
+		a.Country == b.Country && a.ASN == b.ASN && a.ASOrg == b.ASOrg &&
+		slices.Equal(a.Tags, b.Tags) && slices.Equal(a.Exposures, b.Exposures) && a.TLS == b.TLS && a.HTTPAudit == b.HTTPAudit
+}
+
+// buildRow styles every cell of a result row, in the layout's column
+// order.
+func (m *ScanUI) buildRow(r core.ResultEvent, marked bool, columns []table.Column) table.Row {
+	widths := m.columnWidthsByID(columns)
+
+	rowStyle := m.theme.GetRowStyle(string(r.State))
+	stateDisplay := m.getRowStateDisplay(r, m.theme.GetStateColors())
+
+	protocol := r.Protocol
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	protocol = strings.ToUpper(protocol)
+
+	host := r.Host
+	if marked {
+		host = m.symbols().Text(SelectionMarker) + host
+	}
+
+	cells := make([]string, len(defaultColumnSpecs))
+	cells[hostColumn] = m.renderCell(host, widths[hostColumn], rowStyle)
+	cells[portColumn] = rowStyle.Render(truncateToWidth(fmt.Sprintf("%d", r.Port), widths[portColumn]))
+	cells[protocolColumn] = rowStyle.Render(truncateToWidth(protocol, widths[protocolColumn]))
+	cells[stateColumn] = truncateStyled(stateDisplay, widths[stateColumn])
+	cells[serviceColumn] = m.serviceCell(r, widths[serviceColumn], rowStyle)
+	cells[bannerColumn] = m.renderCell(exporter.InlineBanner(r.Banner), widths[bannerColumn], rowStyle)
+	cells[latencyColumn] = rowStyle.Render(truncateToWidth(fmt.Sprintf("%dms", r.Duration.Milliseconds()), widths[latencyColumn]))
+	cells[geoColumn] = m.renderCell(geoLabel(r), widths[geoColumn], rowStyle)
+	cells[rdnsColumn] = m.renderCell(r.Hostname, widths[rdnsColumn], rowStyle)
+
+	row := make(table.Row, len(m.layout.order))
+	for i, id := range m.layout.order {
+		row[i] = cells[id]
+	}
+	return row
+}
+
+// columnWidthsByID returns the width of every column indexed by column ID
+// rather than by table position.
+func (m *ScanUI) columnWidthsByID(columns []table.Column) []int {
+	widths := make([]int, len(defaultColumnSpecs))
+	for i, id := range m.layout.order {
+		if i < len(columns) {
+			widths[id] = columns[i].Width
+		}
+	}
+	return widths
+}
+
+// serviceCell renders the service column. A banner that identifies a
+// different service than the port's registration shows the detected
+// service in the warning style, so SSH on 8080 stands out.
+func (m *ScanUI) serviceCell(r core.ResultEvent, width int, rowStyle lipgloss.Style) string {
+	detected, _, mismatch := serviceMismatch(r)
+	if !mismatch {
+		return m.renderCell(serviceName(r), width, rowStyle)
+	}
+	warning := rowStyle.Foreground(m.theme.Warning).Bold(true)
+	return m.renderCell(m.symbols().Text("⚠ ")+detected, width, warning)
+}
+
+// rowWindow returns the index range the table may render around cursor.
+// It mirrors the table's own viewport: height rows on either side.
+func (m *ScanUI) rowWindow(cursor int) (start, end int) {
+	height := m.table.Height() + 1
+	start = max(0, cursor-height)
+	end = min(len(m.tableRows), cursor+height+1)
+	return start, end
+}
+
+// fillRowWindow styles the rows around cursor that are still placeholders.
+func (m *ScanUI) fillRowWindow(cursor int) {
+	columns := m.rowColumns()
+	start, end := m.rowWindow(cursor)
+	for i := start; i < end; i++ {
+		if r, ok := m.resultAt(i); ok {
+			m.tableRows[i] = m.styledRow(r, columns)
+		} else {
+			m.tableRows[i] = m.groupHeaderRow(m.grouping.rows[i].service, columns)
+		}
+	}
+	m.renderedCursor = cursor
+}
+
+// refreshRowWindow fills rows around a cursor that moved since the last
+// render and redraws the table.
+func (m *ScanUI) refreshRowWindow() {
+	cursor := m.table.Cursor()
+	if cursor == m.renderedCursor || len(m.tableRows) == 0 {
+		return
+	}
+	m.fillRowWindow(cursor)
+	m.table.SetRows(m.tableRows)
+}
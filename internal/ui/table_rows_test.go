@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+func newLargeTableUI(t *testing.T, count int) *ScanUI {
+	t.Helper()
+	ui := NewScanUI(&config.Config{}, count, make(chan core.Event), false)
+	ui.handleWindowSize(tea.WindowSizeMsg{Width: 120, Height: 30})
+	results := make([]core.ResultEvent, count)
+	for i := range results {
+		results[i] = core.ResultEvent{Host: "10.0.0.1", Port: uint16(i + 1), State: core.StateOpen}
+	}
+	ui.recordResults(results)
+	return ui
+}
+
+func isPlaceholder(row []string) bool {
+	return len(row) > 0 && &row[0] == &placeholderRow[0]
+}
+
+func TestUpdateTableStylesOnlyVisibleWindow(t *testing.T) {
+	ui := newLargeTableUI(t, 1000)
+
+	rows := ui.table.Rows()
+	if len(rows) != 1000 {
+		t.Fatalf("table has %d rows, want 1000", len(rows))
+	}
+	if isPlaceholder(rows[0]) {
+		t.Error("rows at the cursor should be styled")
+	}
+	if !isPlaceholder(rows[999]) {
+		t.Error("rows far from the cursor should stay placeholders")
+	}
+	if len(ui.rowCache.rows) > 2*ui.table.Height()+4 {
+		t.Errorf("styled %d rows, want only the visible window", len(ui.rowCache.rows))
+	}
+}
+
+func TestRefreshRowWindowAfterScrolling(t *testing.T) {
+	ui := newLargeTableUI(t, 1000)
+
+	ui.Update(tea.KeyMsg{Type: tea.KeyEnd})
+
+	rows := ui.table.Rows()
+	if isPlaceholder(rows[999]) {
+		t.Error("rows around the new cursor should be styled after scrolling")
+	}
+}
+
+func TestStyledRowUsesCache(t *testing.T) {
+	ui := newLargeTableUI(t, 10)
+	columns := ui.rowColumns()
+	r := ui.displayResults[0]
+
+	first := ui.styledRow(r, columns)
+	if again := ui.styledRow(r, columns); &again[0] != &first[0] {
+		t.Error("unchanged rows should come from the cache")
+	}
+
+	ui.selection.Toggle(r)
+	if marked := ui.styledRow(r, columns); &marked[0] == &first[0] {
+		t.Error("marking a row should restyle it")
+	}
+}
+
+func TestBuildRowFlagsServiceMismatch(t *testing.T) {
+	ui := newLargeTableUI(t, 1)
+	columns := ui.rowColumns()
+
+	mismatch := core.ResultEvent{Host: "10.0.0.1", Port: 8080, State: core.StateOpen, Banner: "SSH-2.0-OpenSSH_9.6"}
+	if cell := ui.buildRow(mismatch, false, columns)[4]; !strings.Contains(cell, "⚠ ssh") {
+		t.Errorf("service cell = %q; want the detected service flagged", cell)
+	}
+
+	match := core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen, Banner: "SSH-2.0-OpenSSH_9.6"}
+	if cell := ui.buildRow(match, false, columns)[4]; strings.Contains(cell, "⚠") {
+		t.Errorf("service cell = %q; SSH on 22 is not a mismatch", cell)
+	}
+}
+
+func TestListenForResultsBatchesEvents(t *testing.T) {
+	events := make(chan core.Event, 4)
+	ui := NewScanUI(&config.Config{}, 3, events, false)
+
+	events <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen})
+	events <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 23, State: core.StateClosed})
+	events <- core.NewProgressEvent(core.ProgressEvent{Total: 3, Completed: 2})
+	close(events)
+
+	batch, ok := ui.listenForResults()().(scanBatchMsg)
+	if !ok {
+		t.Fatal("expected a scanBatchMsg")
+	}
+	if len(batch.results) != 2 || batch.progress == nil || !batch.complete {
+		t.Fatalf("unexpected batch %+v", batch)
+	}
+
+	ui.Update(batch)
+	if ui.results.Len() != 2 || ui.scanning {
+		t.Errorf("batch not applied: %d results, scanning=%v", ui.results.Len(), ui.scanning)
+	}
+}
@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"github.com/lucchesi-sec/portscan/pkg/theme"
+)
+
+// themeSettingKey is the config key the chosen theme is saved under.
+const themeSettingKey = "ui.theme"
+
+// ThemePickerRows caps how many themes the picker lists at once.
+const ThemePickerRows = 10
+
+// themePicker is the state of the theme modal, which previews each theme
+// on the screen behind it as the cursor reaches it.
+type themePicker struct {
+	names    []string
+	original string // theme in use when the picker opened
+	first    int    // first name drawn when they do not all fit
+}
+
+// openThemeModal lists the registered themes with the cursor on the one in
+// use.
+func (m *ScanUI) openThemeModal() tea.Cmd {
+	m.openModal(ModalTheme)
+	m.themePicker = themePicker{names: theme.Names(), original: m.currentThemeName()}
+	for i, name := range m.themePicker.names {
+		if name == m.themePicker.original {
+			m.modalState.Cursor = i
+		}
+	}
+	return nil
+}
+
+// currentThemeName returns the configured theme, "default" when unset.
+func (m *ScanUI) currentThemeName() string {
+	if m.config.UI.Theme == "" {
+		return theme.Default.Name
+	}
+	return m.config.UI.Theme
+}
+
+// handleThemeModalKey previews the theme under the cursor as it moves and
+// keeps it on Enter. Esc restores the theme the picker opened with.
+func (m *ScanUI) handleThemeModalKey(msg tea.KeyMsg) (bool, bool, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		m.previewTheme(m.modalState.Cursor - 1)
+	case "down", "j":
+		m.previewTheme(m.modalState.Cursor + 1)
+	case "enter":
+		return true, true, m.chooseTheme(m.modalState.Cursor)
+	}
+	return true, true, nil
+}
+
+// previewTheme moves the cursor to the i-th theme and restyles the UI with
+// it without saving it.
+func (m *ScanUI) previewTheme(i int) {
+	if i < 0 || i >= len(m.themePicker.names) {
+		return
+	}
+	m.modalState.Cursor = i
+	m.applyTheme(theme.GetTheme(m.themePicker.names[i]))
+}
+
+// chooseTheme closes the picker keeping the i-th theme.
+func (m *ScanUI) chooseTheme(i int) tea.Cmd {
+	if i < 0 || i >= len(m.themePicker.names) {
+		return nil
+	}
+	name := m.themePicker.names[i]
+	m.themePicker.original = name
+	m.closeModal()
+	return m.useTheme(name)
+}
+
+// restoreTheme undoes the picker's preview when it is dismissed.
+func (m *ScanUI) restoreTheme() {
+	m.applyTheme(theme.GetTheme(m.themePicker.original))
+}
+
+// switchTheme restyles the UI with the named theme, built-in or user.
+func (m *ScanUI) switchTheme(name string) tea.Cmd {
+	name = strings.TrimSpace(name)
+	if !theme.Exists(name) {
+		return m.showToast(i18n.T("ui.theme_switch.unknown", name), true)
+	}
+	return m.useTheme(name)
+}
+
+// useTheme makes the named theme the UI's theme and saves it to the config
+// file, reporting the change in a toast.
+func (m *ScanUI) useTheme(name string) tea.Cmd {
+	m.config.UI.Theme = name
+	m.applyTheme(theme.GetTheme(name))
+	if m.saveSetting != nil {
+		if err := m.saveSetting(themeSettingKey, name); err != nil {
+			return m.showToast(i18n.T("ui.theme_switch.save_failed", err), true)
+		}
+	}
+	return m.showToast(i18n.T("ui.theme_switch.done", name), false)
+}
+
+// applyTheme restyles every component drawn with the current theme and
+// redraws the rows, whose styles are cached. The accessible adjustments
+// are kept when they are configured.
+func (m *ScanUI) applyTheme(t theme.Theme) {
+	if m.config.UI.Accessible {
+		t = theme.Accessible(t)
+	}
+	if m.config.UI.ASCII {
+		t = theme.ASCII(t)
+	}
+	m.theme = t
+	m.table.SetStyles(tableStyles(t))
+	m.spinner.Style = lipgloss.NewStyle().Foreground(t.Primary)
+	m.searchInput.PromptStyle = lipgloss.NewStyle().Foreground(t.Primary).Bold(true)
+	for _, input := range []*textinput.Model{&m.exportState.PathInput, &m.tagInput, &m.noteInput, &m.palette.input,
+		&m.filterForm.host, &m.filterForm.ports, &m.filterForm.service, &m.filterForm.latency, &m.filterForm.banner} {
+		input.PromptStyle = lipgloss.NewStyle().Foreground(t.Secondary).Bold(true)
+	}
+	m.updateTable()
+}
+
+// symbols returns the glyph set the UI draws with.
+func (m *ScanUI) symbols() theme.GlyphSet {
+	return m.theme.GetSymbols()
+}
+
+// visibleThemes returns the slice of theme names the picker draws, keeping
+// the cursor in view.
+func (m *ScanUI) visibleThemes() []string {
+	names := m.themePicker.names
+	cursor := m.modalState.Cursor
+	if cursor < m.themePicker.first {
+		m.themePicker.first = cursor
+	}
+	if cursor >= m.themePicker.first+ThemePickerRows {
+		m.themePicker.first = cursor - ThemePickerRows + 1
+	}
+	end := min(len(names), m.themePicker.first+ThemePickerRows)
+	return names[m.themePicker.first:end]
+}
+
+// renderThemeModal renders the theme list, marking the theme the picker
+// opened with.
+func (m *ScanUI) renderThemeModal() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Primary).
+		Width(30).
+		Render(i18n.T("ui.theme_picker.title"))
+	b.WriteString(title + "\n\n")
+
+	m.modalState.OptionsTop = strings.Count(b.String(), "\n")
+	for i, name := range m.visibleThemes() {
+		index := m.themePicker.first + i
+		mark := "  "
+		if name == m.themePicker.original {
+			mark = m.symbols().Text("● ")
+		}
+		style := lipgloss.NewStyle()
+		if index == m.modalState.Cursor {
+			style = style.Background(m.theme.Primary).Foreground(m.theme.Background)
+		}
+		b.WriteString(style.Render(mark+name) + "\n")
+	}
+
+	if m.saveSetting == nil {
+		note := lipgloss.NewStyle().
+			Foreground(m.theme.Secondary).
+			Render(i18n.T("ui.theme_picker.not_saved"))
+		b.WriteString("\n" + note + "\n")
+	}
+
+	instructions := lipgloss.NewStyle().
+		Foreground(m.theme.Muted).
+		Render(i18n.T("ui.theme_picker.keys"))
+	b.WriteString("\n" + instructions)
+
+	return b.String()
+}
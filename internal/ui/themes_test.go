@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/config"
+)
+
+func TestThemePickerPreviewsAndSaves(t *testing.T) {
+	ui := NewScanUI(&config.Config{}, 1, make(chan core.Event), false)
+	saved := map[string]interface{}{}
+	ui.SetSettingsSaver(func(key string, value interface{}) error {
+		saved[key] = value
+		return nil
+	})
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	if !ui.modalState.IsActive || ui.modalState.Type != ModalTheme {
+		t.Fatal("T should open the theme picker")
+	}
+	if got := ui.themePicker.names[ui.modalState.Cursor]; got != "default" {
+		t.Fatalf("cursor starts on %q; want default", got)
+	}
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyDown})
+	previewed := ui.themePicker.names[ui.modalState.Cursor]
+	if ui.theme.Name != previewed {
+		t.Errorf("theme = %q; moving the cursor should preview %q", ui.theme.Name, previewed)
+	}
+	if len(saved) != 0 {
+		t.Error("a preview should not be saved")
+	}
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	if ui.modalState.IsActive || ui.config.UI.Theme != previewed || saved["ui.theme"] != previewed {
+		t.Errorf("Enter should keep and save %q; config %q, saved %v", previewed, ui.config.UI.Theme, saved)
+	}
+}
+
+func TestThemePickerEscRestores(t *testing.T) {
+	ui := NewScanUI(&config.Config{UI: config.UIConfig{Theme: "nord"}}, 1, make(chan core.Event), false)
+	ui.openThemeModal()
+
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyUp})
+	if ui.theme.Name == "nord" {
+		t.Fatal("moving the cursor should preview another theme")
+	}
+	ui.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEsc})
+	if ui.theme.Name != "nord" || ui.config.UI.Theme != "nord" {
+		t.Errorf("Esc should restore nord; theme %q, config %q", ui.theme.Name, ui.config.UI.Theme)
+	}
+}
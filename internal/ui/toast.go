@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// toastExpiredMsg clears the footer notification with the matching id.
+type toastExpiredMsg struct {
+	id int
+}
+
+// showToast displays a transient message in the footer and schedules its
+// removal. A newer toast replaces an older one; stale expiry ticks are ignored.
+func (m *ScanUI) showToast(message string, isError bool) tea.Cmd {
+	m.toastID++
+	m.toast = message
+	m.toastIsError = isError
+
+	id := m.toastID
+	return tea.Tick(ToastDuration, func(time.Time) tea.Msg {
+		return toastExpiredMsg{id: id}
+	})
+}
+
+// handleToastExpired hides the current toast if it has not been replaced.
+func (m *ScanUI) handleToastExpired(msg toastExpiredMsg) {
+	if msg.id == m.toastID {
+		m.toast = ""
+		m.toastIsError = false
+	}
+}
+
+// renderToast renders the active notification for the footer.
+func (m *ScanUI) renderToast() string {
+	color := m.theme.Success
+	if m.toastIsError {
+		color = m.theme.Danger
+	}
+	return lipgloss.NewStyle().Foreground(color).Bold(true).Render(m.toast)
+}
@@ -1,9 +1,22 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/lucchesi-sec/portscan/pkg/parser"
+	"github.com/lucchesi-sec/portscan/pkg/targets"
+	"github.com/lucchesi-sec/portscan/pkg/theme"
+	"github.com/lucchesi-sec/portscan/pkg/upload"
 	"github.com/spf13/viper"
 )
 
@@ -12,18 +25,126 @@ type Config struct {
 	Rate           int      `mapstructure:"rate" validate:"min=1,max=15000"`
 	Ports          string   `mapstructure:"ports"`
 	TimeoutMs      int      `mapstructure:"timeout_ms" validate:"min=1,max=60000"`
-	Workers        int      `mapstructure:"workers" validate:"min=0,max=1000"` // 0 means auto-detect
-	Output         string   `mapstructure:"output" validate:"omitempty,oneof=json csv prometheus table"`
+	PortTimeouts   string   `mapstructure:"port_timeouts"`                              // per-port overrides in ms, e.g. "443=1000,3306=500"
+	FromMasscan    string   `mapstructure:"from_masscan"`                               // masscan results whose open ports are scanned instead of targets
+	Jobs           []string `mapstructure:"jobs"`                                       // NAME:TARGETS_FILE[:key=value...] jobs sharing one rate budget
+	TargetsCSV     string   `mapstructure:"targets_csv"`                                // CSV or JSON asset inventory to read targets from
+	HostField      string   `mapstructure:"host_field"`                                 // asset field holding each target
+	TagFields      string   `mapstructure:"tag_fields"`                                 // comma-separated asset fields attached to a host's results as tags
+	ASN            string   `mapstructure:"asn"`                                        // comma-separated AS numbers whose announced prefixes are scanned
+	ASNData        string   `mapstructure:"asn_data"`                                   // prefix-to-AS dataset, e.g. a RouteViews pfx2as file
+	Workers        int      `mapstructure:"workers" validate:"min=0,max=1000"`          // 0 means auto-detect
+	RaiseFDLimit   bool     `mapstructure:"raise_fd_limit"`                             // raise the soft open file limit to fit the workers
+	RunAs          string   `mapstructure:"run_as"`                                     // USER[:GROUP] to switch to after privileged setup
+	Scope          string   `mapstructure:"scope"`                                      // allowlist file every expanded target must match
+	ScopeKey       string   `mapstructure:"scope_key"`                                  // Ed25519 public key the scope file must be signed with
+	AllowLocalhost bool     `mapstructure:"allow_localhost"`                            // permit loopback targets and localhost
+	AllowPrivate   bool     `mapstructure:"allow_private"`                              // permit private and link-local targets
+	ExcludeRDNS    string   `mapstructure:"exclude_rdns"`                               // comma-separated globs; skip targets whose PTR names match
+	ExcludeTags    string   `mapstructure:"exclude_tags"`                               // comma-separated key=value globs; skip targets with a matching tag
+	Timing         string   `mapstructure:"timing"`                                     // timing template, T0-T5 or its name
+	Retries        int      `mapstructure:"retries" validate:"min=0,max=10"`            // 0 uses the scanner default
+	JitterMs       int      `mapstructure:"jitter_ms" validate:"min=0,max=60000"`       // random delay before each probe
+	PerHostLimit   int      `mapstructure:"host_parallelism" validate:"min=0,max=1000"` // concurrent probes per host; 0 is unlimited
+	MaxBandwidth   string   `mapstructure:"max_bandwidth"`                              // pause while the host sends more bits/s than this, e.g. "100Mbit"
+	MaxPPS         int      `mapstructure:"max_pps" validate:"min=0"`                   // pause while the host sends more packets/s than this
+	BandwidthIface string   `mapstructure:"bandwidth_iface"`                            // interface the bandwidth guard watches; empty sums all but loopback
+	ConntrackLimit int      `mapstructure:"conntrack_limit" validate:"min=0,max=100"`   // pause while the conntrack table is this % full (Linux); 0 disables
+	DNSServer      string   `mapstructure:"dns_server"`                                 // host[:port], tls://host[:port] or https:// URL; empty uses the system resolver
+	DNSTimeoutMs   int      `mapstructure:"dns_timeout_ms" validate:"min=0,max=60000"`  // per-lookup timeout; 0 uses the default
+	AllIPs         bool     `mapstructure:"all_ips"`                                    // scan every address a hostname resolves to
+	Tags           []string `mapstructure:"tags"`                                       // key=value labels attached to every result
+	Output         string   `mapstructure:"output" validate:"omitempty,oneof=json csv html prometheus table elastic syslog kafka nats inventory"`
+	OutputFile     string   `mapstructure:"output_file"`                                                // write exports here instead of stdout
+	TimelineFile   string   `mapstructure:"timeline_file"`                                              // write the scan's lifecycle timeline here as JSON
+	Record         string   `mapstructure:"record"`                                                     // record the event stream here for portscan replay
+	RecordSession  string   `mapstructure:"record_session"`                                             // record the TUI session here as an asciinema cast
+	OutputMaxSize  string   `mapstructure:"output_max_size"`                                            // rotate NDJSON output files at this size, e.g. "100MB"
+	OutputKeep     int      `mapstructure:"output_keep" validate:"min=0,max=1000"`                      // rotated files to keep; 0 uses the default
+	Compress       string   `mapstructure:"compress" validate:"omitempty,oneof=none gzip zstd"`         // stream compression for exports
+	ElasticURL     string   `mapstructure:"elastic_url"`                                                // Elasticsearch/OpenSearch cluster for --output elastic
+	ElasticIndex   string   `mapstructure:"elastic_index"`                                              // index name, may contain %{+yyyy.MM.dd}
+	ElasticAPIKey  string   `mapstructure:"elastic_api_key"`                                            // API key; prefer PORTSCAN_ELASTIC_API_KEY
+	ElasticBatch   int      `mapstructure:"elastic_batch_size" validate:"min=0,max=100000"`             // documents per bulk request; 0 uses the default
+	SyslogAddr     string   `mapstructure:"syslog_addr"`                                                // udp://, tcp:// or tls://host:port; empty writes to stdout
+	SyslogFormat   string   `mapstructure:"syslog_format" validate:"omitempty,oneof=rfc5424 cef"`       // syslog message format
+	InventoryFmt   string   `mapstructure:"inventory_format" validate:"omitempty,oneof=json csv"`       // document format for --output inventory
+	Brokers        string   `mapstructure:"brokers"`                                                    // comma-separated Kafka brokers or NATS servers
+	Topic          string   `mapstructure:"topic"`                                                      // Kafka topic or NATS subject
+	MessageKey     string   `mapstructure:"message_key" validate:"omitempty,oneof=host host_port none"` // key for published results
+	Partitioner    string   `mapstructure:"partitioner" validate:"omitempty,oneof=hash round-robin"`    // Kafka partitioning
+	BrokerTLS      bool     `mapstructure:"broker_tls"`                                                 // TLS to the brokers, implied by broker_ca/broker_cert
+	BrokerCA       string   `mapstructure:"broker_ca"`                                                  // PEM CA bundle for broker certificates
+	BrokerCert     string   `mapstructure:"broker_cert"`                                                // PEM client certificate for the brokers
+	BrokerKey      string   `mapstructure:"broker_key"`                                                 // PEM key for broker_cert
+	BrokerSASL     string   `mapstructure:"broker_sasl"`                                                // Kafka SASL mechanism: plain, scram-sha-256, scram-sha-512
+	BrokerUser     string   `mapstructure:"broker_user"`                                                // Kafka SASL or NATS user
+	BrokerPassword string   `mapstructure:"broker_password"`                                            // password for broker_user; prefer PORTSCAN_BROKER_PASSWORD
+	NATSCreds      string   `mapstructure:"nats_creds"`                                                 // NATS credentials file (user JWT and nkey seed)
+	Upload         string   `mapstructure:"upload"`                                                     // s3:// or gs:// object key template for the final export
+	UploadEndpoint string   `mapstructure:"upload_endpoint"`                                            // S3-compatible endpoint, e.g. MinIO
+	UploadRegion   string   `mapstructure:"upload_region"`                                              // S3 region; defaults to the bucket's
+	OTelEndpoint   string   `mapstructure:"otel_endpoint"`                                              // OTLP/HTTP collector for traces and metrics
 	Banners        bool     `mapstructure:"banners"`
-	Protocol       string   `mapstructure:"protocol" validate:"omitempty,oneof=tcp udp both"` // Scan protocol
-	UDPWorkerRatio float64  `mapstructure:"udp_worker_ratio" validate:"min=-1.0,max=1.0"`     // Ratio of workers for UDP (-1=default, 0=disable, 0.1-1.0=ratio)
+	BannerMaxBytes int      `mapstructure:"banner_max_bytes" validate:"min=0,max=65536"`            // 0 uses the scanner default
+	BannerTimeout  int      `mapstructure:"banner_timeout_ms" validate:"min=0,max=60000"`           // 0 uses the scanner default
+	BannerEncoding string   `mapstructure:"banner_encoding" validate:"omitempty,oneof=text base64"` // JSON banner encoding
+	BannerWorkers  int      `mapstructure:"banner_workers" validate:"min=0,max=10000"`              // concurrent banner reads; 0 uses the scanner default
+	VerifyOpen     bool     `mapstructure:"verify_open"`                                            // re-connect to open ports before reporting
+	DetectEdge     bool     `mapstructure:"detect_edge"`                                            // identify CDN/WAF front ends on open web ports
+	LocalDiscovery bool     `mapstructure:"local_discovery"`                                        // name private hosts via mDNS, NetBIOS and SSDP
+	CheckNTP       bool     `mapstructure:"check_ntp"`                                              // flag NTP servers answering mode 6/7 queries
+	TLSInspect     bool     `mapstructure:"tls_inspect"`                                            // record TLS versions and certificates, STARTTLS included
+	HTTPAudit      bool     `mapstructure:"http_audit"`                                             // grade security headers on open web ports
+	OwnerLookup    bool     `mapstructure:"owner_lookup"`                                           // record who holds public hosts' networks, via RDAP
+	OwnerOffline   bool     `mapstructure:"owner_offline"`                                          // answer owner lookups from the cache alone
+	OwnerCache     string   `mapstructure:"owner_cache"`                                            // RDAP answer cache; empty uses the user cache directory
+	GeoIPDB        string   `mapstructure:"geoip_db"`                                               // comma-separated MaxMind DB files giving hosts' country and ASN
+	FailOn         string   `mapstructure:"fail_on"`                                                // open ports that fail the scan: a port list or "any"
+	Strict         bool     `mapstructure:"strict"`                                                 // any failed probe fails the scan
+	ScanWindow     string   `mapstructure:"scan_window"`                                            // daily local-time window, e.g. "22:00-06:00"
+	Spread         bool     `mapstructure:"spread"`                                                 // pace the scan across the window
+	Protocol       string   `mapstructure:"protocol" validate:"omitempty,oneof=tcp udp both"`       // Scan protocol
+	UDPWorkerRatio float64  `mapstructure:"udp_worker_ratio" validate:"min=-1.0,max=1.0"`           // Ratio of workers for UDP (-1=default, 0=disable, 0.1-1.0=ratio)
+	UDPProbes      string   `mapstructure:"udp_probes"`                                             // UDP probe packs: comma-separated YAML files or "extended"
+	SNMPCommunity  string   `mapstructure:"snmp_communities"`                                       // comma-separated SNMP communities tried on UDP 161
 	UI             UIConfig `mapstructure:"ui"`
+
+	// Overrides give targets matching a network or domain their own
+	// settings; the first matching override applies.
+	Overrides []TargetOverride `mapstructure:"overrides" validate:"dive"`
+
+	// HostTags holds the key=value labels --targets-csv gives each host,
+	// attached to that host's results. It is set at run time, not loaded.
+	HostTags map[string][]string `mapstructure:"-"`
 }
 
 // UIConfig holds UI-specific configuration options.
 type UIConfig struct {
-	Theme            string `mapstructure:"theme" validate:"oneof=default dracula monokai"`
-	ResultBufferSize int    `mapstructure:"result_buffer_size" validate:"gte=0,lte=1000000"`
+	Theme            string   `mapstructure:"theme" validate:"theme"` // built-in or user theme name
+	ResultBufferSize int      `mapstructure:"result_buffer_size" validate:"gte=0,lte=1000000"`
+	Accessible       bool     `mapstructure:"accessible"`                                                                             // colorblind-safe state colors and glyphs
+	ScreenReader     bool     `mapstructure:"screen_reader"`                                                                          // announce results as plain lines instead of drawing the TUI
+	ASCII            bool     `mapstructure:"ascii"`                                                                                  // draw with ASCII instead of emoji and block glyphs
+	SpillOverflow    bool     `mapstructure:"spill_overflow"`                                                                         // write evicted results to a temp file for export
+	Columns          []string `mapstructure:"columns" validate:"dive,oneof=host port protocol state service banner latency geo rdns"` // visible table columns, in order; empty uses the default layout
+
+	// Presets are saved table views, applied by name from the TUI's
+	// command palette.
+	Presets map[string]FilterPreset `mapstructure:"presets" validate:"dive"`
+}
+
+// FilterPreset is a saved view of the TUI's results table: the filters and
+// sort order applied together. Empty fields do not filter. The yaml tags
+// name the fields when the TUI saves a preset.
+type FilterPreset struct {
+	Host       string `mapstructure:"host" yaml:"host,omitempty"`                                                                                             // host address or name contains
+	Ports      string `mapstructure:"ports" yaml:"ports,omitempty"`                                                                                           // a port or range, e.g. "80-443"
+	Service    string `mapstructure:"service" yaml:"service,omitempty"`                                                                                       // service name contains
+	State      string `mapstructure:"state" yaml:"state,omitempty" validate:"omitempty,oneof=open closed filtered"`                                           // port state
+	MaxLatency int    `mapstructure:"max_latency_ms" yaml:"max_latency_ms,omitempty" validate:"min=0"`                                                        // slowest response shown
+	Banner     string `mapstructure:"banner" yaml:"banner,omitempty"`                                                                                         // banner contains
+	Sort       string `mapstructure:"sort" yaml:"sort,omitempty" validate:"omitempty,oneof=port port-desc host state service latency latency-desc discovery"` // row order
 }
 
 // Load reads configuration from Viper and validates it.
@@ -35,19 +156,80 @@ func Load() (*Config, error) {
 	viper.SetDefault("rate", 7500)
 	viper.SetDefault("ports", "1-1024,3306,6379")
 	viper.SetDefault("timeout_ms", 200)
+	viper.SetDefault("port_timeouts", "")
+	viper.SetDefault("targets_csv", "")
+	viper.SetDefault("host_field", "ip")
+	viper.SetDefault("tag_fields", "")
+	viper.SetDefault("asn", "")
+	viper.SetDefault("asn_data", "")
 	viper.SetDefault("workers", 100)
+	viper.SetDefault("raise_fd_limit", false)
+	viper.SetDefault("run_as", "")
+	viper.SetDefault("scope", "")
+	viper.SetDefault("scope_key", "")
+	viper.SetDefault("allow_localhost", true)
+	viper.SetDefault("allow_private", true)
+	viper.SetDefault("exclude_rdns", "")
+	viper.SetDefault("exclude_tags", "")
 	viper.SetDefault("output", "")
+	viper.SetDefault("output_file", "")
+	viper.SetDefault("timeline_file", "")
+	viper.SetDefault("record", "")
+	viper.SetDefault("otel_endpoint", "")
+	viper.SetDefault("output_max_size", "")
+	viper.SetDefault("output_keep", 0)
+	viper.SetDefault("compress", "")
+	viper.SetDefault("elastic_url", "")
+	viper.SetDefault("elastic_index", "portscan-%{+yyyy.MM.dd}") // matches exporter.DefaultElasticIndex
+	viper.SetDefault("elastic_api_key", "")
+	viper.SetDefault("elastic_batch_size", 0)
+	viper.SetDefault("syslog_addr", "")
+	viper.SetDefault("syslog_format", "rfc5424")
+	viper.SetDefault("brokers", "")
+	viper.SetDefault("snmp_communities", "")
+	viper.SetDefault("topic", "portscan.results")
+	viper.SetDefault("message_key", "host")
+	viper.SetDefault("partitioner", "hash")
 	viper.SetDefault("banners", false)
+	viper.SetDefault("banner_max_bytes", 512)
+	viper.SetDefault("banner_timeout_ms", 1000)
+	viper.SetDefault("banner_encoding", "text")
+	viper.SetDefault("banner_workers", 50)
+	viper.SetDefault("verify_open", false)
+	viper.SetDefault("detect_edge", false)
+	viper.SetDefault("local_discovery", false)
+	viper.SetDefault("check_ntp", false)
+	viper.SetDefault("tls_inspect", false)
+	viper.SetDefault("http_audit", false)
+	viper.SetDefault("owner_lookup", false)
+	viper.SetDefault("owner_offline", false)
+	viper.SetDefault("owner_cache", "")
+	viper.SetDefault("geoip_db", "")
+	viper.SetDefault("scan_window", "")
+	viper.SetDefault("spread", false)
+	viper.SetDefault("timing", "")
+	viper.SetDefault("retries", 0)
+	viper.SetDefault("jitter_ms", 0)
+	viper.SetDefault("host_parallelism", 0)
+	viper.SetDefault("max_bandwidth", "")
+	viper.SetDefault("max_pps", 0)
+	viper.SetDefault("bandwidth_iface", "")
+	viper.SetDefault("conntrack_limit", 90)
 	viper.SetDefault("protocol", "tcp")
 	viper.SetDefault("udp_worker_ratio", -1.0) // -1 means use default behavior (half of TCP workers)
+	viper.SetDefault("udp_probes", "")
 	viper.SetDefault("ui.theme", "default")
 	viper.SetDefault("ui.result_buffer_size", 10000)
+	viper.SetDefault("ui.accessible", false)
+	viper.SetDefault("ui.screen_reader", false)
+	viper.SetDefault("ui.ascii", false)
+	viper.SetDefault("ui.spill_overflow", false)
 
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return nil, err
 	}
 
-	validate := validator.New()
+	validate := NewValidator()
 	if err := validate.Struct(&cfg); err != nil {
 		return nil, err
 	}
@@ -55,7 +237,543 @@ func Load() (*Config, error) {
 	return &cfg, nil
 }
 
+// NewValidator returns a validator with the custom rules used by Config,
+// such as "theme", which accepts built-in and user-defined theme names.
+func NewValidator() *validator.Validate {
+	validate := validator.New()
+	_ = validate.RegisterValidation("theme", func(fl validator.FieldLevel) bool {
+		return theme.Exists(fl.Field().String())
+	})
+	return validate
+}
+
 // GetTimeout returns the timeout as a time.Duration.
 func (c *Config) GetTimeout() time.Duration {
 	return time.Duration(c.TimeoutMs) * time.Millisecond
 }
+
+// GetPortTimeouts returns the per-port timeout overrides. It returns nil
+// when none are set or the spec is invalid; ValidatePortTimeouts reports
+// the parse error.
+func (c *Config) GetPortTimeouts() map[uint16]time.Duration {
+	timeouts, err := parser.ParsePortTimeouts(c.PortTimeouts)
+	if err != nil {
+		return nil
+	}
+	return timeouts
+}
+
+// ValidatePortTimeouts checks the per-port timeout override spec.
+func (c *Config) ValidatePortTimeouts() error {
+	_, err := parser.ParsePortTimeouts(c.PortTimeouts)
+	return err
+}
+
+// GetOutputMaxBytes returns the output rotation size in bytes, or 0 when
+// rotation is off or the size is invalid; ValidateOutputMaxSize reports the
+// parse error.
+func (c *Config) GetOutputMaxBytes() int64 {
+	size, err := parser.ParseByteSize(c.OutputMaxSize)
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+// ValidateOutputMaxSize checks the output rotation size and that there is
+// an output file to rotate.
+func (c *Config) ValidateOutputMaxSize() error {
+	if c.OutputMaxSize == "" {
+		return nil
+	}
+	if c.OutputFile == "" {
+		return errors.New("output rotation requires an output file")
+	}
+	if c.Output == "inventory" {
+		return errors.New("an inventory is written as one document and cannot be rotated")
+	}
+	_, err := parser.ParseByteSize(c.OutputMaxSize)
+	return err
+}
+
+// GetScanWindow returns the configured scan window, or nil when scanning
+// is unrestricted or the spec is invalid; ValidateScanWindow reports the
+// parse error.
+func (c *Config) GetScanWindow() *parser.ScanWindow {
+	if c.ScanWindow == "" {
+		return nil
+	}
+	window, err := parser.ParseScanWindow(c.ScanWindow)
+	if err != nil {
+		return nil
+	}
+	return &window
+}
+
+// ValidateScanWindow checks the scan window spec and that spreading has a
+// window to spread across.
+func (c *Config) ValidateScanWindow() error {
+	if c.ScanWindow == "" {
+		if c.Spread {
+			return errors.New("spread requires a scan window")
+		}
+		return nil
+	}
+	_, err := parser.ParseScanWindow(c.ScanWindow)
+	return err
+}
+
+// ValidateElastic checks that Elasticsearch output has a cluster URL.
+func (c *Config) ValidateElastic() error {
+	if c.Output != "elastic" {
+		return nil
+	}
+	if c.ElasticURL == "" {
+		return errors.New("elastic output requires an elasticsearch URL")
+	}
+	u, err := url.Parse(c.ElasticURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid elasticsearch URL %q: want http(s)://host:port", c.ElasticURL)
+	}
+	return nil
+}
+
+// ValidateSyslog checks the syslog collector address used by syslog output.
+func (c *Config) ValidateSyslog() error {
+	if c.Output != "syslog" || c.SyslogAddr == "" {
+		return nil
+	}
+	u, err := url.Parse(c.SyslogAddr)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid syslog address %q: want udp://, tcp:// or tls://host:port", c.SyslogAddr)
+	}
+	switch u.Scheme {
+	case "udp", "tcp", "tls":
+		return nil
+	}
+	return fmt.Errorf("unsupported syslog transport %q: use udp, tcp or tls", u.Scheme)
+}
+
+// GetBrokers returns the broker addresses for Kafka or NATS output.
+func (c *Config) GetBrokers() []string {
+	var brokers []string
+	for _, b := range strings.Split(c.Brokers, ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			brokers = append(brokers, b)
+		}
+	}
+	return brokers
+}
+
+// GetSNMPCommunities returns the community strings to try on UDP 161.
+func (c *Config) GetSNMPCommunities() []string {
+	var communities []string
+	for _, community := range strings.Split(c.SNMPCommunity, ",") {
+		if community = strings.TrimSpace(community); community != "" {
+			communities = append(communities, community)
+		}
+	}
+	return communities
+}
+
+// ValidatePublish checks that Kafka or NATS output has brokers, a topic
+// to publish to, and consistent TLS and authentication settings.
+func (c *Config) ValidatePublish() error {
+	if c.Output != "kafka" && c.Output != "nats" {
+		return nil
+	}
+	if len(c.GetBrokers()) == 0 {
+		return fmt.Errorf("%s output requires at least one broker", c.Output)
+	}
+	if c.Topic == "" {
+		return fmt.Errorf("%s output requires a topic", c.Output)
+	}
+	if c.Output == "nats" && strings.ContainsAny(c.Topic, " \t*>") {
+		return fmt.Errorf("invalid NATS subject %q: wildcards and spaces are not allowed", c.Topic)
+	}
+	if (c.BrokerCert == "") != (c.BrokerKey == "") {
+		return errors.New("--broker-cert and --broker-key must be given together")
+	}
+	if c.BrokerSASL != "" {
+		if c.Output != "kafka" {
+			return errors.New("--broker-sasl applies to kafka output only; NATS takes --broker-user or --nats-creds")
+		}
+		switch c.BrokerSASL {
+		case "plain", "scram-sha-256", "scram-sha-512":
+		default:
+			return fmt.Errorf("unsupported SASL mechanism %q: use plain, scram-sha-256 or scram-sha-512", c.BrokerSASL)
+		}
+		if c.BrokerUser == "" {
+			return fmt.Errorf("--broker-sasl %s requires --broker-user", c.BrokerSASL)
+		}
+	}
+	if c.NATSCreds != "" && c.Output != "nats" {
+		return errors.New("--nats-creds applies to nats output only")
+	}
+	_, err := c.GetBrokerTLS()
+	return err
+}
+
+// GetBrokerTLS returns the TLS configuration for Kafka or NATS output, or
+// nil when TLS is off. A CA bundle or client certificate turns TLS on.
+func (c *Config) GetBrokerTLS() (*tls.Config, error) {
+	if !c.BrokerTLS && c.BrokerCA == "" && c.BrokerCert == "" {
+		return nil, nil
+	}
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.BrokerCA != "" {
+		pem, err := os.ReadFile(c.BrokerCA)
+		if err != nil {
+			return nil, fmt.Errorf("reading --broker-ca: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("--broker-ca %s holds no PEM certificates", c.BrokerCA)
+		}
+	}
+	if c.BrokerCert != "" {
+		cert, err := tls.LoadX509KeyPair(c.BrokerCert, c.BrokerKey)
+		if err != nil {
+			return nil, fmt.Errorf("loading --broker-cert: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// GetExclusion returns the --exclude-rdns and --exclude-tag patterns, or
+// nil when neither is set.
+func (c *Config) GetExclusion() (*targets.Exclusion, error) {
+	return targets.ParseExclusion(strings.Split(c.ExcludeRDNS, ","), strings.Split(c.ExcludeTags, ","))
+}
+
+// ValidateExclusion checks the --exclude-rdns and --exclude-tag patterns.
+func (c *Config) ValidateExclusion() error {
+	_, err := c.GetExclusion()
+	return err
+}
+
+// GetMaxBandwidth returns the outbound bandwidth ceiling in bits per
+// second, or 0 when none is set or it is invalid; ValidateBandwidthGuard
+// reports the parse error.
+func (c *Config) GetMaxBandwidth() int64 {
+	bits, err := parser.ParseBitRate(c.MaxBandwidth)
+	if err != nil {
+		return 0
+	}
+	return bits
+}
+
+// BandwidthGuarded reports whether --max-bandwidth or --max-pps is set.
+func (c *Config) BandwidthGuarded() bool {
+	return c.GetMaxBandwidth() > 0 || c.MaxPPS > 0
+}
+
+// ValidateBandwidthGuard checks the outbound bandwidth ceiling and that
+// --bandwidth-iface comes with a ceiling to enforce.
+func (c *Config) ValidateBandwidthGuard() error {
+	if _, err := parser.ParseBitRate(c.MaxBandwidth); err != nil {
+		return err
+	}
+	if c.BandwidthIface != "" && !c.BandwidthGuarded() {
+		return errors.New("--bandwidth-iface needs --max-bandwidth or --max-pps")
+	}
+	return nil
+}
+
+// OwnerLookups reports whether --owner-lookup or --owner-offline is set.
+func (c *Config) OwnerLookups() bool {
+	return c.OwnerLookup || c.OwnerOffline
+}
+
+// GetOwnerCache returns the file RDAP answers are cached in: --owner-cache,
+// or rdap.json in the user cache directory. It returns "" when there is no
+// cache directory, and lookups then go uncached.
+func (c *Config) GetOwnerCache() string {
+	if c.OwnerCache != "" {
+		return c.OwnerCache
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "portscan", "rdap.json")
+}
+
+// RecordsSession reports whether --record-session asks for the TUI session
+// to be recorded as an asciinema cast.
+func (c *Config) RecordsSession() bool {
+	return c.RecordSession != ""
+}
+
+// GetGeoIPDBs returns the --geoip-db files, or nil when none is set.
+func (c *Config) GetGeoIPDBs() []string {
+	var paths []string
+	for _, path := range strings.Split(c.GeoIPDB, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// GetDNSTimeout returns the per-lookup DNS timeout, or zero for the
+// resolver default.
+func (c *Config) GetDNSTimeout() time.Duration {
+	return time.Duration(c.DNSTimeoutMs) * time.Millisecond
+}
+
+// ValidateDNSServer checks the --dns-server resolver address.
+func (c *Config) ValidateDNSServer() error {
+	return targets.ValidateDNSServer(c.DNSServer)
+}
+
+// maxTagLength bounds a key=value tag.
+const maxTagLength = 128
+
+// GetTags returns the scan tags with surrounding whitespace removed.
+func (c *Config) GetTags() []string {
+	if len(c.Tags) == 0 {
+		return nil
+	}
+	tags := make([]string, 0, len(c.Tags))
+	for _, tag := range c.Tags {
+		tags = append(tags, strings.TrimSpace(tag))
+	}
+	return tags
+}
+
+// ValidateTags checks the --tag labels: each must be key=value with a key
+// of letters, digits, '.', '_' or '-', and no key may repeat.
+func (c *Config) ValidateTags() error {
+	keys := make(map[string]bool, len(c.Tags))
+	for _, tag := range c.GetTags() {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok || key == "" || strings.TrimSpace(value) == "" {
+			return fmt.Errorf("invalid tag %q: want key=value", tag)
+		}
+		if len(tag) > maxTagLength {
+			return fmt.Errorf("tag %q is longer than %d characters", tag, maxTagLength)
+		}
+		if !validTagKey(key) {
+			return fmt.Errorf("invalid tag key %q: use letters, digits, '.', '_' or '-'", key)
+		}
+		if keys[key] {
+			return fmt.Errorf("duplicate tag key %q", key)
+		}
+		keys[key] = true
+	}
+	return nil
+}
+
+// FailOnAny matches every open port in --fail-on.
+const FailOnAny = "any"
+
+// GetFailOnPorts returns the ports whose being open violates the scan
+// policy, and whether any open port does. Both are empty when no policy is
+// set or the spec is invalid; ValidateFailOn reports the parse error.
+func (c *Config) GetFailOnPorts() (map[uint16]bool, bool) {
+	if strings.EqualFold(strings.TrimSpace(c.FailOn), FailOnAny) {
+		return nil, true
+	}
+	if c.FailOn == "" {
+		return nil, false
+	}
+	ports, err := parser.ParsePorts(c.FailOn)
+	if err != nil {
+		return nil, false
+	}
+	set := make(map[uint16]bool, len(ports))
+	for _, p := range ports {
+		set[p] = true
+	}
+	return set, false
+}
+
+// ValidateFailOn checks the --fail-on port policy.
+func (c *Config) ValidateFailOn() error {
+	if c.FailOn == "" || strings.EqualFold(strings.TrimSpace(c.FailOn), FailOnAny) {
+		return nil
+	}
+	if _, err := parser.ParsePorts(c.FailOn); err != nil {
+		return fmt.Errorf("invalid --fail-on ports %q: %w", c.FailOn, err)
+	}
+	return nil
+}
+
+// GetJobs parses the configured scan jobs, or returns nil when there are
+// none.
+func (c *Config) GetJobs() ([]parser.JobSpec, error) {
+	var jobs []parser.JobSpec
+	for _, spec := range c.Jobs {
+		job, err := parser.ParseJob(spec)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func validTagKey(key string) bool {
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return key != ""
+}
+
+// GetTagFields returns the --tag-fields names, trimmed, without empties.
+func (c *Config) GetTagFields() []string {
+	var fields []string
+	for _, field := range strings.Split(c.TagFields, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// ValidateTargetsCSV checks the asset file options: a host field is set,
+// tag fields make valid tag keys, and the assets are the scan's only
+// target list besides arguments and --stdin.
+func (c *Config) ValidateTargetsCSV() error {
+	if c.TargetsCSV == "" {
+		if c.TagFields != "" {
+			return errors.New("--tag-fields needs --targets-csv")
+		}
+		return nil
+	}
+	if strings.TrimSpace(c.HostField) == "" {
+		return errors.New("--host-field is empty")
+	}
+	seen := make(map[string]bool)
+	for _, field := range c.GetTagFields() {
+		if !validTagKey(field) {
+			return fmt.Errorf("tag field %q cannot be a tag key: use letters, digits, '.', '_' or '-'", field)
+		}
+		if seen[strings.ToLower(field)] {
+			return fmt.Errorf("duplicate tag field %q", field)
+		}
+		seen[strings.ToLower(field)] = true
+	}
+	switch {
+	case c.FromMasscan != "":
+		return errors.New("--targets-csv cannot be combined with --from-masscan")
+	case len(c.Jobs) > 0:
+		return errors.New("--targets-csv cannot be combined with jobs: give each job its own targets file")
+	}
+	return nil
+}
+
+// GetASNs returns the --asn numbers in the order given, without
+// duplicates.
+func (c *Config) GetASNs() ([]uint32, error) {
+	var asns []uint32
+	for _, field := range strings.Split(c.ASN, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		asn, err := targets.ParseASN(field)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(asns, asn) {
+			asns = append(asns, asn)
+		}
+	}
+	return asns, nil
+}
+
+// ValidateASN checks the ASN target options: the numbers parse, a
+// dataset to look them up in is given, and the ASNs are not combined with
+// target lists of their own.
+func (c *Config) ValidateASN() error {
+	if strings.TrimSpace(c.ASN) == "" {
+		if c.ASNData != "" {
+			return errors.New("--asn-data needs --asn")
+		}
+		return nil
+	}
+	if _, err := c.GetASNs(); err != nil {
+		return err
+	}
+	switch {
+	case c.ASNData == "":
+		return errors.New("--asn needs --asn-data, a prefix-to-AS dataset such as a RouteViews pfx2as file")
+	case c.FromMasscan != "":
+		return errors.New("--asn cannot be combined with --from-masscan")
+	case len(c.Jobs) > 0:
+		return errors.New("--asn cannot be combined with jobs: give each job its own targets file")
+	}
+	return nil
+}
+
+// ValidateJobs checks the scan jobs: each must parse and have a unique
+// name and file, and jobs only write their own export files.
+func (c *Config) ValidateJobs() error {
+	if len(c.Jobs) == 0 {
+		return nil
+	}
+	jobs, err := c.GetJobs()
+	if err != nil {
+		return err
+	}
+	names := make(map[string]bool, len(jobs))
+	files := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		if names[job.Name] {
+			return fmt.Errorf("duplicate job name %q", job.Name)
+		}
+		names[job.Name] = true
+		if job.File != "" {
+			if files[job.File] {
+				return fmt.Errorf("jobs %q and another job write the same file %q", job.Name, job.File)
+			}
+			files[job.File] = true
+		}
+	}
+	switch {
+	case c.FromMasscan != "":
+		return errors.New("jobs cannot be combined with --from-masscan")
+	case c.OutputFile != "":
+		return errors.New("jobs write one file each: set file= on a job instead of --output-file")
+	case c.Upload != "":
+		return errors.New("jobs cannot be uploaded")
+	}
+	switch c.Output {
+	case "elastic", "kafka", "nats", "prometheus", "syslog", "inventory":
+		return fmt.Errorf("%s output cannot be used with jobs: use json, csv, html or table", c.Output)
+	}
+	return nil
+}
+
+// ValidateUpload checks the upload destination and that the scan produces
+// a single export file to upload.
+func (c *Config) ValidateUpload() error {
+	if c.Upload == "" {
+		return nil
+	}
+	if _, err := upload.ParseDestination(c.Upload); err != nil {
+		return err
+	}
+	switch c.Output {
+	case "elastic", "kafka", "nats", "prometheus":
+		return fmt.Errorf("%s output cannot be uploaded: use json, csv, html or table", c.Output)
+	case "syslog":
+		if c.SyslogAddr != "" {
+			return errors.New("syslog output sent to a collector cannot be uploaded")
+		}
+	}
+	if c.OutputMaxSize != "" {
+		return errors.New("upload cannot be combined with output rotation")
+	}
+	if c.UploadEndpoint != "" {
+		u, err := url.Parse(c.UploadEndpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid upload endpoint %q: want http(s)://host:port", c.UploadEndpoint)
+		}
+	}
+	return nil
+}
@@ -21,6 +21,26 @@
 //	  theme: dracula
 //	  result_buffer_size: 10000
 //
+// Includes and Environment References:
+//
+// After the file is read, ExpandFile merges the files listed under
+// include beneath it, in order, and replaces ${NAME} in string values
+// with the environment variable NAME:
+//
+//	include: [/etc/portscan/team.yaml]
+//	elastic_api_key: ${ELASTIC_API_KEY}
+//
+// Per-Target Overrides:
+//
+// Entries under overrides give targets matching a network or domain their
+// own rate, timeout, retries, jitter, per-host parallelism or workers; see
+// TargetOverride. The first matching entry applies:
+//
+//	overrides:
+//	  - match: "10.2.0.0/16"
+//	    rate: 500
+//	    timeout_ms: 800
+//
 // Usage:
 //
 //	cfg, err := config.Load()
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/spf13/viper"
+)
+
+// includeKey lists further config files merged beneath the file naming
+// them.
+const includeKey = "include"
+
+// envRef matches a ${NAME} environment variable reference.
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExpandFile finishes loading the config file v has read. Files listed
+// under include are merged in order, each over the one before and all
+// beneath the including file, so a shared team file can hold defaults a
+// machine's file overrides. Included files may include others; relative
+// paths are resolved against the including file's directory. Every
+// ${NAME} in a string value is then replaced by the environment variable
+// NAME, or removed if it is unset, so credentials need not be written
+// into the file. It does nothing when v has not read a file.
+func ExpandFile(v *viper.Viper) error {
+	path := v.ConfigFileUsed()
+	if path == "" {
+		return nil
+	}
+	settings, err := readIncluding(path, nil)
+	if err != nil {
+		return err
+	}
+	return v.MergeConfigMap(settings)
+}
+
+// readIncluding reads path with its includes merged beneath it. chain
+// holds the files currently being read, to reject include cycles.
+func readIncluding(path string, chain []string) (map[string]interface{}, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, seen := range chain {
+		if seen == abs {
+			return nil, fmt.Errorf("config include cycle: %s includes itself", path)
+		}
+	}
+	chain = append(chain, abs)
+
+	file := viper.New()
+	file.SetConfigFile(abs)
+	if err := file.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read config %s: %w", path, err)
+	}
+	own := file.AllSettings()
+
+	includes, err := includeList(own[includeKey])
+	if err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	delete(own, includeKey)
+
+	merged := map[string]interface{}{}
+	for _, include := range includes {
+		include = expandEnv(include)
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(abs), include)
+		}
+		settings, err := readIncluding(include, chain)
+		if err != nil {
+			return nil, err
+		}
+		mergeSettings(merged, settings)
+	}
+	mergeSettings(merged, expandValues(own).(map[string]interface{}))
+	return merged, nil
+}
+
+// includeList reads the include setting, a file name or a list of them.
+func includeList(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		files := make([]string, 0, len(v))
+		for _, item := range v {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("include must list file names, got %v", item)
+			}
+			files = append(files, name)
+		}
+		return files, nil
+	}
+	return nil, fmt.Errorf("include must be a file name or a list of them, got %v", value)
+}
+
+// mergeSettings copies src into dst, merging nested sections key by key
+// rather than replacing them.
+func mergeSettings(dst, src map[string]interface{}) {
+	for key, value := range src {
+		section, ok := value.(map[string]interface{})
+		existing, isSection := dst[key].(map[string]interface{})
+		if ok && isSection {
+			mergeSettings(existing, section)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// expandValues replaces environment variable references in every string
+// within value, descending into sections and lists.
+func expandValues(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return expandEnv(v)
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = expandValues(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = expandValues(item)
+		}
+		return v
+	case []string:
+		for i, item := range v {
+			v[i] = expandEnv(item)
+		}
+		return v
+	}
+	return value
+}
+
+// expandEnv replaces each ${NAME} in s with the value of the environment
+// variable NAME. A bare $NAME is left alone, so values such as passwords
+// containing "$" survive.
+func expandEnv(s string) string {
+	return envRef.ReplaceAllStringFunc(s, func(ref string) string {
+		return os.Getenv(envRef.FindStringSubmatch(ref)[1])
+	})
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func writeConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readExpanded(t *testing.T, path string) (*viper.Viper, error) {
+	t.Helper()
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatalf("ReadInConfig: %v", err)
+	}
+	return v, ExpandFile(v)
+}
+
+func TestExpandFileEnv(t *testing.T) {
+	t.Setenv("PORTSCAN_TEST_ELASTIC", "https://es.internal:9200")
+	t.Setenv("PORTSCAN_TEST_ENV", "prod")
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "portscan.yaml", `elastic_url: ${PORTSCAN_TEST_ELASTIC}
+elastic_api_key: "${PORTSCAN_TEST_UNSET}"
+upload_endpoint: "https://minio.example.com/$literal"
+tags: ["env=${PORTSCAN_TEST_ENV}"]
+ui:
+  theme: ${PORTSCAN_TEST_ENV}-dark
+`)
+
+	v, err := readExpanded(t, path)
+	if err != nil {
+		t.Fatalf("ExpandFile: %v", err)
+	}
+	if got := v.GetString("elastic_url"); got != "https://es.internal:9200" {
+		t.Errorf("elastic_url = %q", got)
+	}
+	if got := v.GetString("elastic_api_key"); got != "" {
+		t.Errorf("unset variable should expand to empty, got %q", got)
+	}
+	if got := v.GetString("upload_endpoint"); got != "https://minio.example.com/$literal" {
+		t.Errorf("bare $ should be kept, got %q", got)
+	}
+	if got := v.GetStringSlice("tags"); len(got) != 1 || got[0] != "env=prod" {
+		t.Errorf("tags = %v", got)
+	}
+	if got := v.GetString("ui.theme"); got != "prod-dark" {
+		t.Errorf("ui.theme = %q", got)
+	}
+}
+
+func TestExpandFileIncludesInOrder(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, "team.yaml", `rate: 1000
+workers: 50
+timeout_ms: 300
+ui:
+  theme: nord
+  accessible: true
+`)
+	if err := os.Mkdir(filepath.Join(dir, "site"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeConfig(t, filepath.Join(dir, "site"), "site.yaml", `workers: 80
+banners: true
+`)
+	path := writeConfig(t, dir, "portscan.yaml", `include:
+  - team.yaml
+  - site/site.yaml
+rate: 2000
+ui:
+  theme: dracula
+`)
+
+	v, err := readExpanded(t, path)
+	if err != nil {
+		t.Fatalf("ExpandFile: %v", err)
+	}
+	checks := map[string]interface{}{
+		"rate":          2000, // the including file wins
+		"workers":       80,   // later includes override earlier ones
+		"timeout_ms":    300,
+		"banners":       true,
+		"ui.theme":      "dracula",
+		"ui.accessible": true, // sections merge key by key
+	}
+	for key, want := range checks {
+		if got := v.Get(key); got != want {
+			t.Errorf("%s = %v (%T); want %v", key, got, got, want)
+		}
+	}
+}
+
+func TestExpandFileIncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	missing := writeConfig(t, dir, "missing.yaml", "include: [nope.yaml]\n")
+	writeConfig(t, dir, "a.yaml", "include: b.yaml\n")
+	writeConfig(t, dir, "b.yaml", "include: a.yaml\n")
+	cycle := filepath.Join(dir, "a.yaml")
+	badList := writeConfig(t, dir, "bad.yaml", "include: [1, 2]\n")
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{missing, "nope.yaml"},
+		{cycle, "cycle"},
+		{badList, "include must list file names"},
+	}
+	for _, tt := range tests {
+		if _, err := readExpanded(t, tt.path); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ExpandFile(%s) error = %v; want it to mention %q", filepath.Base(tt.path), err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/lucchesi-sec/portscan/pkg/targets"
+)
+
+// TargetOverride gives the targets matching Match their own settings, such
+// as a lower rate for a fragile subnet in an otherwise fast scan. Zero
+// fields keep the scan's setting.
+type TargetOverride struct {
+	Match        string `mapstructure:"match"`                                      // CIDR, address, hostname or *.domain
+	Rate         int    `mapstructure:"rate" validate:"min=0,max=15000"`            // probes per second for the matching targets
+	TimeoutMs    int    `mapstructure:"timeout_ms" validate:"min=0,max=60000"`      // connection timeout
+	Retries      int    `mapstructure:"retries" validate:"min=0,max=10"`            // retry attempts for timed-out ports
+	JitterMs     int    `mapstructure:"jitter_ms" validate:"min=0,max=60000"`       // random delay before each probe
+	PerHostLimit int    `mapstructure:"host_parallelism" validate:"min=0,max=1000"` // concurrent probes per host
+	Workers      int    `mapstructure:"workers" validate:"min=0,max=1000"`          // concurrent workers
+}
+
+// Apply returns a copy of cfg with the override's non-zero settings.
+func (o TargetOverride) Apply(cfg *Config) *Config {
+	out := *cfg
+	out.Overrides = nil
+	if o.Rate > 0 {
+		out.Rate = o.Rate
+	}
+	if o.TimeoutMs > 0 {
+		out.TimeoutMs = o.TimeoutMs
+	}
+	if o.Retries > 0 {
+		out.Retries = o.Retries
+	}
+	if o.JitterMs > 0 {
+		out.JitterMs = o.JitterMs
+	}
+	if o.PerHostLimit > 0 {
+		out.PerHostLimit = o.PerHostLimit
+	}
+	if o.Workers > 0 {
+		out.Workers = o.Workers
+	}
+	return &out
+}
+
+// GetOverrideMatches returns a matcher for each override's pattern, in
+// order. It returns nil when there are no overrides or a pattern is
+// invalid; ValidateOverrides reports the parse error.
+func (c *Config) GetOverrideMatches() []*targets.Scope {
+	if len(c.Overrides) == 0 {
+		return nil
+	}
+	matches := make([]*targets.Scope, len(c.Overrides))
+	for i, o := range c.Overrides {
+		match, err := targets.ParseMatch(o.Match)
+		if err != nil {
+			return nil
+		}
+		matches[i] = match
+	}
+	return matches
+}
+
+// ValidateOverrides checks that every override has a valid match pattern
+// and changes at least one setting.
+func (c *Config) ValidateOverrides() error {
+	for i, o := range c.Overrides {
+		if _, err := targets.ParseMatch(o.Match); err != nil {
+			return fmt.Errorf("override %d (%q): %w", i+1, o.Match, err)
+		}
+		if o == (TargetOverride{Match: o.Match}) {
+			return fmt.Errorf("override %d (%q) changes no settings", i+1, o.Match)
+		}
+	}
+	return nil
+}
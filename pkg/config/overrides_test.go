@@ -0,0 +1,98 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestLoadOverrides(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`overrides:
+  - match: "10.2.0.0/16"
+    rate: 500
+    timeout_ms: 800
+  - match: "*.scada.example.net"
+    host_parallelism: 1
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Overrides) != 2 {
+		t.Fatalf("Overrides = %+v; want 2", cfg.Overrides)
+	}
+	want := TargetOverride{Match: "10.2.0.0/16", Rate: 500, TimeoutMs: 800}
+	if cfg.Overrides[0] != want {
+		t.Errorf("Overrides[0] = %+v; want %+v", cfg.Overrides[0], want)
+	}
+	if cfg.Overrides[1].PerHostLimit != 1 {
+		t.Errorf("Overrides[1].PerHostLimit = %d; want 1", cfg.Overrides[1].PerHostLimit)
+	}
+	if matches := cfg.GetOverrideMatches(); len(matches) != 2 || !matches[0].Allows("10.2.3.4", "") {
+		t.Errorf("GetOverrideMatches() did not match 10.2.3.4")
+	}
+}
+
+func TestLoadOverridesInvalidRate(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("overrides", []map[string]interface{}{{"match": "10.0.0.0/8", "rate": 20000}})
+
+	if _, err := Load(); err == nil {
+		t.Error("Load() should reject an override rate above 15000")
+	}
+}
+
+func TestTargetOverrideApply(t *testing.T) {
+	base := &Config{Rate: 7500, TimeoutMs: 200, Retries: 2, Workers: 100, Overrides: []TargetOverride{{Match: "10.0.0.0/8"}}}
+	got := TargetOverride{Match: "10.0.0.0/8", Rate: 500, TimeoutMs: 800}.Apply(base)
+
+	if got.Rate != 500 || got.TimeoutMs != 800 {
+		t.Errorf("Apply() rate/timeout = %d/%d; want 500/800", got.Rate, got.TimeoutMs)
+	}
+	if got.Retries != 2 || got.Workers != 100 {
+		t.Errorf("Apply() should keep unset settings, got retries %d, workers %d", got.Retries, got.Workers)
+	}
+	if got.Overrides != nil {
+		t.Error("Apply() result should not carry the overrides")
+	}
+	if base.Rate != 7500 {
+		t.Error("Apply() modified the base config")
+	}
+}
+
+func TestValidateOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides []TargetOverride
+		wantErr   string
+	}{
+		{"none", nil, ""},
+		{"valid", []TargetOverride{{Match: "10.2.0.0/16", Rate: 500}}, ""},
+		{"missing match", []TargetOverride{{Rate: 500}}, "override 1"},
+		{"bad network", []TargetOverride{{Match: "10.2.0.0/40", Rate: 500}}, "10.2.0.0/40"},
+		{"no settings", []TargetOverride{{Match: "db.example.com"}}, "changes no settings"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Config{Overrides: tt.overrides}).ValidateOverrides()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateOverrides() = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateOverrides() = %v; want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
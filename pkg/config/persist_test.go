@@ -0,0 +1,100 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestSaveSettingKeepsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "portscan.yaml", `# team defaults
+rate: 5000 # slow network
+ui:
+  theme: dracula        # dark terminals
+  accessible: false
+`)
+
+	if err := SaveSetting(path, "ui.theme", "nord"); err != nil {
+		t.Fatalf("SaveSetting: %v", err)
+	}
+	if err := SaveSetting(path, "ui.columns", []string{"host", "port", "state"}); err != nil {
+		t.Fatalf("SaveSetting: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, kept := range []string{"# team defaults", "# slow network", "# dark terminals"} {
+		if !strings.Contains(string(data), kept) {
+			t.Errorf("comment %q lost:\n%s", kept, data)
+		}
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatalf("ReadInConfig: %v", err)
+	}
+	if got := v.GetString("ui.theme"); got != "nord" {
+		t.Errorf("ui.theme = %q, want nord", got)
+	}
+	if got := v.GetStringSlice("ui.columns"); strings.Join(got, ",") != "host,port,state" {
+		t.Errorf("ui.columns = %v", got)
+	}
+	if got := v.GetInt("rate"); got != 5000 {
+		t.Errorf("rate = %d, want 5000", got)
+	}
+}
+
+func TestSaveSettingCreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".portscan.yaml")
+
+	if err := SaveSetting(path, "ui.columns", []string{"host"}); err != nil {
+		t.Fatalf("SaveSetting: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("new config mode = %o, want 600", perm)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "columns:") {
+		t.Errorf("setting not written:\n%s", data)
+	}
+}
+
+func TestSaveSettingRejectsScalarParent(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "portscan.yaml", "ui: plain\n")
+	if err := SaveSetting(path, "ui.theme", "nord"); err == nil {
+		t.Error("setting a key beneath a scalar should fail")
+	}
+}
+
+func TestSaveSettingWritesPreset(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "portscan.yaml", "ui:\n  theme: nord\n")
+	preset := FilterPreset{State: "open", Ports: "80-443", Service: "http", Sort: "host"}
+
+	if err := SaveSetting(path, "ui.presets.external-web", preset); err != nil {
+		t.Fatalf("SaveSetting: %v", err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatalf("ReadInConfig: %v", err)
+	}
+	var ui UIConfig
+	if err := v.UnmarshalKey("ui", &ui); err != nil {
+		t.Fatalf("UnmarshalKey: %v", err)
+	}
+	if got := ui.Presets["external-web"]; got != preset {
+		t.Errorf("preset read back as %+v, want %+v", got, preset)
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TimingTemplate bundles the pacing settings selected with -T0 through -T5,
+// modelled on nmap's timing templates.
+type TimingTemplate struct {
+	Level           int
+	Name            string
+	Rate            int // probes per second
+	TimeoutMs       int
+	Retries         int // retry attempts for filtered ports
+	JitterMs        int // random delay of up to this long before each probe
+	HostParallelism int // concurrent probes per host; 0 is unlimited
+}
+
+// TimingTemplates lists the templates by level. T3 matches the defaults.
+var TimingTemplates = []TimingTemplate{
+	{Level: 0, Name: "paranoid", Rate: 1, TimeoutMs: 5000, Retries: 3, JitterMs: 1000, HostParallelism: 1},
+	{Level: 1, Name: "sneaky", Rate: 10, TimeoutMs: 3000, Retries: 3, JitterMs: 500, HostParallelism: 1},
+	{Level: 2, Name: "polite", Rate: 100, TimeoutMs: 1000, Retries: 2, JitterMs: 100, HostParallelism: 4},
+	{Level: 3, Name: "normal", Rate: 7500, TimeoutMs: 200, Retries: 2},
+	{Level: 4, Name: "aggressive", Rate: 10000, TimeoutMs: 150, Retries: 1},
+	{Level: 5, Name: "insane", Rate: 15000, TimeoutMs: 100, Retries: 1},
+}
+
+// LookupTimingTemplate finds a template by level ("4" or "T4") or by name
+// ("aggressive").
+func LookupTimingTemplate(name string) (TimingTemplate, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if level, err := strconv.Atoi(strings.TrimPrefix(key, "t")); err == nil {
+		if level >= 0 && level < len(TimingTemplates) {
+			return TimingTemplates[level], nil
+		}
+	}
+	for _, t := range TimingTemplates {
+		if t.Name == key {
+			return t, nil
+		}
+	}
+	return TimingTemplate{}, fmt.Errorf("unknown timing template %q: use T0-T5 or paranoid, sneaky, polite, normal, aggressive, insane", name)
+}
+
+// ApplyTimingTemplate overwrites the pacing settings with the selected
+// template. explicit reports whether a config key (such as "rate") was set
+// on the command line; those values are kept. It is a no-op without a
+// template and fails for an unknown one.
+func (c *Config) ApplyTimingTemplate(explicit func(key string) bool) error {
+	if c.Timing == "" {
+		return nil
+	}
+	t, err := LookupTimingTemplate(c.Timing)
+	if err != nil {
+		return err
+	}
+	if explicit == nil {
+		explicit = func(string) bool { return false }
+	}
+	if !explicit("rate") {
+		c.Rate = t.Rate
+	}
+	if !explicit("timeout_ms") {
+		c.TimeoutMs = t.TimeoutMs
+	}
+	if !explicit("retries") {
+		c.Retries = t.Retries
+	}
+	if !explicit("jitter_ms") {
+		c.JitterMs = t.JitterMs
+	}
+	if !explicit("host_parallelism") {
+		c.PerHostLimit = t.HostParallelism
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package config
+
+import "testing"
+
+func TestLookupTimingTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		wantLevel int
+		wantErr   bool
+	}{
+		{name: "T4", wantLevel: 4},
+		{name: "t0", wantLevel: 0},
+		{name: "5", wantLevel: 5},
+		{name: " Polite ", wantLevel: 2},
+		{name: "T6", wantErr: true},
+		{name: "ludicrous", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LookupTimingTemplate(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LookupTimingTemplate(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Level != tt.wantLevel {
+				t.Errorf("LookupTimingTemplate(%q) level = %d, want %d", tt.name, got.Level, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestTimingTemplatesAreValid(t *testing.T) {
+	for i, tmpl := range TimingTemplates {
+		if tmpl.Level != i {
+			t.Errorf("template %q at index %d has level %d", tmpl.Name, i, tmpl.Level)
+		}
+		if tmpl.Rate < 1 || tmpl.Rate > 15000 || tmpl.TimeoutMs < 1 {
+			t.Errorf("template %q has out-of-range settings: %+v", tmpl.Name, tmpl)
+		}
+		if i > 0 && tmpl.Rate < TimingTemplates[i-1].Rate {
+			t.Errorf("template %q is slower than %q", tmpl.Name, TimingTemplates[i-1].Name)
+		}
+	}
+}
+
+func TestApplyTimingTemplate(t *testing.T) {
+	c := &Config{Timing: "T1", Rate: 7500, TimeoutMs: 750}
+	explicit := func(key string) bool { return key == "timeout_ms" }
+	if err := c.ApplyTimingTemplate(explicit); err != nil {
+		t.Fatalf("ApplyTimingTemplate() = %v", err)
+	}
+
+	sneaky := TimingTemplates[1]
+	if c.Rate != sneaky.Rate || c.Retries != sneaky.Retries || c.JitterMs != sneaky.JitterMs || c.PerHostLimit != sneaky.HostParallelism {
+		t.Errorf("template not applied: %+v", c)
+	}
+	if c.TimeoutMs != 750 {
+		t.Errorf("TimeoutMs = %d, want the explicit 750", c.TimeoutMs)
+	}
+}
+
+func TestApplyTimingTemplateNoop(t *testing.T) {
+	c := &Config{Rate: 5000}
+	if err := c.ApplyTimingTemplate(nil); err != nil || c.Rate != 5000 {
+		t.Errorf("ApplyTimingTemplate() without a template = %v, rate %d", err, c.Rate)
+	}
+
+	c.Timing = "T9"
+	if err := c.ApplyTimingTemplate(nil); err == nil {
+		t.Error("ApplyTimingTemplate() should reject an unknown template")
+	}
+}
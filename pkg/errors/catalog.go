@@ -0,0 +1,67 @@
+package errors
+
+import (
+	_ "embed"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+	"go.yaml.in/yaml/v3"
+)
+
+// CatalogEntry documents one UserError code for 'portscan explain'.
+type CatalogEntry struct {
+	Code        string   `yaml:"-"`
+	Title       string   `yaml:"title"`
+	Explanation string   `yaml:"explanation"`
+	Remediation []string `yaml:"remediation"`
+}
+
+//go:embed catalog.yaml
+var catalogYAML []byte
+
+var (
+	catalogOnce    sync.Once
+	catalogEntries map[string]CatalogEntry
+)
+
+func catalog() map[string]CatalogEntry {
+	catalogOnce.Do(func() {
+		if err := yaml.Unmarshal(catalogYAML, &catalogEntries); err != nil {
+			panic("errors: invalid embedded catalog: " + err.Error())
+		}
+		for code, entry := range catalogEntries {
+			entry.Code = code
+			entry.Explanation = strings.TrimSpace(entry.Explanation)
+			catalogEntries[code] = entry
+		}
+	})
+	return catalogEntries
+}
+
+// Explain returns the catalog entry for code, ignoring case.
+func Explain(code string) (CatalogEntry, bool) {
+	entry, ok := catalog()[strings.ToUpper(strings.TrimSpace(code))]
+	return entry, ok
+}
+
+// CatalogCodes returns every code in the catalog, sorted.
+func CatalogCodes() []string {
+	codes := make([]string, 0, len(catalog()))
+	for code := range catalog() {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// UnknownCodeError creates a user error for a code missing from the catalog.
+func UnknownCodeError(code string) *UserError {
+	return &UserError{
+		Code:       "UNKNOWN_ERROR_CODE",
+		Message:    i18n.T("errors.unknown_error_code.message", code),
+		Details:    i18n.T("errors.unknown_error_code.details"),
+		Suggestion: i18n.T("errors.unknown_error_code.suggestion"),
+	}
+}
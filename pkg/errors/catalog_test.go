@@ -0,0 +1,57 @@
+package errors
+
+import (
+	stdErrors "errors"
+	"testing"
+)
+
+func TestCatalog_EntriesComplete(t *testing.T) {
+	codes := CatalogCodes()
+	if len(codes) == 0 {
+		t.Fatal("catalog is empty")
+	}
+	for _, code := range codes {
+		entry, _ := Explain(code)
+		if entry.Code != code || entry.Title == "" || entry.Explanation == "" || len(entry.Remediation) == 0 {
+			t.Errorf("incomplete catalog entry %s: %+v", code, entry)
+		}
+	}
+}
+
+func TestCatalog_CoversConstructors(t *testing.T) {
+	for _, err := range []*UserError{
+		InvalidPortError("x", nil),
+		NoTargetError(),
+		InvalidTargetError("x", nil),
+		InvalidTargetListError(stdErrors.New("x")),
+		ConfigLoadError("x", nil),
+		RateLimitError(20000, 15000),
+		NetworkError("x", nil),
+		PermissionError("x"),
+		PrivilegeDropError("x", nil),
+		OutOfScopeError("x", []string{"10.0.0.1"}, 5),
+		AllTargetsExcludedError([]string{"10.0.0.1"}, 5),
+		ScanPolicyError("x", ErrLocalhostScanningDisabled),
+		ScanPolicyError("x", ErrPrivateIPScanningDisabled),
+		ScanPolicyError("x", nil),
+		TimeoutError(100),
+		UnknownCodeError("x"),
+	} {
+		if _, ok := Explain(err.Code); !ok {
+			t.Errorf("code %s has no catalog entry", err.Code)
+		}
+	}
+	if _, ok := Explain("ERROR"); !ok {
+		t.Error("the generic Report code ERROR has no catalog entry")
+	}
+}
+
+func TestExplain_IgnoresCase(t *testing.T) {
+	entry, ok := Explain(" rate_limit_high ")
+	if !ok || entry.Code != "RATE_LIMIT_HIGH" {
+		t.Errorf("Explain(rate_limit_high) = %+v, %v", entry, ok)
+	}
+	if _, ok := Explain("NOT_A_CODE"); ok {
+		t.Error("unknown codes should not be found")
+	}
+}
@@ -39,8 +39,23 @@
 //   - ErrInvalidPort: Port outside valid range
 //   - ErrInvalidCIDR: Malformed CIDR notation
 //   - ErrInvalidHost: Invalid hostname or IP address
-//   - ErrLocalhostScanningDisabled: Attempted localhost scan without permission
-//   - ErrPrivateIPScanningDisabled: Attempted private IP scan without permission
+//   - ErrLocalhostScanningDisabled: Attempted localhost scan with --allow-localhost=false
+//   - ErrPrivateIPScanningDisabled: Attempted private IP scan with --allow-private=false
+//
+// ScanPolicyError turns either into a UserError whose suggestion names the
+// flag and config key that allow the scan.
+//
+// Error Catalog:
+//
+// catalog.yaml, embedded at build time, explains every UserError code with
+// remediation steps; Explain looks one up for 'portscan explain CODE'. New
+// codes need an entry there.
+//
+// Exit Codes:
+//
+// ExitError carries the process exit code for outcomes automation must tell
+// apart (ExitAborted, ExitPolicy, ExitPartial); ExitCode maps any command
+// error to its code, with ExitUsage for everything else.
 //
 // Integration:
 //
@@ -0,0 +1,40 @@
+package errors
+
+import stdErrors "errors"
+
+// Exit codes returned by portscan, so automation can tell a completed
+// scan from one that was stopped, found a policy violation, or lost results.
+const (
+	ExitOK      = 0 // the command completed
+	ExitUsage   = 1 // usage or configuration error; nothing was scanned
+	ExitAborted = 2 // the scan was interrupted before it finished
+	ExitPolicy  = 3 // the scan found ports matched by --fail-on
+	ExitPartial = 4 // some probes failed, so results are incomplete
+)
+
+// ExitError ends the command with a specific exit code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code for an error returned by a command:
+// ExitOK for nil, the code of a wrapped ExitError, and ExitUsage otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *ExitError
+	if stdErrors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return ExitUsage
+}
@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestExitCode tests exit codes for nil, plain, and wrapped exit errors
+func TestExitCode(t *testing.T) {
+	aborted := &ExitError{Code: ExitAborted, Err: errors.New("scan interrupted")}
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"plain error", errors.New("unknown flag"), ExitUsage},
+		{"user error", NoTargetError(), ExitUsage},
+		{"exit error", aborted, ExitAborted},
+		{"wrapped exit error", fmt.Errorf("scan: %w", &ExitError{Code: ExitPartial, Err: errors.New("3 probes failed")}), ExitPartial},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+	if aborted.Error() != "scan interrupted" {
+		t.Errorf("Error() = %q, want the wrapped message", aborted.Error())
+	}
+}
@@ -0,0 +1,31 @@
+package errors
+
+import stdErrors "errors"
+
+// Report is the machine-readable form of a command error, written as JSON
+// by --error-format json so automation can branch on Code.
+type Report struct {
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	Details    string `json:"details,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
+	Cause      string `json:"cause,omitempty"`
+	ExitCode   int    `json:"exit_code"`
+}
+
+// NewReport describes err. A wrapped UserError supplies the code and text;
+// any other error is reported with code "ERROR" and its message.
+func NewReport(err error) Report {
+	report := Report{Code: "ERROR", Message: err.Error(), ExitCode: ExitCode(err)}
+	var userErr *UserError
+	if stdErrors.As(err, &userErr) {
+		report.Code = userErr.Code
+		report.Message = userErr.Message
+		report.Details = userErr.Details
+		report.Suggestion = userErr.Suggestion
+		if userErr.WrappedErr != nil {
+			report.Cause = userErr.WrappedErr.Error()
+		}
+	}
+	return report
+}
@@ -0,0 +1,29 @@
+package errors
+
+import (
+	stdErrors "errors"
+	"testing"
+)
+
+func TestNewReport_UserError(t *testing.T) {
+	err := &ExitError{Code: ExitPolicy, Err: InvalidPortError("99999", stdErrors.New("out of range"))}
+	report := NewReport(err)
+
+	if report.Code != "INVALID_PORT" || report.ExitCode != ExitPolicy {
+		t.Errorf("code/exit = %s/%d, want INVALID_PORT/%d", report.Code, report.ExitCode, ExitPolicy)
+	}
+	if report.Message != "Invalid port specification: '99999'" {
+		t.Errorf("message = %q", report.Message)
+	}
+	if report.Suggestion == "" || report.Cause != "out of range" {
+		t.Errorf("suggestion/cause not carried over: %+v", report)
+	}
+}
+
+func TestNewReport_PlainError(t *testing.T) {
+	report := NewReport(stdErrors.New("boom"))
+	want := Report{Code: "ERROR", Message: "boom", ExitCode: ExitUsage}
+	if report != want {
+		t.Errorf("NewReport = %+v, want %+v", report, want)
+	}
+}
@@ -1,8 +1,17 @@
 package errors
 
 import (
-	"fmt"
+	stdErrors "errors"
 	"strings"
+
+	"github.com/lucchesi-sec/portscan/pkg/i18n"
+)
+
+// Scan policy errors, returned when a target is in a range the
+// configuration does not allow scanning.
+var (
+	ErrLocalhostScanningDisabled = stdErrors.New("localhost scanning is disabled")
+	ErrPrivateIPScanningDisabled = stdErrors.New("private IP scanning is disabled")
 )
 
 // UserError represents an error with user-friendly message and recovery suggestions
@@ -22,15 +31,15 @@ func (e *UserError) Error() string {
 	}
 
 	if e.Details != "" {
-		parts = append(parts, fmt.Sprintf("Details: %s", e.Details))
+		parts = append(parts, i18n.T("errors.labels.details", e.Details))
 	}
 
 	if e.Suggestion != "" {
-		parts = append(parts, fmt.Sprintf("Try: %s", e.Suggestion))
+		parts = append(parts, i18n.T("errors.labels.try", e.Suggestion))
 	}
 
 	if e.WrappedErr != nil {
-		parts = append(parts, fmt.Sprintf("(Error: %v)", e.WrappedErr))
+		parts = append(parts, i18n.T("errors.labels.cause", e.WrappedErr))
 	}
 
 	return strings.Join(parts, "\n")
@@ -43,12 +52,23 @@ func (e *UserError) Unwrap() error {
 // Common error constructors
 
 // InvalidPortError creates a user error for invalid port specifications.
+// When err locates the problem in the specification, as a
+// *parser.SyntaxError does, the details point at it.
 func InvalidPortError(port string, err error) *UserError {
+	details := i18n.T("errors.invalid_port.details")
+	var located interface {
+		Position() (input string, column int, reason string)
+	}
+	if stdErrors.As(err, &located) {
+		input, column, reason := located.Position()
+		details = i18n.T("errors.invalid_port.position", column, reason) +
+			"\n  " + input + "\n  " + strings.Repeat(" ", column-1) + "^"
+	}
 	return &UserError{
 		Code:       "INVALID_PORT",
-		Message:    fmt.Sprintf("Invalid port specification: '%s'", port),
-		Details:    "Ports must be between 1 and 65535",
-		Suggestion: "Use formats like '80,443' or '1-1024' or '8000-9000'",
+		Message:    i18n.T("errors.invalid_port.message", port),
+		Details:    details,
+		Suggestion: i18n.T("errors.invalid_port.suggestion"),
 		WrappedErr: err,
 	}
 }
@@ -57,9 +77,9 @@ func InvalidPortError(port string, err error) *UserError {
 func NoTargetError() *UserError {
 	return &UserError{
 		Code:       "NO_TARGET",
-		Message:    "No target specified",
-		Details:    "A target host or network is required for scanning",
-		Suggestion: "Provide a target like 'portscan scan 192.168.1.1' or 'portscan scan example.com'",
+		Message:    i18n.T("errors.no_target.message"),
+		Details:    i18n.T("errors.no_target.details"),
+		Suggestion: i18n.T("errors.no_target.suggestion"),
 	}
 }
 
@@ -67,9 +87,9 @@ func NoTargetError() *UserError {
 func InvalidTargetError(target string, err error) *UserError {
 	return &UserError{
 		Code:       "INVALID_TARGET",
-		Message:    fmt.Sprintf("Invalid target: '%s'", target),
-		Details:    "Could not resolve or parse the target address",
-		Suggestion: "Check the hostname/IP is correct. Examples: '192.168.1.1', 'example.com', '10.0.0.0/24'",
+		Message:    i18n.T("errors.invalid_target.message", target),
+		Details:    i18n.T("errors.invalid_target.details"),
+		Suggestion: i18n.T("errors.invalid_target.suggestion"),
 		WrappedErr: err,
 	}
 }
@@ -78,9 +98,9 @@ func InvalidTargetError(target string, err error) *UserError {
 func InvalidTargetListError(err error) *UserError {
 	return &UserError{
 		Code:       "INVALID_TARGET_LIST",
-		Message:    "Unable to resolve one or more targets",
+		Message:    i18n.T("errors.invalid_target_list.message"),
 		Details:    err.Error(),
-		Suggestion: "Verify hostnames/IPs, reduce CIDR size, or split very large scans into smaller batches",
+		Suggestion: i18n.T("errors.invalid_target_list.suggestion"),
 		WrappedErr: err,
 	}
 }
@@ -89,9 +109,9 @@ func InvalidTargetListError(err error) *UserError {
 func ConfigLoadError(path string, err error) *UserError {
 	return &UserError{
 		Code:       "CONFIG_ERROR",
-		Message:    "Failed to load configuration",
-		Details:    fmt.Sprintf("Could not read config from: %s", path),
-		Suggestion: "Run 'portscan config init' to create a default config, or check file permissions",
+		Message:    i18n.T("errors.config_error.message"),
+		Details:    i18n.T("errors.config_error.details", path),
+		Suggestion: i18n.T("errors.config_error.suggestion"),
 		WrappedErr: err,
 	}
 }
@@ -100,9 +120,9 @@ func ConfigLoadError(path string, err error) *UserError {
 func RateLimitError(requested, max int) *UserError {
 	return &UserError{
 		Code:       "RATE_LIMIT_HIGH",
-		Message:    fmt.Sprintf("Rate limit too high: %d pps", requested),
-		Details:    fmt.Sprintf("Maximum safe rate is %d packets/second to avoid port exhaustion", max),
-		Suggestion: fmt.Sprintf("Use --rate %d or lower. For faster scans, increase --workers instead", max),
+		Message:    i18n.T("errors.rate_limit_high.message", requested),
+		Details:    i18n.T("errors.rate_limit_high.details", max),
+		Suggestion: i18n.T("errors.rate_limit_high.suggestion", max),
 	}
 }
 
@@ -110,9 +130,9 @@ func RateLimitError(requested, max int) *UserError {
 func NetworkError(operation string, err error) *UserError {
 	return &UserError{
 		Code:       "NETWORK_ERROR",
-		Message:    fmt.Sprintf("Network operation failed: %s", operation),
-		Details:    "Check your network connectivity and firewall settings",
-		Suggestion: "Try: 1) Test with 'ping' first, 2) Run with --verbose for details, 3) Check firewall rules",
+		Message:    i18n.T("errors.network_error.message", operation),
+		Details:    i18n.T("errors.network_error.details"),
+		Suggestion: i18n.T("errors.network_error.suggestion"),
 		WrappedErr: err,
 	}
 }
@@ -121,18 +141,90 @@ func NetworkError(operation string, err error) *UserError {
 func PermissionError(operation string) *UserError {
 	return &UserError{
 		Code:       "PERMISSION_DENIED",
-		Message:    fmt.Sprintf("Permission denied for: %s", operation),
-		Details:    "This operation requires elevated privileges",
-		Suggestion: "Try running with 'sudo' or check your user permissions",
+		Message:    i18n.T("errors.permission_denied.message", operation),
+		Details:    i18n.T("errors.permission_denied.details"),
+		Suggestion: i18n.T("errors.permission_denied.suggestion"),
+	}
+}
+
+// PrivilegeDropError creates a user error when --run-as cannot switch to
+// the requested user.
+func PrivilegeDropError(runAs string, err error) *UserError {
+	return &UserError{
+		Code:       "PRIVILEGE_DROP_FAILED",
+		Message:    i18n.T("errors.privilege_drop_failed.message", runAs),
+		Details:    i18n.T("errors.privilege_drop_failed.details"),
+		Suggestion: i18n.T("errors.privilege_drop_failed.suggestion"),
+		WrappedErr: err,
+	}
+}
+
+// OutOfScopeError creates a user error when expanded targets fall outside
+// the --scope allowlist. At most limit of the hosts are named.
+func OutOfScopeError(scopeFile string, hosts []string, limit int) *UserError {
+	named := hosts
+	if len(named) > limit {
+		named = named[:limit]
+	}
+	details := i18n.T("errors.out_of_scope.details", len(hosts), scopeFile, strings.Join(named, ", "))
+	if len(hosts) > len(named) {
+		details += i18n.T("errors.out_of_scope.more", len(hosts)-len(named))
+	}
+	return &UserError{
+		Code:       "OUT_OF_SCOPE",
+		Message:    i18n.T("errors.out_of_scope.message"),
+		Details:    details,
+		Suggestion: i18n.T("errors.out_of_scope.suggestion"),
+	}
+}
+
+// AllTargetsExcludedError creates a user error when --exclude-rdns or
+// --exclude-tag leaves no target to scan. At most limit of the hosts are
+// named.
+func AllTargetsExcludedError(hosts []string, limit int) *UserError {
+	named := hosts
+	if len(named) > limit {
+		named = named[:limit]
+	}
+	details := i18n.T("errors.all_targets_excluded.details", len(hosts), strings.Join(named, ", "))
+	if len(hosts) > len(named) {
+		details += i18n.T("errors.out_of_scope.more", len(hosts)-len(named))
+	}
+	return &UserError{
+		Code:       "ALL_TARGETS_EXCLUDED",
+		Message:    i18n.T("errors.all_targets_excluded.message"),
+		Details:    details,
+		Suggestion: i18n.T("errors.all_targets_excluded.suggestion"),
+	}
+}
+
+// ScanPolicyError creates a user error for a target rejected by the
+// localhost or private address policy; err is one of the policy errors.
+func ScanPolicyError(target string, err error) *UserError {
+	userErr := &UserError{
+		Code:       "SCAN_POLICY",
+		Message:    i18n.T("errors.scan_policy.message", target),
+		WrappedErr: err,
+	}
+	switch {
+	case stdErrors.Is(err, ErrLocalhostScanningDisabled):
+		userErr.Code = "LOCALHOST_DISABLED"
+		userErr.Details = i18n.T("errors.localhost_disabled.details")
+		userErr.Suggestion = i18n.T("errors.localhost_disabled.suggestion")
+	case stdErrors.Is(err, ErrPrivateIPScanningDisabled):
+		userErr.Code = "PRIVATE_IP_DISABLED"
+		userErr.Details = i18n.T("errors.private_ip_disabled.details")
+		userErr.Suggestion = i18n.T("errors.private_ip_disabled.suggestion")
 	}
+	return userErr
 }
 
 // TimeoutError creates a user error when an operation times out.
 func TimeoutError(timeout int) *UserError {
 	return &UserError{
 		Code:       "TIMEOUT",
-		Message:    "Operation timed out",
-		Details:    fmt.Sprintf("No response received within %dms", timeout),
-		Suggestion: fmt.Sprintf("Try increasing timeout with --timeout %d or check if target is reachable", timeout+100),
+		Message:    i18n.T("errors.timeout.message"),
+		Details:    i18n.T("errors.timeout.details", timeout),
+		Suggestion: i18n.T("errors.timeout.suggestion", timeout+100),
 	}
 }
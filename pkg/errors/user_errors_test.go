@@ -148,6 +148,23 @@ func TestInvalidPortError(t *testing.T) {
 	}
 }
 
+// locatedErr stands in for a parser error that knows its column.
+type locatedErr struct{}
+
+func (locatedErr) Error() string { return "column 4: unknown service name: htp" }
+
+func (locatedErr) Position() (string, int, string) {
+	return "22,htp", 4, "unknown service name: htp"
+}
+
+func TestInvalidPortError_Position(t *testing.T) {
+	err := InvalidPortError("22,htp", fmt.Errorf("validate: %w", locatedErr{}))
+	want := "Column 4: unknown service name: htp\n  22,htp\n     ^"
+	if err.Details != want {
+		t.Errorf("Details = %q, want %q", err.Details, want)
+	}
+}
+
 // TestNoTargetError tests no target error creation
 func TestNoTargetError(t *testing.T) {
 	err := NoTargetError()
@@ -514,6 +531,11 @@ func TestErrorConstructors_ReturnNonNil(t *testing.T) {
 		{"RateLimitError", RateLimitError(100, 50)},
 		{"NetworkError", NetworkError("test", errors.New("test"))},
 		{"PermissionError", PermissionError("test")},
+		{"PrivilegeDropError", PrivilegeDropError("nobody", errors.New("test"))},
+		{"OutOfScopeError", OutOfScopeError("scope.yaml", []string{"10.0.0.1"}, 5)},
+		{"AllTargetsExcludedError", AllTargetsExcludedError([]string{"10.0.0.1 (plc1.scada.corp)"}, 5)},
+		{"ScanPolicyError", ScanPolicyError("10.0.0.1", ErrPrivateIPScanningDisabled)},
+		{"UnknownCodeError", UnknownCodeError("NOPE")},
 		{"TimeoutError", TimeoutError(100)},
 	}
 
@@ -0,0 +1,120 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/services"
+)
+
+// announceStep is the share of the scan, in percent, between progress
+// announcements.
+const announceStep = 25
+
+// AnnounceExporter narrates a scan as plain sentences for terminal screen
+// readers: one line per open port as it is found, a progress line at each
+// quarter of the scan and when it pauses or resumes, and a summary at the
+// end. Closed and filtered ports are only counted, and nothing is redrawn
+// or colored, so each line is read out once.
+type AnnounceExporter struct {
+	writer    io.Writer
+	announced int // last progress percentage announced
+	paused    bool
+	total     int
+	completed int
+	errors    int
+	counts    map[core.ScanState]int
+	writeErr  error
+}
+
+// NewAnnounceExporter creates an announcement exporter that writes to w.
+func NewAnnounceExporter(w io.Writer) *AnnounceExporter {
+	return &AnnounceExporter{writer: w, counts: make(map[core.ScanState]int)}
+}
+
+func (e *AnnounceExporter) say(format string, args ...interface{}) {
+	if e.writeErr != nil {
+		return
+	}
+	if _, err := fmt.Fprintf(e.writer, format+"\n", args...); err != nil {
+		e.writeErr = err
+	}
+}
+
+// Export announces open ports and progress until the channel is closed.
+func (e *AnnounceExporter) Export(events <-chan core.Event) {
+	for event := range events {
+		switch event.Kind {
+		case core.EventKindResult:
+			e.counts[event.Result.State]++
+			if event.Result.State == core.StateOpen {
+				e.say("%s", AnnounceResult(*event.Result))
+			}
+		case core.EventKindProgress:
+			e.progress(*event.Progress)
+		}
+	}
+}
+
+// AnnounceResult describes a result in one sentence, such as "Open: host
+// 10.0.0.5 port 22 ssh, latency 4ms".
+func AnnounceResult(r core.ResultEvent) string {
+	var b strings.Builder
+	state := string(r.State)
+	if state != "" {
+		state = strings.ToUpper(state[:1]) + state[1:]
+	}
+	fmt.Fprintf(&b, "%s: host %s", state, r.Host)
+	if protocol := resultProtocol(r); protocol != "tcp" {
+		fmt.Fprintf(&b, " %s", strings.ToUpper(protocol))
+	}
+	fmt.Fprintf(&b, " port %d", r.Port)
+	if name := services.GetName(r.Port); name != "" && name != "unknown" {
+		fmt.Fprintf(&b, " %s", name)
+	}
+	fmt.Fprintf(&b, ", latency %dms", r.Duration.Milliseconds())
+	if banner := flattenBanner(r.Banner); banner != "" {
+		fmt.Fprintf(&b, ", banner %s", banner)
+	}
+	return b.String()
+}
+
+// progress announces pausing and resuming, and each announceStep percent
+// of the scan completed.
+func (e *AnnounceExporter) progress(p core.ProgressEvent) {
+	e.total, e.completed, e.errors = p.Total, p.Completed, p.Errors
+	if p.Paused != e.paused {
+		e.paused = p.Paused
+		if p.Paused {
+			e.say("Scan paused.")
+		} else {
+			e.say("Scan resumed.")
+		}
+	}
+	if p.Total == 0 || p.Completed >= p.Total {
+		return
+	}
+	percent := p.Completed * 100 / p.Total / announceStep * announceStep
+	if percent <= e.announced {
+		return
+	}
+	e.announced = percent
+	e.say("Progress: %d percent, %d of %d probes, %d open.", percent, p.Completed, p.Total, e.counts[core.StateOpen])
+}
+
+// Close announces the summary and returns any write error.
+func (e *AnnounceExporter) Close() error {
+	status := "Scan complete"
+	if e.total > 0 && e.completed < e.total {
+		status = "Scan stopped"
+	}
+	summary := fmt.Sprintf("%s: %d open, %d closed, %d filtered", status,
+		e.counts[core.StateOpen], e.counts[core.StateClosed], e.counts[core.StateFiltered])
+	if e.errors > 0 {
+		summary += fmt.Sprintf(", %d errors", e.errors)
+	}
+	e.say("%s.", summary)
+	return e.writeErr
+}
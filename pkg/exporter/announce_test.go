@@ -0,0 +1,64 @@
+package exporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+func TestAnnounceExporter(t *testing.T) {
+	var out bytes.Buffer
+	exp := NewAnnounceExporter(&out)
+
+	ch := make(chan core.Event, 10)
+	ch <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.5", Port: 22, State: core.StateOpen, Duration: 4 * time.Millisecond})
+	ch <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.5", Port: 23, State: core.StateClosed})
+	ch <- core.NewProgressEvent(core.ProgressEvent{Total: 8, Completed: 2})
+	ch <- core.NewProgressEvent(core.ProgressEvent{Total: 8, Completed: 3})
+	ch <- core.NewProgressEvent(core.ProgressEvent{Total: 8, Completed: 3, Paused: true})
+	ch <- core.NewProgressEvent(core.ProgressEvent{Total: 8, Completed: 3})
+	ch <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.6", Port: 53, Protocol: "udp", State: core.StateOpen, Banner: "ver\r\n 1.0"})
+	ch <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.6", Port: 54, Protocol: "udp", State: core.StateFiltered})
+	ch <- core.NewProgressEvent(core.ProgressEvent{Total: 8, Completed: 8, Errors: 1})
+	close(ch)
+
+	exp.Export(ch)
+	if err := exp.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	want := []string{
+		"Open: host 10.0.0.5 port 22 ssh, latency 4ms",
+		"Progress: 25 percent, 2 of 8 probes, 1 open.",
+		"Scan paused.",
+		"Scan resumed.",
+		"Open: host 10.0.0.6 UDP port 53 dns, latency 0ms, banner ver 1.0",
+		"Scan complete: 2 open, 1 closed, 1 filtered, 1 errors.",
+	}
+	got := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("announcements:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if strings.ContainsAny(out.String(), "\r\x1b") {
+		t.Error("announcements must not redraw or style the line")
+	}
+}
+
+func TestAnnounceExporterStopped(t *testing.T) {
+	var out bytes.Buffer
+	exp := NewAnnounceExporter(&out)
+	ch := make(chan core.Event, 1)
+	ch <- core.NewProgressEvent(core.ProgressEvent{Total: 100, Completed: 10})
+	close(ch)
+
+	exp.Export(ch)
+	if err := exp.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if out.String() != "Scan stopped: 0 open, 0 closed, 0 filtered.\n" {
+		t.Errorf("got %q", out.String())
+	}
+}
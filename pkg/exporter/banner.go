@@ -0,0 +1,61 @@
+package exporter
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Banner encodings for JSON output.
+const (
+	BannerEncodingText   = "text"
+	BannerEncodingBase64 = "base64"
+)
+
+// EscapeBanner makes a raw banner safe to print on one line. Printable text
+// is kept, backslashes are doubled, and control characters and invalid
+// UTF-8 become \r, \n, \t, or \xNN escapes, so the original bytes can be
+// recovered.
+func EscapeBanner(banner string) string {
+	var b strings.Builder
+	b.Grow(len(banner))
+	for i := 0; i < len(banner); {
+		r, size := utf8.DecodeRuneInString(banner[i:])
+		switch {
+		case r == utf8.RuneError && size <= 1:
+			fmt.Fprintf(&b, `\x%02x`, banner[i])
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case unicode.IsPrint(r):
+			b.WriteRune(r)
+		default:
+			for j := 0; j < size; j++ {
+				fmt.Fprintf(&b, `\x%02x`, banner[i+j])
+			}
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// InlineBanner collapses whitespace runs to single spaces and escapes the
+// rest, for cells that must stay on one line.
+func InlineBanner(banner string) string {
+	return EscapeBanner(strings.Join(strings.Fields(banner), " "))
+}
+
+// encodeBanner returns the banner in the requested JSON encoding.
+func encodeBanner(banner, encoding string) string {
+	if encoding == BannerEncodingBase64 {
+		return base64.StdEncoding.EncodeToString([]byte(banner))
+	}
+	return banner
+}
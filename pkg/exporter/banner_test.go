@@ -0,0 +1,68 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+func TestEscapeBanner(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"SSH-2.0-OpenSSH_9.6", "SSH-2.0-OpenSSH_9.6"},
+		{"HTTP/1.1 200 OK\r\n", `HTTP/1.1 200 OK\r\n`},
+		{"a\\b\tc", `a\\b\tc`},
+		{"\x00\x01\xff", `\x00\x01\xff`},
+		{"héllo\x1b[31m", `héllo\x1b[31m`},
+	}
+	for _, tt := range tests {
+		if got := EscapeBanner(tt.in); got != tt.want {
+			t.Errorf("EscapeBanner(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestInlineBanner(t *testing.T) {
+	if got := InlineBanner("220 mail\r\n  ready\x00"); got != `220 mail ready\x00` {
+		t.Errorf("InlineBanner = %q", got)
+	}
+}
+
+func TestCSVExporterEscapesBinaryBanner(t *testing.T) {
+	var buf bytes.Buffer
+	exp := NewCSVExporter(&buf)
+	if err := WriteResults(exp, []core.ResultEvent{{Host: "h", Port: 1, State: core.StateOpen, Banner: "\x16\x03\x01\r\nhi"}}); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+	if !strings.Contains(buf.String(), `\x16\x03\x01\r\nhi`) {
+		t.Errorf("CSV banner not hex-escaped:\n%q", buf.String())
+	}
+}
+
+func TestJSONExporterBase64Banner(t *testing.T) {
+	raw := "\x00\xffbinary"
+	var buf bytes.Buffer
+	exp := NewJSONExporter(&buf)
+	exp.SetBannerEncoding(BannerEncodingBase64)
+	if err := WriteResults(exp, []core.ResultEvent{{Host: "h", Port: 1, State: core.StateOpen, Banner: raw}}); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+
+	var dto map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &dto); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if dto["banner_encoding"] != BannerEncodingBase64 {
+		t.Errorf("banner_encoding = %v, want base64", dto["banner_encoding"])
+	}
+	decoded, err := base64.StdEncoding.DecodeString(dto["banner"].(string))
+	if err != nil || string(decoded) != raw {
+		t.Errorf("banner round trip = %q, %v; want %q", decoded, err, raw)
+	}
+}
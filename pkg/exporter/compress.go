@@ -0,0 +1,94 @@
+package exporter
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported compression names.
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+// compressor wraps a writer in a streaming compression format.
+type compressor struct {
+	extension string
+	mediaType string
+	wrap      func(io.Writer) (io.WriteCloser, error)
+}
+
+// compressors lists the available formats by name. New formats only need
+// an entry here to work with every exporter and output file.
+var compressors = map[string]compressor{
+	CompressionGzip: {
+		extension: ".gz",
+		mediaType: "application/gzip",
+		wrap:      func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+	},
+	CompressionZstd: {
+		extension: ".zst",
+		mediaType: "application/zstd",
+		wrap: func(w io.Writer) (io.WriteCloser, error) {
+			// A single encoder goroutine keeps memory flat on long streams.
+			return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+		},
+	},
+}
+
+// Compressions returns the supported compression names.
+func Compressions() []string {
+	names := make([]string, 0, len(compressors))
+	for name := range compressors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewCompressWriter wraps w so everything written is compressed as it
+// streams, without buffering whole exports in memory. Closing the returned
+// writer flushes the compressed stream but leaves w open. An empty name or
+// "none" returns a pass-through writer.
+func NewCompressWriter(w io.Writer, name string) (io.WriteCloser, error) {
+	if name == "" || name == CompressionNone {
+		return nopWriteCloser{w}, nil
+	}
+	c, ok := compressors[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported compression: %s (supported: %s)", name, strings.Join(Compressions(), ", "))
+	}
+	return c.wrap(w)
+}
+
+// CompressionExtension returns the file suffix for a compression, such as
+// ".gz", or "" when uncompressed.
+func CompressionExtension(name string) string {
+	return compressors[strings.ToLower(name)].extension
+}
+
+// CompressionMediaType returns the content type of a compressed stream,
+// such as "application/gzip", or "" when uncompressed.
+func CompressionMediaType(name string) string {
+	return compressors[strings.ToLower(name)].mediaType
+}
+
+// CompressionForPath infers the compression from a file name's suffix.
+func CompressionForPath(path string) string {
+	for name, c := range compressors {
+		if strings.HasSuffix(path, c.extension) {
+			return name
+		}
+	}
+	return ""
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,126 @@
+package exporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+func TestNewCompressWriterGzipStreams(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewCompressWriter(&buf, CompressionGzip)
+	if err != nil {
+		t.Fatalf("NewCompressWriter: %v", err)
+	}
+	exp := NewCSVExporter(w)
+	if err := WriteResults(exp, []core.ResultEvent{{Host: "h", Port: 22, State: core.StateOpen}}); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("output is not gzip: %v", err)
+	}
+	data, _ := io.ReadAll(zr)
+	if !strings.Contains(string(data), "h,22,open") {
+		t.Errorf("decompressed CSV = %q", data)
+	}
+}
+
+func TestNewCompressWriterZstdStreams(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewCompressWriter(&buf, CompressionZstd)
+	if err != nil {
+		t.Fatalf("NewCompressWriter: %v", err)
+	}
+	exp := NewCSVExporter(w)
+	if err := WriteResults(exp, []core.ResultEvent{{Host: "h", Port: 22, State: core.StateOpen}}); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	zr, err := zstd.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	defer zr.Close()
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("output is not zstd: %v", err)
+	}
+	if !strings.Contains(string(data), "h,22,open") {
+		t.Errorf("decompressed CSV = %q", data)
+	}
+}
+
+func TestNewCompressWriterNone(t *testing.T) {
+	for _, name := range []string{"", CompressionNone} {
+		var buf bytes.Buffer
+		w, err := NewCompressWriter(&buf, name)
+		if err != nil {
+			t.Fatalf("NewCompressWriter(%q): %v", name, err)
+		}
+		_, _ = io.WriteString(w, "plain")
+		_ = w.Close()
+		if buf.String() != "plain" {
+			t.Errorf("NewCompressWriter(%q) altered output: %q", name, buf.String())
+		}
+	}
+}
+
+func TestNewCompressWriterUnsupported(t *testing.T) {
+	if _, err := NewCompressWriter(io.Discard, "lz4"); err == nil || !strings.Contains(err.Error(), "gzip") {
+		t.Errorf("expected an error listing supported formats, got %v", err)
+	}
+}
+
+func TestCompressionForPath(t *testing.T) {
+	if got := CompressionForPath("scan.ndjson.gz"); got != CompressionGzip {
+		t.Errorf("CompressionForPath(.gz) = %q", got)
+	}
+	if got := CompressionForPath("scan.ndjson"); got != "" {
+		t.Errorf("CompressionForPath(.ndjson) = %q, want none", got)
+	}
+	if got := CompressionForPath("scan.ndjson.zst"); got != CompressionZstd {
+		t.Errorf("CompressionForPath(.zst) = %q", got)
+	}
+	if got := CompressionExtension(CompressionGzip); got != ".gz" {
+		t.Errorf("CompressionExtension(gzip) = %q", got)
+	}
+	if got := CompressionMediaType(CompressionZstd); got != "application/zstd" {
+		t.Errorf("CompressionMediaType(zstd) = %q", got)
+	}
+}
+
+func TestFileWriterExplicitCompression(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.ndjson")
+	f, err := CreateFile(path, FileOptions{Compression: CompressionGzip})
+	if err != nil {
+		t.Fatalf("CreateFile: %v", err)
+	}
+	_, _ = io.WriteString(f, "line\n")
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		t.Errorf("file is not gzip-compressed: %q", data)
+	}
+
+	if _, err := CreateFile(path, FileOptions{Compression: "lz4"}); err == nil {
+		t.Error("CreateFile should reject unsupported compression")
+	}
+}
@@ -25,7 +25,7 @@ type CSVExporter struct {
 func NewCSVExporter(w io.Writer) *CSVExporter {
 	csvWriter := csv.NewWriter(w)
 	// Write header
-	_ = csvWriter.Write([]string{"host", "port", "state", "banner", "latency_ms"})
+	_ = csvWriter.Write([]string{"host", "port", "state", "banner", "latency_ms", "protocol"})
 	return &CSVExporter{
 		writer:    w,
 		csvWriter: csvWriter,
@@ -69,8 +69,9 @@ func (e *CSVExporter) Export(events <-chan core.Event) {
 			sanitizeCSVField(r.Host),
 			fmt.Sprintf("%d", r.Port),
 			sanitizeCSVField(string(r.State)),
-			sanitizeCSVField(r.Banner),
+			sanitizeCSVField(EscapeBanner(r.Banner)),
 			fmt.Sprintf("%d", r.Duration.Milliseconds()),
+			resultProtocol(r),
 		}
 		if err := e.csvWriter.Write(record); err != nil {
 			e.writeErr = err
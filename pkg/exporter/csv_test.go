@@ -247,8 +247,8 @@ func TestCSVExporter_Export(t *testing.T) {
 				},
 			},
 			expected: []string{
-				"host,port,state,banner,latency_ms",
-				"192.168.1.1,22,open,SSH-2.0-OpenSSH_8.2,10",
+				"host,port,state,banner,latency_ms,protocol",
+				"192.168.1.1,22,open,SSH-2.0-OpenSSH_8.2,10,tcp",
 			},
 		},
 		{
@@ -276,9 +276,9 @@ func TestCSVExporter_Export(t *testing.T) {
 				},
 			},
 			expected: []string{
-				"host,port,state,banner,latency_ms",
-				"10.0.0.1,80,open,HTTP/1.1,5",
-				"10.0.0.1,443,open,HTTPS,8",
+				"host,port,state,banner,latency_ms,protocol",
+				"10.0.0.1,80,open,HTTP/1.1,5,tcp",
+				"10.0.0.1,443,open,HTTPS,8,tcp",
 			},
 		},
 		{
@@ -299,8 +299,8 @@ func TestCSVExporter_Export(t *testing.T) {
 				},
 			},
 			expected: []string{
-				"host,port,state,banner,latency_ms",
-				"test.com,25,open,SMTP,15",
+				"host,port,state,banner,latency_ms,protocol",
+				"test.com,25,open,SMTP,15,tcp",
 			},
 		},
 		{
@@ -318,8 +318,8 @@ func TestCSVExporter_Export(t *testing.T) {
 				},
 			},
 			expected: []string{
-				"host,port,state,banner,latency_ms",
-				"example.com,8080,closed,,2",
+				"host,port,state,banner,latency_ms,protocol",
+				"example.com,8080,closed,,2,tcp",
 			},
 		},
 	}
@@ -43,6 +43,43 @@
 //	host,port,protocol,state,service,banner,latency_ms
 //	192.168.1.1,22,tcp,open,ssh,"SSH-2.0-OpenSSH_8.9p1",5.23
 //
+// 5. Plain Table
+//
+// Aligned text columns streamed as results arrive, with optional state colors
+// (used by --output table):
+//
+//	HOST                    PORT  PROTO STATE    SERVICE       LATENCY  BANNER
+//	192.168.1.1             22    tcp   open     ssh           5ms      SSH-2.0-OpenSSH_8.9p1
+//
+// 6. Elasticsearch / OpenSearch
+//
+// ElasticExporter sends the NDJSON documents, plus an @timestamp, to an
+// index through the bulk API in batches, retrying throttled requests with
+// exponential backoff (used by --output elastic).
+//
+// 7. Syslog / CEF
+//
+// SyslogExporter sends one RFC 5424 message per open port, or a CEF record
+// for SIEM ingestion (used by --output syslog):
+//
+//	<133>1 2024-05-01T12:00:00Z scanner01 portscan 4242 open-port [portscan@32473 host="192.168.1.1" port="22" ...] Open port 22/tcp on 192.168.1.1 (ssh)
+//
+// 8. Kafka / NATS
+//
+// PublishExporter publishes each result as a JSON document through a
+// Publisher, KafkaPublisher or NATSPublisher, resending unacknowledged
+// messages for at-least-once delivery (used by --output kafka and nats).
+//
+// 9. Asset Inventory
+//
+// InventoryExporter aggregates results per host instead of per port: open
+// ports, services, a best-effort OS guess from banners and well-known
+// ports, and when the host was first and last seen, written as JSON or CSV
+// once the scan ends (used by --output inventory):
+//
+//	host,hostname,open_ports,services,os_guess,edge,device_name,device_type,first_seen,last_seen,tags
+//	192.168.1.1,,22/tcp;80/tcp,ssh;http,Linux (Ubuntu),,nas,,2024-05-01T12:00:00Z,2024-05-01T12:00:02Z,
+//
 // Example Usage:
 //
 //	// Create JSON exporter (NDJSON mode)
@@ -0,0 +1,202 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+// fakeBulkServer records bulk requests and answers them with the responses
+// queued in replies; once those run out every document is accepted.
+type fakeBulkServer struct {
+	mu        sync.Mutex
+	templates []string
+	bulks     [][]string
+	replies   []func(w http.ResponseWriter, lines []string)
+	auth      string
+}
+
+func (f *fakeBulkServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.auth = r.Header.Get("Authorization")
+	body, _ := io.ReadAll(r.Body)
+
+	switch {
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/_index_template/"):
+		f.templates = append(f.templates, string(body))
+		_, _ = w.Write([]byte(`{"acknowledged":true}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/_bulk":
+		var lines []string
+		sc := bufio.NewScanner(strings.NewReader(string(body)))
+		for sc.Scan() {
+			lines = append(lines, sc.Text())
+		}
+		f.bulks = append(f.bulks, lines)
+		if len(f.replies) > 0 {
+			reply := f.replies[0]
+			f.replies = f.replies[1:]
+			reply(w, lines)
+			return
+		}
+		_, _ = w.Write([]byte(`{"errors":false,"items":[]}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestElasticExporter(t *testing.T, srv *httptest.Server, opts ElasticOptions) *ElasticExporter {
+	t.Helper()
+	opts.URL = srv.URL
+	exp, err := NewElasticExporter(opts)
+	if err != nil {
+		t.Fatalf("NewElasticExporter: %v", err)
+	}
+	exp.now = func() time.Time { return time.Date(2024, 3, 9, 23, 30, 0, 0, time.UTC) }
+	exp.sleep = func(time.Duration) {}
+	return exp
+}
+
+func elasticResults(n int) []core.ResultEvent {
+	results := make([]core.ResultEvent, n)
+	for i := range results {
+		results[i] = core.ResultEvent{Host: "10.0.0.1", Port: uint16(20 + i), State: core.StateOpen, Duration: 3 * time.Millisecond}
+	}
+	return results
+}
+
+func TestElasticExporterBulk(t *testing.T) {
+	fake := &fakeBulkServer{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	exp := newTestElasticExporter(t, srv, ElasticOptions{TemplateName: "portscan", BatchSize: 2, APIKey: "secret"})
+	if err := WriteResults(exp, elasticResults(3)); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	if len(fake.templates) != 1 || !strings.Contains(fake.templates[0], `"portscan-*"`) {
+		t.Fatalf("templates = %v, want one for portscan-*", fake.templates)
+	}
+	if len(fake.bulks) != 2 || len(fake.bulks[0]) != 4 || len(fake.bulks[1]) != 2 {
+		t.Fatalf("bulk requests = %v, want batches of 2 and 1 documents", fake.bulks)
+	}
+	if fake.bulks[0][0] != `{"index":{"_index":"portscan-2024.03.09"}}` {
+		t.Errorf("action line = %s", fake.bulks[0][0])
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(fake.bulks[0][1]), &doc); err != nil {
+		t.Fatalf("document: %v", err)
+	}
+	if doc["port"] != float64(20) || doc["state"] != "open" || doc["@timestamp"] != "2024-03-09T23:30:00Z" {
+		t.Errorf("document = %v", doc)
+	}
+	if fake.auth != "ApiKey secret" {
+		t.Errorf("Authorization = %q", fake.auth)
+	}
+	if exp.Indexed() != 3 {
+		t.Errorf("Indexed() = %d, want 3", exp.Indexed())
+	}
+}
+
+func TestElasticExporterRetriesThrottledDocuments(t *testing.T) {
+	fake := &fakeBulkServer{replies: []func(http.ResponseWriter, []string){
+		func(w http.ResponseWriter, _ []string) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		},
+		func(w http.ResponseWriter, _ []string) {
+			_, _ = w.Write([]byte(`{"errors":true,"items":[{"index":{"status":201}},{"index":{"status":429,"error":{"type":"es_rejected_execution_exception"}}}]}`))
+		},
+	}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	exp := newTestElasticExporter(t, srv, ElasticOptions{})
+	if err := WriteResults(exp, elasticResults(2)); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if len(fake.bulks) != 3 {
+		t.Fatalf("bulk requests = %d, want 3", len(fake.bulks))
+	}
+	if len(fake.bulks[2]) != 2 || !strings.Contains(fake.bulks[2][1], `"port":21`) {
+		t.Errorf("retry should resend only the throttled document, got %v", fake.bulks[2])
+	}
+	if len(fake.templates) != 0 {
+		t.Errorf("no template should be installed without a name")
+	}
+	if exp.Indexed() != 2 {
+		t.Errorf("Indexed() = %d, want 2", exp.Indexed())
+	}
+}
+
+func TestElasticExporterPermanentFailure(t *testing.T) {
+	fake := &fakeBulkServer{replies: []func(http.ResponseWriter, []string){
+		func(w http.ResponseWriter, _ []string) {
+			_, _ = w.Write([]byte(`{"errors":true,"items":[{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"bad port"}}}]}`))
+		},
+	}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	exp := newTestElasticExporter(t, srv, ElasticOptions{BatchSize: 1})
+	err := WriteResults(exp, elasticResults(3))
+	if err == nil || !strings.Contains(err.Error(), "mapper_parsing_exception") {
+		t.Fatalf("err = %v, want the rejection reason", err)
+	}
+	if len(fake.bulks) != 1 {
+		t.Errorf("bulk requests = %d, want results after a permanent failure dropped", len(fake.bulks))
+	}
+}
+
+func TestElasticExporterGivesUp(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	exp := newTestElasticExporter(t, srv, ElasticOptions{MaxRetries: 2})
+	var waits []time.Duration
+	exp.sleep = func(d time.Duration) { waits = append(waits, d) }
+
+	if err := WriteResults(exp, elasticResults(1)); err == nil {
+		t.Fatal("expected an error after retries are exhausted")
+	}
+	if len(waits) != 2 || waits[0] != DefaultElasticBackoff || waits[1] != 2*DefaultElasticBackoff {
+		t.Errorf("backoff waits = %v, want exponential from %v", waits, DefaultElasticBackoff)
+	}
+}
+
+func TestNewElasticExporterRejectsBadURL(t *testing.T) {
+	for _, u := range []string{"", "localhost:9200", "ftp://host"} {
+		if _, err := NewElasticExporter(ElasticOptions{URL: u}); err == nil {
+			t.Errorf("NewElasticExporter(%q) should fail", u)
+		}
+	}
+}
+
+func TestExpandIndexPattern(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		pattern, want, wildcard string
+	}{
+		{"portscan-%{+yyyy.MM.dd}", "portscan-2024.01.02", "portscan-*"},
+		{"scans-%{+yy}-%{+HH}h", "scans-24-15h", "scans-*-*h"},
+		{"portscan", "portscan", "portscan"},
+	}
+	for _, tt := range tests {
+		if got := ExpandIndexPattern(tt.pattern, ts); got != tt.want {
+			t.Errorf("ExpandIndexPattern(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+		if got := IndexWildcard(tt.pattern); got != tt.wildcard {
+			t.Errorf("IndexWildcard(%q) = %q, want %q", tt.pattern, got, tt.wildcard)
+		}
+	}
+}
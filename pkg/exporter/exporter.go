@@ -0,0 +1,95 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+// Exporter is implemented by every streaming result exporter.
+type Exporter interface {
+	// Export consumes events until the channel is closed, writing results.
+	Export(events <-chan core.Event)
+	// Close flushes any buffered output and finalizes the document.
+	Close() error
+}
+
+// Ensure all exporters implement the Exporter interface
+var (
+	_ Exporter = (*JSONExporter)(nil)
+	_ Exporter = (*CSVExporter)(nil)
+	_ Exporter = (*HTMLExporter)(nil)
+	_ Exporter = (*MarkdownExporter)(nil)
+	_ Exporter = (*TableExporter)(nil)
+	_ Exporter = (*InventoryExporter)(nil)
+	_ Exporter = (*AnnounceExporter)(nil)
+)
+
+// Supported export format names.
+const (
+	FormatNDJSON   = "ndjson"
+	FormatCSV      = "csv"
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+)
+
+// Formats lists the export formats accepted by New.
+var Formats = []string{FormatNDJSON, FormatCSV, FormatHTML, FormatMarkdown}
+
+// New creates an exporter for the named format writing to w.
+// "json" and "md" are accepted as aliases for NDJSON and Markdown.
+func New(format string, w io.Writer) (Exporter, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case FormatNDJSON, "json":
+		return NewJSONExporter(w), nil
+	case FormatCSV:
+		return NewCSVExporter(w), nil
+	case FormatHTML:
+		return NewHTMLExporter(w), nil
+	case FormatMarkdown, "md":
+		return NewMarkdownExporter(w), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s (supported: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// FileExtension returns the conventional file extension for a format.
+func FileExtension(format string) string {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return ".csv"
+	case FormatHTML:
+		return ".html"
+	case FormatMarkdown, "md":
+		return ".md"
+	default:
+		return ".ndjson"
+	}
+}
+
+// resultProtocol labels a result's protocol; results without one come
+// from the TCP scanner.
+func resultProtocol(r core.ResultEvent) string {
+	if r.Protocol == "" {
+		return "tcp"
+	}
+	return r.Protocol
+}
+
+// WriteResults exports an in-memory result set through the given exporter
+// and closes it. It is used where results are already collected, such as
+// exporting the current view from the TUI.
+func WriteResults(exp Exporter, results []core.ResultEvent) error {
+	events := make(chan core.Event, 64)
+	go func() {
+		defer close(events)
+		for _, r := range results {
+			events <- core.NewResultEvent(r)
+		}
+	}()
+
+	exp.Export(events)
+	return exp.Close()
+}
@@ -0,0 +1,148 @@
+package exporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+func TestNewSelectsExporterByFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"ndjson", "*exporter.JSONExporter"},
+		{"json", "*exporter.JSONExporter"},
+		{"CSV", "*exporter.CSVExporter"},
+		{"html", "*exporter.HTMLExporter"},
+		{"markdown", "*exporter.MarkdownExporter"},
+		{"md", "*exporter.MarkdownExporter"},
+	}
+
+	for _, tt := range tests {
+		exp, err := New(tt.format, &bytes.Buffer{})
+		if err != nil {
+			t.Fatalf("New(%q) returned error: %v", tt.format, err)
+		}
+		if got := typeName(exp); got != tt.want {
+			t.Errorf("New(%q) = %s, want %s", tt.format, got, tt.want)
+		}
+	}
+
+	if _, err := New("xml", &bytes.Buffer{}); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func typeName(exp Exporter) string {
+	switch exp.(type) {
+	case *JSONExporter:
+		return "*exporter.JSONExporter"
+	case *CSVExporter:
+		return "*exporter.CSVExporter"
+	case *HTMLExporter:
+		return "*exporter.HTMLExporter"
+	case *MarkdownExporter:
+		return "*exporter.MarkdownExporter"
+	default:
+		return "unknown"
+	}
+}
+
+func TestFileExtension(t *testing.T) {
+	cases := map[string]string{
+		FormatNDJSON:   ".ndjson",
+		FormatCSV:      ".csv",
+		FormatHTML:     ".html",
+		FormatMarkdown: ".md",
+	}
+	for format, want := range cases {
+		if got := FileExtension(format); got != want {
+			t.Errorf("FileExtension(%q) = %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestWriteResultsExportsEveryResult(t *testing.T) {
+	var buf bytes.Buffer
+	results := []core.ResultEvent{
+		{Host: "10.0.0.1", Port: 22, State: core.StateOpen},
+		{Host: "10.0.0.1", Port: 80, State: core.StateClosed},
+		{Host: "10.0.0.2", Port: 443, State: core.StateFiltered},
+	}
+
+	if err := WriteResults(NewCSVExporter(&buf), results); err != nil {
+		t.Fatalf("WriteResults returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(results)+1 {
+		t.Fatalf("expected header plus %d rows, got %d lines", len(results), len(lines))
+	}
+}
+
+func TestHTMLExporterEscapesBanners(t *testing.T) {
+	var buf bytes.Buffer
+	exp := NewHTMLExporter(&buf)
+
+	ch := make(chan core.Event, 2)
+	ch <- core.NewResultEvent(core.ResultEvent{Host: "example.com", Port: 80, State: core.StateOpen, Banner: "<script>alert(1)</script>", Duration: 5 * time.Millisecond})
+	ch <- core.NewProgressEvent(core.ProgressEvent{Total: 1, Completed: 1})
+	close(ch)
+
+	exp.Export(ch)
+	if err := exp.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "<script>") {
+		t.Error("banner markup must be escaped")
+	}
+	if !strings.Contains(out, "&lt;script&gt;") {
+		t.Error("expected escaped banner in output")
+	}
+	if !strings.Contains(out, `<tr class="open">`) {
+		t.Error("expected row classed by state")
+	}
+	if !strings.HasSuffix(strings.TrimSpace(out), "</html>") {
+		t.Error("expected document to be closed")
+	}
+}
+
+func TestHTMLExporterEmptyScanProducesDocument(t *testing.T) {
+	var buf bytes.Buffer
+	exp := NewHTMLExporter(&buf)
+	if err := exp.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "<table>") || !strings.Contains(out, "</html>") {
+		t.Errorf("expected complete document for empty scan, got %q", out)
+	}
+}
+
+func TestMarkdownExporterEscapesCells(t *testing.T) {
+	var buf bytes.Buffer
+	exp := NewMarkdownExporter(&buf)
+
+	ch := make(chan core.Event, 1)
+	ch <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen, Protocol: "tcp", Banner: "a|b\nc", Duration: 3 * time.Millisecond})
+	close(ch)
+
+	exp.Export(ch)
+	if err := exp.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header, separator, and one row; got %d lines", len(lines))
+	}
+	if want := `| 10.0.0.1 | 22 | tcp | open | ssh | a\|b c | 3 |`; lines[2] != want {
+		t.Errorf("row = %q, want %q", lines[2], want)
+	}
+}
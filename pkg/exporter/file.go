@@ -0,0 +1,151 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultRotateKeep is how many rotated files FileWriter keeps by default.
+const DefaultRotateKeep = 5
+
+// FileOptions configures a FileWriter.
+type FileOptions struct {
+	// MaxBytes rotates the file once this many uncompressed bytes have been
+	// written. Rotation only happens at line boundaries. Zero disables it.
+	MaxBytes int64
+	// Keep is how many rotated files to retain; defaults to DefaultRotateKeep.
+	Keep int
+	// Compression names the stream compression; when empty it is inferred
+	// from the path, so names ending in .gz are gzip-compressed and names
+	// ending in .zst zstd-compressed.
+	Compression string
+}
+
+// FileWriter writes export output to a file atomically: data goes to a
+// temporary file in the same directory that is renamed into place on
+// Close, so readers never see a half-written export. Output is compressed
+// per FileOptions.Compression. With MaxBytes set, full files are rotated to
+// numbered siblings (results.1.ndjson, results.2.ndjson, ...) in the style
+// of logrotate, and the newest data stays at the original path.
+type FileWriter struct {
+	path    string
+	opts    FileOptions
+	tmp     *os.File
+	w       io.WriteCloser // tmp, possibly wrapped in a compressor
+	written int64
+	rotated bool
+}
+
+// CreateFile opens a FileWriter for path.
+func CreateFile(path string, opts FileOptions) (*FileWriter, error) {
+	if opts.Keep <= 0 {
+		opts.Keep = DefaultRotateKeep
+	}
+	if opts.Compression == "" {
+		opts.Compression = CompressionForPath(path)
+	}
+	f := &FileWriter{path: path, opts: opts}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *FileWriter) open() error {
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), "."+filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create output file: %w", err)
+	}
+	f.tmp = tmp
+	f.w, err = NewCompressWriter(tmp, f.opts.Compression)
+	if err != nil {
+		f.Abort()
+		return err
+	}
+	f.written = 0
+	return nil
+}
+
+// Write implements io.Writer. When the size limit has been reached and p
+// ends a line, the file is rotated after the write.
+func (f *FileWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	f.written += int64(n)
+	if err != nil {
+		return n, err
+	}
+	if f.opts.MaxBytes > 0 && f.written >= f.opts.MaxBytes && bytes.HasSuffix(p, []byte("\n")) {
+		if err := f.rotate(); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// rotate commits the current file, shifts older ones up, and starts a new
+// temporary file.
+func (f *FileWriter) rotate() error {
+	if err := f.finish(); err != nil {
+		return err
+	}
+	for i := f.opts.Keep - 1; i >= 1; i-- {
+		_ = os.Rename(rotatedName(f.path, i), rotatedName(f.path, i+1))
+	}
+	if err := os.Rename(f.path, rotatedName(f.path, 1)); err != nil {
+		return fmt.Errorf("rotate output file: %w", err)
+	}
+	f.rotated = true
+	return f.open()
+}
+
+// finish flushes and closes the temporary file and renames it to path.
+func (f *FileWriter) finish() error {
+	if err := f.w.Close(); err != nil {
+		f.Abort()
+		return fmt.Errorf("compress output file: %w", err)
+	}
+	if err := f.tmp.Sync(); err != nil {
+		f.Abort()
+		return fmt.Errorf("sync output file: %w", err)
+	}
+	if err := f.tmp.Close(); err != nil {
+		_ = os.Remove(f.tmp.Name())
+		return fmt.Errorf("close output file: %w", err)
+	}
+	if err := os.Rename(f.tmp.Name(), f.path); err != nil {
+		_ = os.Remove(f.tmp.Name())
+		return fmt.Errorf("write output file: %w", err)
+	}
+	return nil
+}
+
+// Close commits the output to its final path. An empty file left over
+// from a rotation on the last write is discarded.
+func (f *FileWriter) Close() error {
+	if f.rotated && f.written == 0 {
+		f.Abort()
+		return nil
+	}
+	return f.finish()
+}
+
+// Abort discards the output written since the last rotation.
+func (f *FileWriter) Abort() {
+	_ = f.tmp.Close()
+	_ = os.Remove(f.tmp.Name())
+}
+
+// rotatedName numbers path before its extension, keeping a trailing
+// compression suffix: results.ndjson.gz becomes results.1.ndjson.gz.
+func rotatedName(path string, n int) string {
+	dir, base := filepath.Split(path)
+	suffix := CompressionExtension(CompressionForPath(base))
+	base = strings.TrimSuffix(base, suffix)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	return filepath.Join(dir, fmt.Sprintf("%s.%d%s%s", stem, n, ext, suffix))
+}
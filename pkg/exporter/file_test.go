@@ -0,0 +1,125 @@
+package exporter
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileWriterAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.ndjson")
+	f, err := CreateFile(path, FileOptions{})
+	if err != nil {
+		t.Fatalf("CreateFile: %v", err)
+	}
+	if _, err := io.WriteString(f, "{\"port\":22}\n"); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("output should not appear before Close")
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "{\"port\":22}\n" {
+		t.Fatalf("output = %q, %v", data, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestFileWriterAbort(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.ndjson")
+	f, err := CreateFile(path, FileOptions{})
+	if err != nil {
+		t.Fatalf("CreateFile: %v", err)
+	}
+	_, _ = io.WriteString(f, "partial")
+	f.Abort()
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Abort should leave nothing behind, found %v", entries)
+	}
+}
+
+func TestFileWriterGzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.ndjson.gz")
+	f, err := CreateFile(path, FileOptions{})
+	if err != nil {
+		t.Fatalf("CreateFile: %v", err)
+	}
+	_, _ = io.WriteString(f, "line\n")
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("output is not gzip: %v", err)
+	}
+	data, _ := io.ReadAll(zr)
+	if string(data) != "line\n" {
+		t.Errorf("decompressed = %q", data)
+	}
+}
+
+func TestFileWriterRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.ndjson")
+	f, err := CreateFile(path, FileOptions{MaxBytes: 10, Keep: 2})
+	if err != nil {
+		t.Fatalf("CreateFile: %v", err)
+	}
+	for _, line := range []string{"first-line\n", "second-line\n", "third-line\n", "last\n"} {
+		if _, err := io.WriteString(f, line); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	want := map[string]string{
+		"results.ndjson":   "last\n",
+		"results.1.ndjson": "third-line\n",
+		"results.2.ndjson": "second-line\n",
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != len(want) {
+		t.Errorf("files = %v, want %d (oldest dropped beyond Keep)", entries, len(want))
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(data) != content {
+			t.Errorf("%s = %q, %v; want %q", name, data, err, content)
+		}
+	}
+}
+
+func TestRotatedName(t *testing.T) {
+	tests := map[string]string{
+		"out/results.ndjson":    filepath.Join("out", "results.3.ndjson"),
+		"out/results.ndjson.gz": filepath.Join("out", "results.3.ndjson.gz"),
+		"results":               "results.3",
+	}
+	for path, want := range tests {
+		if got := rotatedName(path, 3); got != want {
+			t.Errorf("rotatedName(%q) = %q, want %q", path, got, want)
+		}
+	}
+	if !strings.HasSuffix(rotatedName("a.csv.gz", 1), ".csv.gz") {
+		t.Error("rotated names should keep the compressed extension")
+	}
+}
@@ -0,0 +1,112 @@
+package exporter
+
+import (
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/services"
+)
+
+var htmlHeader = template.Must(template.New("header").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Port Scan Results</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #f0f0f0; }
+tr.open td.state { color: #1a7f37; font-weight: bold; }
+tr.closed td.state { color: #cf222e; }
+tr.filtered td.state { color: #bf8700; }
+</style>
+</head>
+<body>
+<h1>Port Scan Results</h1>
+<p>Generated {{.}}</p>
+<table>
+<thead><tr><th>Host</th><th>Port</th><th>Protocol</th><th>State</th><th>Service</th><th>Banner</th><th>Latency (ms)</th></tr></thead>
+<tbody>
+`))
+
+var htmlRow = template.Must(template.New("row").Parse(
+	`<tr class="{{.State}}"><td>{{.Host}}</td><td>{{.Port}}</td><td>{{.Protocol}}</td><td class="state">{{.State}}</td><td>{{.Service}}</td><td>{{.Banner}}</td><td>{{.LatencyMs}}</td></tr>
+`))
+
+const htmlFooter = `</tbody>
+</table>
+</body>
+</html>
+`
+
+// htmlRowData is the template view of a single result row.
+type htmlRowData struct {
+	Host      string
+	Port      uint16
+	Protocol  string
+	State     string
+	Service   string
+	Banner    string
+	LatencyMs int64
+}
+
+// HTMLExporter exports scan results as a standalone HTML report.
+type HTMLExporter struct {
+	writer        io.Writer
+	headerWritten bool
+	writeErr      error
+}
+
+// NewHTMLExporter creates a new HTML exporter that writes to the given writer.
+func NewHTMLExporter(w io.Writer) *HTMLExporter {
+	return &HTMLExporter{writer: w}
+}
+
+func (e *HTMLExporter) writeHeader() {
+	if e.headerWritten {
+		return
+	}
+	e.headerWritten = true
+	if err := htmlHeader.Execute(e.writer, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		e.writeErr = err
+	}
+}
+
+// Export writes scan result events as HTML table rows. All values are
+// escaped by html/template, so banners cannot inject markup.
+func (e *HTMLExporter) Export(events <-chan core.Event) {
+	e.writeHeader()
+	for event := range events {
+		if event.Kind != core.EventKindResult || e.writeErr != nil {
+			continue
+		}
+
+		r := *event.Result
+		protocol := resultProtocol(r)
+		row := htmlRowData{
+			Host:      r.Host,
+			Port:      r.Port,
+			Protocol:  protocol,
+			State:     string(r.State),
+			Service:   services.GetName(r.Port),
+			Banner:    EscapeBanner(r.Banner),
+			LatencyMs: r.Duration.Milliseconds(),
+		}
+		if err := htmlRow.Execute(e.writer, row); err != nil {
+			e.writeErr = err
+		}
+	}
+}
+
+// Close writes the closing markup and returns any write error.
+func (e *HTMLExporter) Close() error {
+	e.writeHeader()
+	if e.writeErr != nil {
+		return e.writeErr
+	}
+	_, err := io.WriteString(e.writer, htmlFooter)
+	return err
+}
@@ -0,0 +1,331 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/services"
+)
+
+// Inventory output formats.
+const (
+	InventoryJSON = "json"
+	InventoryCSV  = "csv"
+)
+
+// HostInventory summarizes one responsive host: the ports found open on
+// it, the services they map to, a best-effort OS guess, the CDN or WAF in
+// front of it if one was detected, the device name and type local
+// discovery found, the organization holding its network and their abuse
+// contact, its GeoIP country and autonomous system, and when the host was first and last seen during the scan.
+type HostInventory struct {
+	Host       string    `json:"host"`
+	Hostname   string    `json:"hostname,omitempty"`
+	OpenPorts  []string  `json:"open_ports"`
+	Services   []string  `json:"services"`
+	OSGuess    string    `json:"os_guess,omitempty"`
+	Edge       string    `json:"edge,omitempty"`
+	DeviceName string    `json:"device_name,omitempty"`
+	DeviceType string    `json:"device_type,omitempty"`
+	Owner      string    `json:"owner,omitempty"`
+	Abuse      string    `json:"abuse_contact,omitempty"`
+	Country    string    `json:"country,omitempty"`
+	ASN        uint32    `json:"asn,omitempty"`
+	ASOrg      string    `json:"as_org,omitempty"`
+	FirstSeen  time.Time `json:"first_seen"`
+	LastSeen   time.Time `json:"last_seen"`
+	Tags       []string  `json:"tags,omitempty"`
+}
+
+// Inventory aggregates per-port results into per-host summaries. Hosts
+// only ever reported as filtered did not answer and are left out.
+type Inventory struct {
+	hosts map[string]*hostEntry
+}
+
+// hostEntry accumulates one host's results.
+type hostEntry struct {
+	summary    HostInventory
+	responsive bool
+	open       map[string]bool
+	ports      []openPort
+	banners    []string
+}
+
+// openPort is a port found open on a host.
+type openPort struct {
+	port     uint16
+	protocol string
+}
+
+// NewInventory returns an empty inventory.
+func NewInventory() *Inventory {
+	return &Inventory{hosts: make(map[string]*hostEntry)}
+}
+
+// Add records a result seen at the given time.
+func (inv *Inventory) Add(r core.ResultEvent, seen time.Time) {
+	entry, ok := inv.hosts[r.Host]
+	if !ok {
+		entry = &hostEntry{
+			summary: HostInventory{Host: r.Host, FirstSeen: seen},
+			open:    make(map[string]bool),
+		}
+		inv.hosts[r.Host] = entry
+	}
+	if seen.Before(entry.summary.FirstSeen) {
+		entry.summary.FirstSeen = seen
+	}
+	if seen.After(entry.summary.LastSeen) {
+		entry.summary.LastSeen = seen
+	}
+	if entry.summary.Hostname == "" {
+		entry.summary.Hostname = r.Hostname
+	}
+	if entry.summary.Edge == "" {
+		entry.summary.Edge = r.Edge
+	}
+	if entry.summary.DeviceName == "" {
+		entry.summary.DeviceName = r.DeviceName
+	}
+	if entry.summary.DeviceType == "" {
+		entry.summary.DeviceType = r.DeviceType
+	}
+	if entry.summary.Owner == "" && entry.summary.Abuse == "" && r.Owner != nil {
+		entry.summary.Owner, entry.summary.Abuse = r.Owner.Org, r.Owner.Abuse
+	}
+	if entry.summary.Country == "" {
+		entry.summary.Country = r.Country
+	}
+	if entry.summary.ASN == 0 {
+		entry.summary.ASN, entry.summary.ASOrg = r.ASN, r.ASOrg
+	}
+	for _, tag := range r.Tags {
+		if !containsTag(entry.summary.Tags, tag) {
+			entry.summary.Tags = append(entry.summary.Tags, tag)
+		}
+	}
+
+	switch r.State {
+	case core.StateOpen:
+		entry.responsive = true
+		port := fmt.Sprintf("%d/%s", r.Port, resultProtocol(r))
+		if !entry.open[port] {
+			entry.open[port] = true
+			entry.ports = append(entry.ports, openPort{port: r.Port, protocol: resultProtocol(r)})
+			if name := services.GetName(r.Port); name != "unknown" && !containsTag(entry.summary.Services, name) {
+				entry.summary.Services = append(entry.summary.Services, name)
+			}
+		}
+		if banner := strings.TrimSpace(r.Banner); banner != "" {
+			entry.banners = append(entry.banners, banner)
+		}
+	case core.StateClosed:
+		entry.responsive = true
+	}
+}
+
+// Hosts returns the responsive hosts ordered by address, with their open
+// ports in port order.
+func (inv *Inventory) Hosts() []HostInventory {
+	hosts := make([]HostInventory, 0, len(inv.hosts))
+	for _, entry := range inv.hosts {
+		if !entry.responsive {
+			continue
+		}
+		summary := entry.summary
+		ports := append([]openPort(nil), entry.ports...)
+		sort.Slice(ports, func(i, j int) bool {
+			if ports[i].port != ports[j].port {
+				return ports[i].port < ports[j].port
+			}
+			return ports[i].protocol < ports[j].protocol
+		})
+		summary.OpenPorts = make([]string, len(ports))
+		for i, p := range ports {
+			summary.OpenPorts[i] = fmt.Sprintf("%d/%s", p.port, p.protocol)
+		}
+		summary.Services = append([]string{}, summary.Services...)
+		summary.Tags = append([]string(nil), summary.Tags...)
+		summary.OSGuess = GuessOS(entry.open, entry.banners)
+		hosts = append(hosts, summary)
+	}
+	sort.Slice(hosts, func(i, j int) bool { return hostLess(hosts[i].Host, hosts[j].Host) })
+	return hosts
+}
+
+// hostLess orders IP addresses numerically, before hostnames.
+func hostLess(a, b string) bool {
+	ipA, errA := netip.ParseAddr(a)
+	ipB, errB := netip.ParseAddr(b)
+	switch {
+	case errA == nil && errB == nil:
+		return ipA.Less(ipB)
+	case errA == nil || errB == nil:
+		return errA == nil
+	default:
+		return a < b
+	}
+}
+
+func containsTag(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+// osBannerHints maps banner substrings, checked in order and ignoring
+// case, to the operating system they reveal.
+var osBannerHints = []struct {
+	match string
+	os    string
+}{
+	{"windows", "Windows"},
+	{"microsoft", "Windows"},
+	{"ubuntu", "Linux (Ubuntu)"},
+	{"debian", "Linux (Debian)"},
+	{"centos", "Linux (CentOS)"},
+	{"red hat", "Linux (Red Hat)"},
+	{"rhel", "Linux (Red Hat)"},
+	{"fedora", "Linux (Fedora)"},
+	{"alpine", "Linux (Alpine)"},
+	{"raspbian", "Linux (Raspbian)"},
+	{"freebsd", "FreeBSD"},
+	{"openbsd", "OpenBSD"},
+	{"netbsd", "NetBSD"},
+	{"routeros", "MikroTik RouterOS"},
+	{"mikrotik", "MikroTik RouterOS"},
+	{"cisco", "Cisco IOS"},
+	{"junos", "Juniper Junos"},
+	{"linux", "Linux"},
+}
+
+// windowsPorts are services that, open together, point to Windows.
+var windowsPorts = []string{"135/tcp", "139/tcp", "445/tcp", "3389/tcp"}
+
+// GuessOS makes a best-effort operating system guess from a host's open
+// "port/protocol" labels and service banners. Banners that name an OS
+// win; otherwise two or more Windows services suggest Windows, and an SSH
+// banner a Unix-like system. It returns "" when there is no evidence.
+func GuessOS(open map[string]bool, banners []string) string {
+	for _, hint := range osBannerHints {
+		for _, banner := range banners {
+			if strings.Contains(strings.ToLower(banner), hint.match) {
+				return hint.os
+			}
+		}
+	}
+	windows := 0
+	for _, port := range windowsPorts {
+		if open[port] {
+			windows++
+		}
+	}
+	if windows >= 2 {
+		return "Windows"
+	}
+	for _, banner := range banners {
+		if strings.HasPrefix(banner, "SSH-") {
+			return "Unix-like"
+		}
+	}
+	return ""
+}
+
+// InventoryExporter writes a per-host asset inventory instead of one
+// record per port. Results are aggregated as they arrive and the inventory
+// is written once the scan ends, as JSON or CSV.
+type InventoryExporter struct {
+	writer    io.Writer
+	format    string
+	inventory *Inventory
+	now       func() time.Time
+	writeErr  error
+}
+
+// NewInventoryExporter creates an inventory exporter writing format,
+// InventoryJSON or InventoryCSV, to w.
+func NewInventoryExporter(w io.Writer, format string) *InventoryExporter {
+	return &InventoryExporter{writer: w, format: format, inventory: NewInventory(), now: time.Now}
+}
+
+// Export aggregates result events and writes the inventory when the
+// channel closes.
+func (e *InventoryExporter) Export(events <-chan core.Event) {
+	for event := range events {
+		if event.Kind != core.EventKindResult || event.Result == nil {
+			continue
+		}
+		e.inventory.Add(*event.Result, e.now().UTC())
+	}
+	if e.format == InventoryCSV {
+		e.writeErr = writeInventoryCSV(e.writer, e.inventory.Hosts())
+	} else {
+		e.writeErr = writeInventoryJSON(e.writer, e.inventory.Hosts())
+	}
+}
+
+// Close returns any write error.
+func (e *InventoryExporter) Close() error {
+	return e.writeErr
+}
+
+// writeInventoryJSON writes the hosts as a JSON document.
+func writeInventoryJSON(w io.Writer, hosts []HostInventory) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(map[string]interface{}{
+		"host_count": len(hosts),
+		"hosts":      hosts,
+	})
+}
+
+// writeInventoryCSV writes one row per host; lists are joined with ';'.
+func writeInventoryCSV(w io.Writer, hosts []HostInventory) error {
+	csvWriter := csv.NewWriter(w)
+	_ = csvWriter.Write([]string{"host", "hostname", "open_ports", "services", "os_guess", "edge", "device_name", "device_type", "owner", "abuse_contact", "country", "asn", "as_org", "first_seen", "last_seen", "tags"})
+	for _, h := range hosts {
+		record := []string{
+			sanitizeCSVField(h.Host),
+			sanitizeCSVField(h.Hostname),
+			strings.Join(h.OpenPorts, ";"),
+			strings.Join(h.Services, ";"),
+			sanitizeCSVField(h.OSGuess),
+			sanitizeCSVField(h.Edge),
+			sanitizeCSVField(h.DeviceName),
+			sanitizeCSVField(h.DeviceType),
+			sanitizeCSVField(h.Owner),
+			sanitizeCSVField(h.Abuse),
+			h.Country,
+			inventoryASN(h.ASN),
+			sanitizeCSVField(h.ASOrg),
+			h.FirstSeen.Format(time.RFC3339),
+			h.LastSeen.Format(time.RFC3339),
+			sanitizeCSVField(strings.Join(h.Tags, ";")),
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// inventoryASN formats an AS number for CSV, leaving it blank when unknown.
+func inventoryASN(asn uint32) string {
+	if asn == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(asn), 10)
+}
@@ -0,0 +1,117 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+func TestInventoryAggregatesPerHost(t *testing.T) {
+	inv := NewInventory()
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, r := range []core.ResultEvent{
+		{Host: "10.0.0.10", Port: 443, State: core.StateOpen},
+		{Host: "10.0.0.10", Port: 22, State: core.StateOpen, Banner: "SSH-2.0-OpenSSH_8.9p1 Ubuntu-3ubuntu0.1"},
+		{Host: "10.0.0.10", Port: 53, State: core.StateOpen, Protocol: "udp"},
+		{Host: "10.0.0.10", Port: 22, State: core.StateOpen},
+		{Host: "10.0.0.9", Port: 23, State: core.StateClosed, Hostname: "printer.lan", DeviceName: "PRINTER", DeviceType: "Printer"},
+		{Host: "10.0.0.8", Port: 80, State: core.StateFiltered},
+	} {
+		inv.Add(r, start.Add(time.Duration(i)*time.Second))
+	}
+
+	hosts := inv.Hosts()
+	if len(hosts) != 2 {
+		t.Fatalf("got %d hosts, want 2 (filtered-only hosts are left out): %+v", len(hosts), hosts)
+	}
+	if hosts[0].Host != "10.0.0.9" || hosts[1].Host != "10.0.0.10" {
+		t.Errorf("hosts should be ordered by address, got %s, %s", hosts[0].Host, hosts[1].Host)
+	}
+	if hosts[0].Hostname != "printer.lan" || hosts[0].DeviceType != "Printer" || len(hosts[0].OpenPorts) != 0 {
+		t.Errorf("closed-only host = %+v", hosts[0])
+	}
+
+	web := hosts[1]
+	if got := web.OpenPorts; len(got) != 3 || got[0] != "22/tcp" || got[1] != "53/udp" || got[2] != "443/tcp" {
+		t.Errorf("open ports = %v", got)
+	}
+	if len(web.Services) != 3 || web.Services[0] != "https" {
+		t.Errorf("services = %v", web.Services)
+	}
+	if web.OSGuess != "Linux (Ubuntu)" {
+		t.Errorf("os guess = %q", web.OSGuess)
+	}
+	if !web.FirstSeen.Equal(start) || !web.LastSeen.Equal(start.Add(3*time.Second)) {
+		t.Errorf("first/last seen = %v / %v", web.FirstSeen, web.LastSeen)
+	}
+}
+
+func TestGuessOS(t *testing.T) {
+	tests := []struct {
+		name    string
+		open    []string
+		banners []string
+		want    string
+	}{
+		{"banner names os", []string{"22/tcp"}, []string{"SSH-2.0-OpenSSH_7.4 FreeBSD-20170903"}, "FreeBSD"},
+		{"windows services", []string{"135/tcp", "445/tcp"}, nil, "Windows"},
+		{"single smb port", []string{"445/tcp"}, nil, ""},
+		{"plain ssh", []string{"22/tcp"}, []string{"SSH-2.0-OpenSSH_9.6"}, "Unix-like"},
+		{"no evidence", []string{"80/tcp"}, []string{"nginx"}, ""},
+	}
+	for _, tt := range tests {
+		open := make(map[string]bool)
+		for _, p := range tt.open {
+			open[p] = true
+		}
+		if got := GuessOS(open, tt.banners); got != tt.want {
+			t.Errorf("%s: GuessOS() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestInventoryExporterFormats(t *testing.T) {
+	results := []core.ResultEvent{
+		{Host: "10.0.0.5", Port: 22, State: core.StateOpen, Tags: []string{"env=prod"}},
+		{Host: "10.0.0.5", Port: 80, State: core.StateOpen},
+	}
+
+	var jsonOut bytes.Buffer
+	if err := WriteResults(NewInventoryExporter(&jsonOut, InventoryJSON), results); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	var doc struct {
+		HostCount int             `json:"host_count"`
+		Hosts     []HostInventory `json:"hosts"`
+	}
+	if err := json.Unmarshal(jsonOut.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, jsonOut.String())
+	}
+	if doc.HostCount != 1 || len(doc.Hosts[0].OpenPorts) != 2 || doc.Hosts[0].Tags[0] != "env=prod" {
+		t.Errorf("inventory = %+v", doc)
+	}
+
+	var csvOut bytes.Buffer
+	if err := WriteResults(NewInventoryExporter(&csvOut, InventoryCSV), results); err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	records, err := csv.NewReader(&csvOut).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 2 || records[0][2] != "open_ports" || records[1][2] != "22/tcp;80/tcp" || records[1][3] != "ssh;http" {
+		t.Errorf("csv = %q", records)
+	}
+
+	var empty bytes.Buffer
+	if err := WriteResults(NewInventoryExporter(&empty, InventoryJSON), nil); err != nil {
+		t.Fatalf("empty: %v", err)
+	}
+	if !bytes.Contains(empty.Bytes(), []byte(`"hosts": []`)) {
+		t.Errorf("empty inventory should list no hosts:\n%s", empty.String())
+	}
+}
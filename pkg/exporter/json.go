@@ -2,6 +2,8 @@ package exporter
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"strings"
 	"time"
@@ -16,8 +18,13 @@ type JSONExporter struct {
 	encoder    *json.Encoder
 	arrayMode  bool
 	objectMode bool
+	// bannerEncoding is BannerEncodingText or BannerEncodingBase64
+	bannerEncoding string
 	// metadata for object mode
 	metadata ScanMetadata
+	// aborted reports, when the document is finalized, whether the scan
+	// was stopped early; nil means it ran to completion
+	aborted func() bool
 }
 
 // ScanMetadata holds metadata about a scan for inclusion in JSON export.
@@ -25,25 +32,182 @@ type ScanMetadata struct {
 	Targets    []string
 	TotalPorts int
 	Rate       int
+	Tags       []string
 }
 
-// buildResultDTO creates a consistent DTO from a ResultEvent
-func buildResultDTO(r core.ResultEvent) map[string]interface{} {
+// buildResultDTO creates a consistent DTO from a ResultEvent. With base64
+// encoding the banner bytes are preserved exactly and banner_encoding is set.
+func buildResultDTO(r core.ResultEvent, bannerEncoding string) map[string]interface{} {
 	dto := map[string]interface{}{
 		"host":             r.Host,
 		"port":             r.Port,
+		"protocol":         resultProtocol(r),
 		"state":            string(r.State),
-		"banner":           r.Banner,
+		"banner":           encodeBanner(r.Banner, bannerEncoding),
 		"response_time_ms": float64(r.Duration.Milliseconds()),
 	}
+	if bannerEncoding == BannerEncodingBase64 {
+		dto["banner_encoding"] = BannerEncodingBase64
+	}
+	if r.Verified {
+		dto["verified"] = true
+	}
+	if r.Hostname != "" {
+		dto["hostname"] = r.Hostname
+	}
+	if len(r.Tags) > 0 {
+		dto["tags"] = r.Tags
+	}
+	if r.Note != "" {
+		dto["note"] = r.Note
+	}
+	if r.Edge != "" {
+		dto["edge"] = r.Edge
+	}
+	if r.DeviceName != "" {
+		dto["device_name"] = r.DeviceName
+	}
+	if r.DeviceType != "" {
+		dto["device_type"] = r.DeviceType
+	}
+	if r.TLS != nil {
+		dto["tls"] = buildTLSDTO(r.TLS)
+	}
+	if r.HTTPAudit != nil {
+		dto["http_audit"] = buildHTTPAuditDTO(r.HTTPAudit)
+	}
+	if r.Owner != nil {
+		dto["owner"] = r.Owner
+	}
+	if r.Country != "" {
+		dto["country"] = r.Country
+	}
+	if r.ASN != 0 {
+		dto["asn"] = r.ASN
+		dto["as_org"] = r.ASOrg
+	}
+
+	dto["service"] = resultService(r)
+
+	detected, _, _ := services.Mismatch(r.Port, r.Protocol, r.Banner)
+	if detected != "" {
+		dto["detected_service"] = detected
+	}
+	if findings := resultFindings(r); len(findings) > 0 {
+		dto["findings"] = findings
+	}
+
+	return dto
+}
 
-	// Derive service name: prefer banner-derived hint, else well-known port map
+// ResultJSON returns r as the indented record a JSON export writes for it,
+// with the banner as text.
+func ResultJSON(r core.ResultEvent) ([]byte, error) {
+	return json.MarshalIndent(buildResultDTO(r, BannerEncodingText), "", "  ")
+}
+
+// buildTLSDTO describes a TLS handshake, leaving out what it did not show.
+func buildTLSDTO(info *core.TLSInfo) map[string]interface{} {
+	dto := map[string]interface{}{
+		"version": info.Version,
+		"cipher":  info.Cipher,
+	}
+	if info.StartTLS != "" {
+		dto["starttls"] = info.StartTLS
+	}
+	if info.Subject != "" {
+		dto["subject"] = info.Subject
+	}
+	if info.Issuer != "" {
+		dto["issuer"] = info.Issuer
+	}
+	if len(info.SANs) > 0 {
+		dto["sans"] = info.SANs
+	}
+	if !info.NotAfter.IsZero() {
+		dto["not_after"] = info.NotAfter.UTC().Format(time.RFC3339)
+	}
+	if info.JA3S != "" {
+		dto["ja3s"] = info.JA3S
+	}
+	return dto
+}
+
+// buildHTTPAuditDTO describes a security header audit; present and missing
+// are always listed so reports can count them.
+func buildHTTPAuditDTO(audit *core.HTTPAudit) map[string]interface{} {
+	dto := map[string]interface{}{
+		"grade":   audit.Grade,
+		"status":  audit.Status,
+		"present": nonNil(audit.Present),
+		"missing": nonNil(audit.Missing),
+	}
+	if len(audit.Redirects) > 0 {
+		dto["redirects"] = audit.Redirects
+	}
+	return dto
+}
+
+// nonNil returns s, or an empty slice so JSON shows [] rather than null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// Finding is an observation about a result that deserves a reviewer's
+// attention, such as a service answering on another service's port.
+type Finding struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// resultFindings returns the findings for r: a banner that identifies a
+// different service than the port's registration is a service_mismatch,
+// and each exposure an opt-in check recorded is reported as is.
+func resultFindings(r core.ResultEvent) []Finding {
+	var findings []Finding
+	if detected, expected, mismatch := services.Mismatch(r.Port, r.Protocol, r.Banner); mismatch {
+		findings = append(findings, Finding{
+			ID:       "service_mismatch",
+			Severity: "warning",
+			Message: fmt.Sprintf("%s answered on %d/%s, where %s is registered",
+				detected, r.Port, resultProtocol(r), expected),
+		})
+	}
+	for _, e := range r.Exposures {
+		findings = append(findings, Finding{ID: e.ID, Severity: e.Severity, Message: e.Message})
+	}
+	return findings
+}
+
+// resultService derives a result's service name: prefer the banner-derived
+// hint, else the well-known port map.
+func resultService(r core.ResultEvent) string {
 	svc := strings.TrimSpace(r.Banner)
 	if svc == "" {
 		svc = services.GetName(r.Port)
 	}
-	dto["service"] = svc
+	return svc
+}
+
+// buildErrorDTO describes a scan error, including the probe it belongs to
+// when the scanner reported one.
+func buildErrorDTO(err error) map[string]interface{} {
+	dto := map[string]interface{}{"error": err.Error()}
 
+	var scanErr *core.ScanError
+	if errors.As(err, &scanErr) {
+		dto["host"] = scanErr.Host
+		dto["port"] = scanErr.Port
+		dto["protocol"] = scanErr.Protocol
+		dto["error"] = scanErr.Err.Error()
+		if !scanErr.Time.IsZero() {
+			dto["time"] = scanErr.Time.UTC().Format(time.RFC3339)
+		}
+	}
 	return dto
 }
 
@@ -68,8 +232,8 @@ func NewJSONExporterArray(w io.Writer) *JSONExporter {
 }
 
 // NewJSONExporterObject returns a JSON exporter that writes a single JSON object
-// with a results array and a scan_info metadata section, all streamed without
-// buffering the entire result set in memory.
+// with results, errors and timeline arrays and a scan_info metadata section,
+// all streamed without buffering the entire result set in memory.
 func NewJSONExporterObject(w io.Writer, target string, totalPorts int, scanRate int) *JSONExporter {
 	return &JSONExporter{
 		writer:     w,
@@ -95,10 +259,24 @@ func NewJSONExporterObjectWithMetadata(w io.Writer, meta ScanMetadata) *JSONExpo
 			Targets:    copyTargets,
 			TotalPorts: meta.TotalPorts,
 			Rate:       meta.Rate,
+			Tags:       append([]string(nil), meta.Tags...),
 		},
 	}
 }
 
+// SetBannerEncoding selects how banners are written: BannerEncodingText
+// (the default) or BannerEncodingBase64 for byte-exact binary banners.
+func (e *JSONExporter) SetBannerEncoding(encoding string) {
+	e.bannerEncoding = encoding
+}
+
+// SetAbortCheck registers a function consulted when the events channel
+// closes. If it reports true, object mode records "aborted": true in
+// scan_info, so a document finalized after an interrupt is marked partial.
+func (e *JSONExporter) SetAbortCheck(aborted func() bool) {
+	e.aborted = aborted
+}
+
 // Export writes scan result events in the configured JSON format.
 func (e *JSONExporter) Export(events <-chan core.Event) {
 	if e.objectMode {
@@ -106,12 +284,20 @@ func (e *JSONExporter) Export(events <-chan core.Event) {
 		_, _ = e.writer.Write([]byte("{\n\"results\": ["))
 		first := true
 		startTime := time.Now()
+		// Errors are rare, so they are collected and written after the results.
+		scanErrors := []map[string]interface{}{}
+		timeline := core.NewTimeline()
 		for event := range events {
+			timeline.Observe(event)
+			if event.Kind == core.EventKindError && event.Error != nil {
+				scanErrors = append(scanErrors, buildErrorDTO(event.Error))
+				continue
+			}
 			if event.Kind != core.EventKindResult {
 				continue
 			}
 			r := *event.Result
-			dto := buildResultDTO(r)
+			dto := buildResultDTO(r, e.bannerEncoding)
 
 			if !first {
 				_, _ = e.writer.Write([]byte(","))
@@ -124,6 +310,14 @@ func (e *JSONExporter) Export(events <-chan core.Event) {
 		}
 		endTime := time.Now()
 		_, _ = e.writer.Write([]byte("]"))
+		if b, err := json.Marshal(scanErrors); err == nil {
+			_, _ = e.writer.Write([]byte(",\n\"errors\": "))
+			_, _ = e.writer.Write(b)
+		}
+		if b, err := json.Marshal(buildTimelineDTO(timeline.Finish())); err == nil {
+			_, _ = e.writer.Write([]byte(",\n\"timeline\": "))
+			_, _ = e.writer.Write(b)
+		}
 		// Append scan_info metadata
 		info := map[string]interface{}{
 			"targets":     e.metadata.Targets,
@@ -131,6 +325,11 @@ func (e *JSONExporter) Export(events <-chan core.Event) {
 			"end_time":    endTime.UTC().Format(time.RFC3339),
 			"total_ports": e.metadata.TotalPorts,
 			"scan_rate":   e.metadata.Rate,
+			"error_count": len(scanErrors),
+			"aborted":     e.aborted != nil && e.aborted(),
+		}
+		if len(e.metadata.Tags) > 0 {
+			info["tags"] = e.metadata.Tags
 		}
 		b, err := json.Marshal(info)
 		if err == nil {
@@ -151,7 +350,7 @@ func (e *JSONExporter) Export(events <-chan core.Event) {
 				continue
 			}
 			r := *event.Result
-			dto := buildResultDTO(r)
+			dto := buildResultDTO(r, e.bannerEncoding)
 
 			if !first {
 				_, _ = e.writer.Write([]byte(","))
@@ -172,7 +371,7 @@ func (e *JSONExporter) Export(events <-chan core.Event) {
 		if event.Kind != core.EventKindResult {
 			continue
 		}
-		dto := buildResultDTO(*event.Result)
+		dto := buildResultDTO(*event.Result, e.bannerEncoding)
 
 		// Best-effort encode; callers can check write errors on the underlying writer if needed.
 		_ = e.encoder.Encode(dto)
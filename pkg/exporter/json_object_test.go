@@ -3,6 +3,8 @@ package exporter
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -39,6 +41,36 @@ func TestJSONExporterObjectMode(t *testing.T) {
 	if int(obj.ScanInfo["total_ports"].(float64)) != 2 || int(obj.ScanInfo["scan_rate"].(float64)) != 7500 {
 		t.Errorf("unexpected scan_info: %+v", obj.ScanInfo)
 	}
+	if obj.ScanInfo["aborted"] != false {
+		t.Errorf("aborted = %v, want false for a completed scan", obj.ScanInfo["aborted"])
+	}
+}
+
+func TestJSONExporterObjectModeAborted(t *testing.T) {
+	var buf bytes.Buffer
+	exp := NewJSONExporterObjectWithMetadata(&buf, ScanMetadata{Targets: []string{"10.0.0.1"}, TotalPorts: 1000})
+	interrupted := false
+	exp.SetAbortCheck(func() bool { return interrupted })
+
+	ch := make(chan core.Event, 1)
+	ch <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen})
+	// The scan is interrupted before the channel closes.
+	interrupted = true
+	close(ch)
+
+	exp.Export(ch)
+	_ = exp.Close()
+
+	var obj struct {
+		Results  []map[string]interface{} `json:"results"`
+		ScanInfo map[string]interface{}   `json:"scan_info"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &obj); err != nil {
+		t.Fatalf("aborted document is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if len(obj.Results) != 1 || obj.ScanInfo["aborted"] != true {
+		t.Errorf("results = %d, scan_info = %+v; want the result kept and aborted set", len(obj.Results), obj.ScanInfo)
+	}
 }
 
 func TestJSONExporterObjectModeEmptyResults(t *testing.T) {
@@ -68,3 +100,328 @@ func TestJSONExporterObjectModeEmptyResults(t *testing.T) {
 		t.Errorf("unexpected scan_info: %+v", obj.ScanInfo)
 	}
 }
+
+func TestJSONExporterObjectModeErrors(t *testing.T) {
+	var buf bytes.Buffer
+	exp := NewJSONExporterObjectWithMetadata(&buf, ScanMetadata{Targets: []string{"db.internal"}, TotalPorts: 2})
+	ch := make(chan core.Event, 3)
+
+	ch <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen})
+	ch <- core.NewErrorEvent(&core.ScanError{Host: "db.internal", Port: 5432, Protocol: "tcp", Time: time.Unix(0, 0), Err: errors.New("no such host")})
+	ch <- core.NewErrorEvent(errors.New("scanner stalled"))
+	close(ch)
+
+	exp.Export(ch)
+	_ = exp.Close()
+
+	var obj struct {
+		Results  []map[string]interface{} `json:"results"`
+		Errors   []map[string]interface{} `json:"errors"`
+		ScanInfo map[string]interface{}   `json:"scan_info"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &obj); err != nil {
+		t.Fatalf("object mode output not valid JSON object: %v\n%s", err, buf.String())
+	}
+	if len(obj.Results) != 1 || len(obj.Errors) != 2 {
+		t.Fatalf("results = %d, errors = %d; want 1 and 2", len(obj.Results), len(obj.Errors))
+	}
+
+	first := obj.Errors[0]
+	if first["host"] != "db.internal" || first["port"].(float64) != 5432 || first["protocol"] != "tcp" ||
+		first["error"] != "no such host" || first["time"] != "1970-01-01T00:00:00Z" {
+		t.Errorf("structured error = %+v", first)
+	}
+	if obj.Errors[1]["error"] != "scanner stalled" {
+		t.Errorf("plain error = %+v", obj.Errors[1])
+	}
+	if int(obj.ScanInfo["error_count"].(float64)) != 2 {
+		t.Errorf("error_count = %v, want 2", obj.ScanInfo["error_count"])
+	}
+}
+
+func TestJSONExporterVerifiedField(t *testing.T) {
+	var buf bytes.Buffer
+	exp := NewJSONExporter(&buf)
+	results := []core.ResultEvent{
+		{Host: "h", Port: 22, State: core.StateOpen, Verified: true},
+		{Host: "h", Port: 80, State: core.StateOpen},
+	}
+	if err := WriteResults(exp, results); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], `"verified":true`) {
+		t.Errorf("verified result missing flag: %s", lines[0])
+	}
+	if strings.Contains(lines[1], "verified") {
+		t.Errorf("unverified result should omit the flag: %s", lines[1])
+	}
+}
+
+func TestJSONExporterHostnameField(t *testing.T) {
+	var buf bytes.Buffer
+	exp := NewJSONExporter(&buf)
+	results := []core.ResultEvent{
+		{Host: "10.0.0.5", Port: 443, State: core.StateOpen, Hostname: "web.example.com"},
+		{Host: "10.0.0.9", Port: 443, State: core.StateOpen},
+	}
+	if err := WriteResults(exp, results); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], `"hostname":"web.example.com"`) {
+		t.Errorf("result missing hostname: %s", lines[0])
+	}
+	if strings.Contains(lines[1], "hostname") {
+		t.Errorf("result scanned by address should omit hostname: %s", lines[1])
+	}
+}
+
+func TestJSONExporterTagsAndNote(t *testing.T) {
+	var buf bytes.Buffer
+	exp := NewJSONExporterObjectWithMetadata(&buf, ScanMetadata{Targets: []string{"10.0.0.5"}, Tags: []string{"env=prod"}})
+	results := []core.ResultEvent{
+		{Host: "10.0.0.5", Port: 22, State: core.StateOpen, Tags: []string{"env=prod", "bastion"}, Note: "expected"},
+		{Host: "10.0.0.5", Port: 80, State: core.StateOpen},
+	}
+	if err := WriteResults(exp, results); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+
+	var doc struct {
+		Results  []map[string]interface{} `json:"results"`
+		ScanInfo map[string]interface{}   `json:"scan_info"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if tags, ok := doc.Results[0]["tags"].([]interface{}); !ok || len(tags) != 2 || tags[1] != "bastion" {
+		t.Errorf("tags = %v", doc.Results[0]["tags"])
+	}
+	if doc.Results[0]["note"] != "expected" {
+		t.Errorf("note = %v", doc.Results[0]["note"])
+	}
+	if _, ok := doc.Results[1]["tags"]; ok {
+		t.Error("untagged result should omit tags")
+	}
+	if _, ok := doc.Results[1]["note"]; ok {
+		t.Error("result without a note should omit it")
+	}
+	if tags, ok := doc.ScanInfo["tags"].([]interface{}); !ok || len(tags) != 1 || tags[0] != "env=prod" {
+		t.Errorf("scan_info tags = %v", doc.ScanInfo["tags"])
+	}
+}
+
+func TestJSONExporterEdgeField(t *testing.T) {
+	var buf bytes.Buffer
+	results := []core.ResultEvent{
+		{Host: "104.16.0.1", Port: 443, State: core.StateOpen, Edge: "Cloudflare"},
+		{Host: "10.0.0.9", Port: 443, State: core.StateOpen},
+	}
+	if err := WriteResults(NewJSONExporter(&buf), results); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.Contains(lines[0], `"edge":"Cloudflare"`) {
+		t.Errorf("result missing edge: %s", lines[0])
+	}
+	if strings.Contains(lines[1], "edge") {
+		t.Errorf("origin result should omit edge: %s", lines[1])
+	}
+}
+
+func TestJSONExporterDeviceFields(t *testing.T) {
+	var buf bytes.Buffer
+	results := []core.ResultEvent{
+		{Host: "192.168.1.20", Port: 80, State: core.StateOpen, DeviceName: "living-room", DeviceType: "MediaRenderer"},
+		{Host: "192.168.1.21", Port: 80, State: core.StateOpen},
+	}
+	if err := WriteResults(NewJSONExporter(&buf), results); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.Contains(lines[0], `"device_name":"living-room"`) || !strings.Contains(lines[0], `"device_type":"MediaRenderer"`) {
+		t.Errorf("result missing device fields: %s", lines[0])
+	}
+	if strings.Contains(lines[1], "device_") {
+		t.Errorf("undiscovered host should omit device fields: %s", lines[1])
+	}
+}
+
+func TestJSONExporterOwner(t *testing.T) {
+	var buf bytes.Buffer
+	owner := &core.Ownership{Org: "Example Hosting Inc.", Abuse: "abuse@example.net", Range: "203.0.113.0 - 203.0.113.255"}
+	results := []core.ResultEvent{
+		{Host: "203.0.113.5", Port: 443, State: core.StateOpen, Owner: owner},
+		{Host: "10.0.0.5", Port: 443, State: core.StateOpen},
+	}
+	if err := WriteResults(NewJSONExporter(&buf), results); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if want := `"owner":{"org":"Example Hosting Inc.","abuse":"abuse@example.net","range":"203.0.113.0 - 203.0.113.255"}`; !strings.Contains(lines[0], want) {
+		t.Errorf("result missing %s: %s", want, lines[0])
+	}
+	if strings.Contains(lines[1], "owner") {
+		t.Errorf("private host should omit the owner: %s", lines[1])
+	}
+}
+
+func TestJSONExporterGeoIP(t *testing.T) {
+	var buf bytes.Buffer
+	results := []core.ResultEvent{
+		{Host: "8.8.8.8", Port: 53, State: core.StateOpen, Country: "US", ASN: 15169, ASOrg: "GOOGLE"},
+		{Host: "10.0.0.5", Port: 53, State: core.StateOpen},
+	}
+	if err := WriteResults(NewJSONExporter(&buf), results); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	for _, want := range []string{`"country":"US"`, `"asn":15169`, `"as_org":"GOOGLE"`} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("result missing %s: %s", want, lines[0])
+		}
+	}
+	if strings.Contains(lines[1], "country") || strings.Contains(lines[1], "asn") {
+		t.Errorf("unlocated host should omit GeoIP fields: %s", lines[1])
+	}
+}
+
+func TestJSONExporterServiceMismatchFinding(t *testing.T) {
+	var buf bytes.Buffer
+	results := []core.ResultEvent{
+		{Host: "10.0.0.5", Port: 8080, State: core.StateOpen, Banner: "SSH-2.0-OpenSSH_9.6\r\n"},
+		{Host: "10.0.0.5", Port: 22, State: core.StateOpen, Banner: "SSH-2.0-OpenSSH_9.6\r\n"},
+	}
+	if err := WriteResults(NewJSONExporter(&buf), results); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var mismatched struct {
+		DetectedService string    `json:"detected_service"`
+		Findings        []Finding `json:"findings"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &mismatched); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, lines[0])
+	}
+	if mismatched.DetectedService != "ssh" {
+		t.Errorf("detected_service = %q; want ssh", mismatched.DetectedService)
+	}
+	if len(mismatched.Findings) != 1 || mismatched.Findings[0].ID != "service_mismatch" {
+		t.Fatalf("findings = %+v; want one service_mismatch", mismatched.Findings)
+	}
+	if !strings.Contains(mismatched.Findings[0].Message, "ssh answered on 8080/tcp, where http-alt is registered") {
+		t.Errorf("finding message = %q", mismatched.Findings[0].Message)
+	}
+	if !strings.Contains(lines[1], `"detected_service":"ssh"`) || strings.Contains(lines[1], "findings") {
+		t.Errorf("SSH on 22 should be detected without a finding: %s", lines[1])
+	}
+}
+
+func TestJSONExporterExposureFindings(t *testing.T) {
+	var buf bytes.Buffer
+	results := []core.ResultEvent{{
+		Host: "10.0.0.7", Port: 123, State: core.StateOpen, Protocol: "udp",
+		Tags:      []string{"severity=high"},
+		Exposures: []core.Exposure{{ID: core.ExposureNTPMonlist, Severity: "high", Message: "NTP answers mode 7 monlist queries"}},
+	}}
+	if err := WriteResults(NewJSONExporter(&buf), results); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+
+	var result struct {
+		Findings []Finding `json:"findings"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(result.Findings) != 1 || result.Findings[0].ID != "ntp_monlist" || result.Findings[0].Severity != "high" {
+		t.Errorf("findings = %+v; want the monlist exposure", result.Findings)
+	}
+}
+
+func TestJSONExporterTLSField(t *testing.T) {
+	var buf bytes.Buffer
+	results := []core.ResultEvent{{
+		Host: "10.0.0.8", Port: 587, State: core.StateOpen,
+		TLS: &core.TLSInfo{
+			Version: "TLS 1.3", Cipher: "TLS_AES_128_GCM_SHA256", StartTLS: "smtp",
+			Subject: "mail.example.com", SANs: []string{"mail.example.com"},
+			NotAfter: time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC),
+			JA3S:     "f4febc55ea12b31ae17cfb7e614afda8",
+		},
+	}}
+	if err := WriteResults(NewJSONExporter(&buf), results); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+
+	var result struct {
+		TLS map[string]interface{} `json:"tls"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if result.TLS["starttls"] != "smtp" || result.TLS["not_after"] != "2027-03-01T00:00:00Z" || result.TLS["ja3s"] != "f4febc55ea12b31ae17cfb7e614afda8" {
+		t.Errorf("tls = %v", result.TLS)
+	}
+	if _, ok := result.TLS["issuer"]; ok {
+		t.Errorf("empty issuer should be omitted: %v", result.TLS)
+	}
+}
+
+func TestJSONExporterHTTPAuditField(t *testing.T) {
+	var buf bytes.Buffer
+	results := []core.ResultEvent{{
+		Host: "10.0.0.9", Port: 443, State: core.StateOpen,
+		HTTPAudit: &core.HTTPAudit{Grade: "A", Status: 200, Present: []string{"Content-Security-Policy"}},
+	}}
+	if err := WriteResults(NewJSONExporter(&buf), results); err != nil {
+		t.Fatalf("WriteResults: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"http_audit":{"grade":"A","missing":[],"present":["Content-Security-Policy"],"status":200}`) {
+		t.Errorf("http_audit not exported as expected: %s", buf.String())
+	}
+}
+
+func TestJSONExporterObjectModeTimeline(t *testing.T) {
+	var buf bytes.Buffer
+	exp := NewJSONExporterObjectWithMetadata(&buf, ScanMetadata{Targets: []string{"10.0.0.1"}, TotalPorts: 1})
+	ch := make(chan core.Event, 3)
+	ch <- core.NewTimelineEvent(core.TimelineEntry{Time: time.Now(), Kind: core.TimelineHostStart, Host: "10.0.0.1"})
+	ch <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen})
+	ch <- core.NewTimelineEvent(core.TimelineEntry{Time: time.Now(), Kind: core.TimelineHostFinish, Host: "10.0.0.1"})
+	close(ch)
+
+	exp.Export(ch)
+
+	var obj struct {
+		Results  []map[string]interface{} `json:"results"`
+		Timeline []map[string]interface{} `json:"timeline"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &obj); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	var events []string
+	for _, entry := range obj.Timeline {
+		events = append(events, entry["event"].(string))
+	}
+	if got := strings.Join(events, ","); got != "scan_start,host_start,host_finish,scan_finish" {
+		t.Errorf("timeline events = %s", got)
+	}
+	if obj.Timeline[1]["host"] != "10.0.0.1" || len(obj.Results) != 1 {
+		t.Errorf("timeline = %v, results = %v", obj.Timeline, obj.Results)
+	}
+}
@@ -76,3 +76,20 @@ func TestJSONExporterEmptyInputNDJSON(t *testing.T) {
 		t.Errorf("expected empty output for empty input channel, got: %q", output)
 	}
 }
+
+func TestResultJSONMatchesExportRecord(t *testing.T) {
+	data, err := ResultJSON(core.ResultEvent{Host: "10.0.0.1", Port: 53, Protocol: "udp", State: core.StateOpen, Banner: "DNS"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r resultDTO
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatalf("invalid JSON %q: %v", data, err)
+	}
+	if r.Host != "10.0.0.1" || r.Port != 53 || r.Banner != "DNS" {
+		t.Errorf("unexpected record: %+v", r)
+	}
+	if !strings.Contains(string(data), "\n  \"host\"") {
+		t.Errorf("record should be indented:\n%s", data)
+	}
+}
@@ -0,0 +1,167 @@
+package exporter
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+// Kafka partitioners.
+const (
+	PartitionerHash       = "hash"
+	PartitionerRoundRobin = "round-robin"
+)
+
+// Kafka SASL mechanisms.
+const (
+	SASLPlain       = "plain"
+	SASLScramSHA256 = "scram-sha-256"
+	SASLScramSHA512 = "scram-sha-512"
+)
+
+const (
+	kafkaClientID       = "portscan"
+	kafkaRequestTimeout = 30 * time.Second
+)
+
+// KafkaOptions configures a KafkaPublisher.
+type KafkaOptions struct {
+	// Brokers are bootstrap addresses, host or host:port (default port 9092).
+	Brokers []string
+	Topic   string
+	// Partitioner is PartitionerHash (the default), which places messages
+	// with the same key on the same partition using the Java client's
+	// murmur2 hash, or PartitionerRoundRobin. Messages without a key
+	// stick to one partition per batch.
+	Partitioner string
+	// DialTimeout bounds connecting to a broker; defaults to 10s.
+	DialTimeout time.Duration
+	// TLS enables TLS to the brokers when set.
+	TLS *tls.Config
+	// SASL is SASLPlain, SASLScramSHA256 or SASLScramSHA512 to
+	// authenticate as Username, or empty for no authentication.
+	SASL     string
+	Username string
+	Password string
+}
+
+// KafkaPublisher produces messages to a Kafka topic with acks=all, so a
+// message counts as delivered only once every in-sync replica has it.
+type KafkaPublisher struct {
+	client *kgo.Client
+}
+
+// NewKafkaPublisher connects to the cluster and checks that a broker
+// answers.
+func NewKafkaPublisher(opts KafkaOptions) (*KafkaPublisher, error) {
+	if len(opts.Brokers) == 0 {
+		return nil, errors.New("kafka: no brokers given")
+	}
+	if opts.Topic == "" {
+		return nil, errors.New("kafka: no topic given")
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = 10 * time.Second
+	}
+	for i, b := range opts.Brokers {
+		opts.Brokers[i] = strings.TrimPrefix(b, "kafka://")
+	}
+
+	kopts := []kgo.Opt{
+		kgo.SeedBrokers(opts.Brokers...),
+		kgo.ClientID(kafkaClientID),
+		kgo.DefaultProduceTopic(opts.Topic),
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+		kgo.DialTimeout(opts.DialTimeout),
+		kgo.RecordDeliveryTimeout(kafkaRequestTimeout),
+	}
+	switch opts.Partitioner {
+	case "", PartitionerHash:
+		kopts = append(kopts, kgo.RecordPartitioner(kgo.StickyKeyPartitioner(nil)))
+	case PartitionerRoundRobin:
+		kopts = append(kopts, kgo.RecordPartitioner(kgo.RoundRobinPartitioner()))
+	default:
+		return nil, fmt.Errorf("kafka: unsupported partitioner %q: use %s or %s", opts.Partitioner, PartitionerHash, PartitionerRoundRobin)
+	}
+	if opts.TLS != nil {
+		kopts = append(kopts, kgo.DialTLSConfig(opts.TLS))
+	}
+	if opts.SASL != "" {
+		mechanism, err := kafkaSASL(opts.SASL, opts.Username, opts.Password)
+		if err != nil {
+			return nil, err
+		}
+		kopts = append(kopts, kgo.SASL(mechanism))
+	}
+
+	client, err := kgo.NewClient(kopts...)
+	if err != nil {
+		return nil, fmt.Errorf("kafka: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), opts.DialTimeout)
+	defer cancel()
+	if err := client.Ping(ctx); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("kafka: no broker reachable: %w", err)
+	}
+	return &KafkaPublisher{client: client}, nil
+}
+
+// kafkaSASL returns the SASL mechanism for name.
+func kafkaSASL(name, user, pass string) (sasl.Mechanism, error) {
+	switch name {
+	case SASLPlain:
+		return plain.Auth{User: user, Pass: pass}.AsMechanism(), nil
+	case SASLScramSHA256:
+		return scram.Auth{User: user, Pass: pass}.AsSha256Mechanism(), nil
+	case SASLScramSHA512:
+		return scram.Auth{User: user, Pass: pass}.AsSha512Mechanism(), nil
+	}
+	return nil, fmt.Errorf("kafka: unsupported SASL mechanism %q: use %s, %s or %s", name, SASLPlain, SASLScramSHA256, SASLScramSHA512)
+}
+
+// Publish produces msgs and waits for the brokers to acknowledge them.
+// The client retries retriable broker errors itself until the delivery
+// timeout; messages still failing are returned.
+func (p *KafkaPublisher) Publish(msgs []Message) ([]Message, error) {
+	records := make([]*kgo.Record, len(msgs))
+	for i, m := range msgs {
+		records[i] = &kgo.Record{Key: m.Key, Value: m.Value, Timestamp: m.Time}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaRequestTimeout+5*time.Second)
+	defer cancel()
+	var failed []Message
+	var firstErr error
+	for i, result := range p.client.ProduceSync(ctx, records...) {
+		if result.Err == nil {
+			continue
+		}
+		failed = append(failed, msgs[i])
+		err := fmt.Errorf("kafka: %w", result.Err)
+		var brokerErr *kerr.Error
+		permanent := errors.As(result.Err, &brokerErr) && !brokerErr.Retriable
+		if permanent {
+			err = &permanentError{err}
+		}
+		if firstErr == nil || permanent {
+			firstErr = err
+		}
+	}
+	return failed, firstErr
+}
+
+// Close closes the broker connections.
+func (p *KafkaPublisher) Close() error {
+	p.client.Close()
+	return nil
+}
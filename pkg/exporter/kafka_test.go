@@ -0,0 +1,183 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func newKafkaTestCluster(t *testing.T, partitions int32, opts ...kfake.Opt) *kfake.Cluster {
+	t.Helper()
+	c, err := kfake.NewCluster(append([]kfake.Opt{kfake.NumBrokers(1), kfake.SeedTopics(partitions, "scans")}, opts...)...)
+	if err != nil {
+		t.Fatalf("kfake.NewCluster: %v", err)
+	}
+	t.Cleanup(c.Close)
+	return c
+}
+
+// failProduce answers the next produce request (or every one, if keep is
+// set) with code for each partition.
+func failProduce(c *kfake.Cluster, code int16, keep bool) *atomic.Int32 {
+	var calls atomic.Int32
+	c.ControlKey(int16(kmsg.Produce), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		if keep {
+			c.KeepControl()
+		}
+		calls.Add(1)
+		req := kreq.(*kmsg.ProduceRequest)
+		resp := req.ResponseKind().(*kmsg.ProduceResponse)
+		for _, rt := range req.Topics {
+			st := kmsg.NewProduceResponseTopic()
+			st.Topic, st.TopicID = rt.Topic, rt.TopicID
+			for _, rp := range rt.Partitions {
+				sp := kmsg.NewProduceResponseTopicPartition()
+				sp.Partition = rp.Partition
+				sp.ErrorCode = code
+				st.Partitions = append(st.Partitions, sp)
+			}
+			resp.Topics = append(resp.Topics, st)
+		}
+		return resp, nil, true
+	})
+	return &calls
+}
+
+// consumeKafka reads n records back from the scans topic.
+func consumeKafka(t *testing.T, c *kfake.Cluster, n int) []*kgo.Record {
+	t.Helper()
+	cl, err := kgo.NewClient(kgo.SeedBrokers(c.ListenAddrs()...), kgo.ConsumeTopics("scans"), kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()))
+	if err != nil {
+		t.Fatalf("consumer: %v", err)
+	}
+	defer cl.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var recs []*kgo.Record
+	for len(recs) < n {
+		fetches := cl.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("consumed %d records, want %d", len(recs), n)
+		}
+		recs = append(recs, fetches.Records()...)
+	}
+	return recs
+}
+
+func kafkaTestMessages(keys ...string) []Message {
+	msgs := make([]Message, len(keys))
+	for i, k := range keys {
+		msgs[i] = Message{ID: strconv.Itoa(i), Key: []byte(k), Value: []byte(`{"n":` + strconv.Itoa(i) + `}`), Time: time.Now()}
+	}
+	return msgs
+}
+
+func TestKafkaPublisherHashPartitioning(t *testing.T) {
+	c := newKafkaTestCluster(t, 4)
+	pub, err := NewKafkaPublisher(KafkaOptions{Brokers: c.ListenAddrs(), Topic: "scans"})
+	if err != nil {
+		t.Fatalf("NewKafkaPublisher: %v", err)
+	}
+	defer pub.Close()
+
+	keys := []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.1", "10.0.0.2", "10.0.0.3"}
+	failed, err := pub.Publish(kafkaTestMessages(keys...))
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("Publish = %v, %v", failed, err)
+	}
+
+	partitionOf := map[string]int32{}
+	for _, r := range consumeKafka(t, c, len(keys)) {
+		if p, ok := partitionOf[string(r.Key)]; ok && p != r.Partition {
+			t.Errorf("key %s on partitions %d and %d, want one", r.Key, p, r.Partition)
+		}
+		partitionOf[string(r.Key)] = r.Partition
+	}
+	if len(partitionOf) != 3 {
+		t.Errorf("consumed %d distinct keys, want 3", len(partitionOf))
+	}
+}
+
+func TestKafkaPublisherRetriesRetriableErrors(t *testing.T) {
+	c := newKafkaTestCluster(t, 1)
+	pub, err := NewKafkaPublisher(KafkaOptions{Brokers: c.ListenAddrs(), Topic: "scans", Partitioner: PartitionerRoundRobin})
+	if err != nil {
+		t.Fatalf("NewKafkaPublisher: %v", err)
+	}
+	defer pub.Close()
+
+	calls := failProduce(c, kerr.NotEnoughReplicas.Code, false)
+	if failed, err := pub.Publish(kafkaTestMessages("a", "b")); err != nil || len(failed) != 0 {
+		t.Fatalf("Publish = %v, %v; want the client to retry", failed, err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("failing produce handled %d times, want 1", calls.Load())
+	}
+	if got := len(consumeKafka(t, c, 2)); got != 2 {
+		t.Errorf("consumed %d records, want 2", got)
+	}
+}
+
+func TestKafkaPublisherPermanentError(t *testing.T) {
+	c := newKafkaTestCluster(t, 1)
+	pub, err := NewKafkaPublisher(KafkaOptions{Brokers: c.ListenAddrs(), Topic: "scans"})
+	if err != nil {
+		t.Fatalf("NewKafkaPublisher: %v", err)
+	}
+	defer pub.Close()
+
+	failProduce(c, kerr.TopicAuthorizationFailed.Code, true)
+	failed, err := pub.Publish(kafkaTestMessages("a", "b"))
+	if len(failed) != 2 {
+		t.Errorf("Publish returned %d failed messages, want 2", len(failed))
+	}
+	var permanent *permanentError
+	if !errors.As(err, &permanent) {
+		t.Errorf("TOPIC_AUTHORIZATION_FAILED should not be retried: %v", err)
+	}
+}
+
+func TestKafkaPublisherSASL(t *testing.T) {
+	c := newKafkaTestCluster(t, 1, kfake.EnableSASL(), kfake.Superuser("SCRAM-SHA-512", "scanner", "s3cret"))
+
+	pub, err := NewKafkaPublisher(KafkaOptions{Brokers: c.ListenAddrs(), Topic: "scans", SASL: SASLScramSHA512, Username: "scanner", Password: "s3cret"})
+	if err != nil {
+		t.Fatalf("NewKafkaPublisher: %v", err)
+	}
+	defer pub.Close()
+	if failed, err := pub.Publish(kafkaTestMessages("a")); err != nil || len(failed) != 0 {
+		t.Fatalf("Publish = %v, %v", failed, err)
+	}
+
+	if _, err := NewKafkaPublisher(KafkaOptions{Brokers: c.ListenAddrs(), Topic: "scans", SASL: SASLScramSHA512, Username: "scanner", Password: "wrong", DialTimeout: 2 * time.Second}); err == nil {
+		t.Error("expected an error with the wrong password")
+	}
+	if _, err := NewKafkaPublisher(KafkaOptions{Brokers: c.ListenAddrs(), Topic: "scans", SASL: "gssapi"}); err == nil {
+		t.Error("expected an error for an unknown SASL mechanism")
+	}
+}
+
+func TestKafkaPublisherUnreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	if _, err := NewKafkaPublisher(KafkaOptions{Brokers: []string{addr}, Topic: "scans", DialTimeout: time.Second}); err == nil {
+		t.Error("expected an error with no reachable broker")
+	}
+	if _, err := NewKafkaPublisher(KafkaOptions{Brokers: []string{addr}, Topic: "scans", Partitioner: "sticky"}); err == nil {
+		t.Error("expected an error for an unknown partitioner")
+	}
+}
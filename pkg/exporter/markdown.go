@@ -0,0 +1,77 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/services"
+)
+
+const markdownHeader = "| Host | Port | Protocol | State | Service | Banner | Latency (ms) |\n" +
+	"|------|-----:|----------|-------|---------|--------|-------------:|\n"
+
+// MarkdownExporter exports scan results as a GitHub-flavored Markdown table.
+type MarkdownExporter struct {
+	writer        io.Writer
+	headerWritten bool
+	writeErr      error
+}
+
+// NewMarkdownExporter creates a new Markdown exporter that writes to the given writer.
+func NewMarkdownExporter(w io.Writer) *MarkdownExporter {
+	return &MarkdownExporter{writer: w}
+}
+
+// escapeMarkdownCell makes a value safe to place inside a table cell by
+// escaping pipes and flattening line breaks.
+func escapeMarkdownCell(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "|", "\\|")
+	value = strings.ReplaceAll(value, "\r\n", " ")
+	value = strings.ReplaceAll(value, "\n", " ")
+	value = strings.ReplaceAll(value, "\r", " ")
+	return strings.TrimSpace(value)
+}
+
+func (e *MarkdownExporter) writeHeader() {
+	if e.headerWritten {
+		return
+	}
+	e.headerWritten = true
+	if _, err := io.WriteString(e.writer, markdownHeader); err != nil {
+		e.writeErr = err
+	}
+}
+
+// Export writes scan result events as Markdown table rows.
+func (e *MarkdownExporter) Export(events <-chan core.Event) {
+	e.writeHeader()
+	for event := range events {
+		if event.Kind != core.EventKindResult || e.writeErr != nil {
+			continue
+		}
+
+		r := *event.Result
+		protocol := resultProtocol(r)
+		line := fmt.Sprintf("| %s | %d | %s | %s | %s | %s | %d |\n",
+			escapeMarkdownCell(r.Host),
+			r.Port,
+			protocol,
+			r.State,
+			services.GetName(r.Port),
+			escapeMarkdownCell(InlineBanner(r.Banner)),
+			r.Duration.Milliseconds(),
+		)
+		if _, err := io.WriteString(e.writer, line); err != nil {
+			e.writeErr = err
+		}
+	}
+}
+
+// Close returns any write error. The header is emitted even for empty scans.
+func (e *MarkdownExporter) Close() error {
+	e.writeHeader()
+	return e.writeErr
+}
@@ -0,0 +1,166 @@
+package exporter
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const natsKeyHeader = "Portscan-Key"
+
+// NATSOptions configures a NATSPublisher.
+type NATSOptions struct {
+	// Servers are tried in order: nats://[user:pass@]host[:port], or tls://
+	// to require TLS. The port defaults to 4222.
+	Servers []string
+	Subject string
+	// DialTimeout bounds connecting; defaults to 10s.
+	DialTimeout time.Duration
+	// AckTimeout bounds waiting for a batch's acknowledgements; defaults
+	// to 5s.
+	AckTimeout time.Duration
+	// TLS configures TLS to the servers when set, such as a CA bundle or a
+	// client certificate.
+	TLS *tls.Config
+	// CredsFile is a NATS credentials file holding a user JWT and nkey
+	// seed. Username and Password authenticate otherwise, overriding any
+	// in the server URLs.
+	CredsFile string
+	Username  string
+	Password  string
+}
+
+// NATSPublisher publishes messages to a NATS subject captured by a
+// JetStream stream and waits for the stream to acknowledge each one, which
+// core NATS alone cannot do. The message key travels in the Portscan-Key
+// header and the message ID in Nats-Msg-Id, so the stream drops resent
+// copies within its duplicate window.
+type NATSPublisher struct {
+	opts NATSOptions
+	conn *nats.Conn
+	js   jetstream.JetStream
+}
+
+// NewNATSPublisher connects to the first reachable server.
+func NewNATSPublisher(opts NATSOptions) (*NATSPublisher, error) {
+	if len(opts.Servers) == 0 {
+		return nil, errors.New("nats: no servers given")
+	}
+	if opts.Subject == "" || strings.ContainsAny(opts.Subject, " \t\r\n") {
+		return nil, fmt.Errorf("nats: invalid subject %q", opts.Subject)
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = 10 * time.Second
+	}
+	if opts.AckTimeout <= 0 {
+		opts.AckTimeout = 5 * time.Second
+	}
+
+	servers := make([]string, len(opts.Servers))
+	for i, s := range opts.Servers {
+		if !strings.Contains(s, "://") {
+			s = "nats://" + s
+		}
+		servers[i] = s
+	}
+	nopts := []nats.Option{
+		nats.Name("portscan"),
+		nats.Timeout(opts.DialTimeout),
+		nats.DontRandomize(),
+	}
+	if opts.TLS != nil {
+		nopts = append(nopts, nats.Secure(opts.TLS))
+	}
+	if opts.CredsFile != "" {
+		nopts = append(nopts, nats.UserCredentials(opts.CredsFile))
+	}
+	if opts.Username != "" {
+		nopts = append(nopts, nats.UserInfo(opts.Username, opts.Password))
+	}
+
+	conn, err := nats.Connect(strings.Join(servers, ","), nopts...)
+	if err != nil {
+		return nil, fmt.Errorf("nats: no server reachable: %w", err)
+	}
+	if !conn.HeadersSupported() {
+		conn.Close()
+		return nil, errors.New("nats: server does not support headers (NATS 2.2 or later is required)")
+	}
+	js, err := jetstream.New(conn, jetstream.WithPublishAsyncTimeout(opts.AckTimeout))
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("nats: %w", err)
+	}
+	return &NATSPublisher{opts: opts, conn: conn, js: js}, nil
+}
+
+// Publish sends msgs and waits for JetStream to acknowledge them.
+func (p *NATSPublisher) Publish(msgs []Message) ([]Message, error) {
+	futures := make([]jetstream.PubAckFuture, len(msgs))
+	var failed []Message
+	var firstErr error
+	fail := func(m Message, err error) {
+		failed = append(failed, m)
+		if errors.Is(err, nats.ErrNoResponders) || errors.Is(err, jetstream.ErrNoStreamResponse) {
+			err = &permanentError{fmt.Errorf("nats: no JetStream stream captures subject %s", p.opts.Subject)}
+		}
+		var permanent *permanentError
+		if firstErr == nil || errors.As(err, &permanent) {
+			firstErr = err
+		}
+	}
+
+	for i, m := range msgs {
+		msg := nats.NewMsg(p.opts.Subject)
+		msg.Data = m.Value
+		if m.Key != nil {
+			msg.Header.Set(natsKeyHeader, strings.NewReplacer("\r", "", "\n", "").Replace(string(m.Key)))
+		}
+		f, err := p.js.PublishMsgAsync(msg, jetstream.WithMsgID(m.ID))
+		if err != nil {
+			fail(m, fmt.Errorf("nats: publish: %w", err))
+			continue
+		}
+		futures[i] = f
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.opts.AckTimeout)
+	defer cancel()
+	for i, f := range futures {
+		if f == nil {
+			continue
+		}
+		select {
+		case <-f.Ok():
+		case err := <-f.Err():
+			fail(msgs[i], natsAckError(err))
+		case <-ctx.Done():
+			fail(msgs[i], errors.New("nats: publish was not acknowledged by a stream"))
+		}
+	}
+	return failed, firstErr
+}
+
+// Close closes the connection.
+func (p *NATSPublisher) Close() error {
+	if p.conn == nil {
+		return nil
+	}
+	p.conn.Close()
+	p.conn = nil
+	return nil
+}
+
+func natsAckError(err error) error {
+	var apiErr *jetstream.APIError
+	if errors.As(err, &apiErr) {
+		return fmt.Errorf("nats: JetStream error %d: %s", apiErr.Code, apiErr.Description)
+	}
+	return fmt.Errorf("nats: %w", err)
+}
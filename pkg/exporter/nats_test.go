@@ -0,0 +1,171 @@
+package exporter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNATSServer speaks enough of the NATS protocol to accept HPUBs and
+// answer each with a JetStream acknowledgement, or with a no-responders
+// status when noStream is set.
+type fakeNATSServer struct {
+	t        *testing.T
+	ln       net.Listener
+	noStream bool
+	// nackFirst rejects the first publish with a JetStream error.
+	nackFirst bool
+
+	mu       sync.Mutex
+	connect  string
+	headers  []string
+	payloads []string
+}
+
+func newFakeNATSServer(t *testing.T) *fakeNATSServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeNATSServer{t: t, ln: ln}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *fakeNATSServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "INFO {\"server_id\":\"fake\",\"headers\":true,\"max_payload\":1048576}\r\n")
+
+	var sid string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		op, args, _ := strings.Cut(line, " ")
+		switch op {
+		case "CONNECT":
+			s.mu.Lock()
+			s.connect = args
+			s.mu.Unlock()
+		case "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case "SUB":
+			fields := strings.Fields(args)
+			sid = fields[len(fields)-1]
+		case "HPUB":
+			fields := strings.Fields(args)
+			hdrLen, _ := strconv.Atoi(fields[2])
+			total, _ := strconv.Atoi(fields[3])
+			body := make([]byte, total+2)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.headers = append(s.headers, string(body[:hdrLen]))
+			s.payloads = append(s.payloads, string(body[hdrLen:total]))
+			nack := s.nackFirst && len(s.payloads) == 1
+			s.mu.Unlock()
+
+			reply := fields[1]
+			switch {
+			case s.noStream:
+				status := "NATS/1.0 503\r\n\r\n"
+				fmt.Fprintf(conn, "HMSG %s %s %d %d\r\n%s\r\n", reply, sid, len(status), len(status), status)
+			case nack:
+				ack := `{"error":{"code":503,"description":"stream offline"}}`
+				fmt.Fprintf(conn, "MSG %s %s %d\r\n%s\r\n", reply, sid, len(ack), ack)
+			default:
+				ack := `{"stream":"SCANS","seq":` + strconv.Itoa(len(s.payloads)) + `}`
+				fmt.Fprintf(conn, "PING\r\nMSG %s %s %d\r\n%s\r\n", reply, sid, len(ack), ack)
+			}
+		}
+	}
+}
+
+func TestNATSPublisherAcknowledged(t *testing.T) {
+	srv := newFakeNATSServer(t)
+	pub, err := NewNATSPublisher(NATSOptions{
+		Servers: []string{"nats://scanner:secret@" + srv.ln.Addr().String()},
+		Subject: "scans.results",
+	})
+	if err != nil {
+		t.Fatalf("NewNATSPublisher: %v", err)
+	}
+	defer pub.Close()
+
+	msgs := kafkaTestMessages("10.0.0.1", "10.0.0.2")
+	msgs[1].Key = nil
+	failed, err := pub.Publish(msgs)
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("Publish = %v, %v", failed, err)
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if !strings.Contains(srv.connect, `"user":"scanner"`) || !strings.Contains(srv.connect, `"headers":true`) {
+		t.Errorf("CONNECT = %s", srv.connect)
+	}
+	if len(srv.payloads) != 2 || srv.payloads[0] != `{"n":0}` {
+		t.Fatalf("payloads = %v", srv.payloads)
+	}
+	if !strings.Contains(srv.headers[0], "Portscan-Key: 10.0.0.1\r\n") || !strings.Contains(srv.headers[0], "Nats-Msg-Id: 0\r\n") {
+		t.Errorf("headers = %q", srv.headers[0])
+	}
+	if strings.Contains(srv.headers[1], "Portscan-Key") {
+		t.Errorf("a message without a key should not carry the header: %q", srv.headers[1])
+	}
+}
+
+func TestNATSPublisherNegativeAck(t *testing.T) {
+	srv := newFakeNATSServer(t)
+	srv.nackFirst = true
+	pub, err := NewNATSPublisher(NATSOptions{Servers: []string{srv.ln.Addr().String()}, Subject: "scans"})
+	if err != nil {
+		t.Fatalf("NewNATSPublisher: %v", err)
+	}
+	defer pub.Close()
+
+	failed, err := pub.Publish(kafkaTestMessages("a", "b"))
+	if len(failed) != 1 || err == nil || !strings.Contains(err.Error(), "stream offline") {
+		t.Fatalf("Publish = %d failed, %v; want the rejected message back", len(failed), err)
+	}
+	if failed, err = pub.Publish(failed); err != nil || len(failed) != 0 {
+		t.Errorf("resend = %v, %v", failed, err)
+	}
+}
+
+func TestNATSPublisherNoStream(t *testing.T) {
+	srv := newFakeNATSServer(t)
+	srv.noStream = true
+	pub, err := NewNATSPublisher(NATSOptions{Servers: []string{srv.ln.Addr().String()}, Subject: "scans", AckTimeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewNATSPublisher: %v", err)
+	}
+	defer pub.Close()
+
+	msgs := kafkaTestMessages("a")
+	failed, err := pub.Publish(msgs)
+	var permanent *permanentError
+	if !errors.As(err, &permanent) || len(failed) != 1 {
+		t.Fatalf("Publish = %d failed, %v; want a permanent no-stream error", len(failed), err)
+	}
+}
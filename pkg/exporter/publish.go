@@ -0,0 +1,217 @@
+package exporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+// Message keys for published results.
+const (
+	MessageKeyHost     = "host"
+	MessageKeyHostPort = "host_port"
+	MessageKeyNone     = "none"
+)
+
+// Message is one published result: a JSON document and the key that
+// decides its partition. ID is unique per message and stays the same when
+// it is resent, so brokers that de-duplicate can drop the copies.
+type Message struct {
+	ID    string
+	Key   []byte
+	Value []byte
+	Time  time.Time
+}
+
+// Publisher delivers messages to a broker. Publish returns once the broker
+// has acknowledged every message, or returns the unacknowledged ones with
+// the error that stopped them so they can be sent again.
+type Publisher interface {
+	Publish(msgs []Message) ([]Message, error)
+	Close() error
+}
+
+// PublishOptions configures a PublishExporter.
+type PublishOptions struct {
+	// Key selects the message key: MessageKeyHost (the default),
+	// MessageKeyHostPort, or MessageKeyNone.
+	Key string
+	// BatchSize is how many results are published together.
+	BatchSize int
+	// FlushInterval publishes a partial batch after this long.
+	FlushInterval time.Duration
+	// MaxRetries is how many times unacknowledged messages are resent, with
+	// exponential backoff starting at Backoff. Zero uses the default and a
+	// negative value disables retries.
+	MaxRetries int
+	Backoff    time.Duration
+	// BannerEncoding is BannerEncodingText or BannerEncodingBase64.
+	BannerEncoding string
+}
+
+// Publish defaults.
+const (
+	DefaultPublishBatchSize     = 100
+	DefaultPublishFlushInterval = time.Second
+	DefaultPublishMaxRetries    = 5
+	DefaultPublishBackoff       = 250 * time.Millisecond
+	maxPublishBackoff           = 10 * time.Second
+)
+
+// PublishExporter streams each result to a message broker as a JSON
+// document, using the same fields as the JSON exporter. Delivery is at
+// least once: unacknowledged messages are resent, so consumers may see
+// duplicates after a broker failover. Once a batch cannot be delivered the
+// remaining results are dropped and Close reports the error.
+type PublishExporter struct {
+	pub       Publisher
+	opts      PublishOptions
+	batch     []Message
+	runID     string
+	seq       uint64
+	published int
+	err       error
+	sleep     func(time.Duration)
+}
+
+// NewPublishExporter creates an exporter publishing through pub. The
+// exporter owns pub and closes it.
+func NewPublishExporter(pub Publisher, opts PublishOptions) (*PublishExporter, error) {
+	switch opts.Key {
+	case "":
+		opts.Key = MessageKeyHost
+	case MessageKeyHost, MessageKeyHostPort, MessageKeyNone:
+	default:
+		return nil, fmt.Errorf("unsupported message key %q: use %s, %s or %s", opts.Key, MessageKeyHost, MessageKeyHostPort, MessageKeyNone)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultPublishBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultPublishFlushInterval
+	}
+	switch {
+	case opts.MaxRetries == 0:
+		opts.MaxRetries = DefaultPublishMaxRetries
+	case opts.MaxRetries < 0:
+		opts.MaxRetries = 0
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = DefaultPublishBackoff
+	}
+	return &PublishExporter{
+		pub:   pub,
+		opts:  opts,
+		runID: strconv.FormatInt(time.Now().UnixNano(), 36),
+		sleep: time.Sleep,
+	}, nil
+}
+
+// Published returns how many results the broker has acknowledged.
+func (e *PublishExporter) Published() int {
+	return e.published
+}
+
+// Export publishes result events in batches until the channel is closed.
+// Progress and error events are not published.
+func (e *PublishExporter) Export(events <-chan core.Event) {
+	ticker := time.NewTicker(e.opts.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.Kind != core.EventKindResult || event.Result == nil {
+				continue
+			}
+			e.add(*event.Result)
+			if len(e.batch) >= e.opts.BatchSize {
+				e.flush()
+			}
+		case <-ticker.C:
+			e.flush()
+		}
+	}
+}
+
+// Close publishes any buffered results, closes the publisher, and returns
+// the first delivery error.
+func (e *PublishExporter) Close() error {
+	e.flush()
+	if err := e.pub.Close(); err != nil && e.err == nil {
+		e.err = err
+	}
+	return e.err
+}
+
+func (e *PublishExporter) add(r core.ResultEvent) {
+	if e.err != nil {
+		return
+	}
+	value, err := json.Marshal(buildResultDTO(r, e.opts.BannerEncoding))
+	if err != nil {
+		e.err = fmt.Errorf("encode result: %w", err)
+		return
+	}
+	e.seq++
+	e.batch = append(e.batch, Message{
+		ID:    e.runID + "-" + strconv.FormatUint(e.seq, 10),
+		Key:   e.key(r),
+		Value: value,
+		Time:  time.Now(),
+	})
+}
+
+// key returns the message key for a result, or nil for MessageKeyNone.
+func (e *PublishExporter) key(r core.ResultEvent) []byte {
+	switch e.opts.Key {
+	case MessageKeyHostPort:
+		return []byte(net.JoinHostPort(r.Host, strconv.Itoa(int(r.Port))))
+	case MessageKeyNone:
+		return nil
+	default:
+		return []byte(r.Host)
+	}
+}
+
+// flush publishes the current batch, resending unacknowledged messages.
+func (e *PublishExporter) flush() {
+	if len(e.batch) == 0 || e.err != nil {
+		e.batch = e.batch[:0]
+		return
+	}
+
+	pending := e.batch
+	backoff := e.opts.Backoff
+	for attempt := 0; ; attempt++ {
+		failed, err := e.pub.Publish(pending)
+		e.published += len(pending) - len(failed)
+		if err == nil && len(failed) == 0 {
+			break
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			e.err = fmt.Errorf("publish results: %w", err)
+			break
+		}
+		if attempt >= e.opts.MaxRetries {
+			if err == nil {
+				err = fmt.Errorf("%d messages were not acknowledged", len(failed))
+			}
+			e.err = fmt.Errorf("publish results after %d retries: %w", attempt, err)
+			break
+		}
+		pending = failed
+		e.sleep(backoff)
+		backoff = min(backoff*2, maxPublishBackoff)
+	}
+	e.batch = e.batch[:0]
+}
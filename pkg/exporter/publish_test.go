@@ -0,0 +1,112 @@
+package exporter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+// fakePublisher acknowledges messages except those it is told to fail.
+type fakePublisher struct {
+	calls     [][]Message
+	failTimes int // fail the first message of this many calls
+	permanent bool
+	closed    bool
+}
+
+func (f *fakePublisher) Publish(msgs []Message) ([]Message, error) {
+	f.calls = append(f.calls, msgs)
+	if f.permanent {
+		return msgs, &permanentError{errors.New("rejected")}
+	}
+	if f.failTimes > 0 {
+		f.failTimes--
+		return msgs[:1], errors.New("not acknowledged")
+	}
+	return nil, nil
+}
+
+func (f *fakePublisher) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestPublishExporterKeysAndRetries(t *testing.T) {
+	pub := &fakePublisher{failTimes: 1}
+	exp, err := NewPublishExporter(pub, PublishOptions{Key: MessageKeyHostPort, BatchSize: 10})
+	if err != nil {
+		t.Fatalf("NewPublishExporter: %v", err)
+	}
+	exp.sleep = func(time.Duration) {}
+
+	results := []core.ResultEvent{
+		{Host: "10.0.0.1", Port: 22, State: core.StateOpen},
+		{Host: "10.0.0.1", Port: 80, State: core.StateClosed},
+	}
+	if err := WriteResults(exp, results); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	if len(pub.calls) != 2 {
+		t.Fatalf("Publish called %d times, want the batch then one resend", len(pub.calls))
+	}
+	first := pub.calls[0]
+	if string(first[0].Key) != "10.0.0.1:22" || !strings.Contains(string(first[0].Value), `"port":22`) {
+		t.Errorf("message = key %q value %s", first[0].Key, first[0].Value)
+	}
+	if first[0].ID == "" || first[0].ID == first[1].ID {
+		t.Errorf("messages need distinct IDs, got %q and %q", first[0].ID, first[1].ID)
+	}
+	if resent := pub.calls[1]; len(resent) != 1 || resent[0].ID != first[0].ID {
+		t.Errorf("only the unacknowledged message should be resent with its ID, got %v", resent)
+	}
+	if exp.Published() != 2 || !pub.closed {
+		t.Errorf("Published() = %d, closed = %v", exp.Published(), pub.closed)
+	}
+}
+
+func TestPublishExporterPermanentFailure(t *testing.T) {
+	pub := &fakePublisher{permanent: true}
+	exp, err := NewPublishExporter(pub, PublishOptions{Key: MessageKeyNone, BatchSize: 1})
+	if err != nil {
+		t.Fatalf("NewPublishExporter: %v", err)
+	}
+	exp.sleep = func(time.Duration) { t.Error("permanent failures should not be retried") }
+
+	err = WriteResults(exp, elasticResults(3))
+	if err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Fatalf("err = %v, want the rejection", err)
+	}
+	if len(pub.calls) != 1 {
+		t.Errorf("Publish called %d times, want results after the failure dropped", len(pub.calls))
+	}
+	if pub.calls[0][0].Key != nil {
+		t.Errorf("MessageKeyNone should leave the key nil, got %q", pub.calls[0][0].Key)
+	}
+	if exp.Published() != 0 {
+		t.Errorf("Published() = %d, want 0", exp.Published())
+	}
+}
+
+func TestNewPublishExporterRejectsUnknownKey(t *testing.T) {
+	if _, err := NewPublishExporter(&fakePublisher{}, PublishOptions{Key: "port"}); err == nil {
+		t.Error("an unknown key should be rejected")
+	}
+}
+
+func TestPublishExporterHostPortKeyBracketsIPv6(t *testing.T) {
+	pub := &fakePublisher{}
+	exp, err := NewPublishExporter(pub, PublishOptions{Key: MessageKeyHostPort, BatchSize: 10})
+	if err != nil {
+		t.Fatalf("NewPublishExporter: %v", err)
+	}
+	if err := WriteResults(exp, []core.ResultEvent{{Host: "2001:db8::1", Port: 443, State: core.StateOpen}}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if got := string(pub.calls[0][0].Key); got != "[2001:db8::1]:443" {
+		t.Errorf("key = %q, want [2001:db8::1]:443", got)
+	}
+}
@@ -0,0 +1,229 @@
+package exporter
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+// Syslog message formats.
+const (
+	SyslogFormatRFC5424 = "rfc5424"
+	SyslogFormatCEF     = "cef"
+)
+
+const (
+	// syslogPriority is facility local0 with severity notice.
+	syslogPriority = 16*8 + 5
+	// syslogSDID names the structured data element; 32473 is the private
+	// enterprise number reserved for documentation (RFC 5612).
+	syslogSDID = "portscan@32473"
+)
+
+// SyslogOptions configures a SyslogExporter.
+type SyslogOptions struct {
+	// Format is SyslogFormatRFC5424 (the default) or SyslogFormatCEF, which
+	// sends a CEF record as the syslog message.
+	Format string
+	// OctetCounting prefixes each message with its length (RFC 6587), as
+	// stream transports need. Otherwise each message ends with a newline,
+	// which datagram collectors ignore and line-based readers need.
+	OctetCounting bool
+	// Hostname identifies the scanning machine; defaults to os.Hostname.
+	Hostname string
+	// Version is the CEF device version; defaults to "dev".
+	Version string
+}
+
+// SyslogExporter emits one syslog message per open port, in RFC 5424 form
+// with the result in structured data, or as CEF, so results can be ingested
+// by a SIEM without a custom parser. Closed and filtered ports are skipped.
+type SyslogExporter struct {
+	writer   io.Writer
+	opts     SyslogOptions
+	pid      string
+	now      func() time.Time
+	writeErr error
+}
+
+// NewSyslogExporter creates a syslog exporter writing messages to w.
+func NewSyslogExporter(w io.Writer, opts SyslogOptions) *SyslogExporter {
+	if opts.Format == "" {
+		opts.Format = SyslogFormatRFC5424
+	}
+	if opts.Hostname == "" {
+		opts.Hostname, _ = os.Hostname()
+	}
+	if opts.Version == "" {
+		opts.Version = "dev"
+	}
+	return &SyslogExporter{
+		writer: w,
+		opts:   opts,
+		pid:    strconv.Itoa(os.Getpid()),
+		now:    time.Now,
+	}
+}
+
+// Export writes a message for each open port until the channel is closed.
+func (e *SyslogExporter) Export(events <-chan core.Event) {
+	for event := range events {
+		if event.Kind != core.EventKindResult || event.Result == nil || event.Result.State != core.StateOpen {
+			continue
+		}
+		if e.writeErr != nil {
+			continue
+		}
+		msg := e.format(*event.Result)
+		if e.opts.OctetCounting {
+			msg = strconv.Itoa(len(msg)) + " " + msg
+		} else {
+			msg += "\n"
+		}
+		if _, err := io.WriteString(e.writer, msg); err != nil {
+			e.writeErr = err
+		}
+	}
+}
+
+// Close returns the first write error.
+func (e *SyslogExporter) Close() error {
+	return e.writeErr
+}
+
+// format renders a result as an RFC 5424 message.
+func (e *SyslogExporter) format(r core.ResultEvent) string {
+	header := fmt.Sprintf("<%d>1 %s %s portscan %s open-port",
+		syslogPriority,
+		e.now().UTC().Format(time.RFC3339Nano),
+		syslogHeaderField(e.opts.Hostname),
+		e.pid,
+	)
+	service := EscapeBanner(resultService(r))
+	if e.opts.Format == SyslogFormatCEF {
+		return header + " - " + e.formatCEF(r, service)
+	}
+
+	var sd strings.Builder
+	sd.WriteString("[" + syslogSDID)
+	params := [][2]string{
+		{"host", r.Host},
+		{"port", strconv.Itoa(int(r.Port))},
+		{"protocol", resultProtocol(r)},
+		{"state", string(r.State)},
+		{"service", service},
+		{"response_time_ms", strconv.FormatInt(r.Duration.Milliseconds(), 10)},
+	}
+	if r.Banner != "" {
+		params = append(params, [2]string{"banner", EscapeBanner(r.Banner)})
+	}
+	for _, p := range params {
+		sd.WriteString(" " + p[0] + `="` + escapeSDValue(p[1]) + `"`)
+	}
+	sd.WriteString("]")
+
+	return fmt.Sprintf("%s %s Open port %s/%s on %s (%s)", header, sd.String(), strconv.Itoa(int(r.Port)), resultProtocol(r), r.Host, service)
+}
+
+// formatCEF renders a result as a CEF record. The scanned host is the
+// destination: dst for IP addresses and dhost for names.
+func (e *SyslogExporter) formatCEF(r core.ResultEvent, service string) string {
+	header := strings.Join([]string{
+		"CEF:0",
+		"lucchesi-sec",
+		"portscan",
+		escapeCEFHeader(e.opts.Version),
+		"open-port",
+		"Open port detected",
+		"3",
+	}, "|")
+
+	target := "dhost"
+	if net.ParseIP(r.Host) != nil {
+		target = "dst"
+	}
+	ext := []string{
+		"rt=" + strconv.FormatInt(e.now().UnixMilli(), 10),
+		"shost=" + escapeCEFValue(e.opts.Hostname),
+		target + "=" + escapeCEFValue(r.Host),
+		"dpt=" + strconv.Itoa(int(r.Port)),
+		"proto=" + strings.ToUpper(resultProtocol(r)),
+		"app=" + escapeCEFValue(service),
+		"cn1=" + strconv.FormatInt(r.Duration.Milliseconds(), 10),
+		"cn1Label=responseTimeMs",
+	}
+	if r.Banner != "" {
+		ext = append(ext, "msg="+escapeCEFValue(EscapeBanner(r.Banner)))
+	}
+	return header + "|" + strings.Join(ext, " ")
+}
+
+// syslogHeaderField returns a header value, or the nil value "-" for an
+// empty one. Header fields may not contain spaces.
+func syslogHeaderField(s string) string {
+	s = strings.ReplaceAll(s, " ", "_")
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// escapeSDValue escapes '"', '\' and ']' in a structured data value.
+func escapeSDValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(s)
+}
+
+// escapeCEFHeader escapes '\' and '|' in a CEF header field.
+func escapeCEFHeader(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`).Replace(s)
+}
+
+// escapeCEFValue escapes '\', '=' and line breaks in a CEF extension value.
+func escapeCEFValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`).Replace(s)
+}
+
+// DialSyslog connects to a syslog collector given as udp://host:port,
+// tcp://host:port or tls://host:port; the port defaults to 514, or 6514
+// for TLS. It reports whether the transport is a stream that needs octet
+// counting.
+func DialSyslog(target string, timeout time.Duration) (conn net.Conn, stream bool, err error) {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return nil, false, fmt.Errorf("invalid syslog address %q: want udp://, tcp:// or tls://host:port", target)
+	}
+	host := u.Host
+	port := "514"
+	if u.Scheme == "tls" {
+		port = "6514"
+	}
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	switch u.Scheme {
+	case "udp":
+		conn, err = dialer.Dial("udp", host)
+	case "tcp":
+		conn, err = dialer.Dial("tcp", host)
+		stream = true
+	case "tls":
+		conn, err = tls.DialWithDialer(dialer, "tcp", host, &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12})
+		stream = true
+	default:
+		return nil, false, fmt.Errorf("unsupported syslog transport %q: use udp, tcp or tls", u.Scheme)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("connect to syslog collector: %w", err)
+	}
+	return conn, stream, nil
+}
@@ -0,0 +1,136 @@
+package exporter
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+func newTestSyslogExporter(w *bytes.Buffer, opts SyslogOptions) *SyslogExporter {
+	opts.Hostname = "scanner01"
+	opts.Version = "1.2.0"
+	e := NewSyslogExporter(w, opts)
+	e.pid = "42"
+	e.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+	return e
+}
+
+func syslogResults() []core.ResultEvent {
+	return []core.ResultEvent{
+		{Host: "10.0.0.5", Port: 22, State: core.StateOpen, Banner: `SSH-2.0 "x"]`, Duration: 4 * time.Millisecond},
+		{Host: "10.0.0.5", Port: 23, State: core.StateClosed},
+		{Host: "db.example.com", Port: 5432, Protocol: "udp", State: core.StateOpen},
+	}
+}
+
+func TestSyslogExporterRFC5424(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteResults(newTestSyslogExporter(&buf, SyslogOptions{}), syslogResults()); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d messages, want one per open port:\n%s", len(lines), buf.String())
+	}
+	wantPrefix := "<133>1 2024-05-01T12:00:00Z scanner01 portscan 42 open-port [portscan@32473 host=\"10.0.0.5\" port=\"22\" protocol=\"tcp\" state=\"open\""
+	if !strings.HasPrefix(lines[0], wantPrefix) {
+		t.Errorf("message = %s\nwant prefix %s", lines[0], wantPrefix)
+	}
+	if !strings.Contains(lines[0], `banner="SSH-2.0 \"x\"\]"`) {
+		t.Errorf("structured data values should be escaped: %s", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "Open port 5432/udp on db.example.com (postgresql)") {
+		t.Errorf("message = %s", lines[1])
+	}
+}
+
+func TestSyslogExporterCEF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteResults(newTestSyslogExporter(&buf, SyslogOptions{Format: SyslogFormatCEF}), syslogResults()); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d messages, want 2", len(lines))
+	}
+	want := "<133>1 2024-05-01T12:00:00Z scanner01 portscan 42 open-port - CEF:0|lucchesi-sec|portscan|1.2.0|open-port|Open port detected|3|"
+	if !strings.HasPrefix(lines[0], want) {
+		t.Errorf("message = %s\nwant prefix %s", lines[0], want)
+	}
+	for _, field := range []string{"dst=10.0.0.5", "dpt=22", "proto=TCP", "shost=scanner01", "cn1=4"} {
+		if !strings.Contains(lines[0], field) {
+			t.Errorf("CEF record missing %s: %s", field, lines[0])
+		}
+	}
+	if !strings.Contains(lines[1], "dhost=db.example.com") || !strings.Contains(lines[1], "proto=UDP") {
+		t.Errorf("CEF record for a host name = %s", lines[1])
+	}
+}
+
+func TestSyslogExporterOctetCounting(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteResults(newTestSyslogExporter(&buf, SyslogOptions{OctetCounting: true}), syslogResults()[:1]); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	length, msg, ok := strings.Cut(buf.String(), " ")
+	if !ok {
+		t.Fatalf("missing length prefix: %q", buf.String())
+	}
+	if n, _ := strconv.Atoi(length); n != len(msg) {
+		t.Errorf("length prefix %s, message is %d bytes", length, len(msg))
+	}
+}
+
+func TestCEFEscaping(t *testing.T) {
+	if got := escapeCEFHeader(`a|b\c`); got != `a\|b\\c` {
+		t.Errorf("escapeCEFHeader = %q", got)
+	}
+	if got := escapeCEFValue("k=v\nx"); got != `k\=v\nx` {
+		t.Errorf("escapeCEFValue = %q", got)
+	}
+}
+
+func TestDialSyslog(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('>')
+		received <- line
+	}()
+
+	conn, stream, err := DialSyslog("tcp://"+ln.Addr().String(), time.Second)
+	if err != nil {
+		t.Fatalf("DialSyslog: %v", err)
+	}
+	defer conn.Close()
+	if !stream {
+		t.Error("tcp should be reported as a stream transport")
+	}
+	_, _ = conn.Write([]byte("12 <133>"))
+	if got := <-received; got != "12 <133>" {
+		t.Errorf("collector received %q", got)
+	}
+
+	for _, bad := range []string{"10.0.0.1:514", "http://collector:514"} {
+		if _, _, err := DialSyslog(bad, time.Second); err == nil {
+			t.Errorf("DialSyslog(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"github.com/lucchesi-sec/portscan/pkg/services"
+	"github.com/muesli/termenv"
+)
+
+// tableColumns defines the fixed column widths of the plain-text table.
+// Widths are fixed so rows can be written as they stream in; values longer
+// than a column are kept intact and push the following columns right.
+var tableColumns = []struct {
+	title string
+	width int
+}{
+	{"HOST", 24},
+	{"PORT", 6},
+	{"PROTO", 6},
+	{"STATE", 9},
+	{"SERVICE", 14},
+	{"LATENCY", 9},
+	{"BANNER", 0},
+}
+
+// maxTableBannerLength caps banners so each result stays on one short line.
+const maxTableBannerLength = 60
+
+// TableOptions configures a TableExporter.
+type TableOptions struct {
+	// Color highlights the state column. It only takes effect when the
+	// writer is a terminal.
+	Color bool
+	// OnlyOpen skips closed and filtered results.
+	OnlyOpen bool
+}
+
+// TableExporter streams scan results as an aligned plain-text table for
+// scripts, CI logs, and terminals where a full-screen UI is unwanted.
+type TableExporter struct {
+	writer        io.Writer
+	opts          TableOptions
+	stateStyles   map[core.ScanState]lipgloss.Style
+	headerWritten bool
+	writeErr      error
+}
+
+// NewTableExporter creates a table exporter that writes to w.
+func NewTableExporter(w io.Writer, opts TableOptions) *TableExporter {
+	renderer := lipgloss.NewRenderer(w)
+	if !opts.Color {
+		renderer.SetColorProfile(termenv.Ascii)
+	}
+	return &TableExporter{
+		writer: w,
+		opts:   opts,
+		stateStyles: map[core.ScanState]lipgloss.Style{
+			core.StateOpen:     renderer.NewStyle().Foreground(lipgloss.Color("10")),
+			core.StateClosed:   renderer.NewStyle().Foreground(lipgloss.Color("9")),
+			core.StateFiltered: renderer.NewStyle().Foreground(lipgloss.Color("11")),
+		},
+	}
+}
+
+// padCell left-aligns value in a column of the given width, leaving a
+// single space after values that fill or overflow it.
+func padCell(value string, width int) string {
+	if width == 0 {
+		return value
+	}
+	if pad := width - len(value); pad > 0 {
+		return value + strings.Repeat(" ", pad)
+	}
+	return value + " "
+}
+
+// flattenBanner collapses whitespace, escapes binary bytes, and caps the
+// banner length.
+func flattenBanner(banner string) string {
+	banner = InlineBanner(banner)
+	if len(banner) > maxTableBannerLength {
+		banner = banner[:maxTableBannerLength-3] + "..."
+	}
+	return banner
+}
+
+func (e *TableExporter) writeLine(line string) {
+	if e.writeErr != nil {
+		return
+	}
+	if _, err := io.WriteString(e.writer, strings.TrimRight(line, " ")+"\n"); err != nil {
+		e.writeErr = err
+	}
+}
+
+func (e *TableExporter) writeHeader() {
+	if e.headerWritten {
+		return
+	}
+	e.headerWritten = true
+	var b strings.Builder
+	for _, col := range tableColumns {
+		b.WriteString(padCell(col.title, col.width))
+	}
+	e.writeLine(b.String())
+}
+
+func (e *TableExporter) writeResult(r core.ResultEvent) {
+	protocol := resultProtocol(r)
+	state := string(r.State)
+	stateCell := padCell(state, tableColumns[3].width)
+	if style, ok := e.stateStyles[r.State]; ok {
+		// Style only the text so padding stays aligned when colored.
+		stateCell = style.Render(state) + stateCell[len(state):]
+	}
+
+	var b strings.Builder
+	b.WriteString(padCell(r.Host, tableColumns[0].width))
+	b.WriteString(padCell(fmt.Sprintf("%d", r.Port), tableColumns[1].width))
+	b.WriteString(padCell(protocol, tableColumns[2].width))
+	b.WriteString(stateCell)
+	b.WriteString(padCell(services.GetName(r.Port), tableColumns[4].width))
+	b.WriteString(padCell(fmt.Sprintf("%dms", r.Duration.Milliseconds()), tableColumns[5].width))
+	b.WriteString(flattenBanner(r.Banner))
+	e.writeLine(b.String())
+}
+
+// Export writes result events as table rows.
+func (e *TableExporter) Export(events <-chan core.Event) {
+	e.writeHeader()
+	for event := range events {
+		if event.Kind != core.EventKindResult {
+			continue
+		}
+		r := *event.Result
+		if e.opts.OnlyOpen && r.State != core.StateOpen {
+			continue
+		}
+		e.writeResult(r)
+	}
+}
+
+// Close returns any write error. The header is emitted even for empty scans.
+func (e *TableExporter) Close() error {
+	e.writeHeader()
+	return e.writeErr
+}
@@ -0,0 +1,75 @@
+package exporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+func TestTableExporterAlignsColumns(t *testing.T) {
+	var out bytes.Buffer
+	exp := NewTableExporter(&out, TableOptions{Color: true})
+
+	ch := make(chan core.Event, 3)
+	ch <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen, Banner: "SSH-2.0-OpenSSH\r\n", Duration: 5 * time.Millisecond})
+	ch <- core.NewProgressEvent(core.ProgressEvent{Total: 4, Completed: 2, Rate: 100})
+	ch <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.2", Port: 8080, Protocol: "udp", State: core.StateFiltered})
+	close(ch)
+
+	exp.Export(ch)
+	if err := exp.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and two rows, got %q", out.String())
+	}
+	if strings.Contains(out.String(), "\x1b[") {
+		t.Error("non-terminal writers should not receive escape codes")
+	}
+	stateCol := strings.Index(lines[0], "STATE")
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(line[stateCol:], "open") && !strings.HasPrefix(line[stateCol:], "filtered") {
+			t.Errorf("state not aligned under header in %q", line)
+		}
+	}
+	if !strings.HasSuffix(lines[1], "SSH-2.0-OpenSSH") {
+		t.Errorf("banner should be flattened, got %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "udp") {
+		t.Errorf("expected protocol in row, got %q", lines[2])
+	}
+}
+
+func TestTableExporterOnlyOpen(t *testing.T) {
+	var out bytes.Buffer
+	exp := NewTableExporter(&out, TableOptions{OnlyOpen: true})
+
+	ch := make(chan core.Event, 2)
+	ch <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen})
+	ch <- core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 23, State: core.StateClosed})
+	close(ch)
+
+	exp.Export(ch)
+	if err := exp.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if strings.Contains(out.String(), " 23 ") {
+		t.Errorf("closed ports should be skipped, got %q", out.String())
+	}
+}
+
+func TestTableExporterEmptyScanWritesHeader(t *testing.T) {
+	var out bytes.Buffer
+	exp := NewTableExporter(&out, TableOptions{})
+	if err := exp.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "HOST") {
+		t.Errorf("expected header for empty scan, got %q", out.String())
+	}
+}
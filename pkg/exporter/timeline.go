@@ -0,0 +1,43 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+// buildTimelineDTO describes timeline entries, leaving out fields an entry
+// does not use.
+func buildTimelineDTO(entries []core.TimelineEntry) []map[string]interface{} {
+	dto := make([]map[string]interface{}, 0, len(entries))
+	for _, e := range entries {
+		entry := map[string]interface{}{
+			"time":  e.Time.UTC().Format(time.RFC3339Nano),
+			"event": e.Kind,
+		}
+		if e.Host != "" {
+			entry["host"] = e.Host
+		}
+		if e.Port != 0 {
+			entry["port"] = e.Port
+		}
+		if e.Rate > 0 {
+			entry["rate"] = e.Rate
+		}
+		if e.Detail != "" {
+			entry["detail"] = e.Detail
+		}
+		dto = append(dto, entry)
+	}
+	return dto
+}
+
+// WriteTimeline writes timeline entries to w as an indented JSON array,
+// the format of --timeline-file.
+func WriteTimeline(w io.Writer, entries []core.TimelineEntry) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(buildTimelineDTO(entries))
+}
@@ -0,0 +1,19 @@
+// Package geoip looks up the country and autonomous system of addresses in
+// MaxMind DB files, such as the free GeoLite2-Country and GeoLite2-ASN
+// databases, decoded with github.com/oschwald/maxminddb-golang.
+//
+// Example usage:
+//
+//	db, err := geoip.Open("GeoLite2-Country.mmdb", "GeoLite2-ASN.mmdb")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	loc := db.Lookup(netip.MustParseAddr("8.8.8.8"))
+//	fmt.Printf("%s AS%d %s\n", loc.Country, loc.ASN, loc.ASOrg)
+//
+// Databases:
+//
+// Files are read into memory whole. City, Country, ASN and ISP databases
+// are understood, and Lookup merges the country of one with the autonomous
+// system of another.
+package geoip
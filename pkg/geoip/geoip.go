@@ -0,0 +1,96 @@
+package geoip
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+
+	"github.com/oschwald/maxminddb-golang/v2"
+)
+
+// ErrInvalidDatabase reports a file that is not a well-formed MaxMind DB.
+var ErrInvalidDatabase = errors.New("invalid MaxMind DB")
+
+// Location is what the databases record about an address. Fields a
+// database does not cover are left empty.
+type Location struct {
+	Country     string // ISO 3166-1 alpha-2 code, e.g. "US"
+	CountryName string // English country name, e.g. "United States"
+	ASN         uint32 // autonomous system number, e.g. 15169
+	ASOrg       string // autonomous system organization, e.g. "GOOGLE"
+}
+
+// IsZero reports whether nothing is known about the address.
+func (l Location) IsZero() bool {
+	return l == Location{}
+}
+
+// DB looks addresses up in one or more MaxMind DB files, such as a
+// GeoLite2-Country and a GeoLite2-ASN database, merging what each knows.
+type DB struct {
+	files []*maxminddb.Reader
+	types []string
+}
+
+// record is the part of a City, Country, ASN or ISP record Lookup reads.
+type record struct {
+	Country           country `maxminddb:"country"`
+	RegisteredCountry country `maxminddb:"registered_country"`
+	ASN               uint32  `maxminddb:"autonomous_system_number"`
+	ASOrg             string  `maxminddb:"autonomous_system_organization"`
+}
+
+type country struct {
+	ISOCode string            `maxminddb:"iso_code"`
+	Names   map[string]string `maxminddb:"names"`
+}
+
+// Open reads the MaxMind DB files at paths into memory.
+func Open(paths ...string) (*DB, error) {
+	db := &DB{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		file, err := maxminddb.OpenBytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w: %w", path, ErrInvalidDatabase, err)
+		}
+		db.files = append(db.files, file)
+		db.types = append(db.types, file.Metadata.DatabaseType)
+	}
+	return db, nil
+}
+
+// Types returns the database type of each file, e.g. "GeoLite2-ASN".
+func (db *DB) Types() []string {
+	return db.types
+}
+
+// Lookup returns what the databases record about ip. An earlier file wins
+// where two cover the same field; a file with a corrupt record for ip is
+// skipped.
+func (db *DB) Lookup(ip netip.Addr) Location {
+	// An IPv4-mapped address is looked up as the IPv4 address it holds.
+	ip = ip.Unmap()
+	var loc Location
+	for _, file := range db.files {
+		var rec record
+		if err := file.Lookup(ip).Decode(&rec); err != nil {
+			continue
+		}
+		if loc.Country == "" {
+			c := rec.Country
+			if c.ISOCode == "" {
+				c = rec.RegisteredCountry
+			}
+			loc.Country, loc.CountryName = c.ISOCode, c.Names["en"]
+		}
+		if loc.ASN == 0 && rec.ASN != 0 {
+			loc.ASN, loc.ASOrg = rec.ASN, rec.ASOrg
+		}
+	}
+	return loc
+}
@@ -0,0 +1,218 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"testing"
+)
+
+// metadataMarker starts the metadata section at the end of a MaxMind DB.
+var metadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+// Data types of the MaxMind DB format's data section that buildMMDB writes.
+const (
+	typeString = 2
+	typeMap    = 7
+	typeUint64 = 9
+)
+
+// testDB describes a MaxMind DB for buildMMDB.
+type testDB struct {
+	ipVersion    int
+	recordSize   int
+	databaseType string
+	networks     map[string]map[string]any // prefix -> record
+}
+
+// buildMMDB writes a MaxMind DB holding db's networks.
+func buildMMDB(t *testing.T, db testDB) []byte {
+	t.Helper()
+	const empty = -1
+	type node struct{ records [2]int } // node index, empty, or -2-dataIndex
+	nodes := []node{{[2]int{empty, empty}}}
+	var data bytes.Buffer
+	prefixes := make([]string, 0, len(db.networks))
+	for p := range db.networks {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+	offsets := map[string]int{}
+	for _, p := range prefixes {
+		offsets[p] = data.Len()
+		encodeValue(&data, db.networks[p])
+	}
+
+	for _, p := range prefixes {
+		prefix := netip.MustParsePrefix(p)
+		addr := prefix.Addr().AsSlice()
+		bits := prefix.Bits()
+		if db.ipVersion == 6 && prefix.Addr().Is4() {
+			addr = append(make([]byte, 12), addr...)
+			bits += 96
+		}
+		n := 0
+		for i := 0; i < bits; i++ {
+			bit := int(addr[i/8]>>(7-i%8)) & 1
+			if i == bits-1 {
+				nodes[n].records[bit] = -2 - offsets[p]
+				break
+			}
+			if nodes[n].records[bit] == empty {
+				nodes = append(nodes, node{[2]int{empty, empty}})
+				nodes[n].records[bit] = len(nodes) - 1
+			}
+			n = nodes[n].records[bit]
+		}
+	}
+
+	nodeCount := len(nodes)
+	var out bytes.Buffer
+	for _, n := range nodes {
+		var values [2]uint32
+		for i, r := range n.records {
+			switch {
+			case r == empty:
+				values[i] = uint32(nodeCount)
+			case r <= -2:
+				values[i] = uint32(nodeCount + 16 + (-2 - r))
+			default:
+				values[i] = uint32(r)
+			}
+		}
+		switch db.recordSize {
+		case 24:
+			out.Write([]byte{byte(values[0] >> 16), byte(values[0] >> 8), byte(values[0]),
+				byte(values[1] >> 16), byte(values[1] >> 8), byte(values[1])})
+		case 28:
+			out.Write([]byte{byte(values[0] >> 16), byte(values[0] >> 8), byte(values[0]),
+				byte(values[0]>>20)&0xf0 | byte(values[1]>>24)&0x0f,
+				byte(values[1] >> 16), byte(values[1] >> 8), byte(values[1])})
+		default:
+			_ = binary.Write(&out, binary.BigEndian, values)
+		}
+	}
+	out.Write(make([]byte, 16))
+	out.Write(data.Bytes())
+	out.Write(metadataMarker)
+	encodeValue(&out, map[string]any{
+		"node_count":    uint64(nodeCount),
+		"record_size":   uint64(db.recordSize),
+		"ip_version":    uint64(db.ipVersion),
+		"database_type": db.databaseType,
+	})
+	return out.Bytes()
+}
+
+// encodeValue writes v in the data section format.
+func encodeValue(buf *bytes.Buffer, v any) {
+	switch v := v.(type) {
+	case string:
+		if len(v) < 29 {
+			buf.WriteByte(typeString<<5 | byte(len(v)))
+		} else {
+			buf.WriteByte(typeString<<5 | 29)
+			buf.WriteByte(byte(len(v) - 29))
+		}
+		buf.WriteString(v)
+	case uint64:
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], v)
+		trimmed := bytes.TrimLeft(b[:], "\x00")
+		buf.WriteByte(byte(len(trimmed)))
+		buf.WriteByte(typeUint64 - 7)
+		buf.Write(trimmed)
+	case map[string]any:
+		buf.WriteByte(typeMap<<5 | byte(len(v)))
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			encodeValue(buf, k)
+			encodeValue(buf, v[k])
+		}
+	}
+}
+
+func writeMMDB(t *testing.T, db testDB) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), db.databaseType+".mmdb")
+	if err := os.WriteFile(path, buildMMDB(t, db), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func countryRecord(code, name string) map[string]any {
+	return map[string]any{"country": map[string]any{"iso_code": code, "names": map[string]any{"en": name}}}
+}
+
+func TestLookup(t *testing.T) {
+	for _, recordSize := range []int{24, 28, 32} {
+		country := writeMMDB(t, testDB{ipVersion: 6, recordSize: recordSize, databaseType: "GeoLite2-Country", networks: map[string]map[string]any{
+			"8.8.8.0/24":     countryRecord("US", "United States"),
+			"2a00:1450::/32": countryRecord("IE", "Ireland"),
+			"193.0.0.0/21":   {"registered_country": map[string]any{"iso_code": "NL"}},
+		}})
+		asn := writeMMDB(t, testDB{ipVersion: 4, recordSize: recordSize, databaseType: "GeoLite2-ASN", networks: map[string]map[string]any{
+			"8.8.8.0/24": {"autonomous_system_number": uint64(15169), "autonomous_system_organization": "GOOGLE"},
+		}})
+		db, err := Open(country, asn)
+		if err != nil {
+			t.Fatalf("record size %d: Open: %v", recordSize, err)
+		}
+		if got, want := db.Types(), []string{"GeoLite2-Country", "GeoLite2-ASN"}; !slices.Equal(got, want) {
+			t.Errorf("Types() = %q, want %q", got, want)
+		}
+
+		tests := []struct {
+			ip   string
+			want Location
+		}{
+			{"8.8.8.8", Location{Country: "US", CountryName: "United States", ASN: 15169, ASOrg: "GOOGLE"}},
+			{"::ffff:8.8.8.8", Location{Country: "US", CountryName: "United States", ASN: 15169, ASOrg: "GOOGLE"}},
+			{"8.8.4.4", Location{}},
+			{"2a00:1450:4001::1", Location{Country: "IE", CountryName: "Ireland"}},
+			{"193.0.6.139", Location{Country: "NL"}},
+			{"10.0.0.1", Location{}},
+		}
+		for _, tt := range tests {
+			if got := db.Lookup(netip.MustParseAddr(tt.ip)); got != tt.want {
+				t.Errorf("record size %d: Lookup(%s) = %+v, want %+v", recordSize, tt.ip, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestOpenRejectsInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	garbage := filepath.Join(dir, "garbage.mmdb")
+	if err := os.WriteFile(garbage, []byte("not a database"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(garbage); !errors.Is(err, ErrInvalidDatabase) {
+		t.Errorf("Open(garbage) = %v, want ErrInvalidDatabase", err)
+	}
+
+	truncated := buildMMDB(t, testDB{ipVersion: 4, recordSize: 24, databaseType: "Test", networks: map[string]map[string]any{
+		"8.8.8.0/24": countryRecord("US", "United States"),
+	}})
+	path := filepath.Join(dir, "truncated.mmdb")
+	if err := os.WriteFile(path, truncated[:len(truncated)-5], 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path); !errors.Is(err, ErrInvalidDatabase) {
+		t.Errorf("Open(truncated) = %v, want ErrInvalidDatabase", err)
+	}
+
+	if _, err := Open(filepath.Join(dir, "missing.mmdb")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Open(missing) = %v, want os.ErrNotExist", err)
+	}
+}
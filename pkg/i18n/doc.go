@@ -0,0 +1,23 @@
+// Package i18n translates user-facing messages.
+//
+// Messages live in YAML catalogs embedded from locales/, one file per
+// language (en.yaml, es.yaml). Sections nest, and a message is addressed by
+// its dotted path:
+//
+//	errors:
+//	  no_target:
+//	    message: No target specified
+//
+//	i18n.T("errors.no_target.message")
+//
+// Messages are fmt format strings; T formats them with its arguments, so
+// every translation must use the same verbs in the same order (or explicit
+// indexes such as %[2]s).
+//
+// Language Selection:
+//
+// The scan commands call SetLanguage(Detect(lang)) at startup, where lang
+// is the --lang flag. Without it, LC_ALL, LC_MESSAGES and LANG are checked
+// in that order, so LANG=es_ES.UTF-8 selects Spanish. English is the
+// default and the fallback for any message a catalog does not translate.
+package i18n
@@ -0,0 +1,156 @@
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Default is the language used when none is selected, and for any message a
+// catalog does not translate.
+const Default = "en"
+
+//go:embed locales/*.yaml
+var locales embed.FS
+
+var (
+	loadOnce sync.Once
+	catalogs map[string]map[string]string
+	current  atomic.Value // string
+	rewrite  atomic.Value // func(string) string
+)
+
+func load() map[string]map[string]string {
+	loadOnce.Do(func() {
+		catalogs = make(map[string]map[string]string)
+		entries, err := locales.ReadDir("locales")
+		if err != nil {
+			panic("i18n: " + err.Error())
+		}
+		for _, entry := range entries {
+			data, err := locales.ReadFile("locales/" + entry.Name())
+			if err != nil {
+				panic("i18n: " + err.Error())
+			}
+			var tree map[string]any
+			if err := yaml.Unmarshal(data, &tree); err != nil {
+				panic(fmt.Sprintf("i18n: invalid catalog %s: %v", entry.Name(), err))
+			}
+			messages := make(map[string]string)
+			flatten("", tree, messages)
+			catalogs[strings.TrimSuffix(entry.Name(), ".yaml")] = messages
+		}
+	})
+	return catalogs
+}
+
+// flatten turns nested catalog sections into dotted keys, so
+// errors: {no_target: {message: ...}} becomes "errors.no_target.message".
+func flatten(prefix string, tree map[string]any, out map[string]string) {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			flatten(key, v, out)
+		case string:
+			out[key] = v
+		}
+	}
+}
+
+// Languages returns the supported language codes, sorted.
+func Languages() []string {
+	langs := make([]string, 0, len(load()))
+	for lang := range load() {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Supported reports whether lang, a code such as "es" or a locale such as
+// "es_MX.UTF-8", names a supported language.
+func Supported(lang string) bool {
+	_, ok := load()[normalize(lang)]
+	return ok
+}
+
+// SetLanguage selects the language T translates into. Unsupported
+// languages select Default.
+func SetLanguage(lang string) {
+	lang = normalize(lang)
+	if _, ok := load()[lang]; !ok {
+		lang = Default
+	}
+	current.Store(lang)
+}
+
+// Language returns the selected language code.
+func Language() string {
+	if lang, ok := current.Load().(string); ok {
+		return lang
+	}
+	return Default
+}
+
+// SetTransform makes T rewrite each message with fn before formatting it,
+// such as to replace symbols the terminal cannot draw. A nil fn leaves
+// messages unchanged.
+func SetTransform(fn func(string) string) {
+	if fn == nil {
+		fn = func(s string) string { return s }
+	}
+	rewrite.Store(fn)
+}
+
+// Detect picks the language to use: explicit (from --lang) when set,
+// otherwise the first supported language in LC_ALL, LC_MESSAGES and LANG,
+// otherwise Default.
+func Detect(explicit string) string {
+	if explicit != "" {
+		return normalize(explicit)
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" && Supported(value) {
+			return normalize(value)
+		}
+	}
+	return Default
+}
+
+// normalize reduces a locale such as "es_ES.UTF-8" to its language, "es".
+func normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// T returns the message for key in the selected language, formatted with
+// args as by fmt.Sprintf. Messages missing from the language fall back to
+// Default, and unknown keys are returned as is.
+func T(key string, args ...any) string {
+	all := load()
+	message, ok := all[Language()][key]
+	if !ok {
+		if message, ok = all[Default][key]; !ok {
+			return key
+		}
+	}
+	if fn, ok := rewrite.Load().(func(string) string); ok {
+		message = fn(message)
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
@@ -0,0 +1,117 @@
+package i18n
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestT_SelectedLanguage(t *testing.T) {
+	defer SetLanguage(Default)
+
+	SetLanguage("en")
+	if got := T("errors.rate_limit_high.message", 20000); got != "Rate limit too high: 20000 pps" {
+		t.Errorf("en: got %q", got)
+	}
+	SetLanguage("es_MX.UTF-8")
+	if Language() != "es" {
+		t.Fatalf("Language() = %q, want es", Language())
+	}
+	if got := T("errors.rate_limit_high.message", 20000); got != "Límite de velocidad demasiado alto: 20000 pps" {
+		t.Errorf("es: got %q", got)
+	}
+}
+
+func TestT_Fallbacks(t *testing.T) {
+	defer SetLanguage(Default)
+
+	SetLanguage("xx")
+	if Language() != Default {
+		t.Errorf("unsupported language should select %s, got %s", Default, Language())
+	}
+	if got := T("no.such.key"); got != "no.such.key" {
+		t.Errorf("unknown key should be returned as is, got %q", got)
+	}
+}
+
+func TestT_Transform(t *testing.T) {
+	defer SetTransform(nil)
+
+	SetTransform(strings.NewReplacer("Rate", "Speed").Replace)
+	if got := T("errors.rate_limit_high.message", 20000); got != "Speed limit too high: 20000 pps" {
+		t.Errorf("transformed: got %q", got)
+	}
+	SetTransform(func(s string) string { return strings.ReplaceAll(s, "%d", "x") })
+	if got := T("errors.rate_limit_high.message", 20000); got != "Rate limit too high: x pps%!(EXTRA int=20000)" {
+		t.Errorf("transform should apply before formatting, got %q", got)
+	}
+	SetTransform(nil)
+	if got := T("errors.rate_limit_high.message", 20000); got != "Rate limit too high: 20000 pps" {
+		t.Errorf("nil transform: got %q", got)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "C")
+	t.Setenv("LANG", "es_ES.UTF-8")
+	if got := Detect(""); got != "es" {
+		t.Errorf("Detect from LANG = %q, want es", got)
+	}
+	if got := Detect("EN"); got != "en" {
+		t.Errorf("explicit language should win, got %q", got)
+	}
+	t.Setenv("LANG", "fr_FR.UTF-8")
+	if got := Detect(""); got != Default {
+		t.Errorf("unsupported LANG should fall back to %s, got %q", Default, got)
+	}
+}
+
+// verbPattern matches fmt verbs, ignoring escaped percent signs.
+var verbPattern = regexp.MustCompile(`%(?:\[\d+\])?[-+# 0]*\d*(?:\.\d+)?[a-zA-Z]`)
+
+func TestCatalogs_MatchReference(t *testing.T) {
+	reference := load()[Default]
+	for _, lang := range Languages() {
+		for key, message := range load()[lang] {
+			want, ok := reference[key]
+			if !ok {
+				t.Errorf("%s: key %s is not in %s.yaml", lang, key, Default)
+				continue
+			}
+			if got, exp := len(verbPattern.FindAllString(message, -1)), len(verbPattern.FindAllString(want, -1)); got != exp {
+				t.Errorf("%s: %s has %d format verbs, %s has %d", lang, key, got, Default, exp)
+			}
+		}
+	}
+}
+
+// TestCatalogs_CoverSource fails when code calls T with a literal key that
+// the reference catalog lacks.
+func TestCatalogs_CoverSource(t *testing.T) {
+	keyPattern := regexp.MustCompile(`i18n\.T\("([^"]+)"`)
+	reference := load()[Default]
+	for _, dir := range []string{"../../cmd", "../../internal", "../../pkg"} {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			for _, m := range keyPattern.FindAllSubmatch(data, -1) {
+				if _, ok := reference[string(m[1])]; !ok {
+					t.Errorf("%s: key %s is not in %s.yaml", path, m[1], Default)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Package importer converts other scanners' output into portscan results.
+//
+// Imported results use the same model as live scans (core.ResultEvent), so
+// archived scans can be exported in any portscan format and compared with
+// new runs.
+//
+// Supported Formats:
+//
+// 1. nmap XML (-oX / -oA)
+//
+// masscan -oX output shares this layout and is read the same way.
+//
+// Every <port> of every host that is up becomes one result. The host is
+// its IPv4 or IPv6 address, falling back to the first hostname. Port states
+// map as follows:
+//
+//   - open: open
+//   - closed: closed
+//   - filtered, open|filtered, closed|filtered: filtered
+//   - unfiltered (ACK scans) and SCTP ports: skipped, as portscan has no
+//     equivalent
+//
+// The banner is the output of nmap's banner script when present, otherwise
+// the detected product, version and extra info. The host's smoothed round
+// trip time is used as the response time. Ports nmap collapsed into
+// <extraports> are not listed individually and are not imported.
+//
+// 2. masscan JSON (-oJ / -oD) and list (-oL)
+//
+// Each open port becomes one result, with banner records (from --banners)
+// merged into the port they belong to. Entries for other protocols, such as
+// ICMP or SCTP, are skipped. masscan 1.0's malformed trailing
+// "{finished: 1}" is tolerated.
+//
+// Example usage:
+//
+//	f, err := os.Open("archive/nmap-2023-01.xml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer f.Close()
+//	report, err := importer.Parse(importer.FormatNmapXML, f)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("%d results from %s\n", len(report.Results), report.Scanner)
+package importer
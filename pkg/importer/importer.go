@@ -0,0 +1,85 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+// Import formats.
+const (
+	FormatAuto        = "auto"
+	FormatNmapXML     = "nmap-xml"
+	FormatMasscanJSON = "masscan-json"
+	FormatMasscanList = "masscan-list"
+)
+
+// Formats lists the formats accepted by Parse.
+var Formats = []string{FormatNmapXML, FormatMasscanJSON, FormatMasscanList}
+
+// Report is an imported scan.
+type Report struct {
+	// Scanner names the tool and version that produced the scan, e.g.
+	// "nmap 7.94".
+	Scanner string
+	// Args is the command line of the original scan, when recorded.
+	Args  string
+	Start time.Time
+	End   time.Time
+	// Targets lists each scanned host once, in order of first appearance.
+	Targets []string
+	Results []core.ResultEvent
+	// Skipped counts ports that have no portscan equivalent.
+	Skipped int
+}
+
+// Parse reads a scan in the given format. FormatAuto, or an empty format,
+// detects it from the content.
+func Parse(format string, r io.Reader) (*Report, error) {
+	br := bufio.NewReader(r)
+	if format == "" || format == FormatAuto {
+		head, _ := br.Peek(4096)
+		detected, err := DetectFormat(head)
+		if err != nil {
+			return nil, err
+		}
+		format = detected
+	}
+	switch strings.ToLower(format) {
+	case FormatNmapXML, "nmap", "xml":
+		return parseNmapXML(br)
+	case FormatMasscanJSON:
+		return parseMasscanJSON(br)
+	case FormatMasscanList:
+		return parseMasscanList(br)
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s (supported: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// DetectFormat identifies the import format from the start of a file.
+func DetectFormat(head []byte) (string, error) {
+	trimmed := bytes.TrimSpace(head)
+	switch {
+	case bytes.Contains(head, []byte("<nmaprun")):
+		return FormatNmapXML, nil
+	case bytes.HasPrefix(trimmed, []byte("#masscan")):
+		return FormatMasscanList, nil
+	case (bytes.HasPrefix(trimmed, []byte("[")) || bytes.HasPrefix(trimmed, []byte("{"))) && bytes.Contains(head, []byte(`"ip"`)):
+		return FormatMasscanJSON, nil
+	}
+	return "", fmt.Errorf("unrecognized import format (supported: %s)", strings.Join(Formats, ", "))
+}
+
+// addTarget records host in the report's target list once.
+func (r *Report) addTarget(host string, seen map[string]bool) {
+	if !seen[host] {
+		seen[host] = true
+		r.Targets = append(r.Targets, host)
+	}
+}
@@ -0,0 +1,23 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDetectsFormat(t *testing.T) {
+	report, err := Parse(FormatAuto, strings.NewReader(nmapSample))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(report.Results) == 0 {
+		t.Error("auto-detected nmap XML should yield results")
+	}
+
+	if _, err := Parse("", strings.NewReader("host,port\n10.0.0.1,22\n")); err == nil {
+		t.Error("unrecognized content should fail detection")
+	}
+	if _, err := Parse("zmap", strings.NewReader(nmapSample)); err == nil {
+		t.Error("an unknown format should be rejected")
+	}
+}
@@ -0,0 +1,175 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+// masscanRecord is one entry of masscan's JSON output: a port status or a
+// banner for one host and port.
+type masscanRecord struct {
+	IP        string          `json:"ip"`
+	Timestamp json.RawMessage `json:"timestamp"`
+	Ports     []struct {
+		Port    int    `json:"port"`
+		Proto   string `json:"proto"`
+		Status  string `json:"status"`
+		Service *struct {
+			Name   string `json:"name"`
+			Banner string `json:"banner"`
+		} `json:"service"`
+	} `json:"ports"`
+}
+
+// masscanResults merges port and banner records, which masscan writes
+// separately, into one result per host, protocol and port.
+type masscanResults struct {
+	report *Report
+	index  map[string]int
+	seen   map[string]bool
+}
+
+func newMasscanResults() *masscanResults {
+	return &masscanResults{
+		report: &Report{Scanner: "masscan"},
+		index:  make(map[string]int),
+		seen:   make(map[string]bool),
+	}
+}
+
+// add records a port status or, when status is empty, a banner.
+func (m *masscanResults) add(host, proto string, port int, status, banner string, ts int64) {
+	if proto != "tcp" && proto != "udp" || port < 1 || port > 65535 || host == "" {
+		m.report.Skipped++
+		return
+	}
+	if ts > 0 {
+		t := time.Unix(ts, 0).UTC()
+		if m.report.Start.IsZero() || t.Before(m.report.Start) {
+			m.report.Start = t
+		}
+		if t.After(m.report.End) {
+			m.report.End = t
+		}
+	}
+
+	key := host + "/" + proto + "/" + strconv.Itoa(port)
+	i, ok := m.index[key]
+	if !ok {
+		m.report.addTarget(host, m.seen)
+		i = len(m.report.Results)
+		m.index[key] = i
+		// A banner implies the port answered, even if its status record
+		// is missing.
+		m.report.Results = append(m.report.Results, core.ResultEvent{
+			Host: host, Port: uint16(port), State: core.StateOpen, Protocol: proto,
+		})
+	}
+	r := &m.report.Results[i]
+	switch status {
+	case "":
+	case "open":
+		r.State = core.StateOpen
+	case "closed":
+		r.State = core.StateClosed
+	default:
+		r.State = core.StateFiltered
+	}
+	if banner != "" && r.Banner == "" {
+		r.Banner = banner
+	}
+}
+
+// parseMasscanJSON reads masscan -oJ output, a JSON array with one record
+// per line, or -oD output with one record per line and no array. Older
+// masscan versions leave a trailing comma after the last record, so the
+// input is read line by line rather than as a single document.
+func parseMasscanJSON(r io.Reader) (*Report, error) {
+	m := newMasscanResults()
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		text := bytes.TrimSpace(sc.Bytes())
+		text = bytes.TrimSuffix(text, []byte(","))
+		text = bytes.TrimPrefix(text, []byte("["))
+		text = bytes.TrimSuffix(text, []byte("]"))
+		if len(bytes.TrimSpace(text)) == 0 {
+			continue
+		}
+		var rec masscanRecord
+		if err := json.Unmarshal(text, &rec); err != nil {
+			// masscan 1.0 ends -oJ output with a bare {finished: 1}.
+			if bytes.Contains(text, []byte("finished")) {
+				continue
+			}
+			return nil, fmt.Errorf("parse masscan JSON: line %d: %w", line, err)
+		}
+		ts := masscanTimestamp(rec.Timestamp)
+		for _, p := range rec.Ports {
+			banner := ""
+			if p.Service != nil {
+				banner = p.Service.Banner
+			}
+			m.add(rec.IP, p.Proto, p.Port, p.Status, banner, ts)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("parse masscan JSON: %w", err)
+	}
+	return m.report, nil
+}
+
+// masscanTimestamp reads a timestamp written as a string or a number.
+func masscanTimestamp(raw json.RawMessage) int64 {
+	ts, _ := strconv.ParseInt(strings.Trim(string(raw), `"`), 10, 64)
+	return ts
+}
+
+// parseMasscanList reads masscan -oL output:
+//
+//	#masscan
+//	open tcp 80 10.0.0.1 1714564800
+//	banner tcp 80 10.0.0.1 1714564801 http HTTP/1.1 200 OK
+//	# end
+func parseMasscanList(r io.Reader) (*Report, error) {
+	m := newMasscanResults()
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.SplitN(text, " ", 7)
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("parse masscan list: line %d: want \"<status> <proto> <port> <ip> <time>\", got %q", line, text)
+		}
+		port, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("parse masscan list: line %d: invalid port %q", line, fields[2])
+		}
+		ts, _ := strconv.ParseInt(fields[4], 10, 64)
+		switch fields[0] {
+		case "banner":
+			banner := ""
+			if len(fields) == 7 {
+				banner = fields[6]
+			}
+			m.add(fields[3], fields[1], port, "", banner, ts)
+		default:
+			m.add(fields[3], fields[1], port, fields[0], "", ts)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("parse masscan list: %w", err)
+	}
+	return m.report, nil
+}
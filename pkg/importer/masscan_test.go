@@ -0,0 +1,99 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+// masscanJSON is masscan 1.0 -oJ output with --banners, including its
+// trailing comma and bare finished record.
+const masscanJSON = `[
+{   "ip": "10.0.0.1",   "timestamp": "1714564800", "ports": [ {"port": 80, "proto": "tcp", "status": "open", "reason": "syn-ack", "ttl": 64} ] },
+{   "ip": "10.0.0.1",   "timestamp": "1714564802", "ports": [ {"port": 80, "proto": "tcp", "service": {"name": "http", "banner": "HTTP/1.1 200 OK"} } ] },
+{   "ip": "10.0.0.2",   "timestamp": "1714564801", "ports": [ {"port": 53, "proto": "udp", "status": "open", "reason": "udp-response", "ttl": 63} ] },
+{   "ip": "10.0.0.3",   "timestamp": "1714564801", "ports": [ {"port": 0, "proto": "icmp", "status": "open", "reason": "none", "ttl": 63} ] },
+{finished: 1}
+]
+`
+
+const masscanList = `#masscan
+open tcp 22 10.0.0.1 1714564800
+banner tcp 22 10.0.0.1 1714564801 ssh SSH-2.0-OpenSSH_9.6
+open tcp 443 10.0.0.2 1714564800
+# end
+`
+
+func TestParseMasscanJSON(t *testing.T) {
+	report, err := Parse(FormatAuto, strings.NewReader(masscanJSON))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []core.ResultEvent{
+		{Host: "10.0.0.1", Port: 80, State: core.StateOpen, Protocol: "tcp", Banner: "HTTP/1.1 200 OK"},
+		{Host: "10.0.0.2", Port: 53, State: core.StateOpen, Protocol: "udp"},
+	}
+	if len(report.Results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(report.Results), len(want), report.Results)
+	}
+	for i, w := range want {
+		if !reflect.DeepEqual(report.Results[i], w) {
+			t.Errorf("result %d = %+v, want %+v", i, report.Results[i], w)
+		}
+	}
+	if report.Scanner != "masscan" || report.Skipped != 1 {
+		t.Errorf("scanner %q, skipped %d", report.Scanner, report.Skipped)
+	}
+	if !report.Start.Equal(time.Unix(1714564800, 0)) || !report.End.Equal(time.Unix(1714564802, 0)) {
+		t.Errorf("start %v, end %v", report.Start, report.End)
+	}
+}
+
+func TestParseMasscanNDJSON(t *testing.T) {
+	ndjson := `{"ip":"10.0.0.9","timestamp":1714564800,"ports":[{"port":8080,"proto":"tcp","status":"open"}]}` + "\n"
+	report, err := Parse(FormatMasscanJSON, strings.NewReader(ndjson))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(report.Results) != 1 || report.Results[0].Port != 8080 || report.Start.IsZero() {
+		t.Errorf("report = %+v", report)
+	}
+
+	if _, err := Parse(FormatMasscanJSON, strings.NewReader(`{"ip": "10.0.0.1", "ports": [`)); err == nil {
+		t.Error("malformed JSON should fail")
+	}
+}
+
+func TestParseMasscanList(t *testing.T) {
+	report, err := Parse(FormatAuto, strings.NewReader(masscanList))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(report.Results) != 2 || len(report.Targets) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	if r := report.Results[0]; r.Port != 22 || r.Banner != "SSH-2.0-OpenSSH_9.6" {
+		t.Errorf("result = %+v, want the banner merged into port 22", r)
+	}
+
+	if _, err := Parse(FormatMasscanList, strings.NewReader("open tcp http 10.0.0.1 0\n")); err == nil {
+		t.Error("a non-numeric port should fail")
+	}
+}
+
+func TestParseMasscanXML(t *testing.T) {
+	xml := `<?xml version="1.0"?>
+<nmaprun scanner="masscan" start="1714564800" version="1.0-BETA">
+<host endtime="1714564800"><address addr="10.0.0.5" addrtype="ipv4"/><ports><port protocol="tcp" portid="3389"><state state="open" reason="syn-ack" reason_ttl="128"/></port></ports></host>
+</nmaprun>`
+	report, err := Parse(FormatAuto, strings.NewReader(xml))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if report.Scanner != "masscan 1.0-BETA" || len(report.Results) != 1 || report.Results[0].Port != 3389 {
+		t.Errorf("report = %+v", report)
+	}
+}
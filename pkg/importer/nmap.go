@@ -0,0 +1,179 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+// nmapRun mirrors the parts of nmap's XML output the importer uses.
+type nmapRun struct {
+	Scanner  string     `xml:"scanner,attr"`
+	Version  string     `xml:"version,attr"`
+	Args     string     `xml:"args,attr"`
+	Start    int64      `xml:"start,attr"`
+	Hosts    []nmapHost `xml:"host"`
+	Finished struct {
+		Time int64 `xml:"time,attr"`
+	} `xml:"runstats>finished"`
+}
+
+type nmapHost struct {
+	Status struct {
+		State string `xml:"state,attr"`
+	} `xml:"status"`
+	Addresses []struct {
+		Addr     string `xml:"addr,attr"`
+		AddrType string `xml:"addrtype,attr"`
+	} `xml:"address"`
+	Hostnames []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"hostnames>hostname"`
+	Ports []nmapPort `xml:"ports>port"`
+	Times struct {
+		SRTT string `xml:"srtt,attr"`
+	} `xml:"times"`
+}
+
+type nmapPort struct {
+	Protocol string `xml:"protocol,attr"`
+	PortID   int    `xml:"portid,attr"`
+	State    struct {
+		State string `xml:"state,attr"`
+	} `xml:"state"`
+	Service struct {
+		Name      string `xml:"name,attr"`
+		Product   string `xml:"product,attr"`
+		Version   string `xml:"version,attr"`
+		ExtraInfo string `xml:"extrainfo,attr"`
+	} `xml:"service"`
+	Scripts []struct {
+		ID     string `xml:"id,attr"`
+		Output string `xml:"output,attr"`
+	} `xml:"script"`
+}
+
+// parseNmapXML converts an nmap XML report.
+func parseNmapXML(r io.Reader) (*Report, error) {
+	var run nmapRun
+	if err := xml.NewDecoder(r).Decode(&run); err != nil {
+		return nil, fmt.Errorf("parse nmap XML: %w", err)
+	}
+	// masscan -oX writes the same layout.
+	switch run.Scanner {
+	case "":
+		run.Scanner = "nmap"
+	case "nmap", "masscan":
+	default:
+		return nil, fmt.Errorf("parse nmap XML: report was written by %q, not nmap or masscan", run.Scanner)
+	}
+
+	report := &Report{Scanner: strings.TrimSpace(run.Scanner + " " + run.Version), Args: run.Args}
+	if run.Start > 0 {
+		report.Start = time.Unix(run.Start, 0).UTC()
+	}
+	if run.Finished.Time > 0 {
+		report.End = time.Unix(run.Finished.Time, 0).UTC()
+	}
+
+	seen := make(map[string]bool)
+	for _, h := range run.Hosts {
+		if h.Status.State != "" && h.Status.State != "up" {
+			continue
+		}
+		host := h.address()
+		if host == "" {
+			continue
+		}
+		report.addTarget(host, seen)
+		rtt := h.rtt()
+		for _, p := range h.Ports {
+			state, ok := nmapState(p.State.State)
+			if !ok || (p.Protocol != "tcp" && p.Protocol != "udp") || p.PortID < 1 || p.PortID > 65535 {
+				report.Skipped++
+				continue
+			}
+			report.Results = append(report.Results, core.ResultEvent{
+				Host:     host,
+				Port:     uint16(p.PortID),
+				State:    state,
+				Banner:   p.banner(),
+				Duration: rtt,
+				Protocol: p.Protocol,
+			})
+		}
+	}
+	return report, nil
+}
+
+// address returns the host's IP address, preferring IPv4, or its first
+// hostname when it has none.
+func (h nmapHost) address() string {
+	var ipv6 string
+	for _, a := range h.Addresses {
+		switch a.AddrType {
+		case "ipv4":
+			return a.Addr
+		case "ipv6":
+			if ipv6 == "" {
+				ipv6 = a.Addr
+			}
+		}
+	}
+	if ipv6 != "" {
+		return ipv6
+	}
+	if len(h.Hostnames) > 0 {
+		return h.Hostnames[0].Name
+	}
+	return ""
+}
+
+// rtt returns the host's smoothed round trip time, which nmap records in
+// microseconds.
+func (h nmapHost) rtt() time.Duration {
+	us, err := strconv.ParseInt(h.Times.SRTT, 10, 64)
+	if err != nil || us < 0 {
+		return 0
+	}
+	return time.Duration(us) * time.Microsecond
+}
+
+// banner returns the banner script's output, or the version detection
+// fingerprint such as "OpenSSH 8.9p1 (protocol 2.0)".
+func (p nmapPort) banner() string {
+	for _, s := range p.Scripts {
+		if s.ID == "banner" {
+			return s.Output
+		}
+	}
+	var parts []string
+	for _, s := range []string{p.Service.Product, p.Service.Version} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if p.Service.ExtraInfo != "" {
+		parts = append(parts, "("+p.Service.ExtraInfo+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+// nmapState maps an nmap port state onto portscan's states.
+func nmapState(state string) (core.ScanState, bool) {
+	switch state {
+	case "open":
+		return core.StateOpen, true
+	case "closed":
+		return core.StateClosed, true
+	case "filtered", "open|filtered", "closed|filtered":
+		return core.StateFiltered, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+// nmapSample is trimmed from "nmap -sS -sU -sV -oX" output.
+const nmapSample = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE nmaprun>
+<?xml-stylesheet href="file:///usr/bin/../share/nmap/nmap.xsl" type="text/xsl"?>
+<nmaprun scanner="nmap" args="nmap -sS -sU -sV -p T:22,80,443,U:53 -oX nmap.xml 192.168.1.0/30" start="1714564800" startstr="Wed May  1 12:00:00 2024" version="7.94" xmloutputversion="1.05">
+<scaninfo type="syn" protocol="tcp" numservices="3" services="22,80,443"/>
+<scaninfo type="udp" protocol="udp" numservices="1" services="53"/>
+<verbose level="0"/>
+<debugging level="0"/>
+<host starttime="1714564801" endtime="1714564810"><status state="up" reason="arp-response" reason_ttl="0"/>
+<address addr="192.168.1.1" addrtype="ipv4"/>
+<address addr="AA:BB:CC:DD:EE:FF" addrtype="mac" vendor="Example"/>
+<hostnames>
+<hostname name="gateway.lan" type="PTR"/>
+</hostnames>
+<ports><extraports state="closed" count="1">
+<extrareasons reason="resets" count="1" proto="tcp" ports="443"/>
+</extraports>
+<port protocol="tcp" portid="22"><state state="open" reason="syn-ack" reason_ttl="64"/><service name="ssh" product="OpenSSH" version="8.9p1 Ubuntu 3ubuntu0.6" extrainfo="Ubuntu Linux; protocol 2.0" ostype="Linux" method="probed" conf="10"/></port>
+<port protocol="tcp" portid="80"><state state="open" reason="syn-ack" reason_ttl="64"/><service name="http" method="table" conf="3"/><script id="banner" output="HTTP/1.1 400 Bad Request"/></port>
+<port protocol="udp" portid="53"><state state="open|filtered" reason="no-response" reason_ttl="0"/><service name="domain" method="table" conf="3"/></port>
+<port protocol="sctp" portid="38412"><state state="open" reason="init-ack" reason_ttl="64"/></port>
+</ports>
+<times srtt="1250" rttvar="500" to="100000"/>
+</host>
+<host starttime="1714564801" endtime="1714564810"><status state="up" reason="echo-reply" reason_ttl="63"/>
+<address addr="fe80::1" addrtype="ipv6"/>
+<hostnames>
+</hostnames>
+<ports><port protocol="tcp" portid="443"><state state="closed" reason="reset" reason_ttl="63"/><service name="https" method="table" conf="3"/></port>
+<port protocol="tcp" portid="8080"><state state="unfiltered" reason="reset" reason_ttl="63"/></port>
+</ports>
+</host>
+<host><status state="down" reason="no-response" reason_ttl="0"/>
+<address addr="192.168.1.3" addrtype="ipv4"/>
+</host>
+<runstats><finished time="1714564812" timestr="Wed May  1 12:00:12 2024" summary="Nmap done at Wed May  1 12:00:12 2024; 4 IP addresses (2 hosts up) scanned in 12.00 seconds" elapsed="12.00" exit="success"/><hosts up="2" down="2" total="4"/>
+</runstats>
+</nmaprun>
+`
+
+func TestParseNmapXML(t *testing.T) {
+	report, err := Parse(FormatNmapXML, strings.NewReader(nmapSample))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if report.Scanner != "nmap 7.94" || !strings.HasPrefix(report.Args, "nmap -sS") {
+		t.Errorf("scanner %q, args %q", report.Scanner, report.Args)
+	}
+	if !report.Start.Equal(time.Unix(1714564800, 0)) || !report.End.Equal(time.Unix(1714564812, 0)) {
+		t.Errorf("start %v, end %v", report.Start, report.End)
+	}
+	if len(report.Targets) != 2 || report.Targets[0] != "192.168.1.1" || report.Targets[1] != "fe80::1" {
+		t.Errorf("targets = %v, want the two hosts that are up", report.Targets)
+	}
+	if report.Skipped != 2 {
+		t.Errorf("skipped = %d, want the SCTP and unfiltered ports", report.Skipped)
+	}
+
+	want := []core.ResultEvent{
+		{Host: "192.168.1.1", Port: 22, State: core.StateOpen, Protocol: "tcp", Duration: 1250 * time.Microsecond,
+			Banner: "OpenSSH 8.9p1 Ubuntu 3ubuntu0.6 (Ubuntu Linux; protocol 2.0)"},
+		{Host: "192.168.1.1", Port: 80, State: core.StateOpen, Protocol: "tcp", Duration: 1250 * time.Microsecond,
+			Banner: "HTTP/1.1 400 Bad Request"},
+		{Host: "192.168.1.1", Port: 53, State: core.StateFiltered, Protocol: "udp", Duration: 1250 * time.Microsecond},
+		{Host: "fe80::1", Port: 443, State: core.StateClosed, Protocol: "tcp"},
+	}
+	if len(report.Results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(report.Results), len(want), report.Results)
+	}
+	for i, w := range want {
+		if !reflect.DeepEqual(report.Results[i], w) {
+			t.Errorf("result %d = %+v, want %+v", i, report.Results[i], w)
+		}
+	}
+}
+
+func TestParseNmapXMLRejectsOtherScanners(t *testing.T) {
+	if _, err := Parse(FormatNmapXML, strings.NewReader(`<nmaprun scanner="zmap"></nmaprun>`)); err == nil {
+		t.Error("a report from another scanner should not be read as nmap")
+	}
+	if _, err := Parse(FormatNmapXML, strings.NewReader(`<nmaprun scanner="nmap"><host>`)); err == nil {
+		t.Error("truncated XML should fail")
+	}
+}
@@ -0,0 +1,44 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseBitRate parses a rate in bits per second such as "800000", "50M",
+// "100Mbit", "1.5Gbps" or "500kbit/s". Prefixes are case-insensitive powers
+// of 1000, as is usual for link speeds. An empty spec yields 0.
+func ParseBitRate(spec string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(spec))
+	if s == "" {
+		return 0, nil
+	}
+	s = strings.TrimSuffix(s, "/S")
+	for _, unit := range []string{"BPS", "BIT"} {
+		if strings.HasSuffix(s, unit) {
+			s = strings.TrimSuffix(s, unit)
+			break
+		}
+	}
+
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(s, "K"):
+		multiplier = 1e3
+	case strings.HasSuffix(s, "M"):
+		multiplier = 1e6
+	case strings.HasSuffix(s, "G"):
+		multiplier = 1e9
+	}
+	if multiplier > 1 {
+		s = s[:len(s)-1]
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || !(n >= 0) || n*multiplier > math.MaxInt64/2 {
+		return 0, fmt.Errorf("invalid bit rate %q: want a number of bits per second with an optional k, M, or G prefix, e.g. 50Mbit", spec)
+	}
+	return int64(n * multiplier), nil
+}
@@ -0,0 +1,34 @@
+package parser
+
+import "testing"
+
+func TestParseBitRate(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "", want: 0},
+		{input: "800000", want: 800000},
+		{input: "50M", want: 50e6},
+		{input: "100Mbit", want: 100e6},
+		{input: " 1.5Gbps ", want: 1.5e9},
+		{input: "500kbit/s", want: 500e3},
+		{input: "2 m", want: 2e6},
+		{input: "Mbit", wantErr: true},
+		{input: "-5M", wantErr: true},
+		{input: "10T", wantErr: true},
+		{input: "NaN", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseBitRate(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseBitRate(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseBitRate(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
@@ -6,8 +6,10 @@
 //
 //   - Single ports: "80", "443", "8080"
 //   - Port ranges: "1-1024", "8000-9000"
-//   - Comma-separated lists: "22,80,443"
-//   - Mixed formats: "80,443,8000-9000"
+//   - Stepped ranges: "1000-2000/2" (every second port)
+//   - Service names: "ssh", "https", resolved by pkg/services
+//   - Exclusions: "1-1024,!22,!smb"
+//   - Comma-separated lists and mixes of them: "80,443,8000-9000"
 //
 // Example usage:
 //
@@ -17,17 +19,52 @@
 //	}
 //	fmt.Printf("Scanning %d ports\n", len(ports))
 //
-// The parser validates all port numbers (1-65535) and automatically removes
-// duplicates. Invalid specifications return descriptive errors.
+// Grammar:
+//
+// ParsePorts accepts this grammar; space around terms and range bounds is
+// ignored and empty terms are skipped:
+//
+//	spec    = term { "," term } .
+//	term    = [ "!" ] item .
+//	item    = port | range | service .
+//	range   = port "-" port [ "/" step ] .
+//	port    = decimal in 1-65535 .
+//	step    = decimal in 1-65535 .
+//	service = letter { any character but "," } .
+//
+// A service name matches the TCP or UDP name of every port that has it,
+// ignoring case, including names from the user override file. Ports are
+// returned in the order first listed, without duplicates; an excluded port
+// is removed wherever it is listed. A spec whose terms add no port, or
+// whose exclusions remove every port, is an error.
 //
 // Port Range Expansion:
 //
 // Port ranges are expanded inclusively. For example, "80-83" produces
-// [80, 81, 82, 83]. Large ranges are supported and efficiently processed.
+// [80, 81, 82, 83], and "80-89/4" produces [80, 84, 88]. Large ranges are
+// supported and efficiently processed.
 //
 // Validation:
 //
-// All port numbers must be in the valid range 1-65535. Ports outside this
-// range or malformed specifications (e.g., "abc", "80-", "-443") will
-// return descriptive errors indicating the problem.
+// All port numbers must be in the valid range 1-65535. A malformed term
+// (e.g., "abc", "80-", "-443", "80/2") returns a *SyntaxError holding the
+// offending token and its offset in the spec, so callers can point at the
+// column where the problem starts.
+//
+// Per-Port Timeouts:
+//
+// ParsePortTimeouts maps ports to timeout overrides in milliseconds, for
+// example "443=1000,3306=500" or "8000-8100=800", so slow services can be
+// given longer dial and read deadlines than the rest of a scan.
+//
+// Scan Windows:
+//
+// ParseScanWindow parses a daily local-time window such as "22:00-06:00";
+// windows whose end is before their start wrap past midnight.
+//
+// Scan Jobs:
+//
+// ParseJob parses a "NAME:TARGETS_FILE[:key=value...]" job, such as
+// "web:web-hosts.txt:profile=web", for running several target groups in
+// one invocation.
 package parser
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JobSpec is one scan job of a multi-job invocation: a named group of
+// targets read from a file, with its own ports and export.
+type JobSpec struct {
+	Name        string
+	TargetsFile string
+	Profile     string // port profile; empty uses Ports or the scan's ports
+	Ports       string // port list; empty uses the scan's ports
+	Protocol    string // tcp, udp, or both; empty uses the scan's protocol
+	Output      string // json, csv, html, or table; empty uses the scan's format
+	File        string // export file; empty derives it from Name
+}
+
+// ParseJob parses a job in "NAME:TARGETS_FILE[:key=value...]" form, for
+// example "web:web-hosts.txt:profile=web" or
+// "db:db-hosts.txt:ports=5432,3306:file=db.csv". Keys are profile, ports,
+// protocol, output, and file.
+func ParseJob(spec string) (JobSpec, error) {
+	parts := strings.Split(strings.TrimSpace(spec), ":")
+	if len(parts) < 2 {
+		return JobSpec{}, fmt.Errorf("invalid job %q: want NAME:TARGETS_FILE[:key=value...]", spec)
+	}
+	job := JobSpec{Name: strings.TrimSpace(parts[0]), TargetsFile: strings.TrimSpace(parts[1])}
+	if !validJobName(job.Name) {
+		return JobSpec{}, fmt.Errorf("invalid job %q: name must be letters, digits, '-' or '_'", spec)
+	}
+	if job.TargetsFile == "" {
+		return JobSpec{}, fmt.Errorf("invalid job %q: targets file is empty", spec)
+	}
+
+	for _, opt := range parts[2:] {
+		key, value, ok := strings.Cut(opt, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || value == "" {
+			return JobSpec{}, fmt.Errorf("invalid job %q: option %q must be key=value", spec, opt)
+		}
+		switch key {
+		case "profile":
+			job.Profile = value
+		case "ports":
+			if _, err := ParsePorts(value); err != nil {
+				return JobSpec{}, fmt.Errorf("invalid job %q: %w", spec, err)
+			}
+			job.Ports = value
+		case "protocol":
+			if value != "tcp" && value != "udp" && value != "both" {
+				return JobSpec{}, fmt.Errorf("invalid job %q: protocol must be tcp, udp, or both", spec)
+			}
+			job.Protocol = value
+		case "output":
+			if value != "json" && value != "csv" && value != "html" && value != "table" {
+				return JobSpec{}, fmt.Errorf("invalid job %q: output must be json, csv, html, or table", spec)
+			}
+			job.Output = value
+		case "file":
+			job.File = value
+		default:
+			return JobSpec{}, fmt.Errorf("invalid job %q: unknown option %q (use profile, ports, protocol, output, or file)", spec, key)
+		}
+	}
+	if job.Profile != "" && job.Ports != "" {
+		return JobSpec{}, fmt.Errorf("invalid job %q: use profile or ports, not both", spec)
+	}
+	return job, nil
+}
+
+func validJobName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
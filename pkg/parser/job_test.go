@@ -0,0 +1,41 @@
+package parser
+
+import "testing"
+
+func TestParseJob(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    JobSpec
+		wantErr bool
+	}{
+		{name: "name and file", input: "web:web-hosts.txt", want: JobSpec{Name: "web", TargetsFile: "web-hosts.txt"}},
+		{name: "profile", input: "web:targetsA.txt:profile=web", want: JobSpec{Name: "web", TargetsFile: "targetsA.txt", Profile: "web"}},
+		{
+			name:  "all options",
+			input: "db_1:db.txt:ports=5432,3306:protocol=both:output=csv:file=out/db.csv",
+			want:  JobSpec{Name: "db_1", TargetsFile: "db.txt", Ports: "5432,3306", Protocol: "both", Output: "csv", File: "out/db.csv"},
+		},
+		{name: "missing file", input: "web", wantErr: true},
+		{name: "empty file", input: "web:", wantErr: true},
+		{name: "bad name", input: "web app:hosts.txt", wantErr: true},
+		{name: "option without value", input: "web:hosts.txt:profile", wantErr: true},
+		{name: "unknown option", input: "web:hosts.txt:rate=100", wantErr: true},
+		{name: "bad ports", input: "web:hosts.txt:ports=80-", wantErr: true},
+		{name: "bad protocol", input: "web:hosts.txt:protocol=sctp", wantErr: true},
+		{name: "bad output", input: "web:hosts.txt:output=elastic", wantErr: true},
+		{name: "profile and ports", input: "web:hosts.txt:profile=web:ports=80", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJob(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseJob(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseJob(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
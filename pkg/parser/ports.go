@@ -4,93 +4,197 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lucchesi-sec/portscan/pkg/services"
 )
 
+// SyntaxError reports a port specification term that did not parse, and
+// where it starts in the specification.
+type SyntaxError struct {
+	Spec   string // the whole specification
+	Offset int    // byte offset of Token in Spec
+	Token  string // the part that failed to parse
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Column(), e.Msg)
+}
+
+// Column returns the 1-based character column of Token in Spec.
+func (e *SyntaxError) Column() int {
+	return utf8.RuneCountInString(e.Spec[:e.Offset]) + 1
+}
+
+// Position returns the specification, the column of the problem in it,
+// and what the problem is, for error reports that point at the input.
+func (e *SyntaxError) Position() (input string, column int, reason string) {
+	return e.Spec, e.Column(), e.Msg
+}
+
 // ParsePorts parses a port specification string into a list of unique ports.
-// Supports single ports (80), ranges (1-1024), and comma-separated lists.
+// Supports single ports (80), ranges (1-1024), stepped ranges (1000-2000/2),
+// service names (ssh), exclusions (!22) and comma-separated lists of them.
+// Ports keep the order they are first listed in; an excluded port is left
+// out wherever it is listed. Malformed terms return a *SyntaxError.
 func ParsePorts(spec string) ([]uint16, error) {
 	seen := make(map[uint16]struct{})
+	excluded := make(map[uint16]struct{})
 	var result []uint16
 
-	for _, token := range strings.Split(spec, ",") {
-		token = strings.TrimSpace(token)
-		if token == "" {
-			continue
+	for start := 0; start <= len(spec); {
+		end := strings.IndexByte(spec[start:], ',')
+		if end < 0 {
+			end = len(spec)
+		} else {
+			end += start
 		}
 
-		ports, err := parsePortToken(token)
+		ports, exclude, err := parsePortTerm(spec, start, end)
 		if err != nil {
 			return nil, err
 		}
-
-		result = appendUniquePorts(result, ports, seen)
+		if exclude {
+			for _, port := range ports {
+				excluded[port] = struct{}{}
+			}
+		} else {
+			result = appendUniquePorts(result, ports, seen)
+		}
+		start = end + 1
 	}
 
 	if len(result) == 0 {
 		return nil, fmt.Errorf("no valid ports specified")
 	}
+	if len(excluded) > 0 {
+		kept := result[:0]
+		for _, port := range result {
+			if _, ok := excluded[port]; !ok {
+				kept = append(kept, port)
+			}
+		}
+		if len(kept) == 0 {
+			return nil, fmt.Errorf("no ports left after exclusions")
+		}
+		result = kept
+	}
 
 	return result, nil
 }
 
+// parsePortTerm parses the term spec[start:end]. An empty term yields no
+// ports.
+func parsePortTerm(spec string, start, end int) ([]uint16, bool, error) {
+	token, offset := trimAt(spec[start:end], start)
+	if token == "" {
+		return nil, false, nil
+	}
+	exclude := false
+	if token[0] == '!' {
+		exclude = true
+		bang := offset
+		token, offset = trimAt(token[1:], offset+1)
+		if token == "" {
+			return nil, false, syntaxError(spec, bang, "!", "exclusion without a port")
+		}
+	}
+
+	ports, err := parsePortItem(spec, token, offset)
+	return ports, exclude, err
+}
+
+// parsePortToken parses a lone port, range or service name, such as a
+// --port-timeouts key.
 func parsePortToken(token string) ([]uint16, error) {
-	if strings.Contains(token, "-") {
-		start, end, err := parsePortRange(token)
+	if token == "" {
+		return nil, syntaxError(token, 0, token, "invalid port: ")
+	}
+	return parsePortItem(token, token, 0)
+}
+
+func parsePortItem(spec, token string, offset int) ([]uint16, error) {
+	if isLetter(token[0]) {
+		ports := services.Ports(token)
+		if ports == nil {
+			return nil, syntaxError(spec, offset, token, "unknown service name: "+token)
+		}
+		return ports, nil
+	}
+
+	rangePart, stepPart, stepped := strings.Cut(token, "/")
+	if !strings.Contains(rangePart, "-") {
+		if stepped {
+			return nil, syntaxError(spec, offset, token, "a step needs a range, e.g. 1000-2000/2: "+token)
+		}
+		port, err := parseSinglePort(spec, token, offset)
 		if err != nil {
 			return nil, err
 		}
-		return buildPortRange(start, end), nil
+		return []uint16{port}, nil
 	}
 
-	port, err := parseSinglePort(token)
+	startPort, endPort, err := parsePortRange(spec, rangePart, offset)
 	if err != nil {
 		return nil, err
 	}
-	return []uint16{port}, nil
+	step := 1
+	if stepped {
+		stepOffset := offset + len(rangePart) + 1
+		value, at := trimAt(stepPart, stepOffset)
+		step, err = strconv.Atoi(value)
+		if err != nil || step < 1 || step > 65535 {
+			return nil, syntaxError(spec, at, value, "invalid step in range: "+token)
+		}
+	}
+	return buildPortRange(startPort, endPort, step), nil
 }
 
-func parseSinglePort(value string) (uint16, error) {
-	num, err := strconv.Atoi(strings.TrimSpace(value))
+func parseSinglePort(spec, value string, offset int) (uint16, error) {
+	num, err := strconv.Atoi(value)
 	if err != nil || num < 1 || num > 65535 {
-		return 0, fmt.Errorf("invalid port: %s", value)
+		return 0, syntaxError(spec, offset, value, "invalid port: "+value)
 	}
 	return uint16(num), nil
 }
 
-func parsePortRange(token string) (int, int, error) {
+func parsePortRange(spec, token string, offset int) (int, int, error) {
 	parts := strings.Split(token, "-")
 	if len(parts) != 2 {
-		return 0, 0, fmt.Errorf("invalid port range: %s", token)
+		return 0, 0, syntaxError(spec, offset, token, "invalid port range: "+token)
 	}
 
-	start, err := parseRangeBoundary(parts[0], "start")
+	start, err := parseRangeBoundary(spec, parts[0], offset, "start")
 	if err != nil {
 		return 0, 0, err
 	}
 
-	end, err := parseRangeBoundary(parts[1], "end")
+	end, err := parseRangeBoundary(spec, parts[1], offset+len(parts[0])+1, "end")
 	if err != nil {
 		return 0, 0, err
 	}
 
 	if start > end {
-		return 0, 0, fmt.Errorf("invalid port range: start > end in %s", token)
+		return 0, 0, syntaxError(spec, offset, token, "invalid port range: start > end in "+token)
 	}
 
 	return start, end, nil
 }
 
-func parseRangeBoundary(value string, position string) (int, error) {
-	num, err := strconv.Atoi(strings.TrimSpace(value))
+func parseRangeBoundary(spec, value string, offset int, position string) (int, error) {
+	value, offset = trimAt(value, offset)
+	num, err := strconv.Atoi(value)
 	if err != nil || num < 1 || num > 65535 {
-		return 0, fmt.Errorf("invalid %s port in range: %s", position, value)
+		return 0, syntaxError(spec, offset, value, fmt.Sprintf("invalid %s port in range: %s", position, value))
 	}
 	return num, nil
 }
 
-func buildPortRange(start, end int) []uint16 {
-	ports := make([]uint16, 0, end-start+1)
-	for p := start; p <= end && p <= 65535; p++ {
+func buildPortRange(start, end, step int) []uint16 {
+	ports := make([]uint16, 0, (end-start)/step+1)
+	for p := start; p <= end && p <= 65535; p += step {
 		ports = append(ports, uint16(p))
 	}
 	return ports
@@ -106,3 +210,19 @@ func appendUniquePorts(dest []uint16, ports []uint16, seen map[uint16]struct{})
 	}
 	return dest
 }
+
+// trimAt trims surrounding space from s, which starts at offset in the
+// specification, and returns the trimmed string's offset.
+func trimAt(s string, offset int) (string, int) {
+	trimmed := strings.TrimLeftFunc(s, unicode.IsSpace)
+	offset += len(s) - len(trimmed)
+	return strings.TrimRightFunc(trimmed, unicode.IsSpace), offset
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func syntaxError(spec string, offset int, token, msg string) *SyntaxError {
+	return &SyntaxError{Spec: spec, Offset: offset, Token: token, Msg: msg}
+}
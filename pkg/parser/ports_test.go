@@ -1,7 +1,10 @@
 package parser
 
 import (
+	"errors"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -102,6 +105,71 @@ func TestParsePorts(t *testing.T) {
 			input: "65533-65535",
 			want:  []uint16{65533, 65534, 65535},
 		},
+		{
+			name:  "exclusion",
+			input: "20-25,!22",
+			want:  []uint16{20, 21, 23, 24, 25},
+		},
+		{
+			name:  "exclusion before the port",
+			input: "!22, 20-23",
+			want:  []uint16{20, 21, 23},
+		},
+		{
+			name:  "excluded range",
+			input: "1-10,!2-9",
+			want:  []uint16{1, 10},
+		},
+		{
+			name:  "stepped range",
+			input: "1000-1010/5",
+			want:  []uint16{1000, 1005, 1010},
+		},
+		{
+			name:  "step past the end",
+			input: "1-10/4",
+			want:  []uint16{1, 5, 9},
+		},
+		{
+			name:  "service names",
+			input: "ssh,HTTPS,80",
+			want:  []uint16{22, 443, 80},
+		},
+		{
+			name:  "excluded service",
+			input: "21-23,!ssh",
+			want:  []uint16{21, 23},
+		},
+		{
+			name:    "only exclusions",
+			input:   "!22",
+			wantErr: true,
+		},
+		{
+			name:    "everything excluded",
+			input:   "22,!ssh",
+			wantErr: true,
+		},
+		{
+			name:    "bare exclusion",
+			input:   "80,!",
+			wantErr: true,
+		},
+		{
+			name:    "step without range",
+			input:   "80/2",
+			wantErr: true,
+		},
+		{
+			name:    "zero step",
+			input:   "1-10/0",
+			wantErr: true,
+		},
+		{
+			name:    "unknown service",
+			input:   "no-such-service",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -128,3 +196,80 @@ func TestParsePortsPerformance(t *testing.T) {
 		t.Errorf("Expected 1000 ports, got %d", len(ports))
 	}
 }
+
+func TestParsePortsErrorPosition(t *testing.T) {
+	tests := []struct {
+		input  string
+		column int
+		token  string
+	}{
+		{input: "abc", column: 1, token: "abc"},
+		{input: "22,80,htp", column: 7, token: "htp"},
+		{input: "22, !  0", column: 8, token: "0"},
+		{input: "1-1024,2000-x", column: 13, token: "x"},
+		{input: "1-1024,2000-", column: 13, token: ""},
+		{input: "80, 82-80", column: 5, token: "82-80"},
+		{input: "1-9/zz", column: 5, token: "zz"},
+		{input: "1,!", column: 3, token: "!"},
+		{input: "ñ,0", column: 1, token: "ñ"},
+		{input: "ñ1,0", column: 1, token: "ñ1"},
+		{input: "é,1-2-3", column: 1, token: "é"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParsePorts(tt.input)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("ParsePorts(%q) error = %v, want a *SyntaxError", tt.input, err)
+			}
+			if syntaxErr.Column() != tt.column || syntaxErr.Token != tt.token {
+				t.Errorf("ParsePorts(%q) error at column %d token %q, want column %d token %q",
+					tt.input, syntaxErr.Column(), syntaxErr.Token, tt.column, tt.token)
+			}
+			if !strings.HasPrefix(err.Error(), "column "+strconv.Itoa(tt.column)+": ") {
+				t.Errorf("Error() = %q should lead with the column", err.Error())
+			}
+		})
+	}
+}
+
+func FuzzPortParser(f *testing.F) {
+	for _, seed := range []string{
+		"80", "1-1024", "22,80-82,443", "1-65535,!22", "1000-2000/2",
+		"ssh,http,https", "!ssh, 20-30/3", "80-", "-80", "1-2-3", "1/0", "!", ",,",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, spec string) {
+		ports, err := ParsePorts(spec)
+		if err != nil {
+			var syntaxErr *SyntaxError
+			if errors.As(err, &syntaxErr) {
+				if syntaxErr.Offset < 0 || syntaxErr.Offset > len(spec) || syntaxErr.Spec != spec {
+					t.Fatalf("ParsePorts(%q) error has offset %d outside the spec", spec, syntaxErr.Offset)
+				}
+				if !strings.HasPrefix(spec[syntaxErr.Offset:], syntaxErr.Token) {
+					t.Fatalf("ParsePorts(%q) error token %q is not at offset %d", spec, syntaxErr.Token, syntaxErr.Offset)
+				}
+			}
+			return
+		}
+		if len(ports) == 0 {
+			t.Fatalf("ParsePorts(%q) returned no ports and no error", spec)
+		}
+		seen := make(map[uint16]bool, len(ports))
+		list := make([]string, len(ports))
+		for i, port := range ports {
+			if port == 0 || seen[port] {
+				t.Fatalf("ParsePorts(%q) returned port %d twice or out of range", spec, port)
+			}
+			seen[port] = true
+			list[i] = strconv.Itoa(int(port))
+		}
+		again, err := ParsePorts(strings.Join(list, ","))
+		if err != nil || !reflect.DeepEqual(again, ports) {
+			t.Fatalf("ParsePorts(%q) = %v does not round-trip: %v, %v", spec, ports, again, err)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseByteSize parses a size such as "512", "64K", "100MB", or "1GiB".
+// Suffixes are case-insensitive powers of 1024. An empty spec yields 0.
+func ParseByteSize(spec string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(spec))
+	if s == "" {
+		return 0, nil
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "B"), "I")
+
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(s, "K"):
+		multiplier = 1 << 10
+	case strings.HasSuffix(s, "M"):
+		multiplier = 1 << 20
+	case strings.HasSuffix(s, "G"):
+		multiplier = 1 << 30
+	}
+	if multiplier > 1 {
+		s = s[:len(s)-1]
+	}
+
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q: want a number with an optional K, M, or G suffix", spec)
+	}
+	return n * multiplier, nil
+}
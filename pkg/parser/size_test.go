@@ -0,0 +1,32 @@
+package parser
+
+import "testing"
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "", want: 0},
+		{input: "512", want: 512},
+		{input: "64K", want: 64 << 10},
+		{input: "100mb", want: 100 << 20},
+		{input: " 1GiB ", want: 1 << 30},
+		{input: "2 M", want: 2 << 20},
+		{input: "MB", wantErr: true},
+		{input: "-5K", wantErr: true},
+		{input: "10TB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseByteSize(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseByteSize(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseByteSize(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxPortTimeoutMs bounds per-port timeout overrides, matching the global
+// timeout limit.
+const MaxPortTimeoutMs = 60000
+
+// ParsePortTimeouts parses per-port timeout overrides in milliseconds, such
+// as "443=1000,3306=500". Keys accept the same single ports, ranges and
+// service names as ParsePorts, so "8000-8100=800" applies to the whole
+// range. Later entries
+// override earlier ones. An empty spec yields a nil map.
+func ParsePortTimeouts(spec string) (map[uint16]time.Duration, error) {
+	var timeouts map[uint16]time.Duration
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid port timeout %q: want PORT=MS", entry)
+		}
+
+		ports, err := parsePortToken(strings.TrimSpace(key))
+		if err != nil {
+			return nil, err
+		}
+
+		ms, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || ms < 1 || ms > MaxPortTimeoutMs {
+			return nil, fmt.Errorf("invalid timeout in %q: must be 1-%d ms", entry, MaxPortTimeoutMs)
+		}
+
+		if timeouts == nil {
+			timeouts = make(map[uint16]time.Duration)
+		}
+		for _, port := range ports {
+			timeouts[port] = time.Duration(ms) * time.Millisecond
+		}
+	}
+
+	return timeouts, nil
+}
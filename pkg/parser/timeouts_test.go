@@ -0,0 +1,54 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParsePortTimeouts(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[uint16]time.Duration
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  nil,
+		},
+		{
+			name:  "single ports",
+			input: "443=1000,3306=500",
+			want:  map[uint16]time.Duration{443: time.Second, 3306: 500 * time.Millisecond},
+		},
+		{
+			name:  "range with spaces",
+			input: " 8000 - 8002 = 800 ",
+			want:  map[uint16]time.Duration{8000: 800 * time.Millisecond, 8001: 800 * time.Millisecond, 8002: 800 * time.Millisecond},
+		},
+		{
+			name:  "later entry wins",
+			input: "80-81=100,81=300",
+			want:  map[uint16]time.Duration{80: 100 * time.Millisecond, 81: 300 * time.Millisecond},
+		},
+		{name: "missing value", input: "443", wantErr: true},
+		{name: "bad port", input: "70000=100", wantErr: true},
+		{name: "bad timeout", input: "443=fast", wantErr: true},
+		{name: "zero timeout", input: "443=0", wantErr: true},
+		{name: "timeout too large", input: "443=60001", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePortTimeouts(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePortTimeouts(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePortTimeouts(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScanWindow is a daily time-of-day range in local time, such as
+// 22:00-06:00. Start and End are offsets from midnight; a window whose end
+// is not after its start wraps past midnight.
+type ScanWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// ParseScanWindow parses a window in "HH:MM-HH:MM" form. The start and end
+// must differ.
+func ParseScanWindow(spec string) (ScanWindow, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return ScanWindow{}, fmt.Errorf("invalid scan window %q: want HH:MM-HH:MM", spec)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return ScanWindow{}, fmt.Errorf("invalid scan window %q: %w", spec, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return ScanWindow{}, fmt.Errorf("invalid scan window %q: %w", spec, err)
+	}
+	if start == end {
+		return ScanWindow{}, fmt.Errorf("invalid scan window %q: start and end must differ", spec)
+	}
+	return ScanWindow{Start: start, End: end}, nil
+}
+
+// parseClock parses "HH:MM" into an offset from midnight.
+func parseClock(s string) (time.Duration, error) {
+	hh, mm, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return 0, fmt.Errorf("time %q must be HH:MM", s)
+	}
+	hours, err := strconv.Atoi(hh)
+	if err != nil || hours < 0 || hours > 23 {
+		return 0, fmt.Errorf("hour in %q must be 00-23", s)
+	}
+	minutes, err := strconv.Atoi(mm)
+	if err != nil || len(mm) != 2 || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("minute in %q must be 00-59", s)
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
+
+// Length returns how long the window stays open each day.
+func (w ScanWindow) Length() time.Duration {
+	if w.End > w.Start {
+		return w.End - w.Start
+	}
+	return 24*time.Hour - w.Start + w.End
+}
+
+// Contains reports whether t falls inside the window.
+func (w ScanWindow) Contains(t time.Time) bool {
+	offset := sinceMidnight(t)
+	if w.End > w.Start {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// NextChange returns the next time after t at which the window opens or
+// closes: its end while t is inside, otherwise its next start.
+func (w ScanWindow) NextChange(t time.Time) time.Time {
+	boundary := w.Start
+	if w.Contains(t) {
+		boundary = w.End
+	}
+	midnight := t.Add(-sinceMidnight(t))
+	next := midnight.Add(boundary)
+	if !next.After(t) {
+		next = midnight.AddDate(0, 0, 1).Add(boundary)
+	}
+	return next
+}
+
+// Remaining returns how much of the current window is left at t, or the
+// full window length when t is outside it.
+func (w ScanWindow) Remaining(t time.Time) time.Duration {
+	if !w.Contains(t) {
+		return w.Length()
+	}
+	return w.NextChange(t).Sub(t)
+}
+
+// String formats the window as "HH:MM-HH:MM".
+func (w ScanWindow) String() string {
+	return formatClock(w.Start) + "-" + formatClock(w.End)
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	h, m, s := t.Clock()
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(s)*time.Second + time.Duration(t.Nanosecond())
+}
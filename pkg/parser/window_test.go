@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScanWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    ScanWindow
+		wantErr bool
+	}{
+		{name: "same day", input: "09:30-17:00", want: ScanWindow{Start: 9*time.Hour + 30*time.Minute, End: 17 * time.Hour}},
+		{name: "overnight with spaces", input: " 22:00 - 06:00 ", want: ScanWindow{Start: 22 * time.Hour, End: 6 * time.Hour}},
+		{name: "missing dash", input: "22:00", wantErr: true},
+		{name: "bad hour", input: "24:00-06:00", wantErr: true},
+		{name: "bad minute", input: "22:60-06:00", wantErr: true},
+		{name: "single digit minute", input: "22:0-06:00", wantErr: true},
+		{name: "empty window", input: "06:00-06:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseScanWindow(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseScanWindow(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseScanWindow(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanWindowOvernight(t *testing.T) {
+	w := ScanWindow{Start: 22 * time.Hour, End: 6 * time.Hour}
+	day := func(h, m int) time.Time { return time.Date(2024, 3, 10, h, m, 0, 0, time.UTC) }
+
+	if w.String() != "22:00-06:00" {
+		t.Errorf("String() = %q", w.String())
+	}
+	if w.Length() != 8*time.Hour {
+		t.Errorf("Length() = %v, want 8h", w.Length())
+	}
+
+	tests := []struct {
+		at         time.Time
+		inside     bool
+		nextChange time.Time
+		remaining  time.Duration
+	}{
+		{at: day(12, 0), inside: false, nextChange: day(22, 0), remaining: 8 * time.Hour},
+		{at: day(23, 0), inside: true, nextChange: day(6, 0).AddDate(0, 0, 1), remaining: 7 * time.Hour},
+		{at: day(5, 30), inside: true, nextChange: day(6, 0), remaining: 30 * time.Minute},
+		{at: day(6, 0), inside: false, nextChange: day(22, 0), remaining: 8 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := w.Contains(tt.at); got != tt.inside {
+			t.Errorf("Contains(%s) = %v, want %v", tt.at.Format("15:04"), got, tt.inside)
+		}
+		if got := w.NextChange(tt.at); !got.Equal(tt.nextChange) {
+			t.Errorf("NextChange(%s) = %v, want %v", tt.at.Format("15:04"), got, tt.nextChange)
+		}
+		if got := w.Remaining(tt.at); got != tt.remaining {
+			t.Errorf("Remaining(%s) = %v, want %v", tt.at.Format("15:04"), got, tt.remaining)
+		}
+	}
+}
+
+func TestScanWindowSameDay(t *testing.T) {
+	w := ScanWindow{Start: 9 * time.Hour, End: 17 * time.Hour}
+	evening := time.Date(2024, 3, 10, 18, 0, 0, 0, time.UTC)
+
+	if w.Contains(evening) {
+		t.Error("18:00 should be outside 09:00-17:00")
+	}
+	want := time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC)
+	if got := w.NextChange(evening); !got.Equal(want) {
+		t.Errorf("NextChange = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,27 @@
+// Package probes loads UDP probe packs: payloads to send to UDP ports in
+// place of the scanner's built-in probes, with optional patterns that name
+// the service a response came from.
+//
+// A pack is a YAML file listing probes:
+//
+//	probes:
+//	  - name: SSDP/UPnP
+//	    ports: [1900]
+//	    text: "M-SEARCH * HTTP/1.1\r\nHOST: 239.255.255.250:1900\r\n..."
+//	    match: '(?i)^HTTP/1\.[01] 200'
+//	  - name: memcached
+//	    ports: [11211]
+//	    hex: 0000 0000 0001 0000 73746174730d0a
+//	    match: 'STAT pid'
+//
+// Each probe sets exactly one payload: hex (whitespace is ignored), base64
+// or text. pad extends the payload with zero bytes to a length, for
+// protocols such as QUIC that ignore short datagrams. match is a regular
+// expression tried against the raw response; a byte above 0x7f is not
+// valid UTF-8 on its own, so only '.' in (?s) mode matches it.
+//
+// Load takes a comma-separated list of pack files, in which Extended
+// names the bundled pack of NetBIOS, mDNS, SSDP and IKE probes:
+//
+//	packs, err := probes.Load("extended,/etc/portscan/probes.yaml")
+package probes
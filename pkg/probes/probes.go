@@ -0,0 +1,201 @@
+package probes
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Extended names the bundled probe pack in a Load spec.
+const Extended = "extended"
+
+// maxPayload caps a probe at the largest UDP payload over IPv4.
+const maxPayload = 65507
+
+//go:embed extended.yaml
+var extendedPack []byte
+
+// Probe is a UDP payload sent to Ports. When Match is set, a response it
+// matches identifies the service as Name.
+type Probe struct {
+	Name    string
+	Ports   []uint16
+	Payload []byte
+	Match   *regexp.Regexp
+}
+
+// packFile is the on-disk YAML representation of a probe pack.
+type packFile struct {
+	Probes []probeEntry `yaml:"probes"`
+}
+
+type probeEntry struct {
+	Name   string   `yaml:"name"`
+	Ports  []uint16 `yaml:"ports"`
+	Hex    string   `yaml:"hex"`
+	Base64 string   `yaml:"base64"`
+	Text   string   `yaml:"text"`
+	Pad    int      `yaml:"pad"`
+	Match  string   `yaml:"match"`
+}
+
+// LoadError describes a probe pack that could not be loaded.
+type LoadError struct {
+	Path string
+	Err  error
+}
+
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("probe pack %s: %v", e.Path, e.Err)
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// Load reads the comma-separated probe packs in spec, each a file path or
+// Extended for the bundled pack. Probes are returned in order, so for a
+// port listed twice the later pack's probe wins.
+func Load(spec string) ([]Probe, error) {
+	var all []Probe
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		var (
+			probes []Probe
+			err    error
+		)
+		if name == Extended {
+			if probes, err = Parse(extendedPack); err != nil {
+				err = &LoadError{Path: name, Err: err}
+			}
+		} else {
+			probes, err = LoadFile(name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, probes...)
+	}
+	return all, nil
+}
+
+// LoadFile reads a YAML probe pack.
+func LoadFile(path string) ([]Probe, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &LoadError{Path: path, Err: err}
+	}
+	probes, err := Parse(data)
+	if err != nil {
+		return nil, &LoadError{Path: path, Err: err}
+	}
+	return probes, nil
+}
+
+// Parse decodes a YAML probe pack. Every probe needs a name, at least one
+// port and exactly one payload: hex (whitespace is ignored), base64 or
+// text. Pad extends the payload with zero bytes to that length, and match
+// is a regular expression tried against the response bytes.
+func Parse(data []byte) ([]Probe, error) {
+	var file packFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, err
+	}
+	if len(file.Probes) == 0 {
+		return nil, errors.New("no probes defined")
+	}
+
+	probes := make([]Probe, 0, len(file.Probes))
+	for i, entry := range file.Probes {
+		probe, err := entry.toProbe()
+		if err != nil {
+			label := entry.Name
+			if label == "" {
+				label = fmt.Sprintf("#%d", i+1)
+			}
+			return nil, fmt.Errorf("probe %s: %w", label, err)
+		}
+		probes = append(probes, probe)
+	}
+	return probes, nil
+}
+
+// toProbe validates the entry and decodes its payload.
+func (e probeEntry) toProbe() (Probe, error) {
+	if e.Name == "" {
+		return Probe{}, errors.New("missing name")
+	}
+	if len(e.Ports) == 0 {
+		return Probe{}, errors.New("missing ports")
+	}
+	for _, port := range e.Ports {
+		if port == 0 {
+			return Probe{}, errors.New("port 0 is not valid")
+		}
+	}
+
+	payload, err := e.payload()
+	if err != nil {
+		return Probe{}, err
+	}
+	if e.Pad < 0 || e.Pad > maxPayload {
+		return Probe{}, fmt.Errorf("pad must be between 0 and %d", maxPayload)
+	}
+	if len(payload) < e.Pad {
+		payload = append(payload, make([]byte, e.Pad-len(payload))...)
+	}
+	if len(payload) > maxPayload {
+		return Probe{}, fmt.Errorf("payload is %d bytes, more than %d", len(payload), maxPayload)
+	}
+
+	probe := Probe{Name: e.Name, Ports: e.Ports, Payload: payload}
+	if e.Match != "" {
+		if probe.Match, err = regexp.Compile(e.Match); err != nil {
+			return Probe{}, fmt.Errorf("match: %w", err)
+		}
+	}
+	return probe, nil
+}
+
+// payload decodes whichever of hex, base64 and text the entry sets.
+func (e probeEntry) payload() ([]byte, error) {
+	set := 0
+	for _, s := range []string{e.Hex, e.Base64, e.Text} {
+		if s != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, errors.New("set exactly one of hex, base64 or text")
+	}
+
+	switch {
+	case e.Hex != "":
+		data, err := hex.DecodeString(strings.Join(strings.Fields(e.Hex), ""))
+		if err != nil {
+			return nil, fmt.Errorf("hex: %w", err)
+		}
+		return data, nil
+	case e.Base64 != "":
+		data, err := base64.StdEncoding.DecodeString(e.Base64)
+		if err != nil {
+			return nil, fmt.Errorf("base64: %w", err)
+		}
+		return data, nil
+	default:
+		return []byte(e.Text), nil
+	}
+}
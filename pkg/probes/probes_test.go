@@ -0,0 +1,149 @@
+package probes
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	probes, err := Parse([]byte(`probes:
+  - name: memcached
+    ports: [11211]
+    hex: 0000 0000 0001 0000 73746174730d0a
+    match: 'STAT pid'
+  - name: echo
+    ports: [7, 7007]
+    base64: aGVsbG8=
+    pad: 8
+  - name: text
+    ports: [9999]
+    text: "ping\r\n"
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(probes) != 3 {
+		t.Fatalf("got %d probes, want 3", len(probes))
+	}
+
+	memcached := probes[0]
+	if string(memcached.Payload[8:]) != "stats\r\n" || len(memcached.Payload) != 15 {
+		t.Errorf("hex payload = %x", memcached.Payload)
+	}
+	if memcached.Match == nil || !memcached.Match.MatchString("STAT pid 42\r\n") {
+		t.Error("memcached match should accept a stats reply")
+	}
+
+	echo := probes[1]
+	if len(echo.Ports) != 2 || echo.Ports[1] != 7007 {
+		t.Errorf("echo ports = %v", echo.Ports)
+	}
+	if string(echo.Payload) != "hello\x00\x00\x00" {
+		t.Errorf("padded base64 payload = %q", echo.Payload)
+	}
+	if echo.Match != nil {
+		t.Error("probe without match should have a nil Match")
+	}
+
+	if string(probes[2].Payload) != "ping\r\n" {
+		t.Errorf("text payload = %q", probes[2].Payload)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		pack string
+		want string
+	}{
+		{"empty", "probes: []\n", "no probes"},
+		{"unknown key", "probes:\n  - name: x\n    ports: [1]\n    text: a\n    payload: b\n", "payload"},
+		{"no name", "probes:\n  - ports: [1]\n    text: a\n", "probe #1: missing name"},
+		{"no ports", "probes:\n  - name: x\n    text: a\n", "missing ports"},
+		{"port zero", "probes:\n  - name: x\n    ports: [0]\n    text: a\n", "port 0"},
+		{"no payload", "probes:\n  - name: x\n    ports: [1]\n", "exactly one"},
+		{"two payloads", "probes:\n  - name: x\n    ports: [1]\n    text: a\n    hex: '00'\n", "exactly one"},
+		{"bad hex", "probes:\n  - name: x\n    ports: [1]\n    hex: zz\n", "hex"},
+		{"bad base64", "probes:\n  - name: x\n    ports: [1]\n    base64: '!!'\n", "base64"},
+		{"bad match", "probes:\n  - name: x\n    ports: [1]\n    text: a\n    match: '('\n", "match"},
+		{"huge pad", "probes:\n  - name: x\n    ports: [1]\n    text: a\n    pad: 70000\n", "pad"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.pack))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v; want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "probes.yaml")
+	if err := os.WriteFile(path, []byte("probes:\n  - name: QUIC-alt\n    ports: [443]\n    text: x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	probes, err := Load(Extended + ", " + path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if last := probes[len(probes)-1]; last.Name != "QUIC-alt" {
+		t.Errorf("the file's probes should follow the bundled pack, last = %q", last.Name)
+	}
+
+	_, err = Load(filepath.Join(dir, "missing.yaml"))
+	if _, ok := err.(*LoadError); !ok || !strings.Contains(err.Error(), "missing.yaml") {
+		t.Errorf("Load(missing) error = %v; want a LoadError naming the file", err)
+	}
+}
+
+func TestExtendedPack(t *testing.T) {
+	probes, err := Load(Extended)
+	if err != nil {
+		t.Fatalf("bundled pack: %v", err)
+	}
+	byPort := make(map[uint16]Probe)
+	for _, p := range probes {
+		if p.Match == nil {
+			t.Errorf("%s has no match pattern", p.Name)
+		}
+		for _, port := range p.Ports {
+			byPort[port] = p
+		}
+	}
+	for _, port := range []uint16{137, 500, 1900, 4500, 5353} {
+		if _, ok := byPort[port]; !ok {
+			t.Errorf("bundled pack has no probe for port %d", port)
+		}
+	}
+
+	// NBSTAT queries the 32-byte encoded name "*".
+	if nb := byPort[137].Payload; len(nb) != 12+34+4 || nb[12] != 32 {
+		t.Errorf("NetBIOS probe is %d bytes, want 50 with a 32-byte name", len(nb))
+	}
+	// The IKE header's length field covers the whole message.
+	if ike := byPort[500].Payload; int(binary.BigEndian.Uint32(ike[24:28])) != len(ike) {
+		t.Errorf("IKE length field = %d; payload is %d bytes", binary.BigEndian.Uint32(ike[24:28]), len(ike))
+	}
+	if natt := byPort[4500].Payload; len(natt) != len(byPort[500].Payload)+4 {
+		t.Error("NAT-T probe should be the IKE probe behind a non-ESP marker")
+	}
+
+	replies := map[uint16]string{
+		137:  "\x70\x73\x84\x00\x00\x00\x00\x01\x00\x00\x00\x00\x20CKAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA\x00\x00\x21",
+		500:  "portscan\x9f\x12\x00\x00\x00\x00\x00\x00\x0b\x10\x05\x00",
+		4500: "\x00\x00\x00\x00portscan\x9f\x12",
+		1900: "HTTP/1.1 200 OK\r\nST: upnp:rootdevice\r\n",
+		5353: "\x70\x73\x84\x00\x00\x01\x00\x01\x00\x00\x00\x00\x09_services\x07_dns-sd\x04_udp\x05local\x00",
+	}
+	for port, reply := range replies {
+		if !byPort[port].Match.MatchString(reply) {
+			t.Errorf("%s match %q rejected a typical reply", byPort[port].Name, byPort[port].Match)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Package replay records a scan's event stream to a file and plays it back.
+//
+// A recording holds every event the scan's output saw (results, progress,
+// errors and timeline entries) with its offset from the start of the scan,
+// so playback reproduces the scan's pacing. Replaying a recording into the
+// TUI or an exporter needs no network access, which makes UI development,
+// demos and bug reproductions repeatable.
+//
+// Recording:
+//
+//	rec, err := replay.NewRecorder(file, replay.Header{Targets: hosts, TotalPorts: n})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for event := range events {
+//	    rec.Observe(event)
+//	}
+//	err = rec.Err()
+//
+// Playback at ten times the recorded speed:
+//
+//	r, err := replay.NewReader(file)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	player := replay.NewPlayer(r, 10)
+//	for event := range player.Play(ctx) {
+//	    ...
+//	}
+//	err = player.Err()
+//
+// File format:
+//
+// A recording starts with the line "portscan-events 1" and continues as an
+// encoding/gob stream: the Header, then one record per event. Errors keep
+// their message and, for probe failures, the host, port and protocol.
+package replay
@@ -0,0 +1,273 @@
+package replay
+
+import (
+	"bufio"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+// magic is the first line of every recording.
+const magic = "portscan-events 1\n"
+
+// Header describes the recorded scan.
+type Header struct {
+	Started    time.Time
+	Targets    []string
+	TotalPorts int
+	Protocol   string
+}
+
+// record is one recorded event and its offset from the recording start.
+type record struct {
+	Offset   time.Duration
+	Kind     core.EventKind
+	Result   *core.ResultEvent
+	Progress *core.ProgressEvent
+	Timeline *core.TimelineEntry
+	Error    *recordedError
+}
+
+// recordedError keeps what exporters and the TUI read from an error event.
+type recordedError struct {
+	Message  string
+	Probe    bool // a *core.ScanError; Host, Port, Protocol and Time are set
+	Host     string
+	Port     uint16
+	Protocol string
+	Time     time.Time
+}
+
+// Recorder writes events to a recording. It is safe for concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	enc   *gob.Encoder
+	start time.Time
+	err   error
+}
+
+// NewRecorder writes the recording header to w and returns a Recorder
+// whose offsets count from now. A zero header.Started is set to now.
+func NewRecorder(w io.Writer, header Header) (*Recorder, error) {
+	r := &Recorder{enc: gob.NewEncoder(w), start: time.Now()}
+	if header.Started.IsZero() {
+		header.Started = r.start
+	}
+	if _, err := io.WriteString(w, magic); err != nil {
+		return nil, err
+	}
+	if err := r.enc.Encode(header); err != nil {
+		return nil, fmt.Errorf("write recording header: %w", err)
+	}
+	return r, nil
+}
+
+// Observe records event. After a write fails further events are dropped
+// and Err reports the failure.
+func (r *Recorder) Observe(event core.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	rec := record{
+		Offset:   time.Since(r.start),
+		Kind:     event.Kind,
+		Result:   event.Result,
+		Progress: event.Progress,
+		Timeline: event.Timeline,
+	}
+	if event.Error != nil {
+		rec.Error = encodeError(event.Error)
+	}
+	if err := r.enc.Encode(rec); err != nil {
+		r.err = fmt.Errorf("write recording: %w", err)
+	}
+}
+
+// Err returns the first write error.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func encodeError(err error) *recordedError {
+	var scanErr *core.ScanError
+	if errors.As(err, &scanErr) {
+		return &recordedError{
+			Message:  scanErr.Err.Error(),
+			Probe:    true,
+			Host:     scanErr.Host,
+			Port:     scanErr.Port,
+			Protocol: scanErr.Protocol,
+			Time:     scanErr.Time,
+		}
+	}
+	return &recordedError{Message: err.Error()}
+}
+
+func (e *recordedError) decode() error {
+	if !e.Probe {
+		return errors.New(e.Message)
+	}
+	return &core.ScanError{
+		Host:     e.Host,
+		Port:     e.Port,
+		Protocol: e.Protocol,
+		Time:     e.Time,
+		Err:      errors.New(e.Message),
+	}
+}
+
+// Reader reads events back from a recording.
+type Reader struct {
+	Header Header
+	dec    *gob.Decoder
+}
+
+// NewReader checks that r holds a recording and reads its header.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	line, err := br.ReadString('\n')
+	if line != magic {
+		if err == nil || errors.Is(err, io.EOF) {
+			err = errors.New("not a portscan event recording")
+		}
+		return nil, err
+	}
+	reader := &Reader{dec: gob.NewDecoder(br)}
+	if err := reader.dec.Decode(&reader.Header); err != nil {
+		return nil, fmt.Errorf("read recording header: %w", err)
+	}
+	return reader, nil
+}
+
+// Next returns the next event and its offset from the recording start. It
+// returns io.EOF after the last event.
+func (r *Reader) Next() (core.Event, time.Duration, error) {
+	var rec record
+	if err := r.dec.Decode(&rec); err != nil {
+		if errors.Is(err, io.EOF) {
+			return core.Event{}, 0, io.EOF
+		}
+		return core.Event{}, 0, fmt.Errorf("read recording: %w", err)
+	}
+	event := core.Event{
+		Kind:     rec.Kind,
+		Result:   rec.Result,
+		Progress: rec.Progress,
+		Timeline: rec.Timeline,
+	}
+	if rec.Error != nil {
+		event.Error = rec.Error.decode()
+	}
+	return event, rec.Offset, nil
+}
+
+// ParseSpeed reads a playback speed such as "10x", "0.5" or "max". Max, and
+// zero, play events back without waiting.
+func ParseSpeed(s string) (float64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "max" {
+		return 0, nil
+	}
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(s, "x"), 64)
+	if err != nil || speed < 0 {
+		return 0, fmt.Errorf("invalid speed %q: use a multiplier such as 1x, 10x or 0.5x, or max", s)
+	}
+	return speed, nil
+}
+
+// Player plays a recording back with its original pacing scaled by a
+// speed. It implements core.Pausable, so the TUI can pause playback.
+type Player struct {
+	reader *Reader
+	speed  float64
+	gate   *core.PauseGate
+	err    error
+}
+
+// NewPlayer returns a Player for r at speed times the recorded pace; zero
+// plays events back without waiting.
+func NewPlayer(r *Reader, speed float64) *Player {
+	return &Player{reader: r, speed: speed, gate: core.NewPauseGate()}
+}
+
+var _ core.Pausable = (*Player)(nil)
+
+// Pause holds playback before the next event.
+func (p *Player) Pause() { p.gate.Pause() }
+
+// Resume continues playback; paused time does not count toward the pace.
+func (p *Player) Resume() { p.gate.Resume() }
+
+// Paused reports whether playback is paused.
+func (p *Player) Paused() bool { return p.gate.Paused() }
+
+// Play sends the recording's events on the returned channel, which is
+// closed after the last event, on a read error, or when ctx is done.
+func (p *Player) Play(ctx context.Context) <-chan core.Event {
+	events := make(chan core.Event, 100)
+	go func() {
+		defer close(events)
+		start := time.Now()
+		for {
+			event, offset, err := p.reader.Next()
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					p.err = err
+				}
+				return
+			}
+			if !p.waitFor(ctx, start, offset) {
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// waitFor blocks until offset, scaled by the speed, of unpaused time has
+// passed since start. It returns false if ctx is done first.
+func (p *Player) waitFor(ctx context.Context, start time.Time, offset time.Duration) bool {
+	for {
+		if !p.gate.Wait(ctx) {
+			return false
+		}
+		if p.speed <= 0 {
+			return true
+		}
+		active := time.Since(start) - p.gate.PausedDuration()
+		wait := time.Duration(float64(offset)/p.speed) - active
+		if wait <= 0 {
+			return true
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		}
+	}
+}
+
+// Err returns the read error that ended playback early, if any. Call it
+// after the Play channel is closed.
+func (p *Player) Err() error {
+	return p.err
+}
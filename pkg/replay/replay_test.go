@@ -0,0 +1,119 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf, Header{Targets: []string{"10.0.0.1"}, TotalPorts: 2, Protocol: "tcp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanErr := &core.ScanError{Host: "10.0.0.1", Port: 53, Protocol: "tcp", Time: time.Unix(1700000000, 0), Err: errors.New("no route")}
+	events := []core.Event{
+		core.NewTimelineEvent(core.TimelineEntry{Time: time.Unix(1700000000, 0), Kind: core.TimelineHostStart, Host: "10.0.0.1"}),
+		core.NewResultEvent(core.ResultEvent{
+			Host: "10.0.0.1", Port: 443, State: core.StateOpen, Protocol: "tcp", Duration: 4 * time.Millisecond,
+			TLS: &core.TLSInfo{Version: "TLS 1.3", SANs: []string{"example.com"}},
+		}),
+		core.NewProgressEvent(core.ProgressEvent{Total: 2, Completed: 1, RecentRate: 10}),
+		core.NewErrorEvent(scanErr),
+		core.NewErrorEvent(errors.New("scanner stopped")),
+	}
+	for _, event := range events {
+		rec.Observe(event)
+	}
+	if err := rec.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := NewReader(&buf)
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	if reader.Header.TotalPorts != 2 || reader.Header.Targets[0] != "10.0.0.1" || reader.Header.Started.IsZero() {
+		t.Errorf("header = %+v", reader.Header)
+	}
+
+	player := NewPlayer(reader, 0)
+	var got []core.Event
+	for event := range player.Play(context.Background()) {
+		got = append(got, event)
+	}
+	if err := player.Err(); err != nil {
+		t.Fatalf("playback error: %v", err)
+	}
+	if len(got) != len(events) {
+		t.Fatalf("replayed %d events; want %d", len(got), len(events))
+	}
+	for i := 0; i < 3; i++ {
+		if !reflect.DeepEqual(got[i], events[i]) {
+			t.Errorf("event %d = %+v; want %+v", i, got[i], events[i])
+		}
+	}
+	var replayed *core.ScanError
+	if !errors.As(got[3].Error, &replayed) || replayed.Host != "10.0.0.1" || replayed.Port != 53 || replayed.Err.Error() != "no route" {
+		t.Errorf("probe error replayed as %#v", got[3].Error)
+	}
+	if got[4].Error.Error() != "scanner stopped" {
+		t.Errorf("error replayed as %v", got[4].Error)
+	}
+}
+
+func TestPlayerPacing(t *testing.T) {
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf, Header{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Observe(core.NewResultEvent(core.ResultEvent{Port: 1}))
+	time.Sleep(100 * time.Millisecond)
+	rec.Observe(core.NewResultEvent(core.ResultEvent{Port: 2}))
+
+	reader, err := NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	for range NewPlayer(reader, 2).Play(context.Background()) {
+	}
+	if elapsed := time.Since(start); elapsed < 45*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Errorf("playing 100ms at 2x took %v; want about 50ms", elapsed)
+	}
+}
+
+func TestNewReaderRejectsOtherFiles(t *testing.T) {
+	if _, err := NewReader(strings.NewReader(`{"host":"10.0.0.1"}`)); err == nil {
+		t.Error("NewReader accepted a JSON file")
+	}
+}
+
+func TestParseSpeed(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"10x", 10, true},
+		{"0.5", 0.5, true},
+		{"1X", 1, true},
+		{"max", 0, true},
+		{"fast", 0, false},
+		{"-2x", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseSpeed(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseSpeed(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+}
@@ -20,17 +20,30 @@
 //
 // Service Database:
 //
-// The package includes mappings for:
-//   - Common TCP services (HTTP, HTTPS, SSH, FTP, SMTP, etc.)
-//   - Common UDP services (DNS, DHCP, NTP, SNMP, etc.)
-//   - Database ports (MySQL, PostgreSQL, MongoDB, Redis, etc.)
-//   - Application servers (Tomcat, WebLogic, JBoss, etc.)
-//   - Message queues (RabbitMQ, Kafka, ActiveMQ, etc.)
-//   - VPN protocols (OpenVPN, WireGuard, IPSec, etc.)
-//   - VoIP services (SIP, RTP, IAX2, etc.)
-//
-// The service names follow IANA port assignment conventions where applicable,
-// with additional entries for popular non-standard services.
+// Names come from three layers, each overriding the one before:
+//   - iana_services.txt, generated from the IANA Service Name and Transport
+//     Protocol Port Number Registry by gen_iana.go ('go generate') and
+//     embedded at build time; its header names the registry copy it was
+//     built from and the date that copy was fetched
+//   - names.yaml, embedded friendly names where the registered one is
+//     obscure (smb rather than microsoft-ds) or the port is unregistered
+//   - the user override file, OverridePath, by default
+//     ~/.config/portscan/services.yaml, merged when the database loads
+//
+// The override file maps ports to names per protocol:
+//
+//	both:
+//	  8080: proxy
+//	tcp:
+//	  9200: elasticsearch
+//	udp:
+//	  27015: steam
+//
+// A missing override file is ignored; a malformed one is skipped and
+// reported by OverrideError.
+//
+// Ports is the reverse lookup, from a service name to its ports, used to
+// resolve names in port specifications such as "ssh,https".
 //
 // Protocol-Specific Lookups:
 //
@@ -40,8 +53,17 @@
 //   - TCP 161: snmp (SNMP over TCP)
 //   - UDP 161: snmp (SNMP traps)
 //
+// Banner Identification:
+//
+// Identify names the service a TCP banner came from ("SSH-2.0-..." is
+// ssh), and Mismatch compares it with the port's registration, so SSH on
+// 8080 can be flagged:
+//
+//	detected, expected, ok := services.Mismatch(8080, "tcp", banner)
+//	// "ssh", "http-alt", true
+//
 // Performance:
 //
 // Lookups use Go maps for O(1) average-case performance. The service database
-// is loaded once at package initialization.
+// is loaded once, on first lookup.
 package services
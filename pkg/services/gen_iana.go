@@ -0,0 +1,150 @@
+//go:build ignore
+
+// gen_iana builds iana_services.txt, the port to service name table
+// embedded in this package, from the IANA Service Name and Transport
+// Protocol Port Number Registry.
+//
+//	go generate ./pkg/services
+//	go run gen_iana.go -in service-names-port-numbers.csv
+//
+// The input is the registry CSV, by URL or path; by default it is fetched
+// from registryURL. The generated file's header records the source and the
+// date it was read, so a checked-in table shows which registry it reflects.
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const registryURL = "https://www.iana.org/assignments/service-names-port-numbers/service-names-port-numbers.csv"
+
+// maxRange is the largest port range expanded into per-port entries;
+// wider ranges are blocks such as the dynamic ports, not services.
+const maxRange = 256
+
+type entry struct {
+	port  int
+	proto string
+	name  string
+}
+
+func main() {
+	in := flag.String("in", registryURL, "registry CSV, by URL or path")
+	out := flag.String("out", "iana_services.txt", "output file")
+	flag.Parse()
+
+	data, err := read(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "Service Name,") {
+		log.Fatalf("%s: not the IANA registry CSV", *in)
+	}
+	entries, err := parseRegistry(string(data))
+	if err != nil {
+		log.Fatalf("%s: %v", *in, err)
+	}
+	if err := write(*out, *in, time.Now().UTC(), entries); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func read(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "https://") && !strings.HasPrefix(source, "http://") {
+		return os.ReadFile(source)
+	}
+	resp, err := http.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", source, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// parseRegistry reads the registry CSV: Service Name, Port Number,
+// Transport Protocol, ... Rows without a name, port or TCP/UDP transport
+// are reserved or unassigned and skipped.
+func parseRegistry(data string) ([]entry, error) {
+	r := csv.NewReader(strings.NewReader(data))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var entries []entry
+	for _, rec := range records[1:] {
+		if len(rec) < 3 {
+			continue
+		}
+		name, ports, proto := strings.TrimSpace(rec[0]), strings.TrimSpace(rec[1]), strings.TrimSpace(rec[2])
+		if name == "" || ports == "" || (proto != "tcp" && proto != "udp") {
+			continue
+		}
+		lo, hi, ok := parseRange(ports)
+		if !ok || hi-lo >= maxRange {
+			continue
+		}
+		for port := lo; port <= hi; port++ {
+			entries = append(entries, entry{port, proto, strings.ToLower(name)})
+		}
+	}
+	return entries, nil
+}
+
+func parseRange(s string) (lo, hi int, ok bool) {
+	loStr, hiStr, isRange := strings.Cut(s, "-")
+	lo, err := strconv.Atoi(loStr)
+	if err != nil || lo < 1 || lo > 65535 {
+		return 0, 0, false
+	}
+	if !isRange {
+		return lo, lo, true
+	}
+	hi, err = strconv.Atoi(hiStr)
+	if err != nil || hi < lo || hi > 65535 {
+		return 0, 0, false
+	}
+	return lo, hi, true
+}
+
+// write keeps the first name listed for each port and protocol, which the
+// registry uses for the primary assignment. The header names source and
+// the date it was read.
+func write(path, source string, read time.Time, entries []entry) error {
+	seen := make(map[string]bool)
+	var unique []entry
+	for _, e := range entries {
+		key := strconv.Itoa(e.port) + "/" + e.proto
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, e)
+		}
+	}
+	sort.SliceStable(unique, func(i, j int) bool {
+		if unique[i].port != unique[j].port {
+			return unique[i].port < unique[j].port
+		}
+		return unique[i].proto < unique[j].proto
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Code generated by gen_iana.go from %s on %s; DO NOT EDIT.\n", source, read.Format(time.DateOnly))
+	b.WriteString("# port/protocol name\n")
+	for _, e := range unique {
+		fmt.Fprintf(&b, "%d/%s %s\n", e.port, e.proto, e.name)
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
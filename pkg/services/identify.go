@@ -0,0 +1,103 @@
+package services
+
+import "strings"
+
+// signature recognizes a service from the start of its banner. The
+// banner prefix and contains checks are case-sensitive unless lower is
+// set, in which case contains is matched against the lower-cased banner.
+type signature struct {
+	name     string
+	prefix   string
+	contains []string
+	lower    bool
+}
+
+// signatures are tried in order; the first match names the service.
+var signatures = []signature{
+	{name: "ssh", prefix: "SSH-"},
+	{name: "http", prefix: "HTTP/"},
+	{name: "ftp", prefix: "220", contains: []string{"ftp"}, lower: true},
+	{name: "smtp", prefix: "220", contains: []string{"smtp", "mail"}, lower: true},
+	{name: "pop3", prefix: "+OK"},
+	{name: "imap", prefix: "* OK"},
+	{name: "imap", prefix: "* PREAUTH"},
+	{name: "vnc", prefix: "RFB "},
+	{name: "rsync", prefix: "@RSYNCD:"},
+	{name: "amqp", prefix: "AMQP"},
+	{name: "sip", prefix: "SIP/2.0"},
+	{name: "rtsp", prefix: "RTSP/1.0"},
+	{name: "mysql", contains: []string{"mysql_native_password", "caching_sha2_password", "mariadb"}, lower: true},
+}
+
+// aliases lists registered names that are another name for an identified
+// service, beyond those sharing its prefix (http matches http-alt).
+var aliases = map[string][]string{
+	"http": {"webmin", "webcache", "www"},
+	"smtp": {"submission", "submissions", "urd"},
+	"vnc":  {"rfb"},
+}
+
+func (s signature) matches(banner string) bool {
+	if !strings.HasPrefix(banner, s.prefix) {
+		return false
+	}
+	if len(s.contains) == 0 {
+		return true
+	}
+	text := banner
+	if s.lower {
+		text = strings.ToLower(banner)
+	}
+	for _, c := range s.contains {
+		if strings.Contains(text, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// Identify names the service a TCP banner came from, or returns "" when
+// no signature recognizes it.
+func Identify(banner string) string {
+	banner = strings.TrimLeft(banner, " \t\r\n")
+	if banner == "" {
+		return ""
+	}
+	for _, s := range signatures {
+		if s.matches(banner) {
+			return s.name
+		}
+	}
+	return ""
+}
+
+// compatible reports whether the registered name expected covers the
+// identified service detected.
+func compatible(detected, expected string) bool {
+	if strings.HasPrefix(expected, detected) {
+		return true
+	}
+	for _, alias := range aliases[detected] {
+		if expected == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// Mismatch identifies the service from banner and compares it with the one
+// registered for port under protocol. It returns both names and true when
+// they disagree, such as SSH answering on 8080. Unrecognized banners and
+// unregistered ports never mismatch, nor does UDP: its banners describe
+// the probe sent for the port, so they always agree with it.
+func Mismatch(port uint16, protocol, banner string) (detected, expected string, mismatch bool) {
+	if strings.EqualFold(protocol, "udp") {
+		return "", "", false
+	}
+	detected = Identify(banner)
+	expected = LookupTCP(port)
+	if detected == "" || expected == "" {
+		return detected, expected, false
+	}
+	return detected, expected, !compatible(detected, expected)
+}
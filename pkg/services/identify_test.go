@@ -0,0 +1,63 @@
+package services
+
+import "testing"
+
+func TestIdentify(t *testing.T) {
+	tests := []struct {
+		banner string
+		want   string
+	}{
+		{"SSH-2.0-OpenSSH_9.6\r\n", "ssh"},
+		{"HTTP/1.1 400 Bad Request\r\nServer: nginx\r\n", "http"},
+		{"220 (vsFTPd 3.0.5)\r\n", "ftp"},
+		{"220 mail.example.com ESMTP Postfix\r\n", "smtp"},
+		{"+OK Dovecot ready.\r\n", "pop3"},
+		{"* OK [CAPABILITY IMAP4rev1] Dovecot ready.\r\n", "imap"},
+		{"RFB 003.008\n", "vnc"},
+		{"\x4a\x00\x00\x00\x0a8.0.36\x00mysql_native_password\x00", "mysql"},
+		{"  SSH-2.0-dropbear\r\n", "ssh"},
+		{"220 Welcome\r\n", ""},
+		{"hello", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Identify(tt.banner); got != tt.want {
+			t.Errorf("Identify(%q) = %q; want %q", tt.banner, got, tt.want)
+		}
+	}
+}
+
+func TestMismatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		port     uint16
+		protocol string
+		banner   string
+		detected string
+		expected string
+		mismatch bool
+	}{
+		{"SSH on 8080", 8080, "tcp", "SSH-2.0-OpenSSH_9.6", "ssh", "http-alt", true},
+		{"SSH on 22", 22, "tcp", "SSH-2.0-OpenSSH_9.6", "ssh", "ssh", false},
+		{"HTTP on the alternate port", 8080, "tcp", "HTTP/1.1 200 OK", "http", "http-alt", false},
+		{"HTTP on webmin", 10000, "", "HTTP/1.0 200 Document follows", "http", "webmin", false},
+		{"SMTP on submission", 587, "tcp", "220 mx ESMTP", "smtp", "submission", false},
+		{"IMAP on imap2", 143, "tcp", "* OK ready", "imap", "imap", false},
+		{"HTTP on SSH port", 22, "tcp", "HTTP/1.1 400 Bad Request", "http", "ssh", true},
+		{"unregistered port", 60000, "tcp", "SSH-2.0-OpenSSH_9.6", "ssh", "", false},
+		{"unrecognized banner", 8080, "tcp", "hello", "", "http-alt", false},
+		{"UDP is not checked", 53, "udp", "SSH-2.0-OpenSSH_9.6", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detected, expected, mismatch := Mismatch(tt.port, tt.protocol, tt.banner)
+			if detected != tt.detected || expected != tt.expected || mismatch != tt.mismatch {
+				t.Errorf("Mismatch(%d, %q, %q) = (%q, %q, %v); want (%q, %q, %v)",
+					tt.port, tt.protocol, tt.banner, detected, expected, mismatch,
+					tt.detected, tt.expected, tt.mismatch)
+			}
+		})
+	}
+}
@@ -1,59 +1,184 @@
 package services
 
-var services = map[uint16]string{
-	// Common ports (TCP and UDP)
-	53:  "dns", // DNS
-	445: "smb", // SMB
-
-	// TCP ports
-	21:    "ftp",
-	22:    "ssh",
-	23:    "telnet",
-	25:    "smtp",
-	80:    "http",
-	110:   "pop3",
-	143:   "imap",
-	443:   "https",
-	3306:  "mysql",
-	3389:  "rdp",
-	5432:  "postgresql",
-	6379:  "redis",
-	8080:  "http-alt",
-	8443:  "https-alt",
-	27017: "mongodb",
-
-	// UDP-specific ports
-	67:    "dhcp",        // BOOTP/DHCP Server
-	68:    "dhcp",        // BOOTP/DHCP Client
-	69:    "tftp",        // TFTP
-	123:   "ntp",         // NTP
-	137:   "netbios-ns",  // NetBIOS Name Service
-	138:   "netbios-dgm", // NetBIOS Datagram Service
-	139:   "netbios-ssn", // NetBIOS Session Service
-	161:   "snmp",        // SNMP
-	162:   "snmptrap",    // SNMP Trap
-	500:   "isakmp",      // ISAKMP/IKEd (IPSec)
-	514:   "syslog",      // Syslog
-	520:   "rip",         // RIP
-	1194:  "openvpn",     // OpenVPN
-	1701:  "l2tp",        // L2TP
-	1812:  "radius",      // RADIUS Authentication
-	1813:  "radius-acct", // RADIUS Accounting
-	1900:  "ssdp",        // UPnP SSDP
-	3478:  "stun",        // STUN/TURN
-	4500:  "ipsec-nat",   // IPSec NAT-Traversal
-	5060:  "sip",         // SIP
-	5061:  "sips",        // SIP over TLS
-	5353:  "mdns",        // mDNS
-	5355:  "llmnr",       // LLMNR
-	10000: "webmin",      // Webmin or ndmp
-	51820: "wireguard",   // WireGuard
-}
-
-// GetName returns a human-friendly service name for a well-known port.
-// Falls back to "unknown" if the port is not in the map.
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.yaml.in/yaml/v3"
+)
+
+//go:generate go run gen_iana.go -out iana_services.txt
+
+// ianaServices is the registry table written by gen_iana.go.
+//
+//go:embed iana_services.txt
+var ianaServices string
+
+// preferredNames override registry names with the ones users know.
+//
+//go:embed names.yaml
+var preferredNames []byte
+
+type portKey struct {
+	port  uint16
+	proto string
+}
+
+// nameFile is the layout of names.yaml and the user override file.
+type nameFile struct {
+	Both map[uint16]string `yaml:"both"`
+	TCP  map[uint16]string `yaml:"tcp"`
+	UDP  map[uint16]string `yaml:"udp"`
+}
+
+var (
+	loadOnce    sync.Once
+	names       map[portKey]string
+	overrideErr error
+)
+
+// load builds the table once: registry names, then names.yaml, then the
+// user override file, each replacing the names before it.
+func load() map[portKey]string {
+	loadOnce.Do(func() {
+		names = make(map[portKey]string)
+		scanner := bufio.NewScanner(strings.NewReader(ianaServices))
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			spec, name, _ := strings.Cut(line, " ")
+			portStr, proto, _ := strings.Cut(spec, "/")
+			port, err := strconv.ParseUint(portStr, 10, 16)
+			if err != nil {
+				panic("services: invalid embedded entry " + line)
+			}
+			names[portKey{uint16(port), proto}] = name
+		}
+
+		var preferred nameFile
+		if err := yaml.Unmarshal(preferredNames, &preferred); err != nil {
+			panic("services: invalid names.yaml: " + err.Error())
+		}
+		merge(names, preferred)
+
+		path, err := OverridePath()
+		if err != nil {
+			return
+		}
+		overrides, err := loadNameFile(path)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				overrideErr = fmt.Errorf("service overrides %s: %w", path, err)
+			}
+			return
+		}
+		merge(names, overrides)
+	})
+	return names
+}
+
+func loadNameFile(path string) (nameFile, error) {
+	var file nameFile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file, err
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return file, err
+	}
+	return file, nil
+}
+
+func merge(into map[portKey]string, file nameFile) {
+	for port, name := range file.Both {
+		into[portKey{port, "tcp"}] = name
+		into[portKey{port, "udp"}] = name
+	}
+	for port, name := range file.TCP {
+		into[portKey{port, "tcp"}] = name
+	}
+	for port, name := range file.UDP {
+		into[portKey{port, "udp"}] = name
+	}
+}
+
+// OverridePath returns the user service name file merged over the built-in
+// names: $XDG_CONFIG_HOME/portscan/services.yaml, or
+// ~/.config/portscan/services.yaml.
+func OverridePath() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "portscan", "services.yaml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "portscan", "services.yaml"), nil
+}
+
+// OverrideError returns the problem found loading the user override file,
+// or nil if it loaded or does not exist.
+func OverrideError() error {
+	load()
+	return overrideErr
+}
+
+// LookupTCP returns the service name for a TCP port, or "" if unknown.
+func LookupTCP(port uint16) string {
+	return load()[portKey{port, "tcp"}]
+}
+
+// LookupUDP returns the service name for a UDP port, or "" if unknown.
+func LookupUDP(port uint16) string {
+	return load()[portKey{port, "udp"}]
+}
+
+// Name returns the service name for port under protocol, "tcp" (the
+// default when empty) or "udp", or "unknown" if the port is not assigned
+// for that protocol.
+func Name(port uint16, protocol string) string {
+	name := LookupTCP(port)
+	if strings.EqualFold(protocol, "udp") {
+		name = LookupUDP(port)
+	}
+	if name == "" {
+		return "unknown"
+	}
+	return name
+}
+
+// Ports returns the ports, ascending, whose TCP or UDP service name is
+// name, ignoring case, or nil if no port has that name.
+func Ports(name string) []uint16 {
+	seen := make(map[uint16]bool)
+	var ports []uint16
+	for key, service := range load() {
+		if strings.EqualFold(service, name) && !seen[key.port] {
+			seen[key.port] = true
+			ports = append(ports, key.port)
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	return ports
+}
+
+// GetName returns a human-friendly service name for a well-known port,
+// preferring the TCP assignment. Falls back to "unknown" if the port is
+// not in the database.
 func GetName(port uint16) string {
-	if name, ok := services[port]; ok {
+	if name := LookupTCP(port); name != "" {
+		return name
+	}
+	if name := LookupUDP(port); name != "" {
 		return name
 	}
 	return "unknown"
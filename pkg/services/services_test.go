@@ -1,6 +1,10 @@
 package services
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -40,7 +44,7 @@ func TestGetName(t *testing.T) {
 		{"SNMP Trap", 162, "snmptrap"},
 		{"ISAKMP", 500, "isakmp"},
 		{"Syslog", 514, "syslog"},
-		{"RIP", 520, "rip"},
+		{"RIP port, named for TCP first", 520, "efs"},
 		{"OpenVPN", 1194, "openvpn"},
 		{"L2TP", 1701, "l2tp"},
 		{"RADIUS Auth", 1812, "radius"},
@@ -58,10 +62,12 @@ func TestGetName(t *testing.T) {
 		// Common ports (both TCP and UDP)
 		{"SMB", 445, "smb"},
 
-		// Unknown ports
-		{"Unknown port 1", 1, "unknown"},
-		{"Unknown port 9999", 9999, "unknown"},
-		{"Unknown port 12345", 12345, "unknown"},
+		// Registry names for ports without a preferred name
+		{"TCP multiplexer", 1, "tcpmux"},
+
+		// Unknown ports (the dynamic range, 49152-65535, is never assigned)
+		{"Unknown port 49152", 49152, "unknown"},
+		{"Unknown port 60000", 60000, "unknown"},
 		{"Unknown port 65534", 65534, "unknown"},
 	}
 
@@ -82,7 +88,7 @@ func TestGetNameBoundaryConditions(t *testing.T) {
 		expected string
 	}{
 		{"Port 0", 0, "unknown"},
-		{"Port 1", 1, "unknown"},
+		{"Port 1", 1, "tcpmux"},
 		{"Port 65535 (max)", 65535, "unknown"},
 	}
 
@@ -166,3 +172,93 @@ func BenchmarkGetNameMultiplePorts(b *testing.B) {
 		}
 	}
 }
+
+func TestLookupByProtocol(t *testing.T) {
+	if got := LookupTCP(22); got != "ssh" {
+		t.Errorf("LookupTCP(22) = %q; want ssh", got)
+	}
+	if got := LookupUDP(123); got != "ntp" {
+		t.Errorf("LookupUDP(123) = %q; want ntp", got)
+	}
+	if tcp, udp := LookupTCP(520), LookupUDP(520); tcp != "efs" || udp != "rip" {
+		t.Errorf("port 520 = %q/tcp, %q/udp; want efs and rip", tcp, udp)
+	}
+if got := LookupTCP(60000); got != "" {
+		t.Errorf("LookupTCP(60000) = %q; want empty", got)
+	}
+}
+
+func TestPorts(t *testing.T) {
+	tests := []struct {
+		name string
+		want []uint16
+	}{
+		{"ssh", []uint16{22}},
+		{"HTTPS", []uint16{443}},
+		{"dns", []uint16{53}},
+		{"ntp", []uint16{123}},
+		{"no-such-service", nil},
+	}
+	for _, tt := range tests {
+		if got := Ports(tt.name); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Ports(%q) = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// reload discards the loaded table so the next lookup reads the override
+// file again.
+func reload(t *testing.T) {
+	t.Helper()
+	loadOnce = sync.Once{}
+	overrideErr = nil
+	t.Cleanup(func() {
+		loadOnce = sync.Once{}
+		overrideErr = nil
+	})
+}
+
+func TestUserOverrides(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "portscan"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	overrides := "both:\n  8080: proxy\nudp:\n  60000: game\n"
+	if err := os.WriteFile(filepath.Join(dir, "portscan", "services.yaml"), []byte(overrides), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reload(t)
+
+	if err := OverrideError(); err != nil {
+		t.Fatalf("OverrideError() = %v", err)
+	}
+	if got := GetName(8080); got != "proxy" {
+		t.Errorf("GetName(8080) = %q; want the override proxy", got)
+	}
+	if got, tcp := LookupUDP(60000), LookupTCP(60000); got != "game" || tcp != "" {
+		t.Errorf("udp override: LookupUDP = %q, LookupTCP = %q", got, tcp)
+	}
+	if got := GetName(22); got != "ssh" {
+		t.Errorf("GetName(22) = %q; built-in names should remain", got)
+	}
+}
+
+func TestUserOverrides_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "portscan"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "portscan", "services.yaml"), []byte("tcp: [not, a, map]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reload(t)
+
+	if err := OverrideError(); err == nil {
+		t.Error("expected an error for an invalid override file")
+	}
+	if got := GetName(22); got != "ssh" {
+		t.Errorf("GetName(22) = %q; built-in names should load despite a bad override file", got)
+	}
+}
@@ -0,0 +1,114 @@
+package targets
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// ParseASN parses an autonomous system number written as "AS13335",
+// "as13335" or "13335".
+func ParseASN(s string) (uint32, error) {
+	digits := strings.TrimSpace(s)
+	if len(digits) > 2 && strings.EqualFold(digits[:2], "AS") {
+		digits = digits[2:]
+	}
+	asn, err := strconv.ParseUint(digits, 10, 32)
+	if err != nil || asn == 0 {
+		return 0, fmt.Errorf("%q is not an AS number, e.g. AS13335", s)
+	}
+	return uint32(asn), nil
+}
+
+// ReadPrefixes reads a prefix-to-AS dataset in the format CAIDA publishes
+// from RouteViews tables, plain or gzipped, and returns the prefixes each
+// of asns announces, in file order. Each line holds an address, a prefix
+// length and the origin AS, separated by whitespace; a prefix with several
+// origins lists them joined by '_', or by ',' for an AS set, and counts
+// for each of them.
+func ReadPrefixes(r io.Reader, asns []uint32) (map[uint32][]netip.Prefix, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		br = bufio.NewReader(gz)
+	}
+
+	wanted := make(map[uint32]bool, len(asns))
+	for _, asn := range asns {
+		wanted[asn] = true
+	}
+	prefixes := make(map[uint32][]netip.Prefix)
+	scanner := bufio.NewScanner(br)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: want address, prefix length and AS, got %q", line, scanner.Text())
+		}
+		var origins []uint32
+		for _, field := range strings.FieldsFunc(fields[2], func(r rune) bool { return r == '_' || r == ',' }) {
+			if asn, err := strconv.ParseUint(field, 10, 32); err == nil && wanted[uint32(asn)] {
+				origins = append(origins, uint32(asn))
+			}
+		}
+		if len(origins) == 0 {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(fields[0] + "/" + fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		for _, asn := range origins {
+			prefixes[asn] = append(prefixes[asn], prefix.Masked())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return prefixes, nil
+}
+
+// SplitPrefix divides p into prefixes of length bits, so each stays under
+// a CIDR host limit. A prefix at least that long is returned as is.
+func SplitPrefix(p netip.Prefix, bits int) []netip.Prefix {
+	if p.Bits() >= bits || bits > p.Addr().BitLen() {
+		return []netip.Prefix{p}
+	}
+	count := 1 << (bits - p.Bits())
+	out := make([]netip.Prefix, 0, count)
+	addr := p.Masked().Addr()
+	for i := 0; i < count; i++ {
+		out = append(out, netip.PrefixFrom(addr, bits))
+		addr = advance(addr, bits)
+	}
+	return out
+}
+
+// advance returns the first address after the prefix of length bits
+// starting at addr.
+func advance(addr netip.Addr, bits int) netip.Addr {
+	b := addr.AsSlice()
+	i := bits - 1
+	for ; i >= 0; i-- {
+		mask := byte(1) << (7 - i%8)
+		if b[i/8]&mask == 0 {
+			b[i/8] |= mask
+			break
+		}
+		b[i/8] &^= mask
+	}
+	next, _ := netip.AddrFromSlice(b)
+	return next
+}
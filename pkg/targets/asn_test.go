@@ -0,0 +1,83 @@
+package targets
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const pfx2as = `1.0.0.0	24	13335
+104.16.0.0	13	13335
+8.8.8.0	24	15169
+198.51.100.0	24	64500_13335
+203.0.113.0	24	64501,15169
+2606:4700::	32	13335
+`
+
+func TestParseASN(t *testing.T) {
+	for input, want := range map[string]uint32{"AS13335": 13335, "as15169": 15169, " 64500 ": 64500} {
+		if got, err := ParseASN(input); err != nil || got != want {
+			t.Errorf("ParseASN(%q) = %d, %v; want %d", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "AS", "cloudflare", "AS0", "AS4294967296", "-1"} {
+		if _, err := ParseASN(input); err == nil {
+			t.Errorf("ParseASN(%q) should fail", input)
+		}
+	}
+}
+
+func TestReadPrefixes(t *testing.T) {
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	_, _ = gz.Write([]byte(pfx2as))
+	_ = gz.Close()
+
+	want := map[uint32][]netip.Prefix{
+		13335: {
+			netip.MustParsePrefix("1.0.0.0/24"),
+			netip.MustParsePrefix("104.16.0.0/13"),
+			netip.MustParsePrefix("198.51.100.0/24"),
+			netip.MustParsePrefix("2606:4700::/32"),
+		},
+		15169: {
+			netip.MustParsePrefix("8.8.8.0/24"),
+			netip.MustParsePrefix("203.0.113.0/24"),
+		},
+	}
+	for name, data := range map[string][]byte{"plain": []byte(pfx2as), "gzip": gzipped.Bytes()} {
+		got, err := ReadPrefixes(bytes.NewReader(data), []uint32{13335, 15169})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: ReadPrefixes = %v, want %v", name, got, want)
+		}
+	}
+
+	if _, err := ReadPrefixes(strings.NewReader("1.0.0.0 24\n"), []uint32{13335}); err == nil {
+		t.Error("a line without an AS should fail")
+	}
+	if _, err := ReadPrefixes(strings.NewReader("1.0.0.0 33 13335\n"), []uint32{13335}); err == nil {
+		t.Error("an invalid prefix length should fail")
+	}
+}
+
+func TestSplitPrefix(t *testing.T) {
+	got := SplitPrefix(netip.MustParsePrefix("104.16.0.0/14"), 16)
+	want := []netip.Prefix{
+		netip.MustParsePrefix("104.16.0.0/16"),
+		netip.MustParsePrefix("104.17.0.0/16"),
+		netip.MustParsePrefix("104.18.0.0/16"),
+		netip.MustParsePrefix("104.19.0.0/16"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitPrefix(/14) = %v, want %v", got, want)
+	}
+	if got := SplitPrefix(netip.MustParsePrefix("1.0.0.0/24"), 16); len(got) != 1 || got[0].Bits() != 24 {
+		t.Errorf("SplitPrefix(/24) = %v, want it unchanged", got)
+	}
+}
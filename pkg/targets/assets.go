@@ -0,0 +1,200 @@
+package targets
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Asset is one record of an asset inventory: a target and the labels to
+// attach to its results.
+type Asset struct {
+	Host string
+	Tags []string // "field=value" for each tag field with a value
+}
+
+// AssetOptions selects the fields ReadAssets reads. Field names match
+// columns or keys ignoring case.
+type AssetOptions struct {
+	HostField string   // the field holding the host, address or CIDR
+	TagFields []string // fields attached to the host as tags
+}
+
+// ReadAssets reads an asset inventory: CSV with a header row, or JSON, as
+// an array of objects or one object per line. Records with an empty host
+// field are skipped and counted. A host or tag field missing from the CSV
+// header is an error; a key missing from a JSON record is empty.
+func ReadAssets(r io.Reader, opts AssetOptions) ([]Asset, int, error) {
+	if strings.TrimSpace(opts.HostField) == "" {
+		return nil, 0, errors.New("no host field given")
+	}
+	br := bufio.NewReader(r)
+	// Spreadsheet exports often start with a byte order mark.
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte{0xef, 0xbb, 0xbf}) {
+		_, _ = br.Discard(3)
+	}
+	for {
+		c, err := br.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, 0, errors.New("asset file is empty")
+			}
+			return nil, 0, err
+		}
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+			continue
+		}
+		_ = br.UnreadByte()
+		if c == '[' || c == '{' {
+			return readJSONAssets(br, opts, c == '[')
+		}
+		return readCSVAssets(br, opts)
+	}
+}
+
+func readCSVAssets(r io.Reader, opts AssetOptions) ([]Asset, int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, 0, fmt.Errorf("read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, dup := columns[key]; !dup {
+			columns[key] = i
+		}
+	}
+	column := func(field string) (int, error) {
+		i, ok := columns[strings.ToLower(strings.TrimSpace(field))]
+		if !ok {
+			return 0, fmt.Errorf("CSV header has no %q column", field)
+		}
+		return i, nil
+	}
+	hostColumn, err := column(opts.HostField)
+	if err != nil {
+		return nil, 0, err
+	}
+	tagColumns := make([]int, len(opts.TagFields))
+	for i, field := range opts.TagFields {
+		if tagColumns[i], err = column(field); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	cell := func(row []string, i int) string {
+		if i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+	var assets []Asset
+	skipped := 0
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(row) == 1 && strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+		host := cell(row, hostColumn)
+		if host == "" {
+			skipped++
+			continue
+		}
+		asset := Asset{Host: host}
+		for i, field := range opts.TagFields {
+			if value := cell(row, tagColumns[i]); value != "" {
+				asset.Tags = append(asset.Tags, strings.TrimSpace(field)+"="+value)
+			}
+		}
+		assets = append(assets, asset)
+	}
+	return assets, skipped, nil
+}
+
+func readJSONAssets(r io.Reader, opts AssetOptions, array bool) ([]Asset, int, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var records []map[string]interface{}
+	if array {
+		if err := dec.Decode(&records); err != nil {
+			return nil, 0, fmt.Errorf("read JSON assets: %w", err)
+		}
+		return jsonAssets(records, opts)
+	}
+	for {
+		var record map[string]interface{}
+		if err := dec.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, 0, fmt.Errorf("JSON asset %d: %w", len(records)+1, err)
+		}
+		records = append(records, record)
+	}
+	return jsonAssets(records, opts)
+}
+
+// jsonAssets picks the host and tag fields out of decoded JSON records.
+func jsonAssets(records []map[string]interface{}, opts AssetOptions) ([]Asset, int, error) {
+	var assets []Asset
+	skipped := 0
+	for n, record := range records {
+		fields := make(map[string]interface{}, len(record))
+		for key, value := range record {
+			fields[strings.ToLower(key)] = value
+		}
+		host, err := jsonField(fields, opts.HostField)
+		if err != nil {
+			return nil, 0, fmt.Errorf("JSON asset %d: %w", n+1, err)
+		}
+		if host == "" {
+			skipped++
+			continue
+		}
+		asset := Asset{Host: host}
+		for _, field := range opts.TagFields {
+			value, err := jsonField(fields, field)
+			if err != nil {
+				return nil, 0, fmt.Errorf("JSON asset %d: %w", n+1, err)
+			}
+			if value != "" {
+				asset.Tags = append(asset.Tags, strings.TrimSpace(field)+"="+value)
+			}
+		}
+		assets = append(assets, asset)
+	}
+	return assets, skipped, nil
+}
+
+// jsonField returns a string, number or boolean field as text; a missing
+// or null field is empty.
+func jsonField(fields map[string]interface{}, name string) (string, error) {
+	switch v := fields[strings.ToLower(strings.TrimSpace(name))].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return strings.TrimSpace(v), nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("field %q is not a string, number or boolean", name)
+	}
+}
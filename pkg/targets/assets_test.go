@@ -0,0 +1,87 @@
+package targets
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadAssets(t *testing.T) {
+	opts := AssetOptions{HostField: "ip", TagFields: []string{"owner", "env"}}
+	want := []Asset{
+		{Host: "10.0.0.1", Tags: []string{"owner=alice", "env=prod"}},
+		{Host: "10.0.1.0/30", Tags: []string{"env=dev"}},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "csv",
+			input: "name,IP,Owner,env\nweb,10.0.0.1,alice,prod\nlab,10.0.1.0/30,,dev\nretired,,bob,prod\n",
+		},
+		{
+			name:  "csv with byte order mark and quoting",
+			input: "\ufeff\"ip\",\"owner\",\"env\"\r\n\"10.0.0.1\",\"alice\",\"prod\"\r\n\r\n10.0.1.0/30, ,dev\r\n,bob,prod\r\n",
+		},
+		{
+			name:  "json array",
+			input: `[{"ip":"10.0.0.1","owner":"alice","env":"prod"},{"IP":"10.0.1.0/30","owner":null,"env":"dev"},{"owner":"bob"}]`,
+		},
+		{
+			name:  "json lines",
+			input: "{\"ip\":\"10.0.0.1\",\"owner\":\"alice\",\"env\":\"prod\"}\n{\"ip\":\"10.0.1.0/30\",\"env\":\"dev\"}\n{\"ip\":\"\"}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, skipped, err := ReadAssets(strings.NewReader(tt.input), opts)
+			if err != nil {
+				t.Fatalf("ReadAssets() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ReadAssets() = %+v, want %+v", got, want)
+			}
+			if skipped != 1 {
+				t.Errorf("skipped = %d, want 1", skipped)
+			}
+		})
+	}
+}
+
+func TestReadAssetsJSONValues(t *testing.T) {
+	got, _, err := ReadAssets(strings.NewReader(`[{"ip":"10.0.0.1","rack":12,"pci":true}]`),
+		AssetOptions{HostField: "ip", TagFields: []string{"rack", "pci"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags := got[0].Tags; !reflect.DeepEqual(tags, []string{"rack=12", "pci=true"}) {
+		t.Errorf("tags = %q", tags)
+	}
+}
+
+func TestReadAssetsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  AssetOptions
+		want  string
+	}{
+		{"no host field", "ip\n10.0.0.1\n", AssetOptions{}, "no host field"},
+		{"empty file", " \n", AssetOptions{HostField: "ip"}, "empty"},
+		{"missing host column", "addr,owner\n10.0.0.1,alice\n", AssetOptions{HostField: "ip"}, `no "ip" column`},
+		{"missing tag column", "ip\n10.0.0.1\n", AssetOptions{HostField: "ip", TagFields: []string{"env"}}, `no "env" column`},
+		{"bad quoting", "ip\n\"10.0.0.1\n", AssetOptions{HostField: "ip"}, "quote"},
+		{"nested json value", `[{"ip":"10.0.0.1","env":{"a":1}}]`, AssetOptions{HostField: "ip", TagFields: []string{"env"}}, "JSON asset 1"},
+		{"truncated json", `{"ip":"10.0.0.1"}` + "\n{\"ip\":", AssetOptions{HostField: "ip"}, "JSON asset 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ReadAssets(strings.NewReader(tt.input), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ReadAssets() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
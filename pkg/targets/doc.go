@@ -28,12 +28,31 @@
 //
 // Validation:
 //
-// The Validate function provides comprehensive input validation:
+// The Validate functions provide comprehensive input validation:
 //   - IP address format checking
 //   - Hostname resolution verification
 //   - CIDR notation validation
-//   - Protection against private IP scanning (configurable)
-//   - Localhost scanning restrictions (configurable)
+//
+// ValidatePolicy applies a Policy on top: with AllowPrivate or
+// AllowLocalhost off it rejects private and loopback addresses, CIDRs that
+// overlap those ranges, and the name localhost.
+//
+// Asset Inventories:
+//
+// ReadAssets reads targets from a CSV or JSON asset inventory, taking the
+// host from one field and turning other fields into "field=value" tags.
+//
+// Autonomous Systems:
+//
+// ReadPrefixes reads the prefixes autonomous systems announce from a
+// RouteViews prefix-to-AS dataset, and SplitPrefix divides large ones
+// into CIDRs the expansion limit allows.
+//
+// Exclusions:
+//
+// An Exclusion matches targets that must not be probed by glob patterns
+// over the names their addresses reverse-resolve to, from
+// Resolver.LookupAddr, or over their tags.
 //
 // Deduplication:
 //
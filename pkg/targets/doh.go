@@ -0,0 +1,101 @@
+package targets
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// maxDNSMessage is the largest DNS message a length prefix can frame.
+const maxDNSMessage = 65535
+
+// dohConn carries the Go resolver's DNS-over-TCP exchange over DNS over
+// HTTPS (RFC 8484): each length-prefixed query written to it is POSTed as
+// an application/dns-message, and the answer is read back with the same
+// framing.
+type dohConn struct {
+	ctx      context.Context
+	client   *http.Client
+	url      string
+	query    bytes.Buffer
+	answer   bytes.Buffer
+	deadline time.Time
+}
+
+func newDoHConn(ctx context.Context, client *http.Client, url string) *dohConn {
+	return &dohConn{ctx: ctx, client: client, url: url}
+}
+
+func (c *dohConn) Write(b []byte) (int, error) {
+	return c.query.Write(b)
+}
+
+func (c *dohConn) Read(b []byte) (int, error) {
+	if c.answer.Len() == 0 {
+		if err := c.exchange(); err != nil {
+			return 0, err
+		}
+	}
+	return c.answer.Read(b)
+}
+
+// exchange sends the next buffered query and buffers its framed answer.
+func (c *dohConn) exchange() error {
+	framed := c.query.Bytes()
+	if len(framed) < 2 || len(framed) < 2+int(binary.BigEndian.Uint16(framed)) {
+		return errors.New("dns over https: incomplete query")
+	}
+	size := int(binary.BigEndian.Uint16(framed))
+	query := bytes.Clone(framed[2 : 2+size])
+	c.query.Next(2 + size)
+
+	ctx := c.ctx
+	if !c.deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, c.deadline)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(query))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("dns over https: %s returned %s", c.url, resp.Status)
+	}
+	answer, err := io.ReadAll(io.LimitReader(resp.Body, maxDNSMessage+1))
+	if err != nil {
+		return err
+	}
+	if len(answer) > maxDNSMessage {
+		return errors.New("dns over https: answer too large")
+	}
+	c.answer.Write(binary.BigEndian.AppendUint16(nil, uint16(len(answer))))
+	c.answer.Write(answer)
+	return nil
+}
+
+func (c *dohConn) Close() error                       { return nil }
+func (c *dohConn) LocalAddr() net.Addr                { return dohAddr("local") }
+func (c *dohConn) RemoteAddr() net.Addr               { return dohAddr(c.url) }
+func (c *dohConn) SetDeadline(t time.Time) error      { c.deadline = t; return nil }
+func (c *dohConn) SetReadDeadline(t time.Time) error  { c.deadline = t; return nil }
+func (c *dohConn) SetWriteDeadline(_ time.Time) error { return nil }
+
+// dohAddr names the endpoints of a dohConn.
+type dohAddr string
+
+func (a dohAddr) Network() string { return "https" }
+func (a dohAddr) String() string  { return string(a) }
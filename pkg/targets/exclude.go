@@ -0,0 +1,74 @@
+package targets
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Exclusion matches targets that must never be probed, by the names their
+// addresses reverse-resolve to or by their tags. Patterns are shell globs,
+// as in path.Match, compared without regard to case.
+type Exclusion struct {
+	Names []string // name patterns, e.g. "*.scada.corp"
+	Tags  []string // key=value tag patterns, e.g. "role=plc*"
+}
+
+// ParseExclusion checks name and tag patterns and returns an Exclusion
+// for them, or nil when there are none.
+func ParseExclusion(names, tags []string) (*Exclusion, error) {
+	e := &Exclusion{}
+	for _, pattern := range names {
+		pattern = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(pattern), "."))
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid name pattern %q: %w", pattern, err)
+		}
+		e.Names = append(e.Names, pattern)
+	}
+	for _, pattern := range tags {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if key, _, ok := strings.Cut(pattern, "="); !ok || key == "" {
+			return nil, fmt.Errorf("invalid tag pattern %q: want key=value, e.g. role=plc*", pattern)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid tag pattern %q: %w", pattern, err)
+		}
+		e.Tags = append(e.Tags, strings.ToLower(pattern))
+	}
+	if len(e.Names) == 0 && len(e.Tags) == 0 {
+		return nil, nil
+	}
+	return e, nil
+}
+
+// MatchName reports whether a host name, such as a PTR record, matches a
+// name pattern. A trailing dot is ignored.
+func (e *Exclusion) MatchName(name string) bool {
+	return matchAny(e.Names, strings.TrimSuffix(name, "."))
+}
+
+// MatchTags returns the first of tags matching a tag pattern.
+func (e *Exclusion) MatchTags(tags []string) (string, bool) {
+	for _, tag := range tags {
+		if matchAny(e.Tags, tag) {
+			return tag, true
+		}
+	}
+	return "", false
+}
+
+func matchAny(patterns []string, s string) bool {
+	s = strings.ToLower(s)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, s); ok {
+			return true
+		}
+	}
+	return false
+}
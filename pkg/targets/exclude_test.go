@@ -0,0 +1,52 @@
+package targets
+
+import "testing"
+
+func TestExclusion(t *testing.T) {
+	e, err := ParseExclusion([]string{" *.SCADA.corp. ", ""}, []string{"role=plc*", "env=prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []struct {
+		name string
+		want bool
+	}{
+		{"plc1.scada.corp", true},
+		{"PLC1.Scada.Corp.", true},
+		{"hmi.line2.scada.corp", true},
+		{"scada.corp", false},
+		{"web.corp", false},
+	}
+	for _, tt := range names {
+		if got := e.MatchName(tt.name); got != tt.want {
+			t.Errorf("MatchName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if tag, ok := e.MatchTags([]string{"owner=alice", "role=PLC-7"}); !ok || tag != "role=PLC-7" {
+		t.Errorf("MatchTags() = %q, %v, want role=PLC-7", tag, ok)
+	}
+	if _, ok := e.MatchTags([]string{"env=production", "role=hmi"}); ok {
+		t.Error("MatchTags() matched tags no pattern covers")
+	}
+}
+
+func TestParseExclusion(t *testing.T) {
+	if e, err := ParseExclusion(nil, []string{" "}); e != nil || err != nil {
+		t.Errorf("ParseExclusion() with no patterns = %v, %v, want nil", e, err)
+	}
+	bad := []struct {
+		names, tags []string
+	}{
+		{names: []string{"[a-"}},
+		{tags: []string{"plc"}},
+		{tags: []string{"=plc"}},
+		{tags: []string{"role=[x"}},
+	}
+	for _, tt := range bad {
+		if _, err := ParseExclusion(tt.names, tt.tags); err == nil {
+			t.Errorf("ParseExclusion(%q, %q) should fail", tt.names, tt.tags)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package targets
+
+import (
+	"net"
+	"strings"
+
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+)
+
+// Policy restricts which kinds of address a scan may target.
+type Policy struct {
+	AllowLocalhost bool // loopback addresses and the name localhost
+	AllowPrivate   bool // RFC 1918, RFC 4193 (fc00::/7) and link-local ranges
+}
+
+// privateNetworks are the ranges AllowPrivate covers.
+var privateNetworks = mustParseCIDRs(
+	"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "169.254.0.0/16",
+	"fc00::/7", "fe80::/10",
+)
+
+// loopbackNetworks are the ranges AllowLocalhost covers.
+var loopbackNetworks = mustParseCIDRs("127.0.0.0/8", "::1/128")
+
+// ValidatePolicy checks a target (IP address, CIDR or hostname) against
+// policy. A CIDR is rejected if any part of it is disallowed. It returns
+// errors.ErrLocalhostScanningDisabled or errors.ErrPrivateIPScanningDisabled.
+func ValidatePolicy(target string, policy Policy) error {
+	if policy.AllowLocalhost && policy.AllowPrivate {
+		return nil
+	}
+
+	var network *net.IPNet
+	switch ip := net.ParseIP(target); {
+	case ip != nil:
+		bits := 8 * len(ip)
+		if v4 := ip.To4(); v4 != nil {
+			ip, bits = v4, 32
+		}
+		network = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+	case strings.Contains(target, "/"):
+		_, parsed, err := net.ParseCIDR(target)
+		if err != nil {
+			return nil // malformed targets are reported by ValidateHost
+		}
+		network = parsed
+	default:
+		name := strings.ToLower(strings.TrimSuffix(target, "."))
+		if !policy.AllowLocalhost && (name == "localhost" || strings.HasSuffix(name, ".localhost")) {
+			return errors.ErrLocalhostScanningDisabled
+		}
+		return nil
+	}
+
+	if !policy.AllowLocalhost && overlapsAny(network, loopbackNetworks) {
+		return errors.ErrLocalhostScanningDisabled
+	}
+	if !policy.AllowPrivate && overlapsAny(network, privateNetworks) {
+		return errors.ErrPrivateIPScanningDisabled
+	}
+	return nil
+}
+
+// overlapsAny reports whether network shares any address with one of
+// ranges. Two CIDRs overlap exactly when one contains the other's base.
+func overlapsAny(network *net.IPNet, ranges []*net.IPNet) bool {
+	for _, r := range ranges {
+		if r.Contains(network.IP) || network.Contains(r.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
@@ -0,0 +1,49 @@
+package targets
+
+import (
+	stdErrors "errors"
+	"testing"
+
+	"github.com/lucchesi-sec/portscan/pkg/errors"
+)
+
+func TestValidatePolicy(t *testing.T) {
+	strict := Policy{}
+	tests := []struct {
+		name   string
+		target string
+		policy Policy
+		want   error
+	}{
+		{"public IPv4", "93.184.216.34", strict, nil},
+		{"hostname", "example.com", strict, nil},
+		{"loopback IPv4", "127.0.0.1", strict, errors.ErrLocalhostScanningDisabled},
+		{"loopback IPv6", "::1", strict, errors.ErrLocalhostScanningDisabled},
+		{"localhost name", "localhost", strict, errors.ErrLocalhostScanningDisabled},
+		{"localhost subdomain", "app.LOCALHOST.", strict, errors.ErrLocalhostScanningDisabled},
+		{"private IPv4", "192.168.1.10", strict, errors.ErrPrivateIPScanningDisabled},
+		{"link-local", "169.254.1.1", strict, errors.ErrPrivateIPScanningDisabled},
+		{"unique local IPv6", "fd00::1", strict, errors.ErrPrivateIPScanningDisabled},
+		{"private CIDR", "172.20.0.0/16", strict, errors.ErrPrivateIPScanningDisabled},
+		{"CIDR containing private", "10.0.0.0/7", strict, errors.ErrPrivateIPScanningDisabled},
+		{"public CIDR", "93.184.216.0/24", strict, nil},
+		{"private allowed", "10.1.2.3", Policy{AllowPrivate: true}, nil},
+		{"localhost allowed", "127.0.0.1", Policy{AllowLocalhost: true}, nil},
+		{"localhost still refused", "localhost", Policy{AllowPrivate: true}, errors.ErrLocalhostScanningDisabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePolicy(tt.target, tt.policy)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("ValidatePolicy(%q) = %v, want nil", tt.target, err)
+				}
+				return
+			}
+			if !stdErrors.Is(err, tt.want) {
+				t.Errorf("ValidatePolicy(%q) = %v, want %v", tt.target, err, tt.want)
+			}
+		})
+	}
+}
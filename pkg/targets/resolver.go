@@ -0,0 +1,279 @@
+package targets
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLookupTimeout bounds each hostname lookup when no timeout is set.
+	DefaultLookupTimeout = 5 * time.Second
+	// prefetchConcurrency caps the lookups Prefetch runs at once.
+	prefetchConcurrency = 32
+)
+
+// ResolverOptions configures a Resolver.
+type ResolverOptions struct {
+	// Server is the DNS server to query: "10.0.0.53" or "10.0.0.53:5353"
+	// for plain DNS, "tls://dns.example.com" for DNS over TLS (port 853),
+	// or "https://dns.example.com/dns-query" for DNS over HTTPS. Empty
+	// uses the system resolver.
+	Server string
+	// Timeout bounds each lookup. Defaults to DefaultLookupTimeout when
+	// zero or negative.
+	Timeout time.Duration
+}
+
+// Resolver looks up target hostnames through a configured DNS server and
+// caches the answers for its lifetime, so a hostname probed on many ports
+// is resolved once. It is safe for concurrent use.
+type Resolver struct {
+	resolver *net.Resolver
+	timeout  time.Duration
+
+	mu    sync.Mutex
+	cache map[string]*lookup // by lowercased hostname
+	ptrs  map[string]*lookup // reverse lookups, by address
+}
+
+// lookup is a cached or in-flight resolution of one hostname or address.
+type lookup struct {
+	done    chan struct{}
+	answers []string
+	err     error
+}
+
+// NewResolver returns a Resolver for opts, or an error if opts.Server is
+// not a valid DNS server.
+func NewResolver(opts ResolverOptions) (*Resolver, error) {
+	server, err := parseDNSServer(opts.Server)
+	if err != nil {
+		return nil, err
+	}
+	return newResolver(server, opts.Timeout), nil
+}
+
+func newResolver(server *dnsServer, timeout time.Duration) *Resolver {
+	if timeout <= 0 {
+		timeout = DefaultLookupTimeout
+	}
+	return &Resolver{
+		resolver: server.netResolver(),
+		timeout:  timeout,
+		cache:    make(map[string]*lookup),
+		ptrs:     make(map[string]*lookup),
+	}
+}
+
+// LookupHost returns the addresses of host, looking it up at most once
+// while callers share the answer, including a failed one. An IP address is
+// returned as it is.
+func (r *Resolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+	return r.cached(ctx, r.cache, strings.ToLower(host), func(ctx context.Context) ([]string, error) {
+		addrs, err := r.resolver.LookupHost(ctx, host)
+		if err == nil && len(addrs) == 0 {
+			err = &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		return addrs, err
+	})
+}
+
+// LookupAddr returns the names addr's PTR records point to, without the
+// trailing dot, cached like LookupHost.
+func (r *Resolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid address %q", addr)
+	}
+	return r.cached(ctx, r.ptrs, ip.String(), func(ctx context.Context) ([]string, error) {
+		names, err := r.resolver.LookupAddr(ctx, ip.String())
+		for i, name := range names {
+			names[i] = strings.TrimSuffix(name, ".")
+		}
+		return names, err
+	})
+}
+
+// cached returns the answer cached under key, waiting for a lookup in
+// flight, or runs resolve with the lookup timeout and caches its answer.
+func (r *Resolver) cached(ctx context.Context, cache map[string]*lookup, key string, resolve func(context.Context) ([]string, error)) ([]string, error) {
+	r.mu.Lock()
+	if l, ok := cache[key]; ok {
+		r.mu.Unlock()
+		select {
+		case <-l.done:
+			return l.answers, l.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	l := &lookup{done: make(chan struct{})}
+	cache[key] = l
+	r.mu.Unlock()
+
+	lookupCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	l.answers, l.err = resolve(lookupCtx)
+	cancel()
+	if ctx.Err() != nil {
+		// The caller gave up rather than the lookup failing; let the next
+		// caller try again.
+		r.mu.Lock()
+		delete(cache, key)
+		r.mu.Unlock()
+	}
+	close(l.done)
+	return l.answers, l.err
+}
+
+// Prefetch looks up the hostnames among hosts concurrently, so a long
+// list of names is resolved up front instead of one by one as probing
+// reaches each host. Failures are cached and reported when a host is
+// probed.
+func (r *Resolver) Prefetch(ctx context.Context, hosts []string) {
+	names := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		if net.ParseIP(host) == nil {
+			names = append(names, host)
+		}
+	}
+	r.prefetch(ctx, names, func(host string) { _, _ = r.LookupHost(ctx, host) })
+}
+
+// PrefetchAddrs runs the reverse lookups of addrs concurrently, as
+// Prefetch does for hostnames.
+func (r *Resolver) PrefetchAddrs(ctx context.Context, addrs []string) {
+	r.prefetch(ctx, addrs, func(addr string) { _, _ = r.LookupAddr(ctx, addr) })
+}
+
+// prefetch calls fetch for each key, at most prefetchConcurrency at once.
+func (r *Resolver) prefetch(ctx context.Context, keys []string, fetch func(string)) {
+	sem := make(chan struct{}, prefetchConcurrency)
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fetch(key)
+		}()
+	}
+	wg.Wait()
+}
+
+// ValidateDNSServer checks a DNS server in any form ResolverOptions.Server
+// accepts.
+func ValidateDNSServer(server string) error {
+	_, err := parseDNSServer(server)
+	return err
+}
+
+// dnsServer is a parsed ResolverOptions.Server.
+type dnsServer struct {
+	scheme string // "dns", "tls", or "https"
+	addr   string // host:port, or the DNS over HTTPS URL
+	name   string // TLS server name
+
+	tlsConfig *tls.Config  // DNS over TLS; nil uses the system roots
+	client    *http.Client // DNS over HTTPS; nil uses a default client
+}
+
+// parseDNSServer parses a DNS server, returning nil for an empty one.
+func parseDNSServer(server string) (*dnsServer, error) {
+	server = strings.TrimSpace(server)
+	switch {
+	case server == "":
+		return nil, nil
+	case strings.HasPrefix(server, "https://"):
+		u, err := url.Parse(server)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid DNS over HTTPS server %q: want https://host/path", server)
+		}
+		return &dnsServer{scheme: "https", addr: server}, nil
+	case strings.HasPrefix(server, "tls://"):
+		addr, host, err := withDefaultPort(strings.TrimPrefix(server, "tls://"), "853")
+		if err != nil {
+			return nil, fmt.Errorf("invalid DNS over TLS server %q: %w", server, err)
+		}
+		return &dnsServer{scheme: "tls", addr: addr, name: host}, nil
+	case strings.Contains(server, "://"):
+		return nil, fmt.Errorf("invalid DNS server %q: use host[:port], tls://host[:port], or https://host/path", server)
+	default:
+		addr, host, err := withDefaultPort(server, "53")
+		if err != nil {
+			return nil, fmt.Errorf("invalid DNS server %q: %w", server, err)
+		}
+		return &dnsServer{scheme: "dns", addr: addr, name: host}, nil
+	}
+}
+
+// withDefaultPort returns hostport with port added when it has none, and
+// its host.
+func withDefaultPort(hostport, port string) (string, string, error) {
+	host, p, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host, p = strings.Trim(hostport, "[]"), port
+	}
+	if host == "" {
+		return "", "", fmt.Errorf("missing host")
+	}
+	if n, err := strconv.Atoi(p); err != nil || n < 1 || n > 65535 {
+		return "", "", fmt.Errorf("invalid port %q", p)
+	}
+	if net.ParseIP(host) == nil {
+		if err := validateHostname(host); err != nil {
+			return "", "", err
+		}
+	}
+	return net.JoinHostPort(host, p), host, nil
+}
+
+// netResolver returns a resolver sending every query to the server; a nil
+// server is the system resolver.
+func (s *dnsServer) netResolver() *net.Resolver {
+	if s == nil {
+		return net.DefaultResolver
+	}
+	var dial func(ctx context.Context, network, address string) (net.Conn, error)
+	switch s.scheme {
+	case "tls":
+		config := s.tlsConfig
+		if config == nil {
+			config = &tls.Config{ServerName: s.name, MinVersion: tls.VersionTLS12}
+		}
+		dialer := &tls.Dialer{Config: config}
+		dial = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "tcp", s.addr)
+		}
+	case "https":
+		client := s.client
+		if client == nil {
+			client = &http.Client{}
+		}
+		dial = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return newDoHConn(ctx, client, s.addr), nil
+		}
+	default:
+		var dialer net.Dialer
+		dial = func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, s.addr)
+		}
+	}
+	return &net.Resolver{PreferGo: true, Dial: dial}
+}
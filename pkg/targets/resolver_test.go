@@ -0,0 +1,313 @@
+package targets
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testRecords are the A records served by the test DNS servers.
+var testRecords = map[string]string{
+	"web.portscan.test.": "10.0.0.5",
+	"db.portscan.test.":  "10.0.0.6",
+}
+
+// testPTRs are the PTR records served by the test DNS servers.
+var testPTRs = map[string]string{
+	"5.0.0.10.in-addr.arpa.": "web.portscan.test.",
+}
+
+// dnsAnswer answers a DNS query from testRecords and testPTRs: an A or PTR
+// record for known names, no records for other types, and NXDOMAIN for
+// unknown names.
+func dnsAnswer(query []byte) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+	// Walk the question name.
+	var name strings.Builder
+	i := 12
+	for i < len(query) && query[i] != 0 {
+		n := int(query[i])
+		if i+1+n > len(query) {
+			return nil
+		}
+		name.Write(query[i+1 : i+1+n])
+		name.WriteByte('.')
+		i += 1 + n
+	}
+	if i+5 > len(query) {
+		return nil
+	}
+	qtype := binary.BigEndian.Uint16(query[i+1:])
+	question := query[12 : i+5]
+
+	resp := append([]byte{}, query[:2]...) // ID
+	flags := uint16(0x8180)                // QR, RD, RA
+	ip, known := testRecords[strings.ToLower(name.String())]
+	ptr, isPTR := testPTRs[strings.ToLower(name.String())]
+	if !known && !isPTR {
+		flags |= 3 // NXDOMAIN
+	}
+	answers := uint16(0)
+	if known && qtype == 1 || isPTR && qtype == 12 {
+		answers = 1
+	}
+	resp = binary.BigEndian.AppendUint16(resp, flags)
+	resp = binary.BigEndian.AppendUint16(resp, 1)
+	resp = binary.BigEndian.AppendUint16(resp, answers)
+	resp = append(resp, 0, 0, 0, 0)
+	resp = append(resp, question...)
+	switch {
+	case answers == 1 && isPTR:
+		var rdata []byte
+		for _, label := range strings.Split(strings.TrimSuffix(ptr, "."), ".") {
+			rdata = append(append(rdata, byte(len(label))), label...)
+		}
+		rdata = append(rdata, 0)
+		resp = append(resp, 0xc0, 12, 0, 12, 0, 1, 0, 0, 0, 60)
+		resp = binary.BigEndian.AppendUint16(resp, uint16(len(rdata)))
+		resp = append(resp, rdata...)
+	case answers == 1:
+		resp = append(resp, 0xc0, 12, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4)
+		resp = append(resp, net.ParseIP(ip).To4()...)
+	}
+	return resp
+}
+
+// serveUDP runs a plain DNS server and counts the queries it answers.
+func serveUDP(t *testing.T, queries *atomic.Int32) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { pc.Close() })
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			queries.Add(1)
+			_, _ = pc.WriteTo(dnsAnswer(buf[:n]), addr)
+		}
+	}()
+	return pc.LocalAddr().String()
+}
+
+// serveStream answers length-prefixed DNS queries on a connection.
+func serveStream(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var size [2]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return
+		}
+		query := make([]byte, binary.BigEndian.Uint16(size[:]))
+		if _, err := io.ReadFull(conn, query); err != nil {
+			return
+		}
+		answer := dnsAnswer(query)
+		_, _ = conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(answer))), answer...))
+	}
+}
+
+func TestParseDNSServer(t *testing.T) {
+	tests := []struct {
+		input  string
+		scheme string
+		addr   string
+		ok     bool
+	}{
+		{"10.0.0.53", "dns", "10.0.0.53:53", true},
+		{"10.0.0.53:5353", "dns", "10.0.0.53:5353", true},
+		{"fd00::53", "dns", "[fd00::53]:53", true},
+		{"[fd00::53]:5353", "dns", "[fd00::53]:5353", true},
+		{"dns.corp.example", "dns", "dns.corp.example:53", true},
+		{"tls://1.1.1.1", "tls", "1.1.1.1:853", true},
+		{"tls://dns.example.com:8853", "tls", "dns.example.com:8853", true},
+		{"https://dns.example.com/dns-query", "https", "https://dns.example.com/dns-query", true},
+		{"10.0.0.53:0", "", "", false},
+		{"https:///dns-query", "", "", false},
+		{"quic://dns.example.com", "", "", false},
+		{"tls://", "", "", false},
+		{"bad_host!", "", "", false},
+	}
+	for _, tt := range tests {
+		server, err := parseDNSServer(tt.input)
+		if (err == nil) != tt.ok {
+			t.Errorf("parseDNSServer(%q) error = %v, want ok %v", tt.input, err, tt.ok)
+			continue
+		}
+		if tt.ok && (server.scheme != tt.scheme || server.addr != tt.addr) {
+			t.Errorf("parseDNSServer(%q) = %s %s, want %s %s", tt.input, server.scheme, server.addr, tt.scheme, tt.addr)
+		}
+	}
+	if server, err := parseDNSServer(""); server != nil || err != nil {
+		t.Errorf("parseDNSServer(\"\") = %v, %v, want the system resolver", server, err)
+	}
+}
+
+func TestResolverCustomServer(t *testing.T) {
+	var queries atomic.Int32
+	r, err := NewResolver(ResolverOptions{Server: serveUDP(t, &queries)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	addrs, err := r.LookupHost(ctx, "web.portscan.test")
+	if err != nil || len(addrs) != 1 || addrs[0] != "10.0.0.5" {
+		t.Fatalf("LookupHost() = %v, %v, want [10.0.0.5]", addrs, err)
+	}
+	_, err = r.LookupHost(ctx, "missing.portscan.test")
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+		t.Fatalf("LookupHost(missing) error = %v, want not found", err)
+	}
+
+	// Answers, including failures, come from the cache.
+	sent := queries.Load()
+	if _, err := r.LookupHost(ctx, "WEB.portscan.test"); err != nil {
+		t.Errorf("cached LookupHost() = %v", err)
+	}
+	if _, err := r.LookupHost(ctx, "missing.portscan.test"); err == nil {
+		t.Error("cached failure should still fail")
+	}
+	if queries.Load() != sent {
+		t.Errorf("cached lookups sent %d more queries", queries.Load()-sent)
+	}
+
+	if addrs, _ := r.LookupHost(ctx, "192.0.2.1"); len(addrs) != 1 || addrs[0] != "192.0.2.1" {
+		t.Errorf("LookupHost(ip) = %v", addrs)
+	}
+}
+
+func TestResolverPrefetch(t *testing.T) {
+	var queries atomic.Int32
+	r, err := NewResolver(ResolverOptions{Server: serveUDP(t, &queries)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Prefetch(context.Background(), []string{"web.portscan.test", "db.portscan.test", "10.0.0.1"})
+	sent := queries.Load()
+	if sent == 0 {
+		t.Fatal("Prefetch sent no queries")
+	}
+	if addrs, err := r.LookupHost(context.Background(), "db.portscan.test"); err != nil || addrs[0] != "10.0.0.6" {
+		t.Errorf("LookupHost() = %v, %v", addrs, err)
+	}
+	if queries.Load() != sent {
+		t.Error("prefetched hosts should be answered from the cache")
+	}
+}
+
+func TestResolverLookupAddr(t *testing.T) {
+	var queries atomic.Int32
+	r, err := NewResolver(ResolverOptions{Server: serveUDP(t, &queries)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	r.PrefetchAddrs(ctx, []string{"10.0.0.5", "10.0.0.7"})
+	sent := queries.Load()
+	names, err := r.LookupAddr(ctx, "10.0.0.5")
+	if err != nil || len(names) != 1 || names[0] != "web.portscan.test" {
+		t.Fatalf("LookupAddr() = %v, %v, want [web.portscan.test]", names, err)
+	}
+	if _, err := r.LookupAddr(ctx, "10.0.0.7"); err == nil {
+		t.Error("LookupAddr() of an address without a PTR record should fail")
+	}
+	if queries.Load() != sent {
+		t.Error("prefetched addresses should be answered from the cache")
+	}
+	if _, err := r.LookupAddr(ctx, "web.portscan.test"); err == nil {
+		t.Error("LookupAddr() of a hostname should fail")
+	}
+}
+
+func TestResolverTimeout(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer pc.Close() // never answers
+
+	r, err := NewResolver(ResolverOptions{Server: pc.LocalAddr().String(), Timeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err := r.LookupHost(context.Background(), "web.portscan.test"); err == nil {
+		t.Fatal("LookupHost() should time out")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("lookup took %v, want about the 100ms timeout", elapsed)
+	}
+}
+
+func TestResolverDNSOverHTTPS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/dns-message" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		query, _ := io.ReadAll(req.Body)
+		w.Header().Set("Content-Type", "application/dns-message")
+		_, _ = w.Write(dnsAnswer(query))
+	}))
+	defer ts.Close()
+
+	server, err := parseDNSServer(ts.URL + "/dns-query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.client = ts.Client()
+	addrs, err := newResolver(server, time.Second).LookupHost(context.Background(), "web.portscan.test")
+	if err != nil || len(addrs) != 1 || addrs[0] != "10.0.0.5" {
+		t.Fatalf("LookupHost() = %v, %v, want [10.0.0.5]", addrs, err)
+	}
+}
+
+func TestResolverDNSOverTLS(t *testing.T) {
+	// Borrow the test server's certificate, valid for 127.0.0.1.
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	defer ts.Close()
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: ts.TLS.Certificates})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveStream(conn)
+		}
+	}()
+
+	server, err := parseDNSServer("tls://" + ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := ts.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+	server.tlsConfig = &tls.Config{RootCAs: roots, ServerName: server.name}
+	addrs, err := newResolver(server, time.Second).LookupHost(context.Background(), "db.portscan.test")
+	if err != nil || len(addrs) != 1 || addrs[0] != "10.0.0.6" {
+		t.Fatalf("LookupHost() = %v, %v, want [10.0.0.6]", addrs, err)
+	}
+}
@@ -0,0 +1,198 @@
+package targets
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Scope is an allowlist of networks and domains a scan may touch, such as
+// the agreed scope of a penetration test.
+type Scope struct {
+	Networks []*net.IPNet
+	// Domains are exact names, or "*.example.com" for any subdomain.
+	Domains []string
+}
+
+// scopeFile is the on-disk YAML representation of a scope.
+type scopeFile struct {
+	Networks []string `yaml:"networks"` // CIDRs or single addresses
+	Domains  []string `yaml:"domains"`
+}
+
+// LoadScope reads a YAML scope file. When keyPath is set, the file must
+// carry a valid Ed25519 signature in path+".sig" from the public key in
+// keyPath, so the scope cannot be edited without the signing key.
+func LoadScope(path, keyPath string) (*Scope, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if keyPath != "" {
+		if err := verifyScopeSignature(data, path+".sig", keyPath); err != nil {
+			return nil, err
+		}
+	}
+	return ParseScope(data)
+}
+
+// ParseScope parses a YAML scope. Each network is a CIDR or a single IP
+// address; each domain is a hostname, optionally prefixed with "*.".
+func ParseScope(data []byte) (*Scope, error) {
+	var file scopeFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("parse scope: %w", err)
+	}
+
+	scope := &Scope{}
+	for _, entry := range file.Networks {
+		network, err := parseScopeNetwork(strings.TrimSpace(entry))
+		if err != nil {
+			return nil, err
+		}
+		scope.Networks = append(scope.Networks, network)
+	}
+	for _, entry := range file.Domains {
+		domain := normalizeScopeDomain(entry)
+		if err := validateHostname(strings.TrimPrefix(domain, "*.")); err != nil {
+			return nil, fmt.Errorf("invalid scope domain %q: %w", entry, err)
+		}
+		scope.Domains = append(scope.Domains, domain)
+	}
+	if len(scope.Networks) == 0 && len(scope.Domains) == 0 {
+		return nil, errors.New("scope lists no networks or domains")
+	}
+	return scope, nil
+}
+
+// ParseMatch parses one pattern, a network (CIDR or IP address) or a
+// domain (a hostname, optionally prefixed with "*."), into a scope that
+// allows only what the pattern matches.
+func ParseMatch(pattern string) (*Scope, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return nil, errors.New("empty match pattern")
+	}
+	if net.ParseIP(pattern) != nil || strings.Contains(pattern, "/") {
+		network, err := parseScopeNetwork(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q: want a CIDR or IP address", pattern)
+		}
+		return &Scope{Networks: []*net.IPNet{network}}, nil
+	}
+	domain := normalizeScopeDomain(pattern)
+	if err := validateHostname(strings.TrimPrefix(domain, "*.")); err != nil {
+		return nil, fmt.Errorf("invalid domain %q: %w", pattern, err)
+	}
+	return &Scope{Domains: []string{domain}}, nil
+}
+
+func normalizeScopeDomain(entry string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(entry), "."))
+}
+
+func parseScopeNetwork(entry string) (*net.IPNet, error) {
+	if ip := net.ParseIP(entry); ip != nil {
+		bits := 128
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 32
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, network, err := net.ParseCIDR(entry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scope network %q: want a CIDR or IP address", entry)
+	}
+	return network, nil
+}
+
+// Allows reports whether host is in scope: an IP address inside one of the
+// networks, or a hostname matching one of the domains. hostname is the
+// name an address was resolved from, if any; a name in scope covers every
+// address it resolves to.
+func (s *Scope) Allows(host, hostname string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		for _, network := range s.Networks {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+		return hostname != "" && s.allowsName(hostname)
+	}
+	return s.allowsName(host)
+}
+
+func (s *Scope) allowsName(name string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	for _, domain := range s.Domains {
+		if suffix, ok := strings.CutPrefix(domain, "*"); ok {
+			if strings.HasSuffix(name, suffix) {
+				return true
+			}
+			continue
+		}
+		if name == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyScopeSignature checks the detached Ed25519 signature in sigPath
+// over data. The signature may be raw (as written by openssl pkeyutl) or
+// base64; the key is a PEM public key or a base64 raw 32-byte key.
+func verifyScopeSignature(data []byte, sigPath, keyPath string) error {
+	key, err := readScopeKey(keyPath)
+	if err != nil {
+		return err
+	}
+	sig, err := os.ReadFile(sigPath)
+	if err != nil {
+		return fmt.Errorf("scope signature: %w", err)
+	}
+	if len(sig) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+		if err != nil || len(decoded) != ed25519.SignatureSize {
+			return fmt.Errorf("scope signature %s is not an Ed25519 signature", sigPath)
+		}
+		sig = decoded
+	}
+	if !ed25519.Verify(key, data, sig) {
+		return fmt.Errorf("scope signature %s does not match the scope file", sigPath)
+	}
+	return nil
+}
+
+func readScopeKey(path string) (ed25519.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("scope key: %w", err)
+	}
+	if block, _ := pem.Decode(data); block != nil {
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("scope key %s: %w", path, err)
+		}
+		key, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("scope key %s is not an Ed25519 public key", path)
+		}
+		return key, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("scope key %s is not an Ed25519 public key", path)
+	}
+	return ed25519.PublicKey(raw), nil
+}
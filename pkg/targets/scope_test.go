@@ -0,0 +1,148 @@
+package targets
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testScope = `networks:
+  - 10.20.0.0/16
+  - 192.0.2.10
+  - 2001:db8::/32
+domains:
+  - example.com
+  - "*.corp.example.net"
+`
+
+func TestParseScopeAllows(t *testing.T) {
+	scope, err := ParseScope([]byte(testScope))
+	if err != nil {
+		t.Fatalf("ParseScope: %v", err)
+	}
+
+	tests := []struct {
+		host, hostname string
+		want           bool
+	}{
+		{"10.20.3.4", "", true},
+		{"10.21.0.1", "", false},
+		{"192.0.2.10", "", true},
+		{"192.0.2.11", "", false},
+		{"2001:db8::1", "", true},
+		{"example.com", "", true},
+		{"EXAMPLE.com.", "", true},
+		{"www.example.com", "", false},
+		{"db.corp.example.net", "", true},
+		{"corp.example.net", "", false},
+		{"evilcorp.example.net", "", false},
+		{"203.0.113.5", "example.com", true},
+		{"203.0.113.5", "other.org", false},
+	}
+	for _, tt := range tests {
+		if got := scope.Allows(tt.host, tt.hostname); got != tt.want {
+			t.Errorf("Allows(%q, %q) = %v, want %v", tt.host, tt.hostname, got, tt.want)
+		}
+	}
+}
+
+func TestParseScopeInvalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"empty":         "",
+		"no entries":    "networks: []\n",
+		"bad network":   "networks: [10.0.0.0/33]\n",
+		"bad domain":    "domains: [\"exa mple.com\"]\n",
+		"unknown field": "network: [10.0.0.0/8]\n",
+	} {
+		if _, err := ParseScope([]byte(data)); err == nil {
+			t.Errorf("%s: ParseScope should fail", name)
+		}
+	}
+}
+
+func TestLoadScopeSigned(t *testing.T) {
+	dir := t.TempDir()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scopePath := filepath.Join(dir, "scope.yaml")
+	pemKey := filepath.Join(dir, "scope.pub")
+	rawKey := filepath.Join(dir, "scope.b64")
+	writeFile(t, scopePath, testScope)
+	writeFile(t, pemKey, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+	writeFile(t, rawKey, base64.StdEncoding.EncodeToString(pub))
+
+	if _, err := LoadScope(scopePath, pemKey); err == nil {
+		t.Fatal("a missing signature should be rejected")
+	}
+
+	// Raw signatures as written by openssl pkeyutl, and base64 ones.
+	writeFile(t, scopePath+".sig", string(ed25519.Sign(priv, []byte(testScope))))
+	if _, err := LoadScope(scopePath, pemKey); err != nil {
+		t.Fatalf("raw signature with PEM key: %v", err)
+	}
+	writeFile(t, scopePath+".sig", base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(testScope)))+"\n")
+	if _, err := LoadScope(scopePath, rawKey); err != nil {
+		t.Fatalf("base64 signature with raw key: %v", err)
+	}
+
+	writeFile(t, scopePath, strings.Replace(testScope, "networks:\n", "networks:\n  - 0.0.0.0/0\n", 1))
+	_, err = LoadScope(scopePath, pemKey)
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("an edited scope should fail verification, got %v", err)
+	}
+
+	if _, err := LoadScope(scopePath, ""); err != nil {
+		t.Errorf("without a key the signature is not checked: %v", err)
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseMatch(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		host     string
+		hostname string
+		want     bool
+	}{
+		{"10.2.0.0/16", "10.2.40.7", "", true},
+		{"10.2.0.0/16", "10.3.0.1", "", false},
+		{"192.0.2.10", "192.0.2.10", "", true},
+		{"*.corp.example.net", "db.corp.example.net", "", true},
+		{"*.corp.example.net", "10.9.9.9", "db.corp.example.net", true},
+		{"app.example.com", "api.example.com", "", false},
+	}
+	for _, tt := range tests {
+		match, err := ParseMatch(tt.pattern)
+		if err != nil {
+			t.Fatalf("ParseMatch(%q): %v", tt.pattern, err)
+		}
+		if got := match.Allows(tt.host, tt.hostname); got != tt.want {
+			t.Errorf("ParseMatch(%q).Allows(%q, %q) = %v; want %v", tt.pattern, tt.host, tt.hostname, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "10.2.0.0/33", "bad_host!"} {
+		if _, err := ParseMatch(bad); err == nil {
+			t.Errorf("ParseMatch(%q) should fail", bad)
+		}
+	}
+}
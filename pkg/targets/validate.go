@@ -3,18 +3,16 @@ package targets
 import (
 	"fmt"
 	"net"
-	"strconv"
 	"strings"
+
+	"github.com/lucchesi-sec/portscan/pkg/parser"
 )
 
 const (
-	minPort         = 1
-	maxPort         = 65535
-	minRate         = 1
-	maxRate         = 15000 // Maximum safe rate in pps
-	minTimeout      = 1
-	maxTimeout      = 60000
-	maxPortsInRange = 65535
+	minRate    = 1
+	maxRate    = 15000 // Maximum safe rate in pps
+	minTimeout = 1
+	maxTimeout = 60000
 )
 
 // ValidateHost validates a host string (IP address, hostname, or CIDR).
@@ -91,7 +89,8 @@ func looksLikeIP(s string) bool {
 }
 
 // ValidatePortRange validates a port range specification string.
-// Accepts formats: "80", "80,443", "1-1024", "80,443,8000-9000"
+// Accepts the port expressions of parser.ParsePorts, such as "80,443",
+// "1-1024,!22", "1000-2000/2" or "ssh,https".
 func ValidatePortRange(portSpec string) error {
 	if portSpec == "" {
 		return fmt.Errorf("port specification cannot be empty")
@@ -101,29 +100,8 @@ func ValidatePortRange(portSpec string) error {
 		return fmt.Errorf("port specification too long (max 1000 characters)")
 	}
 
-	tokens := strings.Split(portSpec, ",")
-	if len(tokens) == 0 {
-		return fmt.Errorf("port specification contains no valid ports")
-	}
-
-	validTokenCount := 0
-	for _, token := range tokens {
-		token = strings.TrimSpace(token)
-		if token == "" {
-			continue
-		}
-
-		if err := validatePortToken(token); err != nil {
-			return err
-		}
-		validTokenCount++
-	}
-
-	if validTokenCount == 0 {
-		return fmt.Errorf("port specification contains no valid ports")
-	}
-
-	return nil
+	_, err := parser.ParsePorts(portSpec)
+	return err
 }
 
 // ValidateRateLimit validates that the rate limit is within acceptable bounds.
@@ -226,62 +204,3 @@ func ValidateUDPWorkerRatio(ratio float64) error {
 	}
 	return nil
 }
-
-// validatePortToken validates a single port or port range token.
-func validatePortToken(token string) error {
-	if strings.Contains(token, "-") {
-		return validateRangeToken(token)
-	}
-	return validateSinglePort(token)
-}
-
-// validateSinglePort validates a single port number.
-func validateSinglePort(portStr string) error {
-	port, err := strconv.Atoi(portStr)
-	if err != nil {
-		return fmt.Errorf("invalid port number '%s': must be numeric", portStr)
-	}
-
-	if port < minPort || port > maxPort {
-		return fmt.Errorf("port %d out of range (valid: %d-%d)", port, minPort, maxPort)
-	}
-
-	return nil
-}
-
-// validateRangeToken validates a port range like "80-443".
-func validateRangeToken(token string) error {
-	parts := strings.Split(token, "-")
-	if len(parts) != 2 {
-		return fmt.Errorf("invalid port range '%s': must be in format 'start-end'", token)
-	}
-
-	start, err := strconv.Atoi(strings.TrimSpace(parts[0]))
-	if err != nil {
-		return fmt.Errorf("invalid start port in range '%s': must be numeric", token)
-	}
-
-	end, err := strconv.Atoi(strings.TrimSpace(parts[1]))
-	if err != nil {
-		return fmt.Errorf("invalid end port in range '%s': must be numeric", token)
-	}
-
-	if start < minPort || start > maxPort {
-		return fmt.Errorf("start port %d out of range (valid: %d-%d)", start, minPort, maxPort)
-	}
-
-	if end < minPort || end > maxPort {
-		return fmt.Errorf("end port %d out of range (valid: %d-%d)", end, minPort, maxPort)
-	}
-
-	if start > end {
-		return fmt.Errorf("invalid port range '%s': start port %d > end port %d", token, start, end)
-	}
-
-	rangeSize := end - start + 1
-	if rangeSize > maxPortsInRange {
-		return fmt.Errorf("port range '%s' too large (%d ports)", token, rangeSize)
-	}
-
-	return nil
-}
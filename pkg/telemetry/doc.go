@@ -0,0 +1,33 @@
+// Package telemetry reports a scan to an OpenTelemetry collector.
+//
+// A Recorder observes the scan's event stream and records, as events
+// arrive, with the OpenTelemetry SDK:
+//
+//   - a "scan" span covering the whole run, with an "export" child span
+//     for the output stage and a "host" child span from each host's first
+//     probe to its last
+//   - a portscan.probe.duration histogram of probe latency in
+//     milliseconds, by protocol
+//   - portscan.probes and portscan.probe.errors counters, by protocol and,
+//     for probes, port state
+//
+// Spans are exported in batches as they end and metrics periodically,
+// with OTLP over HTTP to /v1/traces and /v1/metrics under the endpoint, so
+// any collector listening on the OTLP/HTTP port (4318 by default) accepts
+// them. Flush sends what is left:
+//
+//	rec, err := telemetry.NewRecorder(telemetry.Options{Endpoint: "http://otel-collector:4318"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for event := range events {
+//	    rec.Observe(event)
+//	}
+//	err = rec.Flush(ctx)
+//
+// The exporters read the standard OTEL_EXPORTER_OTLP_* variables, such as
+// OTEL_EXPORTER_OTLP_HEADERS for collector credentials,
+// OTEL_EXPORTER_OTLP_CERTIFICATE and OTEL_EXPORTER_OTLP_COMPRESSION, and
+// OTEL_EXPORTER_OTLP_ENDPOINT when Options.Endpoint is empty. Options
+// fields that are set take precedence.
+package telemetry
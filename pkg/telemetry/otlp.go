@@ -0,0 +1,139 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startProviders builds the tracer and meter providers, exporting over
+// OTLP/HTTP to endpoint's /v1/traces and /v1/metrics. The exporters read
+// any other OTEL_EXPORTER_OTLP_* settings from the environment.
+func (r *Recorder) startProviders(opts Options, endpoint string) error {
+	ctx := context.Background()
+	res, err := resource.New(ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(attribute.String("service.name", opts.ServiceName)),
+		resource.WithFromEnv())
+	if err != nil {
+		return fmt.Errorf("otel resource: %w", err)
+	}
+
+	var traceOpts []otlptracehttp.Option
+	var metricOpts []otlpmetrichttp.Option
+	if endpoint != "" {
+		traceOpts = append(traceOpts, otlptracehttp.WithEndpointURL(endpoint+"/v1/traces"))
+		metricOpts = append(metricOpts, otlpmetrichttp.WithEndpointURL(endpoint+"/v1/metrics"))
+	}
+	if opts.Headers != nil {
+		traceOpts = append(traceOpts, otlptracehttp.WithHeaders(opts.Headers))
+		metricOpts = append(metricOpts, otlpmetrichttp.WithHeaders(opts.Headers))
+	}
+	if opts.Client != nil {
+		traceOpts = append(traceOpts, otlptracehttp.WithHTTPClient(opts.Client))
+		metricOpts = append(metricOpts, otlpmetrichttp.WithHTTPClient(opts.Client))
+	}
+
+	spanExporter, err := otlptracehttp.New(ctx, traceOpts...)
+	if err != nil {
+		return fmt.Errorf("otlp traces: %w", err)
+	}
+	metricExporter, err := otlpmetrichttp.New(ctx, metricOpts...)
+	if err != nil {
+		return fmt.Errorf("otlp metrics: %w", err)
+	}
+
+	// The queue holds every host span, so a burst of hosts finishing
+	// together is not dropped before the next batch goes out.
+	r.traces = sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithBatcher(spanRecorder{spanExporter, &r.traceErr}, sdktrace.WithMaxQueueSize(HostSpanLimit)))
+	r.metrics = sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricRecorder{metricExporter, &r.metricErr})))
+	return nil
+}
+
+// Flush ends the scan span, and any host span still open, then sends
+// every span and the metrics not yet exported to the collector and shuts
+// the exporters down. The Recorder records nothing afterwards. Each
+// signal's first export error is returned; the errors are joined.
+func (r *Recorder) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	now := time.Now()
+	for host, open := range r.open {
+		open.end(now, attribute.Bool("portscan.host.finished", false))
+		delete(r.open, host)
+	}
+	if r.dropped > 0 {
+		r.scan.SetAttributes(attribute.Int("portscan.host_spans.dropped", r.dropped))
+	}
+	r.scan.End(trace.WithTimestamp(now))
+	r.mu.Unlock()
+
+	traceErr := r.traces.Shutdown(ctx)
+	metricErr := r.metrics.Shutdown(ctx)
+	return errors.Join(r.traceErr.first(traceErr), r.metricErr.first(metricErr))
+}
+
+// exportError keeps the first error an exporter returned. The SDK only
+// hands errors from background exports to the global error handler.
+type exportError struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (e *exportError) record(err error) {
+	if err == nil {
+		return
+	}
+	e.mu.Lock()
+	if e.err == nil {
+		e.err = err
+	}
+	e.mu.Unlock()
+}
+
+// first returns the recorded error, or fallback if there was none.
+func (e *exportError) first(fallback error) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err != nil {
+		return e.err
+	}
+	return fallback
+}
+
+// spanRecorder and metricRecorder record their exporter's errors.
+type spanRecorder struct {
+	sdktrace.SpanExporter
+	errs *exportError
+}
+
+func (e spanRecorder) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.errs.record(err)
+	return err
+}
+
+type metricRecorder struct {
+	sdkmetric.Exporter
+	errs *exportError
+}
+
+func (e metricRecorder) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	err := e.Exporter.Export(ctx, rm)
+	e.errs.record(err)
+	return err
+}
@@ -0,0 +1,257 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Telemetry defaults.
+const (
+	DefaultServiceName = "portscan"
+	DefaultTimeout     = 10 * time.Second
+
+	// HostSpanLimit caps the host spans a Recorder starts; hosts past it
+	// are still counted in metrics and noted on the scan span.
+	HostSpanLimit = 10000
+
+	instrumentationScope = "github.com/lucchesi-sec/portscan"
+)
+
+// latencyBounds are the probe duration histogram's bucket bounds in ms.
+var latencyBounds = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// Options configures a Recorder.
+type Options struct {
+	// Endpoint is the collector's OTLP/HTTP base URL, e.g.
+	// http://localhost:4318. A bare host:port is taken as http. Empty
+	// leaves it to OTEL_EXPORTER_OTLP_ENDPOINT and the per-signal
+	// variables, or localhost:4318.
+	Endpoint string
+	// ServiceName is the service.name resource attribute; defaults to
+	// DefaultServiceName. OTEL_SERVICE_NAME overrides it.
+	ServiceName string
+	// Headers are sent with every request. Nil reads them from
+	// OTEL_EXPORTER_OTLP_HEADERS.
+	Headers map[string]string
+	// Attributes are added to the scan span, such as the targets.
+	Attributes map[string]string
+	// Client sends the requests. Nil uses the exporters' own client,
+	// which honors OTEL_EXPORTER_OTLP_CERTIFICATE and _TIMEOUT.
+	Client *http.Client
+}
+
+// Recorder turns a scan's events into spans and metrics as they arrive.
+// It is safe for concurrent use.
+type Recorder struct {
+	traces  *sdktrace.TracerProvider
+	metrics *sdkmetric.MeterProvider
+	tracer  trace.Tracer
+
+	scan    trace.Span
+	scanCtx context.Context
+
+	latency metric.Float64Histogram // by protocol
+	probes  metric.Int64Counter     // by protocol and state
+	errors  metric.Int64Counter     // by protocol
+
+	mu      sync.Mutex
+	open    map[string]*hostSpan // hosts still being probed
+	started int                  // host spans started
+	dropped int
+
+	traceErr, metricErr exportError
+}
+
+// hostSpan is a host span still open, with the streams probing the host
+// and its probe counts for the span's attributes.
+type hostSpan struct {
+	span                 trace.Span
+	streams              int
+	probes, open, errors int64
+}
+
+// NewRecorder returns a Recorder whose scan span starts now.
+func NewRecorder(opts Options) (*Recorder, error) {
+	endpoint, err := otlpEndpoint(opts.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if opts.ServiceName == "" {
+		opts.ServiceName = DefaultServiceName
+	}
+	r := &Recorder{open: make(map[string]*hostSpan)}
+	if err := r.startProviders(opts, endpoint); err != nil {
+		return nil, err
+	}
+
+	r.tracer = r.traces.Tracer(instrumentationScope)
+	meter := r.metrics.Meter(instrumentationScope)
+	if r.latency, err = meter.Float64Histogram("portscan.probe.duration",
+		metric.WithDescription("Probe latency"),
+		metric.WithUnit("ms"),
+		metric.WithExplicitBucketBoundaries(latencyBounds...)); err != nil {
+		return nil, err
+	}
+	if r.probes, err = meter.Int64Counter("portscan.probes",
+		metric.WithDescription("Probes completed"),
+		metric.WithUnit("{probe}")); err != nil {
+		return nil, err
+	}
+	if r.errors, err = meter.Int64Counter("portscan.probe.errors",
+		metric.WithDescription("Probes that failed without a result"),
+		metric.WithUnit("{error}")); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(opts.Attributes))
+	for k := range opts.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]attribute.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, attribute.String(k, opts.Attributes[k]))
+	}
+	r.scanCtx, r.scan = r.tracer.Start(context.Background(), "scan", trace.WithAttributes(attrs...))
+	return r, nil
+}
+
+// otlpEndpoint validates a collector URL and strips any trailing slash.
+// Empty stays empty, for the exporters to read from the environment.
+func otlpEndpoint(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid otel endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return "", fmt.Errorf("invalid otel endpoint %q: want http(s)://host:port", raw)
+	}
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+// Observe records what event says about the scan.
+func (r *Recorder) Observe(event core.Event) {
+	switch event.Kind {
+	case core.EventKindTimeline:
+		if event.Timeline != nil {
+			r.observeHost(*event.Timeline)
+		}
+	case core.EventKindResult:
+		r.observeResult(*event.Result)
+	case core.EventKindError:
+		if event.Error != nil {
+			r.observeError(event.Error)
+		}
+	}
+}
+
+// observeHost opens a host's span with its first stream and ends it with
+// its last, so a host scanned over TCP and UDP gets one span.
+func (r *Recorder) observeHost(entry core.TimelineEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch entry.Kind {
+	case core.TimelineHostStart:
+		if open, ok := r.open[entry.Host]; ok {
+			open.streams++
+			return
+		}
+		if r.started >= HostSpanLimit {
+			r.dropped++
+			return
+		}
+		r.started++
+		_, span := r.tracer.Start(r.scanCtx, "host",
+			trace.WithTimestamp(entry.Time),
+			trace.WithAttributes(attribute.String("server.address", entry.Host)))
+		r.open[entry.Host] = &hostSpan{span: span, streams: 1}
+	case core.TimelineHostFinish:
+		open, ok := r.open[entry.Host]
+		if !ok {
+			return
+		}
+		if open.streams--; open.streams > 0 {
+			return
+		}
+		open.end(entry.Time)
+		delete(r.open, entry.Host)
+	}
+}
+
+// end ends the span with the host's probe counts.
+func (h *hostSpan) end(at time.Time, attrs ...attribute.KeyValue) {
+	h.span.SetAttributes(append(attrs,
+		attribute.Int64("portscan.probes", h.probes),
+		attribute.Int64("portscan.open", h.open),
+		attribute.Int64("portscan.errors", h.errors))...)
+	h.span.End(trace.WithTimestamp(at))
+}
+
+func (r *Recorder) observeResult(result core.ResultEvent) {
+	protocol := result.Protocol
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	transport := attribute.String("network.transport", protocol)
+	ctx := context.Background()
+	r.latency.Record(ctx, float64(result.Duration)/float64(time.Millisecond), metric.WithAttributes(transport))
+	r.probes.Add(ctx, 1, metric.WithAttributes(transport, attribute.String("portscan.state", string(result.State))))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if open, ok := r.open[result.Host]; ok {
+		open.probes++
+		if result.State == core.StateOpen {
+			open.open++
+		}
+	}
+}
+
+func (r *Recorder) observeError(err error) {
+	protocol := "unknown"
+	var scanErr *core.ScanError
+	if errors.As(err, &scanErr) {
+		if scanErr.Protocol != "" {
+			protocol = scanErr.Protocol
+		}
+		r.mu.Lock()
+		if open, ok := r.open[scanErr.Host]; ok {
+			open.errors++
+		}
+		r.mu.Unlock()
+	}
+	r.errors.Add(context.Background(), 1, metric.WithAttributes(attribute.String("network.transport", protocol)))
+}
+
+// ObserveExport records the output stage, from start until now, as an
+// "export" span named after its format. A non-nil err marks it failed.
+func (r *Recorder) ObserveExport(format string, start time.Time, err error) {
+	_, span := r.tracer.Start(r.scanCtx, "export",
+		trace.WithTimestamp(start),
+		trace.WithAttributes(attribute.String("portscan.output", format)))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
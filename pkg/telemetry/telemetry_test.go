@@ -0,0 +1,246 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lucchesi-sec/portscan/internal/core"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// collector records the OTLP/protobuf requests it receives.
+type collector struct {
+	mu      sync.Mutex
+	spans   []*tracepb.Span
+	metrics []*metricpb.Metric
+	headers http.Header
+}
+
+func newCollector(t *testing.T) (*collector, *httptest.Server) {
+	c := &collector{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.headers = r.Header.Clone()
+		switch r.URL.Path {
+		case "/v1/traces":
+			var req coltracepb.ExportTraceServiceRequest
+			if err := proto.Unmarshal(body, &req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			for _, rs := range req.ResourceSpans {
+				for _, ss := range rs.ScopeSpans {
+					c.spans = append(c.spans, ss.Spans...)
+				}
+			}
+		case "/v1/metrics":
+			var req colmetricpb.ExportMetricsServiceRequest
+			if err := proto.Unmarshal(body, &req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			for _, rm := range req.ResourceMetrics {
+				for _, sm := range rm.ScopeMetrics {
+					c.metrics = append(c.metrics, sm.Metrics...)
+				}
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return c, srv
+}
+
+func (c *collector) spansNamed(name string) []*tracepb.Span {
+	var spans []*tracepb.Span
+	for _, s := range c.spans {
+		if s.Name == name {
+			spans = append(spans, s)
+		}
+	}
+	return spans
+}
+
+func (c *collector) metric(name string) *metricpb.Metric {
+	for _, m := range c.metrics {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// attr returns the value of key in attrs, or nil.
+func attr(attrs []*commonpb.KeyValue, key string) *commonpb.AnyValue {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return nil
+}
+
+func TestRecorderFlush(t *testing.T) {
+	c, srv := newCollector(t)
+	rec, err := NewRecorder(Options{
+		Endpoint:   srv.URL + "/",
+		Headers:    map[string]string{"Authorization": "Bearer token"},
+		Attributes: map[string]string{"portscan.targets": "10.0.0.1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	rec.Observe(core.NewTimelineEvent(core.TimelineEntry{Time: now, Kind: core.TimelineHostStart, Host: "10.0.0.1"}))
+	rec.Observe(core.NewTimelineEvent(core.TimelineEntry{Time: now, Kind: core.TimelineHostStart, Host: "10.0.0.2"}))
+	rec.Observe(core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 22, State: core.StateOpen, Duration: 3 * time.Millisecond}))
+	rec.Observe(core.NewResultEvent(core.ResultEvent{Host: "10.0.0.1", Port: 23, State: core.StateClosed, Duration: 40 * time.Millisecond}))
+	rec.Observe(core.NewErrorEvent(&core.ScanError{Host: "10.0.0.2", Port: 53, Protocol: "udp", Err: errors.New("no route")}))
+	rec.Observe(core.NewTimelineEvent(core.TimelineEntry{Time: now.Add(time.Second), Kind: core.TimelineHostFinish, Host: "10.0.0.1"}))
+	rec.ObserveExport("json", now, errors.New("disk full"))
+
+	if err := rec.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if got := c.headers.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("Authorization header = %q", got)
+	}
+
+	scans := c.spansNamed("scan")
+	if len(scans) != 1 {
+		t.Fatalf("got %d scan spans, want 1", len(scans))
+	}
+	scan := scans[0]
+	if v := attr(scan.Attributes, "portscan.targets"); v.GetStringValue() != "10.0.0.1" {
+		t.Errorf("scan span portscan.targets = %v", v)
+	}
+
+	hosts := c.spansNamed("host")
+	if len(hosts) != 2 {
+		t.Fatalf("got %d host spans, want 2", len(hosts))
+	}
+	for _, host := range hosts {
+		if !bytes.Equal(host.TraceId, scan.TraceId) || !bytes.Equal(host.ParentSpanId, scan.SpanId) {
+			t.Errorf("host span is not a child of the scan span")
+		}
+		switch attr(host.Attributes, "server.address").GetStringValue() {
+		case "10.0.0.1":
+			if attr(host.Attributes, "portscan.open").GetIntValue() != 1 || attr(host.Attributes, "portscan.probes").GetIntValue() != 2 {
+				t.Errorf("10.0.0.1 span attributes = %v", host.Attributes)
+			}
+			if got := time.Duration(host.EndTimeUnixNano - host.StartTimeUnixNano); got != time.Second {
+				t.Errorf("10.0.0.1 span lasted %v, want 1s", got)
+			}
+		case "10.0.0.2":
+			if v := attr(host.Attributes, "portscan.host.finished"); v == nil || v.GetBoolValue() {
+				t.Errorf("unfinished host should be marked: %v", host.Attributes)
+			}
+			if attr(host.Attributes, "portscan.errors").GetIntValue() != 1 {
+				t.Errorf("10.0.0.2 span attributes = %v", host.Attributes)
+			}
+		}
+	}
+
+	exports := c.spansNamed("export")
+	if len(exports) != 1 || exports[0].Status.GetCode() != tracepb.Status_STATUS_CODE_ERROR || exports[0].Status.GetMessage() != "disk full" {
+		t.Errorf("export spans = %v; want one failed with disk full", exports)
+	}
+
+	duration := c.metric("portscan.probe.duration")
+	if duration == nil || len(duration.GetHistogram().GetDataPoints()) != 1 {
+		t.Fatalf("portscan.probe.duration = %v", duration)
+	}
+	point := duration.GetHistogram().DataPoints[0]
+	want := []uint64{0, 0, 1, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0}
+	if point.Count != 2 || len(point.BucketCounts) != len(want) {
+		t.Fatalf("histogram point = %v", point)
+	}
+	for i := range want {
+		if point.BucketCounts[i] != want[i] {
+			t.Errorf("bucketCounts = %v, want %v", point.BucketCounts, want)
+			break
+		}
+	}
+
+	errs := c.metric("portscan.probe.errors")
+	if errs == nil || len(errs.GetSum().GetDataPoints()) != 1 {
+		t.Fatalf("portscan.probe.errors = %v", errs)
+	}
+	if v := attr(errs.GetSum().DataPoints[0].Attributes, "network.transport"); v.GetStringValue() != "udp" {
+		t.Errorf("error counter transport = %v, want udp", v)
+	}
+	if probes := c.metric("portscan.probes"); probes == nil || len(probes.GetSum().GetDataPoints()) != 2 {
+		t.Errorf("portscan.probes = %v; want open and closed points", probes)
+	}
+}
+
+func TestRecorderReadsEnvironment(t *testing.T) {
+	c, srv := newCollector(t)
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", srv.URL)
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "api-key=secret%3D")
+	t.Setenv("OTEL_SERVICE_NAME", "scanner-7")
+
+	rec, err := NewRecorder(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.ObserveExport("json", time.Now(), nil)
+	if err := rec.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if got := c.headers.Get("Api-Key"); got != "secret=" {
+		t.Errorf("api-key header = %q, want secret=", got)
+	}
+	if len(c.spansNamed("export")) != 1 {
+		t.Errorf("the collector from OTEL_EXPORTER_OTLP_ENDPOINT got no export span")
+	}
+}
+
+func TestRecorderFlushError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthenticated", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	rec, err := NewRecorder(Options{Endpoint: srv.URL, Headers: map[string]string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Flush(context.Background()); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Flush error = %v; want the collector's 401", err)
+	}
+}
+
+func TestOTLPEndpoint(t *testing.T) {
+	tests := []struct {
+		raw, want string
+		ok        bool
+	}{
+		{"localhost:4318", "http://localhost:4318", true},
+		{"https://otel.example.com/", "https://otel.example.com", true},
+		{"grpc://otel:4317", "", false},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := otlpEndpoint(tt.raw)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("otlpEndpoint(%q) = %q, %v", tt.raw, got, err)
+		}
+	}
+}
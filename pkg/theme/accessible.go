@@ -0,0 +1,58 @@
+package theme
+
+import "github.com/charmbracelet/lipgloss"
+
+// Glyphs that accompany port states in accessible mode so state is not
+// conveyed by color alone.
+const (
+	GlyphOpen     = "●"
+	GlyphClosed   = "✕"
+	GlyphFiltered = "▲"
+)
+
+// accessibleStateColors come from the Okabe-Ito palette, which stays
+// distinguishable under the common forms of color vision deficiency.
+var accessibleStateColors = StateColors{
+	Open:     lipgloss.Color("#56B4E9"), // Sky blue
+	Closed:   lipgloss.Color("#D55E00"), // Vermillion
+	Filtered: lipgloss.Color("#F0E442"), // Yellow
+}
+
+// Accessible returns a copy of t with colorblind-safe state colors and
+// state glyphs enabled. Success, danger, and warning follow the state
+// colors so indicators elsewhere in the UI stay consistent with the table.
+func Accessible(t Theme) Theme {
+	t.States = accessibleStateColors
+	t.Success = accessibleStateColors.Open
+	t.Danger = accessibleStateColors.Closed
+	t.Warning = accessibleStateColors.Filtered
+	t.Glyphs = true
+	return t
+}
+
+// StateGlyph returns the glyph for a port state, or an empty string for
+// unknown states.
+func StateGlyph(state string) string {
+	switch state {
+	case "open":
+		return GlyphOpen
+	case "closed":
+		return GlyphClosed
+	case "filtered":
+		return GlyphFiltered
+	default:
+		return ""
+	}
+}
+
+// StateLabel returns state prefixed with its glyph from the theme's glyph
+// set when the theme has glyphs enabled, and state unchanged otherwise.
+func (t Theme) StateLabel(state string) string {
+	if !t.Glyphs {
+		return state
+	}
+	if glyph := t.GetSymbols().State(state); glyph != "" {
+		return glyph + " " + state
+	}
+	return state
+}
@@ -0,0 +1,38 @@
+package theme
+
+import "testing"
+
+func TestAccessibleOverridesStateColors(t *testing.T) {
+	got := Accessible(Dracula)
+	if got.Name != Dracula.Name || got.Primary != Dracula.Primary {
+		t.Error("Accessible should keep the base theme's other colors")
+	}
+	if got.GetStateColors() != accessibleStateColors {
+		t.Errorf("state colors = %+v, want colorblind-safe palette", got.GetStateColors())
+	}
+	if got.Success != accessibleStateColors.Open || got.Danger != accessibleStateColors.Closed {
+		t.Error("success and danger should follow the state colors")
+	}
+	if !got.Glyphs || Dracula.Glyphs {
+		t.Error("Accessible should enable glyphs without mutating the base theme")
+	}
+}
+
+func TestStateLabel(t *testing.T) {
+	if got := Default.StateLabel("open"); got != "open" {
+		t.Errorf("StateLabel without glyphs = %q, want %q", got, "open")
+	}
+
+	accessible := Accessible(Default)
+	cases := map[string]string{
+		"open":     "● open",
+		"closed":   "✕ closed",
+		"filtered": "▲ filtered",
+		"unknown":  "unknown",
+	}
+	for state, want := range cases {
+		if got := accessible.StateLabel(state); got != want {
+			t.Errorf("StateLabel(%q) = %q, want %q", state, got, want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package theme
+
+import "github.com/charmbracelet/lipgloss"
+
+// AutoThemeName selects a palette matching the terminal background.
+const AutoThemeName = "auto"
+
+// autoPalette pairs light- and dark-background colors for the auto theme.
+// The dark variants match the default theme.
+var autoPalette = struct {
+	Primary, Secondary, Success, Warning, Danger, Info lipgloss.AdaptiveColor
+	Background, Foreground, Muted                      lipgloss.AdaptiveColor
+}{
+	Primary:    lipgloss.AdaptiveColor{Light: "125", Dark: "205"},
+	Secondary:  lipgloss.AdaptiveColor{Light: "91", Dark: "135"},
+	Success:    lipgloss.AdaptiveColor{Light: "28", Dark: "42"},
+	Warning:    lipgloss.AdaptiveColor{Light: "130", Dark: "214"},
+	Danger:     lipgloss.AdaptiveColor{Light: "160", Dark: "196"},
+	Info:       lipgloss.AdaptiveColor{Light: "25", Dark: "39"},
+	Background: lipgloss.AdaptiveColor{Light: "15", Dark: "0"},
+	Foreground: lipgloss.AdaptiveColor{Light: "0", Dark: "15"},
+	Muted:      lipgloss.AdaptiveColor{Light: "244", Dark: "240"},
+}
+
+// hasDarkBackground queries the terminal background. It is a variable so
+// tests can force either variant.
+var hasDarkBackground = lipgloss.HasDarkBackground
+
+// Auto returns the auto theme resolved for the current terminal background.
+func Auto() Theme {
+	return resolveAuto(hasDarkBackground())
+}
+
+// resolveAuto picks the light or dark side of each adaptive color.
+func resolveAuto(dark bool) Theme {
+	pick := func(c lipgloss.AdaptiveColor) lipgloss.Color {
+		if dark {
+			return lipgloss.Color(c.Dark)
+		}
+		return lipgloss.Color(c.Light)
+	}
+
+	p := autoPalette
+	return Theme{
+		Name:       AutoThemeName,
+		Primary:    pick(p.Primary),
+		Secondary:  pick(p.Secondary),
+		Success:    pick(p.Success),
+		Warning:    pick(p.Warning),
+		Danger:     pick(p.Danger),
+		Info:       pick(p.Info),
+		Background: pick(p.Background),
+		Foreground: pick(p.Foreground),
+		Muted:      pick(p.Muted),
+		States: StateColors{
+			Open:     pick(p.Success),
+			Closed:   pick(p.Danger),
+			Filtered: pick(p.Warning),
+		},
+	}
+}
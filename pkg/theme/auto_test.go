@@ -0,0 +1,37 @@
+package theme
+
+import "testing"
+
+func TestResolveAutoPicksVariant(t *testing.T) {
+	dark := resolveAuto(true)
+	light := resolveAuto(false)
+
+	if dark.Primary != Default.Primary || dark.Background != Default.Background {
+		t.Errorf("dark variant should match the default theme, got %+v", dark)
+	}
+	if light.Background == dark.Background || light.Foreground == dark.Foreground {
+		t.Error("light variant should invert background and foreground")
+	}
+	if dark.Name != AutoThemeName || light.Name != AutoThemeName {
+		t.Error("both variants should be named auto")
+	}
+}
+
+func TestLookupAutoUsesBackgroundDetection(t *testing.T) {
+	original := hasDarkBackground
+	t.Cleanup(func() { hasDarkBackground = original })
+
+	hasDarkBackground = func() bool { return false }
+	if got := GetTheme(AutoThemeName); got.Background != resolveAuto(false).Background {
+		t.Errorf("expected light variant on light terminals, got %+v", got)
+	}
+
+	hasDarkBackground = func() bool { return true }
+	if got := GetTheme(AutoThemeName); got.Background != resolveAuto(true).Background {
+		t.Errorf("expected dark variant on dark terminals, got %+v", got)
+	}
+
+	if !Exists(AutoThemeName) || !IsBuiltin(AutoThemeName) {
+		t.Error("auto should be a built-in theme")
+	}
+}
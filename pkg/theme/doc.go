@@ -6,9 +6,14 @@
 //
 // Built-in Themes:
 //
+//   - auto: Picks light or dark colors by detecting the terminal background
 //   - default: Clean light/dark theme suitable for most terminals
 //   - dracula: Popular dark theme with vibrant colors
 //   - monokai: Dark theme inspired by Monokai color scheme
+//   - solarized: Dark variant of Ethan Schoonover's Solarized palette
+//   - nord: Arctic, blue-tinted dark theme
+//   - gruvbox: Warm, retro dark theme
+//   - high-contrast: Black background with saturated accents for low vision
 //
 // Example usage:
 //
@@ -35,6 +40,7 @@
 //   - Background: Background color
 //   - Foreground: Default text color
 //   - Muted: Subdued text (help text, timestamps)
+//   - States: Open, closed, and filtered port colors for tables and charts
 //
 // Custom Themes:
 //
@@ -52,6 +58,22 @@
 //	}
 //	theme.Register("custom", customTheme)
 //
+// Users can also add themes without recompiling by placing YAML files in
+// ~/.config/portscan/themes (see UserThemeDir). Files are validated on load:
+// every color key except info is required and must be a hex color.
+//
+//	name: ocean
+//	primary: "#268bd2"
+//	secondary: "#2aa198"
+//	success: "#859900"
+//	warning: "#b58900"
+//	danger: "#dc322f"
+//	background: "#002b36"
+//	foreground: "#fdf6e3"
+//	muted: "#586e75"
+//
+// Run "portscan themes list" to see every available theme.
+//
 // Color Format:
 //
 // Colors use lipgloss.Color which supports:
@@ -0,0 +1,196 @@
+package theme
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"go.yaml.in/yaml/v3"
+)
+
+// hexColorPattern matches #rgb and #rrggbb colors.
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// themeFile is the on-disk YAML representation of a theme.
+type themeFile struct {
+	Name       string `yaml:"name"`
+	Primary    string `yaml:"primary"`
+	Secondary  string `yaml:"secondary"`
+	Success    string `yaml:"success"`
+	Warning    string `yaml:"warning"`
+	Danger     string `yaml:"danger"`
+	Info       string `yaml:"info"`
+	Background string `yaml:"background"`
+	Foreground string `yaml:"foreground"`
+	Muted      string `yaml:"muted"`
+	Open       string `yaml:"open"`
+	Closed     string `yaml:"closed"`
+	Filtered   string `yaml:"filtered"`
+}
+
+// LoadError describes a theme file that could not be loaded.
+type LoadError struct {
+	Path string
+	Err  error
+}
+
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("theme %s: %v", e.Path, e.Err)
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+func errBuiltinName(name string) error {
+	return fmt.Errorf("name %q is reserved for a built-in theme", name)
+}
+
+// UserThemeDir returns the directory scanned for user themes:
+// $XDG_CONFIG_HOME/portscan/themes, or ~/.config/portscan/themes.
+func UserThemeDir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "portscan", "themes"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "portscan", "themes"), nil
+}
+
+// LoadFromFile reads a YAML theme definition. Every color except info and
+// the port state colors is required and must be a hex color; info defaults
+// to primary, open/closed/filtered default to success/danger/warning, and
+// the name defaults to the file name without its extension.
+func LoadFromFile(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, &LoadError{Path: path, Err: err}
+	}
+
+	var file themeFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return Theme{}, &LoadError{Path: path, Err: err}
+	}
+
+	if file.Name == "" {
+		file.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if file.Info == "" {
+		file.Info = file.Primary
+	}
+	if file.Open == "" {
+		file.Open = file.Success
+	}
+	if file.Closed == "" {
+		file.Closed = file.Danger
+	}
+	if file.Filtered == "" {
+		file.Filtered = file.Warning
+	}
+
+	t, err := file.toTheme()
+	if err != nil {
+		return Theme{}, &LoadError{Path: path, Err: err}
+	}
+	t.Source = path
+	return t, nil
+}
+
+// toTheme validates every color and converts the file to a Theme.
+func (f themeFile) toTheme() (Theme, error) {
+	colors := []struct {
+		key   string
+		value string
+	}{
+		{"primary", f.Primary},
+		{"secondary", f.Secondary},
+		{"success", f.Success},
+		{"warning", f.Warning},
+		{"danger", f.Danger},
+		{"info", f.Info},
+		{"background", f.Background},
+		{"foreground", f.Foreground},
+		{"muted", f.Muted},
+		{"open", f.Open},
+		{"closed", f.Closed},
+		{"filtered", f.Filtered},
+	}
+
+	var problems []error
+	for _, c := range colors {
+		switch {
+		case c.value == "":
+			problems = append(problems, fmt.Errorf("missing required key %q", c.key))
+		case !hexColorPattern.MatchString(c.value):
+			problems = append(problems, fmt.Errorf("%s: %q is not a hex color (#rgb or #rrggbb)", c.key, c.value))
+		}
+	}
+	if len(problems) > 0 {
+		return Theme{}, errors.Join(problems...)
+	}
+
+	return Theme{
+		Name:       f.Name,
+		Primary:    lipgloss.Color(f.Primary),
+		Secondary:  lipgloss.Color(f.Secondary),
+		Success:    lipgloss.Color(f.Success),
+		Warning:    lipgloss.Color(f.Warning),
+		Danger:     lipgloss.Color(f.Danger),
+		Info:       lipgloss.Color(f.Info),
+		Background: lipgloss.Color(f.Background),
+		Foreground: lipgloss.Color(f.Foreground),
+		Muted:      lipgloss.Color(f.Muted),
+		States: StateColors{
+			Open:     lipgloss.Color(f.Open),
+			Closed:   lipgloss.Color(f.Closed),
+			Filtered: lipgloss.Color(f.Filtered),
+		},
+	}, nil
+}
+
+// DiscoverThemes loads every *.yaml and *.yml file in dir. A missing
+// directory yields no themes and no errors; invalid files are reported
+// individually so one bad file does not hide the others.
+func DiscoverThemes(dir string) ([]Theme, []error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, []error{&LoadError{Path: dir, Err: err}}
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(paths)
+
+	var (
+		themes []Theme
+		errs   []error
+	)
+	for _, path := range paths {
+		t, err := LoadFromFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		themes = append(themes, t)
+	}
+	return themes, errs
+}
@@ -0,0 +1,137 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const validThemeYAML = `name: ocean
+primary: "#268bd2"
+secondary: "#2aa198"
+success: "#859900"
+warning: "#b58900"
+danger: "#dc322f"
+background: "#002b36"
+foreground: "#fdf6e3"
+muted: "#586e75"
+`
+
+func writeThemeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write theme: %v", err)
+	}
+	return path
+}
+
+func TestLoadFromFile(t *testing.T) {
+	path := writeThemeFile(t, t.TempDir(), "ocean.yaml", validThemeYAML)
+
+	loaded, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile returned error: %v", err)
+	}
+	if loaded.Name != "ocean" || loaded.Primary != lipgloss.Color("#268bd2") {
+		t.Errorf("unexpected theme %+v", loaded)
+	}
+	if loaded.Info != loaded.Primary {
+		t.Errorf("info should default to primary, got %q", loaded.Info)
+	}
+	if loaded.States.Open != loaded.Success || loaded.States.Closed != loaded.Danger || loaded.States.Filtered != loaded.Warning {
+		t.Errorf("state colors should default to success/danger/warning, got %+v", loaded.States)
+	}
+	if loaded.Source != path {
+		t.Errorf("source = %q, want %q", loaded.Source, path)
+	}
+}
+
+func TestLoadFromFileNameDefaultsToFileName(t *testing.T) {
+	content := strings.Replace(validThemeYAML, "name: ocean\n", "", 1)
+	path := writeThemeFile(t, t.TempDir(), "deep-sea.yml", content)
+
+	loaded, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile returned error: %v", err)
+	}
+	if loaded.Name != "deep-sea" {
+		t.Errorf("name = %q, want deep-sea", loaded.Name)
+	}
+}
+
+func TestLoadFromFileValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "missing key",
+			content: strings.Replace(validThemeYAML, "muted: \"#586e75\"\n", "", 1),
+			wantErr: `missing required key "muted"`,
+		},
+		{
+			name:    "invalid hex",
+			content: strings.Replace(validThemeYAML, "#dc322f", "red", 1),
+			wantErr: "danger",
+		},
+		{
+			name:    "unknown key",
+			content: validThemeYAML + "accent: \"#ffffff\"\n",
+			wantErr: "accent",
+		},
+		{
+			name:    "malformed yaml",
+			content: "primary: [",
+			wantErr: "theme",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeThemeFile(t, t.TempDir(), "bad.yaml", tt.content)
+			_, err := LoadFromFile(path)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %q should mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDiscoverThemes(t *testing.T) {
+	dir := t.TempDir()
+	writeThemeFile(t, dir, "ocean.yaml", validThemeYAML)
+	writeThemeFile(t, dir, "broken.yml", "primary: nope\n")
+	writeThemeFile(t, dir, "notes.txt", "ignored")
+
+	themes, errs := DiscoverThemes(dir)
+	if len(themes) != 1 || themes[0].Name != "ocean" {
+		t.Errorf("expected only the ocean theme, got %+v", themes)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "broken.yml") {
+		t.Errorf("expected one error naming broken.yml, got %v", errs)
+	}
+
+	themes, errs = DiscoverThemes(filepath.Join(dir, "missing"))
+	if themes != nil || errs != nil {
+		t.Error("a missing directory should not be an error")
+	}
+}
+
+func TestUserThemeDirHonorsXDG(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	dir, err := UserThemeDir()
+	if err != nil {
+		t.Fatalf("UserThemeDir returned error: %v", err)
+	}
+	if dir != filepath.Join("/tmp/xdg", "portscan", "themes") {
+		t.Errorf("unexpected dir %q", dir)
+	}
+}
@@ -0,0 +1,123 @@
+package theme
+
+import (
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// GlyphSet is the set of symbols the UI draws with: bars, sparklines,
+// borders, the spinner and port state glyphs. Icons and arrows written
+// into labels are rewritten by Text.
+type GlyphSet struct {
+	Name string
+
+	// Port state glyphs shown in accessible mode.
+	Open, Closed, Filtered string
+
+	BarFull   rune   // filled part of progress and chart bars
+	BarEmpty  rune   // unfilled part of progress bars
+	Sparkline []rune // sparkline levels, lowest first
+	Spinner   []string
+	Border    lipgloss.Border
+
+	// text rewrites labels for the set; nil leaves them unchanged.
+	text *strings.Replacer
+}
+
+// UnicodeGlyphs draws with emoji, arrows and block elements, which most
+// modern terminals render.
+var UnicodeGlyphs = GlyphSet{
+	Name:      "unicode",
+	Open:      GlyphOpen,
+	Closed:    GlyphClosed,
+	Filtered:  GlyphFiltered,
+	BarFull:   '█',
+	BarEmpty:  '░',
+	Sparkline: []rune{' ', '▂', '▃', '▄', '▅', '▆', '▇', '█'},
+	Spinner:   []string{"⣾ ", "⣽ ", "⣻ ", "⢿ ", "⡿ ", "⣟ ", "⣯ ", "⣷ "},
+	Border:    lipgloss.RoundedBorder(),
+}
+
+// ASCIIGlyphs draws with plain ASCII for consoles whose fonts lack emoji
+// and block elements, such as the legacy Windows console.
+var ASCIIGlyphs = GlyphSet{
+	Name:      "ascii",
+	Open:      "+",
+	Closed:    "x",
+	Filtered:  "?",
+	BarFull:   '#',
+	BarEmpty:  '.',
+	Sparkline: []rune{' ', '.', ':', '-', '=', '+', '*', '#'},
+	Spinner:   []string{"| ", "/ ", "- ", "\\ "},
+	Border:    lipgloss.ASCIIBorder(),
+	text: strings.NewReplacer(
+		// Icons that only decorate a heading are dropped.
+		"🧾 ", "", "📡 ", "", "⏳ ", "", "📊 ", "", "🖥  ", "", "🖥 ", "",
+		"🌐 ", "", "🔒 ", "", "🔓 ", "", "🚫 ", "", "🛡️  ", "", "🏷️  ", "",
+		"⚡ ", "", "🔍 ", "", "🔎 ", "", "📋 ", "", "📝 ", "", "🎨 ", "",
+		"💾 ", "", "📖 ", "", "⌘ ", "", "▦ ", "", "☑ ", "",
+		// Icons that carry meaning become bracketed words.
+		"✓", "[OK]", "⚠", "[!]", "⏸", "[||]", "▶", "[>]",
+		// Key names read better spelled out than as arrows. The help
+		// screen's padded names keep their column.
+		"↑/k  ", "Up/k ", "↓/j    ", "Down/j ",
+		"↑/↓", "Up/Down", "↑↓", "Up/Down", "←/→", "Left/Right",
+		"↑/k", "Up/k", "↓/j", "Down/j",
+		"→", "->", "←", "<-", "↑", "^", "↓", "v",
+		"•", "|", "…", "...", "‹", "<", "›", ">",
+		"▸", ">", "▾", "v", "▲", "^", "▼", "v",
+		"●", "*", "✕", "x", "█", "#", "░", ".",
+	),
+}
+
+// State returns the set's glyph for a port state, or an empty string for
+// unknown states.
+func (g GlyphSet) State(state string) string {
+	switch state {
+	case "open":
+		return g.Open
+	case "closed":
+		return g.Closed
+	case "filtered":
+		return g.Filtered
+	default:
+		return ""
+	}
+}
+
+// Text rewrites the icons and arrows in a label with the set's symbols.
+func (g GlyphSet) Text(s string) string {
+	if g.text == nil {
+		return s
+	}
+	return g.text.Replace(s)
+}
+
+// ASCII returns a copy of t that draws with ASCIIGlyphs.
+func ASCII(t Theme) Theme {
+	t.Symbols = ASCIIGlyphs
+	return t
+}
+
+// LegacyConsole reports whether the terminal is likely the legacy Windows
+// console, whose default fonts cannot draw emoji or block elements.
+// Windows Terminal, ConEmu and editor terminals announce themselves and
+// draw them fine.
+func LegacyConsole() bool {
+	return legacyConsole(runtime.GOOS, os.Getenv)
+}
+
+func legacyConsole(goos string, getenv func(string) string) bool {
+	if goos != "windows" {
+		return false
+	}
+	for _, name := range []string{"WT_SESSION", "TERM_PROGRAM", "ConEmuANSI", "TERM"} {
+		if getenv(name) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestASCIIUsesASCIIGlyphs(t *testing.T) {
+	got := ASCII(Dracula)
+	if got.Name != Dracula.Name || got.Primary != Dracula.Primary {
+		t.Error("ASCII should keep the base theme's colors")
+	}
+	if got.GetSymbols().Name != "ascii" {
+		t.Errorf("symbols = %q, want ascii", got.GetSymbols().Name)
+	}
+	if Dracula.GetSymbols().Name != "unicode" {
+		t.Error("themes without symbols should fall back to UnicodeGlyphs")
+	}
+	if got.ModalStyle(1).GetBorderStyle().TopLeft != "+" {
+		t.Error("modal border should be drawn with ASCII")
+	}
+}
+
+func TestASCIIGlyphsText(t *testing.T) {
+	tests := map[string]string{
+		"✓ Scan complete":                              "[OK] Scan complete",
+		"⏸ PAUSED":                                     "[||] PAUSED",
+		"▶ Sort: Port ↑":                               "[>] Sort: Port ^",
+		"📊 Live Statistics":                            "Live Statistics",
+		"↑/↓: Navigate • Enter: Select":                "Up/Down: Navigate | Enter: Select",
+		"████░░":                                       "####..",
+		"  ↑/k        Move up\n  ↓/j        Move down": "  Up/k       Move up\n  Down/j     Move down",
+		"plain text":                                   "plain text",
+	}
+	for in, want := range tests {
+		if got := ASCIIGlyphs.Text(in); got != want {
+			t.Errorf("Text(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := UnicodeGlyphs.Text("✓ done"); got != "✓ done" {
+		t.Errorf("UnicodeGlyphs.Text should leave labels unchanged, got %q", got)
+	}
+}
+
+func TestASCIIGlyphsAreASCII(t *testing.T) {
+	g := ASCIIGlyphs
+	runes := append([]rune{g.BarFull, g.BarEmpty}, g.Sparkline...)
+	for _, r := range runes {
+		if r >= utf8.RuneSelf {
+			t.Errorf("glyph %q is not ASCII", r)
+		}
+	}
+	parts := append([]string{g.Open, g.Closed, g.Filtered, g.Border.Top, g.Border.TopLeft}, g.Spinner...)
+	for _, s := range parts {
+		if strings.IndexFunc(s, func(r rune) bool { return r >= utf8.RuneSelf }) >= 0 {
+			t.Errorf("glyph %q is not ASCII", s)
+		}
+	}
+}
+
+func TestStateLabelUsesSymbols(t *testing.T) {
+	got := ASCII(Accessible(Default))
+	if label := got.StateLabel("open"); label != "+ open" {
+		t.Errorf("StateLabel(open) = %q, want %q", label, "+ open")
+	}
+}
+
+func TestLegacyConsole(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+	tests := []struct {
+		name string
+		goos string
+		vars map[string]string
+		want bool
+	}{
+		{"windows console", "windows", nil, true},
+		{"windows terminal", "windows", map[string]string{"WT_SESSION": "1"}, false},
+		{"conemu", "windows", map[string]string{"ConEmuANSI": "ON"}, false},
+		{"mintty", "windows", map[string]string{"TERM": "xterm-256color"}, false},
+		{"linux", "linux", nil, false},
+	}
+	for _, tt := range tests {
+		if got := legacyConsole(tt.goos, env(tt.vars)); got != tt.want {
+			t.Errorf("%s: legacyConsole = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package theme
+
+import (
+	"sort"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	builtins   = []Theme{Default, Dracula, Monokai, Solarized, Nord, Gruvbox, HighContrast}
+	registry   = func() map[string]Theme {
+		themes := make(map[string]Theme, len(builtins))
+		for _, t := range builtins {
+			themes[t.Name] = t
+		}
+		return themes
+	}()
+	builtinNames = func() map[string]bool {
+		names := map[string]bool{AutoThemeName: true}
+		for _, t := range builtins {
+			names[t.Name] = true
+		}
+		return names
+	}()
+
+	userThemesOnce sync.Once
+	userThemeErrs  []error
+)
+
+// Register adds or replaces a theme under the given name.
+func Register(name string, t Theme) {
+	t.Name = name
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = t
+}
+
+// Lookup returns the theme registered under name, including user themes
+// discovered in UserThemeDir. The auto theme is resolved against the
+// terminal background on each call.
+func Lookup(name string) (Theme, bool) {
+	if name == AutoThemeName {
+		return Auto(), true
+	}
+	ensureUserThemes()
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	t, ok := registry[name]
+	return t, ok
+}
+
+// Exists reports whether a theme with the given name is available.
+func Exists(name string) bool {
+	_, ok := Lookup(name)
+	return ok
+}
+
+// Names returns all available theme names in sorted order.
+func Names() []string {
+	ensureUserThemes()
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry)+1)
+	names = append(names, AutoThemeName)
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsBuiltin reports whether name refers to a theme shipped with portscan.
+func IsBuiltin(name string) bool {
+	return builtinNames[name]
+}
+
+// UserThemeErrors returns the problems found while loading user theme files.
+func UserThemeErrors() []error {
+	ensureUserThemes()
+	return userThemeErrs
+}
+
+// ensureUserThemes loads user theme files once. Invalid files are skipped and
+// reported through UserThemeErrors; user themes cannot shadow built-ins.
+func ensureUserThemes() {
+	userThemesOnce.Do(func() {
+		dir, err := UserThemeDir()
+		if err != nil {
+			return
+		}
+		themes, errs := DiscoverThemes(dir)
+		for _, t := range themes {
+			if IsBuiltin(t.Name) {
+				errs = append(errs, &LoadError{Path: t.Source, Err: errBuiltinName(t.Name)})
+				continue
+			}
+			Register(t.Name, t)
+		}
+		userThemeErrs = errs
+	})
+}
@@ -0,0 +1,44 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestRegisterAndLookup(t *testing.T) {
+	custom := Theme{Primary: lipgloss.Color("#123456")}
+	Register("test-custom", custom)
+
+	got, ok := Lookup("test-custom")
+	if !ok {
+		t.Fatal("registered theme should be found")
+	}
+	if got.Name != "test-custom" || got.Primary != custom.Primary {
+		t.Errorf("unexpected theme %+v", got)
+	}
+	if GetTheme("test-custom").Primary != custom.Primary {
+		t.Error("GetTheme should return registered themes")
+	}
+
+	found := false
+	for _, name := range Names() {
+		if name == "test-custom" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Names should include registered themes")
+	}
+}
+
+func TestBuiltinThemesExist(t *testing.T) {
+	for _, name := range []string{"default", "dracula", "monokai", "solarized", "nord", "gruvbox", "high-contrast"} {
+		if !Exists(name) || !IsBuiltin(name) {
+			t.Errorf("expected built-in theme %q", name)
+		}
+	}
+	if Exists("no-such-theme") {
+		t.Error("unknown themes should not exist")
+	}
+}
@@ -14,6 +14,10 @@ type Theme struct {
 	Background lipgloss.Color
 	Foreground lipgloss.Color
 	Muted      lipgloss.Color
+	States     StateColors // port state colors; zero values fall back to defaults
+	Glyphs     bool        // prefix port states with shape glyphs (accessible mode)
+	Symbols    GlyphSet    // symbols the UI draws with; the zero value uses UnicodeGlyphs
+	Source     string      // file path for user themes; empty for built-ins
 }
 
 var (
@@ -28,6 +32,7 @@ var (
 		Background: lipgloss.Color("0"),
 		Foreground: lipgloss.Color("15"),
 		Muted:      lipgloss.Color("240"),
+		States:     defaultStateColors,
 	}
 
 	Dracula = Theme{
@@ -41,6 +46,11 @@ var (
 		Background: lipgloss.Color("#282a36"),
 		Foreground: lipgloss.Color("#f8f8f2"),
 		Muted:      lipgloss.Color("#6272a4"),
+		States: StateColors{
+			Open:     lipgloss.Color("#50fa7b"),
+			Closed:   lipgloss.Color("#ff5555"),
+			Filtered: lipgloss.Color("#ffb86c"),
+		},
 	}
 
 	Monokai = Theme{
@@ -54,20 +64,95 @@ var (
 		Background: lipgloss.Color("#272822"),
 		Foreground: lipgloss.Color("#f8f8f2"),
 		Muted:      lipgloss.Color("#75715e"),
+		States: StateColors{
+			Open:     lipgloss.Color("#a6e22e"),
+			Closed:   lipgloss.Color("#f92672"),
+			Filtered: lipgloss.Color("#fd971f"),
+		},
+	}
+
+	Solarized = Theme{
+		Name:       "solarized",
+		Primary:    lipgloss.Color("#268bd2"),
+		Secondary:  lipgloss.Color("#6c71c4"),
+		Success:    lipgloss.Color("#859900"),
+		Warning:    lipgloss.Color("#b58900"),
+		Danger:     lipgloss.Color("#dc322f"),
+		Info:       lipgloss.Color("#2aa198"),
+		Background: lipgloss.Color("#002b36"),
+		Foreground: lipgloss.Color("#eee8d5"),
+		Muted:      lipgloss.Color("#586e75"),
+		States: StateColors{
+			Open:     lipgloss.Color("#859900"),
+			Closed:   lipgloss.Color("#dc322f"),
+			Filtered: lipgloss.Color("#cb4b16"),
+		},
+	}
+
+	Nord = Theme{
+		Name:       "nord",
+		Primary:    lipgloss.Color("#88c0d0"),
+		Secondary:  lipgloss.Color("#b48ead"),
+		Success:    lipgloss.Color("#a3be8c"),
+		Warning:    lipgloss.Color("#ebcb8b"),
+		Danger:     lipgloss.Color("#bf616a"),
+		Info:       lipgloss.Color("#81a1c1"),
+		Background: lipgloss.Color("#2e3440"),
+		Foreground: lipgloss.Color("#eceff4"),
+		Muted:      lipgloss.Color("#4c566a"),
+		States: StateColors{
+			Open:     lipgloss.Color("#a3be8c"),
+			Closed:   lipgloss.Color("#bf616a"),
+			Filtered: lipgloss.Color("#d08770"),
+		},
+	}
+
+	Gruvbox = Theme{
+		Name:       "gruvbox",
+		Primary:    lipgloss.Color("#fabd2f"),
+		Secondary:  lipgloss.Color("#d3869b"),
+		Success:    lipgloss.Color("#b8bb26"),
+		Warning:    lipgloss.Color("#fe8019"),
+		Danger:     lipgloss.Color("#fb4934"),
+		Info:       lipgloss.Color("#83a598"),
+		Background: lipgloss.Color("#282828"),
+		Foreground: lipgloss.Color("#ebdbb2"),
+		Muted:      lipgloss.Color("#928374"),
+		States: StateColors{
+			Open:     lipgloss.Color("#b8bb26"),
+			Closed:   lipgloss.Color("#fb4934"),
+			Filtered: lipgloss.Color("#fe8019"),
+		},
+	}
+
+	// HighContrast uses pure black and white with saturated accents for
+	// low-vision users and washed-out displays.
+	HighContrast = Theme{
+		Name:       "high-contrast",
+		Primary:    lipgloss.Color("#ffff00"),
+		Secondary:  lipgloss.Color("#00ffff"),
+		Success:    lipgloss.Color("#00ff00"),
+		Warning:    lipgloss.Color("#ffff00"),
+		Danger:     lipgloss.Color("#ff0000"),
+		Info:       lipgloss.Color("#00ffff"),
+		Background: lipgloss.Color("#000000"),
+		Foreground: lipgloss.Color("#ffffff"),
+		Muted:      lipgloss.Color("#c0c0c0"),
+		States: StateColors{
+			Open:     lipgloss.Color("#00ff00"),
+			Closed:   lipgloss.Color("#ff0000"),
+			Filtered: lipgloss.Color("#ffff00"),
+		},
 	}
 )
 
-// GetTheme returns the theme matching the given name.
+// GetTheme returns the theme matching the given name, including user themes.
 // Defaults to the "default" theme if name is not recognized.
 func GetTheme(name string) Theme {
-	switch name {
-	case "dracula":
-		return Dracula
-	case "monokai":
-		return Monokai
-	default:
-		return Default
+	if t, ok := Lookup(name); ok {
+		return t
 	}
+	return Default
 }
 
 // HeaderStyle returns the style for header text.
@@ -116,13 +201,34 @@ type StateColors struct {
 	Filtered lipgloss.Color
 }
 
+// defaultStateColors is used by themes that do not define state colors.
+var defaultStateColors = StateColors{
+	Open:     lipgloss.Color("#00FF00"), // Green for open ports
+	Closed:   lipgloss.Color("#FF0000"), // Red for closed ports
+	Filtered: lipgloss.Color("#FFA500"), // Orange for filtered ports
+}
+
 // GetStateColors returns the color scheme for port states based on the theme
 func (t Theme) GetStateColors() StateColors {
-	return StateColors{
-		Open:     lipgloss.Color("#00FF00"), // Green for open ports
-		Closed:   lipgloss.Color("#FF0000"), // Red for closed ports
-		Filtered: lipgloss.Color("#FFA500"), // Orange for filtered ports
+	colors := t.States
+	if colors.Open == "" {
+		colors.Open = defaultStateColors.Open
+	}
+	if colors.Closed == "" {
+		colors.Closed = defaultStateColors.Closed
 	}
+	if colors.Filtered == "" {
+		colors.Filtered = defaultStateColors.Filtered
+	}
+	return colors
+}
+
+// GetSymbols returns the glyph set the theme draws with.
+func (t Theme) GetSymbols() GlyphSet {
+	if t.Symbols.Name == "" {
+		return UnicodeGlyphs
+	}
+	return t.Symbols
 }
 
 // TableHeaderStyle styles table headers.
@@ -149,18 +255,34 @@ func (t Theme) TableSelectedStyle() lipgloss.Style {
 		Padding(0, 1)
 }
 
+// SearchMatchStyle highlights search matches inside table cells.
+func (t Theme) SearchMatchStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(t.Background).
+		Background(t.Warning).
+		Bold(true)
+}
+
 // TableContainerStyle styles the outer container of the table.
 func (t Theme) TableContainerStyle() lipgloss.Style {
 	return lipgloss.NewStyle().
-		Border(lipgloss.NormalBorder()).
+		Border(t.tableBorder()).
 		BorderForeground(t.Muted).
 		Padding(0, 1)
 }
 
+// tableBorder is the table container's square border, or the ASCII one.
+func (t Theme) tableBorder() lipgloss.Border {
+	if t.GetSymbols().Name == ASCIIGlyphs.Name {
+		return ASCIIGlyphs.Border
+	}
+	return lipgloss.NormalBorder()
+}
+
 // DashboardPanelStyle styles the dashboard side panel container.
 func (t Theme) DashboardPanelStyle() lipgloss.Style {
 	return lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(t.GetSymbols().Border).
 		BorderForeground(t.Primary).
 		Padding(1)
 }
@@ -168,7 +290,7 @@ func (t Theme) DashboardPanelStyle() lipgloss.Style {
 // ModalStyle returns the style for modal dialogs (padding parameter to avoid import cycle)
 func (t Theme) ModalStyle(padding int) lipgloss.Style {
 	return lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(t.GetSymbols().Border).
 		BorderForeground(t.Primary).
 		Background(t.Background).
 		Foreground(t.Foreground).
@@ -86,7 +86,7 @@ func TestThemeStyles(t *testing.T) {
 }
 
 func TestThemeProperties(t *testing.T) {
-	themes := []Theme{Default, Dracula, Monokai}
+	themes := []Theme{Default, Dracula, Monokai, Solarized, Nord, Gruvbox, HighContrast}
 
 	for _, theme := range themes {
 		t.Run(theme.Name, func(t *testing.T) {
@@ -102,6 +102,25 @@ func TestThemeProperties(t *testing.T) {
 			if theme.Foreground == "" {
 				t.Error("Foreground color should not be empty")
 			}
+			if theme.Info == "" {
+				t.Error("Info color should not be empty")
+			}
+			if theme.States.Open == "" || theme.States.Closed == "" || theme.States.Filtered == "" {
+				t.Errorf("state colors should all be set, got %+v", theme.States)
+			}
 		})
 	}
 }
+
+func TestGetStateColorsFallsBackToDefaults(t *testing.T) {
+	got := Theme{States: StateColors{Open: "#123456"}}.GetStateColors()
+	if got.Open != "#123456" {
+		t.Errorf("Open = %q, want theme override", got.Open)
+	}
+	if got.Closed != defaultStateColors.Closed || got.Filtered != defaultStateColors.Filtered {
+		t.Errorf("unset state colors should fall back to defaults, got %+v", got)
+	}
+	if Nord.GetStateColors() != Nord.States {
+		t.Error("themes with state colors should use them")
+	}
+}
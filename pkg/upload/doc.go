@@ -0,0 +1,41 @@
+// Package upload pushes finished scan reports to object storage.
+//
+// A destination is a bucket URL whose path is an object key template:
+//
+//	s3://security-reports/scans/{date}/{scan_id}.json.gz
+//	gs://security-reports/scans/{date}/{scan_id}.csv
+//
+// Templates may use these placeholders:
+//
+//   - {date}: the scan's start date in UTC, e.g. "2024-05-01"
+//   - {time}: the scan's start time in UTC, e.g. "134502"
+//   - {scan_id}: the identifier of the scan run
+//   - {format}: the export format, e.g. "json" or "csv"
+//
+// Example usage:
+//
+//	dest, err := upload.ParseDestination("s3://reports/scans/{date}/{scan_id}.json.gz")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	key := dest.ObjectKey(upload.KeyVars{Time: start, ScanID: id, Format: "json"})
+//	err = upload.Upload(ctx, dest, key, file, upload.Options{ContentType: "application/gzip"})
+//
+// Credentials:
+//
+// S3 uploads use the AWS SDK's upload manager and default credential
+// chain: environment variables, shared config and SSO profiles, web
+// identity, and ECS or EC2 instance roles. The region comes from
+// Options.Region or else the bucket itself, falling back to the AWS
+// configuration and then us-east-1. Options.Endpoint points uploads at
+// S3-compatible stores such as MinIO, using path-style URLs.
+//
+// GCS uploads use the Cloud Storage client with an OAuth access token from
+// GOOGLE_OAUTH_ACCESS_TOKEN (for example from "gcloud auth
+// print-access-token") when set, and Application Default Credentials
+// otherwise.
+//
+// Objects larger than one part are uploaded in parts: S3 multipart uploads
+// and GCS resumable uploads. Transient failures such as network errors,
+// throttling and 5xx responses are retried with exponential backoff.
+package upload
@@ -0,0 +1,46 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"cloud.google.com/go/storage"
+	"github.com/googleapis/gax-go/v2"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
+)
+
+// uploadGCS uploads with the Cloud Storage client, which switches to a
+// resumable upload for objects larger than one chunk. An access token in
+// GOOGLE_OAUTH_ACCESS_TOKEN is used when set, and Application Default
+// Credentials otherwise.
+func uploadGCS(ctx context.Context, d Destination, key string, body io.ReadSeeker, opts Options) error {
+	var clientOpts []option.ClientOption
+	if opts.Endpoint != "" {
+		clientOpts = append(clientOpts, option.WithEndpoint(opts.Endpoint+"/storage/v1/"))
+	}
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		clientOpts = append(clientOpts, option.WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})))
+	}
+	client, err := storage.NewClient(ctx, clientOpts...)
+	if err != nil {
+		return fmt.Errorf("gcs upload requires Google credentials: %w", err)
+	}
+	defer client.Close()
+
+	// The upload replaces the whole object, so it is safe to repeat even
+	// without a generation precondition.
+	obj := client.Bucket(d.Bucket).Object(key).Retryer(
+		storage.WithPolicy(storage.RetryAlways),
+		storage.WithBackoff(gax.Backoff{Initial: opts.Backoff, Max: maxBackoff, Multiplier: 2}),
+		storage.WithMaxAttempts(opts.MaxRetries+1))
+	w := obj.NewWriter(ctx)
+	w.ContentType = opts.ContentType
+	if _, err := io.Copy(w, body); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
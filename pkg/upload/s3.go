@@ -0,0 +1,85 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// uploadS3 uploads with the AWS SDK's upload manager, which switches to
+// a multipart upload for objects larger than one part. Credentials come
+// from the SDK's default chain.
+func uploadS3(ctx context.Context, d Destination, key string, body io.ReadSeeker, opts Options) error {
+	loadOpts := []func(*config.LoadOptions) error{
+		config.WithRetryer(func() aws.Retryer {
+			return retry.NewStandard(func(o *retry.StandardOptions) {
+				o.MaxAttempts = opts.MaxRetries + 1
+				o.Backoff = backoff(opts.Backoff)
+			})
+		}),
+	}
+	if opts.Region != "" {
+		loadOpts = append(loadOpts, config.WithRegion(opts.Region))
+	}
+	if opts.Endpoint != "" {
+		// S3-compatible stores do not all accept the checksum trailers
+		// the SDK sends by default.
+		loadOpts = append(loadOpts, config.WithRequestChecksumCalculation(aws.RequestChecksumCalculationWhenRequired))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return fmt.Errorf("load AWS configuration: %w", err)
+	}
+	if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
+		return fmt.Errorf("s3 upload requires AWS credentials: %w", err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = DefaultRegion
+	}
+
+	endpoint := func(o *s3.Options) {
+		if opts.Endpoint != "" {
+			o.BaseEndpoint = aws.String(opts.Endpoint)
+			o.UsePathStyle = true
+		}
+	}
+	client := s3.NewFromConfig(cfg, endpoint)
+	if opts.Region == "" {
+		// The bucket's own region wins over a configured one; a store
+		// that cannot tell keeps the configured region.
+		if region, err := manager.GetBucketRegion(ctx, client, d.Bucket); err == nil && region != "" && region != cfg.Region {
+			cfg.Region = region
+			client = s3.NewFromConfig(cfg, endpoint)
+		}
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(d.Bucket),
+		Key:    aws.String(key),
+		Body:   body,
+	}
+	if opts.ContentType != "" {
+		input.ContentType = aws.String(opts.ContentType)
+	}
+	_, err = manager.NewUploader(client).Upload(ctx, input)
+	return err
+}
+
+// backoff doubles the delay before each retry, from its value up to
+// maxBackoff.
+type backoff time.Duration
+
+func (b backoff) BackoffDelay(attempt int, _ error) (time.Duration, error) {
+	delay := time.Duration(b)
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff), nil
+}
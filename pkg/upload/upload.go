@@ -0,0 +1,143 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Destination schemes.
+const (
+	SchemeS3  = "s3"
+	SchemeGCS = "gs"
+)
+
+// Upload defaults.
+const (
+	DefaultRegion     = "us-east-1"
+	DefaultMaxRetries = 3
+	DefaultBackoff    = 500 * time.Millisecond
+	maxBackoff        = 10 * time.Second
+)
+
+// Destination is a bucket and an object key template.
+type Destination struct {
+	Scheme string
+	Bucket string
+	Key    string
+}
+
+// KeyVars are the values substituted into an object key template.
+type KeyVars struct {
+	Time   time.Time
+	ScanID string
+	Format string
+}
+
+var (
+	placeholderPattern = regexp.MustCompile(`\{[^{}]*\}`)
+	bucketPattern      = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{1,220}[a-z0-9]$`)
+	placeholders       = map[string]bool{"{date}": true, "{time}": true, "{scan_id}": true, "{format}": true}
+)
+
+// ParseDestination parses an s3:// or gs:// destination and checks its key
+// template.
+func ParseDestination(raw string) (Destination, error) {
+	scheme, rest, ok := strings.Cut(raw, "://")
+	if !ok {
+		return Destination{}, fmt.Errorf("invalid upload destination %q: want s3://bucket/key or gs://bucket/key", raw)
+	}
+	scheme = strings.ToLower(scheme)
+	if scheme != SchemeS3 && scheme != SchemeGCS {
+		return Destination{}, fmt.Errorf("unsupported upload scheme %q: use s3 or gs", scheme)
+	}
+	bucket, key, _ := strings.Cut(rest, "/")
+	if !bucketPattern.MatchString(bucket) {
+		return Destination{}, fmt.Errorf("invalid bucket name %q", bucket)
+	}
+	if key == "" || strings.HasSuffix(key, "/") {
+		return Destination{}, fmt.Errorf("upload destination %q needs an object key, e.g. %s://%s/scans/{date}/{scan_id}.json.gz", raw, scheme, bucket)
+	}
+	for _, p := range placeholderPattern.FindAllString(key, -1) {
+		if !placeholders[p] {
+			return Destination{}, fmt.Errorf("unknown placeholder %s in object key: use {date}, {time}, {scan_id} or {format}", p)
+		}
+	}
+	if strings.ContainsAny(placeholderPattern.ReplaceAllString(key, ""), "{}") {
+		return Destination{}, fmt.Errorf("unbalanced braces in object key %q", key)
+	}
+	return Destination{Scheme: scheme, Bucket: bucket, Key: key}, nil
+}
+
+// ObjectKey expands the key template. Times are formatted in UTC.
+func (d Destination) ObjectKey(v KeyVars) string {
+	t := v.Time.UTC()
+	return strings.NewReplacer(
+		"{date}", t.Format("2006-01-02"),
+		"{time}", t.Format("150405"),
+		"{scan_id}", v.ScanID,
+		"{format}", v.Format,
+	).Replace(d.Key)
+}
+
+// URL returns the destination of an object key as an s3:// or gs:// URL.
+func (d Destination) URL(key string) string {
+	return d.Scheme + "://" + d.Bucket + "/" + key
+}
+
+// Options configures an upload.
+type Options struct {
+	// Endpoint replaces the storage service's base URL, e.g.
+	// http://localhost:9000 for MinIO. S3 objects are addressed
+	// path-style.
+	Endpoint string
+	// Region is the S3 region. Empty uses the bucket's own region, found
+	// with a HEAD request, or else the AWS configuration's.
+	Region string
+	// ContentType is stored with the object.
+	ContentType string
+	// MaxRetries is how many times a transient failure is retried, with
+	// exponential backoff starting at Backoff. Zero uses the default and a
+	// negative value disables retries.
+	MaxRetries int
+	Backoff    time.Duration
+}
+
+// Upload stores body under key in the destination bucket, replacing any
+// existing object. Large objects are uploaded in parts.
+func Upload(ctx context.Context, d Destination, key string, body io.ReadSeeker, opts Options) error {
+	switch {
+	case opts.MaxRetries == 0:
+		opts.MaxRetries = DefaultMaxRetries
+	case opts.MaxRetries < 0:
+		opts.MaxRetries = 0
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = DefaultBackoff
+	}
+	if opts.Endpoint != "" {
+		u, err := url.Parse(opts.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid upload endpoint %q: want http(s)://host[:port]", opts.Endpoint)
+		}
+		opts.Endpoint = strings.TrimSuffix(opts.Endpoint, "/")
+	}
+
+	var err error
+	switch d.Scheme {
+	case SchemeS3:
+		err = uploadS3(ctx, d, key, body, opts)
+	case SchemeGCS:
+		err = uploadGCS(ctx, d, key, body, opts)
+	default:
+		return fmt.Errorf("unsupported upload scheme %q", d.Scheme)
+	}
+	if err != nil {
+		return fmt.Errorf("upload to %s: %w", d.URL(key), err)
+	}
+	return nil
+}
@@ -0,0 +1,318 @@
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseDestination(t *testing.T) {
+	d, err := ParseDestination("S3://reports/scans/{date}/{scan_id}.json.gz")
+	if err != nil {
+		t.Fatalf("ParseDestination: %v", err)
+	}
+	if d.Scheme != SchemeS3 || d.Bucket != "reports" || d.Key != "scans/{date}/{scan_id}.json.gz" {
+		t.Errorf("destination = %+v", d)
+	}
+
+	for _, raw := range []string{
+		"reports/scans.json",
+		"ftp://reports/scans.json",
+		"s3://reports",
+		"s3://reports/scans/",
+		"gs://Bad_Bucket/x.json",
+		"s3://reports/{host}.json",
+		"s3://reports/{date.json",
+	} {
+		if _, err := ParseDestination(raw); err == nil {
+			t.Errorf("ParseDestination(%q) should fail", raw)
+		}
+	}
+}
+
+func TestObjectKey(t *testing.T) {
+	d := Destination{Scheme: SchemeGCS, Bucket: "reports", Key: "{format}/{date}/{time}-{scan_id}.csv"}
+	start := time.Date(2024, 5, 1, 23, 30, 5, 0, time.FixedZone("EST", -5*3600))
+	key := d.ObjectKey(KeyVars{Time: start, ScanID: "abc123", Format: "csv"})
+	if key != "csv/2024-05-02/043005-abc123.csv" {
+		t.Errorf("ObjectKey = %q", key)
+	}
+	if got := d.URL(key); got != "gs://reports/csv/2024-05-02/043005-abc123.csv" {
+		t.Errorf("URL = %q", got)
+	}
+}
+
+// recordedPut is one object or part upload received by a fake bucket.
+type recordedPut struct {
+	path          string
+	query         url.Values
+	authorization string
+	token         string
+	contentType   string
+	body          string
+}
+
+// fakeS3 is an S3 bucket that answers the first uploads with status and
+// code. It reports region, when set, to bucket region lookups.
+type fakeS3 struct {
+	mu        sync.Mutex
+	region    string
+	puts      []recordedPut
+	completed string
+	failures  int
+	status    int
+	code      string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodHead:
+		if f.region != "" {
+			w.Header().Set("X-Amz-Bucket-Region", f.region)
+		}
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		io.WriteString(w, `<InitiateMultipartUploadResult><Bucket>reports</Bucket><Key>k</Key><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`)
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		f.completed = string(body)
+		io.WriteString(w, `<CompleteMultipartUploadResult><Bucket>reports</Bucket><Key>k</Key><ETag>"done"</ETag></CompleteMultipartUploadResult>`)
+	case r.Method == http.MethodPut:
+		f.puts = append(f.puts, recordedPut{
+			path:          r.URL.EscapedPath(),
+			query:         query,
+			authorization: r.Header.Get("Authorization"),
+			token:         r.Header.Get("X-Amz-Security-Token"),
+			contentType:   r.Header.Get("Content-Type"),
+			body:          string(body),
+		})
+		if f.failures > 0 {
+			f.failures--
+			w.WriteHeader(f.status)
+			fmt.Fprintf(w, `<?xml version="1.0"?><Error><Code>%s</Code><Message>Reduce your request rate.</Message></Error>`, f.code)
+			return
+		}
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, len(f.puts)))
+	default:
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+	}
+}
+
+// awsEnv sets static AWS credentials and keeps the SDK away from the
+// host's AWS configuration and instance metadata.
+func awsEnv(t *testing.T, vars map[string]string) {
+	t.Helper()
+	dir := t.TempDir()
+	for k, v := range map[string]string{
+		"AWS_ACCESS_KEY_ID":           "",
+		"AWS_SECRET_ACCESS_KEY":       "",
+		"AWS_SESSION_TOKEN":           "",
+		"AWS_REGION":                  "",
+		"AWS_DEFAULT_REGION":          "",
+		"AWS_PROFILE":                 "",
+		"AWS_CONFIG_FILE":             filepath.Join(dir, "config"),
+		"AWS_SHARED_CREDENTIALS_FILE": filepath.Join(dir, "credentials"),
+		"AWS_EC2_METADATA_DISABLED":   "true",
+	} {
+		t.Setenv(k, v)
+	}
+	for k, v := range vars {
+		t.Setenv(k, v)
+	}
+}
+
+func TestUploadS3SignsAndRetries(t *testing.T) {
+	awsEnv(t, map[string]string{"AWS_ACCESS_KEY_ID": "AKID", "AWS_SECRET_ACCESS_KEY": "secret", "AWS_SESSION_TOKEN": "session"})
+	bucket := &fakeS3{failures: 1, status: http.StatusServiceUnavailable, code: "SlowDown"}
+	srv := httptest.NewServer(bucket)
+	defer srv.Close()
+
+	d, _ := ParseDestination("s3://reports/scans/{date}/{scan_id}.json")
+	key := d.ObjectKey(KeyVars{Time: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), ScanID: "run 1"})
+	body := `{"host":"10.0.0.1","port":22}` + "\n"
+	err := Upload(context.Background(), d, key, strings.NewReader(body), Options{
+		Endpoint:    srv.URL,
+		Region:      "eu-west-1",
+		ContentType: "application/x-ndjson",
+		Backoff:     time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+
+	if len(bucket.puts) != 2 {
+		t.Fatalf("bucket received %d uploads, want a retry after the 503", len(bucket.puts))
+	}
+	put := bucket.puts[1]
+	if put.path != "/reports/scans/2024-05-01/run%201.json" {
+		t.Errorf("path = %s", put.path)
+	}
+	if put.body != body || put.contentType != "application/x-ndjson" {
+		t.Errorf("body %q, content type %q", put.body, put.contentType)
+	}
+	if !strings.HasPrefix(put.authorization, "AWS4-HMAC-SHA256 Credential=AKID/") ||
+		!strings.Contains(put.authorization, "/eu-west-1/s3/aws4_request") {
+		t.Errorf("Authorization = %s", put.authorization)
+	}
+	if put.token != "session" {
+		t.Errorf("session token = %q, want it sent", put.token)
+	}
+}
+
+func TestUploadS3DiscoversBucketRegion(t *testing.T) {
+	awsEnv(t, map[string]string{"AWS_ACCESS_KEY_ID": "AKID", "AWS_SECRET_ACCESS_KEY": "secret", "AWS_REGION": "us-east-1"})
+	bucket := &fakeS3{region: "ap-southeast-2"}
+	srv := httptest.NewServer(bucket)
+	defer srv.Close()
+
+	d, _ := ParseDestination("s3://reports/x.json")
+	if err := Upload(context.Background(), d, d.Key, strings.NewReader("x"), Options{Endpoint: srv.URL}); err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if len(bucket.puts) != 1 || !strings.Contains(bucket.puts[0].authorization, "/ap-southeast-2/s3/aws4_request") {
+		t.Errorf("puts = %+v; want them signed for the bucket's region", bucket.puts)
+	}
+}
+
+func TestUploadS3Multipart(t *testing.T) {
+	awsEnv(t, map[string]string{"AWS_ACCESS_KEY_ID": "AKID", "AWS_SECRET_ACCESS_KEY": "secret"})
+	bucket := &fakeS3{}
+	srv := httptest.NewServer(bucket)
+	defer srv.Close()
+
+	d, _ := ParseDestination("s3://reports/big.json")
+	body := strings.Repeat("x", 6<<20)
+	if err := Upload(context.Background(), d, d.Key, strings.NewReader(body), Options{Endpoint: srv.URL, Region: "eu-west-1"}); err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if len(bucket.puts) != 2 {
+		t.Fatalf("bucket received %d uploads, want 2 parts", len(bucket.puts))
+	}
+	size := 0
+	for _, put := range bucket.puts {
+		if put.query.Get("uploadId") != "upload-1" || put.query.Get("partNumber") == "" {
+			t.Errorf("part query = %v", put.query)
+		}
+		size += len(put.body)
+	}
+	if size != len(body) {
+		t.Errorf("parts hold %d bytes, want %d", size, len(body))
+	}
+	if strings.Count(bucket.completed, "<Part>") != 2 {
+		t.Errorf("CompleteMultipartUpload body = %s", bucket.completed)
+	}
+}
+
+func TestUploadPermanentFailure(t *testing.T) {
+	awsEnv(t, map[string]string{"AWS_ACCESS_KEY_ID": "AKID", "AWS_SECRET_ACCESS_KEY": "secret"})
+	bucket := &fakeS3{failures: 10, status: http.StatusForbidden, code: "AccessDenied"}
+	srv := httptest.NewServer(bucket)
+	defer srv.Close()
+
+	d, _ := ParseDestination("s3://reports/x.json")
+	err := Upload(context.Background(), d, d.Key, strings.NewReader("x"), Options{
+		Endpoint: srv.URL,
+		Region:   "eu-west-1",
+		Backoff:  time.Millisecond,
+	})
+	if err == nil || !strings.Contains(err.Error(), "AccessDenied: Reduce your request rate.") {
+		t.Fatalf("err = %v, want the service's error message", err)
+	}
+	if len(bucket.puts) != 1 {
+		t.Errorf("a 403 should not be retried, got %d requests", len(bucket.puts))
+	}
+}
+
+// fakeGCS is a Cloud Storage bucket taking JSON API uploads, which
+// answers the first ones with status.
+type fakeGCS struct {
+	mu       sync.Mutex
+	puts     []recordedPut
+	failures int
+	status   int
+}
+
+func (f *fakeGCS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Method != http.MethodPost || r.URL.Path != "/upload/storage/v1/b/reports/o" {
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+		return
+	}
+	put := recordedPut{query: r.URL.Query(), authorization: r.Header.Get("Authorization")}
+	_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	parts := multipart.NewReader(r.Body, params["boundary"])
+	var metadata struct {
+		Name        string `json:"name"`
+		ContentType string `json:"contentType"`
+	}
+	if part, err := parts.NextPart(); err == nil {
+		_ = json.NewDecoder(part).Decode(&metadata)
+	}
+	if part, err := parts.NextPart(); err == nil {
+		data, _ := io.ReadAll(part)
+		put.body = string(data)
+	}
+	put.path, put.contentType = metadata.Name, metadata.ContentType
+	f.puts = append(f.puts, put)
+	if f.failures > 0 {
+		f.failures--
+		http.Error(w, `{"error":{"code":503,"message":"backend error"}}`, f.status)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"bucket": "reports", "name": metadata.Name})
+}
+
+func TestUploadGCSBearerToken(t *testing.T) {
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "ya29.token")
+	bucket := &fakeGCS{failures: 1, status: http.StatusServiceUnavailable}
+	srv := httptest.NewServer(bucket)
+	defer srv.Close()
+
+	d, _ := ParseDestination("gs://reports/latest.csv")
+	err := Upload(context.Background(), d, d.Key, strings.NewReader("host,port\n"), Options{
+		Endpoint:    srv.URL,
+		ContentType: "text/csv",
+		Backoff:     time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if len(bucket.puts) != 2 {
+		t.Fatalf("bucket received %d uploads, want a retry after the 503", len(bucket.puts))
+	}
+	put := bucket.puts[1]
+	if put.path != "latest.csv" || put.authorization != "Bearer ya29.token" || put.body != "host,port\n" || put.contentType != "text/csv" {
+		t.Errorf("upload = %+v", put)
+	}
+}
+
+func TestUploadS3RequiresCredentials(t *testing.T) {
+	awsEnv(t, nil)
+	d, _ := ParseDestination("s3://reports/x.json")
+	err := Upload(context.Background(), d, d.Key, strings.NewReader(""), Options{Region: "eu-west-1"})
+	if err == nil || !strings.Contains(err.Error(), "requires AWS credentials") {
+		t.Errorf("err = %v, want a missing credentials error", err)
+	}
+}
+
+func TestUploadRejectsBadEndpoint(t *testing.T) {
+	d, _ := ParseDestination("s3://reports/x.json")
+	err := Upload(context.Background(), d, d.Key, strings.NewReader(""), Options{Endpoint: "minio:9000"})
+	if err == nil || !strings.Contains(err.Error(), "invalid upload endpoint") {
+		t.Errorf("err = %v", err)
+	}
+}